@@ -13,7 +13,7 @@ const (
 
 var (
 	usageLine = fmt.Sprintf("Usage:\n"+
-		"%s\n%s\n%s\n%s\n", runLine, stopLine, mckLine, serverTypeLine)
+		"%s\n%s\n%s\n%s\n%s\n", runLine, stopLine, mckLine, replayLine, serverTypeLine)
 
 	serverTypeLine = `
 [server type]
@@ -64,5 +64,25 @@ milvus mck run [flags]
 milvus mck cleanTrash [flags]
 	Clean the back inconsistent data
 	Tips: The flags is the same as its of the 'milvus mck [flags]'
+`
+	replayLine = `
+milvus replay [flags] [workload files or directories]
+	Replay the requests captured by proxy.workloadCapture against a cluster,
+	then report the latency and error differences of each method.
+[flags]
+	-uri 'localhost:19530'
+		Address of the target cluster.
+	-user ''
+		The username to connect the target cluster.
+	-password ''
+		The password to connect the target cluster.
+	-token ''
+		The token to connect the target cluster.
+	-speed '1'
+		The speed of the replay, 2 replays twice as fast as captured, 0 replays as fast as possible.
+	-concurrency '16'
+		The max number of the requests in flight.
+	-methods ''
+		The methods of the requests replayed, separated by comma, all methods by default.
 `
 )
@@ -54,6 +54,8 @@ func RunMilvus(args []string) {
 		c = &dryRun{}
 	case MckCmd:
 		c = &mck{}
+	case ReplayCmd:
+		c = &replay{}
 	default:
 		c = &defaultCommand{}
 	}
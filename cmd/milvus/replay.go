@@ -0,0 +1,80 @@
+package milvus
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/internal/util/workload"
+)
+
+const (
+	ReplayCmd = "replay"
+)
+
+type replay struct {
+	uri         string
+	user        string
+	password    string
+	token       string
+	speed       float64
+	concurrency int
+	methods     string
+}
+
+func (c *replay) execute(args []string, flags *flag.FlagSet) {
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, replayLine)
+	}
+	flags.StringVar(&c.uri, "uri", "localhost:19530", "the address of the target cluster")
+	flags.StringVar(&c.user, "user", "", "the username to connect the target cluster")
+	flags.StringVar(&c.password, "password", "", "the password to connect the target cluster")
+	flags.StringVar(&c.token, "token", "", "the token to connect the target cluster")
+	flags.Float64Var(&c.speed, "speed", 1, "the speed of the replay, the requests are issued as fast as possible if not positive")
+	flags.IntVar(&c.concurrency, "concurrency", 16, "the max number of the requests in flight")
+	flags.StringVar(&c.methods, "methods", "", "the methods of the requests replayed, separated by comma")
+	if err := flags.Parse(args[2:]); err != nil {
+		os.Exit(-1)
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, replayLine)
+		os.Exit(-1)
+	}
+
+	records, err := workload.OpenRecords(flags.Args()...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open the workload files: %s\n", err.Error())
+		os.Exit(-1)
+	}
+	defer records.Close()
+	fmt.Fprintf(os.Stdout, "replaying the captured requests against %s\n", c.uri)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	executor := workload.NewClientExecutor(milvusclient.ClientConfig{
+		Address:  c.uri,
+		Username: c.user,
+		Password: c.password,
+		APIKey:   c.token,
+	})
+	defer executor.Close(context.Background())
+
+	opts := []workload.Option{
+		workload.WithSpeed(c.speed),
+		workload.WithConcurrency(c.concurrency),
+	}
+	if c.methods != "" {
+		opts = append(opts, workload.WithMethods(strings.Split(c.methods, ",")...))
+	}
+	report, err := workload.NewReplayer(executor, opts...).Replay(ctx, records)
+	report.Print(os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "the replay stopped as failed to read the workload files: %s\n", err.Error())
+		os.Exit(-1)
+	}
+}
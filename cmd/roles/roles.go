@@ -432,6 +432,10 @@ func (mr *MilvusRoles) Run() {
 
 	// init tracer before run any component
 	tracer.Init()
+	// export metrics and logs to the otlp collector alongside prometheus and log files, if enabled,
+	// the export of each role starts once it registers its session and gets its node id
+	tracer.InitOTLP(Registry)
+	defer tracer.CloseOTLP()
 
 	enableComponents := []bool{
 		mr.EnableProxy,
@@ -0,0 +1,339 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/apache/arrow/go/v17/parquet"
+	"github.com/apache/arrow/go/v17/parquet/pqarrow"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+const (
+	formatParquet = "parquet"
+	formatJSONL   = "jsonl"
+	formatCSV     = "csv"
+
+	exportBatchSize = 4096
+)
+
+// columnNames names the columns of t by the schema, columns which are not
+// backed by a field keep their own names.
+func columnNames(t *table, lookup *fieldLookup) []string {
+	names := make([]string, 0, len(t.fieldIDs))
+	for i, fieldID := range t.fieldIDs {
+		if fieldID < 0 {
+			names = append(names, t.tbl.Schema().Field(i).Name)
+			continue
+		}
+		names = append(names, lookup.name(fieldID))
+	}
+	return names
+}
+
+// recordSink consumes the records of one table.
+type recordSink interface {
+	write(rec arrow.Record) error
+	close() error
+}
+
+// exportTable writes t into dir in the given format and returns the output path and row count.
+func exportTable(t *table, lookup *fieldLookup, format string, dir string) (string, int64, error) {
+	// check the format first so that an unsupported one doesn't leave an empty file behind.
+	var newSink func(w io.Writer) (recordSink, error)
+	switch format {
+	case formatJSONL:
+		newSink = func(w io.Writer) (recordSink, error) { return newJSONLSink(w, t, lookup), nil }
+	case formatCSV:
+		newSink = func(w io.Writer) (recordSink, error) { return newCSVSink(w, t, lookup) }
+	case formatParquet:
+		newSink = func(w io.Writer) (recordSink, error) { return newParquetSink(w, t, lookup) }
+	default:
+		return "", 0, fmt.Errorf("unsupported format %s", format)
+	}
+
+	out := filepath.Join(dir, t.name+"."+format)
+	f, err := os.Create(out)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	sink, err := newSink(f)
+	if err != nil {
+		return "", 0, err
+	}
+
+	reader := array.NewTableReader(t.tbl, exportBatchSize)
+	defer reader.Release()
+	var rows int64
+	for reader.Next() {
+		rec := reader.Record()
+		if err := sink.write(rec); err != nil {
+			return "", 0, err
+		}
+		rows += rec.NumRows()
+	}
+	if err := reader.Err(); err != nil {
+		return "", 0, err
+	}
+	if err := sink.close(); err != nil {
+		return "", 0, err
+	}
+	return out, rows, nil
+}
+
+func (t *table) fieldAt(i int, lookup *fieldLookup) *schemapb.FieldSchema {
+	if t.fieldIDs[i] < 0 {
+		return nil
+	}
+	return lookup.get(t.fieldIDs[i])
+}
+
+type jsonlSink struct {
+	w      *bufio.Writer
+	t      *table
+	lookup *fieldLookup
+	keys   [][]byte
+}
+
+func newJSONLSink(w io.Writer, t *table, lookup *fieldLookup) *jsonlSink {
+	names := columnNames(t, lookup)
+	keys := make([][]byte, 0, len(names))
+	for _, name := range names {
+		key, _ := json.Marshal(name)
+		keys = append(keys, key)
+	}
+	return &jsonlSink{w: bufio.NewWriter(w), t: t, lookup: lookup, keys: keys}
+}
+
+func (s *jsonlSink) write(rec arrow.Record) error {
+	for row := 0; row < int(rec.NumRows()); row++ {
+		// write fields one by one to keep the column order of the table
+		s.w.WriteByte('{')
+		for i, col := range rec.Columns() {
+			v, err := valueAt(col, row, s.t.fieldAt(i, s.lookup))
+			if err != nil {
+				return err
+			}
+			content, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				s.w.WriteByte(',')
+			}
+			s.w.Write(s.keys[i])
+			s.w.WriteByte(':')
+			s.w.Write(content)
+		}
+		s.w.WriteString("}\n")
+	}
+	return nil
+}
+
+func (s *jsonlSink) close() error {
+	return s.w.Flush()
+}
+
+type csvSink struct {
+	w      *csv.Writer
+	t      *table
+	lookup *fieldLookup
+}
+
+func newCSVSink(w io.Writer, t *table, lookup *fieldLookup) (*csvSink, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columnNames(t, lookup)); err != nil {
+		return nil, err
+	}
+	return &csvSink{w: cw, t: t, lookup: lookup}, nil
+}
+
+func (s *csvSink) write(rec arrow.Record) error {
+	line := make([]string, rec.NumCols())
+	for row := 0; row < int(rec.NumRows()); row++ {
+		for i, col := range rec.Columns() {
+			v, err := valueAt(col, row, s.t.fieldAt(i, s.lookup))
+			if err != nil {
+				return err
+			}
+			line[i], err = csvValue(v)
+			if err != nil {
+				return err
+			}
+		}
+		if err := s.w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *csvSink) close() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// csvValue renders scalars as they are and everything else in json.
+func csvValue(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case json.RawMessage:
+		return string(val), nil
+	case bool, int8, int16, int32, int64, uint8, uint64, float32, float64:
+		return fmt.Sprint(val), nil
+	default:
+		content, err := json.Marshal(val)
+		return string(content), err
+	}
+}
+
+// parquetSink writes parquet files that importutilv2 is able to read back,
+// vectors are written as lists and json, geometry, sparse vectors and arrays as strings.
+type parquetSink struct {
+	w      *pqarrow.FileWriter
+	t      *table
+	lookup *fieldLookup
+	schema *arrow.Schema
+}
+
+func parquetType(field *schemapb.FieldSchema, src arrow.DataType) arrow.DataType {
+	switch field.GetDataType() {
+	case schemapb.DataType_JSON, schemapb.DataType_Geometry, schemapb.DataType_SparseFloatVector, schemapb.DataType_Array:
+		return arrow.BinaryTypes.String
+	case schemapb.DataType_FloatVector:
+		return arrow.ListOf(arrow.PrimitiveTypes.Float32)
+	case schemapb.DataType_Int8Vector:
+		return arrow.ListOf(arrow.PrimitiveTypes.Int8)
+	case schemapb.DataType_BinaryVector, schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector:
+		// importutilv2 reads half precision vectors as raw bytes
+		return arrow.ListOf(arrow.PrimitiveTypes.Uint8)
+	default:
+		return src
+	}
+}
+
+func newParquetSink(w io.Writer, t *table, lookup *fieldLookup) (*parquetSink, error) {
+	names := columnNames(t, lookup)
+	fields := make([]arrow.Field, 0, len(names))
+	for i, src := range t.tbl.Schema().Fields() {
+		fields = append(fields, arrow.Field{
+			Name:     names[i],
+			Type:     parquetType(t.fieldAt(i, lookup), src.Type),
+			Nullable: src.Nullable,
+			Metadata: src.Metadata,
+		})
+	}
+	schema := arrow.NewSchema(fields, nil)
+	fw, err := pqarrow.NewFileWriter(schema, w, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+	return &parquetSink{w: fw, t: t, lookup: lookup, schema: schema}, nil
+}
+
+func (s *parquetSink) write(rec arrow.Record) error {
+	cols := make([]arrow.Array, 0, rec.NumCols())
+	defer func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}()
+	for i, col := range rec.Columns() {
+		target := s.schema.Field(i).Type
+		if arrow.TypeEqual(target, col.DataType()) {
+			col.Retain()
+			cols = append(cols, col)
+			continue
+		}
+		converted, err := convertColumn(col, target, s.t.fieldAt(i, s.lookup))
+		if err != nil {
+			return err
+		}
+		cols = append(cols, converted)
+	}
+	out := array.NewRecord(s.schema, cols, rec.NumRows())
+	defer out.Release()
+	return s.w.Write(out)
+}
+
+func (s *parquetSink) close() error {
+	return s.w.Close()
+}
+
+func convertColumn(col arrow.Array, target arrow.DataType, field *schemapb.FieldSchema) (arrow.Array, error) {
+	builder := array.NewBuilder(memory.DefaultAllocator, target)
+	defer builder.Release()
+	for row := 0; row < col.Len(); row++ {
+		if col.IsNull(row) {
+			builder.AppendNull()
+			continue
+		}
+		switch b := builder.(type) {
+		case *array.StringBuilder:
+			v, err := valueAt(col, row, field)
+			if err != nil {
+				return nil, err
+			}
+			str, err := csvValue(v)
+			if err != nil {
+				return nil, err
+			}
+			b.Append(str)
+		case *array.ListBuilder:
+			b.Append(true)
+			switch vb := b.ValueBuilder().(type) {
+			case *array.Uint8Builder:
+				fsb, ok := col.(*array.FixedSizeBinary)
+				if !ok {
+					return nil, fmt.Errorf("unexpected vector column type %s", col.DataType())
+				}
+				vb.AppendValues(fsb.Value(row), nil)
+			case *array.Float32Builder:
+				v, err := valueAt(col, row, field)
+				if err != nil {
+					return nil, err
+				}
+				vb.AppendValues(v.([]float32), nil)
+			case *array.Int8Builder:
+				v, err := valueAt(col, row, field)
+				if err != nil {
+					return nil, err
+				}
+				vb.AppendValues(v.([]int8), nil)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported conversion from %s to %s", col.DataType(), target)
+		}
+	}
+	return builder.NewArray(), nil
+}
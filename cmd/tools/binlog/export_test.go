@@ -0,0 +1,125 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func newTestTable(t *testing.T) (*table, *fieldLookup) {
+	mem := memory.DefaultAllocator
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "100", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "101", Type: &arrow.FixedSizeBinaryType{ByteWidth: 8}},
+		{Name: "102", Type: arrow.BinaryTypes.Binary, Nullable: true},
+	}, nil)
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+	builder.Field(1).(*array.FixedSizeBinaryBuilder).Append(typeutil.Float32ArrayToBytes([]float32{1, 2}))
+	builder.Field(1).(*array.FixedSizeBinaryBuilder).Append(typeutil.Float32ArrayToBytes([]float32{0, 0}))
+	builder.Field(2).(*array.BinaryBuilder).Append([]byte(`{"a":1}`))
+	builder.Field(2).(*array.BinaryBuilder).AppendNull()
+	rec := builder.NewRecord()
+	defer rec.Release()
+
+	lookup := &fieldLookup{fields: map[int64]*schemapb.FieldSchema{
+		100: {FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64},
+		101: {FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector},
+		102: {FieldID: 102, Name: "meta", DataType: schemapb.DataType_JSON, Nullable: true},
+	}}
+	return &table{
+		name:     "segment_1_insert",
+		fieldIDs: []int64{100, 101, 102},
+		tbl:      array.NewTableFromRecords(schema, []arrow.Record{rec}),
+	}, lookup
+}
+
+func TestDetectKind(t *testing.T) {
+	magic := make([]byte, 4)
+	common.Endian.PutUint32(magic, uint32(storage.MagicNumber))
+	assert.Equal(t, kindBinlog, detectKind(magic))
+	assert.Equal(t, kindPacked, detectKind([]byte("PAR1xxxx")))
+	assert.Equal(t, kindStatslog, detectKind([]byte(` {"fieldID":100}`)))
+	assert.Equal(t, kindUnknown, detectKind([]byte("abc")))
+}
+
+func TestExportTable(t *testing.T) {
+	tbl, lookup := newTestTable(t)
+	dir := t.TempDir()
+
+	out, rows, err := exportTable(tbl, lookup, formatJSONL, dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), rows)
+	content, err := storage.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"pk\":1,\"vec\":[1,2],\"meta\":{\"a\":1}}\n{\"pk\":2,\"vec\":[0,0],\"meta\":null}\n", string(content))
+
+	out, _, err = exportTable(tbl, lookup, formatCSV, dir)
+	require.NoError(t, err)
+	content, err = storage.ReadFile(out)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(t, []string{"pk,vec,meta", `1,"[1,2]","{""a"":1}"`, `2,"[0,0]",`}, lines)
+
+	out, rows, err = exportTable(tbl, lookup, formatParquet, dir)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), rows)
+	exported, err := loadPacked(t.Context(), out, mustRead(t, out))
+	require.NoError(t, err)
+	defer exported.tbl.Release()
+	assert.Equal(t, []int64{100, 101, 102}, exported.fieldIDs)
+	assert.Equal(t, "pk", exported.tbl.Schema().Field(0).Name)
+	assert.Equal(t, arrow.LIST, exported.tbl.Schema().Field(1).Type.ID())
+	assert.True(t, arrow.TypeEqual(arrow.BinaryTypes.String, exported.tbl.Schema().Field(2).Type))
+
+	_, _, err = exportTable(tbl, lookup, "xml", dir)
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, tbl.name+".xml"))
+}
+
+func TestSummarize(t *testing.T) {
+	tbl, lookup := newTestTable(t)
+	s, err := summarize(tbl, lookup)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), s.rows)
+	require.Len(t, s.columns, 3)
+	assert.Equal(t, float64(1), s.columns[0].min)
+	assert.Equal(t, float64(2), s.columns[0].max)
+	assert.Equal(t, int64(1), s.columns[1].zeroVectors)
+	assert.Equal(t, 2, s.columns[1].maxSize)
+	assert.Equal(t, int64(1), s.columns[2].nulls)
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	content, err := storage.ReadFile(path)
+	require.NoError(t, err)
+	return content
+}
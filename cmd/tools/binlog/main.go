@@ -17,19 +17,193 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/milvus-io/milvus/internal/storage"
 )
 
+const usage = `usage: binlog <command> [flags] path...
+
+commands:
+  print    dump every event of v1 binlog files to stdout
+  verify   verify magic number, event headers, payloads and checksums
+  stats    print summary statistics of each column
+  export   export data with the schema applied to parquet, jsonl or csv
+
+paths are local files or directories, or s3://bucket/key urls.
+directories and urls ending with "/" are walked recursively.
+run "binlog <command> -h" for the flags of a command.
+`
+
 func main() {
-	if len(os.Args) == 1 {
-		fmt.Println("usage: binlog file1 file2 ...")
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	var err error
+	switch os.Args[1] {
+	case "print":
+		err = runPrint(ctx, os.Args[2:])
+	case "verify":
+		err = runVerify(ctx, os.Args[2:])
+	case "stats":
+		err = runStats(ctx, os.Args[2:])
+	case "export":
+		err = runExport(ctx, os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		// keep compatible with "binlog file1 file2 ..."
+		err = storage.PrintBinlogFiles(os.Args[1:])
 	}
-	if err := storage.PrintBinlogFiles(os.Args[1:]); err != nil {
+	if err != nil {
 		fmt.Printf("error: %s\n", err.Error())
-	} else {
-		fmt.Printf("print binlog complete.\n")
+		os.Exit(1)
+	}
+}
+
+func runPrint(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("print", flag.ExitOnError)
+	src := &sourceConfig{}
+	src.register(fs)
+	fs.Parse(args)
+
+	files, err := src.resolve(ctx, fs.Args())
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.local {
+			if err := storage.PrintBinlogFiles([]string{f.path}); err != nil {
+				return err
+			}
+			continue
+		}
+		// PrintBinlogFiles mmaps local files, so remote objects are staged in a temp file first
+		data, err := f.read(ctx)
+		if err != nil {
+			return err
+		}
+		tmp, err := os.CreateTemp("", "binlog")
+		if err != nil {
+			return err
+		}
+		_, err = tmp.Write(data)
+		tmp.Close()
+		if err == nil {
+			err = storage.PrintBinlogFiles([]string{tmp.Name()})
+		}
+		os.Remove(tmp.Name())
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("print binlog complete.\n")
+	return nil
+}
+
+func runVerify(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	src := &sourceConfig{}
+	src.register(fs)
+	expectCRC := fs.String("crc", "", "expected crc32 (hex) of the file, only valid with a single path")
+	fs.Parse(args)
+
+	files, err := src.resolve(ctx, fs.Args())
+	if err != nil {
+		return err
+	}
+	if *expectCRC != "" && len(files) != 1 {
+		return fmt.Errorf("-crc requires exactly one file, got %d", len(files))
+	}
+
+	unhealthy := 0
+	for _, f := range files {
+		data, err := f.read(ctx)
+		if err != nil {
+			return err
+		}
+		report := verifyFile(f.path, data)
+		if *expectCRC != "" && fmt.Sprintf("%08x", report.checksum) != *expectCRC {
+			report.issues = append(report.issues, fmt.Sprintf("crc32 %08x mismatches expected %s", report.checksum, *expectCRC))
+		}
+		report.print(os.Stdout)
+		if len(report.issues) > 0 {
+			unhealthy++
+		}
+	}
+	fmt.Printf("verified %d files, %d unhealthy\n", len(files), unhealthy)
+	if unhealthy > 0 {
+		return fmt.Errorf("%d files failed verification", unhealthy)
+	}
+	return nil
+}
+
+func runStats(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	src := &sourceConfig{}
+	src.register(fs)
+	schemaPath := fs.String("schema", "", "collection schema in json, names columns and decodes typed values")
+	fs.Parse(args)
+
+	schema, err := loadSchema(*schemaPath)
+	if err != nil {
+		return err
+	}
+	files, err := src.resolve(ctx, fs.Args())
+	if err != nil {
+		return err
+	}
+	tables, err := loadTables(ctx, files)
+	if err != nil {
+		return err
+	}
+	for _, tbl := range tables {
+		s, err := summarize(tbl, schema)
+		if err != nil {
+			return err
+		}
+		s.print(os.Stdout)
+	}
+	return nil
+}
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	src := &sourceConfig{}
+	src.register(fs)
+	schemaPath := fs.String("schema", "", "collection schema in json, names columns and decodes typed values")
+	format := fs.String("format", formatJSONL, "output format, one of parquet, jsonl, csv")
+	output := fs.String("o", ".", "output directory")
+	fs.Parse(args)
+
+	schema, err := loadSchema(*schemaPath)
+	if err != nil {
+		return err
+	}
+	files, err := src.resolve(ctx, fs.Args())
+	if err != nil {
+		return err
+	}
+	tables, err := loadTables(ctx, files)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*output, os.ModePerm); err != nil {
+		return err
+	}
+	for _, tbl := range tables {
+		out, rows, err := exportTable(tbl, schema, *format, *output)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("exported %d rows of %s to %s\n", rows, tbl.name, out)
 	}
+	return nil
 }
@@ -0,0 +1,184 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// fieldLookup maps field ids to their schemas, it works without a schema as well,
+// in which case columns are named by field id and values are printed as stored.
+type fieldLookup struct {
+	fields map[int64]*schemapb.FieldSchema
+}
+
+// loadSchema reads a collection schema in protobuf json format, an empty path returns an empty lookup.
+func loadSchema(path string) (*fieldLookup, error) {
+	lookup := &fieldLookup{fields: make(map[int64]*schemapb.FieldSchema)}
+	if path == "" {
+		return lookup, nil
+	}
+	content, err := storage.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	schema := &schemapb.CollectionSchema{}
+	if err := protojson.Unmarshal(content, schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", path, err)
+	}
+	for _, field := range typeutil.GetAllFieldSchemas(schema) {
+		lookup.fields[field.GetFieldID()] = field
+	}
+	return lookup, nil
+}
+
+func (l *fieldLookup) get(fieldID int64) *schemapb.FieldSchema {
+	return l.fields[fieldID]
+}
+
+func (l *fieldLookup) name(fieldID int64) string {
+	if field, ok := l.fields[fieldID]; ok {
+		return field.GetName()
+	}
+	switch fieldID {
+	case common.RowIDField:
+		return common.RowIDFieldName
+	case common.TimeStampField:
+		return common.TimeStampFieldName
+	}
+	return fmt.Sprintf("field_%d", fieldID)
+}
+
+// valueAt returns the i-th value of arr as a go value that can be marshaled into json,
+// binary and fixed size binary payloads are decoded according to the field schema if any.
+func valueAt(arr arrow.Array, i int, field *schemapb.FieldSchema) (any, error) {
+	if arr.IsNull(i) {
+		return nil, nil
+	}
+	switch a := arr.(type) {
+	case *array.Boolean:
+		return a.Value(i), nil
+	case *array.Int8:
+		return a.Value(i), nil
+	case *array.Int16:
+		return a.Value(i), nil
+	case *array.Int32:
+		return a.Value(i), nil
+	case *array.Int64:
+		return a.Value(i), nil
+	case *array.Uint8:
+		return a.Value(i), nil
+	case *array.Uint64:
+		return a.Value(i), nil
+	case *array.Float32:
+		return a.Value(i), nil
+	case *array.Float64:
+		return a.Value(i), nil
+	case *array.String:
+		return a.Value(i), nil
+	case *array.Binary:
+		return decodeBinary(a.Value(i), field)
+	case *array.FixedSizeBinary:
+		return decodeVector(a.Value(i), field), nil
+	case *array.List:
+		start, end := a.ValueOffsets(i)
+		values := a.ListValues()
+		var elemField *schemapb.FieldSchema
+		if field != nil {
+			// elements of vector arrays are vectors of the element type
+			elemField = &schemapb.FieldSchema{DataType: field.GetElementType(), TypeParams: field.GetTypeParams()}
+		}
+		list := make([]any, 0, end-start)
+		for j := start; j < end; j++ {
+			v, err := valueAt(values, int(j), elemField)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	default:
+		return arr.ValueStr(i), nil
+	}
+}
+
+func decodeBinary(b []byte, field *schemapb.FieldSchema) (any, error) {
+	switch field.GetDataType() {
+	case schemapb.DataType_JSON:
+		if json.Valid(b) {
+			return json.RawMessage(append([]byte{}, b...)), nil
+		}
+		return string(b), nil
+	case schemapb.DataType_Geometry:
+		return common.ConvertWKBToWKT(b)
+	case schemapb.DataType_SparseFloatVector:
+		row := make(map[uint32]float32, typeutil.SparseFloatRowElementCount(b))
+		for j := 0; j < typeutil.SparseFloatRowElementCount(b); j++ {
+			row[typeutil.SparseFloatRowIndexAt(b, j)] = typeutil.SparseFloatRowValueAt(b, j)
+		}
+		return row, nil
+	case schemapb.DataType_Array:
+		scalar := &schemapb.ScalarField{}
+		if err := proto.Unmarshal(b, scalar); err != nil {
+			return nil, err
+		}
+		content, err := protojson.Marshal(scalar)
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(content), nil
+	default:
+		return append([]byte{}, b...), nil
+	}
+}
+
+func decodeVector(b []byte, field *schemapb.FieldSchema) any {
+	switch field.GetDataType() {
+	case schemapb.DataType_FloatVector:
+		vec := make([]float32, 0, len(b)/4)
+		for j := 0; j+4 <= len(b); j += 4 {
+			vec = append(vec, typeutil.BytesToFloat32(b[j:j+4]))
+		}
+		return vec
+	case schemapb.DataType_Float16Vector:
+		return typeutil.Float16BytesToFloat32Vector(b)
+	case schemapb.DataType_BFloat16Vector:
+		return typeutil.BFloat16BytesToFloat32Vector(b)
+	case schemapb.DataType_Int8Vector:
+		vec := make([]int8, len(b))
+		for j := range b {
+			vec[j] = int8(b[j])
+		}
+		return vec
+	case schemapb.DataType_BinaryVector:
+		return hex.EncodeToString(b)
+	default:
+		return append([]byte{}, b...)
+	}
+}
@@ -0,0 +1,177 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/objectstorage"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+const s3Scheme = "s3"
+
+// sourceConfig holds the object storage flags, defaults are taken from milvus.yaml.
+type sourceConfig struct {
+	address       string
+	accessKey     string
+	secretKey     string
+	useSSL        bool
+	useIAM        bool
+	cloudProvider string
+	region        string
+
+	managers map[string]storage.ChunkManager
+}
+
+func (c *sourceConfig) register(fs *flag.FlagSet) {
+	minioCfg := &paramtable.Get().MinioCfg
+	fs.StringVar(&c.address, "address", minioCfg.Address.GetValue(), "object storage address")
+	fs.StringVar(&c.accessKey, "ak", minioCfg.AccessKeyID.GetValue(), "object storage access key")
+	fs.StringVar(&c.secretKey, "sk", minioCfg.SecretAccessKey.GetValue(), "object storage secret key")
+	fs.BoolVar(&c.useSSL, "ssl", minioCfg.UseSSL.GetAsBool(), "use ssl to access object storage")
+	fs.BoolVar(&c.useIAM, "iam", minioCfg.UseIAM.GetAsBool(), "use iam to access object storage")
+	fs.StringVar(&c.cloudProvider, "cloud", minioCfg.CloudProvider.GetValue(), "cloud provider of object storage")
+	fs.StringVar(&c.region, "region", minioCfg.Region.GetValue(), "region of object storage")
+}
+
+// sourceFile is a resolved file to inspect.
+type sourceFile struct {
+	path  string
+	local bool
+	cm    storage.ChunkManager
+}
+
+func (f *sourceFile) read(ctx context.Context) ([]byte, error) {
+	return f.cm.Read(ctx, f.path)
+}
+
+func (c *sourceConfig) chunkManager(ctx context.Context, bucket string) (storage.ChunkManager, error) {
+	if cm, ok := c.managers[bucket]; ok {
+		return cm, nil
+	}
+	cfg := objectstorage.NewDefaultConfig()
+	for _, opt := range []objectstorage.Option{
+		objectstorage.Address(c.address),
+		objectstorage.AccessKeyID(c.accessKey),
+		objectstorage.SecretAccessKeyID(c.secretKey),
+		objectstorage.UseSSL(c.useSSL),
+		objectstorage.UseIAM(c.useIAM),
+		objectstorage.CloudProvider(c.cloudProvider),
+		objectstorage.Region(c.region),
+		objectstorage.BucketName(bucket),
+		objectstorage.CreateBucket(false),
+	} {
+		opt(cfg)
+	}
+	cm, err := storage.NewRemoteChunkManager(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if c.managers == nil {
+		c.managers = make(map[string]storage.ChunkManager)
+	}
+	c.managers[bucket] = cm
+	return cm, nil
+}
+
+// resolve expands the arguments into a sorted list of files. Directories and
+// object prefixes ending with "/" are walked recursively.
+func (c *sourceConfig) resolve(ctx context.Context, args []string) ([]*sourceFile, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no path specified")
+	}
+	files := make([]*sourceFile, 0, len(args))
+	for _, arg := range args {
+		resolved, err := c.resolveOne(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(resolved) == 0 {
+			return nil, fmt.Errorf("no file found in %s", arg)
+		}
+		files = append(files, resolved...)
+	}
+	return files, nil
+}
+
+func (c *sourceConfig) resolveOne(ctx context.Context, arg string) ([]*sourceFile, error) {
+	if !strings.HasPrefix(arg, s3Scheme+"://") {
+		cm := storage.NewLocalChunkManager()
+		fi, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			return []*sourceFile{{path: arg, local: true, cm: cm}}, nil
+		}
+		var paths []string
+		err = filepath.Walk(arg, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				paths = append(paths, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+		files := make([]*sourceFile, 0, len(paths))
+		for _, p := range paths {
+			files = append(files, &sourceFile{path: p, local: true, cm: cm})
+		}
+		return files, nil
+	}
+
+	u, err := url.Parse(arg)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := c.chunkManager(ctx, u.Host)
+	if err != nil {
+		return nil, err
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	if !strings.HasSuffix(key, "/") {
+		return []*sourceFile{{path: key, cm: cm}}, nil
+	}
+	var paths []string
+	err = cm.WalkWithPrefix(ctx, key, true, func(info *storage.ChunkObjectInfo) bool {
+		paths = append(paths, info.FilePath)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	files := make([]*sourceFile, 0, len(paths))
+	for _, p := range paths {
+		files = append(files, &sourceFile{path: p, cm: cm})
+	}
+	return files, nil
+}
@@ -0,0 +1,173 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+// columnSummary holds the summary statistics of one column.
+type columnSummary struct {
+	name     string
+	dataType string
+	rows     int64
+	nulls    int64
+
+	// numeric columns
+	numeric  bool
+	min, max float64
+	sum      float64
+
+	// string, binary and vector columns, in bytes or dimensions
+	sized            bool
+	minSize, maxSize int
+	totalSize        int64
+
+	// float vector columns
+	nanVectors  int64
+	zeroVectors int64
+}
+
+type tableSummary struct {
+	name    string
+	rows    int64
+	columns []*columnSummary
+}
+
+func (s *tableSummary) print(w io.Writer) {
+	fmt.Fprintf(w, "%s: %d rows, %d columns\n", s.name, s.rows, len(s.columns))
+	for _, c := range s.columns {
+		fmt.Fprintf(w, "\t%s (%s): rows=%d nulls=%d", c.name, c.dataType, c.rows, c.nulls)
+		valid := c.rows - c.nulls
+		if c.numeric && valid > 0 {
+			fmt.Fprintf(w, " min=%v max=%v mean=%v", c.min, c.max, c.sum/float64(valid))
+		}
+		if c.sized && valid > 0 {
+			fmt.Fprintf(w, " min_size=%d max_size=%d avg_size=%.2f", c.minSize, c.maxSize, float64(c.totalSize)/float64(valid))
+		}
+		if c.nanVectors > 0 || c.zeroVectors > 0 {
+			fmt.Fprintf(w, " nan_vectors=%d zero_vectors=%d", c.nanVectors, c.zeroVectors)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func summarize(t *table, lookup *fieldLookup) (*tableSummary, error) {
+	names := columnNames(t, lookup)
+	summary := &tableSummary{name: t.name, rows: t.tbl.NumRows()}
+	for i := 0; i < int(t.tbl.NumCols()); i++ {
+		field := t.fieldAt(i, lookup)
+		c := &columnSummary{
+			name:     names[i],
+			dataType: t.tbl.Column(i).DataType().String(),
+			min:      math.Inf(1),
+			max:      math.Inf(-1),
+			minSize:  math.MaxInt,
+		}
+		if field != nil {
+			c.dataType = field.GetDataType().String()
+		}
+		for _, chunk := range t.tbl.Column(i).Data().Chunks() {
+			if err := c.update(chunk, field); err != nil {
+				return nil, err
+			}
+		}
+		summary.columns = append(summary.columns, c)
+	}
+	return summary, nil
+}
+
+func (c *columnSummary) addNumber(v float64) {
+	c.numeric = true
+	c.min = math.Min(c.min, v)
+	c.max = math.Max(c.max, v)
+	c.sum += v
+}
+
+func (c *columnSummary) addSize(size int) {
+	c.sized = true
+	c.minSize = min(c.minSize, size)
+	c.maxSize = max(c.maxSize, size)
+	c.totalSize += int64(size)
+}
+
+func (c *columnSummary) update(arr arrow.Array, field *schemapb.FieldSchema) error {
+	c.rows += int64(arr.Len())
+	c.nulls += int64(arr.NullN())
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			continue
+		}
+		switch a := arr.(type) {
+		case *array.Boolean:
+			if a.Value(i) {
+				c.addNumber(1)
+			} else {
+				c.addNumber(0)
+			}
+		case *array.Int8:
+			c.addNumber(float64(a.Value(i)))
+		case *array.Int16:
+			c.addNumber(float64(a.Value(i)))
+		case *array.Int32:
+			c.addNumber(float64(a.Value(i)))
+		case *array.Int64:
+			c.addNumber(float64(a.Value(i)))
+		case *array.Float32:
+			c.addNumber(float64(a.Value(i)))
+		case *array.Float64:
+			c.addNumber(a.Value(i))
+		case *array.String:
+			c.addSize(len(a.Value(i)))
+		case *array.Binary:
+			c.addSize(len(a.Value(i)))
+		case *array.List:
+			start, end := a.ValueOffsets(i)
+			c.addSize(int(end - start))
+		case *array.FixedSizeBinary:
+			v, err := valueAt(a, i, field)
+			if err != nil {
+				return err
+			}
+			vec, ok := v.([]float32)
+			if !ok {
+				c.addSize(len(a.Value(i)))
+				continue
+			}
+			c.addSize(len(vec))
+			zero, nan := true, false
+			for _, f := range vec {
+				zero = zero && f == 0
+				nan = nan || math.IsNaN(float64(f)) || math.IsInf(float64(f), 0)
+			}
+			if zero {
+				c.zeroVectors++
+			}
+			if nan {
+				c.nanVectors++
+			}
+		}
+	}
+	return nil
+}
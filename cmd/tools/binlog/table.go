@@ -0,0 +1,362 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/apache/arrow/go/v17/parquet/file"
+	"github.com/apache/arrow/go/v17/parquet/pqarrow"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/storagev2/packed"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+)
+
+type fileKind int
+
+const (
+	kindUnknown fileKind = iota
+	kindBinlog
+	kindPacked
+	kindStatslog
+)
+
+func (k fileKind) String() string {
+	switch k {
+	case kindBinlog:
+		return "binlog"
+	case kindPacked:
+		return "packed"
+	case kindStatslog:
+		return "statslog"
+	default:
+		return "unknown"
+	}
+}
+
+var parquetMagic = []byte("PAR1")
+
+// detectKind tells v1 binlogs (insert and delete logs), packed storage v2 parquet
+// files and json encoded pk statslogs apart by their leading bytes.
+func detectKind(data []byte) fileKind {
+	switch {
+	case len(data) >= 4 && int32(common.Endian.Uint32(data)) == storage.MagicNumber:
+		return kindBinlog
+	case bytes.HasPrefix(data, parquetMagic):
+		return kindPacked
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return kindStatslog
+	}
+	return kindUnknown
+}
+
+// table is an exportable unit, all insert binlogs of a segment, all deltalogs of
+// a segment, a packed file or a statslog file. fieldIDs holds -1 for columns
+// which are not backed by a collection field, e.g. pk and ts of deltalogs.
+type table struct {
+	name     string
+	fieldIDs []int64
+	tbl      arrow.Table
+}
+
+// column collects the chunks of one field of a segment.
+type column struct {
+	fieldID int64
+	name    string
+	dtype   arrow.DataType
+	chunks  []arrow.Array
+	rows    int64
+}
+
+func (c *column) append(arr arrow.Array) {
+	arr.Retain()
+	c.chunks = append(c.chunks, arr)
+	c.rows += int64(arr.Len())
+}
+
+type segmentColumns struct {
+	segmentID int64
+	columns   map[string]*column
+}
+
+func (s *segmentColumns) column(key string, fieldID int64, dtype arrow.DataType) *column {
+	c, ok := s.columns[key]
+	if !ok {
+		c = &column{fieldID: fieldID, name: key, dtype: dtype}
+		s.columns[key] = c
+	}
+	return c
+}
+
+func (s *segmentColumns) build(name string) (*table, error) {
+	cols := make([]*column, 0, len(s.columns))
+	for _, c := range s.columns {
+		cols = append(cols, c)
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		if cols[i].fieldID != cols[j].fieldID {
+			return cols[i].fieldID < cols[j].fieldID
+		}
+		return cols[i].name < cols[j].name
+	})
+
+	fields := make([]arrow.Field, 0, len(cols))
+	data := make([][]arrow.Array, 0, len(cols))
+	fieldIDs := make([]int64, 0, len(cols))
+	for _, c := range cols {
+		if c.rows != cols[0].rows {
+			return nil, fmt.Errorf("%s: field %d has %d rows while field %d has %d rows",
+				name, c.fieldID, c.rows, cols[0].fieldID, cols[0].rows)
+		}
+		fields = append(fields, arrow.Field{
+			Name:     c.name,
+			Type:     c.dtype,
+			Nullable: true,
+			Metadata: arrow.NewMetadata([]string{packed.ArrowFieldIdMetadataKey}, []string{strconv.FormatInt(c.fieldID, 10)}),
+		})
+		data = append(data, c.chunks)
+		fieldIDs = append(fieldIDs, c.fieldID)
+	}
+	tbl := array.NewTableFromSlice(arrow.NewSchema(fields, nil), data)
+	for _, c := range cols {
+		for _, chunk := range c.chunks {
+			chunk.Release()
+		}
+	}
+	return &table{name: name, fieldIDs: fieldIDs, tbl: tbl}, nil
+}
+
+// loadTables reads all files and assembles them into tables. Insert binlogs and
+// deltalogs are grouped by segment, packed files and statslogs are one table each.
+func loadTables(ctx context.Context, files []*sourceFile) ([]*table, error) {
+	inserts := make(map[int64]*segmentColumns)
+	deletes := make(map[int64]*segmentColumns)
+	var tables []*table
+
+	segment := func(m map[int64]*segmentColumns, segmentID int64) *segmentColumns {
+		s, ok := m[segmentID]
+		if !ok {
+			s = &segmentColumns{segmentID: segmentID, columns: make(map[string]*column)}
+			m[segmentID] = s
+		}
+		return s
+	}
+
+	for _, f := range files {
+		data, err := f.read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch detectKind(data) {
+		case kindBinlog:
+			info, err := storage.InspectBinlog(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.path, err)
+			}
+			if info.IsDeltalog() {
+				if err := loadDeltalog(data, info, segment(deletes, info.SegmentID)); err != nil {
+					return nil, fmt.Errorf("%s: %w", f.path, err)
+				}
+				continue
+			}
+			s := segment(inserts, info.SegmentID)
+			err = storage.ReadBinlogRecords(data, func(rec arrow.Record) error {
+				c := s.column(strconv.FormatInt(info.FieldID, 10), info.FieldID, rec.Column(0).DataType())
+				c.append(rec.Column(0))
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.path, err)
+			}
+		case kindPacked:
+			t, err := loadPacked(ctx, f.path, data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.path, err)
+			}
+			tables = append(tables, t)
+		case kindStatslog:
+			t, err := loadStatslog(f.path, data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.path, err)
+			}
+			tables = append(tables, t)
+		default:
+			return nil, fmt.Errorf("%s: unrecognized file format", f.path)
+		}
+	}
+
+	for _, m := range []struct {
+		suffix  string
+		columns map[int64]*segmentColumns
+	}{{"insert", inserts}, {"delta", deletes}} {
+		segmentIDs := make([]int64, 0, len(m.columns))
+		for id := range m.columns {
+			segmentIDs = append(segmentIDs, id)
+		}
+		sort.Slice(segmentIDs, func(i, j int) bool { return segmentIDs[i] < segmentIDs[j] })
+		for _, id := range segmentIDs {
+			t, err := m.columns[id].build(fmt.Sprintf("segment_%d_%s", id, m.suffix))
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, t)
+		}
+	}
+	return tables, nil
+}
+
+// loadDeltalog appends pk and ts columns of a deltalog, the legacy format stores
+// every entry as one serialized DeleteLog string and is split here.
+func loadDeltalog(data []byte, info *storage.BinlogInfo, s *segmentColumns) error {
+	if info.IsMultiFieldDeltalog() {
+		return storage.ReadBinlogRecords(data, func(rec arrow.Record) error {
+			for i, field := range rec.Schema().Fields() {
+				c := s.column(field.Name, -1, field.Type)
+				c.append(rec.Column(i))
+			}
+			return nil
+		})
+	}
+
+	return storage.ReadBinlogRecords(data, func(rec arrow.Record) error {
+		strs, ok := rec.Column(0).(*array.String)
+		if !ok {
+			return fmt.Errorf("unexpected deltalog column type %s", rec.Column(0).DataType())
+		}
+		dl := &storage.DeleteLog{}
+		var pkBuilder array.Builder
+		tsBuilder := array.NewInt64Builder(memory.DefaultAllocator)
+		defer tsBuilder.Release()
+		for i := 0; i < strs.Len(); i++ {
+			if err := dl.Parse(strs.Value(i)); err != nil {
+				return err
+			}
+			if pkBuilder == nil {
+				if dl.PkType == int64(schemapb.DataType_VarChar) {
+					pkBuilder = array.NewStringBuilder(memory.DefaultAllocator)
+				} else {
+					pkBuilder = array.NewInt64Builder(memory.DefaultAllocator)
+				}
+				defer pkBuilder.Release()
+			}
+			switch b := pkBuilder.(type) {
+			case *array.StringBuilder:
+				b.Append(dl.Pk.GetValue().(string))
+			case *array.Int64Builder:
+				b.Append(dl.Pk.GetValue().(int64))
+			}
+			tsBuilder.Append(int64(dl.Ts))
+		}
+		if pkBuilder == nil {
+			return nil
+		}
+		pks, tss := pkBuilder.NewArray(), tsBuilder.NewArray()
+		defer pks.Release()
+		defer tss.Release()
+		s.column("pk", -1, pks.DataType()).append(pks)
+		s.column("ts", -1, tss.DataType()).append(tss)
+		return nil
+	})
+}
+
+// loadPacked reads a storage v2 parquet file, the field ids are kept in the
+// arrow field metadata written by the packed writer.
+func loadPacked(ctx context.Context, filePath string, data []byte) (*table, error) {
+	pf, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer pf.Close()
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: 1024}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := fr.ReadTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fieldIDs := make([]int64, 0, tbl.NumCols())
+	for _, field := range tbl.Schema().Fields() {
+		fieldIDs = append(fieldIDs, packedFieldID(field))
+	}
+	return &table{name: path.Base(filePath), fieldIDs: fieldIDs, tbl: tbl}, nil
+}
+
+func packedFieldID(field arrow.Field) int64 {
+	idx := field.Metadata.FindKey(packed.ArrowFieldIdMetadataKey)
+	if idx < 0 {
+		return -1
+	}
+	id, err := strconv.ParseInt(field.Metadata.Values()[idx], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return id
+}
+
+// loadStatslog turns the pk statistics of a statslog into a table, one row per stats entry.
+func loadStatslog(filePath string, data []byte) (*table, error) {
+	blob := &storage.Blob{Key: filePath, Value: data}
+	stats, err := storage.DeserializeBloomFilterStats([]string{filePath}, []*storage.Blob{blob})
+	if err != nil {
+		return nil, err
+	}
+
+	mem := memory.DefaultAllocator
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "field_id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "pk_type", Type: arrow.BinaryTypes.String},
+		{Name: "min_pk", Type: arrow.BinaryTypes.String},
+		{Name: "max_pk", Type: arrow.BinaryTypes.String},
+		{Name: "bf_type", Type: arrow.BinaryTypes.String},
+	}, nil)
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+	for _, s := range stats {
+		builder.Field(0).(*array.Int64Builder).Append(s.FieldID)
+		builder.Field(1).(*array.StringBuilder).Append(schemapb.DataType(s.PkType).String())
+		builder.Field(2).(*array.StringBuilder).Append(pkString(s.MinPk))
+		builder.Field(3).(*array.StringBuilder).Append(pkString(s.MaxPk))
+		builder.Field(4).(*array.StringBuilder).Append(fmt.Sprint(s.BFType))
+	}
+	rec := builder.NewRecord()
+	defer rec.Release()
+	return &table{
+		name:     path.Base(filePath),
+		fieldIDs: []int64{-1, -1, -1, -1, -1},
+		tbl:      array.NewTableFromRecords(schema, []arrow.Record{rec}),
+	}, nil
+}
+
+func pkString(pk storage.PrimaryKey) string {
+	if pk == nil {
+		return ""
+	}
+	return fmt.Sprint(pk.GetValue())
+}
@@ -0,0 +1,163 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/apache/arrow/go/v17/parquet/file"
+	"github.com/apache/arrow/go/v17/parquet/pqarrow"
+
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
+)
+
+// verifyReport is the outcome of verifying a single file.
+type verifyReport struct {
+	path     string
+	kind     fileKind
+	size     int
+	checksum uint32
+	details  []string
+	issues   []string
+}
+
+func (r *verifyReport) detail(format string, args ...any) {
+	r.details = append(r.details, fmt.Sprintf(format, args...))
+}
+
+func (r *verifyReport) issue(format string, args ...any) {
+	r.issues = append(r.issues, fmt.Sprintf(format, args...))
+}
+
+func (r *verifyReport) print(w io.Writer) {
+	status := "OK"
+	if len(r.issues) > 0 {
+		status = "CORRUPTED"
+	}
+	fmt.Fprintf(w, "%s [%s] %s size=%d crc32=%08x\n", status, r.kind.String(), r.path, r.size, r.checksum)
+	for _, d := range r.details {
+		fmt.Fprintf(w, "\t%s\n", d)
+	}
+	for _, i := range r.issues {
+		fmt.Fprintf(w, "\tissue: %s\n", i)
+	}
+}
+
+func verifyFile(path string, data []byte) *verifyReport {
+	r := &verifyReport{
+		path:     path,
+		kind:     detectKind(data),
+		size:     len(data),
+		checksum: crc32.ChecksumIEEE(data),
+	}
+	switch r.kind {
+	case kindBinlog:
+		verifyBinlog(r, data)
+	case kindPacked:
+		verifyPacked(r, data)
+	case kindStatslog:
+		verifyStatslog(r, data)
+	default:
+		r.issue("unrecognized file format")
+	}
+	return r
+}
+
+func verifyBinlog(r *verifyReport, data []byte) {
+	info, err := storage.InspectBinlog(data)
+	if err != nil {
+		r.issue("failed to parse binlog: %s", err.Error())
+		return
+	}
+	eventType := "none"
+	if len(info.Events) > 0 {
+		eventType = info.Events[0].TypeCode.String()
+	}
+	start, _ := tsoutil.ParseTS(info.StartTimestamp)
+	end, _ := tsoutil.ParseTS(info.EndTimestamp)
+	r.detail("event type: %s, events: %d, rows: %d", eventType, len(info.Events), info.RowNum)
+	r.detail("collection: %d, partition: %d, segment: %d, field: %d", info.CollectionID, info.PartitionID, info.SegmentID, info.FieldID)
+	r.detail("payload type: %s, nullable: %t, time range: [%v, %v]", info.PayloadDataType.String(), info.Nullable, start, end)
+	if info.Encrypted {
+		r.detail("encrypted, payloads are not verified")
+	}
+	r.issues = append(r.issues, info.Issues...)
+}
+
+func verifyPacked(r *verifyReport, data []byte) {
+	pf, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		r.issue("failed to read parquet footer: %s", err.Error())
+		return
+	}
+	defer pf.Close()
+
+	var rowGroupRows int64
+	for i := 0; i < pf.NumRowGroups(); i++ {
+		rowGroupRows += pf.RowGroup(i).NumRows()
+	}
+	if rowGroupRows != pf.NumRows() {
+		r.issue("row groups hold %d rows while footer records %d", rowGroupRows, pf.NumRows())
+	}
+	r.detail("row groups: %d, rows: %d, columns: %d", pf.NumRowGroups(), pf.NumRows(), pf.MetaData().Schema.NumColumns())
+
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: 1024}, memory.DefaultAllocator)
+	if err != nil {
+		r.issue("failed to read arrow schema: %s", err.Error())
+		return
+	}
+	tbl, err := fr.ReadTable(context.Background())
+	if err != nil {
+		r.issue("failed to decode pages: %s", err.Error())
+		return
+	}
+	defer tbl.Release()
+	for _, field := range tbl.Schema().Fields() {
+		if packedFieldID(field) < 0 {
+			r.issue("column %s has no field id metadata", field.Name)
+		}
+	}
+	if tbl.NumRows() != pf.NumRows() {
+		r.issue("decoded %d rows while footer records %d", tbl.NumRows(), pf.NumRows())
+	}
+}
+
+func verifyStatslog(r *verifyReport, data []byte) {
+	stats, err := storage.DeserializeBloomFilterStats([]string{r.path}, []*storage.Blob{{Key: r.path, Value: data}})
+	if err != nil {
+		r.issue("failed to parse statslog: %s", err.Error())
+		return
+	}
+	if len(stats) == 0 {
+		r.issue("statslog has no stats")
+	}
+	for _, s := range stats {
+		r.detail("field: %d, pk type: %d, min pk: %s, max pk: %s", s.FieldID, s.PkType, pkString(s.MinPk), pkString(s.MaxPk))
+		if s.MinPk != nil && s.MaxPk != nil && s.MinPk.GT(s.MaxPk) {
+			r.issue("min pk %s is greater than max pk %s", pkString(s.MinPk), pkString(s.MaxPk))
+		}
+		if s.BF == nil {
+			r.issue("field %d has no bloom filter", s.FieldID)
+		}
+	}
+}
@@ -350,6 +350,11 @@ func WriteYaml(w io.Writer) {
 			name: "streaming",
 			header: `
 # Any configuration related to the streaming service.`,
+		},
+		{
+			name: "cdc",
+			header: `
+# Any configuration related to the cdc server.`,
 		},
 		{
 			name: "knowhere",
@@ -0,0 +1,320 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const queryLimitKey = "limit"
+
+type exporter struct {
+	client      *milvusclient.Client
+	dbName      string
+	collections []string
+	dir         string
+	batchSize   int
+	fileRows    int64
+	withRBAC    bool
+	withRG      bool
+}
+
+func (e *exporter) run(ctx context.Context) (*manifest, error) {
+	svc := e.client.GetService()
+	m := &manifest{
+		Version:    manifestVersion,
+		ExportTime: time.Now().UTC().Format(time.RFC3339),
+	}
+	version, err := svc.GetVersion(ctx, &milvuspb.GetVersionRequest{})
+	if merr.CheckRPCCall(version, err) == nil {
+		m.ServerVersion = version.GetVersion()
+	}
+
+	db, err := svc.DescribeDatabase(ctx, &milvuspb.DescribeDatabaseRequest{DbName: e.dbName})
+	if err := merr.CheckRPCCall(db, err); err != nil {
+		return nil, errors.Wrapf(err, "failed to describe database %s", e.dbName)
+	}
+	m.Database = &databaseMeta{Name: e.dbName, Properties: kvToMap(db.GetProperties())}
+
+	names := e.collections
+	if len(names) == 0 {
+		resp, err := svc.ShowCollections(ctx, &milvuspb.ShowCollectionsRequest{DbName: e.dbName})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return nil, errors.Wrap(err, "failed to list collections")
+		}
+		names = resp.GetCollectionNames()
+	}
+	sort.Strings(names)
+
+	if err := os.MkdirAll(filepath.Join(e.dir, dataDirName), os.ModePerm); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		coll, err := e.exportCollection(ctx, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to export collection %s", name)
+		}
+		fmt.Printf("exported collection %s: %d partitions, %d rows\n", name, len(coll.Partitions), coll.rows())
+		m.Collections = append(m.Collections, coll)
+	}
+
+	if e.withRG {
+		if m.ResourceGroups, err = e.exportResourceGroups(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+	if e.withRBAC {
+		resp, err := svc.BackupRBAC(ctx, &milvuspb.BackupRBACMetaRequest{})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return nil, errors.Wrap(err, "failed to backup rbac meta")
+		}
+		if m.RBAC, err = marshalProto(filterRBAC(resp.GetRBACMeta(), e.dbName)); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeManifest(e.dir, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (e *exporter) exportCollection(ctx context.Context, name string) (*collectionMeta, error) {
+	svc := e.client.GetService()
+	desc, err := svc.DescribeCollection(ctx, &milvuspb.DescribeCollectionRequest{DbName: e.dbName, CollectionName: name})
+	if err := merr.CheckRPCCall(desc, err); err != nil {
+		return nil, err
+	}
+	schema := desc.GetSchema()
+	if len(schema.GetStructArrayFields()) > 0 {
+		return nil, errors.New("struct array fields are not supported")
+	}
+	coll := &collectionMeta{
+		Name:             name,
+		ShardsNum:        desc.GetShardsNum(),
+		ConsistencyLevel: desc.GetConsistencyLevel().String(),
+		Properties:       kvToMap(desc.GetProperties()),
+		NumPartitions:    desc.GetNumPartitions(),
+		Aliases:          desc.GetAliases(),
+	}
+	if coll.Schema, err = marshalProto(schema); err != nil {
+		return nil, err
+	}
+
+	indexes, err := svc.DescribeIndex(ctx, &milvuspb.DescribeIndexRequest{DbName: e.dbName, CollectionName: name})
+	if err := merr.CheckRPCCall(indexes, err); err != nil && !errors.Is(err, merr.ErrIndexNotFound) {
+		return nil, err
+	}
+	for _, index := range indexes.GetIndexDescriptions() {
+		// keep the definition only, build states are meaningless on another cluster
+		raw, err := marshalProto(&milvuspb.IndexDescription{
+			IndexName: index.GetIndexName(),
+			FieldName: index.GetFieldName(),
+			Params:    index.GetParams(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		coll.Indexes = append(coll.Indexes, raw)
+	}
+
+	loadState, err := svc.GetLoadState(ctx, &milvuspb.GetLoadStateRequest{DbName: e.dbName, CollectionName: name})
+	if err := merr.CheckRPCCall(loadState, err); err != nil {
+		return nil, err
+	}
+	if loadState.GetState() == commonpb.LoadState_LoadStateLoaded {
+		replicas, err := svc.GetReplicas(ctx, &milvuspb.GetReplicasRequest{DbName: e.dbName, CollectionName: name})
+		if err := merr.CheckRPCCall(replicas, err); err != nil {
+			return nil, err
+		}
+		rgs := lo.Uniq(lo.Map(replicas.GetReplicas(), func(r *milvuspb.ReplicaInfo, _ int) string { return r.GetResourceGroupName() }))
+		sort.Strings(rgs)
+		coll.Load = &loadMeta{Replicas: int32(len(replicas.GetReplicas())), ResourceGroups: rgs}
+	} else {
+		// query requires the collection to be loaded, load it for the export only
+		task, err := e.client.LoadCollection(ctx, milvusclient.NewLoadCollectionOption(name))
+		if err != nil {
+			return nil, err
+		}
+		if err := task.Await(ctx); err != nil {
+			return nil, err
+		}
+		defer e.client.ReleaseCollection(ctx, milvusclient.NewReleaseCollectionOption(name))
+	}
+
+	// rows of partition key collections are routed by the key, so they are exported as a whole
+	partitionNames := []string{""}
+	if !typeutil.HasPartitionKey(schema) {
+		partitions, err := svc.ShowPartitions(ctx, &milvuspb.ShowPartitionsRequest{DbName: e.dbName, CollectionName: name})
+		if err := merr.CheckRPCCall(partitions, err); err != nil {
+			return nil, err
+		}
+		partitionNames = partitions.GetPartitionNames()
+	}
+	for i, partition := range partitionNames {
+		relDir := filepath.Join(dataDirName, name, strconv.Itoa(i))
+		p, err := e.exportPartition(ctx, name, schema, partition, relDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to export partition %s", partition)
+		}
+		coll.Partitions = append(coll.Partitions, p)
+	}
+	return coll, nil
+}
+
+// exportPartition pages through a partition in primary key order.
+func (e *exporter) exportPartition(ctx context.Context, collection string, schema *schemapb.CollectionSchema, partition string, relDir string) (*partitionMeta, error) {
+	pkField, err := typeutil.GetPrimaryFieldSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	fields := exportFields(schema)
+	writer, err := newPartitionWriter(e.dir, relDir, fields, e.fileRows)
+	if err != nil {
+		return nil, err
+	}
+	outputFields := lo.Map(fields, func(f *schemapb.FieldSchema, _ int) string { return f.GetName() })
+	var partitionNames []string
+	if partition != "" {
+		partitionNames = []string{partition}
+	}
+
+	var lastPK any
+	for {
+		resp, err := e.client.GetService().Query(ctx, &milvuspb.QueryRequest{
+			DbName:           e.dbName,
+			CollectionName:   collection,
+			Expr:             pkRangeExpr(pkField, lastPK),
+			OutputFields:     outputFields,
+			PartitionNames:   partitionNames,
+			QueryParams:      []*commonpb.KeyValuePair{{Key: queryLimitKey, Value: strconv.Itoa(e.batchSize)}},
+			ConsistencyLevel: commonpb.ConsistencyLevel_Strong,
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return nil, err
+		}
+		pkData, ok := lo.Find(resp.GetFieldsData(), func(data *schemapb.FieldData) bool {
+			return data.GetFieldName() == pkField.GetName()
+		})
+		if !ok {
+			return nil, fmt.Errorf("primary key %s is missing in query result", pkField.GetName())
+		}
+		rows, last := lastPrimaryKey(pkData)
+		if rows == 0 {
+			break
+		}
+		if err := writer.write(resp.GetFieldsData(), rows); err != nil {
+			return nil, err
+		}
+		lastPK = last
+		if rows < e.batchSize {
+			break
+		}
+	}
+	files, err := writer.close()
+	if err != nil {
+		return nil, err
+	}
+	return &partitionMeta{Name: partition, Rows: writer.rows, Files: files}, nil
+}
+
+// pkRangeExpr filters the rows after lastPK, an empty expression is returned for the first page.
+func pkRangeExpr(pkField *schemapb.FieldSchema, lastPK any) string {
+	switch pk := lastPK.(type) {
+	case int64:
+		return fmt.Sprintf("%s > %d", pkField.GetName(), pk)
+	case string:
+		return fmt.Sprintf("%s > %s", pkField.GetName(), strconv.Quote(pk))
+	default:
+		return ""
+	}
+}
+
+// lastPrimaryKey returns the row count of a page and its last primary key.
+func lastPrimaryKey(pkData *schemapb.FieldData) (int, any) {
+	if ids := pkData.GetScalars().GetLongData().GetData(); len(ids) > 0 {
+		return len(ids), ids[len(ids)-1]
+	}
+	if ids := pkData.GetScalars().GetStringData().GetData(); len(ids) > 0 {
+		return len(ids), ids[len(ids)-1]
+	}
+	return 0, nil
+}
+
+// exportResourceGroups exports the resource groups used by the database, its
+// collections and their replicas.
+func (e *exporter) exportResourceGroups(ctx context.Context, m *manifest) ([]*resourceGroupMeta, error) {
+	used := typeutil.NewSet[string]()
+	if rgs, err := common.DatabaseLevelResourceGroups(mapToKV(m.Database.Properties)); err == nil {
+		used.Insert(rgs...)
+	}
+	for _, coll := range m.Collections {
+		if rgs, err := common.CollectionLevelResourceGroups(mapToKV(coll.Properties)); err == nil {
+			used.Insert(rgs...)
+		}
+		if coll.Load != nil {
+			used.Insert(coll.Load.ResourceGroups...)
+		}
+	}
+	used.Remove(common.DefaultResourceGroupName)
+
+	names := used.Collect()
+	sort.Strings(names)
+	result := make([]*resourceGroupMeta, 0, len(names))
+	for _, name := range names {
+		resp, err := e.client.GetService().DescribeResourceGroup(ctx, &milvuspb.DescribeResourceGroupRequest{ResourceGroup: name})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return nil, errors.Wrapf(err, "failed to describe resource group %s", name)
+		}
+		config, err := marshalProto(resp.GetResourceGroup().GetConfig())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &resourceGroupMeta{Name: name, Config: config})
+	}
+	return result, nil
+}
+
+// filterRBAC keeps the grants on the exported database and on all databases,
+// users, roles and privilege groups are cluster wide and kept as a whole.
+func filterRBAC(meta *milvuspb.RBACMeta, dbName string) *milvuspb.RBACMeta {
+	return &milvuspb.RBACMeta{
+		Users:           meta.GetUsers(),
+		Roles:           meta.GetRoles(),
+		PrivilegeGroups: meta.GetPrivilegeGroups(),
+		Grants: lo.Filter(meta.GetGrants(), func(grant *milvuspb.GrantEntity, _ int) bool {
+			return grant.GetDbName() == dbName || grant.GetDbName() == util.AnyWord
+		}),
+	}
+}
@@ -0,0 +1,103 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+func TestPKRangeExpr(t *testing.T) {
+	intPK := &schemapb.FieldSchema{Name: "id", DataType: schemapb.DataType_Int64}
+	strPK := &schemapb.FieldSchema{Name: "key", DataType: schemapb.DataType_VarChar}
+	assert.Equal(t, "", pkRangeExpr(intPK, nil))
+	assert.Equal(t, "id > 10", pkRangeExpr(intPK, int64(10)))
+	assert.Equal(t, `key > "a\"b"`, pkRangeExpr(strPK, `a"b`))
+
+	rows, last := lastPrimaryKey(&schemapb.FieldData{Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+		Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"a", "b"}}},
+	}}})
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, "b", last)
+	rows, last = lastPrimaryKey(&schemapb.FieldData{})
+	assert.Equal(t, 0, rows)
+	assert.Nil(t, last)
+}
+
+func TestFilterRBAC(t *testing.T) {
+	meta := &milvuspb.RBACMeta{
+		Users: []*milvuspb.UserInfo{{User: "alice"}},
+		Roles: []*milvuspb.RoleEntity{{Name: "reader"}},
+		Grants: []*milvuspb.GrantEntity{
+			{Role: &milvuspb.RoleEntity{Name: "reader"}, DbName: "db1"},
+			{Role: &milvuspb.RoleEntity{Name: "reader"}, DbName: "db2"},
+			{Role: &milvuspb.RoleEntity{Name: "reader"}, DbName: "*"},
+		},
+	}
+	filtered := filterRBAC(meta, "db1")
+	assert.Len(t, filtered.GetUsers(), 1)
+	assert.Len(t, filtered.GetRoles(), 1)
+	require.Len(t, filtered.GetGrants(), 2)
+	assert.Equal(t, "db1", filtered.GetGrants()[0].GetDbName())
+	assert.Equal(t, "*", filtered.GetGrants()[1].GetDbName())
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	schema, err := marshalProto(newTestSchema())
+	require.NoError(t, err)
+	index, err := marshalProto(&milvuspb.IndexDescription{IndexName: "vec_idx", FieldName: "vec"})
+	require.NoError(t, err)
+	m := &manifest{
+		Version:  manifestVersion,
+		Database: &databaseMeta{Name: "db1", Properties: map[string]string{"database.replica.number": "1"}},
+		Collections: []*collectionMeta{{
+			Name:             "coll",
+			Schema:           schema,
+			ConsistencyLevel: "Strong",
+			Indexes:          []json.RawMessage{index},
+			Partitions:       []*partitionMeta{{Name: "_default", Rows: 3, Files: []string{"data/coll/0/000000.parquet"}}},
+		}},
+	}
+	require.NoError(t, writeManifest(dir, m))
+
+	read, err := readManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "db1", read.Database.Name)
+	require.Len(t, read.Collections, 1)
+	coll := read.Collections[0]
+	assert.Equal(t, int64(3), coll.rows())
+	assert.Equal(t, commonpb.ConsistencyLevel_Strong, coll.consistencyLevel())
+	s, err := coll.schema()
+	require.NoError(t, err)
+	assert.Len(t, s.GetFields(), 7)
+	indexes, err := coll.indexes()
+	require.NoError(t, err)
+	assert.Equal(t, "vec_idx", indexes[0].GetIndexName())
+
+	m.Version = manifestVersion + 1
+	require.NoError(t, writeManifest(dir, m))
+	_, err = readManifest(dir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,407 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/rgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const (
+	importCheckInterval = 2 * time.Second
+	importFailedReason  = "failed_reason"
+)
+
+type importer struct {
+	client      *milvusclient.Client
+	manifest    *manifest
+	dbName      string
+	collections []string
+	dir         string
+	storage     *storageConfig
+	prefix      string
+	withData    bool
+	withRBAC    bool
+	withRG      bool
+	withLoad    bool
+
+	cm storage.ChunkManager
+}
+
+func (i *importer) run(ctx context.Context) error {
+	if i.withRG {
+		if err := i.createResourceGroups(ctx); err != nil {
+			return err
+		}
+	}
+	if err := i.createDatabase(ctx); err != nil {
+		return err
+	}
+	if err := i.client.UseDatabase(ctx, milvusclient.NewUseDatabaseOption(i.dbName)); err != nil {
+		return err
+	}
+
+	if i.withData {
+		cm, err := i.storage.chunkManager(ctx)
+		if err != nil {
+			return err
+		}
+		i.cm = cm
+		if i.prefix == "" {
+			i.prefix = path.Join("dbdump", i.dbName, time.Now().Format("20060102150405"))
+		}
+	}
+	for _, coll := range i.manifest.Collections {
+		if len(i.collections) > 0 && !lo.Contains(i.collections, coll.Name) {
+			continue
+		}
+		if err := i.importCollection(ctx, coll); err != nil {
+			return errors.Wrapf(err, "failed to import collection %s", coll.Name)
+		}
+		fmt.Printf("imported collection %s: %d partitions, %d rows\n", coll.Name, len(coll.Partitions), coll.rows())
+	}
+
+	if i.withRBAC {
+		return i.restoreRBAC(ctx)
+	}
+	return nil
+}
+
+func (i *importer) createDatabase(ctx context.Context) error {
+	dbs, err := i.client.ListDatabase(ctx, milvusclient.NewListDatabaseOption())
+	if err != nil {
+		return err
+	}
+	if lo.Contains(dbs, i.dbName) {
+		fmt.Printf("database %s exists, keep its properties\n", i.dbName)
+		return nil
+	}
+	resp, err := i.client.GetService().CreateDatabase(ctx, &milvuspb.CreateDatabaseRequest{
+		DbName:     i.dbName,
+		Properties: mapToKV(i.manifest.Database.Properties),
+	})
+	return merr.CheckRPCCall(resp, err)
+}
+
+// createResourceGroups creates the missing resource groups. Transfer rules
+// may refer to each other, so they are applied once all groups exist.
+func (i *importer) createResourceGroups(ctx context.Context) error {
+	if len(i.manifest.ResourceGroups) == 0 {
+		return nil
+	}
+	svc := i.client.GetService()
+	list, err := svc.ListResourceGroups(ctx, &milvuspb.ListResourceGroupsRequest{})
+	if err := merr.CheckRPCCall(list, err); err != nil {
+		return err
+	}
+	known := typeutil.NewSet(list.GetResourceGroups()...)
+	configs := make(map[string]*rgpb.ResourceGroupConfig)
+	for _, rg := range i.manifest.ResourceGroups {
+		if known.Contain(rg.Name) {
+			fmt.Printf("resource group %s exists, keep its config\n", rg.Name)
+			continue
+		}
+		config, err := rg.config()
+		if err != nil {
+			return err
+		}
+		initial := proto.Clone(config).(*rgpb.ResourceGroupConfig)
+		initial.TransferFrom, initial.TransferTo = nil, nil
+		resp, err := svc.CreateResourceGroup(ctx, &milvuspb.CreateResourceGroupRequest{ResourceGroup: rg.Name, Config: initial})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return errors.Wrapf(err, "failed to create resource group %s", rg.Name)
+		}
+		known.Insert(rg.Name)
+		configs[rg.Name] = config
+	}
+	if len(configs) == 0 {
+		return nil
+	}
+	for _, config := range configs {
+		config.TransferFrom = filterTransfers(config.GetTransferFrom(), known)
+		config.TransferTo = filterTransfers(config.GetTransferTo(), known)
+	}
+	resp, err := svc.UpdateResourceGroups(ctx, &milvuspb.UpdateResourceGroupsRequest{ResourceGroups: configs})
+	return merr.CheckRPCCall(resp, err)
+}
+
+func filterTransfers(transfers []*rgpb.ResourceGroupTransfer, known typeutil.Set[string]) []*rgpb.ResourceGroupTransfer {
+	return lo.Filter(transfers, func(t *rgpb.ResourceGroupTransfer, _ int) bool {
+		return known.Contain(t.GetResourceGroup())
+	})
+}
+
+// createSchema prepares an exported schema for CreateCollection, fields
+// managed by the server are added back by the server itself.
+func createSchema(coll *collectionMeta) (*schemapb.CollectionSchema, error) {
+	schema, err := coll.schema()
+	if err != nil {
+		return nil, err
+	}
+	schema.Name = coll.Name
+	schema.DbName = ""
+	schema.Fields = lo.Filter(schema.GetFields(), func(field *schemapb.FieldSchema, _ int) bool {
+		return !field.GetIsDynamic() && field.GetName() != common.NamespaceFieldName
+	})
+	return schema, nil
+}
+
+func (i *importer) importCollection(ctx context.Context, coll *collectionMeta) error {
+	svc := i.client.GetService()
+	has, err := i.client.HasCollection(ctx, milvusclient.NewHasCollectionOption(coll.Name))
+	if err != nil {
+		return err
+	}
+	if has {
+		return fmt.Errorf("collection already exists in database %s", i.dbName)
+	}
+
+	schema, err := createSchema(coll)
+	if err != nil {
+		return err
+	}
+	pkField, err := typeutil.GetPrimaryFieldSchema(schema)
+	if err != nil {
+		return err
+	}
+	// keep the exported primary keys of auto id collections during import
+	properties := lo.Assign(coll.Properties)
+	originAllowAutoID, hasAllowAutoID := properties[common.AllowInsertAutoIDKey]
+	overrideAutoID := i.withData && pkField.GetAutoID()
+	if overrideAutoID {
+		properties[common.AllowInsertAutoIDKey] = "true"
+	}
+	schemaBytes, err := proto.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	req := &milvuspb.CreateCollectionRequest{
+		DbName:           i.dbName,
+		CollectionName:   coll.Name,
+		Schema:           schemaBytes,
+		ShardsNum:        coll.ShardsNum,
+		ConsistencyLevel: coll.consistencyLevel(),
+		Properties:       mapToKV(properties),
+	}
+	if typeutil.HasPartitionKey(schema) {
+		req.NumPartitions = coll.NumPartitions
+	}
+	resp, err := svc.CreateCollection(ctx, req)
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return err
+	}
+
+	for _, p := range coll.Partitions {
+		if p.Name == "" {
+			continue
+		}
+		has, err := i.client.HasPartition(ctx, milvusclient.NewHasPartitionOption(coll.Name, p.Name))
+		if err != nil {
+			return err
+		}
+		if has {
+			continue
+		}
+		if err := i.client.CreatePartition(ctx, milvusclient.NewCreatePartitionOption(coll.Name, p.Name)); err != nil {
+			return err
+		}
+	}
+
+	if i.withData {
+		if err := i.importData(ctx, coll); err != nil {
+			return err
+		}
+		if overrideAutoID {
+			alter := &milvuspb.AlterCollectionRequest{DbName: i.dbName, CollectionName: coll.Name}
+			if hasAllowAutoID {
+				alter.Properties = []*commonpb.KeyValuePair{{Key: common.AllowInsertAutoIDKey, Value: originAllowAutoID}}
+			} else {
+				alter.DeleteKeys = []string{common.AllowInsertAutoIDKey}
+			}
+			resp, err := svc.AlterCollection(ctx, alter)
+			if err := merr.CheckRPCCall(resp, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	indexes, err := coll.indexes()
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		resp, err := svc.CreateIndex(ctx, &milvuspb.CreateIndexRequest{
+			DbName:         i.dbName,
+			CollectionName: coll.Name,
+			FieldName:      index.GetFieldName(),
+			IndexName:      index.GetIndexName(),
+			ExtraParams:    index.GetParams(),
+		})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return errors.Wrapf(err, "failed to create index %s", index.GetIndexName())
+		}
+	}
+
+	for _, alias := range coll.Aliases {
+		resp, err := svc.CreateAlias(ctx, &milvuspb.CreateAliasRequest{DbName: i.dbName, CollectionName: coll.Name, Alias: alias})
+		if err := merr.CheckRPCCall(resp, err); err != nil {
+			return errors.Wrapf(err, "failed to create alias %s", alias)
+		}
+	}
+
+	if i.withLoad && coll.Load != nil {
+		opt := milvusclient.NewLoadCollectionOption(coll.Name).
+			WithReplica(int(coll.Load.Replicas)).
+			WithResourceGroup(coll.Load.ResourceGroups...)
+		task, err := i.client.LoadCollection(ctx, opt)
+		if err != nil {
+			return err
+		}
+		return task.Await(ctx)
+	}
+	return nil
+}
+
+// importData stages the parquet files and imports them file by file, then waits for all jobs.
+func (i *importer) importData(ctx context.Context, coll *collectionMeta) error {
+	svc := i.client.GetService()
+	var jobs []int64
+	for _, p := range coll.Partitions {
+		keys, err := stage(ctx, i.cm, i.dir, i.prefix, p.Files)
+		if err != nil {
+			return errors.Wrap(err, "failed to stage data files")
+		}
+		for _, key := range keys {
+			resp, err := svc.Import(ctx, &milvuspb.ImportRequest{
+				DbName:         i.dbName,
+				CollectionName: coll.Name,
+				PartitionName:  p.Name,
+				Files:          []string{key},
+			})
+			if err := merr.CheckRPCCall(resp, err); err != nil {
+				return errors.Wrapf(err, "failed to import %s", key)
+			}
+			jobs = append(jobs, resp.GetTasks()...)
+		}
+	}
+
+	ticker := time.NewTicker(importCheckInterval)
+	defer ticker.Stop()
+	for len(jobs) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		pending := jobs[:0]
+		var imported int64
+		for _, job := range jobs {
+			state, err := svc.GetImportState(ctx, &milvuspb.GetImportStateRequest{Task: job})
+			if err := merr.CheckRPCCall(state, err); err != nil {
+				return err
+			}
+			switch state.GetState() {
+			case commonpb.ImportState_ImportCompleted:
+			case commonpb.ImportState_ImportFailed:
+				reason, _ := lo.Find(state.GetInfos(), func(kv *commonpb.KeyValuePair) bool { return kv.GetKey() == importFailedReason })
+				return fmt.Errorf("import job %d failed: %s", job, reason.GetValue())
+			default:
+				pending = append(pending, job)
+			}
+			imported += state.GetRowCount()
+		}
+		jobs = pending
+		fmt.Printf("importing %s: %d jobs pending, %d rows imported\n", coll.Name, len(jobs), imported)
+	}
+	return nil
+}
+
+func (i *importer) restoreRBAC(ctx context.Context) error {
+	meta, err := i.manifest.rbac()
+	if err != nil || meta == nil {
+		return err
+	}
+	svc := i.client.GetService()
+	existing, err := svc.BackupRBAC(ctx, &milvuspb.BackupRBACMetaRequest{})
+	if err := merr.CheckRPCCall(existing, err); err != nil {
+		return err
+	}
+	delta := rbacDelta(meta, existing.GetRBACMeta(), i.manifest.Database.Name, i.dbName)
+	resp, err := svc.RestoreRBAC(ctx, &milvuspb.RestoreRBACMetaRequest{RBACMeta: delta})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return errors.Wrap(err, "failed to restore rbac meta")
+	}
+	fmt.Printf("restored %d users, %d roles, %d privilege groups, %d grants\n",
+		len(delta.GetUsers()), len(delta.GetRoles()), len(delta.GetPrivilegeGroups()), len(delta.GetGrants()))
+	return nil
+}
+
+func grantKey(grant *milvuspb.GrantEntity) string {
+	return strings.Join([]string{
+		grant.GetRole().GetName(),
+		grant.GetObject().GetName(),
+		grant.GetObjectName(),
+		grant.GetDbName(),
+		grant.GetGrantor().GetPrivilege().GetName(),
+	}, "/")
+}
+
+// rbacDelta returns the part of meta missing in existing, restoring existing
+// users, roles or privilege groups is rejected by the server. Grants on the
+// exported database are moved to the target database.
+func rbacDelta(meta, existing *milvuspb.RBACMeta, fromDB, toDB string) *milvuspb.RBACMeta {
+	roles := typeutil.NewSet(lo.Map(existing.GetRoles(), func(r *milvuspb.RoleEntity, _ int) string { return r.GetName() })...)
+	users := typeutil.NewSet(lo.Map(existing.GetUsers(), func(u *milvuspb.UserInfo, _ int) string { return u.GetUser() })...)
+	groups := typeutil.NewSet(lo.Map(existing.GetPrivilegeGroups(), func(g *milvuspb.PrivilegeGroupInfo, _ int) string { return g.GetGroupName() })...)
+	grants := typeutil.NewSet(lo.Map(existing.GetGrants(), func(g *milvuspb.GrantEntity, _ int) string { return grantKey(g) })...)
+
+	delta := &milvuspb.RBACMeta{
+		Roles: lo.Filter(meta.GetRoles(), func(r *milvuspb.RoleEntity, _ int) bool { return !roles.Contain(r.GetName()) }),
+		Users: lo.Filter(meta.GetUsers(), func(u *milvuspb.UserInfo, _ int) bool { return !users.Contain(u.GetUser()) }),
+		PrivilegeGroups: lo.Filter(meta.GetPrivilegeGroups(), func(g *milvuspb.PrivilegeGroupInfo, _ int) bool {
+			return !groups.Contain(g.GetGroupName())
+		}),
+	}
+	for _, grant := range meta.GetGrants() {
+		grant = proto.Clone(grant).(*milvuspb.GrantEntity)
+		if grant.GetDbName() == fromDB {
+			grant.DbName = toDB
+		}
+		if grants.Contain(grantKey(grant)) {
+			continue
+		}
+		delta.Grants = append(delta.Grants, grant)
+	}
+	return delta
+}
@@ -0,0 +1,83 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/rgpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestCreateSchema(t *testing.T) {
+	raw, err := marshalProto(newTestSchema())
+	require.NoError(t, err)
+	schema, err := createSchema(&collectionMeta{Name: "renamed", Schema: raw})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", schema.GetName())
+	assert.True(t, schema.GetEnableDynamicField())
+	for _, field := range schema.GetFields() {
+		assert.False(t, field.GetIsDynamic())
+	}
+	assert.Len(t, schema.GetFields(), 6)
+}
+
+func TestFilterTransfers(t *testing.T) {
+	transfers := []*rgpb.ResourceGroupTransfer{{ResourceGroup: "rg1"}, {ResourceGroup: "rg2"}}
+	filtered := filterTransfers(transfers, typeutil.NewSet("rg2"))
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "rg2", filtered[0].GetResourceGroup())
+}
+
+func TestRBACDelta(t *testing.T) {
+	grant := func(role, db string) *milvuspb.GrantEntity {
+		return &milvuspb.GrantEntity{
+			Role:       &milvuspb.RoleEntity{Name: role},
+			Object:     &milvuspb.ObjectEntity{Name: "Collection"},
+			ObjectName: "*",
+			DbName:     db,
+			Grantor:    &milvuspb.GrantorEntity{Privilege: &milvuspb.PrivilegeEntity{Name: "Query"}},
+		}
+	}
+	meta := &milvuspb.RBACMeta{
+		Users:           []*milvuspb.UserInfo{{User: "root"}, {User: "alice"}},
+		Roles:           []*milvuspb.RoleEntity{{Name: "admin"}, {Name: "reader"}},
+		PrivilegeGroups: []*milvuspb.PrivilegeGroupInfo{{GroupName: "readers"}},
+		Grants:          []*milvuspb.GrantEntity{grant("reader", "src"), grant("reader", "*"), grant("admin", "src")},
+	}
+	existing := &milvuspb.RBACMeta{
+		Users:  []*milvuspb.UserInfo{{User: "root"}},
+		Roles:  []*milvuspb.RoleEntity{{Name: "admin"}},
+		Grants: []*milvuspb.GrantEntity{grant("admin", "dst")},
+	}
+
+	delta := rbacDelta(meta, existing, "src", "dst")
+	require.Len(t, delta.GetUsers(), 1)
+	assert.Equal(t, "alice", delta.GetUsers()[0].GetUser())
+	require.Len(t, delta.GetRoles(), 1)
+	assert.Equal(t, "reader", delta.GetRoles()[0].GetName())
+	assert.Len(t, delta.GetPrivilegeGroups(), 1)
+	require.Len(t, delta.GetGrants(), 2)
+	assert.Equal(t, "dst", delta.GetGrants()[0].GetDbName())
+	assert.Equal(t, "*", delta.GetGrants()[1].GetDbName())
+	// the exported meta is left untouched
+	assert.Equal(t, "src", meta.GetGrants()[0].GetDbName())
+}
@@ -0,0 +1,200 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+const usage = `usage: dbdump <command> [flags]
+
+commands:
+  export   export a database to a portable directory
+  import   re-create an exported database on another cluster
+
+an export directory holds manifest.json with the database properties,
+collection schemas, partitions, indexes, aliases, load settings, resource
+groups and rbac meta, plus the data of every partition as parquet files
+readable by the bulk import of milvus.
+run "dbdump <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(ctx, os.Args[2:])
+	case "import":
+		err = runImport(ctx, os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		err = fmt.Errorf("unknown command %s", os.Args[1])
+	}
+	if err != nil {
+		fmt.Printf("error: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+// connConfig holds the flags to connect to a milvus cluster.
+type connConfig struct {
+	address  string
+	username string
+	password string
+	token    string
+}
+
+func (c *connConfig) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.address, "uri", "localhost:19530", "address of milvus proxy")
+	fs.StringVar(&c.username, "user", "", "username for authentication")
+	fs.StringVar(&c.password, "password", "", "password for authentication")
+	fs.StringVar(&c.token, "token", "", "api key for authentication")
+}
+
+func (c *connConfig) connect(ctx context.Context, dbName string) (*milvusclient.Client, error) {
+	return milvusclient.New(ctx, &milvusclient.ClientConfig{
+		Address:  c.address,
+		Username: c.username,
+		Password: c.password,
+		APIKey:   c.token,
+		DBName:   dbName,
+	})
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	items := strings.Split(s, ",")
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	conn := &connConfig{}
+	conn.register(fs)
+	dbName := fs.String("db", "default", "database to export")
+	collections := fs.String("collections", "", "comma separated collections to export, all collections by default")
+	output := fs.String("o", "", "output directory")
+	batchSize := fs.Int("batch", 4096, "rows fetched by each query")
+	fileRows := fs.Int64("file-rows", 1000000, "max rows of each parquet file")
+	withRBAC := fs.Bool("rbac", true, "export users, roles and the grants on the database")
+	withRG := fs.Bool("resource-groups", true, "export resource group settings")
+	fs.Parse(args)
+
+	if *output == "" {
+		return fmt.Errorf("output directory is not specified")
+	}
+	c, err := conn.connect(ctx, *dbName)
+	if err != nil {
+		return err
+	}
+	defer c.Close(ctx)
+
+	e := &exporter{
+		client:      c,
+		dbName:      *dbName,
+		collections: splitList(*collections),
+		dir:         *output,
+		batchSize:   *batchSize,
+		fileRows:    *fileRows,
+		withRBAC:    *withRBAC,
+		withRG:      *withRG,
+	}
+	m, err := e.run(ctx)
+	if err != nil {
+		return err
+	}
+	var rows int64
+	for _, coll := range m.Collections {
+		rows += coll.rows()
+	}
+	fmt.Printf("exported database %s: %d collections, %d rows to %s\n", m.Database.Name, len(m.Collections), rows, *output)
+	return nil
+}
+
+func runImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	conn := &connConfig{}
+	conn.register(fs)
+	store := &storageConfig{}
+	store.register(fs)
+	dir := fs.String("dir", "", "directory written by export")
+	dbName := fs.String("db", "", "target database, the exported database name by default")
+	collections := fs.String("collections", "", "comma separated collections to import, all collections by default")
+	prefix := fs.String("prefix", "", "object key prefix to stage the parquet files, dbdump/<db>/<time> by default")
+	withData := fs.Bool("data", true, "import data, otherwise only the meta is re-created")
+	withRBAC := fs.Bool("rbac", true, "restore users, roles and grants")
+	withRG := fs.Bool("resource-groups", true, "re-create resource groups")
+	withLoad := fs.Bool("load", true, "load collections which were loaded at export time")
+	fs.Parse(args)
+
+	if *dir == "" {
+		return fmt.Errorf("export directory is not specified")
+	}
+	m, err := readManifest(*dir)
+	if err != nil {
+		return err
+	}
+	if *dbName == "" {
+		*dbName = m.Database.Name
+	}
+	c, err := conn.connect(ctx, "")
+	if err != nil {
+		return err
+	}
+	defer c.Close(ctx)
+
+	i := &importer{
+		client:      c,
+		manifest:    m,
+		dbName:      *dbName,
+		collections: splitList(*collections),
+		dir:         *dir,
+		storage:     store,
+		prefix:      *prefix,
+		withData:    *withData,
+		withRBAC:    *withRBAC,
+		withRG:      *withRG,
+		withLoad:    *withLoad,
+	}
+	if err := i.run(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("imported database %s into %s\n", m.Database.Name, *dbName)
+	return nil
+}
@@ -0,0 +1,199 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/rgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+)
+
+const (
+	manifestVersion  = 1
+	manifestFileName = "manifest.json"
+	dataDirName      = "data"
+)
+
+// manifest describes an exported database. Protobuf messages are kept in
+// protojson so that the directory stays readable across milvus versions.
+type manifest struct {
+	Version        int                  `json:"version"`
+	ServerVersion  string               `json:"server_version,omitempty"`
+	ExportTime     string               `json:"export_time"`
+	Database       *databaseMeta        `json:"database"`
+	ResourceGroups []*resourceGroupMeta `json:"resource_groups,omitempty"`
+	RBAC           json.RawMessage      `json:"rbac,omitempty"`
+	Collections    []*collectionMeta    `json:"collections"`
+}
+
+type databaseMeta struct {
+	Name       string            `json:"name"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type resourceGroupMeta struct {
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config"`
+}
+
+type collectionMeta struct {
+	Name             string            `json:"name"`
+	Schema           json.RawMessage   `json:"schema"`
+	ShardsNum        int32             `json:"shards_num"`
+	ConsistencyLevel string            `json:"consistency_level"`
+	Properties       map[string]string `json:"properties,omitempty"`
+	NumPartitions    int64             `json:"num_partitions,omitempty"`
+	Aliases          []string          `json:"aliases,omitempty"`
+	Indexes          []json.RawMessage `json:"indexes,omitempty"`
+	Load             *loadMeta         `json:"load,omitempty"`
+	Partitions       []*partitionMeta  `json:"partitions"`
+}
+
+// loadMeta records how a collection was loaded at export time.
+type loadMeta struct {
+	Replicas       int32    `json:"replicas"`
+	ResourceGroups []string `json:"resource_groups,omitempty"`
+}
+
+// partitionMeta lists the data files of a partition. Collections using a
+// partition key hold a single entry without name, rows are routed to
+// partitions by the key on import.
+type partitionMeta struct {
+	Name  string   `json:"name,omitempty"`
+	Rows  int64    `json:"rows"`
+	Files []string `json:"files,omitempty"`
+}
+
+func (c *collectionMeta) rows() int64 {
+	var rows int64
+	for _, p := range c.Partitions {
+		rows += p.Rows
+	}
+	return rows
+}
+
+func (c *collectionMeta) schema() (*schemapb.CollectionSchema, error) {
+	schema := &schemapb.CollectionSchema{}
+	if err := protojson.Unmarshal(c.Schema, schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema of collection %s: %w", c.Name, err)
+	}
+	return schema, nil
+}
+
+func (c *collectionMeta) indexes() ([]*milvuspb.IndexDescription, error) {
+	indexes := make([]*milvuspb.IndexDescription, 0, len(c.Indexes))
+	for _, raw := range c.Indexes {
+		index := &milvuspb.IndexDescription{}
+		if err := protojson.Unmarshal(raw, index); err != nil {
+			return nil, fmt.Errorf("failed to parse index of collection %s: %w", c.Name, err)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+func (c *collectionMeta) consistencyLevel() commonpb.ConsistencyLevel {
+	level, ok := commonpb.ConsistencyLevel_value[c.ConsistencyLevel]
+	if !ok {
+		return commonpb.ConsistencyLevel_Bounded
+	}
+	return commonpb.ConsistencyLevel(level)
+}
+
+func (r *resourceGroupMeta) config() (*rgpb.ResourceGroupConfig, error) {
+	config := &rgpb.ResourceGroupConfig{}
+	if err := protojson.Unmarshal(r.Config, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config of resource group %s: %w", r.Name, err)
+	}
+	return config, nil
+}
+
+func (m *manifest) rbac() (*milvuspb.RBACMeta, error) {
+	if len(m.RBAC) == 0 {
+		return nil, nil
+	}
+	meta := &milvuspb.RBACMeta{}
+	if err := protojson.Unmarshal(m.RBAC, meta); err != nil {
+		return nil, fmt.Errorf("failed to parse rbac meta: %w", err)
+	}
+	return meta, nil
+}
+
+func marshalProto(msg proto.Message) (json.RawMessage, error) {
+	return protojson.Marshal(msg)
+}
+
+func kvToMap(kvs []*commonpb.KeyValuePair) map[string]string {
+	if len(kvs) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		m[kv.GetKey()] = kv.GetValue()
+	}
+	return m
+}
+
+func mapToKV(m map[string]string) []*commonpb.KeyValuePair {
+	kvs := make([]*commonpb.KeyValuePair, 0, len(m))
+	for k, v := range m {
+		kvs = append(kvs, &commonpb.KeyValuePair{Key: k, Value: v})
+	}
+	return kvs
+}
+
+func writeManifest(dir string, m *manifest) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(content)
+	return err
+}
+
+func readManifest(dir string) (*manifest, error) {
+	content, err := storage.ReadFile(filepath.Join(dir, manifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{}
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if m.Version > manifestVersion {
+		return nil, fmt.Errorf("manifest version %d is newer than supported version %d", m.Version, manifestVersion)
+	}
+	if m.Database == nil {
+		return nil, fmt.Errorf("manifest has no database")
+	}
+	return m, nil
+}
@@ -0,0 +1,477 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/apache/arrow/go/v17/parquet"
+	"github.com/apache/arrow/go/v17/parquet/compress"
+	"github.com/apache/arrow/go/v17/parquet/pqarrow"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// exportFields returns the fields written to parquet. Function outputs are
+// generated again on import, everything else including auto id primary keys
+// and the dynamic field is kept.
+func exportFields(schema *schemapb.CollectionSchema) []*schemapb.FieldSchema {
+	fields := make([]*schemapb.FieldSchema, 0, len(schema.GetFields()))
+	for _, field := range schema.GetFields() {
+		if field.GetIsFunctionOutput() {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// arrowType maps a field to the arrow type expected by the parquet reader of importutilv2.
+func arrowType(field *schemapb.FieldSchema, isArray bool) (arrow.DataType, error) {
+	dataType := field.GetDataType()
+	if isArray {
+		dataType = field.GetElementType()
+	}
+	switch dataType {
+	case schemapb.DataType_Bool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case schemapb.DataType_Int8:
+		return arrow.PrimitiveTypes.Int8, nil
+	case schemapb.DataType_Int16:
+		return arrow.PrimitiveTypes.Int16, nil
+	case schemapb.DataType_Int32:
+		return arrow.PrimitiveTypes.Int32, nil
+	case schemapb.DataType_Int64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case schemapb.DataType_Float:
+		return arrow.PrimitiveTypes.Float32, nil
+	case schemapb.DataType_Double:
+		return arrow.PrimitiveTypes.Float64, nil
+	case schemapb.DataType_VarChar, schemapb.DataType_String, schemapb.DataType_Text, schemapb.DataType_Timestamptz,
+		schemapb.DataType_JSON, schemapb.DataType_Geometry, schemapb.DataType_SparseFloatVector:
+		return arrow.BinaryTypes.String, nil
+	case schemapb.DataType_Array:
+		elemType, err := arrowType(field, true)
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(elemType), nil
+	case schemapb.DataType_FloatVector:
+		return arrow.ListOf(arrow.PrimitiveTypes.Float32), nil
+	case schemapb.DataType_BinaryVector, schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector:
+		return arrow.ListOf(arrow.PrimitiveTypes.Uint8), nil
+	case schemapb.DataType_Int8Vector:
+		return arrow.ListOf(arrow.PrimitiveTypes.Int8), nil
+	default:
+		return nil, fmt.Errorf("field %s: data type %s is not supported", field.GetName(), dataType.String())
+	}
+}
+
+func arrowSchema(fields []*schemapb.FieldSchema) (*arrow.Schema, error) {
+	arrowFields := make([]arrow.Field, 0, len(fields))
+	for _, field := range fields {
+		dataType, err := arrowType(field, false)
+		if err != nil {
+			return nil, err
+		}
+		arrowFields = append(arrowFields, arrow.Field{
+			Name:     field.GetName(),
+			Type:     dataType,
+			Nullable: field.GetNullable(),
+		})
+	}
+	return arrow.NewSchema(arrowFields, nil), nil
+}
+
+// rowOffsets maps each row to its offset in the values of a field, -1 for null rows.
+// Nullable fields are either returned with a placeholder for every row or
+// compacted to the valid rows only, both layouts are accepted.
+func rowOffsets(valid []bool, numValues, rows int) ([]int, error) {
+	offsets := make([]int, rows)
+	if len(valid) == 0 {
+		if numValues != rows {
+			return nil, fmt.Errorf("got %d values for %d rows", numValues, rows)
+		}
+		for i := range offsets {
+			offsets[i] = i
+		}
+		return offsets, nil
+	}
+	if len(valid) != rows {
+		return nil, fmt.Errorf("got %d valid flags for %d rows", len(valid), rows)
+	}
+	compacted := numValues != rows
+	next := 0
+	for i := range offsets {
+		switch {
+		case !valid[i]:
+			offsets[i] = -1
+		case compacted:
+			offsets[i] = next
+			next++
+		default:
+			offsets[i] = i
+		}
+	}
+	if compacted && next != numValues {
+		return nil, fmt.Errorf("got %d values for %d valid rows", numValues, next)
+	}
+	return offsets, nil
+}
+
+// fieldDataToArrow converts the field data of a query result to an arrow array of the importutilv2 layout.
+func fieldDataToArrow(field *schemapb.FieldSchema, data *schemapb.FieldData, rows int) (arrow.Array, error) {
+	dataType, err := arrowType(field, false)
+	if err != nil {
+		return nil, err
+	}
+	builder := array.NewBuilder(memory.DefaultAllocator, dataType)
+	defer builder.Release()
+
+	values, err := fieldValues(field, data)
+	if err != nil {
+		return nil, err
+	}
+	offsets, err := rowOffsets(data.GetValidData(), values.len(), rows)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", field.GetName(), err)
+	}
+	for _, offset := range offsets {
+		if offset < 0 {
+			builder.AppendNull()
+			continue
+		}
+		if err := values.append(builder, offset); err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.GetName(), err)
+		}
+	}
+	return builder.NewArray(), nil
+}
+
+// columnValues appends the values of a field data to arrow builders.
+type columnValues struct {
+	len    func() int
+	append func(b array.Builder, i int) error
+}
+
+func fixedValues[T any](data []T, appendFn func(b array.Builder, v T)) *columnValues {
+	return &columnValues{
+		len: func() int { return len(data) },
+		append: func(b array.Builder, i int) error {
+			appendFn(b, data[i])
+			return nil
+		},
+	}
+}
+
+// vectorValues splits a flat vector buffer into rows of width elements.
+func vectorValues[T any](data []T, width int, appendFn func(b *array.ListBuilder, row []T)) (*columnValues, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("invalid vector width %d", width)
+	}
+	return &columnValues{
+		len: func() int { return len(data) / width },
+		append: func(b array.Builder, i int) error {
+			lb := b.(*array.ListBuilder)
+			lb.Append(true)
+			appendFn(lb, data[i*width:(i+1)*width])
+			return nil
+		},
+	}, nil
+}
+
+func appendString(b array.Builder, v string) {
+	b.(*array.StringBuilder).Append(v)
+}
+
+func fieldValues(field *schemapb.FieldSchema, data *schemapb.FieldData) (*columnValues, error) {
+	scalars := data.GetScalars()
+	vectors := data.GetVectors()
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool:
+		return fixedValues(scalars.GetBoolData().GetData(), func(b array.Builder, v bool) {
+			b.(*array.BooleanBuilder).Append(v)
+		}), nil
+	case schemapb.DataType_Int8:
+		return fixedValues(scalars.GetIntData().GetData(), func(b array.Builder, v int32) {
+			b.(*array.Int8Builder).Append(int8(v))
+		}), nil
+	case schemapb.DataType_Int16:
+		return fixedValues(scalars.GetIntData().GetData(), func(b array.Builder, v int32) {
+			b.(*array.Int16Builder).Append(int16(v))
+		}), nil
+	case schemapb.DataType_Int32:
+		return fixedValues(scalars.GetIntData().GetData(), func(b array.Builder, v int32) {
+			b.(*array.Int32Builder).Append(v)
+		}), nil
+	case schemapb.DataType_Int64:
+		return fixedValues(scalars.GetLongData().GetData(), func(b array.Builder, v int64) {
+			b.(*array.Int64Builder).Append(v)
+		}), nil
+	case schemapb.DataType_Float:
+		return fixedValues(scalars.GetFloatData().GetData(), func(b array.Builder, v float32) {
+			b.(*array.Float32Builder).Append(v)
+		}), nil
+	case schemapb.DataType_Double:
+		return fixedValues(scalars.GetDoubleData().GetData(), func(b array.Builder, v float64) {
+			b.(*array.Float64Builder).Append(v)
+		}), nil
+	case schemapb.DataType_VarChar, schemapb.DataType_String, schemapb.DataType_Text:
+		return fixedValues(scalars.GetStringData().GetData(), appendString), nil
+	case schemapb.DataType_Timestamptz:
+		// proxy returns iso strings, raw unix micro seconds are formatted in utc
+		if scalars.GetStringData() != nil {
+			return fixedValues(scalars.GetStringData().GetData(), appendString), nil
+		}
+		return fixedValues(scalars.GetTimestamptzData().GetData(), func(b array.Builder, v int64) {
+			appendString(b, time.UnixMicro(v).UTC().Format(time.RFC3339Nano))
+		}), nil
+	case schemapb.DataType_JSON:
+		return fixedValues(scalars.GetJsonData().GetData(), func(b array.Builder, v []byte) {
+			appendString(b, string(v))
+		}), nil
+	case schemapb.DataType_Geometry:
+		if scalars.GetGeometryWktData() != nil {
+			return fixedValues(scalars.GetGeometryWktData().GetData(), appendString), nil
+		}
+		wkbs := scalars.GetGeometryData().GetData()
+		return &columnValues{
+			len: func() int { return len(wkbs) },
+			append: func(b array.Builder, i int) error {
+				wkt, err := common.ConvertWKBToWKT(wkbs[i])
+				if err != nil {
+					return err
+				}
+				appendString(b, wkt)
+				return nil
+			},
+		}, nil
+	case schemapb.DataType_Array:
+		arrays := scalars.GetArrayData().GetData()
+		return &columnValues{
+			len: func() int { return len(arrays) },
+			append: func(b array.Builder, i int) error {
+				lb := b.(*array.ListBuilder)
+				lb.Append(true)
+				return appendArrayElements(lb.ValueBuilder(), field.GetElementType(), arrays[i])
+			},
+		}, nil
+	case schemapb.DataType_SparseFloatVector:
+		contents := vectors.GetSparseFloatVector().GetContents()
+		return &columnValues{
+			len: func() int { return len(contents) },
+			append: func(b array.Builder, i int) error {
+				content, err := sparseRowToJSON(contents[i])
+				if err != nil {
+					return err
+				}
+				appendString(b, string(content))
+				return nil
+			},
+		}, nil
+	case schemapb.DataType_FloatVector:
+		return vectorValues(vectors.GetFloatVector().GetData(), int(vectors.GetDim()), func(b *array.ListBuilder, row []float32) {
+			b.ValueBuilder().(*array.Float32Builder).AppendValues(row, nil)
+		})
+	case schemapb.DataType_BinaryVector:
+		return vectorValues(vectors.GetBinaryVector(), int(vectors.GetDim())/8, appendBytes)
+	case schemapb.DataType_Float16Vector:
+		return vectorValues(vectors.GetFloat16Vector(), int(vectors.GetDim())*2, appendBytes)
+	case schemapb.DataType_BFloat16Vector:
+		return vectorValues(vectors.GetBfloat16Vector(), int(vectors.GetDim())*2, appendBytes)
+	case schemapb.DataType_Int8Vector:
+		return vectorValues(vectors.GetInt8Vector(), int(vectors.GetDim()), func(b *array.ListBuilder, row []byte) {
+			vb := b.ValueBuilder().(*array.Int8Builder)
+			for _, v := range row {
+				vb.Append(int8(v))
+			}
+		})
+	default:
+		return nil, fmt.Errorf("field %s: data type %s is not supported", field.GetName(), field.GetDataType().String())
+	}
+}
+
+func appendBytes(b *array.ListBuilder, row []byte) {
+	b.ValueBuilder().(*array.Uint8Builder).AppendValues(row, nil)
+}
+
+func appendArrayElements(b array.Builder, elementType schemapb.DataType, scalar *schemapb.ScalarField) error {
+	switch elementType {
+	case schemapb.DataType_Bool:
+		b.(*array.BooleanBuilder).AppendValues(scalar.GetBoolData().GetData(), nil)
+	case schemapb.DataType_Int8:
+		for _, v := range scalar.GetIntData().GetData() {
+			b.(*array.Int8Builder).Append(int8(v))
+		}
+	case schemapb.DataType_Int16:
+		for _, v := range scalar.GetIntData().GetData() {
+			b.(*array.Int16Builder).Append(int16(v))
+		}
+	case schemapb.DataType_Int32:
+		b.(*array.Int32Builder).AppendValues(scalar.GetIntData().GetData(), nil)
+	case schemapb.DataType_Int64:
+		b.(*array.Int64Builder).AppendValues(scalar.GetLongData().GetData(), nil)
+	case schemapb.DataType_Float:
+		b.(*array.Float32Builder).AppendValues(scalar.GetFloatData().GetData(), nil)
+	case schemapb.DataType_Double:
+		b.(*array.Float64Builder).AppendValues(scalar.GetDoubleData().GetData(), nil)
+	case schemapb.DataType_VarChar, schemapb.DataType_String:
+		b.(*array.StringBuilder).AppendValues(scalar.GetStringData().GetData(), nil)
+	default:
+		return fmt.Errorf("array element type %s is not supported", elementType.String())
+	}
+	return nil
+}
+
+// sparseRowToJSON renders a sparse row in the {"indices": [], "values": []} form accepted by import.
+func sparseRowToJSON(row []byte) ([]byte, error) {
+	n := typeutil.SparseFloatRowElementCount(row)
+	indices := make([]uint32, 0, n)
+	values := make([]float32, 0, n)
+	for i := 0; i < n; i++ {
+		indices = append(indices, typeutil.SparseFloatRowIndexAt(row, i))
+		values = append(values, typeutil.SparseFloatRowValueAt(row, i))
+	}
+	return json.Marshal(map[string]any{"indices": indices, "values": values})
+}
+
+// partitionWriter writes the rows of a partition to parquet files, a new
+// file is started every fileRows rows.
+type partitionWriter struct {
+	dir      string
+	relDir   string
+	fileRows int64
+	fields   []*schemapb.FieldSchema
+	schema   *arrow.Schema
+
+	writer   *pqarrow.FileWriter
+	fileRow  int64
+	rows     int64
+	relPaths []string
+}
+
+func newPartitionWriter(root, relDir string, fields []*schemapb.FieldSchema, fileRows int64) (*partitionWriter, error) {
+	schema, err := arrowSchema(fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(root, relDir), os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &partitionWriter{
+		dir:      root,
+		relDir:   relDir,
+		fileRows: fileRows,
+		fields:   fields,
+		schema:   schema,
+	}, nil
+}
+
+func (w *partitionWriter) open() error {
+	relPath := filepath.Join(w.relDir, fmt.Sprintf("%06d.parquet", len(w.relPaths)))
+	f, err := os.Create(filepath.Join(w.dir, relPath))
+	if err != nil {
+		return err
+	}
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Zstd))
+	writer, err := pqarrow.NewFileWriter(w.schema, f, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.writer, w.fileRow = writer, 0
+	w.relPaths = append(w.relPaths, relPath)
+	return nil
+}
+
+func (w *partitionWriter) closeFile() error {
+	if w.writer == nil {
+		return nil
+	}
+	// FileWriter.Close closes the underlying file as well
+	err := w.writer.Close()
+	w.writer = nil
+	return err
+}
+
+// write appends the rows of a query result, fieldsData is matched to the fields by name.
+func (w *partitionWriter) write(fieldsData []*schemapb.FieldData, rows int) error {
+	if rows == 0 {
+		return nil
+	}
+	byName := make(map[string]*schemapb.FieldData, len(fieldsData))
+	for _, data := range fieldsData {
+		byName[data.GetFieldName()] = data
+	}
+	cols := make([]arrow.Array, 0, len(w.fields))
+	defer func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}()
+	for _, field := range w.fields {
+		data, ok := byName[field.GetName()]
+		if !ok {
+			return fmt.Errorf("field %s is missing in query result", field.GetName())
+		}
+		col, err := fieldDataToArrow(field, data, rows)
+		if err != nil {
+			return err
+		}
+		cols = append(cols, col)
+	}
+	rec := array.NewRecord(w.schema, cols, int64(rows))
+	defer rec.Release()
+
+	for offset := int64(0); offset < int64(rows); {
+		if w.writer == nil || w.fileRow >= w.fileRows {
+			if err := w.closeFile(); err != nil {
+				return err
+			}
+			if err := w.open(); err != nil {
+				return err
+			}
+		}
+		n := min(int64(rows)-offset, w.fileRows-w.fileRow)
+		slice := rec.NewSlice(offset, offset+n)
+		err := w.writer.Write(slice)
+		slice.Release()
+		if err != nil {
+			return err
+		}
+		offset += n
+		w.fileRow += n
+		w.rows += n
+	}
+	return nil
+}
+
+func (w *partitionWriter) close() ([]string, error) {
+	if err := w.closeFile(); err != nil {
+		return nil, err
+	}
+	return w.relPaths, nil
+}
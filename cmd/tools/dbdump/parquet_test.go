@@ -0,0 +1,193 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/importutilv2/parquet"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func newTestSchema() *schemapb.CollectionSchema {
+	return &schemapb.CollectionSchema{
+		Name:               "coll",
+		EnableDynamicField: true,
+		Properties:         []*commonpb.KeyValuePair{{Key: common.AllowInsertAutoIDKey, Value: "true"}},
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true, AutoID: true},
+			{
+				FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector,
+				TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "2"}},
+			},
+			{
+				FieldID: 102, Name: "name", DataType: schemapb.DataType_VarChar,
+				TypeParams: []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "64"}},
+			},
+			{FieldID: 103, Name: "meta", DataType: schemapb.DataType_JSON, Nullable: true},
+			{
+				FieldID: 104, Name: "tags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_Int32,
+				TypeParams: []*commonpb.KeyValuePair{{Key: common.MaxCapacityKey, Value: "8"}},
+			},
+			{FieldID: 105, Name: "$meta", DataType: schemapb.DataType_JSON, IsDynamic: true},
+			{FieldID: 106, Name: "sparse", DataType: schemapb.DataType_SparseFloatVector, IsFunctionOutput: true},
+		},
+	}
+}
+
+func newTestFieldsData() []*schemapb.FieldData {
+	return []*schemapb.FieldData{
+		{
+			FieldName: "pk", Type: schemapb.DataType_Int64,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 2, 3}}},
+			}},
+		},
+		{
+			FieldName: "vec", Type: schemapb.DataType_FloatVector,
+			Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+				Dim:  2,
+				Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: []float32{1, 2, 3, 4, 5, 6}}},
+			}},
+		},
+		{
+			FieldName: "name", Type: schemapb.DataType_VarChar,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"a", "b", "c"}}},
+			}},
+		},
+		{
+			FieldName: "meta", Type: schemapb.DataType_JSON,
+			ValidData: []bool{true, false, true},
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_JsonData{JsonData: &schemapb.JSONArray{Data: [][]byte{[]byte(`{"a":1}`), nil, []byte(`{"a":3}`)}}},
+			}},
+		},
+		{
+			FieldName: "tags", Type: schemapb.DataType_Array,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_ArrayData{ArrayData: &schemapb.ArrayArray{
+					ElementType: schemapb.DataType_Int32,
+					Data: []*schemapb.ScalarField{
+						{Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{1}}}},
+						{Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{}}}},
+						{Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{2, 3}}}},
+					},
+				}},
+			}},
+		},
+		{
+			FieldName: "$meta", Type: schemapb.DataType_JSON, IsDynamic: true,
+			Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+				Data: &schemapb.ScalarField_JsonData{JsonData: &schemapb.JSONArray{Data: [][]byte{[]byte(`{}`), []byte(`{"x":1}`), []byte(`{}`)}}},
+			}},
+		},
+	}
+}
+
+func TestRowOffsets(t *testing.T) {
+	offsets, err := rowOffsets(nil, 3, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, offsets)
+
+	offsets, err = rowOffsets([]bool{true, false, true}, 3, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, -1, 2}, offsets)
+
+	offsets, err = rowOffsets([]bool{true, false, true}, 2, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, -1, 1}, offsets)
+
+	_, err = rowOffsets(nil, 2, 3)
+	assert.Error(t, err)
+	_, err = rowOffsets([]bool{true, false, true}, 1, 3)
+	assert.Error(t, err)
+}
+
+func TestFieldDataToArrow(t *testing.T) {
+	schema := newTestSchema()
+	fields := exportFields(schema)
+	assert.Len(t, fields, 6)
+
+	data := newTestFieldsData()
+	arr, err := fieldDataToArrow(fields[3], data[3], 3)
+	require.NoError(t, err)
+	defer arr.Release()
+	jsons := arr.(*array.String)
+	assert.Equal(t, `{"a":1}`, jsons.Value(0))
+	assert.True(t, jsons.IsNull(1))
+
+	arr, err = fieldDataToArrow(fields[4], data[4], 3)
+	require.NoError(t, err)
+	defer arr.Release()
+	start, end := arr.(*array.List).ValueOffsets(2)
+	assert.Equal(t, int64(2), end-start)
+
+	sparse := &schemapb.FieldData{Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+		Data: &schemapb.VectorField_SparseFloatVector{SparseFloatVector: &schemapb.SparseFloatArray{
+			Contents: [][]byte{typeutil.CreateSparseFloatRow([]uint32{3, 7}, []float32{0.5, 1})},
+		}},
+	}}}
+	arr, err = fieldDataToArrow(schema.Fields[6], sparse, 1)
+	require.NoError(t, err)
+	defer arr.Release()
+	assert.JSONEq(t, `{"indices":[3,7],"values":[0.5,1]}`, arr.(*array.String).Value(0))
+}
+
+func TestPartitionWriter(t *testing.T) {
+	schema := newTestSchema()
+	dir := t.TempDir()
+	w, err := newPartitionWriter(dir, filepath.Join(dataDirName, "coll", "0"), exportFields(schema), 2)
+	require.NoError(t, err)
+	require.NoError(t, w.write(newTestFieldsData(), 3))
+	files, err := w.close()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), w.rows)
+	require.Len(t, files, 2)
+
+	// the files must be accepted by the parquet reader of bulk import,
+	// function outputs are generated by import and left out of the schema here
+	schema.Fields = schema.Fields[:6]
+	ctx := t.Context()
+	cm := storage.NewLocalChunkManager()
+	var rows int
+	for _, f := range files {
+		reader, err := parquet.NewReader(ctx, cm, schema, filepath.Join(dir, f), 64*1024*1024)
+		require.NoError(t, err)
+		for {
+			data, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			rows += data.GetRowNum()
+		}
+		reader.Close()
+	}
+	assert.Equal(t, 3, rows)
+}
@@ -0,0 +1,89 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"path"
+	"path/filepath"
+
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/objectstorage"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// storageConfig holds the flags of the object storage used by the target
+// cluster, the parquet files are staged there for bulk import. Defaults are
+// taken from milvus.yaml.
+type storageConfig struct {
+	address       string
+	accessKey     string
+	secretKey     string
+	bucket        string
+	useSSL        bool
+	useIAM        bool
+	cloudProvider string
+	region        string
+}
+
+func (c *storageConfig) register(fs *flag.FlagSet) {
+	minioCfg := &paramtable.Get().MinioCfg
+	fs.StringVar(&c.address, "storage-address", minioCfg.Address.GetValue(), "object storage address of the target cluster")
+	fs.StringVar(&c.accessKey, "ak", minioCfg.AccessKeyID.GetValue(), "object storage access key")
+	fs.StringVar(&c.secretKey, "sk", minioCfg.SecretAccessKey.GetValue(), "object storage secret key")
+	fs.StringVar(&c.bucket, "bucket", minioCfg.BucketName.GetValue(), "bucket of the target cluster")
+	fs.BoolVar(&c.useSSL, "ssl", minioCfg.UseSSL.GetAsBool(), "use ssl to access object storage")
+	fs.BoolVar(&c.useIAM, "iam", minioCfg.UseIAM.GetAsBool(), "use iam to access object storage")
+	fs.StringVar(&c.cloudProvider, "cloud", minioCfg.CloudProvider.GetValue(), "cloud provider of object storage")
+	fs.StringVar(&c.region, "region", minioCfg.Region.GetValue(), "region of object storage")
+}
+
+func (c *storageConfig) chunkManager(ctx context.Context) (storage.ChunkManager, error) {
+	cfg := objectstorage.NewDefaultConfig()
+	for _, opt := range []objectstorage.Option{
+		objectstorage.Address(c.address),
+		objectstorage.AccessKeyID(c.accessKey),
+		objectstorage.SecretAccessKeyID(c.secretKey),
+		objectstorage.UseSSL(c.useSSL),
+		objectstorage.UseIAM(c.useIAM),
+		objectstorage.CloudProvider(c.cloudProvider),
+		objectstorage.Region(c.region),
+		objectstorage.BucketName(c.bucket),
+		objectstorage.CreateBucket(false),
+	} {
+		opt(cfg)
+	}
+	return storage.NewRemoteChunkManager(ctx, cfg)
+}
+
+// stage uploads the exported files under prefix and returns their object keys.
+func stage(ctx context.Context, cm storage.ChunkManager, dir, prefix string, relPaths []string) ([]string, error) {
+	keys := make([]string, 0, len(relPaths))
+	for _, relPath := range relPaths {
+		content, err := storage.ReadFile(filepath.Join(dir, relPath))
+		if err != nil {
+			return nil, err
+		}
+		key := path.Join(prefix, filepath.ToSlash(relPath))
+		if err := cm.Write(ctx, key, content); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
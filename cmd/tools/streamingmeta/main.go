@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tikv/client-go/v2/txnkv"
+	"go.uber.org/zap"
+
+	etcdkv "github.com/milvus-io/milvus/internal/kv/etcd"
+	tikvkv "github.com/milvus-io/milvus/internal/kv/tikv"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/etcd"
+)
+
+// The tool migrates the meta of the streaming coordinator and streaming node from etcd to tikv.
+// The milvus cluster should be stopped while migrating, then started with streaming.metaStore.type set to tikv.
+// The replicate configuration and replicating pchannels are not migrated, they're kept on etcd where the cdc watches them.
+var (
+	etcdAddr     = flag.String("etcd", "127.0.0.1:2379", "Etcd endpoints to migrate from, separated by comma")
+	etcdRootPath = flag.String("etcdRootPath", "by-dev/meta", "Meta root path of etcd, etcd.rootPath + '/' + etcd.metaSubPath")
+	tikvAddr     = flag.String("tikv", "127.0.0.1:2389", "TiKV pd endpoint to migrate to")
+	tikvRootPath = flag.String("tikvRootPath", "by-dev/meta", "Meta root path of tikv, tikv.rootPath + '/' + tikv.metaSubPath")
+	batchSize    = flag.Int("batch", 64, "Max number of keys written by one transaction")
+	dryRun       = flag.Bool("dryRun", false, "Only count the keys to migrate without writing them")
+	overwrite    = flag.Bool("overwrite", false, "Overwrite the streaming meta already existing in tikv")
+)
+
+func main() {
+	flag.Parse()
+
+	etcdCli, err := etcd.GetRemoteEtcdClient(strings.Split(*etcdAddr, ","))
+	if err != nil {
+		log.Fatal("failed to connect to etcd", zap.Error(err))
+	}
+	defer etcdCli.Close()
+	source := etcdkv.NewEtcdKV(etcdCli, *etcdRootPath)
+
+	tikvCli, err := txnkv.NewClient([]string{*tikvAddr})
+	if err != nil {
+		log.Fatal("failed to connect to tikv", zap.Error(err))
+	}
+	defer tikvCli.Close()
+	target := tikvkv.NewTiKV(tikvCli, *tikvRootPath, tikvkv.WithRequestTimeout(30*time.Second))
+
+	counts, err := migrateStreamingMeta(context.Background(), source, target, migrateOption{
+		batchSize: *batchSize,
+		dryRun:    *dryRun,
+		overwrite: *overwrite,
+	})
+	if err != nil {
+		log.Fatal("failed to migrate streaming meta", zap.Error(err))
+	}
+	for _, prefix := range streamingMetaPrefixes {
+		fmt.Printf("%s: %d keys\n", prefix, counts[prefix])
+	}
+	if *dryRun {
+		fmt.Println("dry run, nothing is written into tikv")
+		return
+	}
+	fmt.Println("streaming meta is migrated, set streaming.metaStore.type to tikv before starting milvus")
+}
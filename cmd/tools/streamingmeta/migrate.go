@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/internal/metastore/kv/streamingcoord"
+	"github.com/milvus-io/milvus/internal/metastore/kv/streamingnode"
+	"github.com/milvus-io/milvus/pkg/v2/kv"
+	"github.com/milvus-io/milvus/pkg/v2/util/etcd"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// streamingMetaPrefixes is the prefixes of the meta written by the streaming coordinator and streaming node.
+var streamingMetaPrefixes = []string{
+	streamingcoord.MetaPrefix,
+	streamingnode.MetaPrefix + "/",
+}
+
+// pinnedMetaPrefixes is the prefixes of the streaming meta kept on etcd whatever the streaming meta store is,
+// the replicate meta is watched by cdc on etcd.
+var pinnedMetaPrefixes = []string{
+	streamingcoord.ReplicateConfigurationKey,
+	streamingcoord.ReplicatePChannelMetaPrefix,
+}
+
+// isPinnedMeta returns whether the key is pinned to etcd and not migrated.
+func isPinnedMeta(key string) bool {
+	for _, prefix := range pinnedMetaPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// migrateOption is the option of the streaming meta migration.
+type migrateOption struct {
+	batchSize int
+	dryRun    bool
+	overwrite bool
+}
+
+// migrateStreamingMeta copies the streaming meta from the source meta store to the target meta store,
+// and verifies the copied values. The replicate meta pinned to etcd is skipped. It returns the number of keys of each prefix.
+func migrateStreamingMeta(ctx context.Context, source kv.BaseKV, target kv.BaseKV, opt migrateOption) (map[string]int, error) {
+	if opt.batchSize <= 0 {
+		return nil, errors.Newf("invalid batch size %d", opt.batchSize)
+	}
+	if !opt.overwrite {
+		for _, prefix := range streamingMetaPrefixes {
+			exist, err := target.HasPrefix(ctx, prefix)
+			if err != nil {
+				return nil, errors.Wrapf(err, "check prefix %s of target failed", prefix)
+			}
+			if exist {
+				return nil, errors.Newf("prefix %s already exists in target, use -overwrite to overwrite it", prefix)
+			}
+		}
+	}
+
+	counts := make(map[string]int, len(streamingMetaPrefixes))
+	for _, prefix := range streamingMetaPrefixes {
+		keys, values, err := source.LoadWithPrefix(ctx, prefix)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load prefix %s of source failed", prefix)
+		}
+		kvs := make(map[string]string, len(keys))
+		for i, key := range keys {
+			// the keys loaded may be prefixed by the root path of source.
+			key = prefix + typeutil.After(key, prefix)
+			if isPinnedMeta(key) {
+				continue
+			}
+			kvs[key] = values[i]
+		}
+		counts[prefix] = len(kvs)
+		if opt.dryRun || len(kvs) == 0 {
+			continue
+		}
+		if err := etcd.SaveByBatchWithLimit(kvs, opt.batchSize, func(partialKvs map[string]string) error {
+			return target.MultiSave(ctx, partialKvs)
+		}); err != nil {
+			return nil, errors.Wrapf(err, "save prefix %s into target failed", prefix)
+		}
+		if err := verifyStreamingMeta(ctx, target, kvs); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// verifyStreamingMeta checks the values in target are the same as the migrated ones.
+func verifyStreamingMeta(ctx context.Context, target kv.BaseKV, kvs map[string]string) error {
+	keys := make([]string, 0, len(kvs))
+	for key := range kvs {
+		keys = append(keys, key)
+	}
+	values, err := target.MultiLoad(ctx, keys)
+	if err != nil {
+		return errors.Wrap(err, "load migrated keys from target failed")
+	}
+	for i, key := range keys {
+		if values[i] != kvs[key] {
+			return errors.Newf("value of key %s mismatched after migration", key)
+		}
+	}
+	return nil
+}
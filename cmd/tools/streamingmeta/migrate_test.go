@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/kv/mem"
+)
+
+func TestMigrateStreamingMeta(t *testing.T) {
+	ctx := context.Background()
+	source := mem.NewMemoryKV()
+	assert.NoError(t, source.MultiSave(ctx, map[string]string{
+		"streamingcoord-meta/pchannel/p1":                            "pchannel-1",
+		"streamingcoord-meta/version/":                               "version",
+		"streamingcoord-meta/replicate-configuration":                "not migrated",
+		"streamingcoord-meta/replicating-pchannel/c1-p1":             "not migrated",
+		"streamingnode-meta/wal/p1/consume-checkpoint":               "checkpoint-1",
+		"streamingnode-meta/wal/p1/segment-assign/456398247934":      "segment",
+		"streamingnode-meta/wal/p2/consume-checkpoint":               "checkpoint-2",
+		"datacoord-meta/s/1/2/3":                                     "not migrated",
+		"streamingnode-meta-not-streaming/wal/p1/consume-checkpoint": "not migrated",
+	}))
+
+	// dry run writes nothing.
+	target := mem.NewMemoryKV()
+	counts, err := migrateStreamingMeta(ctx, source, target, migrateOption{batchSize: 2, dryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counts["streamingcoord-meta/"])
+	assert.Equal(t, 3, counts["streamingnode-meta/"])
+	exist, err := target.HasPrefix(ctx, "streaming")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	counts, err = migrateStreamingMeta(ctx, source, target, migrateOption{batchSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counts["streamingcoord-meta/"])
+	assert.Equal(t, 3, counts["streamingnode-meta/"])
+	value, err := target.Load(ctx, "streamingnode-meta/wal/p2/consume-checkpoint")
+	assert.NoError(t, err)
+	assert.Equal(t, "checkpoint-2", value)
+	exist, err = target.HasPrefix(ctx, "datacoord-meta")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	exist, err = target.HasPrefix(ctx, "streamingnode-meta-not-streaming")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	// the replicate meta is kept on etcd.
+	exist, err = target.HasPrefix(ctx, "streamingcoord-meta/replicat")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	// the target is not overwritten by default.
+	_, err = migrateStreamingMeta(ctx, source, target, migrateOption{batchSize: 2})
+	assert.Error(t, err)
+	_, err = migrateStreamingMeta(ctx, source, target, migrateOption{batchSize: 2, overwrite: true})
+	assert.NoError(t, err)
+
+	_, err = migrateStreamingMeta(ctx, source, target, migrateOption{batchSize: 0, overwrite: true})
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package meta
+
+import (
+	"context"
+	"path"
+
+	"github.com/cockroachdb/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+)
+
+// SinkCheckpointPrefix is the prefix of the checkpoints of the cdc sinks under the meta root.
+const SinkCheckpointPrefix = "cdc-sink"
+
+// BuildSinkCheckpointKey returns the key of the checkpoint of a sink on a pchannel.
+func BuildSinkCheckpointKey(metaRoot string, sinkType string, pchannel string) string {
+	return path.Join(metaRoot, SinkCheckpointPrefix, sinkType, pchannel)
+}
+
+// GetSinkCheckpoint gets the checkpoint of a sink from metastore, nil is returned if it's never saved.
+// Every change after the checkpoint is not published yet.
+func GetSinkCheckpoint(ctx context.Context, etcdCli *clientv3.Client, key string) (*commonpb.ReplicateCheckpoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	resp, err := etcdCli.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	cp := &commonpb.ReplicateCheckpoint{}
+	if err := proto.Unmarshal(resp.Kvs[0].Value, cp); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal sink checkpoint %s failed", key)
+	}
+	return cp, nil
+}
+
+// SaveSinkCheckpoint saves the checkpoint of a sink into metastore.
+func SaveSinkCheckpoint(ctx context.Context, etcdCli *clientv3.Client, key string, cp *commonpb.ReplicateCheckpoint) error {
+	value, err := proto.Marshal(cp)
+	if err != nil {
+		return errors.Wrapf(err, "marshal sink checkpoint %s failed", key)
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	_, err = etcdCli.Put(ctx, key, string(value))
+	return err
+}
@@ -0,0 +1,44 @@
+package meta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	kvfactory "github.com/milvus-io/milvus/internal/util/dependency/kv"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestSinkCheckpoint(t *testing.T) {
+	paramtable.Init()
+	etcdCli, _ := kvfactory.GetEtcdAndPath()
+	ctx := context.Background()
+
+	key := BuildSinkCheckpointKey("testSinkCheckpoint-"+uuid.New().String(), "kafka", "p1")
+	assert.Contains(t, key, "cdc-sink/kafka/p1")
+
+	cp, err := GetSinkCheckpoint(ctx, etcdCli, key)
+	assert.NoError(t, err)
+	assert.Nil(t, cp)
+
+	err = SaveSinkCheckpoint(ctx, etcdCli, key, &commonpb.ReplicateCheckpoint{
+		Pchannel:  "p1",
+		MessageId: &commonpb.MessageID{WALName: commonpb.WALName_Kafka, Id: "1"},
+		TimeTick:  100,
+	})
+	assert.NoError(t, err)
+
+	cp, err = GetSinkCheckpoint(ctx, etcdCli, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", cp.GetPchannel())
+	assert.Equal(t, "1", cp.GetMessageId().GetId())
+	assert.Equal(t, uint64(100), cp.GetTimeTick())
+
+	_, err = etcdCli.Put(ctx, key, "invalid")
+	assert.NoError(t, err)
+	_, err = GetSinkCheckpoint(ctx, etcdCli, key)
+	assert.Error(t, err)
+}
@@ -22,12 +22,16 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus/internal/cdc/controller"
+	"github.com/milvus-io/milvus/internal/cdc/resource"
+	"github.com/milvus-io/milvus/internal/cdc/sink"
 	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
 )
 
 type CDCServer struct {
 	ctx        context.Context
 	controller controller.Controller
+	sink       *sink.Manager
 }
 
 // NewCDCServer will return a CDCServer.
@@ -45,12 +49,23 @@ func (svr *CDCServer) Start() error {
 		log.Ctx(svr.ctx).Error("start CDC controller failed", zap.Error(err))
 		return err
 	}
+	if paramtable.Get().CDCCfg.SinkEnabled.GetAsBool() {
+		svr.sink, err = sink.NewManagerFromConfig(resource.Resource().ETCD())
+		if err != nil {
+			log.Ctx(svr.ctx).Error("create CDC sink failed", zap.Error(err))
+			return err
+		}
+		svr.sink.Start()
+	}
 	log.Ctx(svr.ctx).Info("CDCServer start successfully")
 	return nil
 }
 
 // Stop stops CDCServer.
 func (svr *CDCServer) Stop() error {
+	if svr.sink != nil {
+		svr.sink.Stop()
+	}
 	svr.controller.Stop()
 	log.Ctx(svr.ctx).Info("CDCServer stop successfully")
 	return nil
@@ -0,0 +1,407 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// Format is the encoding of the published rows.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatAvro Format = "avro"
+)
+
+// Batch is the encoded rows of an event.
+type Batch struct {
+	Format Format
+	// Key is the vchannel of the event, the rows of a key are published in order.
+	Key string
+	// Schema is the avro writer schema of the rows, nil if the format is json.
+	Schema avro.Schema
+	// Rows is a json object or an avro binary datum per row.
+	Rows [][]byte
+}
+
+// Size returns the bytes of the encoded rows.
+func (b *Batch) Size() int {
+	size := 0
+	for _, row := range b.Rows {
+		size += len(row)
+	}
+	return size
+}
+
+// Encoder encodes the rows of an event.
+type Encoder interface {
+	Encode(ev *Event) (*Batch, error)
+}
+
+// NewEncoder returns the encoder of the format.
+func NewEncoder(format Format) (Encoder, error) {
+	switch format {
+	case FormatJSON:
+		return jsonEncoder{}, nil
+	case FormatAvro:
+		return avroEncoder{}, nil
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("unknown cdc sink format %s", format)
+	}
+}
+
+// jsonRow is the json encoding of a row.
+type jsonRow struct {
+	Op         Op             `json:"op"`
+	Db         string         `json:"db"`
+	Collection string         `json:"collection"`
+	Partition  string         `json:"partition,omitempty"`
+	VChannel   string         `json:"vchannel"`
+	Timestamp  uint64         `json:"timestamp"`
+	Data       map[string]any `json:"data,omitempty"`
+	PK         any            `json:"pk,omitempty"`
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) Encode(ev *Event) (*Batch, error) {
+	columns := newColumns(ev)
+	batch := &Batch{Format: FormatJSON, Key: ev.VChannel, Rows: make([][]byte, 0, ev.NumRows)}
+	for i := 0; i < ev.NumRows; i++ {
+		row := jsonRow{
+			Op:         ev.Op,
+			Db:         dbNameOrDefault(ev.DbName),
+			Collection: ev.CollectionName,
+			Partition:  ev.PartitionName,
+			VChannel:   ev.VChannel,
+			Timestamp:  ev.TimeTick,
+		}
+		if ev.Op == OpDelete {
+			row.PK = typeutil.GetPK(ev.PrimaryKeys, int64(i))
+		} else {
+			row.Data = make(map[string]any, len(columns))
+			for _, col := range columns {
+				v := col.value(i)
+				if col.dataType == schemapb.DataType_JSON && v != nil {
+					v = json.RawMessage(v.([]byte))
+				}
+				row.Data[col.name] = v
+			}
+		}
+		bs, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+		batch.Rows = append(batch.Rows, bs)
+	}
+	return batch, nil
+}
+
+type avroEncoder struct{}
+
+// Encode encodes the rows into the avro binary encoding with a record schema derived from the columns of the event.
+// The record is named after the collection and the operation, the dynamic field $meta is named _meta.
+func (avroEncoder) Encode(ev *Event) (*Batch, error) {
+	columns := newColumns(ev)
+	schema, err := newAvroSchema(ev, columns)
+	if err != nil {
+		return nil, err
+	}
+	batch := &Batch{Format: FormatAvro, Key: ev.VChannel, Schema: schema, Rows: make([][]byte, 0, ev.NumRows)}
+	for i := 0; i < ev.NumRows; i++ {
+		datum := map[string]any{
+			"_op":         string(ev.Op),
+			"_db":         dbNameOrDefault(ev.DbName),
+			"_collection": ev.CollectionName,
+			"_partition":  ev.PartitionName,
+			"_vchannel":   ev.VChannel,
+			"_ts":         int64(ev.TimeTick),
+		}
+		for _, col := range columns {
+			v := col.value(i)
+			if col.dataType == schemapb.DataType_JSON && v != nil {
+				v = string(v.([]byte))
+			}
+			if col.nullable {
+				// the nullable column is a union of null and the value schema.
+				if v == nil {
+					v = map[string]any{}
+				} else {
+					v = map[string]any{string(col.schema.Type()): v}
+				}
+			}
+			datum[avroName(col.name)] = v
+		}
+		bs, err := avro.Marshal(schema, datum)
+		if err != nil {
+			return nil, err
+		}
+		batch.Rows = append(batch.Rows, bs)
+	}
+	return batch, nil
+}
+
+// newAvroSchema returns the record schema of the rows of an event.
+func newAvroSchema(ev *Event, columns []*column) (avro.Schema, error) {
+	stringSchema := avro.NewPrimitiveSchema(avro.String, nil)
+	longSchema := avro.NewPrimitiveSchema(avro.Long, nil)
+	metaFields := []struct {
+		name   string
+		schema avro.Schema
+	}{
+		{"_op", stringSchema},
+		{"_db", stringSchema},
+		{"_collection", stringSchema},
+		{"_partition", stringSchema},
+		{"_vchannel", stringSchema},
+		{"_ts", longSchema},
+	}
+	fields := make([]*avro.Field, 0, len(metaFields)+len(columns))
+	for _, f := range metaFields {
+		field, err := avro.NewField(f.name, f.schema)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	for _, col := range columns {
+		schema := col.schema
+		if col.nullable {
+			union, err := avro.NewUnionSchema([]avro.Schema{avro.NewNullSchema(), schema})
+			if err != nil {
+				return nil, err
+			}
+			schema = union
+		}
+		field, err := avro.NewField(avroName(col.name), schema)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return avro.NewRecordSchema(avroName(ev.CollectionName+"_"+string(ev.Op)), "milvus.cdc", fields)
+}
+
+// column is the values of a field in an event.
+type column struct {
+	name     string
+	dataType schemapb.DataType
+	schema   avro.Schema
+	nullable bool
+	value    func(i int) any
+}
+
+// newColumns returns the columns of an event, a delete event has a single pk column.
+// The fields of the types which have no row representation are skipped.
+func newColumns(ev *Event) []*column {
+	if ev.Op == OpDelete {
+		col := &column{name: "pk", value: func(i int) any { return typeutil.GetPK(ev.PrimaryKeys, int64(i)) }}
+		if ev.PrimaryKeys.GetStrId() != nil {
+			col.dataType, col.schema = schemapb.DataType_VarChar, avro.NewPrimitiveSchema(avro.String, nil)
+		} else {
+			col.dataType, col.schema = schemapb.DataType_Int64, avro.NewPrimitiveSchema(avro.Long, nil)
+		}
+		return []*column{col}
+	}
+	columns := make([]*column, 0, len(ev.FieldsData))
+	for _, field := range ev.FieldsData {
+		if col := newColumn(field); col != nil {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// newColumn returns the column of a field data, nil if the type of the field is not supported.
+func newColumn(field *schemapb.FieldData) *column {
+	scalars, vectors := field.GetScalars(), field.GetVectors()
+	var (
+		schema avro.Schema
+		n      int
+		get    func(i int) any
+	)
+	switch field.GetType() {
+	case schemapb.DataType_Bool:
+		data := scalars.GetBoolData().GetData()
+		schema, n, get = avro.NewPrimitiveSchema(avro.Boolean, nil), len(data), func(i int) any { return data[i] }
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		data := scalars.GetIntData().GetData()
+		schema, n, get = avro.NewPrimitiveSchema(avro.Int, nil), len(data), func(i int) any { return data[i] }
+	case schemapb.DataType_Int64:
+		data := scalars.GetLongData().GetData()
+		schema, n, get = avro.NewPrimitiveSchema(avro.Long, nil), len(data), func(i int) any { return data[i] }
+	case schemapb.DataType_Timestamptz:
+		data := scalars.GetTimestamptzData().GetData()
+		schema, n, get = avro.NewPrimitiveSchema(avro.Long, nil), len(data), func(i int) any { return data[i] }
+	case schemapb.DataType_Float:
+		data := scalars.GetFloatData().GetData()
+		schema, n, get = avro.NewPrimitiveSchema(avro.Float, nil), len(data), func(i int) any { return data[i] }
+	case schemapb.DataType_Double:
+		data := scalars.GetDoubleData().GetData()
+		schema, n, get = avro.NewPrimitiveSchema(avro.Double, nil), len(data), func(i int) any { return data[i] }
+	case schemapb.DataType_VarChar, schemapb.DataType_String, schemapb.DataType_Text:
+		data := scalars.GetStringData().GetData()
+		schema, n, get = avro.NewPrimitiveSchema(avro.String, nil), len(data), func(i int) any { return data[i] }
+	case schemapb.DataType_JSON:
+		data := scalars.GetJsonData().GetData()
+		schema, n, get = avro.NewPrimitiveSchema(avro.String, nil), len(data), func(i int) any { return data[i] }
+	case schemapb.DataType_Geometry:
+		data := scalars.GetGeometryData().GetData()
+		schema, n, get = avro.NewPrimitiveSchema(avro.Bytes, nil), len(data), func(i int) any { return data[i] }
+	case schemapb.DataType_Array:
+		data := scalars.GetArrayData().GetData()
+		items := newArrayItemsSchema(scalars.GetArrayData().GetElementType())
+		if items == nil {
+			return nil
+		}
+		schema, n, get = avro.NewArraySchema(items), len(data), func(i int) any { return arrayValues(data[i]) }
+	case schemapb.DataType_FloatVector:
+		dim := int(vectors.GetDim())
+		data := vectors.GetFloatVector().GetData()
+		schema, n, get = avro.NewArraySchema(avro.NewPrimitiveSchema(avro.Float, nil)), len(data)/max(dim, 1), func(i int) any {
+			return data[i*dim : (i+1)*dim]
+		}
+	case schemapb.DataType_BinaryVector, schemapb.DataType_Float16Vector, schemapb.DataType_BFloat16Vector, schemapb.DataType_Int8Vector:
+		var data []byte
+		var rowBytes int
+		switch field.GetType() {
+		case schemapb.DataType_BinaryVector:
+			data, rowBytes = vectors.GetBinaryVector(), int(vectors.GetDim())/8
+		case schemapb.DataType_Float16Vector:
+			data, rowBytes = vectors.GetFloat16Vector(), int(vectors.GetDim())*2
+		case schemapb.DataType_BFloat16Vector:
+			data, rowBytes = vectors.GetBfloat16Vector(), int(vectors.GetDim())*2
+		default:
+			data, rowBytes = vectors.GetInt8Vector(), int(vectors.GetDim())
+		}
+		schema, n, get = avro.NewPrimitiveSchema(avro.Bytes, nil), len(data)/max(rowBytes, 1), func(i int) any {
+			return data[i*rowBytes : (i+1)*rowBytes]
+		}
+	case schemapb.DataType_SparseFloatVector:
+		data := vectors.GetSparseFloatVector().GetContents()
+		schema, n, get = avro.NewMapSchema(avro.NewPrimitiveSchema(avro.Float, nil)), len(data), func(i int) any {
+			row := data[i]
+			values := make(map[string]float32, typeutil.SparseFloatRowElementCount(row))
+			for j := 0; j < typeutil.SparseFloatRowElementCount(row); j++ {
+				values[strconv.FormatUint(uint64(typeutil.SparseFloatRowIndexAt(row, j)), 10)] = typeutil.SparseFloatRowValueAt(row, j)
+			}
+			return values
+		}
+	default:
+		return nil
+	}
+
+	col := &column{name: field.GetFieldName(), dataType: field.GetType(), schema: schema, value: get}
+	validData := field.GetValidData()
+	if len(validData) == 0 {
+		return col
+	}
+	col.nullable = true
+	if n == len(validData) {
+		// the values of the null rows are zero filled.
+		col.value = func(i int) any {
+			if !validData[i] {
+				return nil
+			}
+			return get(i)
+		}
+		return col
+	}
+	// the values of the null rows are omitted.
+	idxs := make([]int, len(validData))
+	cnt := 0
+	for i, valid := range validData {
+		idxs[i] = -1
+		if valid {
+			idxs[i] = cnt
+			cnt++
+		}
+	}
+	col.value = func(i int) any {
+		if idxs[i] == -1 {
+			return nil
+		}
+		return get(idxs[i])
+	}
+	return col
+}
+
+func newArrayItemsSchema(elementType schemapb.DataType) avro.Schema {
+	switch elementType {
+	case schemapb.DataType_Bool:
+		return avro.NewPrimitiveSchema(avro.Boolean, nil)
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		return avro.NewPrimitiveSchema(avro.Int, nil)
+	case schemapb.DataType_Int64:
+		return avro.NewPrimitiveSchema(avro.Long, nil)
+	case schemapb.DataType_Float:
+		return avro.NewPrimitiveSchema(avro.Float, nil)
+	case schemapb.DataType_Double:
+		return avro.NewPrimitiveSchema(avro.Double, nil)
+	case schemapb.DataType_VarChar, schemapb.DataType_String:
+		return avro.NewPrimitiveSchema(avro.String, nil)
+	default:
+		return nil
+	}
+}
+
+func arrayValues(field *schemapb.ScalarField) any {
+	switch data := field.GetData().(type) {
+	case *schemapb.ScalarField_BoolData:
+		return data.BoolData.GetData()
+	case *schemapb.ScalarField_IntData:
+		return data.IntData.GetData()
+	case *schemapb.ScalarField_LongData:
+		return data.LongData.GetData()
+	case *schemapb.ScalarField_FloatData:
+		return data.FloatData.GetData()
+	case *schemapb.ScalarField_DoubleData:
+		return data.DoubleData.GetData()
+	case *schemapb.ScalarField_StringData:
+		return data.StringData.GetData()
+	default:
+		return []any{}
+	}
+}
+
+// avroName replaces the characters which are not allowed in an avro name with underscores.
+func avroName(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
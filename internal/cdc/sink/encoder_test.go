@@ -0,0 +1,211 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func newTestInsertEvent() *Event {
+	return &Event{
+		Op:             OpInsert,
+		CollectionName: "coll",
+		PartitionName:  "_default",
+		VChannel:       "v1",
+		TimeTick:       100,
+		NumRows:        2,
+		FieldsData: []*schemapb.FieldData{
+			{
+				FieldName: "pk",
+				Type:      schemapb.DataType_Int64,
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 2}}},
+				}},
+			},
+			{
+				// the value of the null row is omitted.
+				FieldName: "title",
+				Type:      schemapb.DataType_VarChar,
+				ValidData: []bool{true, false},
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"a"}}},
+				}},
+			},
+			{
+				FieldName: "$meta",
+				Type:      schemapb.DataType_JSON,
+				IsDynamic: true,
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_JsonData{JsonData: &schemapb.JSONArray{Data: [][]byte{[]byte(`{"x":1}`), []byte(`{}`)}}},
+				}},
+			},
+			{
+				FieldName: "tags",
+				Type:      schemapb.DataType_Array,
+				Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_ArrayData{ArrayData: &schemapb.ArrayArray{
+						ElementType: schemapb.DataType_Int32,
+						Data: []*schemapb.ScalarField{
+							{Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{1, 2}}}},
+							{Data: &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: []int32{}}}},
+						},
+					}},
+				}},
+			},
+			{
+				FieldName: "vec",
+				Type:      schemapb.DataType_FloatVector,
+				Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+					Dim:  2,
+					Data: &schemapb.VectorField_FloatVector{FloatVector: &schemapb.FloatArray{Data: []float32{0.1, 0.2, 0.3, 0.4}}},
+				}},
+			},
+			{
+				FieldName: "sparse",
+				Type:      schemapb.DataType_SparseFloatVector,
+				Field: &schemapb.FieldData_Vectors{Vectors: &schemapb.VectorField{
+					Data: &schemapb.VectorField_SparseFloatVector{SparseFloatVector: &schemapb.SparseFloatArray{
+						Dim: 10,
+						Contents: [][]byte{
+							typeutil.CreateSparseFloatRow([]uint32{3}, []float32{0.5}),
+							typeutil.CreateSparseFloatRow([]uint32{9}, []float32{1}),
+						},
+					}},
+				}},
+			},
+		},
+	}
+}
+
+func newTestDeleteEvent() *Event {
+	return &Event{
+		Op:             OpDelete,
+		DbName:         "db1",
+		CollectionName: "coll",
+		VChannel:       "v1",
+		TimeTick:       101,
+		NumRows:        1,
+		PrimaryKeys:    &schemapb.IDs{IdField: &schemapb.IDs_StrId{StrId: &schemapb.StringArray{Data: []string{"k"}}}},
+	}
+}
+
+func TestNewEncoder(t *testing.T) {
+	_, err := NewEncoder("xml")
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestJSONEncoder(t *testing.T) {
+	encoder, err := NewEncoder(FormatJSON)
+	require.NoError(t, err)
+
+	batch, err := encoder.Encode(newTestInsertEvent())
+	require.NoError(t, err)
+	assert.Equal(t, "v1", batch.Key)
+	assert.Nil(t, batch.Schema)
+	require.Len(t, batch.Rows, 2)
+	assert.Equal(t, len(batch.Rows[0])+len(batch.Rows[1]), batch.Size())
+
+	rows := make([]map[string]any, 2)
+	for i, row := range batch.Rows {
+		require.NoError(t, json.Unmarshal(row, &rows[i]))
+	}
+	assert.Equal(t, "insert", rows[0]["op"])
+	assert.Equal(t, "default", rows[0]["db"])
+	assert.Equal(t, "coll", rows[0]["collection"])
+	assert.Equal(t, float64(100), rows[0]["timestamp"])
+	data := rows[0]["data"].(map[string]any)
+	assert.Equal(t, float64(1), data["pk"])
+	assert.Equal(t, "a", data["title"])
+	assert.Equal(t, map[string]any{"x": float64(1)}, data["$meta"])
+	assert.Equal(t, []any{float64(1), float64(2)}, data["tags"])
+	assert.InDeltaSlice(t, []float64{0.1, 0.2}, data["vec"], 1e-6)
+	assert.Equal(t, map[string]any{"3": 0.5}, data["sparse"])
+	data = rows[1]["data"].(map[string]any)
+	assert.Nil(t, data["title"])
+	assert.Contains(t, data, "title")
+
+	batch, err = encoder.Encode(newTestDeleteEvent())
+	require.NoError(t, err)
+	require.Len(t, batch.Rows, 1)
+	row := make(map[string]any)
+	require.NoError(t, json.Unmarshal(batch.Rows[0], &row))
+	assert.Equal(t, "delete", row["op"])
+	assert.Equal(t, "db1", row["db"])
+	assert.Equal(t, "k", row["pk"])
+	assert.NotContains(t, row, "data")
+}
+
+func TestAvroEncoder(t *testing.T) {
+	encoder, err := NewEncoder(FormatAvro)
+	require.NoError(t, err)
+
+	batch, err := encoder.Encode(newTestInsertEvent())
+	require.NoError(t, err)
+	require.NotNil(t, batch.Schema)
+	assert.Equal(t, "milvus.cdc.coll_insert", batch.Schema.(avro.NamedSchema).FullName())
+	require.Len(t, batch.Rows, 2)
+
+	// the schema is derived from the field types.
+	record := batch.Schema.(*avro.RecordSchema)
+	fieldTypes := make(map[string]string)
+	for _, f := range record.Fields() {
+		fieldTypes[f.Name()] = string(f.Type().Type())
+	}
+	assert.Equal(t, "long", fieldTypes["pk"])
+	assert.Equal(t, "union", fieldTypes["title"])
+	assert.Equal(t, "string", fieldTypes["_meta"])
+	assert.Equal(t, "array", fieldTypes["tags"])
+	assert.Equal(t, "array", fieldTypes["vec"])
+	assert.Equal(t, "map", fieldTypes["sparse"])
+
+	rows := make([]map[string]any, 2)
+	for i, row := range batch.Rows {
+		require.NoError(t, avro.Unmarshal(batch.Schema, row, &rows[i]))
+	}
+	assert.Equal(t, "insert", rows[0]["_op"])
+	assert.Equal(t, int64(100), rows[0]["_ts"])
+	assert.Equal(t, int64(1), rows[0]["pk"])
+	assert.Equal(t, "a", rows[0]["title"])
+	assert.Equal(t, `{"x":1}`, rows[0]["_meta"])
+	assert.Len(t, rows[0]["vec"], 2)
+	assert.Equal(t, int64(2), rows[1]["pk"])
+	assert.Nil(t, rows[1]["title"])
+
+	batch, err = encoder.Encode(newTestDeleteEvent())
+	require.NoError(t, err)
+	assert.Equal(t, "milvus.cdc.coll_delete", batch.Schema.(avro.NamedSchema).FullName())
+	row := make(map[string]any)
+	require.NoError(t, avro.Unmarshal(batch.Schema, batch.Rows[0], &row))
+	assert.Equal(t, "delete", row["_op"])
+	assert.Equal(t, "db1", row["_db"])
+	assert.Equal(t, "k", row["pk"])
+}
+
+func TestAvroName(t *testing.T) {
+	assert.Equal(t, "_meta", avroName("$meta"))
+	assert.Equal(t, "_1a", avroName("1a"))
+	assert.Equal(t, "a_b", avroName("a-b"))
+}
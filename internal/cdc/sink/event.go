@@ -0,0 +1,112 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// Op is the operation of a change event.
+type Op string
+
+const (
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Event is the inserts or the deletes of a message on a collection.
+type Event struct {
+	Op             Op
+	DbName         string
+	CollectionName string
+	PartitionName  string
+	VChannel       string
+	TimeTick       uint64
+	NumRows        int
+
+	// FieldsData is the inserted columns of an insert event.
+	FieldsData []*schemapb.FieldData
+	// PrimaryKeys is the deleted primary keys of a delete event.
+	PrimaryKeys *schemapb.IDs
+}
+
+// decodeEvents decodes the change events of a message read from the wal,
+// the message of a transaction is decoded into the events of its body messages in order.
+func decodeEvents(msg message.ImmutableMessage) ([]*Event, error) {
+	if msg.MessageType() != message.MessageTypeTxn {
+		ev, err := decodeEvent(msg)
+		if err != nil || ev == nil {
+			return nil, err
+		}
+		return []*Event{ev}, nil
+	}
+	events := make([]*Event, 0)
+	err := message.AsImmutableTxnMessage(msg).RangeOver(func(im message.ImmutableMessage) error {
+		ev, err := decodeEvent(im)
+		if err != nil {
+			return err
+		}
+		if ev != nil {
+			events = append(events, ev)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// decodeEvent decodes the change event of a single message, nil is returned if the message is not a change.
+func decodeEvent(msg message.ImmutableMessage) (*Event, error) {
+	switch msg.MessageType() {
+	case message.MessageTypeInsert:
+		body, err := message.MustAsImmutableInsertMessageV1(msg).Body()
+		if err != nil {
+			return nil, err
+		}
+		return &Event{
+			Op:             OpInsert,
+			DbName:         body.GetDbName(),
+			CollectionName: body.GetCollectionName(),
+			PartitionName:  body.GetPartitionName(),
+			VChannel:       msg.VChannel(),
+			TimeTick:       msg.TimeTick(),
+			NumRows:        int(body.GetNumRows()),
+			FieldsData:     body.GetFieldsData(),
+		}, nil
+	case message.MessageTypeDelete:
+		body, err := message.MustAsImmutableDeleteMessageV1(msg).Body()
+		if err != nil {
+			return nil, err
+		}
+		return &Event{
+			Op:             OpDelete,
+			DbName:         body.GetDbName(),
+			CollectionName: body.GetCollectionName(),
+			PartitionName:  body.GetPartitionName(),
+			VChannel:       msg.VChannel(),
+			TimeTick:       msg.TimeTick(),
+			NumRows:        typeutil.GetSizeOfIDs(body.GetPrimaryKeys()),
+			PrimaryKeys:    body.GetPrimaryKeys(),
+		}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,95 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hamba/avro/v2"
+)
+
+var _ Sink = (*fileSink)(nil)
+
+// fileSink appends the batches to the local files of the route.
+// The json rows are appended to <route>.jsonl, the avro rows are appended to the object container file
+// <route>-<schema fingerprint>.avro, so a schema change of the collection starts a new file.
+type fileSink struct {
+	mu sync.Mutex
+}
+
+// NewFileSink creates a file sink.
+func NewFileSink() Sink {
+	return &fileSink{}
+}
+
+func (s *fileSink) Type() string {
+	return SinkTypeFile
+}
+
+// Publish appends the batch and syncs the file.
+func (s *fileSink) Publish(ctx context.Context, route string, batch *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := FilePath(route, batch)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrapf(err, "create the directory of %s failed", path)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return errors.Wrapf(err, "open %s failed", path)
+	}
+	defer f.Close()
+
+	if batch.Format == FormatAvro {
+		err = writeOCF(f, batch)
+	} else {
+		w := bufio.NewWriter(f)
+		for _, row := range batch.Rows {
+			w.Write(row)
+			w.WriteByte('\n')
+		}
+		err = w.Flush()
+	}
+	if err != nil {
+		return errors.Wrapf(err, "write %s failed", path)
+	}
+	return f.Sync()
+}
+
+func (s *fileSink) Close() {}
+
+// FilePath returns the path of the file the batch is appended to by the file sink.
+func FilePath(route string, batch *Batch) (string, error) {
+	if batch.Format != FormatAvro {
+		return route + ".jsonl", nil
+	}
+	fingerprint, err := batch.Schema.FingerprintUsing(avro.CRC64Avro)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%x.avro", route, fingerprint), nil
+}
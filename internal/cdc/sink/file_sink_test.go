@@ -0,0 +1,81 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink(t *testing.T) {
+	s := NewFileSink()
+	defer s.Close()
+	assert.Equal(t, SinkTypeFile, s.Type())
+	ctx := context.Background()
+	route := filepath.Join(t.TempDir(), "default", "coll")
+
+	// the json rows are appended as lines.
+	jsonBatch, err := jsonEncoder{}.Encode(newTestInsertEvent())
+	require.NoError(t, err)
+	require.NoError(t, s.Publish(ctx, route, jsonBatch))
+	require.NoError(t, s.Publish(ctx, route, jsonBatch))
+	f, err := os.OpenFile(route+".jsonl", os.O_RDONLY, 0)
+	require.NoError(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lines := 0
+	for scanner.Scan() {
+		assert.Equal(t, jsonBatch.Rows[lines%2], scanner.Bytes())
+		lines++
+	}
+	assert.Equal(t, 4, lines)
+
+	// the avro rows are appended into the container file of the schema.
+	avroBatch, err := avroEncoder{}.Encode(newTestInsertEvent())
+	require.NoError(t, err)
+	require.NoError(t, s.Publish(ctx, route, avroBatch))
+	require.NoError(t, s.Publish(ctx, route, avroBatch))
+	path, err := FilePath(route, avroBatch)
+	require.NoError(t, err)
+	f2, err := os.OpenFile(path, os.O_RDONLY, 0)
+	require.NoError(t, err)
+	defer f2.Close()
+	dec, err := ocf.NewDecoder(f2)
+	require.NoError(t, err)
+	rows := 0
+	for dec.HasNext() {
+		row := make(map[string]any)
+		require.NoError(t, dec.Decode(&row))
+		rows++
+	}
+	require.NoError(t, dec.Error())
+	assert.Equal(t, 4, rows)
+
+	// a new schema starts a new file.
+	deleteBatch, err := avroEncoder{}.Encode(newTestDeleteEvent())
+	require.NoError(t, err)
+	deletePath, err := FilePath(route, deleteBatch)
+	require.NoError(t, err)
+	assert.NotEqual(t, path, deletePath)
+}
@@ -0,0 +1,139 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/hamba/avro/v2"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+)
+
+const (
+	// KafkaHeaderContentType is the header of the content type of a published kafka message.
+	KafkaHeaderContentType = "content-type"
+	// KafkaHeaderAvroSchema is the header of the writer schema of a published avro kafka message.
+	KafkaHeaderAvroSchema = "avro.schema"
+
+	contentTypeJSON = "application/json"
+	contentTypeAvro = "avro/binary"
+)
+
+// avroSingleObjectMagic is the marker of the avro single object encoding.
+var avroSingleObjectMagic = []byte{0xC3, 0x01}
+
+var _ Sink = (*kafkaSink)(nil)
+
+// kafkaSink publishes a row per kafka message into the topic of the route,
+// the avro rows are in the single object encoding.
+type kafkaSink struct {
+	p *kafka.Producer
+}
+
+// NewKafkaSink creates a kafka sink with the brokers and the extra producer config.
+func NewKafkaSink(brokers string, producerConfig map[string]string) (Sink, error) {
+	config := &kafka.ConfigMap{
+		"bootstrap.servers":   brokers,
+		"acks":                "all",
+		"enable.idempotence":  true,
+		"go.delivery.reports": true,
+	}
+	for k, v := range producerConfig {
+		if err := config.SetKey(k, v); err != nil {
+			return nil, errors.Wrapf(err, "invalid kafka producer config %s", k)
+		}
+	}
+	p, err := kafka.NewProducer(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "create kafka producer failed")
+	}
+	s := &kafkaSink{p: p}
+	go s.logEvents()
+	return s, nil
+}
+
+func (s *kafkaSink) Type() string {
+	return SinkTypeKafka
+}
+
+// Publish produces the rows and waits until all of them are acknowledged.
+func (s *kafkaSink) Publish(ctx context.Context, route string, batch *Batch) error {
+	headers := []kafka.Header{{Key: KafkaHeaderContentType, Value: []byte(contentTypeJSON)}}
+	var prefix []byte
+	if batch.Format == FormatAvro {
+		fingerprint, err := batch.Schema.FingerprintUsing(avro.CRC64AvroLE)
+		if err != nil {
+			return err
+		}
+		prefix = append(append([]byte{}, avroSingleObjectMagic...), fingerprint...)
+		headers = []kafka.Header{
+			{Key: KafkaHeaderContentType, Value: []byte(contentTypeAvro)},
+			{Key: KafkaHeaderAvroSchema, Value: []byte(batch.Schema.String())},
+		}
+	}
+
+	deliveryCh := make(chan kafka.Event, len(batch.Rows))
+	for _, row := range batch.Rows {
+		value := row
+		if prefix != nil {
+			value = append(append(make([]byte, 0, len(prefix)+len(row)), prefix...), row...)
+		}
+		if err := s.p.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &route, Partition: kafka.PartitionAny},
+			Key:            []byte(batch.Key),
+			Value:          value,
+			Headers:        headers,
+		}, deliveryCh); err != nil {
+			return errors.Wrapf(err, "produce to kafka topic %s failed", route)
+		}
+	}
+
+	// wait for all the produced messages even if some of them failed,
+	// so no delivery report is left on a channel nobody receives.
+	var finalErr error
+	for range batch.Rows {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e := <-deliveryCh:
+			if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil {
+				finalErr = errors.Wrapf(m.TopicPartition.Error, "deliver to kafka topic %s failed", route)
+			}
+		}
+	}
+	return finalErr
+}
+
+// logEvents logs the errors reported by the producer.
+func (s *kafkaSink) logEvents() {
+	for e := range s.p.Events() {
+		if err, ok := e.(kafka.Error); ok {
+			log.Warn("kafka sink producer error", zap.Error(err))
+		}
+	}
+}
+
+func (s *kafkaSink) Close() {
+	if left := s.p.Flush(10000); left > 0 {
+		log.Warn("there are still un-flushed messages of the kafka sink", zap.Int("count", left))
+	}
+	s.p.Close()
+}
@@ -0,0 +1,94 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaSink(t *testing.T) {
+	mockCluster, err := kafka.NewMockCluster(1)
+	require.NoError(t, err)
+	defer mockCluster.Close()
+	brokers := mockCluster.BootstrapServers()
+
+	_, err = NewKafkaSink(brokers, map[string]string{"unknown.config": "1"})
+	assert.Error(t, err)
+
+	s, err := NewKafkaSink(brokers, map[string]string{"linger.ms": "0"})
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, SinkTypeKafka, s.Type())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	jsonBatch, err := jsonEncoder{}.Encode(newTestInsertEvent())
+	require.NoError(t, err)
+	require.NoError(t, s.Publish(ctx, "milvus-cdc-default-coll", jsonBatch))
+	avroBatch, err := avroEncoder{}.Encode(newTestDeleteEvent())
+	require.NoError(t, err)
+	require.NoError(t, s.Publish(ctx, "milvus-cdc-db1-coll", avroBatch))
+
+	consume := func(topic string, n int) []*kafka.Message {
+		consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+			"bootstrap.servers": brokers,
+			"group.id":          topic,
+			"auto.offset.reset": "earliest",
+		})
+		require.NoError(t, err)
+		defer consumer.Close()
+		require.NoError(t, consumer.Subscribe(topic, nil))
+		msgs := make([]*kafka.Message, 0, n)
+		for len(msgs) < n {
+			msg, err := consumer.ReadMessage(10 * time.Second)
+			require.NoError(t, err)
+			msgs = append(msgs, msg)
+		}
+		return msgs
+	}
+
+	msgs := consume("milvus-cdc-default-coll", 2)
+	for i, msg := range msgs {
+		assert.Equal(t, "v1", string(msg.Key))
+		assert.Equal(t, jsonBatch.Rows[i], msg.Value)
+		assert.Equal(t, []kafka.Header{{Key: KafkaHeaderContentType, Value: []byte(contentTypeJSON)}}, msg.Headers)
+	}
+
+	// the avro rows are published in the single object encoding.
+	msgs = consume("milvus-cdc-db1-coll", 1)
+	value := msgs[0].Value
+	fingerprint, err := avroBatch.Schema.FingerprintUsing(avro.CRC64AvroLE)
+	require.NoError(t, err)
+	assert.Equal(t, avroSingleObjectMagic, value[:2])
+	assert.Equal(t, fingerprint, value[2:10])
+	assert.Equal(t, avroBatch.Rows[0], value[10:])
+	for _, h := range msgs[0].Headers {
+		if h.Key == KafkaHeaderAvroSchema {
+			schema, err := avro.ParseBytes(h.Value)
+			require.NoError(t, err)
+			assert.Equal(t, avroBatch.Schema.String(), schema.String())
+		}
+	}
+}
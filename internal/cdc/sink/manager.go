@@ -0,0 +1,70 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/util/streamingutil/util"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// Manager publishes the changes of all the pchannels of the cluster into the sink.
+type Manager struct {
+	sink       Sink
+	publishers []*publisher
+}
+
+// NewManagerFromConfig creates the manager of the sink configured by the cdc.sink params.
+func NewManagerFromConfig(etcdCli *clientv3.Client) (*Manager, error) {
+	cfg := &paramtable.Get().CDCCfg
+	encoder, err := NewEncoder(Format(cfg.SinkFormat.GetValue()))
+	if err != nil {
+		return nil, err
+	}
+	s, err := NewSinkFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	router := NewRouter(cfg.SinkRoute.GetValue(), cfg.SinkCollections.GetAsStrings())
+
+	pchannels := util.GetAllTopicsFromConfiguration()
+	m := &Manager{sink: s, publishers: make([]*publisher, 0, pchannels.Len())}
+	for _, pchannel := range pchannels.Collect() {
+		m.publishers = append(m.publishers, newPublisher(pchannel, s, encoder, router, etcdCli))
+	}
+	return m, nil
+}
+
+// Start starts the publishers of the pchannels.
+func (m *Manager) Start() {
+	for _, p := range m.publishers {
+		p.Start()
+	}
+	log.Info("cdc sink started", zap.String("sinkType", m.sink.Type()), zap.Int("pchannels", len(m.publishers)))
+}
+
+// Stop stops the publishers and closes the sink.
+func (m *Manager) Stop() {
+	for _, p := range m.publishers {
+		p.Stop()
+	}
+	m.sink.Close()
+	log.Info("cdc sink stopped", zap.String("sinkType", m.sink.Type()))
+}
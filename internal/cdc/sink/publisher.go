@@ -0,0 +1,201 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/cdc/meta"
+	"github.com/milvus-io/milvus/internal/distributed/streaming"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message/adaptor"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/options"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/retry"
+	"github.com/milvus-io/milvus/pkg/v2/util/syncutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
+)
+
+const publisherRestartInterval = 3 * time.Second
+
+// publisher publishes the changes of a pchannel into the sink.
+// The messages are published in the order of the wal and the checkpoint is saved periodically,
+// so every change is published at least once, the changes after the last saved checkpoint
+// are published again after the publisher restarts.
+type publisher struct {
+	pchannel      string
+	sink          Sink
+	encoder       Encoder
+	router        *Router
+	etcdCli       *clientv3.Client
+	checkpointKey string
+
+	// checkpoint is the position of the last published message.
+	checkpoint      *commonpb.ReplicateCheckpoint
+	savedCheckpoint *commonpb.ReplicateCheckpoint
+
+	notifier *syncutil.AsyncTaskNotifier[struct{}]
+}
+
+func newPublisher(pchannel string, s Sink, encoder Encoder, router *Router, etcdCli *clientv3.Client) *publisher {
+	return &publisher{
+		pchannel:      pchannel,
+		sink:          s,
+		encoder:       encoder,
+		router:        router,
+		etcdCli:       etcdCli,
+		checkpointKey: meta.BuildSinkCheckpointKey(paramtable.Get().EtcdCfg.MetaRootPath.GetValue(), s.Type(), pchannel),
+		notifier:      syncutil.NewAsyncTaskNotifier[struct{}](),
+	}
+}
+
+// Start starts the publish loop, it restarts from the saved checkpoint until the publisher is stopped.
+func (p *publisher) Start() {
+	logger := log.With(zap.String("pchannel", p.pchannel), zap.String("sinkType", p.sink.Type()))
+	go func() {
+		defer p.notifier.Finish(struct{}{})
+		ctx := p.notifier.Context()
+		for {
+			err := p.publishLoop(ctx)
+			if ctx.Err() != nil {
+				logger.Info("cdc sink publisher stopped")
+				return
+			}
+			logger.Warn("cdc sink publisher failed, restart from the last checkpoint", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(publisherRestartInterval):
+			}
+		}
+	}()
+}
+
+// Stop stops the publish loop and waits for it to exit.
+func (p *publisher) Stop() {
+	p.notifier.Cancel()
+	p.notifier.BlockUntilFinish()
+}
+
+func (p *publisher) publishLoop(ctx context.Context) error {
+	cp, err := meta.GetSinkCheckpoint(ctx, p.etcdCli, p.checkpointKey)
+	if err != nil {
+		return err
+	}
+	p.checkpoint, p.savedCheckpoint = cp, cp
+
+	readOpt := streaming.ReadOption{
+		PChannel:               p.pchannel,
+		DeliverPolicy:          options.DeliverPolicyLatest(),
+		IgnorePauseConsumption: true,
+	}
+	if cp != nil {
+		messageID, err := message.UnmarshalMessageID(cp.GetMessageId())
+		if err != nil {
+			return err
+		}
+		readOpt.DeliverPolicy = options.DeliverPolicyStartFrom(messageID)
+		readOpt.DeliverFilters = []options.DeliverFilter{options.DeliverFilterTimeTickGT(cp.GetTimeTick())}
+	}
+	ch := make(adaptor.ChanMessageHandler)
+	readOpt.MessageHandler = ch
+	scanner := streaming.WAL().Read(ctx, readOpt)
+	defer scanner.Close()
+	log.Info("cdc sink publisher started", zap.String("pchannel", p.pchannel), zap.Any("checkpoint", cp))
+
+	ticker := time.NewTicker(paramtable.Get().CDCCfg.SinkCheckpointInterval.GetAsDurationByParse())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// the publisher is stopped, save the last checkpoint with a new context.
+			if err := p.saveCheckpoint(context.Background()); err != nil {
+				log.Warn("save cdc sink checkpoint failed", zap.String("pchannel", p.pchannel), zap.Error(err))
+			}
+			return ctx.Err()
+		case <-scanner.Done():
+			return scanner.Error()
+		case <-ticker.C:
+			if err := p.saveCheckpoint(ctx); err != nil {
+				log.Warn("save cdc sink checkpoint failed", zap.String("pchannel", p.pchannel), zap.Error(err))
+			}
+		case msg := <-ch:
+			if err := p.publish(ctx, msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// publish publishes the changes of a message and moves the checkpoint after it.
+func (p *publisher) publish(ctx context.Context, msg message.ImmutableMessage) error {
+	events, err := decodeEvents(msg)
+	if err != nil {
+		return err
+	}
+	sinkType := p.sink.Type()
+	for _, ev := range events {
+		route, ok := p.router.Route(ev)
+		if !ok {
+			continue
+		}
+		batch, err := p.encoder.Encode(ev)
+		if err != nil {
+			return err
+		}
+		if err := retry.Do(ctx, func() error {
+			err := p.sink.Publish(ctx, route, batch)
+			if err != nil {
+				metrics.CDCSinkPublishFailuresTotal.WithLabelValues(sinkType, p.pchannel).Inc()
+				log.Warn("publish to cdc sink failed", zap.String("route", route), zap.Error(err))
+			}
+			return err
+		}, retry.Attempts(paramtable.Get().CDCCfg.SinkMaxRetries.GetAsUint()), retry.MaxSleepTime(10*time.Second)); err != nil {
+			return err
+		}
+		metrics.CDCSinkPublishedRowsTotal.WithLabelValues(sinkType, p.pchannel, ev.CollectionName, string(ev.Op)).Add(float64(len(batch.Rows)))
+		metrics.CDCSinkPublishedBytesTotal.WithLabelValues(sinkType, p.pchannel, ev.CollectionName).Add(float64(batch.Size()))
+	}
+
+	p.checkpoint = &commonpb.ReplicateCheckpoint{
+		Pchannel:  p.pchannel,
+		MessageId: msg.LastConfirmedMessageID().IntoProto(),
+		TimeTick:  msg.TimeTick(),
+	}
+	metrics.CDCSinkLag.WithLabelValues(sinkType, p.pchannel).Set(float64(time.Since(tsoutil.PhysicalTime(msg.TimeTick())).Milliseconds()))
+	return nil
+}
+
+// saveCheckpoint saves the checkpoint if it moves since the last save.
+func (p *publisher) saveCheckpoint(ctx context.Context) error {
+	if p.checkpoint == nil || proto.Equal(p.checkpoint, p.savedCheckpoint) {
+		return nil
+	}
+	if err := meta.SaveSinkCheckpoint(ctx, p.etcdCli, p.checkpointKey, p.checkpoint); err != nil {
+		return err
+	}
+	p.savedCheckpoint = p.checkpoint
+	return nil
+}
@@ -0,0 +1,208 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/cdc/meta"
+	"github.com/milvus-io/milvus/internal/distributed/streaming"
+	"github.com/milvus-io/milvus/internal/mocks/distributed/mock_streaming"
+	kvfactory "github.com/milvus-io/milvus/internal/util/dependency/kv"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message/adaptor"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/walimpls/impls/walimplstest"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func newTestInsertMessage(collection string, tt uint64, id int64) message.ImmutableMessage {
+	return message.NewInsertMessageBuilderV1().
+		WithVChannel("v1").
+		WithHeader(&message.InsertMessageHeader{}).
+		WithBody(&msgpb.InsertRequest{
+			CollectionName: collection,
+			NumRows:        1,
+			FieldsData: []*schemapb.FieldData{
+				{FieldName: "pk", Type: schemapb.DataType_Int64, Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: []int64{id}}},
+				}}},
+			},
+		}).
+		MustBuildMutable().
+		WithTimeTick(tt).
+		WithLastConfirmed(walimplstest.NewTestMessageID(id)).
+		IntoImmutableMessage(walimplstest.NewTestMessageID(id))
+}
+
+func newTestDeleteMessage(collection string, tt uint64, id int64) message.ImmutableMessage {
+	return message.NewDeleteMessageBuilderV1().
+		WithVChannel("v1").
+		WithHeader(&message.DeleteMessageHeader{}).
+		WithBody(&msgpb.DeleteRequest{
+			CollectionName: collection,
+			NumRows:        1,
+			PrimaryKeys:    &schemapb.IDs{IdField: &schemapb.IDs_IntId{IntId: &schemapb.LongArray{Data: []int64{id}}}},
+		}).
+		MustBuildMutable().
+		WithTimeTick(tt).
+		WithLastConfirmed(walimplstest.NewTestMessageID(id)).
+		IntoImmutableMessage(walimplstest.NewTestMessageID(id))
+}
+
+func newTestTxnMessage(collection string, tt uint64, id int64) message.ImmutableMessage {
+	txnCtx := message.TxnContext{TxnID: 1, Keepalive: time.Second}
+	begin := message.NewBeginTxnMessageBuilderV2().
+		WithVChannel("v1").
+		WithHeader(&message.BeginTxnMessageHeader{}).
+		WithBody(&message.BeginTxnMessageBody{}).
+		MustBuildMutable().
+		WithTxnContext(txnCtx).
+		WithTimeTick(tt).
+		WithLastConfirmed(walimplstest.NewTestMessageID(id)).
+		IntoImmutableMessage(walimplstest.NewTestMessageID(id))
+	commit := message.NewCommitTxnMessageBuilderV2().
+		WithVChannel("v1").
+		WithHeader(&message.CommitTxnMessageHeader{}).
+		WithBody(&message.CommitTxnMessageBody{}).
+		MustBuildMutable().
+		WithTxnContext(txnCtx).
+		WithTimeTick(tt).
+		WithLastConfirmed(walimplstest.NewTestMessageID(id)).
+		IntoImmutableMessage(walimplstest.NewTestMessageID(id + 3))
+	txn, err := message.NewImmutableTxnMessageBuilder(message.MustAsImmutableBeginTxnMessageV2(begin)).
+		Add(newTestInsertMessage(collection, tt, id+1)).
+		Add(newTestDeleteMessage(collection, tt, id+2)).
+		Build(message.MustAsImmutableCommitTxnMessageV2(commit))
+	if err != nil {
+		panic(err)
+	}
+	return txn
+}
+
+// recordSink records the published batches, the first publish fails.
+type recordSink struct {
+	mu      sync.Mutex
+	failed  bool
+	routes  []string
+	batches []*Batch
+}
+
+func (s *recordSink) Type() string { return "record" }
+
+func (s *recordSink) Publish(ctx context.Context, route string, batch *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.failed {
+		s.failed = true
+		return errors.New("mock publish error")
+	}
+	s.routes = append(s.routes, route)
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *recordSink) Close() {}
+
+func (s *recordSink) published() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.batches)
+}
+
+func TestPublisher(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.EtcdCfg.MetaRootPath.Key, "testCDCSinkPublisher-"+uuid.New().String())
+	defer params.Reset(params.EtcdCfg.MetaRootPath.Key)
+	params.Save(params.CDCCfg.SinkCheckpointInterval.Key, "10ms")
+	defer params.Reset(params.CDCCfg.SinkCheckpointInterval.Key)
+	etcdCli, _ := kvfactory.GetEtcdAndPath()
+
+	msgs := []message.ImmutableMessage{
+		newTestInsertMessage("coll", 10, 1),
+		// not published by the router.
+		newTestInsertMessage("other", 11, 2),
+		newTestDeleteMessage("coll", 12, 3),
+		newTestTxnMessage("coll", 20, 10),
+	}
+	reads := make(chan streaming.ReadOption, 2)
+	mockWAL := mock_streaming.NewMockWALAccesser(t)
+	mockWAL.EXPECT().Read(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, opt streaming.ReadOption) streaming.Scanner {
+		reads <- opt
+		done := make(chan struct{})
+		scanner := mock_streaming.NewMockScanner(t)
+		scanner.EXPECT().Done().Return(done)
+		scanner.EXPECT().Error().Return(nil).Maybe()
+		scanner.EXPECT().Close().Run(func() { close(done) })
+		go func() {
+			// the second read starts after the checkpoint, nothing is left.
+			if opt.DeliverPolicy.GetLatest() == nil {
+				return
+			}
+			for _, msg := range msgs {
+				select {
+				case opt.MessageHandler.(adaptor.ChanMessageHandler) <- msg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return scanner
+	})
+	prevWAL := streaming.WAL()
+	streaming.SetWALForTest(mockWAL)
+	defer streaming.SetWALForTest(prevWAL)
+
+	s := &recordSink{}
+	encoder, err := NewEncoder(FormatJSON)
+	require.NoError(t, err)
+	p := newPublisher("p1", s, encoder, NewRouter("{db}.{collection}", []string{"coll"}), etcdCli)
+	p.Start()
+
+	// the failed publish is retried.
+	assert.Eventually(t, func() bool { return s.published() == 4 }, 10*time.Second, 10*time.Millisecond)
+	opt := <-reads
+	assert.Equal(t, "p1", opt.PChannel)
+	assert.NotNil(t, opt.DeliverPolicy.GetLatest())
+	assert.Equal(t, []string{"default.coll", "default.coll", "default.coll", "default.coll"}, s.routes)
+	assert.Equal(t, []int{1, 1, 1, 1}, []int{len(s.batches[0].Rows), len(s.batches[1].Rows), len(s.batches[2].Rows), len(s.batches[3].Rows)})
+	p.Stop()
+
+	cp, err := meta.GetSinkCheckpoint(context.Background(), etcdCli, p.checkpointKey)
+	require.NoError(t, err)
+	assert.Equal(t, "p1", cp.GetPchannel())
+	assert.Equal(t, uint64(20), cp.GetTimeTick())
+
+	// the restarted publisher resumes from the checkpoint.
+	p = newPublisher("p1", s, encoder, NewRouter("{db}.{collection}", nil), etcdCli)
+	p.Start()
+	opt = <-reads
+	assert.NotNil(t, opt.DeliverPolicy.GetStartFrom())
+	assert.Len(t, opt.DeliverFilters, 1)
+	p.Stop()
+}
@@ -0,0 +1,71 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"strings"
+
+	"github.com/milvus-io/milvus/pkg/v2/util"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const (
+	routeDbPlaceholder         = "{db}"
+	routeCollectionPlaceholder = "{collection}"
+)
+
+// Router selects the published collections and routes their events.
+type Router struct {
+	route       string
+	collections typeutil.Set[string] // db.collection, empty means all the collections.
+}
+
+// NewRouter creates a router with the route template and the published collections,
+// a collection is either db.collection or a collection of the default database.
+func NewRouter(route string, collections []string) *Router {
+	r := &Router{route: route, collections: typeutil.NewSet[string]()}
+	for _, c := range collections {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if !strings.Contains(c, ".") {
+			c = util.DefaultDBName + "." + c
+		}
+		r.collections.Insert(c)
+	}
+	return r
+}
+
+// Route returns the route of the event, false if the collection of the event is not published.
+func (r *Router) Route(ev *Event) (string, bool) {
+	db := dbNameOrDefault(ev.DbName)
+	if r.collections.Len() > 0 && !r.collections.Contain(db+"."+ev.CollectionName) {
+		return "", false
+	}
+	return strings.NewReplacer(
+		routeDbPlaceholder, db,
+		routeCollectionPlaceholder, ev.CollectionName,
+	).Replace(r.route), true
+}
+
+func dbNameOrDefault(db string) string {
+	if db == "" {
+		return util.DefaultDBName
+	}
+	return db
+}
@@ -0,0 +1,44 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	r := NewRouter("milvus-cdc-{db}-{collection}", nil)
+	route, ok := r.Route(&Event{CollectionName: "coll"})
+	assert.True(t, ok)
+	assert.Equal(t, "milvus-cdc-default-coll", route)
+	route, ok = r.Route(&Event{DbName: "db1", CollectionName: "coll"})
+	assert.True(t, ok)
+	assert.Equal(t, "milvus-cdc-db1-coll", route)
+
+	r = NewRouter("http://localhost/{collection}", []string{" coll1", "db1.coll2", ""})
+	route, ok = r.Route(&Event{DbName: "default", CollectionName: "coll1"})
+	assert.True(t, ok)
+	assert.Equal(t, "http://localhost/coll1", route)
+	_, ok = r.Route(&Event{DbName: "db1", CollectionName: "coll1"})
+	assert.False(t, ok)
+	_, ok = r.Route(&Event{DbName: "db1", CollectionName: "coll2"})
+	assert.True(t, ok)
+	_, ok = r.Route(&Event{CollectionName: "coll2"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,59 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+const (
+	SinkTypeKafka   = "kafka"
+	SinkTypeWebhook = "webhook"
+	SinkTypeFile    = "file"
+)
+
+// Sink publishes the encoded changes out of the milvus cluster.
+type Sink interface {
+	// Type returns the type of the sink.
+	Type() string
+
+	// Publish publishes a batch to the route,
+	// the batch is durable in the sink once it returns without error.
+	Publish(ctx context.Context, route string, batch *Batch) error
+
+	// Close closes the sink.
+	Close()
+}
+
+// NewSinkFromConfig creates the sink configured by the cdc.sink params.
+func NewSinkFromConfig() (Sink, error) {
+	cfg := &paramtable.Get().CDCCfg
+	switch typ := cfg.SinkType.GetValue(); typ {
+	case SinkTypeKafka:
+		return NewKafkaSink(cfg.SinkKafkaBrokers.GetValue(), cfg.SinkKafkaProducer.GetValue())
+	case SinkTypeWebhook:
+		return NewWebhookSink(cfg.SinkWebhookTimeout.GetAsDuration(time.Second), cfg.SinkWebhookHeaders.GetValue()), nil
+	case SinkTypeFile:
+		return NewFileSink(), nil
+	default:
+		return nil, merr.WrapErrParameterInvalidMsg("unknown cdc sink type %s", typ)
+	}
+}
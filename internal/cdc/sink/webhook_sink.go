@@ -0,0 +1,106 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hamba/avro/v2/ocf"
+)
+
+const contentTypeNDJSON = "application/x-ndjson"
+
+var _ Sink = (*webhookSink)(nil)
+
+// webhookSink posts a batch per request to the url of the route,
+// the json rows are posted as newline delimited json and the avro rows as an object container file.
+type webhookSink struct {
+	client  *http.Client
+	headers map[string]string
+}
+
+// NewWebhookSink creates a webhook sink with the request timeout and the extra request headers.
+func NewWebhookSink(timeout time.Duration, headers map[string]string) Sink {
+	return &webhookSink{
+		client:  &http.Client{Timeout: timeout},
+		headers: headers,
+	}
+}
+
+func (s *webhookSink) Type() string {
+	return SinkTypeWebhook
+}
+
+// Publish posts the batch, any status other than 2xx is an error.
+func (s *webhookSink) Publish(ctx context.Context, route string, batch *Batch) error {
+	body := &bytes.Buffer{}
+	contentType := contentTypeNDJSON
+	if batch.Format == FormatAvro {
+		contentType = contentTypeAvro
+		if err := writeOCF(body, batch); err != nil {
+			return err
+		}
+	} else {
+		for _, row := range batch.Rows {
+			body.Write(row)
+			body.WriteByte('\n')
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, route, body)
+	if err != nil {
+		return errors.Wrapf(err, "invalid webhook url %s", route)
+	}
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Milvus-CDC-Key", batch.Key)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "post to webhook %s failed", route)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Newf("post to webhook %s failed with status %s", route, resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() {
+	s.client.CloseIdleConnections()
+}
+
+// writeOCF writes the avro rows as an object container file, appending to w if it is an existing file.
+func writeOCF(w io.Writer, batch *Batch) error {
+	enc, err := ocf.NewEncoderWithSchema(batch.Schema, w)
+	if err != nil {
+		return err
+	}
+	for _, row := range batch.Rows {
+		if _, err := enc.Write(row); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
@@ -0,0 +1,78 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		status  = http.StatusOK
+		headers http.Header
+		body    []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	s := NewWebhookSink(time.Second, map[string]string{"Authorization": "Bearer token"})
+	defer s.Close()
+	assert.Equal(t, SinkTypeWebhook, s.Type())
+	ctx := context.Background()
+
+	jsonBatch, err := jsonEncoder{}.Encode(newTestInsertEvent())
+	require.NoError(t, err)
+	require.NoError(t, s.Publish(ctx, server.URL+"/coll", jsonBatch))
+	assert.Equal(t, contentTypeNDJSON, headers.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+	assert.Equal(t, "v1", headers.Get("X-Milvus-CDC-Key"))
+	assert.Equal(t, string(jsonBatch.Rows[0])+"\n"+string(jsonBatch.Rows[1])+"\n", string(body))
+
+	// the avro rows are posted as an object container file.
+	avroBatch, err := avroEncoder{}.Encode(newTestInsertEvent())
+	require.NoError(t, err)
+	require.NoError(t, s.Publish(ctx, server.URL+"/coll", avroBatch))
+	assert.Equal(t, contentTypeAvro, headers.Get("Content-Type"))
+	dec, err := ocf.NewDecoder(bytes.NewReader(body))
+	require.NoError(t, err)
+	assert.Equal(t, avroBatch.Schema.String(), dec.Schema().String())
+	n := 0
+	for dec.HasNext() {
+		row := make(map[string]any)
+		require.NoError(t, dec.Decode(&row))
+		n++
+	}
+	assert.Equal(t, 2, n)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, s.Publish(ctx, server.URL+"/coll", jsonBatch))
+	assert.Error(t, s.Publish(ctx, "://invalid", jsonBatch))
+}
@@ -17,9 +17,12 @@
 package compaction
 
 import (
+	"time"
+
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/json"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
 )
@@ -37,6 +40,9 @@ type Params struct {
 	TextInlineThreshold       int64                  `json:"text_inline_threshold,omitempty"`
 	TextMaxLobFileBytes       int64                  `json:"text_max_lob_file_bytes,omitempty"`
 	TextFlushThresholdBytes   int64                  `json:"text_flush_threshold_bytes,omitempty"`
+	// CompactionTimeBucket is the time bucket of the time-partitioned compaction,
+	// the output segments are split by the buckets of the compaction time field if it's set.
+	CompactionTimeBucket time.Duration `json:"compaction_time_bucket,omitempty"`
 }
 
 func GenParams() Params {
@@ -73,6 +79,11 @@ func GenerateJSONParams(schema *schemapb.CollectionSchema) (string, error) {
 			}
 		}
 	}
+	if paramtable.Get().DataCoordCfg.TimePartitionCompactionEnable.GetAsBool() &&
+		common.GetCollectionCompactionTimeFieldID(schema) >= common.StartOfUserFieldID {
+		// an invalid time bucket is rejected when the property is set, it's just ignored here.
+		compactionParams.CompactionTimeBucket, _ = common.GetCollectionCompactionTimeBucket(schema.GetProperties()...)
+	}
 	params, err := json.Marshal(compactionParams)
 	if err != nil {
 		return "", err
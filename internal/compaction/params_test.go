@@ -18,11 +18,15 @@ package compaction
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/json"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
 )
 
@@ -55,6 +59,36 @@ func TestGetJSONParams(t *testing.T) {
 	}, result)
 }
 
+func TestGetJSONParamsWithTimeBucket(t *testing.T) {
+	paramtable.Init()
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "event_time", DataType: schemapb.DataType_Timestamptz},
+		},
+		Properties: []*commonpb.KeyValuePair{
+			{Key: common.CollectionCompactionTimeBucketKey, Value: "day"},
+		},
+	}
+	parse := func() Params {
+		jsonStr, err := GenerateJSONParams(schema)
+		assert.NoError(t, err)
+		result, err := ParseParamsFromJSON(jsonStr)
+		assert.NoError(t, err)
+		return result
+	}
+
+	// the time bucket requires the time field
+	assert.Zero(t, parse().CompactionTimeBucket)
+
+	schema.Properties = append(schema.Properties, &commonpb.KeyValuePair{Key: common.CollectionCompactionTimeFieldKey, Value: "event_time"})
+	assert.Equal(t, 24*time.Hour, parse().CompactionTimeBucket)
+
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.TimePartitionCompactionEnable.Key, "false")
+	defer paramtable.Get().Reset(paramtable.Get().DataCoordCfg.TimePartitionCompactionEnable.Key)
+	assert.Zero(t, parse().CompactionTimeBucket)
+}
+
 func TestGetParamsFromJSON(t *testing.T) {
 	input := `{
 		"storage_version": 0,
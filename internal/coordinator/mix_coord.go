@@ -234,6 +234,28 @@ func (s *mixCoordImpl) initKVCreator() {
 	}
 }
 
+// newStreamingMetaKV creates the meta kv of the streaming coordinator,
+// which can be configured to tikv apart from the other coordinators by streaming.metaStore.type.
+func (s *mixCoordImpl) newStreamingMetaKV() kv.MetaKv {
+	switch Params.GetStreamingMetaStoreType() {
+	case Params.MetaStoreCfg.MetaStoreType.GetValue():
+		return s.metaKVCreator()
+	case util.MetaStoreTypeTiKV:
+		return tikv.NewTiKV(s.tikvCli, Params.TiKVCfg.MetaRootPath.GetValue(),
+			tikv.WithRequestTimeout(paramtable.Get().TiKVCfg.RequestTimeout.GetAsDuration(time.Millisecond)))
+	default:
+		return etcdkv.NewEtcdKV(s.etcdCli, Params.EtcdCfg.MetaRootPath.GetValue(),
+			etcdkv.WithRequestTimeout(paramtable.Get().EtcdCfg.RequestTimeout.GetAsDuration(time.Millisecond)))
+	}
+}
+
+// newReplicateMetaKV creates the meta kv of the replicate configuration and replicating pchannels,
+// which is always etcd whatever the streaming meta store is, because the cdc watches it on etcd.
+func (s *mixCoordImpl) newReplicateMetaKV() kv.MetaKv {
+	return etcdkv.NewEtcdKV(s.etcdCli, Params.EtcdCfg.MetaRootPath.GetValue(),
+		etcdkv.WithRequestTimeout(paramtable.Get().EtcdCfg.RequestTimeout.GetAsDuration(time.Millisecond)))
+}
+
 func (s *mixCoordImpl) Start() error {
 	return nil
 }
@@ -374,7 +396,8 @@ func (s *mixCoordImpl) initStreamingCoord() {
 
 	s.streamingCoord = streamingcoord.NewServerBuilder().
 		WithETCD(s.etcdCli).
-		WithMetaKV(s.metaKVCreator()).
+		WithMetaKV(s.newStreamingMetaKV()).
+		WithReplicateMetaKV(s.newReplicateMetaKV()).
 		WithSession(s.session).
 		WithMixCoordClient(fMixcoord).
 		Build()
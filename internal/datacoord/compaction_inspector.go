@@ -65,6 +65,7 @@ type CompactionInspector interface {
 var _ CompactionInspector = (*compactionInspector)(nil)
 
 type compactionInfo struct {
+	collectionID int64
 	state        commonpb.CompactionState
 	executingCnt int
 	completedCnt int
@@ -88,6 +89,8 @@ type compactionInspector struct {
 	handler          Handler
 	scheduler        task.GlobalScheduler
 	ievm             IndexEngineVersionManager
+	// collectionPriority weights the queued tasks by collection, nil means all the collections are equal.
+	collectionPriority CollectionPriorityFunc
 
 	stopCh   chan struct{}
 	stopOnce sync.Once
@@ -132,6 +135,7 @@ func summaryCompactionState(triggerID int64, tasks []*datapb.CompactionTask) *co
 		default:
 		}
 		mergeInfos[task.GetPlanID()] = getCompactionMergeInfo(task)
+		ret.collectionID = task.GetCollectionID()
 	}
 
 	ret.executingCnt = executingCnt + pipeliningCnt + analyzingCnt + indexingCnt + metaSavedCnt + stats
@@ -240,10 +244,8 @@ func (c *compactionInspector) schedule() []CompactionTask {
 		}
 	}()
 
-	p := getPrioritizer()
-	if &c.queueTasks.prioritizer != &p {
-		c.queueTasks.UpdatePrioritizer(p)
-	}
+	window := paramtable.Get().DataCoordCfg.CompactionCollectionPriorityWindow.GetAsDuration(time.Second)
+	c.queueTasks.UpdateOrdering(getPrioritizer(), NewCollectionPriorityRanker(c.collectionPriority, window))
 
 	// The schedule loop will stop if either:
 	// 1. no more task to schedule (the task queue is empty)
@@ -0,0 +1,238 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+)
+
+const (
+	maintenanceWindowUnrestricted = "unrestricted"
+	maintenanceWindowOpen         = "open"
+	maintenanceWindowClosed       = "closed"
+
+	// extra info keys of the GetCompactionState response.
+	compactionStateDeferredPlansKey     = "deferred_plans"
+	compactionStateMaintenanceWindowKey = "maintenance_window"
+	compactionStatePriorityKey          = "compaction_priority"
+)
+
+// isHeavyCompaction returns whether the trigger type rewrites a large amount of data,
+// heavy compactions are throttled outside the maintenance windows.
+func isHeavyCompaction(triggerType CompactionTriggerType) bool {
+	switch triggerType {
+	case TriggerTypeClustering, TriggerTypeForceMerge, TriggerTypeStorageVersionUpgrade:
+		return true
+	default:
+		return false
+	}
+}
+
+// maintenanceWindow is a daily time range [start, end), the offsets are from midnight.
+// A window with end before start crosses midnight.
+type maintenanceWindow struct {
+	start time.Duration
+	end   time.Duration
+}
+
+func (w maintenanceWindow) contains(offset time.Duration) bool {
+	if w.start <= w.end {
+		return offset >= w.start && offset < w.end
+	}
+	return offset >= w.start || offset < w.end
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid clock %s, expect HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseMaintenanceWindows parses windows in the format of HH:MM-HH:MM.
+func parseMaintenanceWindows(values []string) ([]maintenanceWindow, error) {
+	windows := make([]maintenanceWindow, 0, len(values))
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		start, end, ok := strings.Cut(value, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid maintenance window %s, expect HH:MM-HH:MM", value)
+		}
+		w := maintenanceWindow{}
+		var err error
+		if w.start, err = parseClock(start); err != nil {
+			return nil, err
+		}
+		if w.end, err = parseClock(end); err != nil {
+			return nil, err
+		}
+		if w.start == w.end {
+			return nil, fmt.Errorf("invalid maintenance window %s, start equals to end", value)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// getMaintenanceWindowState returns the state of the maintenance windows at the given time,
+// a misconfigured window never blocks the compaction.
+func getMaintenanceWindowState(now time.Time) string {
+	windows, err := parseMaintenanceWindows(Params.DataCoordCfg.CompactionMaintenanceWindows.GetAsStrings())
+	if err != nil {
+		log.RatedWarn(60, "invalid compaction maintenance windows, heavy compactions are not restricted", zap.Error(err))
+		return maintenanceWindowUnrestricted
+	}
+	if len(windows) == 0 {
+		return maintenanceWindowUnrestricted
+	}
+	loc, err := time.LoadLocation(Params.DataCoordCfg.CompactionMaintenanceWindowTimezone.GetValue())
+	if err != nil {
+		log.RatedWarn(60, "invalid compaction maintenance window timezone, use local timezone", zap.Error(err))
+		loc = time.Local
+	}
+	now = now.In(loc)
+	offset := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute + time.Duration(now.Second())*time.Second
+	for _, w := range windows {
+		if w.contains(offset) {
+			return maintenanceWindowOpen
+		}
+	}
+	return maintenanceWindowClosed
+}
+
+// maxDeferredCompactionGroups bounds the number of groups of one trigger type kept for the maintenance windows.
+const maxDeferredCompactionGroups = 4096
+
+// compactionMaintenanceWindow admits the heavy compaction views of the trigger manager.
+// Inside the maintenance windows all the views are admitted, outside the windows at most
+// outsideMaxViews views of one trigger are admitted, the rest of a manual trigger is kept
+// and submitted once a window opens. The kept views are grouped by collection, partition and channel,
+// a later trigger replaces the views kept for the same group, so retriggering never piles up stale views.
+type compactionMaintenanceWindow struct {
+	mu       sync.Mutex
+	deferred map[CompactionTriggerType]map[CompactionGroupLabel][]CompactionView
+	now      func() time.Time
+}
+
+func newCompactionMaintenanceWindow() *compactionMaintenanceWindow {
+	return &compactionMaintenanceWindow{
+		deferred: make(map[CompactionTriggerType]map[CompactionGroupLabel][]CompactionView),
+		now:      time.Now,
+	}
+}
+
+func (w *compactionMaintenanceWindow) state() string {
+	return getMaintenanceWindowState(w.now())
+}
+
+// admit returns the views allowed to be submitted now.
+// If deferRest is true, the views not admitted are kept until a window opens, otherwise they are dropped.
+func (w *compactionMaintenanceWindow) admit(triggerType CompactionTriggerType, views []CompactionView, deferRest bool) []CompactionView {
+	if !isHeavyCompaction(triggerType) || len(views) == 0 || w.state() != maintenanceWindowClosed {
+		return views
+	}
+	limit := Params.DataCoordCfg.CompactionMaintenanceWindowOutsideMaxViews.GetAsInt()
+	if limit < 0 {
+		limit = 0
+	}
+	if limit >= len(views) {
+		return views
+	}
+	admitted, rest := views[:limit], views[limit:]
+	dropped := 0
+	if deferRest {
+		dropped = w.deferViews(triggerType, rest)
+	}
+	log.Info("heavy compaction throttled outside the maintenance windows",
+		zap.String("triggerType", triggerType.String()),
+		zap.Int("admitted", len(admitted)),
+		zap.Int("throttled", len(rest)),
+		zap.Bool("deferred", deferRest),
+		zap.Int("dropped", dropped))
+	return admitted
+}
+
+// deferViews keeps the views by their groups and returns the number of the views dropped as the groups are full.
+func (w *compactionMaintenanceWindow) deferViews(triggerType CompactionTriggerType, views []CompactionView) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	groups, ok := w.deferred[triggerType]
+	if !ok {
+		groups = make(map[CompactionGroupLabel][]CompactionView)
+		w.deferred[triggerType] = groups
+	}
+	// the views of one trigger replace all the views kept for their groups by the former triggers.
+	latest := lo.GroupBy(views, func(view CompactionView) CompactionGroupLabel {
+		if label := view.GetGroupLabel(); label != nil {
+			return *label
+		}
+		return CompactionGroupLabel{}
+	})
+	dropped := 0
+	for label, groupViews := range latest {
+		if _, exist := groups[label]; !exist && len(groups) >= maxDeferredCompactionGroups {
+			dropped += len(groupViews)
+			continue
+		}
+		groups[label] = groupViews
+	}
+	return dropped
+}
+
+// popDeferred returns and clears the deferred views once a window is open.
+func (w *compactionMaintenanceWindow) popDeferred() map[CompactionTriggerType][]CompactionView {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.deferred) == 0 || w.state() == maintenanceWindowClosed {
+		return nil
+	}
+	deferred := make(map[CompactionTriggerType][]CompactionView, len(w.deferred))
+	for triggerType, groups := range w.deferred {
+		for _, views := range groups {
+			deferred[triggerType] = append(deferred[triggerType], views...)
+		}
+	}
+	w.deferred = make(map[CompactionTriggerType]map[CompactionGroupLabel][]CompactionView)
+	return deferred
+}
+
+// deferredNum returns the number of deferred views of the trigger.
+func (w *compactionMaintenanceWindow) deferredNum(triggerID int64) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cnt := 0
+	for _, groups := range w.deferred {
+		for _, views := range groups {
+			cnt += lo.CountBy(views, func(view CompactionView) bool {
+				return view.GetTriggerID() == triggerID
+			})
+		}
+	}
+	return cnt
+}
@@ -0,0 +1,162 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// triggerView is a dispatchable view stub carrying a trigger id.
+type triggerView struct {
+	stubDispatchableView
+	triggerID int64
+}
+
+func (v triggerView) GetTriggerID() int64 { return v.triggerID }
+
+func saveMaintenanceWindowParams(t *testing.T, windows string, outsideMaxViews string) {
+	params := paramtable.Get()
+	params.Save(params.DataCoordCfg.CompactionMaintenanceWindows.Key, windows)
+	params.Save(params.DataCoordCfg.CompactionMaintenanceWindowTimezone.Key, "UTC")
+	params.Save(params.DataCoordCfg.CompactionMaintenanceWindowOutsideMaxViews.Key, outsideMaxViews)
+	t.Cleanup(func() {
+		params.Reset(params.DataCoordCfg.CompactionMaintenanceWindows.Key)
+		params.Reset(params.DataCoordCfg.CompactionMaintenanceWindowTimezone.Key)
+		params.Reset(params.DataCoordCfg.CompactionMaintenanceWindowOutsideMaxViews.Key)
+	})
+}
+
+func utcClock(hour, minute int) time.Time {
+	return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseMaintenanceWindows(t *testing.T) {
+	windows, err := parseMaintenanceWindows([]string{"01:00-05:30", "", "23:00-02:00"})
+	require.NoError(t, err)
+	assert.Equal(t, []maintenanceWindow{
+		{start: time.Hour, end: 5*time.Hour + 30*time.Minute},
+		{start: 23 * time.Hour, end: 2 * time.Hour},
+	}, windows)
+
+	assert.True(t, windows[0].contains(time.Hour))
+	assert.False(t, windows[0].contains(5*time.Hour+30*time.Minute))
+	assert.True(t, windows[1].contains(23*time.Hour+30*time.Minute))
+	assert.True(t, windows[1].contains(time.Hour))
+	assert.False(t, windows[1].contains(12*time.Hour))
+
+	for _, invalid := range []string{"01:00", "1am-2am", "01:00-25:00", "03:00-03:00"} {
+		_, err = parseMaintenanceWindows([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestGetMaintenanceWindowState(t *testing.T) {
+	paramtable.Init()
+	saveMaintenanceWindowParams(t, "", "0")
+	assert.Equal(t, maintenanceWindowUnrestricted, getMaintenanceWindowState(utcClock(12, 0)))
+
+	saveMaintenanceWindowParams(t, "22:00-06:00", "0")
+	assert.Equal(t, maintenanceWindowOpen, getMaintenanceWindowState(utcClock(23, 0)))
+	assert.Equal(t, maintenanceWindowOpen, getMaintenanceWindowState(utcClock(5, 59)))
+	assert.Equal(t, maintenanceWindowClosed, getMaintenanceWindowState(utcClock(6, 0)))
+
+	// the windows are evaluated in the configured timezone.
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.CompactionMaintenanceWindowTimezone.Key, "Asia/Shanghai")
+	assert.Equal(t, maintenanceWindowOpen, getMaintenanceWindowState(utcClock(15, 0)))
+
+	// a misconfigured window never blocks the compaction.
+	saveMaintenanceWindowParams(t, "22:00", "0")
+	assert.Equal(t, maintenanceWindowUnrestricted, getMaintenanceWindowState(utcClock(12, 0)))
+}
+
+func TestCompactionMaintenanceWindowAdmit(t *testing.T) {
+	paramtable.Init()
+	saveMaintenanceWindowParams(t, "01:00-05:00", "1")
+	w := newCompactionMaintenanceWindow()
+	now := utcClock(12, 0)
+	w.now = func() time.Time { return now }
+
+	views := []CompactionView{
+		triggerView{triggerID: 1},
+		triggerView{triggerID: 1},
+		triggerView{triggerID: 1},
+	}
+
+	// light compactions are never throttled.
+	assert.Len(t, w.admit(TriggerTypeSingle, views, true), 3)
+
+	// the rest of a periodic trigger is dropped.
+	assert.Len(t, w.admit(TriggerTypeClustering, views, false), 1)
+	assert.Equal(t, 0, w.deferredNum(1))
+
+	// the rest of a manual trigger is deferred.
+	assert.Len(t, w.admit(TriggerTypeForceMerge, views, true), 1)
+	assert.Equal(t, 2, w.deferredNum(1))
+	assert.Equal(t, 0, w.deferredNum(2))
+	assert.Nil(t, w.popDeferred())
+
+	// a later trigger replaces the views deferred for the same group.
+	retriggered := []CompactionView{
+		triggerView{triggerID: 2},
+		triggerView{triggerID: 2},
+		triggerView{triggerID: 2},
+	}
+	assert.Len(t, w.admit(TriggerTypeForceMerge, retriggered, true), 1)
+	assert.Equal(t, 0, w.deferredNum(1))
+	assert.Equal(t, 2, w.deferredNum(2))
+
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.CompactionMaintenanceWindowOutsideMaxViews.Key, "0")
+	assert.Empty(t, w.admit(TriggerTypeStorageVersionUpgrade, views, false))
+
+	// all the views are admitted and the deferred ones are released inside the window.
+	now = utcClock(2, 0)
+	assert.Len(t, w.admit(TriggerTypeClustering, views, true), 3)
+	deferred := w.popDeferred()
+	assert.Len(t, deferred[TriggerTypeForceMerge], 2)
+	assert.Equal(t, 0, w.deferredNum(2))
+	assert.Nil(t, w.popDeferred())
+}
+
+func (s *CompactionTriggerManagerSuite) TestSubmitDeferredViews() {
+	saveMaintenanceWindowParams(s.T(), "01:00-05:00", "0")
+	now := utcClock(12, 0)
+	s.triggerManager.maintenanceWindow.now = func() time.Time { return now }
+
+	views := []CompactionView{
+		triggerView{triggerID: 100},
+		triggerView{triggerID: 100},
+	}
+	s.Empty(s.triggerManager.maintenanceWindow.admit(TriggerTypeForceMerge, views, true))
+	s.Equal(2, s.triggerManager.GetDeferredViewNum(100))
+
+	// nothing is submitted while the window is closed.
+	s.inspector.EXPECT().isFull().Return(false).Twice()
+	s.triggerManager.submitDeferredViews(context.Background())
+	s.Equal(2, s.triggerManager.GetDeferredViewNum(100))
+
+	// the deferred views are submitted once the window opens.
+	now = utcClock(3, 0)
+	s.triggerManager.submitDeferredViews(context.Background())
+	s.Equal(0, s.triggerManager.GetDeferredViewNum(100))
+}
@@ -0,0 +1,307 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/datacoord/allocator"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
+)
+
+// maxTimePartitionSplitBuckets is the most buckets a segment is split into by one compaction,
+// the datanode keeps a writer open per bucket. A segment spanning more buckets is left as it is.
+const maxTimePartitionSplitBuckets = 64
+
+// timePartitionCompactionPolicy compacts the segments of append-mostly time-series collections
+// into time buckets, a collection opts in with the collection.compaction.timeBucket property
+// and designates the timestamptz field to bucket by with collection.compaction.timeField.
+//
+// The buckets are keyed on the [min, max] range of the time field recorded in the segment meta,
+// the range is collected when the segment is sorted or compacted. The compaction output is split
+// by bucket on the datanode, so a segment spanning buckets is split into one segment per bucket,
+// and the small segments of the same bucket are merged. The output of a compaction never spans
+// buckets, so the policy converges.
+//
+// Segments whose rows are all expired, by the collection TTL or by the ttl_field, are compacted alone
+// without waiting for their buckets to close, the compaction filters all the rows out and drops them.
+type timePartitionCompactionPolicy struct {
+	meta      *meta
+	allocator allocator.Allocator
+	handler   Handler
+
+	now func() time.Time
+}
+
+// Ensure timePartitionCompactionPolicy implements CompactionPolicy interface
+var _ CompactionPolicy = (*timePartitionCompactionPolicy)(nil)
+
+func newTimePartitionCompactionPolicy(meta *meta, allocator allocator.Allocator, handler Handler) *timePartitionCompactionPolicy {
+	return &timePartitionCompactionPolicy{meta: meta, allocator: allocator, handler: handler, now: time.Now}
+}
+
+func (policy *timePartitionCompactionPolicy) Enable() bool {
+	return Params.DataCoordCfg.EnableAutoCompaction.GetAsBool() &&
+		Params.DataCoordCfg.TimePartitionCompactionEnable.GetAsBool()
+}
+
+func (policy *timePartitionCompactionPolicy) Name() string {
+	return "TimePartitionCompactionPolicy"
+}
+
+// getCollectionTimeBucket returns the time bucket of the collection,
+// zero means the time-partitioned compaction is not enabled for the collection.
+func getCollectionTimeBucket(coll *collectionInfo) time.Duration {
+	if coll == nil || !Params.DataCoordCfg.TimePartitionCompactionEnable.GetAsBool() {
+		return 0
+	}
+	if common.GetCollectionCompactionTimeFieldID(coll.Schema) < common.StartOfUserFieldID {
+		return 0
+	}
+	bucket, err := common.GetCollectionCompactionTimeBucketFromMap(coll.Properties)
+	if err != nil {
+		log.RatedWarn(60, "invalid collection compaction time bucket, ignored",
+			zap.Int64("collectionID", coll.ID), zap.Error(err))
+		return 0
+	}
+	return bucket
+}
+
+// getSegmentTimeBuckets returns the starts of the buckets holding the min and the max value of
+// the time field of the segment, buckets are aligned to the UTC epoch.
+// ok is false if the time field range of the segment is unknown.
+func getSegmentTimeBuckets(segment *SegmentInfo, bucket time.Duration) (first, last time.Time, ok bool) {
+	timeRange := segment.GetTimeFieldRange()
+	if len(timeRange) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+	first = time.UnixMicro(common.GetCompactionTimeBucketStart(timeRange[0], bucket)).UTC()
+	last = time.UnixMicro(common.GetCompactionTimeBucketStart(timeRange[1], bucket)).UTC()
+	return first, last, true
+}
+
+// countTimeBuckets returns the number of buckets in [first, last].
+func countTimeBuckets(first, last time.Time, bucket time.Duration) int {
+	return int(last.Sub(first)/bucket) + 1
+}
+
+// isSegmentFullyExpired returns whether all the rows of the segment are expired.
+func isSegmentFullyExpired(segment *SegmentInfo, collectionTTL time.Duration, now time.Time) bool {
+	if collectionTTL > 0 {
+		_, maxTs := extractTimestampFromBinlogs(segment.GetBinlogs())
+		if maxTs > 0 && tsoutil.PhysicalTime(maxTs).Add(collectionTTL).Before(now) {
+			return true
+		}
+	}
+	// the last quantile is the latest expiration time of the rows in the ttl_field, in microseconds,
+	// rows without expiration are recorded as MaxInt64.
+	quantiles := segment.GetExpirQuantiles()
+	if len(quantiles) == 0 {
+		return false
+	}
+	latest := quantiles[len(quantiles)-1]
+	return latest > 0 && latest <= now.UnixMicro()
+}
+
+// timePartitionCandidates is the candidate segments of a collection.
+type timePartitionCandidates struct {
+	collection    *collectionInfo
+	bucket        time.Duration
+	collectionTTL time.Duration
+	groups        []*chanPartSegments
+}
+
+func (policy *timePartitionCompactionPolicy) getCandidates(ctx context.Context) []*timePartitionCandidates {
+	result := make([]*timePartitionCandidates, 0)
+	for _, collection := range policy.meta.GetCollections() {
+		bucket := getCollectionTimeBucket(collection)
+		if bucket == 0 {
+			continue
+		}
+		if !isCollectionAutoCompactionEnabled(collection) {
+			continue
+		}
+		if policy.meta.isCollectionCompactionBlocked(collection.ID) {
+			log.Ctx(ctx).Info("skip time partition compaction for collection due to unloaded protected snapshot RefIndex",
+				zap.Int64("collectionID", collection.ID))
+			continue
+		}
+		collectionTTL, err := common.GetCollectionTTLFromMap(collection.Properties)
+		if err != nil {
+			log.Ctx(ctx).Warn("skip time partition compaction, get collection ttl failed",
+				zap.Int64("collectionID", collection.ID), zap.Error(err))
+			continue
+		}
+		groups := GetSegmentsChanPart(policy.meta, collection.ID, SegmentFilterFunc(func(segment *SegmentInfo) bool {
+			return isSegmentHealthy(segment) &&
+				isFlushed(segment) &&
+				!segment.isCompacting && // not compacting now
+				!segment.GetIsImporting() && // not importing now
+				segment.GetLevel() != datapb.SegmentLevel_L0 && // ignore level zero segments
+				segment.GetLevel() != datapb.SegmentLevel_L2 && // ignore l2 segment
+				!segment.GetIsInvisible() &&
+				(segment.GetIsSorted() || segment.GetIsSortedByNamespace()) &&
+				!policy.meta.isSegmentCompactionProtected(segment.GetID()) // not protected by snapshot
+		}))
+		for _, group := range groups {
+			group.segments = filterSegmentsWithMatchingSchemaVersion(group.segments, collection)
+		}
+		result = append(result, &timePartitionCandidates{
+			collection:    collection,
+			bucket:        bucket,
+			collectionTTL: collectionTTL,
+			groups:        groups,
+		})
+	}
+	return result
+}
+
+func (policy *timePartitionCompactionPolicy) TriggerInline(_ context.Context) (map[CompactionTriggerType][]CompactionView, error) {
+	return nil, nil
+}
+
+// Trigger splits the segments spanning buckets and merges the small segments of each bucket.
+// A segment is compacted once its last bucket has ended for bucketCloseDelay, so late writes
+// can still land in the bucket. The segments are grouped by their first bucket, the parts of
+// a split segment in the later buckets are merged with the segments of those buckets later.
+func (policy *timePartitionCompactionPolicy) Trigger(ctx context.Context) (map[CompactionTriggerType][]CompactionView, error) {
+	now := policy.now()
+	closeDelay := Params.DataCoordCfg.TimePartitionCompactionBucketCloseDelay.GetAsDuration(time.Second)
+	views := make([]CompactionView, 0)
+	for _, candidates := range policy.getCandidates(ctx) {
+		expectedSize := getExpectedSegmentSize(policy.meta, candidates.collection.ID, candidates.collection.Schema)
+		collectionViews := make([]CompactionView, 0)
+		for _, group := range candidates.groups {
+			buckets := make(map[time.Time][]*SegmentInfo)
+			for _, segment := range group.segments {
+				if isSegmentFullyExpired(segment, candidates.collectionTTL, now) {
+					collectionViews = append(collectionViews, newExpiredTimePartitionView(segment, candidates))
+					continue
+				}
+				first, last, ok := getSegmentTimeBuckets(segment, candidates.bucket)
+				if !ok || last.Add(candidates.bucket).Add(closeDelay).After(now) {
+					continue
+				}
+				if spanned := countTimeBuckets(first, last, candidates.bucket); spanned > maxTimePartitionSplitBuckets {
+					log.Ctx(ctx).RatedInfo(60, "skip time partition compaction of segment spanning too many buckets",
+						zap.Int64("segmentID", segment.GetID()), zap.Int("buckets", spanned))
+					continue
+				}
+				// full segments within one bucket are left as they are.
+				if first.Equal(last) && segment.getSegmentSize() >= expectedSize {
+					continue
+				}
+				buckets[first] = append(buckets[first], segment)
+			}
+			for first, segments := range buckets {
+				for _, pack := range packTimeBucketSegments(segments, expectedSize, candidates.bucket) {
+					segmentViews := GetViewsByInfo(pack...)
+					collectionViews = append(collectionViews, &TimePartitionSegmentsView{
+						label:         segmentViews[0].label,
+						segments:      segmentViews,
+						collectionTTL: candidates.collectionTTL,
+						bucketStart:   first,
+						bucketEnd:     first.Add(candidates.bucket),
+						outputBuckets: countOutputTimeBuckets(pack, candidates.bucket),
+					})
+				}
+			}
+		}
+		if len(collectionViews) == 0 {
+			continue
+		}
+		triggerID, err := policy.allocator.AllocID(ctx)
+		if err != nil {
+			log.Ctx(ctx).Warn("fail to apply timePartitionCompactionPolicy, unable to allocate triggerID", zap.Error(err))
+			return nil, err
+		}
+		for _, view := range collectionViews {
+			view.(*TimePartitionSegmentsView).triggerID = triggerID
+		}
+		log.Ctx(ctx).Info("succeeded to apply timePartitionCompactionPolicy",
+			zap.Int64("collectionID", candidates.collection.ID),
+			zap.Int64("triggerID", triggerID),
+			zap.Int("triggered view num", len(collectionViews)))
+		views = append(views, collectionViews...)
+	}
+	return map[CompactionTriggerType][]CompactionView{TriggerTypeTimePartition: views}, nil
+}
+
+// newExpiredTimePartitionView returns the view compacting a fully expired segment alone.
+func newExpiredTimePartitionView(segment *SegmentInfo, candidates *timePartitionCandidates) *TimePartitionSegmentsView {
+	first, last, _ := getSegmentTimeBuckets(segment, candidates.bucket)
+	segmentViews := GetViewsByInfo(segment)
+	return &TimePartitionSegmentsView{
+		label:         segmentViews[0].label,
+		segments:      segmentViews,
+		collectionTTL: candidates.collectionTTL,
+		bucketStart:   first,
+		bucketEnd:     last.Add(candidates.bucket),
+		expired:       true,
+	}
+}
+
+// packTimeBucketSegments packs the segments of one bucket into groups no larger than expectedSize,
+// smaller segments first. A group with a single segment is skipped unless the segment spans
+// buckets and needs to be split.
+func packTimeBucketSegments(segments []*SegmentInfo, expectedSize int64, bucket time.Duration) [][]*SegmentInfo {
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].getSegmentSize() < segments[j].getSegmentSize()
+	})
+	spanning := func(segment *SegmentInfo) bool {
+		first, last, _ := getSegmentTimeBuckets(segment, bucket)
+		return !first.Equal(last)
+	}
+	packs := make([][]*SegmentInfo, 0)
+	var pack []*SegmentInfo
+	var packSize int64
+	flush := func() {
+		if len(pack) > 1 || (len(pack) == 1 && spanning(pack[0])) {
+			packs = append(packs, pack)
+		}
+		pack, packSize = nil, 0
+	}
+	for _, segment := range segments {
+		size := segment.getSegmentSize()
+		if len(pack) > 0 && packSize+size > expectedSize {
+			flush()
+		}
+		pack = append(pack, segment)
+		packSize += size
+	}
+	flush()
+	return packs
+}
+
+// countOutputTimeBuckets returns the number of buckets the compaction of the segments writes into.
+func countOutputTimeBuckets(segments []*SegmentInfo, bucket time.Duration) int {
+	buckets := make(map[time.Time]struct{})
+	for _, segment := range segments {
+		first, last, _ := getSegmentTimeBuckets(segment, bucket)
+		for start := first; !start.After(last); start = start.Add(bucket) {
+			buckets[start] = struct{}{}
+		}
+	}
+	return len(buckets)
+}
@@ -0,0 +1,316 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/metastore/model"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func TestTimePartitionCompactionPolicySuite(t *testing.T) {
+	suite.Run(t, new(TimePartitionCompactionPolicySuite))
+}
+
+type TimePartitionCompactionPolicySuite struct {
+	suite.Suite
+
+	testLabel *CompactionGroupLabel
+	meta      *meta
+	now       time.Time
+
+	policy *timePartitionCompactionPolicy
+}
+
+func utcTime(hour, minute int) time.Time {
+	return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func buildTimeBucketSegment(id int64, label *CompactionGroupLabel, from, to time.Time, size int64) *SegmentInfo {
+	return &SegmentInfo{
+		SegmentInfo: &datapb.SegmentInfo{
+			ID:            id,
+			CollectionID:  label.CollectionID,
+			PartitionID:   label.PartitionID,
+			InsertChannel: label.Channel,
+			Level:         datapb.SegmentLevel_L1,
+			State:         commonpb.SegmentState_Flushed,
+			NumOfRows:     100,
+			IsSorted:      true,
+			// the rows are written when their time field values are
+			TimeFieldRange: []int64{from.UnixMicro(), to.UnixMicro()},
+			Binlogs: []*datapb.FieldBinlog{{
+				FieldID: 1,
+				Binlogs: []*datapb.Binlog{{
+					EntriesNum:    100,
+					MemorySize:    size,
+					TimestampFrom: tsoutil.ComposeTSByTime(from, 0),
+					TimestampTo:   tsoutil.ComposeTSByTime(to, 0),
+				}},
+			}},
+		},
+	}
+}
+
+func (s *TimePartitionCompactionPolicySuite) SetupTest() {
+	paramtable.Init()
+	// expected segment size is 1MB
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.SegmentMaxSize.Key, "1")
+	s.T().Cleanup(func() { paramtable.Get().Reset(paramtable.Get().DataCoordCfg.SegmentMaxSize.Key) })
+
+	s.testLabel = &CompactionGroupLabel{
+		CollectionID: 1,
+		PartitionID:  10,
+		Channel:      "ch-1",
+	}
+	s.now = utcTime(12, 30)
+	s.meta = &meta{
+		segments:    NewSegmentsInfo(),
+		collections: typeutil.NewConcurrentMap[UniqueID, *collectionInfo](),
+		indexMeta:   &indexMeta{indexes: map[UniqueID]map[UniqueID]*model.Index{}},
+	}
+	segments := []*SegmentInfo{
+		// bucket 10:00, closed
+		buildTimeBucketSegment(1, s.testLabel, utcTime(10, 5), utcTime(10, 20), 100*1024),
+		buildTimeBucketSegment(2, s.testLabel, utcTime(10, 30), utcTime(10, 50), 100*1024),
+		// spans the buckets 9:00 and 10:00, split alone
+		buildTimeBucketSegment(3, s.testLabel, utcTime(9, 50), utcTime(10, 10), 100*1024),
+		// full segment is left as it is
+		buildTimeBucketSegment(4, s.testLabel, utcTime(10, 40), utcTime(10, 45), 2*1024*1024),
+		// bucket 11:00, closed but nothing to merge with
+		buildTimeBucketSegment(5, s.testLabel, utcTime(11, 10), utcTime(11, 20), 100*1024),
+		// bucket 12:00, still open
+		buildTimeBucketSegment(6, s.testLabel, utcTime(12, 5), utcTime(12, 10), 100*1024),
+		buildTimeBucketSegment(7, s.testLabel, utcTime(12, 15), utcTime(12, 20), 100*1024),
+		// spans the buckets 11:00 and 12:00, the bucket 12:00 is still open
+		buildTimeBucketSegment(8, s.testLabel, utcTime(11, 50), utcTime(12, 10), 100*1024),
+		// the time field range is unknown
+		buildTimeBucketSegment(9, s.testLabel, utcTime(10, 0), utcTime(10, 1), 100*1024),
+	}
+	segments[8].TimeFieldRange = nil
+	for _, segment := range segments {
+		s.meta.segments.SetSegment(segment.GetID(), segment)
+	}
+	s.setCollectionProperties(map[string]string{common.CollectionCompactionTimeBucketKey: "hour"})
+
+	s.policy = newTimePartitionCompactionPolicy(s.meta, newMockAllocator(s.T()), NewNMockHandler(s.T()))
+	s.policy.now = func() time.Time { return s.now }
+}
+
+// setCollectionProperties sets the properties of the collection, the time field is set
+// if the time bucket is set.
+func (s *TimePartitionCompactionPolicySuite) setCollectionProperties(properties map[string]string) {
+	if _, ok := properties[common.CollectionCompactionTimeBucketKey]; ok {
+		properties[common.CollectionCompactionTimeFieldKey] = "event_time"
+	}
+	s.meta.collections.Insert(s.testLabel.CollectionID, &collectionInfo{
+		ID: s.testLabel.CollectionID,
+		Schema: &schemapb.CollectionSchema{
+			Fields: []*schemapb.FieldSchema{
+				{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+				{FieldID: 101, Name: "event_time", DataType: schemapb.DataType_Timestamptz},
+			},
+			Properties: common.NewKeyValuePairs(properties),
+		},
+		Properties: properties,
+	})
+}
+
+func segmentIDsOfView(view CompactionView) []int64 {
+	return lo.Map(view.GetSegmentsView(), func(v *SegmentView, _ int) int64 { return v.ID })
+}
+
+func (s *TimePartitionCompactionPolicySuite) TestTrigger() {
+	events, err := s.policy.Trigger(context.Background())
+	s.NoError(err)
+	views := events[TriggerTypeTimePartition]
+	s.Require().Len(views, 2)
+	viewsByBucket := lo.KeyBy(views, func(view CompactionView) time.Time {
+		return view.(*TimePartitionSegmentsView).bucketStart.UTC()
+	})
+
+	view := viewsByBucket[utcTime(10, 0)].(*TimePartitionSegmentsView)
+	s.False(view.IsInlineExecutable())
+	s.Equal(utcTime(11, 0), view.bucketEnd.UTC())
+	s.NotZero(view.GetTriggerID())
+	s.Equal(1, view.outputBuckets)
+	s.ElementsMatch([]int64{1, 2}, segmentIDsOfView(view))
+
+	// the segment spanning buckets is split even if it's alone.
+	view = viewsByBucket[utcTime(9, 0)].(*TimePartitionSegmentsView)
+	s.Equal(2, view.outputBuckets)
+	s.ElementsMatch([]int64{3}, segmentIDsOfView(view))
+
+	// the bucket 12:00 is closed after the close delay.
+	s.now = utcTime(13, 10)
+	events, err = s.policy.Trigger(context.Background())
+	s.NoError(err)
+	views = events[TriggerTypeTimePartition]
+	s.Require().Len(views, 4)
+	viewsByBucket = lo.KeyBy(views, func(view CompactionView) time.Time {
+		return view.(*TimePartitionSegmentsView).bucketStart.UTC()
+	})
+	s.ElementsMatch([]int64{5, 8}, segmentIDsOfView(viewsByBucket[utcTime(11, 0)]))
+	s.Equal(2, viewsByBucket[utcTime(11, 0)].(*TimePartitionSegmentsView).outputBuckets)
+	s.ElementsMatch([]int64{6, 7}, segmentIDsOfView(viewsByBucket[utcTime(12, 0)]))
+}
+
+func (s *TimePartitionCompactionPolicySuite) TestTriggerWithoutTimeField() {
+	s.meta.collections.Insert(s.testLabel.CollectionID, &collectionInfo{
+		ID:         s.testLabel.CollectionID,
+		Schema:     &schemapb.CollectionSchema{},
+		Properties: map[string]string{common.CollectionCompactionTimeBucketKey: "hour"},
+	})
+	s.Zero(getCollectionTimeBucket(s.meta.GetCollection(s.testLabel.CollectionID)))
+	events, err := s.policy.Trigger(context.Background())
+	s.NoError(err)
+	s.Empty(events[TriggerTypeTimePartition])
+}
+
+func (s *TimePartitionCompactionPolicySuite) TestTriggerNotBucketed() {
+	s.setCollectionProperties(nil)
+	events, err := s.policy.Trigger(context.Background())
+	s.NoError(err)
+	s.Empty(events[TriggerTypeTimePartition])
+
+	inlineEvents, err := s.policy.TriggerInline(context.Background())
+	s.NoError(err)
+	s.Empty(inlineEvents)
+
+	s.setCollectionProperties(map[string]string{common.CollectionCompactionTimeBucketKey: "hour"})
+	paramtable.Get().Save(paramtable.Get().DataCoordCfg.TimePartitionCompactionEnable.Key, "false")
+	defer paramtable.Get().Reset(paramtable.Get().DataCoordCfg.TimePartitionCompactionEnable.Key)
+	s.False(s.policy.Enable())
+	events, err = s.policy.Trigger(context.Background())
+	s.NoError(err)
+	s.Empty(events[TriggerTypeTimePartition])
+}
+
+func (s *TimePartitionCompactionPolicySuite) TestTriggerExpired() {
+	s.setCollectionProperties(map[string]string{
+		common.CollectionCompactionTimeBucketKey: "hour",
+		common.CollectionTTLConfigKey:            "7200",
+	})
+	inlineEvents, err := s.policy.TriggerInline(context.Background())
+	s.NoError(err)
+	s.Empty(inlineEvents)
+
+	// the expired segments are compacted alone, the compaction drops them.
+	events, err := s.policy.Trigger(context.Background())
+	s.NoError(err)
+	views := events[TriggerTypeTimePartition]
+	s.Require().Len(views, 2)
+	for _, view := range views {
+		s.True(view.(*TimePartitionSegmentsView).expired)
+		s.False(view.IsInlineExecutable())
+		s.NotZero(view.GetTriggerID())
+		s.Len(view.GetSegmentsView(), 1)
+	}
+	s.ElementsMatch([]int64{1, 3}, lo.Map(views, func(v CompactionView, _ int) int64 { return v.GetSegmentsView()[0].ID }))
+}
+
+func (s *TimePartitionCompactionPolicySuite) TestIsSegmentFullyExpired() {
+	segment := buildTimeBucketSegment(100, s.testLabel, utcTime(10, 0), utcTime(11, 0), 1024)
+	s.False(isSegmentFullyExpired(segment, 0, s.now))
+	s.True(isSegmentFullyExpired(segment, time.Hour, s.now))
+	s.False(isSegmentFullyExpired(segment, 2*time.Hour, s.now))
+
+	segment.ExpirQuantiles = []int64{1, 2, 3, 4, utcTime(12, 0).UnixMicro()}
+	s.True(isSegmentFullyExpired(segment, 0, s.now))
+	// rows without expiration never expire.
+	segment.ExpirQuantiles = []int64{1, 2, 3, 4, math.MaxInt64}
+	s.False(isSegmentFullyExpired(segment, 0, s.now))
+}
+
+func (s *TimePartitionCompactionPolicySuite) TestPackTimeBucketSegments() {
+	segments := []*SegmentInfo{
+		buildTimeBucketSegment(1, s.testLabel, utcTime(10, 0), utcTime(10, 1), 600),
+		buildTimeBucketSegment(2, s.testLabel, utcTime(10, 0), utcTime(10, 1), 300),
+		buildTimeBucketSegment(3, s.testLabel, utcTime(10, 0), utcTime(10, 1), 500),
+		buildTimeBucketSegment(4, s.testLabel, utcTime(10, 0), utcTime(10, 1), 900),
+	}
+	packs := packTimeBucketSegments(segments, 1000, time.Hour)
+	s.Require().Len(packs, 1)
+	s.ElementsMatch([]int64{2, 3}, lo.Map(packs[0], func(segment *SegmentInfo, _ int) int64 { return segment.GetID() }))
+
+	// a segment spanning buckets is packed alone to be split.
+	segments[3] = buildTimeBucketSegment(4, s.testLabel, utcTime(10, 0), utcTime(11, 1), 900)
+	packs = packTimeBucketSegments(segments, 1000, time.Hour)
+	s.Require().Len(packs, 2)
+	s.ElementsMatch([]int64{4}, lo.Map(packs[1], func(segment *SegmentInfo, _ int) int64 { return segment.GetID() }))
+	s.Equal(2, countOutputTimeBuckets(packs[1], time.Hour))
+}
+
+func (s *TimePartitionCompactionPolicySuite) TestGetSegmentTimeBuckets() {
+	segment := buildTimeBucketSegment(1, s.testLabel, utcTime(9, 50), utcTime(12, 10), 1024)
+	first, last, ok := getSegmentTimeBuckets(segment, time.Hour)
+	s.True(ok)
+	s.Equal(utcTime(9, 0), first)
+	s.Equal(utcTime(12, 0), last)
+	s.Equal(4, countTimeBuckets(first, last, time.Hour))
+
+	first, last, ok = getSegmentTimeBuckets(segment, 24*time.Hour)
+	s.True(ok)
+	s.Equal(utcTime(0, 0), first)
+	s.Equal(first, last)
+
+	segment.TimeFieldRange = nil
+	_, _, ok = getSegmentTimeBuckets(segment, time.Hour)
+	s.False(ok)
+}
+
+func (s *CompactionTriggerManagerSuite) TestTimePartitionView() {
+	s.Run("merge view is submitted as mix compaction", func() {
+		s.SetupTest()
+		s.triggerManager.meta.indexMeta = &indexMeta{indexes: map[UniqueID]map[UniqueID]*model.Index{}}
+		s.mockAlloc.EXPECT().AllocN(mock.Anything).Return(1, 12, nil).Once()
+		s.handler.EXPECT().GetCollection(mock.Anything, s.testLabel.CollectionID).Return(&collectionInfo{
+			ID:     s.testLabel.CollectionID,
+			Schema: &schemapb.CollectionSchema{},
+		}, nil).Once()
+		s.inspector.EXPECT().enqueueCompaction(mock.Anything).RunAndReturn(func(task *datapb.CompactionTask) error {
+			s.Equal(datapb.CompactionType_MixCompaction, task.GetType())
+			s.Equal(int64(9), task.GetTriggerID())
+			s.Equal([]int64{1, 2}, task.GetInputSegments())
+			s.Equal(time.Hour.Nanoseconds(), task.GetCollectionTtl())
+			return nil
+		}).Once()
+
+		s.triggerManager.notify(context.Background(), TriggerTypeTimePartition, []CompactionView{
+			&TimePartitionSegmentsView{
+				label:         s.testLabel,
+				segments:      []*SegmentView{{ID: 1, label: s.testLabel}, {ID: 2, label: s.testLabel}},
+				collectionTTL: time.Hour,
+				triggerID:     9,
+			},
+		})
+	})
+}
@@ -18,10 +18,13 @@ package datacoord
 
 import (
 	"container/heap"
+	"math"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/samber/lo"
 
+	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
 	"github.com/milvus-io/milvus/pkg/v2/util/lock"
 )
@@ -29,6 +32,9 @@ import (
 type Item[T any] struct {
 	value    T
 	priority int // The priority of the item in the queue.
+	// rank and seq order the items of the same priority, the smaller first.
+	rank float64
+	seq  int64
 	// The index is needed by update and is maintained by the heap.Interface methods.
 	index int // The index of the item in the heap.
 }
@@ -41,7 +47,13 @@ var _ heap.Interface = (*PriorityQueue[any])(nil)
 func (pq PriorityQueue[T]) Len() int { return len(pq) }
 
 func (pq PriorityQueue[T]) Less(i, j int) bool {
-	return pq[i].priority < pq[j].priority
+	if pq[i].priority != pq[j].priority {
+		return pq[i].priority < pq[j].priority
+	}
+	if pq[i].rank != pq[j].rank {
+		return pq[i].rank < pq[j].rank
+	}
+	return pq[i].seq < pq[j].seq
 }
 
 func (pq PriorityQueue[T]) Swap(i, j int) {
@@ -79,12 +91,18 @@ var (
 	ErrNoSuchElement = errors.New("compaction queue has no element")
 )
 
+// Prioritizer returns the priority of the task, the tasks of a smaller priority are dequeued first.
 type Prioritizer func(t CompactionTask) int
 
+// Ranker orders the tasks of the same priority, the tasks of a smaller rank are dequeued first,
+// and the tasks of the same rank are dequeued in the order of their plan ids.
+type Ranker func(t CompactionTask) float64
+
 type CompactionQueue struct {
 	pq          PriorityQueue[CompactionTask]
 	lock        lock.RWMutex
 	prioritizer Prioritizer
+	ranker      Ranker
 	capacity    int
 }
 
@@ -93,10 +111,20 @@ func NewCompactionQueue(capacity int, prioritizer Prioritizer) *CompactionQueue
 		pq:          make(PriorityQueue[CompactionTask], 0),
 		lock:        lock.RWMutex{},
 		prioritizer: prioritizer,
+		ranker:      FIFORanker,
 		capacity:    capacity,
 	}
 }
 
+func (q *CompactionQueue) newItem(t CompactionTask) *Item[CompactionTask] {
+	return &Item[CompactionTask]{
+		value:    t,
+		priority: q.prioritizer(t),
+		rank:     q.ranker(t),
+		seq:      t.GetTaskProto().GetPlanID(),
+	}
+}
+
 func (q *CompactionQueue) Enqueue(t CompactionTask) error {
 	q.lock.Lock()
 	defer q.lock.Unlock()
@@ -104,7 +132,7 @@ func (q *CompactionQueue) Enqueue(t CompactionTask) error {
 		return ErrFull
 	}
 
-	heap.Push(&q.pq, &Item[CompactionTask]{value: t, priority: q.prioritizer(t)})
+	heap.Push(&q.pq, q.newItem(t))
 	return nil
 }
 
@@ -121,11 +149,20 @@ func (q *CompactionQueue) Dequeue() (CompactionTask, error) {
 }
 
 func (q *CompactionQueue) UpdatePrioritizer(prioritizer Prioritizer) {
-	q.prioritizer = prioritizer
+	q.UpdateOrdering(prioritizer, nil)
+}
+
+// UpdateOrdering reorders the queued tasks by the prioritizer and the ranker, the current ranker is kept if nil.
+func (q *CompactionQueue) UpdateOrdering(prioritizer Prioritizer, ranker Ranker) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
+	q.prioritizer = prioritizer
+	if ranker != nil {
+		q.ranker = ranker
+	}
 	for i := range q.pq {
 		q.pq[i].priority = q.prioritizer(q.pq[i].value)
+		q.pq[i].rank = q.ranker(q.pq[i].value)
 	}
 	heap.Init(&q.pq)
 }
@@ -202,3 +239,48 @@ func getPrioritizer() Prioritizer {
 		return DefaultPrioritizer
 	}
 }
+
+// FIFORanker ranks the tasks by their start time.
+var FIFORanker Ranker = func(task CompactionTask) float64 {
+	return float64(task.GetTaskProto().GetStartTime())
+}
+
+// CollectionPriorityFunc returns the compaction priority weight of the collection.
+type CollectionPriorityFunc func(collectionID int64) float64
+
+// NewCollectionPriorityRanker ranks the tasks by their start time shifted by the weight of their collection,
+// each doubling of the weight moves the tasks ahead by one window and each halving moves them behind by one window.
+// The ranker only breaks the ties of the prioritizer, so the weight never lets a task overtake the tasks
+// the prioritizer puts first, e.g. the L0 compactions of the level prioritizer, and it takes no effect with
+// the default prioritizer which keeps the plan id order. As the shift is bounded,
+// the tasks of a collection with a larger weight only overtake the tasks started less than the shift earlier,
+// the other collections are not starved.
+func NewCollectionPriorityRanker(weight CollectionPriorityFunc, window time.Duration) Ranker {
+	if weight == nil || window <= 0 {
+		return FIFORanker
+	}
+	return func(task CompactionTask) float64 {
+		rank := FIFORanker(task)
+		w := weight(task.GetTaskProto().GetCollectionID())
+		if w <= 0 || w == common.DefaultCollectionCompactionPriority {
+			return rank
+		}
+		return rank - math.Log2(w)*window.Seconds()
+	}
+}
+
+// newCollectionPriorityFunc reads the compaction priority weight from the collection properties,
+// the default weight is used if the collection is unknown or the property is invalid.
+func newCollectionPriorityFunc(m *meta) CollectionPriorityFunc {
+	return func(collectionID int64) float64 {
+		collection := m.GetCollection(collectionID)
+		if collection == nil {
+			return common.DefaultCollectionCompactionPriority
+		}
+		weight, err := common.GetCollectionCompactionPriorityFromMap(collection.Properties)
+		if err != nil {
+			return common.DefaultCollectionCompactionPriority
+		}
+		return weight
+	}
+}
@@ -19,10 +19,13 @@ package datacoord
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
 )
 
 func TestCompactionQueue(t *testing.T) {
@@ -135,6 +138,71 @@ func TestCompactionQueue(t *testing.T) {
 	})
 }
 
+func TestCollectionPriority(t *testing.T) {
+	newTask := func(planID, collectionID, startTime int64, typ datapb.CompactionType) CompactionTask {
+		task := &mixCompactionTask{}
+		task.SetTask(&datapb.CompactionTask{PlanID: planID, CollectionID: collectionID, StartTime: startTime, Type: typ})
+		return task
+	}
+	weights := map[int64]float64{1: 1, 2: 4, 3: 0.5}
+	weight := func(collectionID int64) float64 { return weights[collectionID] }
+	ranker := NewCollectionPriorityRanker(weight, 100*time.Second)
+
+	dequeueAll := func(cq *CompactionQueue) []int64 {
+		planIDs := make([]int64, 0)
+		for cq.Len() > 0 {
+			task, err := cq.Dequeue()
+			assert.NoError(t, err)
+			planIDs = append(planIDs, task.GetTaskProto().GetPlanID())
+		}
+		return planIDs
+	}
+
+	t.Run("weight orders the tasks of the same level", func(t *testing.T) {
+		cq := NewCompactionQueue(0, LevelPrioritizer)
+		cq.UpdateOrdering(LevelPrioritizer, ranker)
+		// the collection of weight 4 moves ahead by 200s, the collection of weight 0.5 moves behind by 100s.
+		assert.NoError(t, cq.Enqueue(newTask(1, 1, 1000, datapb.CompactionType_MixCompaction)))
+		assert.NoError(t, cq.Enqueue(newTask(2, 2, 1150, datapb.CompactionType_MixCompaction)))
+		assert.NoError(t, cq.Enqueue(newTask(3, 2, 1250, datapb.CompactionType_MixCompaction)))
+		assert.NoError(t, cq.Enqueue(newTask(4, 3, 900, datapb.CompactionType_MixCompaction)))
+		assert.NoError(t, cq.Enqueue(newTask(5, 1, 1000, datapb.CompactionType_MixCompaction)))
+		// the weight never moves a task across the levels.
+		assert.NoError(t, cq.Enqueue(newTask(6, 1, 2000, datapb.CompactionType_Level0DeleteCompaction)))
+		assert.NoError(t, cq.Enqueue(newTask(7, 2, 0, datapb.CompactionType_ClusteringCompaction)))
+		assert.Equal(t, []int64{6, 2, 1, 4, 5, 3, 7}, dequeueAll(cq))
+	})
+
+	t.Run("default prioritizer keeps the plan id order", func(t *testing.T) {
+		cq := NewCompactionQueue(0, DefaultPrioritizer)
+		assert.NoError(t, cq.Enqueue(newTask(1, 1, 1000, datapb.CompactionType_Level0DeleteCompaction)))
+		assert.NoError(t, cq.Enqueue(newTask(2, 2, 1100, datapb.CompactionType_ClusteringCompaction)))
+		assert.NoError(t, cq.Enqueue(newTask(3, 1, 1050, datapb.CompactionType_MixCompaction)))
+		assert.Equal(t, []int64{1, 2, 3}, dequeueAll(cq))
+
+		assert.NoError(t, cq.Enqueue(newTask(1, 1, 1000, datapb.CompactionType_Level0DeleteCompaction)))
+		assert.NoError(t, cq.Enqueue(newTask(2, 2, 1100, datapb.CompactionType_ClusteringCompaction)))
+		assert.NoError(t, cq.Enqueue(newTask(3, 1, 1050, datapb.CompactionType_MixCompaction)))
+		cq.UpdateOrdering(DefaultPrioritizer, ranker)
+		assert.Equal(t, []int64{1, 2, 3}, dequeueAll(cq))
+	})
+
+	assert.Equal(t, 7.0, NewCollectionPriorityRanker(nil, time.Second)(newTask(1, 2, 7, datapb.CompactionType_MixCompaction)))
+	assert.Equal(t, 7.0, NewCollectionPriorityRanker(weight, 0)(newTask(1, 2, 7, datapb.CompactionType_MixCompaction)))
+}
+
+func TestNewCollectionPriorityFunc(t *testing.T) {
+	m := &meta{collections: typeutil.NewConcurrentMap[UniqueID, *collectionInfo]()}
+	m.collections.Insert(1, &collectionInfo{ID: 1, Properties: map[string]string{common.CollectionCompactionPriorityKey: "3"}})
+	m.collections.Insert(2, &collectionInfo{ID: 2, Properties: map[string]string{common.CollectionCompactionPriorityKey: "-1"}})
+	m.collections.Insert(3, &collectionInfo{ID: 3})
+	weight := newCollectionPriorityFunc(m)
+	assert.Equal(t, 3.0, weight(1))
+	assert.Equal(t, common.DefaultCollectionCompactionPriority, weight(2))
+	assert.Equal(t, common.DefaultCollectionCompactionPriority, weight(3))
+	assert.Equal(t, common.DefaultCollectionCompactionPriority, weight(4))
+}
+
 func TestConcurrency(t *testing.T) {
 	c := 10
 
@@ -409,7 +409,13 @@ func (t *compactionTrigger) handleSignal(signal *compactionSignal) error {
 		}
 
 		expectedSize := getExpectedSegmentSize(t.meta, coll.ID, coll.Schema)
-		plans := t.generatePlans(group.segments, signal, ct, expectedSize)
+		var plans []*typeutil.Pair[int64, []int64]
+		if !signal.isForce && getCollectionTimeBucket(coll) > 0 {
+			// merging is left to the time partition policy so segments of different buckets are not mixed.
+			plans = t.generateSinglePlans(group.segments, ct)
+		} else {
+			plans = t.generatePlans(group.segments, signal, ct, expectedSize)
+		}
 		for _, plan := range plans {
 			if !signal.isForce && t.inspector.isFull() {
 				log.Warn("skip to generate compaction plan due to handler full")
@@ -568,6 +574,19 @@ func (t *compactionTrigger) generatePlans(segments []*SegmentInfo, signal *compa
 	return tasks
 }
 
+// generateSinglePlans generates a plan for each segment that needs a single compaction,
+// segments are never merged.
+func (t *compactionTrigger) generateSinglePlans(segments []*SegmentInfo, compactTime *compactTime) []*typeutil.Pair[int64, []int64] {
+	tasks := make([]*typeutil.Pair[int64, []int64], 0)
+	for _, segment := range segments {
+		if t.ShouldDoSingleCompaction(segment, compactTime) {
+			pair := typeutil.NewPair(segment.GetNumOfRows(), []int64{segment.GetID()})
+			tasks = append(tasks, &pair)
+		}
+	}
+	return tasks
+}
+
 // getCandidates converts signal criterion into corresponding compaction candidate groups
 // since non-major compaction happens under channel+partition level
 // the selected segments are grouped into these categories.
@@ -3137,6 +3137,44 @@ func Test_compactionTrigger_generatePlansByTime(t *testing.T) {
 	}
 }
 
+func Test_compactionTrigger_generateSinglePlans(t *testing.T) {
+	trigger := newCompactionTrigger(&meta{
+		indexMeta:  newSegmentIndexMeta(nil),
+		channelCPs: newChannelCps(),
+	}, &compactionInspector{}, newMockAllocator(t), newMockHandler(), newIndexEngineVersionManager())
+
+	var deltalogs []*datapb.FieldBinlog
+	for i := 0; i < 1000; i++ {
+		deltalogs = append(deltalogs, &datapb.FieldBinlog{
+			Binlogs: []*datapb.Binlog{{EntriesNum: 5, LogPath: "log1", LogSize: 100, MemorySize: 100}},
+		})
+	}
+	newSegment := func(id int64, deltalogs []*datapb.FieldBinlog) *SegmentInfo {
+		return &SegmentInfo{
+			SegmentInfo: &datapb.SegmentInfo{
+				ID:            id,
+				CollectionID:  2,
+				PartitionID:   1,
+				NumOfRows:     100,
+				InsertChannel: "ch1",
+				State:         commonpb.SegmentState_Flushed,
+				Deltalogs:     deltalogs,
+			},
+		}
+	}
+
+	// small segments are never merged, only the segments needing a single compaction are planned.
+	plans := trigger.generateSinglePlans([]*SegmentInfo{
+		newSegment(1, deltalogs),
+		newSegment(2, nil),
+		newSegment(3, deltalogs),
+	}, &compactTime{})
+	assert.Len(t, plans, 2)
+	assert.Equal(t, []int64{1}, plans[0].B)
+	assert.Equal(t, int64(100), plans[0].A)
+	assert.Equal(t, []int64{3}, plans[1].B)
+}
+
 func Test_compactionTrigger_ShouldCompactExpiryWithTTLField(t *testing.T) {
 	trigger := &compactionTrigger{}
 	ts := time.Now()
@@ -50,6 +50,7 @@ const (
 	TriggerTypeForceMerge
 	TriggerTypeStorageVersionUpgrade
 	TriggerTypeBackfill
+	TriggerTypeTimePartition
 )
 
 type TickerType int8
@@ -60,6 +61,7 @@ const (
 	SingleTicker
 	BackfillTicker
 	StorageVersionTicker
+	TimePartitionTicker
 )
 
 func (t CompactionTriggerType) GetCompactionType() datapb.CompactionType {
@@ -76,6 +78,8 @@ func (t CompactionTriggerType) GetCompactionType() datapb.CompactionType {
 		return datapb.CompactionType_MixCompaction
 	case TriggerTypeBackfill:
 		return datapb.CompactionType_BackfillCompaction
+	case TriggerTypeTimePartition:
+		return datapb.CompactionType_MixCompaction
 	default:
 		return datapb.CompactionType_MixCompaction
 	}
@@ -103,6 +107,8 @@ func (t CompactionTriggerType) String() string {
 		return "StorageVersionUpgrade"
 	case TriggerTypeBackfill:
 		return "Backfill"
+	case TriggerTypeTimePartition:
+		return "TimePartition"
 	default:
 		return ""
 	}
@@ -128,6 +134,7 @@ type TriggerManager interface {
 	Stop()
 	OnCollectionUpdate(collectionID int64)
 	ManualTrigger(ctx context.Context, collectionID int64, clusteringCompaction bool, l0Compaction bool, targetSize int64) (UniqueID, error)
+	GetDeferredViewNum(triggerID UniqueID) int
 	InitForceMergeMemoryQuerier(nodeManager session.NodeManager, mixCoord types.MixCoord, session sessionutil.SessionInterface)
 }
 
@@ -150,6 +157,9 @@ type CompactionTriggerManager struct {
 	forceMergePolicy            *forceMergeCompactionPolicy
 	upgradeStorageVersionPolicy *storageVersionUpgradePolicy
 	backfillPolicy              *backfillCompactionPolicy
+	timePartitionPolicy         *timePartitionCompactionPolicy
+
+	maintenanceWindow *compactionMaintenanceWindow
 
 	cancel  context.CancelFunc
 	closeWg sync.WaitGroup
@@ -164,6 +174,8 @@ func NewCompactionTriggerManager(alloc allocator.Allocator, handler Handler, ins
 		inspector: inspector,
 		meta:      meta,
 		policies:  make(map[TickerType]CompactionPolicy),
+
+		maintenanceWindow: newCompactionMaintenanceWindow(),
 	}
 	// Initialize policies and keep separate pointers for frequently accessed ones
 
@@ -174,6 +186,7 @@ func NewCompactionTriggerManager(alloc allocator.Allocator, handler Handler, ins
 	m.forceMergePolicy = newForceMergeCompactionPolicy(meta, m.allocator, m.handler)
 	m.upgradeStorageVersionPolicy = newStorageVersionUpgradePolicy(meta, m.allocator, m.handler, versionManager)
 	m.backfillPolicy = newBackfillCompactionPolicy(meta, m.allocator, m.handler)
+	m.timePartitionPolicy = newTimePartitionCompactionPolicy(meta, m.allocator, m.handler)
 
 	// Initialize policies map for ticker handling
 	m.policies[L0Ticker] = m.l0Policy
@@ -181,6 +194,7 @@ func NewCompactionTriggerManager(alloc allocator.Allocator, handler Handler, ins
 	m.policies[SingleTicker] = m.singlePolicy
 	m.policies[BackfillTicker] = m.backfillPolicy
 	m.policies[StorageVersionTicker] = m.upgradeStorageVersionPolicy
+	m.policies[TimePartitionTicker] = m.timePartitionPolicy
 	return m
 }
 
@@ -229,6 +243,10 @@ func (m *CompactionTriggerManager) loop(ctx context.Context) {
 	defer storageVersionTicker.Stop()
 	backfillTicker := time.NewTicker(Params.DataCoordCfg.BackfillCompactionTriggerInterval.GetAsDuration(time.Second))
 	defer backfillTicker.Stop()
+	timePartitionTicker := time.NewTicker(Params.DataCoordCfg.TimePartitionCompactionTriggerInterval.GetAsDuration(time.Second))
+	defer timePartitionTicker.Stop()
+	maintenanceWindowTicker := time.NewTicker(time.Minute)
+	defer maintenanceWindowTicker.Stop()
 	log.Info("Compaction trigger manager start")
 	for {
 		select {
@@ -245,6 +263,10 @@ func (m *CompactionTriggerManager) loop(ctx context.Context) {
 			m.handleTicker(ctx, StorageVersionTicker)
 		case <-backfillTicker.C:
 			m.handleTicker(ctx, BackfillTicker)
+		case <-timePartitionTicker.C:
+			m.handleTicker(ctx, TimePartitionTicker)
+		case <-maintenanceWindowTicker.C:
+			m.submitDeferredViews(ctx)
 		case segID := <-getStatsTaskChSingleton():
 			log.Info("receive new segment to trigger sort compaction", zap.Int64("segmentID", segID))
 			view := m.singlePolicy.triggerSegmentSortCompaction(ctx, segID)
@@ -296,6 +318,7 @@ func (m *CompactionTriggerManager) handleTicker(ctx context.Context, tickerType
 		return
 	}
 	for triggerType, views := range events {
+		views = m.maintenanceWindow.admit(triggerType, views, false)
 		if len(views) == 0 {
 			continue
 		}
@@ -303,6 +326,31 @@ func (m *CompactionTriggerManager) handleTicker(ctx context.Context, tickerType
 	}
 }
 
+// submitDeferredViews submits the manual heavy compaction views deferred by the maintenance window
+// once a window opens, the views whose segments are no longer healthy are skipped.
+func (m *CompactionTriggerManager) submitDeferredViews(ctx context.Context) {
+	if m.inspector.isFull() {
+		return
+	}
+	deferred := m.maintenanceWindow.popDeferred()
+	for triggerType, views := range deferred {
+		views = lo.Filter(views, func(view CompactionView, _ int) bool {
+			return lo.EveryBy(view.GetSegmentsView(), func(segView *SegmentView) bool {
+				return m.meta.GetHealthySegment(ctx, segView.ID) != nil
+			})
+		})
+		log.Ctx(ctx).Info("submit compaction views deferred by the maintenance window",
+			zap.String("triggerType", triggerType.String()),
+			zap.Int("views", len(views)))
+		m.notify(ctx, triggerType, views)
+	}
+}
+
+// GetDeferredViewNum returns the number of views of the trigger waiting for the maintenance window.
+func (m *CompactionTriggerManager) GetDeferredViewNum(triggerID UniqueID) int {
+	return m.maintenanceWindow.deferredNum(triggerID)
+}
+
 // executeInline applies all views returned by TriggerInline directly inside datacoord
 // without consuming inspector slots or notifying the scheduler.
 func (m *CompactionTriggerManager) executeInline(ctx context.Context, events map[CompactionTriggerType][]CompactionView) {
@@ -376,6 +424,7 @@ func (m *CompactionTriggerManager) ManualTrigger(ctx context.Context, collection
 	}
 	if len(events) > 0 {
 		for triggerType, views := range events {
+			views = m.maintenanceWindow.admit(triggerType, views, true)
 			m.notify(ctx, triggerType, views)
 		}
 	}
@@ -423,6 +472,8 @@ func (m *CompactionTriggerManager) notify(ctx context.Context, eventType Compact
 					m.SubmitForceMergeViewToScheduler(ctx, outView)
 				case TriggerTypeBackfill:
 					m.SubmitBackfillViewToScheduler(ctx, outView)
+				case TriggerTypeTimePartition:
+					m.SubmitTimePartitionViewToScheduler(ctx, outView)
 				}
 			}
 		}
@@ -774,6 +825,77 @@ func (m *CompactionTriggerManager) SubmitBackfillViewToScheduler(ctx context.Con
 	)
 }
 
+func (m *CompactionTriggerManager) SubmitTimePartitionViewToScheduler(ctx context.Context, view CompactionView) {
+	log := log.Ctx(ctx).With(zap.String("view", view.String()))
+	tpView, ok := view.(*TimePartitionSegmentsView)
+	if !ok {
+		log.Warn("unexpected view type for time partition trigger, expected *TimePartitionSegmentsView",
+			zap.String("actualType", fmt.Sprintf("%T", view)))
+		return
+	}
+	// 1 planID + 10 segmentIDs per output bucket, one more bucket for the rows with a null time field.
+	n := (1 + 10*int64(tpView.outputBuckets+1)) * paramtable.Get().DataCoordCfg.CompactionPreAllocateIDExpansionFactor.GetAsInt64()
+	startID, endID, err := m.allocator.AllocN(n)
+	if err != nil {
+		log.Warn("Failed to submit compaction view to scheduler because allocate id fail", zap.Error(err))
+		return
+	}
+
+	collection, err := m.handler.GetCollection(ctx, view.GetGroupLabel().CollectionID)
+	if err != nil {
+		log.Warn("Failed to submit compaction view to scheduler because get collection fail", zap.Error(err))
+		return
+	}
+	if collection == nil {
+		log.Warn("collection not found when submitting time partition compaction", zap.Int64("collectionID", view.GetGroupLabel().CollectionID))
+		return
+	}
+	if collection.IsExternal() {
+		log.Info("skip submitting time partition compaction for external collection", zap.Int64("collectionID", collection.ID))
+		return
+	}
+	totalRows := lo.SumBy(view.GetSegmentsView(), func(v *SegmentView) int64 { return v.NumOfRows })
+
+	expectedSize := getExpectedSegmentSize(m.meta, collection.ID, collection.Schema)
+	task := &datapb.CompactionTask{
+		PlanID:             startID,
+		TriggerID:          tpView.triggerID,
+		State:              datapb.CompactionTaskState_pipelining,
+		StartTime:          time.Now().Unix(),
+		CollectionTtl:      tpView.collectionTTL.Nanoseconds(),
+		Type:               TriggerTypeTimePartition.GetCompactionType(),
+		CollectionID:       view.GetGroupLabel().CollectionID,
+		PartitionID:        view.GetGroupLabel().PartitionID,
+		Channel:            view.GetGroupLabel().Channel,
+		Schema:             collection.Schema,
+		InputSegments:      lo.Map(view.GetSegmentsView(), func(segmentView *SegmentView, _ int) int64 { return segmentView.ID }),
+		ResultSegments:     []int64{},
+		TotalRows:          totalRows,
+		LastStateStartTime: time.Now().Unix(),
+		MaxSize:            expectedSize,
+		PreAllocatedSegmentIDs: &datapb.IDRange{
+			Begin: startID + 1,
+			End:   endID,
+		},
+	}
+	err = m.inspector.enqueueCompaction(task)
+	if err != nil {
+		log.Warn("Failed to execute compaction task",
+			zap.Int64("triggerID", task.GetTriggerID()),
+			zap.Int64("planID", task.GetPlanID()),
+			zap.Int64s("segmentIDs", task.GetInputSegments()),
+			zap.Error(err))
+		return
+	}
+	log.Info("Finish to submit a time partition compaction task",
+		zap.Int64("triggerID", task.GetTriggerID()),
+		zap.Int64("planID", task.GetPlanID()),
+		zap.Time("bucketStart", tpView.bucketStart),
+		zap.Int("outputBuckets", tpView.outputBuckets),
+		zap.Int64("targetSize", task.GetMaxSize()),
+	)
+}
+
 func getExpectedSegmentSize(meta *meta, collectionID int64, schema *schemapb.CollectionSchema) int64 {
 	allDiskIndex := meta.indexMeta.AllDenseWithDiskIndex(collectionID, schema)
 	if allDiskIndex {
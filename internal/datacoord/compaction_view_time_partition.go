@@ -0,0 +1,89 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"fmt"
+	"time"
+)
+
+var _ CompactionView = (*TimePartitionSegmentsView)(nil)
+
+// TimePartitionSegmentsView is a group of segments starting in the same time bucket,
+// the compaction output is split by bucket. An expired view holds a segment whose rows are
+// all expired, the compaction filters all the rows out and drops the segment.
+type TimePartitionSegmentsView struct {
+	label         *CompactionGroupLabel
+	segments      []*SegmentView
+	collectionTTL time.Duration
+	triggerID     int64
+
+	// the time bucket [bucketStart, bucketEnd) the segments start in
+	bucketStart time.Time
+	bucketEnd   time.Time
+	// the number of buckets the compaction output is split into
+	outputBuckets int
+
+	expired bool
+}
+
+func (v *TimePartitionSegmentsView) IsInlineExecutable() bool { return false }
+
+func (v *TimePartitionSegmentsView) GetGroupLabel() *CompactionGroupLabel {
+	if v == nil {
+		return &CompactionGroupLabel{}
+	}
+	return v.label
+}
+
+func (v *TimePartitionSegmentsView) GetSegmentsView() []*SegmentView {
+	if v == nil {
+		return nil
+	}
+	return v.segments
+}
+
+func (v *TimePartitionSegmentsView) Append(segments ...*SegmentView) {
+	v.segments = append(v.segments, segments...)
+}
+
+func (v *TimePartitionSegmentsView) String() string {
+	return fmt.Sprintf("TimePartition: %s, bucket=[%s, %s), segments=%d, expired=%t, triggerID=%d",
+		v.label, v.bucketStart.UTC().Format(time.RFC3339), v.bucketEnd.UTC().Format(time.RFC3339),
+		len(v.segments), v.expired, v.triggerID)
+}
+
+func (v *TimePartitionSegmentsView) Trigger() (CompactionView, string) {
+	if v.expired {
+		return v, fmt.Sprintf("drop %d expired segments of time bucket %s",
+			len(v.segments), v.bucketStart.UTC().Format(time.RFC3339))
+	}
+	return v, fmt.Sprintf("compact %d segments of time bucket %s into %d buckets",
+		len(v.segments), v.bucketStart.UTC().Format(time.RFC3339), v.outputBuckets)
+}
+
+func (v *TimePartitionSegmentsView) ForceTrigger() (CompactionView, string) {
+	panic("implement me")
+}
+
+func (v *TimePartitionSegmentsView) ForceTriggerAll() ([]CompactionView, string) {
+	panic("implement me")
+}
+
+func (v *TimePartitionSegmentsView) GetTriggerID() int64 {
+	return v.triggerID
+}
@@ -0,0 +1,59 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datacoord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimePartitionSegmentsView(t *testing.T) {
+	label := &CompactionGroupLabel{
+		CollectionID: 1,
+		PartitionID:  10,
+		Channel:      "ch1",
+	}
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	view := &TimePartitionSegmentsView{
+		label:       label,
+		triggerID:   3,
+		bucketStart: start,
+		bucketEnd:   start.Add(time.Hour),
+	}
+	view.Append(&SegmentView{ID: 1}, &SegmentView{ID: 2})
+
+	assert.Equal(t, label, view.GetGroupLabel())
+	assert.Len(t, view.GetSegmentsView(), 2)
+	assert.Equal(t, int64(3), view.GetTriggerID())
+	assert.False(t, view.IsInlineExecutable())
+	assert.Contains(t, view.String(), "bucket=[2024-01-01T10:00:00Z, 2024-01-01T11:00:00Z)")
+
+	outView, reason := view.Trigger()
+	assert.Equal(t, view, outView)
+	assert.NotEmpty(t, reason)
+
+	view.expired = true
+	assert.False(t, view.IsInlineExecutable())
+	_, reason = view.Trigger()
+	assert.Contains(t, reason, "expired")
+
+	var nilView *TimePartitionSegmentsView
+	assert.NotNil(t, nilView.GetGroupLabel())
+	assert.Nil(t, nilView.GetSegmentsView())
+}
@@ -1979,6 +1979,7 @@ func (m *meta) completeClusterCompactionMutation(t *datapb.CompactionTask, resul
 			StorageVersion: seg.GetStorageVersion(),
 			ManifestPath:   seg.GetManifest(),
 			ExpirQuantiles: seg.GetExpirQuantiles(),
+			TimeFieldRange: seg.GetTimeFieldRange(),
 			SchemaVersion:  compactFromSegInfos[0].GetSchemaVersion(),
 		}
 		segment := NewSegmentInfo(segmentInfo)
@@ -2097,6 +2098,7 @@ func (m *meta) completeMixCompactionMutation(
 				ManifestPath:        compactToSegment.GetManifest(),
 				IsSortedByNamespace: compactToSegment.GetIsSortedByNamespace(),
 				ExpirQuantiles:      compactToSegment.GetExpirQuantiles(),
+				TimeFieldRange:      compactToSegment.GetTimeFieldRange(),
 				SchemaVersion:       compactFromSegInfos[0].GetSchemaVersion(),
 			})
 
@@ -2115,6 +2117,7 @@ func (m *meta) completeMixCompactionMutation(
 			zap.Int("deltalog count", len(compactToSegmentInfo.GetDeltalogs())),
 			zap.Int64("segment size", compactToSegmentInfo.getSegmentSize()),
 			zap.Int64s("expirQuantiles", compactToSegmentInfo.GetExpirQuantiles()),
+			zap.Int64s("timeFieldRange", compactToSegmentInfo.GetTimeFieldRange()),
 		)
 		compactToSegments = append(compactToSegments, compactToSegmentInfo)
 	}
@@ -2762,6 +2765,7 @@ func (m *meta) completeSortCompactionMutation(
 		IsSorted:                  resultSegment.GetIsSorted(),
 		ManifestPath:              resultSegment.GetManifest(),
 		ExpirQuantiles:            resultSegment.GetExpirQuantiles(),
+		TimeFieldRange:            resultSegment.GetTimeFieldRange(),
 		IsSortedByNamespace:       resultSegment.GetIsSortedByNamespace(),
 		SchemaVersion:             oldSegment.GetSchemaVersion(),
 	}
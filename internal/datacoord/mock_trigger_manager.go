@@ -26,6 +26,52 @@ func (_m *MockTriggerManager) EXPECT() *MockTriggerManager_Expecter {
 	return &MockTriggerManager_Expecter{mock: &_m.Mock}
 }
 
+// GetDeferredViewNum provides a mock function with given fields: triggerID
+func (_m *MockTriggerManager) GetDeferredViewNum(triggerID int64) int {
+	ret := _m.Called(triggerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeferredViewNum")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int64) int); ok {
+		r0 = rf(triggerID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// MockTriggerManager_GetDeferredViewNum_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeferredViewNum'
+type MockTriggerManager_GetDeferredViewNum_Call struct {
+	*mock.Call
+}
+
+// GetDeferredViewNum is a helper method to define mock.On call
+//   - triggerID int64
+func (_e *MockTriggerManager_Expecter) GetDeferredViewNum(triggerID interface{}) *MockTriggerManager_GetDeferredViewNum_Call {
+	return &MockTriggerManager_GetDeferredViewNum_Call{Call: _e.mock.On("GetDeferredViewNum", triggerID)}
+}
+
+func (_c *MockTriggerManager_GetDeferredViewNum_Call) Run(run func(triggerID int64)) *MockTriggerManager_GetDeferredViewNum_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockTriggerManager_GetDeferredViewNum_Call) Return(_a0 int) *MockTriggerManager_GetDeferredViewNum_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTriggerManager_GetDeferredViewNum_Call) RunAndReturn(run func(int64) int) *MockTriggerManager_GetDeferredViewNum_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // InitForceMergeMemoryQuerier provides a mock function with given fields: nodeManager, mixCoord, _a2
 func (_m *MockTriggerManager) InitForceMergeMemoryQuerier(nodeManager session.NodeManager, mixCoord types.MixCoord, _a2 sessionutil.SessionInterface) {
 	_m.Called(nodeManager, mixCoord, _a2)
@@ -1820,6 +1820,38 @@ func TestGetCompactionState(t *testing.T) {
 		assert.EqualValues(t, 2, resp.GetCompletedPlanNo())
 		assert.EqualValues(t, 1, resp.GetFailedPlanNo())
 		assert.EqualValues(t, 4, resp.GetTimeoutPlanNo())
+		assert.Equal(t, map[string]string{
+			compactionStateDeferredPlansKey:     "0",
+			compactionStateMaintenanceWindowKey: maintenanceWindowUnrestricted,
+			compactionStatePriorityKey:          "1",
+		}, resp.GetStatus().GetExtraInfo())
+	})
+
+	t.Run("test get compaction state deferred by maintenance window", func(t *testing.T) {
+		paramtable.Get().Save(Params.DataCoordCfg.CompactionMaintenanceWindows.Key, "00:00-00:01")
+		defer paramtable.Get().Reset(Params.DataCoordCfg.CompactionMaintenanceWindows.Key)
+		svr := &Server{
+			meta: &meta{collections: typeutil.NewConcurrentMap[UniqueID, *collectionInfo]()},
+		}
+		svr.meta.collections.Insert(100, &collectionInfo{ID: 100, Properties: map[string]string{common.CollectionCompactionPriorityKey: "2.5"}})
+		svr.stateCode.Store(commonpb.StateCode_Healthy)
+		mockHandler := NewMockCompactionInspector(t)
+		mockHandler.EXPECT().getCompactionInfo(mock.Anything, mock.Anything).Return(&compactionInfo{
+			collectionID: 100,
+			state:        commonpb.CompactionState_Completed,
+		})
+		svr.compactionInspector = mockHandler
+		mockTriggerManager := NewMockTriggerManager(t)
+		mockTriggerManager.EXPECT().GetDeferredViewNum(int64(1)).Return(2)
+		svr.compactionTriggerManager = mockTriggerManager
+
+		resp, err := svr.GetCompactionState(context.Background(), &milvuspb.GetCompactionStateRequest{CompactionID: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, commonpb.ErrorCode_Success, resp.GetStatus().GetErrorCode())
+		assert.Equal(t, commonpb.CompactionState_Executing, resp.GetState())
+		assert.Equal(t, "2", resp.GetStatus().GetExtraInfo()[compactionStateDeferredPlansKey])
+		assert.Equal(t, "2.5", resp.GetStatus().GetExtraInfo()[compactionStatePriorityKey])
+		assert.Contains(t, []string{maintenanceWindowOpen, maintenanceWindowClosed}, resp.GetStatus().GetExtraInfo()[compactionStateMaintenanceWindowKey])
 	})
 
 	t.Run("with closed server", func(t *testing.T) {
@@ -1358,8 +1358,27 @@ func (s *Server) GetCompactionState(ctx context.Context, req *milvuspb.GetCompac
 	resp.CompletedPlanNo = int64(info.completedCnt)
 	resp.TimeoutPlanNo = int64(info.timeoutCnt)
 	resp.FailedPlanNo = int64(info.failedCnt)
-	log.Info("success to get compaction state", zap.Any("state", info.state), zap.Int("executing", info.executingCnt),
-		zap.Int("completed", info.completedCnt), zap.Int("failed", info.failedCnt), zap.Int("timeout", info.timeoutCnt))
+
+	// the plans deferred by the maintenance window are not created yet, the compaction is still pending.
+	deferredCnt := 0
+	if s.compactionTriggerManager != nil {
+		deferredCnt = s.compactionTriggerManager.GetDeferredViewNum(req.GetCompactionID())
+	}
+	if deferredCnt > 0 {
+		resp.State = commonpb.CompactionState_Executing
+	}
+	priority := common.DefaultCollectionCompactionPriority
+	if s.meta != nil && info.collectionID != 0 {
+		priority = newCollectionPriorityFunc(s.meta)(info.collectionID)
+	}
+	resp.Status.ExtraInfo = map[string]string{
+		compactionStateDeferredPlansKey:     strconv.Itoa(deferredCnt),
+		compactionStateMaintenanceWindowKey: getMaintenanceWindowState(time.Now()),
+		compactionStatePriorityKey:          strconv.FormatFloat(priority, 'f', -1, 64),
+	}
+	log.Info("success to get compaction state", zap.Any("state", resp.State), zap.Int("executing", info.executingCnt),
+		zap.Int("completed", info.completedCnt), zap.Int("failed", info.failedCnt), zap.Int("timeout", info.timeoutCnt),
+		zap.Int("deferred", deferredCnt))
 
 	return resp, nil
 }
@@ -181,7 +181,14 @@ func (t *backfillCompactionTask) preCompact() error {
 	}
 	backfillFunction := backfillFunctions[0]
 
-	functionRunner, err := function.NewFunctionRunner(t.plan.GetSchema(), backfillFunction)
+	var functionRunner function.FunctionRunner
+	var err error
+	if function.IsExpressionFunction(backfillFunction) {
+		// expression functions are computed by the proxy at write time, so NewFunctionRunner doesn't build them.
+		functionRunner, err = function.NewExpressionFunctionRunner(t.plan.GetSchema(), backfillFunction)
+	} else {
+		functionRunner, err = function.NewFunctionRunner(t.plan.GetSchema(), backfillFunction)
+	}
 	if err != nil {
 		return err
 	}
@@ -232,6 +239,27 @@ func (t *backfillCompactionTask) checkFunctionRunner(functionRunner function.Fun
 			return errors.New("output field data type must be sparse float vector for bm25 function backfill")
 		}
 
+		return nil
+	case function.FunctionTypeExpression:
+		functionSchema := functionRunner.GetSchema()
+
+		// the input fields are checked by the runner, which has resolved them by names.
+		if len(functionSchema.GetInputFieldIds()) != len(functionRunner.GetInputFields()) {
+			return errors.New("expression function input fields mismatch the schema")
+		}
+		for _, inputFieldID := range functionSchema.GetInputFieldIds() {
+			if typeutil.GetField(t.plan.GetSchema(), inputFieldID) == nil {
+				return errors.New("input field not found in schema")
+			}
+		}
+
+		outputFieldIDs := functionSchema.GetOutputFieldIds()
+		if len(outputFieldIDs) != 1 {
+			return errors.New("expression function should have exactly one output field")
+		}
+		if typeutil.GetField(t.plan.GetSchema(), outputFieldIDs[0]) == nil {
+			return errors.New("output field not found in schema")
+		}
 		return nil
 	default:
 		return errors.New("unsupported function type")
@@ -242,22 +270,25 @@ func (t *backfillCompactionTask) runBackfillFunction(ctx context.Context, functi
 	switch functionRunner.GetSchema().GetType() {
 	case schemapb.FunctionType_BM25:
 		return t.runBm25Function(ctx, functionRunner)
+	case function.FunctionTypeExpression:
+		return t.runExpressionFunction(ctx, functionRunner)
 	default:
 		return nil, errors.New("unsupported function type")
 	}
 }
 
-func (t *backfillCompactionTask) openBinlogReader(inputFieldID int64) (storage.RecordReader, error) {
-	inputField := typeutil.GetField(t.plan.GetSchema(), inputFieldID)
+func (t *backfillCompactionTask) openBinlogReader(inputFieldIDs ...int64) (storage.RecordReader, error) {
+	inputFields := make([]*schemapb.FieldSchema, 0, len(inputFieldIDs))
+	for _, inputFieldID := range inputFieldIDs {
+		inputFields = append(inputFields, typeutil.GetField(t.plan.GetSchema(), inputFieldID))
+	}
 
 	segment := t.plan.GetSegmentBinlogs()[0]
 	collectionID := segment.GetCollectionID()
 	partitionID := segment.GetPartitionID()
 	segmentID := segment.GetSegmentID()
 
-	inputSchema := &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
-		inputField,
-	}}
+	inputSchema := &schemapb.CollectionSchema{Fields: inputFields}
 
 	if segment.GetManifest() != "" {
 		return storage.NewManifestRecordReader(t.ctx,
@@ -849,6 +880,180 @@ func (t *backfillCompactionTask) runBm25Function(ctx context.Context, functionRu
 	return ret, nil
 }
 
+// runExpressionFunction computes the generated column of an expression function for the whole segment.
+// Unlike BM25 there are no stats to write, so the result carries no Bm25Logs.
+func (t *backfillCompactionTask) runExpressionFunction(ctx context.Context, functionRunner function.FunctionRunner) (*datapb.CompactionPlanResult, error) {
+	// 1. set up function schema
+	// inputs are passed to the runner in the order of its input fields.
+	functionSchema := functionRunner.GetSchema()
+	inputFields := make([]*schemapb.FieldSchema, 0, len(functionRunner.GetInputFields()))
+	inputFieldIDs := make([]int64, 0, len(functionRunner.GetInputFields()))
+	for _, field := range functionRunner.GetInputFields() {
+		inputField := typeutil.GetField(t.plan.GetSchema(), field.GetFieldID())
+		if inputField == nil {
+			return nil, errors.Newf("expression backfill: input field %d not found (planID=%d)", field.GetFieldID(), t.plan.GetPlanID())
+		}
+		inputFields = append(inputFields, inputField)
+		inputFieldIDs = append(inputFieldIDs, inputField.GetFieldID())
+	}
+	outputFieldIDs := functionSchema.GetOutputFieldIds()
+	if len(outputFieldIDs) != 1 {
+		return nil, errors.Newf("expression backfill: expected exactly one output field, got %d (planID=%d)", len(outputFieldIDs), t.plan.GetPlanID())
+	}
+	outputFieldID := outputFieldIDs[0]
+	outputField := typeutil.GetField(t.plan.GetSchema(), outputFieldID)
+
+	// 2. get segment info
+	segment := t.plan.GetSegmentBinlogs()[0]
+	collectionID := segment.GetCollectionID()
+	partitionID := segment.GetPartitionID()
+	segmentID := segment.GetSegmentID()
+
+	log := log.Ctx(ctx).With(
+		zap.Int64("planID", t.plan.GetPlanID()),
+		zap.Int64("collectionID", collectionID),
+		zap.Int64("partitionID", partitionID),
+		zap.Int64("segmentID", segmentID),
+		zap.Int64s("inputFieldIDs", inputFieldIDs),
+		zap.Int64("outputFieldID", outputFieldID),
+	)
+
+	var readDuration, computeDuration, writeDuration time.Duration
+
+	// 3. open binlog reader
+	reader, err := t.openBinlogReader(inputFieldIDs...)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// 4. set up writer
+	writerResult, err := t.setupWriter(outputField, outputFieldID, segment, collectionID, partitionID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("backfill writer setup",
+		zap.Int64("effectiveStorageVersion", writerResult.storageVersion),
+		zap.Int64("clusterStorageVersion", t.compactionParams.StorageVersion),
+		zap.Bool("segmentHasManifest", segment.GetManifest() != ""),
+		zap.Strings("v2WritePaths", writerResult.paths),
+	)
+	writerClosed := false
+	defer func() {
+		if !writerClosed {
+			writerResult.writer.Close()
+		}
+	}()
+
+	// 5. batch loop: read → compute → write
+	_, span := otel.Tracer(typeutil.DataNodeRole).Start(ctx, "BackfillCompact.batchProcess")
+	var totalRows int64
+	var totalMemorySize int
+	for {
+		readStart := time.Now()
+		record, err := reader.Next()
+		if err != nil {
+			if err == sio.EOF {
+				readDuration += time.Since(readStart)
+				break
+			}
+			span.End()
+			return nil, err
+		}
+		inputs := make([]any, 0, len(inputFields))
+		for _, inputField := range inputFields {
+			fieldData, err := storage.DeserializeFieldData(record, inputField)
+			if err != nil {
+				record.Release()
+				span.End()
+				return nil, err
+			}
+			inputs = append(inputs, fieldData)
+		}
+		numRows := record.Len()
+		record.Release()
+		readDuration += time.Since(readStart)
+
+		computeStart := time.Now()
+		output, err := functionRunner.BatchRun(inputs...)
+		computeDuration += time.Since(computeStart)
+		if err != nil {
+			span.End()
+			return nil, err
+		}
+		outputFieldData, ok := output[0].(storage.FieldData)
+		if len(output) != 1 || !ok {
+			span.End()
+			return nil, errors.New("unexpected output from expression function runner, expected one field data")
+		}
+		insertData := &storage.InsertData{
+			Data: map[int64]storage.FieldData{outputFieldID: outputFieldData},
+		}
+
+		writeStart := time.Now()
+		if err := t.writeBatch(writerResult.writer, writerResult.arrowSchema, insertData, writerResult.outputSchema); err != nil {
+			span.End()
+			return nil, err
+		}
+		writeDuration += time.Since(writeStart)
+
+		totalRows += int64(numRows)
+		totalMemorySize += outputFieldData.GetMemorySize()
+	}
+	span.End()
+
+	// 6. close writer — for V3, this commits the new column group to the existing manifest.
+	if err := writerResult.writer.Close(); err != nil {
+		return nil, err
+	}
+	writerClosed = true
+	if v2w, ok := writerResult.writer.(*v2WriterWrapper); ok {
+		writerResult.fileSizes = v2w.fileSizes
+	}
+	manifestPath := writerResult.writer.Manifest()
+
+	// 7. build merged logs, the bm25 logs are ignored since there are no stats.
+	var mergedInsertLogs []*datapb.FieldBinlog
+	if writerResult.storageVersion == storage.StorageV3 {
+		mergedInsertLogs, _, err = t.buildMergedLogsV3(segment, writerResult, totalMemorySize, totalRows, nil, "", 0, outputFieldID)
+	} else {
+		mergedInsertLogs, _, err = t.buildMergedLogsV2(segment, writerResult, totalMemorySize, totalRows, nil, "", 0, outputFieldID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 8. return compaction result
+	ret := &datapb.CompactionPlanResult{
+		PlanID: t.plan.GetPlanID(),
+		State:  datapb.CompactionTaskState_completed,
+		Segments: []*datapb.CompactionSegment{
+			{
+				SegmentID:           segmentID,
+				NumOfRows:           totalRows,
+				InsertLogs:          mergedInsertLogs,
+				Field2StatslogPaths: segment.GetField2StatslogPaths(),
+				Deltalogs:           segment.GetDeltalogs(),
+				Channel:             segment.GetInsertChannel(),
+				StorageVersion:      writerResult.storageVersion,
+				Manifest:            manifestPath,
+			},
+		},
+		Type: t.plan.GetType(),
+	}
+	log.Info("backfill compaction completed",
+		zap.Int64("numOfRows", totalRows),
+		zap.Int("mergedInsertLogsCount", len(mergedInsertLogs)),
+		zap.String("manifestPath", manifestPath),
+		zap.Int64("effectiveStorageVersion", writerResult.storageVersion),
+		zap.Int32("collectionSchemaVersion", t.plan.GetSchema().GetVersion()),
+		zap.Duration("readDuration", readDuration),
+		zap.Duration("computeDuration", computeDuration),
+		zap.Duration("writeDuration", writeDuration),
+	)
+	return ret, nil
+}
+
 func (t *backfillCompactionTask) Complete() {
 	if t.done != nil {
 		select {
@@ -426,6 +426,25 @@ func (s *BackfillCompactionTaskSuite) TestBackfillCompactionUnsupportedFunctionT
 	s.Contains(err.Error(), "unknown functionRunner type")
 }
 
+func (s *BackfillCompactionTaskSuite) TestBackfillCompactionInvalidExpressionFunction() {
+	s.prepareBackfillCompaction()
+
+	// the expression function can't generate a vector field
+	s.task.plan.Functions = []*schemapb.FunctionSchema{{
+		Name:             "Expression",
+		Type:             function.FunctionTypeExpression,
+		InputFieldNames:  []string{"text"},
+		InputFieldIds:    []int64{101},
+		OutputFieldNames: []string{"sparse"},
+		OutputFieldIds:   []int64{102},
+		Params:           []*commonpb.KeyValuePair{{Key: function.ExpressionKey, Value: "lower(text)"}},
+	}}
+
+	_, err := s.task.Compact()
+	s.Error(err)
+	s.Contains(err.Error(), "must be bool, integer, float, double or varchar")
+}
+
 func (s *BackfillCompactionTaskSuite) TestBackfillCompactionEmptySegmentBinlogs() {
 	// Test with empty segment binlogs
 	s.task.plan.SegmentBinlogs = nil
@@ -49,10 +49,12 @@ func mergeSortMultipleSegments(ctx context.Context,
 	segIDAlloc := allocator.NewLocalAllocator(plan.GetPreAllocatedSegmentIDs().GetBegin(), plan.GetPreAllocatedSegmentIDs().GetEnd())
 	logIDAlloc := allocator.NewLocalAllocator(plan.GetPreAllocatedLogIDs().GetBegin(), plan.GetPreAllocatedLogIDs().GetEnd())
 	compAlloc := NewCompactionAllocator(segIDAlloc, logIDAlloc)
-	writer, err := NewMultiSegmentWriter(ctx, binlogIO, compAlloc, plan.GetMaxSize(), writerSchema, compactionParams, maxRows, partitionID, collectionID, plan.GetChannel(), 4096,
-		storage.WithStorageConfig(compactionParams.StorageConfig),
-		storage.WithUseLoonFFI(compactionParams.UseLoonFFI),
-	)
+	writer, err := newCompactionSegmentWriter(compactionParams, writerSchema, func() (*MultiSegmentWriter, error) {
+		return NewMultiSegmentWriter(ctx, binlogIO, compAlloc, plan.GetMaxSize(), writerSchema, compactionParams, maxRows, partitionID, collectionID, plan.GetChannel(), 4096,
+			storage.WithStorageConfig(compactionParams.StorageConfig),
+			storage.WithUseLoonFFI(compactionParams.UseLoonFFI),
+		)
+	})
 	if err != nil {
 		return nil, err
 	}
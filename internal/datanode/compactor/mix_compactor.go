@@ -22,6 +22,7 @@ import (
 	sio "io"
 	"math"
 	"path"
+	"slices"
 	"time"
 
 	"github.com/apache/arrow/go/v17/arrow/array"
@@ -198,11 +199,13 @@ func (t *mixCompactionTask) mergeSplit(
 		}
 	}
 
-	mWriter, err := NewMultiSegmentWriter(ctx,
-		t.binlogIO, compAlloc, t.plan.GetMaxSize(), writerSchema,
-		t.compactionParams, t.maxRows, t.partitionID, t.collectionID, t.GetChannelName(), 4096,
-		writerOpts...,
-	)
+	mWriter, err := newCompactionSegmentWriter(t.compactionParams, writerSchema, func() (*MultiSegmentWriter, error) {
+		return NewMultiSegmentWriter(ctx,
+			t.binlogIO, compAlloc, t.plan.GetMaxSize(), writerSchema,
+			t.compactionParams, t.maxRows, t.partitionID, t.collectionID, t.GetChannelName(), 4096,
+			slices.Clone(writerOpts)...,
+		)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -254,7 +257,7 @@ func (t *mixCompactionTask) mergeSplit(
 
 func (t *mixCompactionTask) writeSegment(ctx context.Context,
 	seg *datapb.CompactionSegmentBinlogs,
-	mWriter *MultiSegmentWriter, pkField *schemapb.FieldSchema,
+	mWriter compactionSegmentWriter, pkField *schemapb.FieldSchema,
 	writerSchema *schemapb.CollectionSchema,
 ) (deletedRowCount, expiredRowCount int64, err error) {
 	delta, err := compaction.ComposeDeleteFromDeltalogs(ctx, pkField.DataType, seg,
@@ -131,6 +131,7 @@ func (w *MultiSegmentWriter) closeWriter() error {
 			StorageVersion:      w.storageVersion,
 			Manifest:            manifest,
 			ExpirQuantiles:      expirQuantiles,
+			TimeFieldRange:      w.writer.GetTimeFieldRange(),
 		}
 
 		w.res = append(w.res, result)
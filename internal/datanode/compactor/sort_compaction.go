@@ -393,6 +393,7 @@ func (t *sortCompactionTask) sortSegment(ctx context.Context) (*datapb.Compactio
 			StorageVersion:      t.storageVersion,
 			Manifest:            manifest,
 			ExpirQuantiles:      expirQuantiles,
+			TimeFieldRange:      srw.GetTimeFieldRange(),
 		},
 	}
 	planResult := &datapb.CompactionPlanResult{
@@ -0,0 +1,190 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compactor
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow/array"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/compaction"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// nullTimeBucket is the bucket of the rows whose time field is null.
+const nullTimeBucket = math.MinInt64
+
+// compactionSegmentWriter writes the records of a compaction into the result segments.
+type compactionSegmentWriter interface {
+	storage.RecordWriter
+	GetCompactionSegments() []*datapb.CompactionSegment
+}
+
+var (
+	_ compactionSegmentWriter = (*MultiSegmentWriter)(nil)
+	_ compactionSegmentWriter = (*timeBucketSegmentWriter)(nil)
+)
+
+// newCompactionSegmentWriter returns the writer of the mix compaction result,
+// the result is split by the time buckets if the collection enables the time-partitioned compaction.
+func newCompactionSegmentWriter(params compaction.Params, schema *schemapb.CollectionSchema,
+	newWriter func() (*MultiSegmentWriter, error),
+) (compactionSegmentWriter, error) {
+	fieldID := common.GetCollectionCompactionTimeFieldID(schema)
+	if params.CompactionTimeBucket <= 0 || fieldID < common.StartOfUserFieldID {
+		writer, err := newWriter()
+		if err != nil {
+			return nil, err
+		}
+		return writer, nil
+	}
+	return &timeBucketSegmentWriter{
+		fieldID:   fieldID,
+		bucket:    params.CompactionTimeBucket,
+		schema:    schema,
+		newWriter: newWriter,
+		writers:   make(map[int64]*MultiSegmentWriter),
+	}, nil
+}
+
+// timeBucketSegmentWriter splits the rows by the time bucket of the compaction time field,
+// each bucket is written by its own MultiSegmentWriter so no result segment spans buckets.
+// The rows with a null time field are written into a bucket of their own.
+// Not concurrent safe.
+type timeBucketSegmentWriter struct {
+	fieldID   int64
+	bucket    time.Duration
+	schema    *schemapb.CollectionSchema
+	newWriter func() (*MultiSegmentWriter, error)
+	writers   map[int64]*MultiSegmentWriter
+}
+
+func (w *timeBucketSegmentWriter) bucketOf(values *array.Int64, i int) int64 {
+	if values.IsNull(i) {
+		return nullTimeBucket
+	}
+	return common.GetCompactionTimeBucketStart(values.Value(i), w.bucket)
+}
+
+func (w *timeBucketSegmentWriter) getWriter(bucket int64) (*MultiSegmentWriter, error) {
+	if writer, ok := w.writers[bucket]; ok {
+		return writer, nil
+	}
+	writer, err := w.newWriter()
+	if err != nil {
+		return nil, err
+	}
+	w.writers[bucket] = writer
+	return writer, nil
+}
+
+func (w *timeBucketSegmentWriter) Write(r storage.Record) error {
+	values, ok := r.Column(w.fieldID).(*array.Int64)
+	if !ok {
+		return merr.WrapErrServiceInternal("compaction time field is not int64")
+	}
+	rows := r.Len()
+	if rows == 0 {
+		return nil
+	}
+
+	// the rows are appended to the builder of their bucket run by run,
+	// the record is written as it is if all the rows are in one bucket.
+	builders := make(map[int64]*storage.RecordBuilder)
+	start := 0
+	startBucket := w.bucketOf(values, 0)
+	for i := 1; i <= rows; i++ {
+		if i < rows {
+			if bucket := w.bucketOf(values, i); bucket == startBucket {
+				continue
+			}
+		}
+		if start == 0 && i == rows {
+			writer, err := w.getWriter(startBucket)
+			if err != nil {
+				return err
+			}
+			return writer.Write(r)
+		}
+		builder, ok := builders[startBucket]
+		if !ok {
+			builder = storage.NewRecordBuilder(w.schema)
+			builders[startBucket] = builder
+		}
+		if err := builder.Append(r, start, i); err != nil {
+			return err
+		}
+		if i < rows {
+			start = i
+			startBucket = w.bucketOf(values, i)
+		}
+	}
+
+	for bucket, builder := range builders {
+		writer, err := w.getWriter(bucket)
+		if err != nil {
+			return err
+		}
+		err = func() error {
+			rec := builder.Build()
+			defer rec.Release()
+			return writer.Write(rec)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *timeBucketSegmentWriter) GetWrittenUncompressed() uint64 {
+	var size uint64
+	for _, writer := range w.writers {
+		size += writer.GetWrittenUncompressed()
+	}
+	return size
+}
+
+func (w *timeBucketSegmentWriter) Close() error {
+	var firstErr error
+	for _, writer := range w.writers {
+		if err := writer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// GetCompactionSegments returns the result segments ordered by their time buckets.
+func (w *timeBucketSegmentWriter) GetCompactionSegments() []*datapb.CompactionSegment {
+	buckets := make([]int64, 0, len(w.writers))
+	for bucket := range w.writers {
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+	res := make([]*datapb.CompactionSegment, 0)
+	for _, bucket := range buckets {
+		res = append(res, w.writers[bucket].GetCompactionSegments()...)
+	}
+	return res
+}
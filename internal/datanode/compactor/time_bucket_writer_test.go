@@ -0,0 +1,109 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compactor
+
+import (
+	"context"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+)
+
+func (s *MultiSegmentWriterSuite) genTimeBucketSchema() *schemapb.CollectionSchema {
+	schema := s.genSimpleSchema()
+	schema.Fields = append(schema.Fields, &schemapb.FieldSchema{
+		FieldID:  103,
+		Name:     "event_time",
+		DataType: schemapb.DataType_Timestamptz,
+		Nullable: true,
+	})
+	schema.Properties = []*commonpb.KeyValuePair{
+		{Key: common.CollectionCompactionTimeFieldKey, Value: "event_time"},
+		{Key: common.CollectionCompactionTimeBucketKey, Value: "hour"},
+	}
+	return schema
+}
+
+func (s *MultiSegmentWriterSuite) newTestCompactionSegmentWriter(schema *schemapb.CollectionSchema, bucket time.Duration) compactionSegmentWriter {
+	params := s.params
+	params.CompactionTimeBucket = bucket
+	alloc := NewCompactionAllocator(s.mockAlloc, s.mockAlloc)
+	writer, err := newCompactionSegmentWriter(params, schema, func() (*MultiSegmentWriter, error) {
+		return NewMultiSegmentWriter(context.Background(), s.mockBinlogIO, alloc, 10*1024*1024, schema, params,
+			1000, s.partitionID, s.collectionID, s.channel, s.batchSize,
+			storage.WithStorageConfig(params.StorageConfig))
+	})
+	s.Require().NoError(err)
+	return writer
+}
+
+func (s *MultiSegmentWriterSuite) TestNewCompactionSegmentWriter() {
+	schema := s.genTimeBucketSchema()
+	s.IsType(&timeBucketSegmentWriter{}, s.newTestCompactionSegmentWriter(schema, time.Hour))
+	// the time bucket is not enabled
+	s.IsType(&MultiSegmentWriter{}, s.newTestCompactionSegmentWriter(schema, 0))
+	// no time field
+	s.IsType(&MultiSegmentWriter{}, s.newTestCompactionSegmentWriter(s.genSimpleSchema(), time.Hour))
+}
+
+func (s *MultiSegmentWriterSuite) TestTimeBucketSegmentWriter() {
+	schema := s.genTimeBucketSchema()
+	writer := s.newTestCompactionSegmentWriter(schema, time.Hour)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).UnixMicro()
+	step := (20 * time.Minute).Microseconds()
+	genRecord := func(start, end int64) storage.Record {
+		values := make([]*storage.Value, 0)
+		for i := start; i < end; i++ {
+			value := s.genTestValue(i)
+			if i%4 == 3 {
+				value.Value.(map[int64]interface{})[103] = nil
+			} else {
+				value.Value.(map[int64]interface{})[103] = base + i*step
+			}
+			values = append(values, value)
+		}
+		rec, err := storage.ValueSerializer(values, schema)
+		s.Require().NoError(err)
+		return rec
+	}
+
+	// rows 0-12 span 5 hours, every 4th row has a null time field
+	s.NoError(writer.Write(genRecord(0, 6)))
+	s.NoError(writer.Write(genRecord(6, 12)))
+	// a record within one bucket
+	s.NoError(writer.Write(genRecord(12, 13)))
+	s.NoError(writer.Close())
+
+	segments := writer.GetCompactionSegments()
+	// the null bucket comes first, then the buckets of hour 0 to 4
+	s.Require().Len(segments, 6)
+	s.Nil(segments[0].GetTimeFieldRange())
+	s.EqualValues(3, segments[0].GetNumOfRows())
+	expected := []struct {
+		min, max int64
+		rows     int64
+	}{{0, 2, 3}, {4, 5, 2}, {6, 8, 2}, {9, 10, 2}, {12, 12, 1}}
+	for i, e := range expected {
+		segment := segments[i+1]
+		s.Equal([]int64{base + e.min*step, base + e.max*step}, segment.GetTimeFieldRange())
+		s.Equal(e.rows, segment.GetNumOfRows())
+	}
+}
@@ -136,7 +136,9 @@ func (s *Server) start() (err error) {
 		log.Info("start CDC server finished")
 	}()
 
-	s.cdcServer.Start()
+	if err = s.cdcServer.Start(); err != nil {
+		return err
+	}
 
 	s.componentState.OnInitialized(0)
 	return nil
@@ -153,7 +153,9 @@ func (s *Server) init() error {
 	s.mixCoord.SetMixCoordClient(s.mixCoordClient)
 	log.Info("etcd connect done ...")
 
-	if params.MetaStoreCfg.MetaStoreType.GetValue() == util.MetaStoreTypeTiKV {
+	// the streaming meta may be stored on tikv even if the other meta is stored on etcd.
+	if params.MetaStoreCfg.MetaStoreType.GetValue() == util.MetaStoreTypeTiKV ||
+		params.GetStreamingMetaStoreType() == util.MetaStoreTypeTiKV {
 		log.Info("Connecting to tikv metadata storage.")
 		s.tikvCli, err = getTiKVClient(&paramtable.Get().TiKVCfg)
 		if err != nil {
@@ -25,21 +25,26 @@ import (
 // v2
 const (
 	// --- category ---
-	DataBaseCategory        = "/databases/"
-	CollectionCategory      = "/collections/"
-	EntityCategory          = "/entities/"
-	PartitionCategory       = "/partitions/"
-	UserCategory            = "/users/"
-	RoleCategory            = "/roles/"
-	IndexCategory           = "/indexes/"
-	AliasCategory           = "/aliases/"
-	ImportJobCategory       = "/jobs/import/"
-	PrivilegeGroupCategory  = "/privilege_groups/"
-	CollectionFieldCategory = "/collections/fields/"
-	ResourceGroupCategory   = "/resource_groups/"
-	SegmentCategory         = "/segments/"
-	QuotaCenterCategory     = "/quotacenter/"
-	CommonCategory          = "/common/"
+	DataBaseCategory           = "/databases/"
+	CollectionCategory         = "/collections/"
+	EntityCategory             = "/entities/"
+	PartitionCategory          = "/partitions/"
+	UserCategory               = "/users/"
+	RoleCategory               = "/roles/"
+	IndexCategory              = "/indexes/"
+	AliasCategory              = "/aliases/"
+	ImportJobCategory          = "/jobs/import/"
+	PrivilegeGroupCategory     = "/privilege_groups/"
+	CollectionFieldCategory    = "/collections/fields/"
+	ResourceGroupCategory      = "/resource_groups/"
+	SegmentCategory            = "/segments/"
+	QuotaCenterCategory        = "/quotacenter/"
+	CommonCategory             = "/common/"
+	SnapshotCategory           = "/snapshots/"
+	ExternalCollectionCategory = "/collections/external/"
+	ReplicateCategory          = "/replicate/"
+
+	OpenAPIPath = "/openapi.json"
 
 	ListAction           = "list"
 	HasAction            = "has"
@@ -60,6 +65,8 @@ const (
 	SearchAction         = "search"
 	AdvancedSearchAction = "advanced_search"
 	HybridSearchAction   = "hybrid_search"
+	QueryIteratorAction  = "query_iterator"
+	SearchIteratorAction = "search_iterator"
 
 	UpdatePasswordAction            = "update_password"
 	GrantRoleAction                 = "grant_role"
@@ -85,12 +92,22 @@ const (
 	TransferReplicaAction           = "transfer_replica"
 
 	RunAnalyzerAction = "run_analyzer"
+
+	RestoreAction            = "restore"
+	GetRestoreStateAction    = "get_restore_state"
+	ListRestoreJobsAction    = "list_restore_jobs"
+	PinAction                = "pin"
+	UnpinAction              = "unpin"
+	RefreshAction            = "refresh"
+	GetRefreshProgressAction = "get_refresh_progress"
+	ListRefreshJobsAction    = "list_refresh_jobs"
 )
 
 const (
 	ContextRequest                = "request"
 	ContextUsername               = "username"
 	ContextToken                  = "token"
+	ContextResponseStatus         = "response_status"
 	VectorCollectionsPath         = "/vector/collections"
 	VectorCollectionsCreatePath   = "/vector/collections/create"
 	VectorCollectionsDescribePath = "/vector/collections/describe"
@@ -129,10 +146,12 @@ const (
 	HTTPHeaderAllowInt64     = "Accept-Type-Allow-Int64"
 	HTTPHeaderDBName         = "DB-Name"
 	HTTPHeaderRequestTimeout = "Request-Timeout"
+	HTTPHeaderPriorityClass  = "Priority-Class"
 	HTTPReturnCode           = "code"
 	HTTPReturnMessage        = "message"
 	HTTPReturnData           = "data"
 	HTTPReturnCost           = "cost"
+	HTTPReturnCursor         = "cursor"
 	HTTPReturnRecalls        = "recalls"
 	HTTPReturnLoadState      = "loadState"
 	HTTPReturnLoadProgress   = "loadProgress"
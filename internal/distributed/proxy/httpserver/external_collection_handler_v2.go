@@ -0,0 +1,104 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+func toRefreshExternalCollectionJob(info *milvuspb.RefreshExternalCollectionJobInfo) gin.H {
+	job := gin.H{
+		"jobId":            info.GetJobId(),
+		HTTPCollectionName: info.GetCollectionName(),
+		"state":            info.GetState().String(),
+		"progress":         info.GetProgress(),
+		"externalSource":   info.GetExternalSource(),
+		"startTime":        info.GetStartTime(),
+		"endTime":          info.GetEndTime(),
+	}
+	if info.GetReason() != "" {
+		job["reason"] = info.GetReason()
+	}
+	return job
+}
+
+func (h *HandlersV2) refreshExternalCollection(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*RefreshExternalCollectionReq)
+	req := &milvuspb.RefreshExternalCollectionRequest{
+		DbName:         dbName,
+		CollectionName: httpReq.GetCollectionName(),
+		ExternalSource: httpReq.GetExternalSource(),
+		ExternalSpec:   httpReq.GetExternalSpec(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/RefreshExternalCollection", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.RefreshExternalCollection(reqCtx, req.(*milvuspb.RefreshExternalCollectionRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, gin.H{HTTPReturnCode: merr.Code(nil), HTTPReturnData: gin.H{
+			"jobId": resp.(*milvuspb.RefreshExternalCollectionResponse).GetJobId(),
+		}})
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) getRefreshExternalCollectionProgress(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*Int64JobIDReq)
+	req := &milvuspb.GetRefreshExternalCollectionProgressRequest{
+		JobId: httpReq.GetJobID(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/GetRefreshExternalCollectionProgress", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.GetRefreshExternalCollectionProgress(reqCtx, req.(*milvuspb.GetRefreshExternalCollectionProgressRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode: merr.Code(nil),
+			HTTPReturnData: toRefreshExternalCollectionJob(resp.(*milvuspb.GetRefreshExternalCollectionProgressResponse).GetJobInfo()),
+		})
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) listRefreshExternalCollectionJobs(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*CollectionNameReq)
+	req := &milvuspb.ListRefreshExternalCollectionJobsRequest{
+		DbName:         dbName,
+		CollectionName: httpReq.GetCollectionName(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/ListRefreshExternalCollectionJobs", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.ListRefreshExternalCollectionJobs(reqCtx, req.(*milvuspb.ListRefreshExternalCollectionJobsRequest))
+	})
+	if err == nil {
+		jobs := lo.Map(resp.(*milvuspb.ListRefreshExternalCollectionJobsResponse).GetJobs(), func(info *milvuspb.RefreshExternalCollectionJobInfo, _ int) gin.H {
+			return toRefreshExternalCollectionJob(info)
+		})
+		HTTPReturn(c, http.StatusOK, gin.H{HTTPReturnCode: merr.Code(nil), HTTPReturnData: gin.H{"records": jobs}})
+	}
+	return resp, err
+}
@@ -0,0 +1,85 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestExternalCollectionHandlerV2(t *testing.T) {
+	// init params
+	paramtable.Init()
+
+	// disable rate limit
+	paramtable.Get().Save(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key, "false")
+	defer paramtable.Get().Reset(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key)
+
+	// mock proxy
+	mockProxy := mocks.NewMockProxy(t)
+	mockProxy.EXPECT().RefreshExternalCollection(mock.Anything, mock.Anything).Return(&milvuspb.RefreshExternalCollectionResponse{
+		Status: merr.Success(),
+		JobId:  1,
+	}, nil)
+	mockProxy.EXPECT().GetRefreshExternalCollectionProgress(mock.Anything, mock.Anything).Return(&milvuspb.GetRefreshExternalCollectionProgressResponse{
+		Status:  merr.Success(),
+		JobInfo: &milvuspb.RefreshExternalCollectionJobInfo{JobId: 1, CollectionName: "external"},
+	}, nil)
+	mockProxy.EXPECT().ListRefreshExternalCollectionJobs(mock.Anything, mock.Anything).Return(&milvuspb.ListRefreshExternalCollectionJobsResponse{
+		Status: merr.Success(),
+		Jobs:   []*milvuspb.RefreshExternalCollectionJobInfo{{JobId: 1, CollectionName: "external", Reason: "failed"}},
+	}, nil)
+
+	// setup test server
+	testServer := initHTTPServerV2(mockProxy, false)
+
+	testCases := []requestBodyTestCase{
+		{
+			path:        versionalV2(ExternalCollectionCategory, RefreshAction),
+			requestBody: []byte(`{}`),
+			errCode:     1802,
+			errMsg:      "missing required parameters, error: Key: 'RefreshExternalCollectionReq.CollectionName' Error:Field validation for 'CollectionName' failed on the 'required' tag",
+		},
+		{
+			path:        versionalV2(ExternalCollectionCategory, RefreshAction),
+			requestBody: []byte(`{"collectionName":"external","externalSource":"s3://bucket/path"}`),
+		},
+		{
+			path:        versionalV2(ExternalCollectionCategory, GetRefreshProgressAction),
+			requestBody: []byte(`{}`),
+			errCode:     1802,
+			errMsg:      "missing required parameters, error: Key: 'Int64JobIDReq.JobID' Error:Field validation for 'JobID' failed on the 'required' tag",
+		},
+		{
+			path:        versionalV2(ExternalCollectionCategory, GetRefreshProgressAction),
+			requestBody: []byte(`{"jobId":1}`),
+		},
+		{
+			path:        versionalV2(ExternalCollectionCategory, ListRefreshJobsAction),
+			requestBody: []byte(`{"collectionName":"external"}`),
+		},
+	}
+
+	// verify test case
+	validateRequestBodyTestCases(t, testServer, testCases, false)
+}
@@ -49,6 +49,8 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/metrics"
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/util"
+	"github.com/milvus-io/milvus/pkg/v2/util/contextutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/crypto"
 	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
@@ -110,6 +112,8 @@ var routeToMethod = map[string]string{ //nolint:gosec // not credentials, just a
 	"/v2/vectordb/entities/search":          "Search",
 	"/v2/vectordb/entities/advanced_search": "HybridSearch",
 	"/v2/vectordb/entities/hybrid_search":   "HybridSearch",
+	"/v2/vectordb/entities/query_iterator":  "Query",
+	"/v2/vectordb/entities/search_iterator": "Search",
 
 	"/v2/vectordb/partitions/list":      "ShowPartitions",
 	"/v2/vectordb/partitions/has":       "HasPartition",
@@ -171,157 +175,195 @@ var routeToMethod = map[string]string{ //nolint:gosec // not credentials, just a
 	"/v2/vectordb/quotacenter/describe": "GetQuotaMetrics",
 
 	"/v2/vectordb/common/run_analyzer": "RunAnalyzer",
+
+	"/v2/vectordb/snapshots/create":            "CreateSnapshot",
+	"/v2/vectordb/snapshots/drop":              "DropSnapshot",
+	"/v2/vectordb/snapshots/list":              "ListSnapshots",
+	"/v2/vectordb/snapshots/describe":          "DescribeSnapshot",
+	"/v2/vectordb/snapshots/restore":           "RestoreSnapshot",
+	"/v2/vectordb/snapshots/get_restore_state": "GetRestoreSnapshotState",
+	"/v2/vectordb/snapshots/list_restore_jobs": "ListRestoreSnapshotJobs",
+	"/v2/vectordb/snapshots/pin":               "PinSnapshotData",
+	"/v2/vectordb/snapshots/unpin":             "UnpinSnapshotData",
+
+	"/v2/vectordb/collections/external/refresh":              "RefreshExternalCollection",
+	"/v2/vectordb/collections/external/get_refresh_progress": "GetRefreshExternalCollectionProgress",
+	"/v2/vectordb/collections/external/list_refresh_jobs":    "ListRefreshExternalCollectionJobs",
+
+	"/v2/vectordb/replicate/describe": "GetReplicateConfiguration",
+	"/v2/vectordb/replicate/alter":    "UpdateReplicateConfiguration",
 }
 
-func (h *HandlersV2) RegisterRoutesToV2(router gin.IRouter) {
-	router.POST(CollectionCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReq{} }, wrapperTraceLog(h.listCollections))))
-	router.POST(CollectionCategory+HasAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.hasCollection))))
-	// todo review the return data
-	router.POST(CollectionCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.getCollectionDetails))))
-	router.POST(CollectionCategory+StatsAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.getCollectionStats))))
-	router.POST(CollectionCategory+LoadStateAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.getCollectionLoadState))))
-	router.POST(CollectionCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionReq{AutoID: DisableAutoID} }, wrapperTraceLog(h.createCollection))))
-	router.POST(CollectionCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.dropCollection))))
-	router.POST(CollectionCategory+TruncateAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.truncateCollection))))
-	router.POST(CollectionCategory+RenameAction, timeoutMiddleware(wrapperPost(func() any { return &RenameCollectionReq{} }, wrapperTraceLog(h.renameCollection))))
-	router.POST(CollectionCategory+LoadAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.loadCollection))))
-	router.POST(CollectionCategory+RefreshLoadAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.refreshLoadCollection))))
-	router.POST(CollectionCategory+ReleaseAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.releaseCollection))))
-	router.POST(CollectionCategory+AlterPropertiesAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionReqWithProperties{} }, wrapperTraceLog(h.alterCollectionProperties))))
-	router.POST(CollectionCategory+AddFunctionAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionAddFunction{} }, wrapperTraceLog(h.addCollectionFunction))))
-	router.POST(CollectionCategory+AlterFunctionAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionAlterFunction{} }, wrapperTraceLog(h.alterCollectionFunction))))
-	router.POST(CollectionCategory+DropFunctionAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionDropFunction{} }, wrapperTraceLog(h.dropCollectionFunction))))
-	router.POST(CollectionCategory+DropPropertiesAction, timeoutMiddleware(wrapperPost(func() any { return &DropCollectionPropertiesReq{} }, wrapperTraceLog(h.dropCollectionProperties))))
-	router.POST(CollectionCategory+CompactAction, timeoutMiddleware(wrapperPost(func() any { return &CompactReq{} }, wrapperTraceLog(h.compact))))
-	router.POST(CollectionCategory+CompactionStateAction, timeoutMiddleware(wrapperPost(func() any { return &GetCompactionStateReq{} }, wrapperTraceLog(h.getcompactionState))))
-	router.POST(CollectionCategory+FlushAction, timeoutMiddleware(wrapperPost(func() any { return &FlushReq{} }, wrapperTraceLog(h.flush))))
-
-	router.POST(CollectionFieldCategory+AlterPropertiesAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionFieldReqWithParams{} }, wrapperTraceLog(h.alterCollectionFieldProperties))))
+// routeV2 describes a RESTful v2 route, the router and the OpenAPI spec are both built from routesV2,
+// so the documented request and response are the ones the route binds and returns.
+type routeV2 struct {
+	path    string
+	newReq  newReqFunc
+	handler func(h *HandlersV2, ctx context.Context, c *gin.Context, req any, dbName string) (interface{}, error)
+	// the entity routes are limited by the size of the request, and also of the response if observeOutbound
+	sizeLimited     bool
+	observeOutbound bool
+	summary         string
+	// the response body, only its type is used
+	resp any
+}
 
+func postV2(path string, newReq newReqFunc, handler func(*HandlersV2, context.Context, *gin.Context, any, string) (interface{}, error), summary string, resp any) routeV2 {
+	return routeV2{path: path, newReq: newReq, handler: handler, summary: summary, resp: resp}
+}
+
+func entityPostV2(path string, newReq newReqFunc, handler func(*HandlersV2, context.Context, *gin.Context, any, string) (interface{}, error), observeOutbound bool, summary string, resp any) routeV2 {
+	return routeV2{path: path, newReq: newReq, handler: handler, sizeLimited: true, observeOutbound: observeOutbound, summary: summary, resp: resp}
+}
+
+var routesV2 = []routeV2{
+	postV2(CollectionCategory+ListAction, func() any { return &DatabaseReq{} }, (*HandlersV2).listCollections, "List collections", ListResp{}),
+	postV2(CollectionCategory+HasAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).hasCollection, "Check whether a collection exists", HasResp{}),
+	// todo review the return data
+	postV2(CollectionCategory+DescribeAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).getCollectionDetails, "Describe a collection", CollectionDetailResp{}),
+	postV2(CollectionCategory+StatsAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).getCollectionStats, "Get collection statistics", RowCountResp{}),
+	postV2(CollectionCategory+LoadStateAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).getCollectionLoadState, "Get the load state of a collection", LoadStateResp{}),
+	postV2(CollectionCategory+CreateAction, newCollectionReq, (*HandlersV2).createCollection, "Create a collection", DefaultResp{}),
+	postV2(CollectionCategory+DropAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).dropCollection, "Drop a collection", DefaultResp{}),
+	postV2(CollectionCategory+TruncateAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).truncateCollection, "Truncate a collection", DefaultResp{}),
+	postV2(CollectionCategory+RenameAction, func() any { return &RenameCollectionReq{} }, (*HandlersV2).renameCollection, "Rename a collection", DefaultResp{}),
+	postV2(CollectionCategory+LoadAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).loadCollection, "Load a collection", DefaultResp{}),
+	postV2(CollectionCategory+RefreshLoadAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).refreshLoadCollection, "Refresh the loaded segments of a collection", DefaultResp{}),
+	postV2(CollectionCategory+ReleaseAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).releaseCollection, "Release a collection", DefaultResp{}),
+	postV2(CollectionCategory+AlterPropertiesAction, func() any { return &CollectionReqWithProperties{} }, (*HandlersV2).alterCollectionProperties, "Alter collection properties", DefaultResp{}),
+	postV2(CollectionCategory+AddFunctionAction, func() any { return &CollectionAddFunction{} }, (*HandlersV2).addCollectionFunction, "Add a function to a collection", DefaultResp{}),
+	postV2(CollectionCategory+AlterFunctionAction, func() any { return &CollectionAlterFunction{} }, (*HandlersV2).alterCollectionFunction, "Alter a collection function", DefaultResp{}),
+	postV2(CollectionCategory+DropFunctionAction, func() any { return &CollectionDropFunction{} }, (*HandlersV2).dropCollectionFunction, "Drop a collection function", DefaultResp{}),
+	postV2(CollectionCategory+DropPropertiesAction, func() any { return &DropCollectionPropertiesReq{} }, (*HandlersV2).dropCollectionProperties, "Drop collection properties", DefaultResp{}),
+	postV2(CollectionCategory+CompactAction, func() any { return &CompactReq{} }, (*HandlersV2).compact, "Compact a collection", CompactResp{}),
+	postV2(CollectionCategory+CompactionStateAction, func() any { return &GetCompactionStateReq{} }, (*HandlersV2).getcompactionState, "Get the state of a compaction", CompactionStateResp{}),
+	postV2(CollectionCategory+FlushAction, func() any { return &FlushReq{} }, (*HandlersV2).flush, "Flush a collection", DefaultResp{}),
+
+	postV2(CollectionFieldCategory+AlterPropertiesAction, func() any { return &CollectionFieldReqWithParams{} }, (*HandlersV2).alterCollectionFieldProperties, "Alter field properties", DefaultResp{}),
 	// /collections/fields/add
-	router.POST(CollectionFieldCategory+AddAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionFieldReqWithSchema{} }, wrapperTraceLog(h.addCollectionField))))
-
-	router.POST(DataBaseCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReqWithProperties{} }, wrapperTraceLog(h.createDatabase))))
-	router.POST(DataBaseCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReqRequiredName{} }, wrapperTraceLog(h.dropDatabase))))
-	router.POST(DataBaseCategory+DropPropertiesAction, timeoutMiddleware(wrapperPost(func() any { return &DropDatabasePropertiesReq{} }, wrapperTraceLog(h.dropDatabaseProperties))))
-	router.POST(DataBaseCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &EmptyReq{} }, wrapperTraceLog(h.listDatabases))))
-	router.POST(DataBaseCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReqRequiredName{} }, wrapperTraceLog(h.describeDatabase))))
-	router.POST(DataBaseCategory+AlterAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReqWithProperties{} }, wrapperTraceLog(h.alterDatabase))))
-	router.POST(DataBaseCategory+AlterPropertiesAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReqWithProperties{} }, wrapperTraceLog(h.alterDatabase))))
-	// Query
-	router.POST(EntityCategory+QueryAction, restfulSizeMiddleware(timeoutMiddleware(wrapperPost(func() any {
-		return &QueryReqV2{
-			Limit:        100,
-			OutputFields: []string{DefaultOutputFields},
-		}
-	}, wrapperTraceLog(h.query))), true))
-	// Get
-	router.POST(EntityCategory+GetAction, restfulSizeMiddleware(timeoutMiddleware(wrapperPost(func() any {
-		return &CollectionIDReq{
-			OutputFields: []string{DefaultOutputFields},
-		}
-	}, wrapperTraceLog(h.get))), true))
-	// Delete
-	router.POST(EntityCategory+DeleteAction, restfulSizeMiddleware(timeoutMiddleware(wrapperPost(func() any {
-		return &CollectionFilterReq{}
-	}, wrapperTraceLog(h.delete))), false))
-	// Insert
-	router.POST(EntityCategory+InsertAction, restfulSizeMiddleware(timeoutMiddleware(wrapperPost(func() any {
-		return &CollectionDataReq{}
-	}, wrapperTraceLog(h.insert))), false))
-	// Upsert
-	router.POST(EntityCategory+UpsertAction, restfulSizeMiddleware(timeoutMiddleware(wrapperPost(func() any {
-		return &CollectionDataReq{}
-	}, wrapperTraceLog(h.upsert))), false))
-	// Search
-	router.POST(EntityCategory+SearchAction, restfulSizeMiddleware(timeoutMiddleware(wrapperPost(func() any {
-		return &SearchReqV2{
-			Limit: 100,
-		}
-	}, wrapperTraceLog(h.search))), true))
+	postV2(CollectionFieldCategory+AddAction, func() any { return &CollectionFieldReqWithSchema{} }, (*HandlersV2).addCollectionField, "Add a field to a collection", DefaultResp{}),
+
+	postV2(DataBaseCategory+CreateAction, func() any { return &DatabaseReqWithProperties{} }, (*HandlersV2).createDatabase, "Create a database", DefaultResp{}),
+	postV2(DataBaseCategory+DropAction, func() any { return &DatabaseReqRequiredName{} }, (*HandlersV2).dropDatabase, "Drop a database", DefaultResp{}),
+	postV2(DataBaseCategory+DropPropertiesAction, func() any { return &DropDatabasePropertiesReq{} }, (*HandlersV2).dropDatabaseProperties, "Drop database properties", DefaultResp{}),
+	postV2(DataBaseCategory+ListAction, func() any { return &EmptyReq{} }, (*HandlersV2).listDatabases, "List databases", ListResp{}),
+	postV2(DataBaseCategory+DescribeAction, func() any { return &DatabaseReqRequiredName{} }, (*HandlersV2).describeDatabase, "Describe a database", DatabaseDetailResp{}),
+	postV2(DataBaseCategory+AlterAction, func() any { return &DatabaseReqWithProperties{} }, (*HandlersV2).alterDatabase, "Alter database properties", DefaultResp{}),
+	postV2(DataBaseCategory+AlterPropertiesAction, func() any { return &DatabaseReqWithProperties{} }, (*HandlersV2).alterDatabase, "Alter database properties", DefaultResp{}),
+
+	entityPostV2(EntityCategory+QueryAction, newQueryReqV2, (*HandlersV2).query, true, "Query entities by filter", QueryResp{}),
+	entityPostV2(EntityCategory+GetAction, newCollectionIDReq, (*HandlersV2).get, true, "Get entities by primary key", QueryResp{}),
+	entityPostV2(EntityCategory+DeleteAction, func() any { return &CollectionFilterReq{} }, (*HandlersV2).delete, false, "Delete entities", DeleteResp{}),
+	entityPostV2(EntityCategory+InsertAction, func() any { return &CollectionDataReq{} }, (*HandlersV2).insert, false, "Insert entities", InsertResp{}),
+	entityPostV2(EntityCategory+UpsertAction, func() any { return &CollectionDataReq{} }, (*HandlersV2).upsert, false, "Upsert entities", UpsertResp{}),
+	entityPostV2(EntityCategory+SearchAction, newSearchReqV2, (*HandlersV2).search, true, "Search entities by vectors or primary keys", SearchResp{}),
 	// advanced_search, backward compatible uri
-	router.POST(EntityCategory+AdvancedSearchAction, restfulSizeMiddleware(timeoutMiddleware(wrapperPost(func() any {
-		return &HybridSearchReq{
-			Limit: 100,
-		}
-	}, wrapperTraceLog(h.advancedSearch))), true))
-	// HybridSearch
-	router.POST(EntityCategory+HybridSearchAction, restfulSizeMiddleware(timeoutMiddleware(wrapperPost(func() any {
-		return &HybridSearchReq{
-			Limit: 100,
-		}
-	}, wrapperTraceLog(h.advancedSearch))), true))
-
-	router.POST(PartitionCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.listPartitions))))
-	router.POST(PartitionCategory+HasAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionReq{} }, wrapperTraceLog(h.hasPartitions))))
-	router.POST(PartitionCategory+StatsAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionReq{} }, wrapperTraceLog(h.statsPartition))))
-
-	router.POST(PartitionCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionReq{} }, wrapperTraceLog(h.createPartition))))
-	router.POST(PartitionCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionReq{} }, wrapperTraceLog(h.dropPartition))))
-	router.POST(PartitionCategory+LoadAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionsReq{} }, wrapperTraceLog(h.loadPartitions))))
-	router.POST(PartitionCategory+ReleaseAction, timeoutMiddleware(wrapperPost(func() any { return &PartitionsReq{} }, wrapperTraceLog(h.releasePartitions))))
-
-	router.POST(UserCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReq{} }, wrapperTraceLog(h.listUsers))))
-	router.POST(UserCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &UserReq{} }, wrapperTraceLog(h.describeUser))))
-
-	router.POST(UserCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &PasswordReq{} }, wrapperTraceLog(h.createUser))))
-	router.POST(UserCategory+UpdatePasswordAction, timeoutMiddleware(wrapperPost(func() any { return &NewPasswordReq{} }, wrapperTraceLog(h.updateUser))))
-	router.POST(UserCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &UserReq{} }, wrapperTraceLog(h.dropUser))))
-	router.POST(UserCategory+GrantRoleAction, timeoutMiddleware(wrapperPost(func() any { return &UserRoleReq{} }, wrapperTraceLog(h.addRoleToUser))))
-	router.POST(UserCategory+RevokeRoleAction, timeoutMiddleware(wrapperPost(func() any { return &UserRoleReq{} }, wrapperTraceLog(h.removeRoleFromUser))))
-
-	router.POST(RoleCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReq{} }, wrapperTraceLog(h.listRoles))))
-	router.POST(RoleCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &RoleReq{} }, wrapperTraceLog(h.describeRole))))
-
-	router.POST(RoleCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &RoleReq{} }, wrapperTraceLog(h.createRole))))
-	router.POST(RoleCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &RoleReq{} }, wrapperTraceLog(h.dropRole))))
-	router.POST(RoleCategory+GrantPrivilegeAction, timeoutMiddleware(wrapperPost(func() any { return &GrantReq{} }, wrapperTraceLog(h.addPrivilegeToRole))))
-	router.POST(RoleCategory+RevokePrivilegeAction, timeoutMiddleware(wrapperPost(func() any { return &GrantReq{} }, wrapperTraceLog(h.removePrivilegeFromRole))))
-	router.POST(RoleCategory+GrantPrivilegeActionV2, timeoutMiddleware(wrapperPost(func() any { return &GrantV2Req{} }, wrapperTraceLog(h.grantV2))))
-	router.POST(RoleCategory+RevokePrivilegeActionV2, timeoutMiddleware(wrapperPost(func() any { return &GrantV2Req{} }, wrapperTraceLog(h.revokeV2))))
+	entityPostV2(EntityCategory+AdvancedSearchAction, newHybridSearchReq, (*HandlersV2).advancedSearch, true, "Hybrid search, kept for backward compatibility", HybridSearchResp{}),
+	entityPostV2(EntityCategory+HybridSearchAction, newHybridSearchReq, (*HandlersV2).advancedSearch, true, "Hybrid search", HybridSearchResp{}),
+	entityPostV2(EntityCategory+QueryIteratorAction, newQueryIteratorReqV2, (*HandlersV2).queryIterator, true, "Query entities page by page", IteratorResp{}),
+	entityPostV2(EntityCategory+SearchIteratorAction, newSearchIteratorReqV2, (*HandlersV2).searchIterator, true, "Search entities page by page", IteratorResp{}),
+
+	postV2(PartitionCategory+ListAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).listPartitions, "List partitions", ListResp{}),
+	postV2(PartitionCategory+HasAction, func() any { return &PartitionReq{} }, (*HandlersV2).hasPartitions, "Check whether a partition exists", HasResp{}),
+	postV2(PartitionCategory+StatsAction, func() any { return &PartitionReq{} }, (*HandlersV2).statsPartition, "Get partition statistics", RowCountResp{}),
+	postV2(PartitionCategory+CreateAction, func() any { return &PartitionReq{} }, (*HandlersV2).createPartition, "Create a partition", DefaultResp{}),
+	postV2(PartitionCategory+DropAction, func() any { return &PartitionReq{} }, (*HandlersV2).dropPartition, "Drop a partition", DefaultResp{}),
+	postV2(PartitionCategory+LoadAction, func() any { return &PartitionsReq{} }, (*HandlersV2).loadPartitions, "Load partitions", DefaultResp{}),
+	postV2(PartitionCategory+ReleaseAction, func() any { return &PartitionsReq{} }, (*HandlersV2).releasePartitions, "Release partitions", DefaultResp{}),
+
+	postV2(UserCategory+ListAction, func() any { return &DatabaseReq{} }, (*HandlersV2).listUsers, "List users", ListResp{}),
+	postV2(UserCategory+DescribeAction, func() any { return &UserReq{} }, (*HandlersV2).describeUser, "List the roles of a user", ListResp{}),
+	postV2(UserCategory+CreateAction, func() any { return &PasswordReq{} }, (*HandlersV2).createUser, "Create a user", DefaultResp{}),
+	postV2(UserCategory+UpdatePasswordAction, func() any { return &NewPasswordReq{} }, (*HandlersV2).updateUser, "Update the password of a user", DefaultResp{}),
+	postV2(UserCategory+DropAction, func() any { return &UserReq{} }, (*HandlersV2).dropUser, "Drop a user", DefaultResp{}),
+	postV2(UserCategory+GrantRoleAction, func() any { return &UserRoleReq{} }, (*HandlersV2).addRoleToUser, "Grant a role to a user", DefaultResp{}),
+	postV2(UserCategory+RevokeRoleAction, func() any { return &UserRoleReq{} }, (*HandlersV2).removeRoleFromUser, "Revoke a role from a user", DefaultResp{}),
+
+	postV2(RoleCategory+ListAction, func() any { return &DatabaseReq{} }, (*HandlersV2).listRoles, "List roles", ListResp{}),
+	postV2(RoleCategory+DescribeAction, func() any { return &RoleReq{} }, (*HandlersV2).describeRole, "List the privileges of a role", RoleDetailResp{}),
+	postV2(RoleCategory+CreateAction, func() any { return &RoleReq{} }, (*HandlersV2).createRole, "Create a role", DefaultResp{}),
+	postV2(RoleCategory+DropAction, func() any { return &RoleReq{} }, (*HandlersV2).dropRole, "Drop a role", DefaultResp{}),
+	postV2(RoleCategory+GrantPrivilegeAction, func() any { return &GrantReq{} }, (*HandlersV2).addPrivilegeToRole, "Grant a privilege to a role", DefaultResp{}),
+	postV2(RoleCategory+RevokePrivilegeAction, func() any { return &GrantReq{} }, (*HandlersV2).removePrivilegeFromRole, "Revoke a privilege from a role", DefaultResp{}),
+	postV2(RoleCategory+GrantPrivilegeActionV2, func() any { return &GrantV2Req{} }, (*HandlersV2).grantV2, "Grant a privilege to a role on a database and collection", DefaultResp{}),
+	postV2(RoleCategory+RevokePrivilegeActionV2, func() any { return &GrantV2Req{} }, (*HandlersV2).revokeV2, "Revoke a privilege from a role on a database and collection", DefaultResp{}),
 
 	// privilege group
-	router.POST(PrivilegeGroupCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &PrivilegeGroupReq{} }, wrapperTraceLog(h.createPrivilegeGroup))))
-	router.POST(PrivilegeGroupCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &PrivilegeGroupReq{} }, wrapperTraceLog(h.dropPrivilegeGroup))))
-	router.POST(PrivilegeGroupCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &DatabaseReq{} }, wrapperTraceLog(h.listPrivilegeGroups))))
-	router.POST(PrivilegeGroupCategory+AddPrivilegesToGroupAction, timeoutMiddleware(wrapperPost(func() any { return &PrivilegeGroupReq{} }, wrapperTraceLog(h.addPrivilegesToGroup))))
-	router.POST(PrivilegeGroupCategory+RemovePrivilegesFromGroupAction, timeoutMiddleware(wrapperPost(func() any { return &PrivilegeGroupReq{} }, wrapperTraceLog(h.removePrivilegesFromGroup))))
-
-	router.POST(IndexCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &CollectionNameReq{} }, wrapperTraceLog(h.listIndexes))))
-	router.POST(IndexCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &IndexReq{} }, wrapperTraceLog(h.describeIndex))))
-
-	router.POST(IndexCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &IndexParamReq{} }, wrapperTraceLog(h.createIndex))))
+	postV2(PrivilegeGroupCategory+CreateAction, func() any { return &PrivilegeGroupReq{} }, (*HandlersV2).createPrivilegeGroup, "Create a privilege group", DefaultResp{}),
+	postV2(PrivilegeGroupCategory+DropAction, func() any { return &PrivilegeGroupReq{} }, (*HandlersV2).dropPrivilegeGroup, "Drop a privilege group", DefaultResp{}),
+	postV2(PrivilegeGroupCategory+ListAction, func() any { return &DatabaseReq{} }, (*HandlersV2).listPrivilegeGroups, "List privilege groups", PrivilegeGroupsResp{}),
+	postV2(PrivilegeGroupCategory+AddPrivilegesToGroupAction, func() any { return &PrivilegeGroupReq{} }, (*HandlersV2).addPrivilegesToGroup, "Add privileges to a privilege group", DefaultResp{}),
+	postV2(PrivilegeGroupCategory+RemovePrivilegesFromGroupAction, func() any { return &PrivilegeGroupReq{} }, (*HandlersV2).removePrivilegesFromGroup, "Remove privileges from a privilege group", DefaultResp{}),
+
+	postV2(IndexCategory+ListAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).listIndexes, "List indexes", ListResp{}),
+	postV2(IndexCategory+DescribeAction, func() any { return &IndexReq{} }, (*HandlersV2).describeIndex, "Describe an index", IndexDetailResp{}),
+	postV2(IndexCategory+CreateAction, func() any { return &IndexParamReq{} }, (*HandlersV2).createIndex, "Create indexes", DefaultResp{}),
 	// todo cannot drop index before release it ?
-	router.POST(IndexCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &IndexReq{} }, wrapperTraceLog(h.dropIndex))))
-	router.POST(IndexCategory+AlterPropertiesAction, timeoutMiddleware(wrapperPost(func() any { return &IndexReqWithProperties{} }, wrapperTraceLog(h.alterIndexProperties))))
-	router.POST(IndexCategory+DropPropertiesAction, timeoutMiddleware(wrapperPost(func() any { return &DropIndexPropertiesReq{} }, wrapperTraceLog(h.dropIndexProperties))))
-
-	router.POST(AliasCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &OptionalCollectionNameReq{} }, wrapperTraceLog(h.listAlias))))
-	router.POST(AliasCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &AliasReq{} }, wrapperTraceLog(h.describeAlias))))
+	postV2(IndexCategory+DropAction, func() any { return &IndexReq{} }, (*HandlersV2).dropIndex, "Drop an index", DefaultResp{}),
+	postV2(IndexCategory+AlterPropertiesAction, func() any { return &IndexReqWithProperties{} }, (*HandlersV2).alterIndexProperties, "Alter index properties", DefaultResp{}),
+	postV2(IndexCategory+DropPropertiesAction, func() any { return &DropIndexPropertiesReq{} }, (*HandlersV2).dropIndexProperties, "Drop index properties", DefaultResp{}),
 
-	router.POST(AliasCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &AliasCollectionReq{} }, wrapperTraceLog(h.createAlias))))
-	router.POST(AliasCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &AliasReq{} }, wrapperTraceLog(h.dropAlias))))
-	router.POST(AliasCategory+AlterAction, timeoutMiddleware(wrapperPost(func() any { return &AliasCollectionReq{} }, wrapperTraceLog(h.alterAlias))))
+	postV2(AliasCategory+ListAction, func() any { return &OptionalCollectionNameReq{} }, (*HandlersV2).listAlias, "List aliases", ListResp{}),
+	postV2(AliasCategory+DescribeAction, func() any { return &AliasReq{} }, (*HandlersV2).describeAlias, "Describe an alias", AliasDetailResp{}),
+	postV2(AliasCategory+CreateAction, func() any { return &AliasCollectionReq{} }, (*HandlersV2).createAlias, "Create an alias", DefaultResp{}),
+	postV2(AliasCategory+DropAction, func() any { return &AliasReq{} }, (*HandlersV2).dropAlias, "Drop an alias", DefaultResp{}),
+	postV2(AliasCategory+AlterAction, func() any { return &AliasCollectionReq{} }, (*HandlersV2).alterAlias, "Alter an alias", DefaultResp{}),
 
-	router.POST(ImportJobCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &OptionalCollectionNameReq{} }, wrapperTraceLog(h.listImportJob))))
-	router.POST(ImportJobCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &ImportReq{} }, wrapperTraceLog(h.createImportJob))))
-	router.POST(ImportJobCategory+GetProgressAction, timeoutMiddleware(wrapperPost(func() any { return &JobIDReq{} }, wrapperTraceLog(h.getImportJobProcess))))
-	router.POST(ImportJobCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &JobIDReq{} }, wrapperTraceLog(h.getImportJobProcess))))
+	postV2(ImportJobCategory+ListAction, func() any { return &OptionalCollectionNameReq{} }, (*HandlersV2).listImportJob, "List import jobs", ImportJobsResp{}),
+	postV2(ImportJobCategory+CreateAction, func() any { return &ImportReq{} }, (*HandlersV2).createImportJob, "Create an import job", JobIDResp{}),
+	postV2(ImportJobCategory+GetProgressAction, func() any { return &JobIDReq{} }, (*HandlersV2).getImportJobProcess, "Get the progress of an import job", ImportProgressResp{}),
+	postV2(ImportJobCategory+DescribeAction, func() any { return &JobIDReq{} }, (*HandlersV2).getImportJobProcess, "Describe an import job", ImportProgressResp{}),
 
 	// resource group
-	router.POST(ResourceGroupCategory+CreateAction, timeoutMiddleware(wrapperPost(func() any { return &ResourceGroupReq{} }, wrapperTraceLog(h.createResourceGroup))))
-	router.POST(ResourceGroupCategory+DropAction, timeoutMiddleware(wrapperPost(func() any { return &ResourceGroupReq{} }, wrapperTraceLog(h.dropResourceGroup))))
-	router.POST(ResourceGroupCategory+AlterAction, timeoutMiddleware(wrapperPost(func() any { return &UpdateResourceGroupReq{} }, wrapperTraceLog(h.updateResourceGroup))))
-	router.POST(ResourceGroupCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &ResourceGroupReq{} }, wrapperTraceLog(h.describeResourceGroup))))
-	router.POST(ResourceGroupCategory+ListAction, timeoutMiddleware(wrapperPost(func() any { return &EmptyReq{} }, wrapperTraceLog(h.listResourceGroups))))
-	router.POST(ResourceGroupCategory+TransferReplicaAction, timeoutMiddleware(wrapperPost(func() any { return &TransferReplicaReq{} }, wrapperTraceLog(h.transferReplica))))
+	postV2(ResourceGroupCategory+CreateAction, func() any { return &ResourceGroupReq{} }, (*HandlersV2).createResourceGroup, "Create a resource group", DefaultResp{}),
+	postV2(ResourceGroupCategory+DropAction, func() any { return &ResourceGroupReq{} }, (*HandlersV2).dropResourceGroup, "Drop a resource group", DefaultResp{}),
+	postV2(ResourceGroupCategory+AlterAction, func() any { return &UpdateResourceGroupReq{} }, (*HandlersV2).updateResourceGroup, "Update resource groups", DefaultResp{}),
+	postV2(ResourceGroupCategory+DescribeAction, func() any { return &ResourceGroupReq{} }, (*HandlersV2).describeResourceGroup, "Describe a resource group", ResourceGroupDetailResp{}),
+	postV2(ResourceGroupCategory+ListAction, func() any { return &EmptyReq{} }, (*HandlersV2).listResourceGroups, "List resource groups", ListResp{}),
+	postV2(ResourceGroupCategory+TransferReplicaAction, func() any { return &TransferReplicaReq{} }, (*HandlersV2).transferReplica, "Transfer replicas between resource groups", DefaultResp{}),
 
 	// segment group
-	router.POST(SegmentCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &GetSegmentsInfoReq{} }, wrapperTraceLog(h.getSegmentsInfo))))
-	router.POST(QuotaCenterCategory+DescribeAction, timeoutMiddleware(wrapperPost(func() any { return &GetQuotaMetricsReq{} }, wrapperTraceLog(h.getQuotaMetrics))))
+	postV2(SegmentCategory+DescribeAction, func() any { return &GetSegmentsInfoReq{} }, (*HandlersV2).getSegmentsInfo, "Describe segments", SegmentsInfoResp{}),
+	postV2(QuotaCenterCategory+DescribeAction, func() any { return &GetQuotaMetricsReq{} }, (*HandlersV2).getQuotaMetrics, "Get quota center metrics", QuotaMetricsResp{}),
 
 	// common
-	router.POST(CommonCategory+RunAnalyzerAction, timeoutMiddleware(wrapperPost(func() any { return &RunAnalyzerReq{} }, wrapperTraceLog(h.runAnalyzer))))
+	postV2(CommonCategory+RunAnalyzerAction, func() any { return &RunAnalyzerReq{} }, (*HandlersV2).runAnalyzer, "Run an analyzer on text", RunAnalyzerResp{}),
+
+	// snapshot
+	postV2(SnapshotCategory+CreateAction, func() any { return &CreateSnapshotReq{} }, (*HandlersV2).createSnapshot, "Create a snapshot", DefaultResp{}),
+	postV2(SnapshotCategory+DropAction, func() any { return &SnapshotReq{} }, (*HandlersV2).dropSnapshot, "Drop a snapshot", DefaultResp{}),
+	postV2(SnapshotCategory+ListAction, func() any { return &OptionalCollectionNameReq{} }, (*HandlersV2).listSnapshots, "List snapshots", ListResp{}),
+	postV2(SnapshotCategory+DescribeAction, func() any { return &SnapshotReq{} }, (*HandlersV2).describeSnapshot, "Describe a snapshot", SnapshotDetailResp{}),
+	postV2(SnapshotCategory+RestoreAction, func() any { return &RestoreSnapshotReq{} }, (*HandlersV2).restoreSnapshot, "Restore a snapshot", Int64JobIDResp{}),
+	postV2(SnapshotCategory+GetRestoreStateAction, func() any { return &Int64JobIDReq{} }, (*HandlersV2).getRestoreSnapshotState, "Get the state of a snapshot restore job", RestoreSnapshotJobResp{}),
+	postV2(SnapshotCategory+ListRestoreJobsAction, func() any { return &OptionalCollectionNameReq{} }, (*HandlersV2).listRestoreSnapshotJobs, "List snapshot restore jobs", RestoreSnapshotJobsResp{}),
+	postV2(SnapshotCategory+PinAction, func() any { return &PinSnapshotReq{} }, (*HandlersV2).pinSnapshotData, "Pin snapshot data", PinSnapshotResp{}),
+	postV2(SnapshotCategory+UnpinAction, func() any { return &UnpinSnapshotReq{} }, (*HandlersV2).unpinSnapshotData, "Unpin snapshot data", DefaultResp{}),
+
+	// external collection
+	postV2(ExternalCollectionCategory+RefreshAction, func() any { return &RefreshExternalCollectionReq{} }, (*HandlersV2).refreshExternalCollection, "Refresh an external collection", Int64JobIDResp{}),
+	postV2(ExternalCollectionCategory+GetRefreshProgressAction, func() any { return &Int64JobIDReq{} }, (*HandlersV2).getRefreshExternalCollectionProgress, "Get the progress of an external collection refresh", RefreshExternalCollectionJobResp{}),
+	postV2(ExternalCollectionCategory+ListRefreshJobsAction, func() any { return &CollectionNameReq{} }, (*HandlersV2).listRefreshExternalCollectionJobs, "List external collection refresh jobs", RefreshExternalCollectionJobsResp{}),
+
+	// replicate
+	postV2(ReplicateCategory+DescribeAction, func() any { return &EmptyReq{} }, (*HandlersV2).getReplicateConfiguration, "Get the replicate configuration", ReplicateConfigurationResp{}),
+	postV2(ReplicateCategory+AlterAction, func() any { return &UpdateReplicateConfigurationReq{} }, (*HandlersV2).updateReplicateConfiguration, "Update the replicate configuration", DefaultResp{}),
+}
+
+func (h *HandlersV2) RegisterRoutesToV2(router gin.IRouter) {
+	for _, route := range routesV2 {
+		handler := route.handler
+		handle := timeoutMiddleware(wrapperPost(route.newReq, wrapperTraceLog(func(ctx context.Context, c *gin.Context, req any, dbName string) (interface{}, error) {
+			return handler(h, ctx, c, req, dbName)
+		})))
+		if route.sizeLimited {
+			handle = restfulSizeMiddleware(handle, route.observeOutbound)
+		}
+		router.POST(route.path, handle)
+	}
+
+	// machine-readable description of the routes above
+	router.GET(OpenAPIPath, h.openAPISpec)
 }
 
 type (
@@ -329,6 +371,49 @@ type (
 	handlerFuncV2 func(ctx context.Context, c *gin.Context, req any, dbName string) (interface{}, error)
 )
 
+// request constructors with non-zero defaults, shared by the router and the OpenAPI spec
+func newCollectionReq() any {
+	return &CollectionReq{AutoID: DisableAutoID}
+}
+
+func newQueryReqV2() any {
+	return &QueryReqV2{
+		Limit:        100,
+		OutputFields: []string{DefaultOutputFields},
+	}
+}
+
+func newCollectionIDReq() any {
+	return &CollectionIDReq{
+		OutputFields: []string{DefaultOutputFields},
+	}
+}
+
+func newSearchReqV2() any {
+	return &SearchReqV2{
+		Limit: 100,
+	}
+}
+
+func newHybridSearchReq() any {
+	return &HybridSearchReq{
+		Limit: 100,
+	}
+}
+
+func newQueryIteratorReqV2() any {
+	return &QueryIteratorReqV2{
+		BatchSize:    100,
+		OutputFields: []string{DefaultOutputFields},
+	}
+}
+
+func newSearchIteratorReqV2() any {
+	return &SearchIteratorReqV2{
+		BatchSize: 100,
+	}
+}
+
 func wrapperPost(newReq newReqFunc, v2 handlerFuncV2) gin.HandlerFunc {
 	return func(gCtx *gin.Context) {
 		req := newReq()
@@ -499,6 +584,9 @@ func wrapperProxyWithLimit(ctx context.Context, ginCtx *gin.Context, req any, ch
 		_, err := CheckLimiter(ctx, req, pxy)
 		if err != nil {
 			log.Warn("high level restful api, fail to check limiter", zap.Error(err), zap.String("method", fullMethod))
+			if retryAfter, ok := proxy.GetRetryAfter(err); ok {
+				ginCtx.Header(proxy.RetryAfterHeader, strconv.Itoa(proxy.RetryAfterSeconds(retryAfter)))
+			}
 			hookutil.GetExtension().ReportRefused(ctx, req, WrapErrorToResponse(merr.ErrHTTPRateLimit), nil, ginCtx.FullPath())
 			HTTPAbortReturn(ginCtx, http.StatusOK, gin.H{
 				HTTPReturnCode:    merr.Code(merr.ErrHTTPRateLimit),
@@ -522,10 +610,12 @@ func wrapperProxyWithLimit(ctx context.Context, ginCtx *gin.Context, req any, ch
 			return handler(ctx, req)
 		})
 	}
+	ctx = withPriorityClass(ctx, ginCtx, req)
 	response, err := proxy.HookInterceptor(context.WithValue(ctx, hook.GinParamsKey, ginCtx.Keys), req, username.(string), fullMethod, forwardHandler)
 	if err == nil {
 		status, ok := requestutil.GetStatusFromResponse(response)
 		if ok {
+			ginCtx.Set(ContextResponseStatus, status)
 			err = merr.Error(status)
 		}
 	}
@@ -539,6 +629,15 @@ func wrapperProxyWithLimit(ctx context.Context, ginCtx *gin.Context, req any, ch
 	return response, err
 }
 
+// withPriorityClass passes the priority class of the request to the query nodes like proxy.PriorityClassInterceptor,
+// the class may be carried in the Priority-Class header.
+func withPriorityClass(ctx context.Context, ginCtx *gin.Context, req any) context.Context {
+	if class := ginCtx.GetHeader(HTTPHeaderPriorityClass); class != "" {
+		ctx = contextutil.AppendToIncomingContext(ctx, util.HeaderPriorityClass, class)
+	}
+	return contextutil.PriorityClassHeader.With(ctx, proxy.ResolvePriorityClass(ctx, req))
+}
+
 func (h *HandlersV2) hasCollection(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
 	getter, _ := anyReq.(requestutil.CollectionNameGetter)
 	collectionName := getter.GetCollectionName()
@@ -0,0 +1,129 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/internal/proxy"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+const (
+	queryIteratorKind  = "query"
+	searchIteratorKind = "search"
+)
+
+// iteratorCursor is the state of a restful iterator, which is carried between the pages by an opaque signed token.
+type iteratorCursor struct {
+	Kind           string `json:"kind"`
+	DbName         string `json:"db"`
+	CollectionName string `json:"collection"`
+	// the digest of the iterated request, the following pages must be requested with the same parameters.
+	Fingerprint string `json:"fingerprint"`
+	// the guarantee timestamp of the iteration, all pages are read at the same timestamp.
+	SessionTs uint64 `json:"ts"`
+	// the primary key of the last returned entity of the query iterator.
+	LastPK *string `json:"lastPk,omitempty"`
+	// the token and the last distance bound of the search iterator.
+	SearchID  string  `json:"searchId,omitempty"`
+	LastBound float32 `json:"lastBound,omitempty"`
+	// the number of the entities left to iterate, negative means unlimited.
+	Remaining int64 `json:"remaining"`
+	// in unix seconds
+	ExpireAt int64 `json:"expireAt"`
+}
+
+// generatedCursorSecret signs the cursors if no secret is configured, it's generated at startup,
+// so the cursors can only be resumed on the proxy that issued them and before it restarts.
+var generatedCursorSecret = func() []byte {
+	secret := make([]byte, sha256.Size)
+	rand.Read(secret)
+	return secret
+}()
+
+func cursorSecret() []byte {
+	if secret := proxy.Params.HTTPCfg.IteratorCursorSecret.GetValue(); secret != "" {
+		return []byte(secret)
+	}
+	return generatedCursorSecret
+}
+
+func signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, cursorSecret())
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// encodeCursor signs the cursor into an opaque token, the expiration of the cursor is renewed.
+func encodeCursor(cursor *iteratorCursor) (string, error) {
+	cursor.ExpireAt = time.Now().Add(proxy.Params.HTTPCfg.IteratorCursorTTL.GetAsDuration(time.Second)).Unix()
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(signCursor(payload)), nil
+}
+
+// decodeCursor verifies the signature and the expiration of the token and decodes the cursor.
+func decodeCursor(token string) (*iteratorCursor, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid cursor format")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid cursor format")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signCursor(payload)) {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid cursor signature")
+	}
+	cursor := &iteratorCursor{}
+	if err := json.Unmarshal(payload, cursor); err != nil {
+		return nil, merr.WrapErrParameterInvalidMsg("invalid cursor format")
+	}
+	if time.Now().Unix() > cursor.ExpireAt {
+		return nil, merr.WrapErrParameterInvalidMsg("cursor expired, please restart the iteration")
+	}
+	return cursor, nil
+}
+
+// checkCursor checks whether the cursor is issued by the same kind of iteration on the same request.
+func (cursor *iteratorCursor) checkCursor(kind, dbName, collectionName, fingerprint string) error {
+	if cursor.Kind != kind || cursor.DbName != dbName || cursor.CollectionName != collectionName || cursor.Fingerprint != fingerprint {
+		return merr.WrapErrParameterInvalidMsg("the cursor does not belong to this %s iteration, the parameters must not change between the pages", kind)
+	}
+	return nil
+}
+
+// requestFingerprint returns the digest of the iterated request, the cursor and the batch size are excluded by the caller.
+func requestFingerprint(req any) (string, error) {
+	bs, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(bs)
+	return hex.EncodeToString(digest[:16]), nil
+}
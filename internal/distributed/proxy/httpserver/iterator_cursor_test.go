@@ -0,0 +1,105 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestIteratorCursor(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+
+	lastPK := "10"
+	cursor := &iteratorCursor{
+		Kind:           queryIteratorKind,
+		DbName:         "default",
+		CollectionName: "book",
+		Fingerprint:    "fp",
+		SessionTs:      100,
+		LastPK:         &lastPK,
+		Remaining:      -1,
+	}
+	token, err := encodeCursor(cursor)
+	require.NoError(t, err)
+
+	decoded, err := decodeCursor(token)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+	assert.NoError(t, decoded.checkCursor(queryIteratorKind, "default", "book", "fp"))
+	assert.ErrorIs(t, decoded.checkCursor(searchIteratorKind, "default", "book", "fp"), merr.ErrParameterInvalid)
+	assert.ErrorIs(t, decoded.checkCursor(queryIteratorKind, "default", "book", "other"), merr.ErrParameterInvalid)
+
+	// tampered or malformed cursor
+	_, err = decodeCursor("invalid")
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	_, err = decodeCursor("e30." + token[len(token)-43:])
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	_, err = decodeCursor(token[:len(token)-2] + "AA")
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	// the cursor signed by another secret
+	params.Save(params.HTTPCfg.IteratorCursorSecret.Key, "another")
+	_, err = decodeCursor(token)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	params.Reset(params.HTTPCfg.IteratorCursorSecret.Key)
+
+	// the cursor forged with the etcd root path is not accepted without a configured secret
+	params.Save(params.HTTPCfg.IteratorCursorSecret.Key, params.EtcdCfg.RootPath.GetValue())
+	token, err = encodeCursor(cursor)
+	require.NoError(t, err)
+	params.Reset(params.HTTPCfg.IteratorCursorSecret.Key)
+	_, err = decodeCursor(token)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	// expired cursor
+	params.Save(params.HTTPCfg.IteratorCursorTTL.Key, "-10")
+	token, err = encodeCursor(cursor)
+	require.NoError(t, err)
+	params.Reset(params.HTTPCfg.IteratorCursorTTL.Key)
+	_, err = decodeCursor(token)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestIteratorCursorAdvance(t *testing.T) {
+	paramtable.Init()
+
+	cursor := &iteratorCursor{Remaining: 5}
+	assert.EqualValues(t, 3, cursor.pageSize(3))
+	next, err := cursor.advance(3, 3, false)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, next)
+	assert.EqualValues(t, 2, cursor.pageSize(3))
+	next, err = cursor.advance(2, 2, false)
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+
+	cursor = &iteratorCursor{Remaining: -1}
+	assert.EqualValues(t, 3, cursor.pageSize(3))
+	next, err = cursor.advance(2, 3, false)
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+	next, err = cursor.advance(3, 3, true)
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+}
@@ -0,0 +1,379 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/proxy"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// composeIteratorExpr combines the filter of the request with the range of the primary keys after the last page,
+// the last primary key decoded from the cursor is checked against the type of the primary key field.
+func composeIteratorExpr(filter string, pkField *schemapb.FieldSchema, lastPK *string) (string, error) {
+	if lastPK == nil {
+		return filter, nil
+	}
+	var pkFilter string
+	switch pkField.GetDataType() {
+	case schemapb.DataType_Int64:
+		pk, err := strconv.ParseInt(*lastPK, 10, 64)
+		if err != nil {
+			return "", merr.WrapErrParameterInvalidMsg("invalid cursor, the last primary key %q is not an int64", *lastPK)
+		}
+		pkFilter = fmt.Sprintf("%s > %d", pkField.GetName(), pk)
+	case schemapb.DataType_VarChar:
+		pkFilter = fmt.Sprintf("%s > %s", pkField.GetName(), strconv.Quote(*lastPK))
+	default:
+		return "", merr.WrapErrParameterInvalidMsg("unsupported primary key type: %s", pkField.GetDataType().String())
+	}
+	if strings.TrimSpace(filter) == "" {
+		return pkFilter, nil
+	}
+	return fmt.Sprintf("(%s) and %s", filter, pkFilter), nil
+}
+
+// iteratorOutputFields makes sure the primary key is returned, which is required to locate the next page.
+func iteratorOutputFields(outputFields []string, pkName string) []string {
+	if len(outputFields) == 0 {
+		return outputFields
+	}
+	for _, field := range outputFields {
+		if field == pkName || field == DefaultOutputFields {
+			return outputFields
+		}
+	}
+	return append(outputFields, pkName)
+}
+
+// lastPrimaryKey returns the number of the returned entities and the primary key of the last one.
+func lastPrimaryKey(fieldsData []*schemapb.FieldData, pkField *schemapb.FieldSchema) (int, string) {
+	for _, fieldData := range fieldsData {
+		if fieldData.GetFieldName() != pkField.GetName() {
+			continue
+		}
+		switch pkField.GetDataType() {
+		case schemapb.DataType_Int64:
+			data := fieldData.GetScalars().GetLongData().GetData()
+			if len(data) > 0 {
+				return len(data), strconv.FormatInt(data[len(data)-1], 10)
+			}
+		case schemapb.DataType_VarChar:
+			data := fieldData.GetScalars().GetStringData().GetData()
+			if len(data) > 0 {
+				return len(data), data[len(data)-1]
+			}
+		}
+	}
+	return 0, ""
+}
+
+// loadIteratorCursor decodes the cursor carried by the request, or starts a new iteration if there is no cursor.
+func loadIteratorCursor(c *gin.Context, kind, dbName, collectionName, token, fingerprint string, limit int64) (*iteratorCursor, error) {
+	if token == "" {
+		remaining := limit
+		if remaining <= 0 {
+			remaining = -1
+		}
+		return &iteratorCursor{
+			Kind:           kind,
+			DbName:         dbName,
+			CollectionName: collectionName,
+			Fingerprint:    fingerprint,
+			Remaining:      remaining,
+		}, nil
+	}
+	cursor, err := decodeCursor(token)
+	if err == nil {
+		err = cursor.checkCursor(kind, dbName, collectionName, fingerprint)
+	}
+	if err != nil {
+		HTTPAbortReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode:    merr.Code(err),
+			HTTPReturnMessage: err.Error(),
+		})
+		return nil, err
+	}
+	return cursor, nil
+}
+
+// pageSize returns the number of the entities to fetch in the next page.
+func (cursor *iteratorCursor) pageSize(batchSize int64) int64 {
+	if cursor.Remaining >= 0 && cursor.Remaining < batchSize {
+		return cursor.Remaining
+	}
+	return batchSize
+}
+
+// advance consumes the returned entities and returns the cursor of the next page, empty if the iteration is done.
+func (cursor *iteratorCursor) advance(count, pageSize int64, exhausted bool) (string, error) {
+	if cursor.Remaining > 0 {
+		cursor.Remaining = max(cursor.Remaining-count, 0)
+	}
+	if exhausted || count == 0 || count < pageSize || cursor.Remaining == 0 {
+		return "", nil
+	}
+	return encodeCursor(cursor)
+}
+
+func checkIteratorBatchSize(c *gin.Context, batchSize int64) error {
+	if batchSize > 0 {
+		return nil
+	}
+	err := merr.WrapErrParameterInvalid("positive integer", batchSize, "invalid batchSize")
+	HTTPAbortReturn(c, http.StatusOK, gin.H{
+		HTTPReturnCode:    merr.Code(err),
+		HTTPReturnMessage: err.Error(),
+	})
+	return err
+}
+
+func (h *HandlersV2) queryIterator(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*QueryIteratorReqV2)
+	if err := checkIteratorBatchSize(c, httpReq.BatchSize); err != nil {
+		return nil, err
+	}
+	params := *httpReq
+	params.BatchSize, params.Limit, params.Cursor = 0, 0, ""
+	fingerprint, err := requestFingerprint(&params)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := loadIteratorCursor(c, queryIteratorKind, dbName, httpReq.CollectionName, httpReq.Cursor, fingerprint, httpReq.Limit)
+	if err != nil {
+		return nil, err
+	}
+	collSchema, err := h.GetCollectionSchema(ctx, c, dbName, httpReq.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	pkField, ok := getPrimaryField(collSchema)
+	if !ok {
+		HTTPAbortReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode:    merr.Code(merr.ErrParameterInvalid),
+			HTTPReturnMessage: "collection has no primary key field",
+		})
+		return nil, merr.ErrParameterInvalid
+	}
+	expr, err := composeIteratorExpr(httpReq.Filter, pkField, cursor.LastPK)
+	if err != nil {
+		HTTPAbortReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode:    merr.Code(err),
+			HTTPReturnMessage: err.Error(),
+		})
+		return nil, err
+	}
+
+	pageSize := cursor.pageSize(httpReq.BatchSize)
+	req := &milvuspb.QueryRequest{
+		DbName:             dbName,
+		CollectionName:     httpReq.CollectionName,
+		Expr:               expr,
+		OutputFields:       iteratorOutputFields(httpReq.OutputFields, pkField.GetName()),
+		PartitionNames:     httpReq.PartitionNames,
+		GuaranteeTimestamp: cursor.SessionTs,
+		QueryParams: []*commonpb.KeyValuePair{
+			{Key: proxy.IteratorField, Value: "true"},
+			{Key: proxy.LimitKey, Value: strconv.FormatInt(pageSize, 10)},
+		},
+	}
+	req.ConsistencyLevel, req.UseDefaultConsistency, err = convertConsistencyLevel(httpReq.ConsistencyLevel)
+	if err != nil {
+		log.Ctx(ctx).Warn("high level restful api, query iterator with consistency_level invalid", zap.Error(err))
+		HTTPAbortReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode:    merr.Code(err),
+			HTTPReturnMessage: "consistencyLevel can only be [Strong, Session, Bounded, Eventually, Customized], default: Bounded, err:" + err.Error(),
+		})
+		return nil, err
+	}
+	req.ExprTemplateValues = generateExpressionTemplate(httpReq.ExprParams)
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/Query", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.Query(reqCtx, req.(*milvuspb.QueryRequest))
+	})
+	if err == nil {
+		queryResp := resp.(*milvuspb.QueryResults)
+		allowJS, _ := strconv.ParseBool(c.Request.Header.Get(HTTPHeaderAllowInt64))
+		outputData, err := buildQueryResp(int64(0), queryResp.OutputFields, queryResp.FieldsData, nil, nil, allowJS, collSchema)
+		if err != nil {
+			log.Ctx(ctx).Warn("high level restful api, fail to deal with query iterator result", zap.Any("response", resp), zap.Error(err))
+			HTTPReturn(c, http.StatusOK, gin.H{
+				HTTPReturnCode:    merr.Code(merr.ErrInvalidSearchResult),
+				HTTPReturnMessage: merr.ErrInvalidSearchResult.Error() + ", error: " + err.Error(),
+			})
+			return resp, err
+		}
+		if cursor.SessionTs == 0 {
+			cursor.SessionTs = queryResp.GetSessionTs()
+		}
+		count, lastPK := lastPrimaryKey(queryResp.GetFieldsData(), pkField)
+		cursor.LastPK = &lastPK
+		nextCursor, err := cursor.advance(int64(count), pageSize, false)
+		if err != nil {
+			return resp, err
+		}
+		HTTPReturnStream(c, http.StatusOK, gin.H{
+			HTTPReturnCode:   merr.Code(nil),
+			HTTPReturnData:   outputData,
+			HTTPReturnCost:   proxy.GetCostValue(queryResp.GetStatus()),
+			HTTPReturnCursor: nextCursor,
+		})
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) searchIterator(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*SearchIteratorReqV2)
+	if err := checkIteratorBatchSize(c, httpReq.BatchSize); err != nil {
+		return nil, err
+	}
+	if len(httpReq.Data) != 1 {
+		err := merr.WrapErrParameterInvalid("1", len(httpReq.Data), "search iterator only supports a single query vector")
+		HTTPAbortReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode:    merr.Code(err),
+			HTTPReturnMessage: err.Error(),
+		})
+		return nil, err
+	}
+	params := *httpReq
+	params.BatchSize, params.Limit, params.Cursor = 0, 0, ""
+	fingerprint, err := requestFingerprint(&params)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := loadIteratorCursor(c, searchIteratorKind, dbName, httpReq.CollectionName, httpReq.Cursor, fingerprint, httpReq.Limit)
+	if err != nil {
+		return nil, err
+	}
+	collSchema, err := h.GetCollectionSchema(ctx, c, dbName, httpReq.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	searchParams, err := generateSearchParams(httpReq.SearchParams)
+	if err != nil {
+		log.Ctx(ctx).Warn("high level restful api, generate SearchParams failed", zap.Error(err))
+		HTTPAbortReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode:    merr.Code(err),
+			HTTPReturnMessage: err.Error(),
+		})
+		return nil, err
+	}
+	pageSize := cursor.pageSize(httpReq.BatchSize)
+	searchParams = append(searchParams,
+		&commonpb.KeyValuePair{Key: common.TopKKey, Value: strconv.FormatInt(pageSize, 10)},
+		&commonpb.KeyValuePair{Key: proxy.AnnsFieldKey, Value: httpReq.AnnsField},
+		&commonpb.KeyValuePair{Key: proxy.IteratorField, Value: "true"},
+		&commonpb.KeyValuePair{Key: proxy.SearchIterV2Key, Value: "true"},
+		&commonpb.KeyValuePair{Key: proxy.SearchIterBatchSizeKey, Value: strconv.FormatInt(pageSize, 10)},
+	)
+	if cursor.SearchID != "" {
+		searchParams = append(searchParams,
+			&commonpb.KeyValuePair{Key: proxy.SearchIterIdKey, Value: cursor.SearchID},
+			&commonpb.KeyValuePair{Key: proxy.SearchIterLastBoundKey, Value: fmt.Sprintf("%v", cursor.LastBound)},
+		)
+	}
+
+	body, _ := c.Get(gin.BodyBytesKey)
+	placeholderGroup, err := generatePlaceholderGroup(ctx, string(body.([]byte)), collSchema, httpReq.AnnsField)
+	if err != nil {
+		log.Ctx(ctx).Warn("high level restful api, search iterator with vector invalid", zap.Error(err))
+		HTTPAbortReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode:    merr.Code(merr.ErrIncorrectParameterFormat),
+			HTTPReturnMessage: merr.ErrIncorrectParameterFormat.Error() + ", error: " + err.Error(),
+		})
+		return nil, err
+	}
+	req := &milvuspb.SearchRequest{
+		DbName:             dbName,
+		CollectionName:     httpReq.CollectionName,
+		Dsl:                httpReq.Filter,
+		DslType:            commonpb.DslType_BoolExprV1,
+		OutputFields:       httpReq.OutputFields,
+		PartitionNames:     httpReq.PartitionNames,
+		SearchParams:       searchParams,
+		GuaranteeTimestamp: cursor.SessionTs,
+		SearchInput: &milvuspb.SearchRequest_PlaceholderGroup{
+			PlaceholderGroup: placeholderGroup,
+		},
+	}
+	req.ConsistencyLevel, req.UseDefaultConsistency, err = convertConsistencyLevel(httpReq.ConsistencyLevel)
+	if err != nil {
+		log.Ctx(ctx).Warn("high level restful api, search iterator with consistency_level invalid", zap.Error(err))
+		HTTPAbortReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode:    merr.Code(err),
+			HTTPReturnMessage: "consistencyLevel can only be [Strong, Session, Bounded, Eventually, Customized], default: Bounded, err:" + err.Error(),
+		})
+		return nil, err
+	}
+	req.ExprTemplateValues = generateExpressionTemplate(httpReq.ExprParams)
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/Search", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.Search(reqCtx, req.(*milvuspb.SearchRequest))
+	})
+	if err == nil {
+		searchResp := resp.(*milvuspb.SearchResults)
+		iteratorInfo := searchResp.GetResults().GetSearchIteratorV2Results()
+		count := typeutil.GetSizeOfIDs(searchResp.GetResults().GetIds())
+		outputData := []map[string]interface{}{}
+		if count > 0 {
+			allowJS, _ := strconv.ParseBool(c.Request.Header.Get(HTTPHeaderAllowInt64))
+			outputData, err = buildQueryResp(0, searchResp.Results.OutputFields, searchResp.Results.FieldsData, searchResp.Results.Ids, searchResp.Results.Scores, allowJS, collSchema)
+			if err != nil {
+				log.Ctx(ctx).Warn("high level restful api, fail to deal with search iterator result", zap.Any("result", searchResp.Results), zap.Error(err))
+				HTTPReturn(c, http.StatusOK, gin.H{
+					HTTPReturnCode:    merr.Code(merr.ErrInvalidSearchResult),
+					HTTPReturnMessage: merr.ErrInvalidSearchResult.Error() + ", error: " + err.Error(),
+				})
+				return resp, err
+			}
+		}
+		if cursor.SessionTs == 0 {
+			cursor.SessionTs = searchResp.GetSessionTs()
+		}
+		cursor.SearchID = iteratorInfo.GetToken()
+		cursor.LastBound = iteratorInfo.GetLastBound()
+		// the search iterator may return less than a batch before the end, it's done only if nothing returned.
+		nextCursor, err := cursor.advance(int64(count), 0, iteratorInfo.GetToken() == "")
+		if err != nil {
+			return resp, err
+		}
+		HTTPReturnStream(c, http.StatusOK, gin.H{
+			HTTPReturnCode:   merr.Code(nil),
+			HTTPReturnData:   outputData,
+			HTTPReturnCost:   proxy.GetCostValue(searchResp.GetStatus()),
+			HTTPReturnCursor: nextCursor,
+		})
+	}
+	return resp, err
+}
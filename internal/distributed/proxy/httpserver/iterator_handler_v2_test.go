@@ -0,0 +1,208 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proxy"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+type iteratorReturn struct {
+	Code    int32                    `json:"code"`
+	Message string                   `json:"message"`
+	Data    []map[string]interface{} `json:"data"`
+	Cursor  string                   `json:"cursor"`
+}
+
+func postIterator(t *testing.T, testEngine *gin.Engine, path string, body string) *iteratorReturn {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader([]byte(body)))
+	w := httptest.NewRecorder()
+	testEngine.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	ret := &iteratorReturn{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), ret))
+	return ret
+}
+
+func TestComposeIteratorExpr(t *testing.T) {
+	int64PK := &schemapb.FieldSchema{Name: "id", DataType: schemapb.DataType_Int64}
+	varcharPK := &schemapb.FieldSchema{Name: "id", DataType: schemapb.DataType_VarChar}
+	composeExpr := func(filter string, pkField *schemapb.FieldSchema, lastPK *string) string {
+		expr, err := composeIteratorExpr(filter, pkField, lastPK)
+		require.NoError(t, err)
+		return expr
+	}
+	lastPK := `a"b`
+	assert.Equal(t, "age > 1", composeExpr("age > 1", int64PK, nil))
+	assert.Equal(t, "id > 10", composeExpr(" ", int64PK, &[]string{"10"}[0]))
+	assert.Equal(t, "(age > 1) and id > 10", composeExpr("age > 1", int64PK, &[]string{"10"}[0]))
+	assert.Equal(t, `(age > 1) and id > "a\"b"`, composeExpr("age > 1", varcharPK, &lastPK))
+	assert.Equal(t, `id > "10 or true"`, composeExpr("", varcharPK, &[]string{"10 or true"}[0]))
+
+	// the last primary key must match the type of the primary key field
+	_, err := composeIteratorExpr("", int64PK, &[]string{"10 or true"}[0])
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	_, err = composeIteratorExpr("", &schemapb.FieldSchema{Name: "id", DataType: schemapb.DataType_Float}, &[]string{"1"}[0])
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	assert.Equal(t, []string{"*"}, iteratorOutputFields([]string{"*"}, "id"))
+	assert.Equal(t, []string{"age", "id"}, iteratorOutputFields([]string{"age"}, "id"))
+	assert.Empty(t, iteratorOutputFields(nil, "id"))
+}
+
+func TestQueryIteratorV2(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key, "false")
+	defer paramtable.Get().Reset(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key)
+
+	mp := mocks.NewMockProxy(t)
+	mp.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+		CollectionName: DefaultCollectionName,
+		Schema:         generateCollectionSchema(schemapb.DataType_Int64, false, true),
+		ShardsNum:      ShardNumDefault,
+		Status:         &StatusSuccess,
+	}, nil)
+	mp.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *milvuspb.QueryRequest) (*milvuspb.QueryResults, error) {
+		isIterator, _ := funcutil.GetAttrByKeyFromRepeatedKV(proxy.IteratorField, req.GetQueryParams())
+		assert.Equal(t, "true", isIterator)
+		limit, _ := funcutil.GetAttrByKeyFromRepeatedKV(proxy.LimitKey, req.GetQueryParams())
+		assert.Equal(t, "3", limit)
+		if req.GetGuaranteeTimestamp() == 0 {
+			assert.Equal(t, "word_count > 0", req.GetExpr())
+			return &milvuspb.QueryResults{
+				Status:       merr.Success(),
+				OutputFields: []string{FieldBookID, FieldWordCount, FieldBookIntro},
+				FieldsData:   generateFieldData(),
+				SessionTs:    100,
+			}, nil
+		}
+		assert.EqualValues(t, 100, req.GetGuaranteeTimestamp())
+		assert.Equal(t, "(word_count > 0) and book_id > 3", req.GetExpr())
+		return &milvuspb.QueryResults{Status: merr.Success()}, nil
+	}).Times(2)
+
+	testEngine := initHTTPServerV2(mp, false)
+	path := versionalV2(EntityCategory, QueryIteratorAction)
+
+	first := postIterator(t, testEngine, path, `{"collectionName": "book", "filter": "word_count > 0", "batchSize": 3}`)
+	assert.EqualValues(t, 0, first.Code)
+	assert.Len(t, first.Data, 3)
+	assert.NotEmpty(t, first.Cursor)
+
+	second := postIterator(t, testEngine, path, `{"collectionName": "book", "filter": "word_count > 0", "batchSize": 3, "cursor": "`+first.Cursor+`"}`)
+	assert.EqualValues(t, 0, second.Code)
+	assert.Empty(t, second.Data)
+	assert.Empty(t, second.Cursor)
+
+	// the cursor can't be used by another iteration
+	ret := postIterator(t, testEngine, path, `{"collectionName": "book", "filter": "word_count > 1", "batchSize": 3, "cursor": "`+first.Cursor+`"}`)
+	assert.Equal(t, merr.Code(merr.ErrParameterInvalid), ret.Code)
+	ret = postIterator(t, testEngine, path, `{"collectionName": "book", "batchSize": 3, "cursor": "invalid"}`)
+	assert.Equal(t, merr.Code(merr.ErrParameterInvalid), ret.Code)
+	ret = postIterator(t, testEngine, path, `{"collectionName": "book", "batchSize": 0}`)
+	assert.Equal(t, merr.Code(merr.ErrParameterInvalid), ret.Code)
+}
+
+func TestSearchIteratorV2(t *testing.T) {
+	paramtable.Init()
+	paramtable.Get().Save(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key, "false")
+	defer paramtable.Get().Reset(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key)
+
+	mp := mocks.NewMockProxy(t)
+	mp.EXPECT().DescribeCollection(mock.Anything, mock.Anything).Return(&milvuspb.DescribeCollectionResponse{
+		CollectionName: DefaultCollectionName,
+		Schema:         generateCollectionSchema(schemapb.DataType_Int64, false, true),
+		ShardsNum:      ShardNumDefault,
+		Status:         &StatusSuccess,
+	}, nil)
+	token := "6f1ed002-ab5f-4b5d-9f6a-1c2b3c4d5e6f"
+	mp.EXPECT().Search(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *milvuspb.SearchRequest) (*milvuspb.SearchResults, error) {
+		isIteratorV2, _ := funcutil.GetAttrByKeyFromRepeatedKV(proxy.SearchIterV2Key, req.GetSearchParams())
+		assert.Equal(t, "true", isIteratorV2)
+		batchSize, _ := funcutil.GetAttrByKeyFromRepeatedKV(proxy.SearchIterBatchSizeKey, req.GetSearchParams())
+		if req.GetGuaranteeTimestamp() == 0 {
+			assert.Equal(t, "3", batchSize)
+			return &milvuspb.SearchResults{
+				Status: merr.Success(),
+				Results: &schemapb.SearchResultData{
+					TopK:                    3,
+					OutputFields:            []string{FieldWordCount},
+					FieldsData:              generateFieldData(),
+					Ids:                     generateIDs(schemapb.DataType_Int64, 3),
+					Scores:                  DefaultScores,
+					SearchIteratorV2Results: &schemapb.SearchIteratorV2Results{Token: token, LastBound: 0.5},
+				},
+				SessionTs: 100,
+			}, nil
+		}
+		// the last page is limited by the remaining entities.
+		assert.Equal(t, "2", batchSize)
+		assert.EqualValues(t, 100, req.GetGuaranteeTimestamp())
+		searchID, _ := funcutil.GetAttrByKeyFromRepeatedKV(proxy.SearchIterIdKey, req.GetSearchParams())
+		assert.Equal(t, token, searchID)
+		lastBound, _ := funcutil.GetAttrByKeyFromRepeatedKV(proxy.SearchIterLastBoundKey, req.GetSearchParams())
+		assert.Equal(t, "0.5", lastBound)
+		return &milvuspb.SearchResults{
+			Status: merr.Success(),
+			Results: &schemapb.SearchResultData{
+				TopK:                    2,
+				OutputFields:            []string{FieldWordCount},
+				FieldsData:              generateFieldData(),
+				Ids:                     generateIDs(schemapb.DataType_Int64, 3),
+				Scores:                  DefaultScores,
+				SearchIteratorV2Results: &schemapb.SearchIteratorV2Results{Token: token, LastBound: 0.8},
+			},
+		}, nil
+	}).Times(2)
+
+	testEngine := initHTTPServerV2(mp, false)
+	path := versionalV2(EntityCategory, SearchIteratorAction)
+
+	body := `{"collectionName": "book", "data": [[0.1, 0.2]], "batchSize": 3, "limit": 5`
+	first := postIterator(t, testEngine, path, body+`}`)
+	assert.EqualValues(t, 0, first.Code)
+	assert.Len(t, first.Data, 3)
+	assert.NotEmpty(t, first.Cursor)
+
+	second := postIterator(t, testEngine, path, body+`, "cursor": "`+first.Cursor+`"}`)
+	assert.EqualValues(t, 0, second.Code)
+	assert.NotEmpty(t, second.Data)
+	assert.Empty(t, second.Cursor)
+
+	// the cursor can't be used by another query vector
+	ret := postIterator(t, testEngine, path, `{"collectionName": "book", "data": [[0.2, 0.2]], "batchSize": 3, "cursor": "`+first.Cursor+`"}`)
+	assert.Equal(t, merr.Code(merr.ErrParameterInvalid), ret.Code)
+	ret = postIterator(t, testEngine, path, `{"collectionName": "book", "data": [[0.1, 0.2], [0.2, 0.2]], "batchSize": 3}`)
+	assert.Equal(t, merr.Code(merr.ErrParameterInvalid), ret.Code)
+}
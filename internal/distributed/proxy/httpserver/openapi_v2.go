@@ -0,0 +1,256 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+const (
+	openAPIVersion       = "3.0.3"
+	openAPISchemaRefBase = "#/components/schemas/"
+)
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Default              any                       `json:"default,omitempty"`
+}
+
+// openAPIGenerator converts request structs into OpenAPI schemas, named structs are
+// collected as components and referenced by name.
+type openAPIGenerator struct {
+	schemas map[string]*openAPISchema
+}
+
+func newOpenAPIGenerator() *openAPIGenerator {
+	return &openAPIGenerator{schemas: make(map[string]*openAPISchema)}
+}
+
+// openAPIFieldName returns the json name of a struct field, empty if the field is not serialized.
+func openAPIFieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+func (g *openAPIGenerator) schemaOf(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+			g.addFields(schema, t)
+			return schema
+		}
+		if _, ok := g.schemas[t.Name()]; !ok {
+			schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+			// register before walking the fields, so that recursive types terminate
+			g.schemas[t.Name()] = schema
+			g.addFields(schema, t)
+		}
+		return &openAPISchema{Ref: openAPISchemaRefBase + t.Name()}
+	default:
+		// interface{} accepts any json value
+		return &openAPISchema{}
+	}
+}
+
+func (g *openAPIGenerator) addFields(schema *openAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldType := f.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		// embedded structs without a json name are flattened, the same way encoding/json does
+		if f.Anonymous && f.Tag.Get("json") == "" && fieldType.Kind() == reflect.Struct {
+			g.addFields(schema, fieldType)
+			continue
+		}
+		name := openAPIFieldName(f)
+		if name == "" {
+			continue
+		}
+		schema.Properties[name] = g.schemaOf(f.Type)
+		if slices.Contains(strings.Split(f.Tag.Get("binding"), ","), "required") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// requestSchema returns the reference to the request body schema, the non-zero values
+// set by the request constructor are published as defaults.
+func (g *openAPIGenerator) requestSchema(newReq newReqFunc) *openAPISchema {
+	value := reflect.ValueOf(newReq())
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	ref := g.schemaOf(value.Type())
+	schema, ok := g.schemas[value.Type().Name()]
+	if !ok {
+		return ref
+	}
+	for i := 0; i < value.NumField(); i++ {
+		name := openAPIFieldName(value.Type().Field(i))
+		property, ok := schema.Properties[name]
+		if !ok || value.Field(i).IsZero() {
+			continue
+		}
+		property.Default = value.Field(i).Interface()
+	}
+	return ref
+}
+
+func openAPIHeaderParameter(name, description string, schema *openAPISchema) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"in":          "header",
+		"required":    false,
+		"description": description,
+		"schema":      schema,
+	}
+}
+
+// buildOpenAPISpecV2 generates the OpenAPI document of the routes.
+func buildOpenAPISpecV2(routes []routeV2, serverURL string) map[string]any {
+	g := newOpenAPIGenerator()
+	headers := []map[string]any{
+		openAPIHeaderParameter(HTTPHeaderDBName, "database used when dbName is not set in the body", &openAPISchema{Type: "string"}),
+		openAPIHeaderParameter(HTTPHeaderRequestTimeout, "request timeout in seconds", &openAPISchema{Type: "integer", Format: "int64"}),
+		openAPIHeaderParameter(HTTPHeaderAllowInt64, "return int64 values as numbers instead of strings", &openAPISchema{Type: "boolean"}),
+	}
+
+	paths := make(map[string]any)
+	for _, route := range routes {
+		path := route.path
+		tag := strings.Trim(path[:strings.LastIndex(path, "/")], "/")
+		paths[path] = map[string]any{
+			"post": map[string]any{
+				"operationId": lo.CamelCase(path),
+				"summary":     route.summary,
+				"tags":        []string{tag},
+				"parameters":  headers,
+				"requestBody": map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": g.requestSchema(route.newReq)},
+					},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "code is 0 on success, otherwise message describes the error",
+						"content": map[string]any{
+							"application/json": map[string]any{"schema": g.schemaOf(reflect.TypeOf(route.resp))},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	spec := map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]any{
+			"title":   "Milvus RESTful API",
+			"version": common.Version.String(),
+		},
+		"servers": []map[string]any{{"url": serverURL}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "username:password or api key",
+				},
+			},
+		},
+		"security": []map[string]any{{"bearerAuth": []string{}}},
+	}
+	return spec
+}
+
+var (
+	openAPISpecOnce sync.Once
+	openAPISpecData []byte
+	openAPISpecErr  error
+)
+
+// openAPISpec serves the OpenAPI document of RESTful v2, it is generated once from the routes
+// RegisterRoutesToV2 registers.
+func (h *HandlersV2) openAPISpec(c *gin.Context) {
+	openAPISpecOnce.Do(func() {
+		serverURL := strings.TrimSuffix(c.FullPath(), OpenAPIPath)
+		openAPISpecData, openAPISpecErr = json.Marshal(buildOpenAPISpecV2(routesV2, serverURL))
+	})
+	if openAPISpecErr != nil {
+		HTTPAbortReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode:    merr.Code(openAPISpecErr),
+			HTTPReturnMessage: openAPISpecErr.Error(),
+		})
+		return
+	}
+	c.Data(http.StatusOK, binding.MIMEJSON, openAPISpecData)
+}
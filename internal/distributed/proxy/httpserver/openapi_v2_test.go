@@ -0,0 +1,142 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestOpenAPISpecV2Coverage(t *testing.T) {
+	paramtable.Init()
+	engine := gin.New()
+	NewHandlersV2(mocks.NewMockProxy(t)).RegisterRoutesToV2(engine.Group("/v2/vectordb"))
+
+	registered := make([]string, 0)
+	for _, route := range engine.Routes() {
+		if route.Method == http.MethodPost {
+			registered = append(registered, strings.TrimPrefix(route.Path, "/v2/vectordb"))
+		}
+	}
+	documented := lo.Keys(buildOpenAPISpecV2(routesV2, "/v2/vectordb")["paths"].(map[string]any))
+	// every registered route is documented, and the paths are unique
+	assert.ElementsMatch(t, registered, documented)
+	assert.Len(t, documented, len(routesV2))
+}
+
+func TestOpenAPISpecV2Response(t *testing.T) {
+	spec := buildOpenAPISpecV2(routesV2, "/v2/vectordb")
+	paths := spec["paths"].(map[string]any)
+	responseSchema := func(path string) *openAPISchema {
+		post := paths[path].(map[string]any)["post"].(map[string]any)
+		content := post["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)
+		return content["application/json"].(map[string]any)["schema"].(*openAPISchema)
+	}
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]*openAPISchema)
+
+	assert.Equal(t, openAPISchemaRefBase+"HasResp", responseSchema(CollectionCategory+HasAction).Ref)
+	has := schemas["HasResp"]
+	assert.Equal(t, []string{HTTPReturnCode}, has.Required)
+	assert.Equal(t, "integer", has.Properties[HTTPReturnCode].Type)
+	assert.Equal(t, "boolean", has.Properties[HTTPReturnData].Properties[HTTPReturnHas].Type)
+
+	assert.Equal(t, openAPISchemaRefBase+"SearchResp", responseSchema(EntityCategory+SearchAction).Ref)
+	search := schemas["SearchResp"]
+	assert.Equal(t, "array", search.Properties[HTTPReturnData].Type)
+	assert.Equal(t, "object", search.Properties[HTTPReturnData].Items.Type)
+	assert.Equal(t, "int64", search.Properties[HTTPReturnTopks].Items.Format)
+	assert.Equal(t, "float", search.Properties[HTTPReturnRecalls].Items.Format)
+	assert.Equal(t, "double", search.Properties[HTTPReturnCacheHitRatio].Format)
+
+	assert.Equal(t, openAPISchemaRefBase+"IteratorResp", responseSchema(EntityCategory+QueryIteratorAction).Ref)
+	assert.Equal(t, "string", schemas["IteratorResp"].Properties[HTTPReturnCursor].Type)
+
+	assert.Equal(t, openAPISchemaRefBase+"IndexDetail", schemas["IndexDetailResp"].Properties[HTTPReturnData].Items.Ref)
+	assert.Equal(t, openAPISchemaRefBase+"DefaultResp", responseSchema(CollectionCategory+DropAction).Ref)
+}
+
+func TestOpenAPISpecV2Schema(t *testing.T) {
+	g := newOpenAPIGenerator()
+	ref := g.requestSchema(newQueryReqV2)
+	assert.Equal(t, openAPISchemaRefBase+"QueryReqV2", ref.Ref)
+
+	schema := g.schemas["QueryReqV2"]
+	require.NotNil(t, schema)
+	assert.Contains(t, schema.Required, HTTPCollectionName)
+	assert.NotContains(t, schema.Required, "filter")
+	assert.Equal(t, "integer", schema.Properties["limit"].Type)
+	assert.Equal(t, "int32", schema.Properties["limit"].Format)
+	assert.EqualValues(t, 100, schema.Properties["limit"].Default)
+	assert.Equal(t, []string{DefaultOutputFields}, schema.Properties["outputFields"].Default)
+	assert.Equal(t, "array", schema.Properties["outputFields"].Type)
+	assert.Equal(t, "string", schema.Properties["outputFields"].Items.Type)
+	assert.Equal(t, "object", schema.Properties["exprParams"].Type)
+	assert.Nil(t, schema.Properties["filter"].Default)
+
+	// nested named structs are referenced as components
+	g.requestSchema(newHybridSearchReq)
+	hybrid := g.schemas["HybridSearchReq"]
+	require.NotNil(t, hybrid)
+	assert.Equal(t, "array", hybrid.Properties["search"].Type)
+	assert.Equal(t, openAPISchemaRefBase+"SubSearchReq", hybrid.Properties["search"].Items.Ref)
+	assert.Equal(t, openAPISchemaRefBase+"Rand", hybrid.Properties["rerank"].Ref)
+	assert.Contains(t, g.schemas["SubSearchReq"].Required, HTTPRequestData)
+
+	// interface{} fields accept any value
+	g.requestSchema(func() any { return &CollectionIDReq{} })
+	assert.Equal(t, &openAPISchema{}, g.schemas["CollectionIDReq"].Properties["id"])
+}
+
+func TestOpenAPISpecV2Handler(t *testing.T) {
+	paramtable.Init()
+	testEngine := initHTTPServerV2(mocks.NewMockProxy(t), false)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/vectordb"+OpenAPIPath, nil)
+	w := httptest.NewRecorder()
+	testEngine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	spec := map[string]any{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, openAPIVersion, spec["openapi"])
+	assert.Equal(t, "/v2/vectordb", spec["servers"].([]any)[0].(map[string]any)["url"])
+
+	paths := spec["paths"].(map[string]any)
+	search, ok := paths[EntityCategory+SearchAction].(map[string]any)
+	require.True(t, ok)
+	post := search["post"].(map[string]any)
+	assert.Equal(t, "entitiesSearch", post["operationId"])
+	assert.Equal(t, []any{"entities"}, post["tags"])
+	_, ok = paths[OpenAPIPath]
+	assert.False(t, ok)
+
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+	searchReq := schemas["SearchReqV2"].(map[string]any)
+	limit := searchReq["properties"].(map[string]any)["limit"].(map[string]any)
+	assert.EqualValues(t, 100, limit["default"])
+}
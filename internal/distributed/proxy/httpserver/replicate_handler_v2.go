@@ -0,0 +1,97 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+func toPbReplicateConfiguration(req *UpdateReplicateConfigurationReq) *commonpb.ReplicateConfiguration {
+	return &commonpb.ReplicateConfiguration{
+		Clusters: lo.Map(req.GetClusters(), func(cluster *ReplicateCluster, _ int) *commonpb.MilvusCluster {
+			return &commonpb.MilvusCluster{
+				ClusterId: cluster.ClusterID,
+				ConnectionParam: &commonpb.ConnectionParam{
+					Uri:   cluster.URI,
+					Token: cluster.Token,
+				},
+				Pchannels: cluster.Pchannels,
+			}
+		}),
+		CrossClusterTopology: lo.Map(req.GetCrossClusterTopology(), func(topology *ReplicateTopology, _ int) *commonpb.CrossClusterTopology {
+			return &commonpb.CrossClusterTopology{
+				SourceClusterId: topology.SourceClusterID,
+				TargetClusterId: topology.TargetClusterID,
+			}
+		}),
+	}
+}
+
+func (h *HandlersV2) getReplicateConfiguration(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	req := &milvuspb.GetReplicateConfigurationRequest{}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/GetReplicateConfiguration", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.GetReplicateConfiguration(reqCtx, req.(*milvuspb.GetReplicateConfigurationRequest))
+	})
+	if err == nil {
+		config := resp.(*milvuspb.GetReplicateConfigurationResponse).GetConfiguration()
+		// the tokens of the clusters are never returned.
+		clusters := lo.Map(config.GetClusters(), func(cluster *commonpb.MilvusCluster, _ int) gin.H {
+			return gin.H{
+				"clusterId": cluster.GetClusterId(),
+				"uri":       cluster.GetConnectionParam().GetUri(),
+				"pchannels": cluster.GetPchannels(),
+			}
+		})
+		topology := lo.Map(config.GetCrossClusterTopology(), func(topology *commonpb.CrossClusterTopology, _ int) gin.H {
+			return gin.H{
+				"sourceClusterId": topology.GetSourceClusterId(),
+				"targetClusterId": topology.GetTargetClusterId(),
+			}
+		})
+		HTTPReturn(c, http.StatusOK, gin.H{HTTPReturnCode: merr.Code(nil), HTTPReturnData: gin.H{
+			"clusters":             clusters,
+			"crossClusterTopology": topology,
+		}})
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) updateReplicateConfiguration(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*UpdateReplicateConfigurationReq)
+	req := &milvuspb.UpdateReplicateConfigurationRequest{
+		ReplicateConfiguration: toPbReplicateConfiguration(httpReq),
+		ForcePromote:           httpReq.GetForcePromote(),
+	}
+	// the request carries the tokens of the clusters, which must not be logged.
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/UpdateReplicateConfiguration", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.UpdateReplicateConfiguration(reqCtx, req.(*milvuspb.UpdateReplicateConfigurationRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, wrapperReturnDefault())
+	}
+	return resp, err
+}
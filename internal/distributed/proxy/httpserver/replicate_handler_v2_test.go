@@ -0,0 +1,96 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestReplicateHandlerV2(t *testing.T) {
+	// init params
+	paramtable.Init()
+
+	// disable rate limit
+	paramtable.Get().Save(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key, "false")
+	defer paramtable.Get().Reset(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key)
+
+	// mock proxy
+	mockProxy := mocks.NewMockProxy(t)
+	mockProxy.EXPECT().GetReplicateConfiguration(mock.Anything, mock.Anything).Return(&milvuspb.GetReplicateConfigurationResponse{
+		Status: merr.Success(),
+		Configuration: &commonpb.ReplicateConfiguration{
+			Clusters: []*commonpb.MilvusCluster{{
+				ClusterId:       "source",
+				ConnectionParam: &commonpb.ConnectionParam{Uri: "http://source:19530", Token: "secret"},
+				Pchannels:       []string{"dml_0"},
+			}},
+			CrossClusterTopology: []*commonpb.CrossClusterTopology{{SourceClusterId: "source", TargetClusterId: "target"}},
+		},
+	}, nil)
+	mockProxy.EXPECT().UpdateReplicateConfiguration(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, req *milvuspb.UpdateReplicateConfigurationRequest) (*commonpb.Status, error) {
+			assert.True(t, req.GetForcePromote())
+			assert.Equal(t, "secret", req.GetReplicateConfiguration().GetClusters()[0].GetConnectionParam().GetToken())
+			assert.Equal(t, "target", req.GetReplicateConfiguration().GetCrossClusterTopology()[0].GetTargetClusterId())
+			return merr.Success(), nil
+		})
+
+	// setup test server
+	testServer := initHTTPServerV2(mockProxy, false)
+
+	testCases := []requestBodyTestCase{
+		{
+			path:        versionalV2(ReplicateCategory, DescribeAction),
+			requestBody: []byte(`{}`),
+		},
+		{
+			path:        versionalV2(ReplicateCategory, AlterAction),
+			requestBody: []byte(`{}`),
+			errCode:     1802,
+			errMsg:      "missing required parameters, error: Key: 'UpdateReplicateConfigurationReq.Clusters' Error:Field validation for 'Clusters' failed on the 'required' tag",
+		},
+		{
+			path:        versionalV2(ReplicateCategory, AlterAction),
+			requestBody: []byte(`{"clusters":[{"clusterId":"source","uri":"http://source:19530","token":"secret","pchannels":["dml_0"]}],"crossClusterTopology":[{"sourceClusterId":"source","targetClusterId":"target"}],"forcePromote":true}`),
+		},
+	}
+
+	// verify test case
+	validateRequestBodyTestCases(t, testServer, testCases, false)
+
+	t.Run("token is not returned", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, versionalV2(ReplicateCategory, DescribeAction), bytes.NewReader([]byte(`{}`)))
+		w := httptest.NewRecorder()
+		testServer.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "http://source:19530")
+		assert.NotContains(t, w.Body.String(), "secret")
+	})
+}
@@ -360,6 +360,47 @@ type SearchReqV2 struct {
 func (req *SearchReqV2) GetDbName() string         { return req.DbName }
 func (req *SearchReqV2) GetCollectionName() string { return req.CollectionName }
 
+type QueryIteratorReqV2 struct {
+	DbName           string                 `json:"dbName"`
+	CollectionName   string                 `json:"collectionName" binding:"required"`
+	PartitionNames   []string               `json:"partitionNames"`
+	OutputFields     []string               `json:"outputFields"`
+	Filter           string                 `json:"filter"`
+	ExprParams       map[string]interface{} `json:"exprParams"`
+	ConsistencyLevel string                 `json:"consistencyLevel"`
+	// the number of the entities returned per page
+	BatchSize int64 `json:"batchSize"`
+	// the total number of the entities to iterate, zero means unlimited
+	Limit int64 `json:"limit"`
+	// the cursor returned by the previous page, empty to start a new iteration
+	Cursor string `json:"cursor"`
+}
+
+func (req *QueryIteratorReqV2) GetDbName() string         { return req.DbName }
+func (req *QueryIteratorReqV2) GetCollectionName() string { return req.CollectionName }
+
+type SearchIteratorReqV2 struct {
+	DbName           string                 `json:"dbName"`
+	CollectionName   string                 `json:"collectionName" binding:"required"`
+	Data             []interface{}          `json:"data" binding:"required"`
+	AnnsField        string                 `json:"annsField"`
+	PartitionNames   []string               `json:"partitionNames"`
+	Filter           string                 `json:"filter"`
+	OutputFields     []string               `json:"outputFields"`
+	SearchParams     map[string]interface{} `json:"searchParams"`
+	ConsistencyLevel string                 `json:"consistencyLevel"`
+	ExprParams       map[string]interface{} `json:"exprParams"`
+	// the number of the entities returned per page
+	BatchSize int64 `json:"batchSize"`
+	// the total number of the entities to iterate, zero means unlimited
+	Limit int64 `json:"limit"`
+	// the cursor returned by the previous page, empty to start a new iteration
+	Cursor string `json:"cursor"`
+}
+
+func (req *SearchIteratorReqV2) GetDbName() string         { return req.DbName }
+func (req *SearchIteratorReqV2) GetCollectionName() string { return req.CollectionName }
+
 type Rand struct {
 	Strategy string                 `json:"strategy"`
 	Params   map[string]interface{} `json:"params"`
@@ -847,3 +888,100 @@ type RunAnalyzerReq struct {
 
 func (req *RunAnalyzerReq) GetDbName() string         { return req.DbName }
 func (req *RunAnalyzerReq) GetCollectionName() string { return req.CollectionName }
+
+type SnapshotReq struct {
+	DbName         string `json:"dbName"`
+	CollectionName string `json:"collectionName"`
+	SnapshotName   string `json:"snapshotName" binding:"required"`
+}
+
+func (req *SnapshotReq) GetDbName() string         { return req.DbName }
+func (req *SnapshotReq) GetCollectionName() string { return req.CollectionName }
+func (req *SnapshotReq) GetSnapshotName() string   { return req.SnapshotName }
+
+type CreateSnapshotReq struct {
+	DbName         string `json:"dbName"`
+	CollectionName string `json:"collectionName" binding:"required"`
+	SnapshotName   string `json:"snapshotName" binding:"required"`
+	Description    string `json:"description"`
+}
+
+func (req *CreateSnapshotReq) GetDbName() string         { return req.DbName }
+func (req *CreateSnapshotReq) GetCollectionName() string { return req.CollectionName }
+func (req *CreateSnapshotReq) GetSnapshotName() string   { return req.SnapshotName }
+func (req *CreateSnapshotReq) GetDescription() string    { return req.Description }
+
+type RestoreSnapshotReq struct {
+	DbName               string `json:"dbName"`
+	CollectionName       string `json:"collectionName"`
+	SnapshotName         string `json:"snapshotName" binding:"required"`
+	TargetDbName         string `json:"targetDbName"`
+	TargetCollectionName string `json:"targetCollectionName" binding:"required"`
+}
+
+func (req *RestoreSnapshotReq) GetDbName() string               { return req.DbName }
+func (req *RestoreSnapshotReq) GetCollectionName() string       { return req.CollectionName }
+func (req *RestoreSnapshotReq) GetSnapshotName() string         { return req.SnapshotName }
+func (req *RestoreSnapshotReq) GetTargetDbName() string         { return req.TargetDbName }
+func (req *RestoreSnapshotReq) GetTargetCollectionName() string { return req.TargetCollectionName }
+
+type PinSnapshotReq struct {
+	DbName         string `json:"dbName"`
+	CollectionName string `json:"collectionName"`
+	SnapshotName   string `json:"snapshotName" binding:"required"`
+	TTLSeconds     int64  `json:"ttlSeconds"`
+}
+
+func (req *PinSnapshotReq) GetDbName() string         { return req.DbName }
+func (req *PinSnapshotReq) GetCollectionName() string { return req.CollectionName }
+func (req *PinSnapshotReq) GetSnapshotName() string   { return req.SnapshotName }
+func (req *PinSnapshotReq) GetTTLSeconds() int64      { return req.TTLSeconds }
+
+type UnpinSnapshotReq struct {
+	PinID int64 `json:"pinId" binding:"required"`
+}
+
+func (req *UnpinSnapshotReq) GetPinID() int64 { return req.PinID }
+
+// Int64JobIDReq is the request of the jobs identified by int64 ids, e.g. the snapshot restore jobs.
+type Int64JobIDReq struct {
+	JobID int64 `json:"jobId" binding:"required"`
+}
+
+func (req *Int64JobIDReq) GetJobID() int64 { return req.JobID }
+
+type RefreshExternalCollectionReq struct {
+	DbName         string `json:"dbName"`
+	CollectionName string `json:"collectionName" binding:"required"`
+	ExternalSource string `json:"externalSource"`
+	ExternalSpec   string `json:"externalSpec"`
+}
+
+func (req *RefreshExternalCollectionReq) GetDbName() string         { return req.DbName }
+func (req *RefreshExternalCollectionReq) GetCollectionName() string { return req.CollectionName }
+func (req *RefreshExternalCollectionReq) GetExternalSource() string { return req.ExternalSource }
+func (req *RefreshExternalCollectionReq) GetExternalSpec() string   { return req.ExternalSpec }
+
+type ReplicateCluster struct {
+	ClusterID string   `json:"clusterId"`
+	URI       string   `json:"uri"`
+	Token     string   `json:"token"`
+	Pchannels []string `json:"pchannels"`
+}
+
+type ReplicateTopology struct {
+	SourceClusterID string `json:"sourceClusterId"`
+	TargetClusterID string `json:"targetClusterId"`
+}
+
+type UpdateReplicateConfigurationReq struct {
+	Clusters             []*ReplicateCluster  `json:"clusters" binding:"required"`
+	CrossClusterTopology []*ReplicateTopology `json:"crossClusterTopology"`
+	ForcePromote         bool                 `json:"forcePromote"`
+}
+
+func (req *UpdateReplicateConfigurationReq) GetClusters() []*ReplicateCluster { return req.Clusters }
+func (req *UpdateReplicateConfigurationReq) GetCrossClusterTopology() []*ReplicateTopology {
+	return req.CrossClusterTopology
+}
+func (req *UpdateReplicateConfigurationReq) GetForcePromote() bool { return req.ForcePromote }
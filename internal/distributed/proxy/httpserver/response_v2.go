@@ -0,0 +1,466 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+// The response types of RESTful v2 describe the bodies the handlers return, they are
+// only used to generate the OpenAPI spec, the handlers build the bodies with gin.H.
+
+// ReturnBase is embedded by every response, code is 0 on success, otherwise message describes the error.
+type ReturnBase struct {
+	Code    int32  `json:"code" binding:"required"`
+	Message string `json:"message,omitempty"`
+}
+
+// StorageCost is returned by the data requests if the storage usage is collected.
+type StorageCost struct {
+	ScannedRemoteBytes int64   `json:"scanned_remote_bytes,omitempty"`
+	ScannedTotalBytes  int64   `json:"scanned_total_bytes,omitempty"`
+	CacheHitRatio      float64 `json:"cache_hit_ratio,omitempty"`
+}
+
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type DefaultResp struct {
+	ReturnBase
+	Data struct{} `json:"data"`
+}
+
+type ListResp struct {
+	ReturnBase
+	Data []string `json:"data"`
+}
+
+type HasResp struct {
+	ReturnBase
+	Data struct {
+		Has bool `json:"has"`
+	} `json:"data"`
+}
+
+type RowCountResp struct {
+	ReturnBase
+	Data struct {
+		RowCount int64 `json:"rowCount"`
+	} `json:"data"`
+}
+
+type FieldDetail struct {
+	Name             string     `json:"name"`
+	ID               int64      `json:"id"`
+	Type             string     `json:"type"`
+	PrimaryKey       bool       `json:"primaryKey"`
+	PartitionKey     bool       `json:"partitionKey"`
+	ClusteringKey    bool       `json:"clusteringKey"`
+	AutoID           bool       `json:"autoId"`
+	Description      string     `json:"description"`
+	Nullable         bool       `json:"nullable"`
+	IsFunctionOutput bool       `json:"isFunctionOutput,omitempty"`
+	ElementType      string     `json:"elementType,omitempty"`
+	Params           []KeyValue `json:"params,omitempty"`
+}
+
+type FunctionDetail struct {
+	Name             string     `json:"name"`
+	ID               int64      `json:"id"`
+	Type             int32      `json:"type"`
+	Description      string     `json:"description"`
+	InputFieldNames  []string   `json:"inputFieldNames"`
+	OutputFieldNames []string   `json:"outputFieldNames"`
+	Params           []KeyValue `json:"params"`
+}
+
+type IndexBrief struct {
+	IndexName  string `json:"indexName"`
+	FieldName  string `json:"fieldName"`
+	MetricType string `json:"metricType"`
+}
+
+type CollectionDetailResp struct {
+	ReturnBase
+	Data struct {
+		CollectionName     string           `json:"collectionName"`
+		CollectionID       int64            `json:"collectionID"`
+		Description        string           `json:"description"`
+		AutoID             bool             `json:"autoId"`
+		Fields             []FieldDetail    `json:"fields"`
+		Functions          []FunctionDetail `json:"functions"`
+		Aliases            []string         `json:"aliases"`
+		Indexes            []IndexBrief     `json:"indexes"`
+		Load               string           `json:"load"`
+		ShardsNum          int32            `json:"shardsNum"`
+		PartitionsNum      int64            `json:"partitionsNum"`
+		ConsistencyLevel   string           `json:"consistencyLevel"`
+		EnableDynamicField bool             `json:"enableDynamicField"`
+		Properties         []KeyValue       `json:"properties"`
+	} `json:"data"`
+}
+
+type LoadStateResp struct {
+	ReturnBase
+	Data struct {
+		LoadState    string `json:"loadState"`
+		LoadProgress int64  `json:"loadProgress,omitempty"`
+	} `json:"data"`
+}
+
+type CompactResp struct {
+	ReturnBase
+	Data struct {
+		CompactionID int64 `json:"compactionID"`
+	} `json:"data"`
+}
+
+type CompactionStateResp struct {
+	ReturnBase
+	Data struct {
+		CompactionID        int64  `json:"compactionID"`
+		State               string `json:"state"`
+		ExecutingPlanNumber int64  `json:"executingPlanNumber"`
+		TimeoutPlanNumber   int64  `json:"timeoutPlanNumber"`
+		CompletedPlanNumber int64  `json:"completedPlanNumber"`
+	} `json:"data"`
+}
+
+type DatabaseDetailResp struct {
+	ReturnBase
+	Data struct {
+		DbName     string     `json:"dbName"`
+		DbID       int64      `json:"dbID"`
+		Properties []KeyValue `json:"properties"`
+	} `json:"data"`
+}
+
+// QueryResp is returned by query and get, each row maps the output fields to their values.
+type QueryResp struct {
+	ReturnBase
+	StorageCost
+	Data []map[string]any `json:"data"`
+	Cost int              `json:"cost"`
+}
+
+type DeleteResp struct {
+	ReturnBase
+	Data struct {
+		DeleteCount int64 `json:"deleteCount"`
+	} `json:"data"`
+}
+
+// InsertResp returns the primary keys of the inserted entities, the int64 keys are strings unless
+// the Accept-Type-Allow-Int64 header is set.
+type InsertResp struct {
+	ReturnBase
+	Data struct {
+		InsertCount int64 `json:"insertCount"`
+		InsertIds   []any `json:"insertIds"`
+	} `json:"data"`
+	Cost int `json:"cost"`
+}
+
+type UpsertResp struct {
+	ReturnBase
+	StorageCost
+	Data struct {
+		UpsertCount int64 `json:"upsertCount"`
+		UpsertIds   []any `json:"upsertIds"`
+	} `json:"data"`
+	Cost int `json:"cost"`
+}
+
+// SearchResp is returned by search, the results of all the queries are concatenated in data
+// and topks gives the number of results of each query.
+type SearchResp struct {
+	ReturnBase
+	StorageCost
+	Data    []map[string]any `json:"data"`
+	Cost    int              `json:"cost"`
+	Topks   []int64          `json:"topks,omitempty"`
+	Recalls []float32        `json:"recalls,omitempty"`
+}
+
+type HybridSearchResp struct {
+	ReturnBase
+	StorageCost
+	Data  []map[string]any `json:"data"`
+	Cost  int              `json:"cost"`
+	Topks []int64          `json:"topks,omitempty"`
+}
+
+// IteratorResp is returned by the iterators, the cursor requests the next page and is empty
+// once the iteration finishes.
+type IteratorResp struct {
+	ReturnBase
+	Data   []map[string]any `json:"data"`
+	Cost   int              `json:"cost"`
+	Cursor string           `json:"cursor"`
+}
+
+type RolePrivilege struct {
+	ObjectType string `json:"objectType"`
+	ObjectName string `json:"objectName"`
+	Privilege  string `json:"privilege"`
+	DbName     string `json:"dbName"`
+	Grantor    string `json:"grantor"`
+}
+
+type RoleDetailResp struct {
+	ReturnBase
+	Data []RolePrivilege `json:"data"`
+}
+
+type PrivilegeGroup struct {
+	PrivilegeGroupName string `json:"privilegeGroupName"`
+	// the privileges separated by commas
+	Privileges string `json:"privileges"`
+}
+
+type PrivilegeGroupsResp struct {
+	ReturnBase
+	Data struct {
+		PrivilegeGroups []PrivilegeGroup `json:"privilegeGroups"`
+	} `json:"data"`
+}
+
+type IndexDetail struct {
+	IndexName               string     `json:"indexName"`
+	FieldName               string     `json:"fieldName"`
+	IndexType               string     `json:"indexType"`
+	MetricType              string     `json:"metricType"`
+	MmapEnabled             string     `json:"mmap.enabled"`
+	IndexOffsetCacheEnabled string     `json:"indexoffsetcache.enabled"`
+	Warmup                  string     `json:"warmup"`
+	TotalRows               int64      `json:"totalRows"`
+	PendingRows             int64      `json:"pendingRows"`
+	IndexedRows             int64      `json:"indexedRows"`
+	IndexState              string     `json:"indexState"`
+	FailReason              string     `json:"failReason"`
+	MinIndexVersion         int32      `json:"minIndexVersion"`
+	MaxIndexVersion         int32      `json:"maxIndexVersion"`
+	IndexParams             []KeyValue `json:"indexParams"`
+}
+
+type IndexDetailResp struct {
+	ReturnBase
+	Data []IndexDetail `json:"data"`
+}
+
+type AliasDetailResp struct {
+	ReturnBase
+	Data struct {
+		DbName         string `json:"dbName"`
+		CollectionName string `json:"collectionName"`
+		AliasName      string `json:"aliasName"`
+	} `json:"data"`
+}
+
+type ImportJob struct {
+	JobID          string `json:"jobId"`
+	CollectionName string `json:"collectionName"`
+	State          string `json:"state"`
+	Progress       int64  `json:"progress"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+type ImportJobsResp struct {
+	ReturnBase
+	Data struct {
+		Records []ImportJob `json:"records"`
+	} `json:"data"`
+}
+
+type JobIDResp struct {
+	ReturnBase
+	Data struct {
+		JobID string `json:"jobId"`
+	} `json:"data"`
+}
+
+type Int64JobIDResp struct {
+	ReturnBase
+	Data struct {
+		JobID int64 `json:"jobId"`
+	} `json:"data"`
+}
+
+type ImportFileProgress struct {
+	FileName     string `json:"fileName"`
+	FileSize     int64  `json:"fileSize"`
+	Progress     int64  `json:"progress"`
+	CompleteTime string `json:"completeTime"`
+	State        string `json:"state"`
+	ImportedRows int64  `json:"importedRows"`
+	TotalRows    int64  `json:"totalRows"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+type ImportProgressResp struct {
+	ReturnBase
+	Data struct {
+		JobID          string               `json:"jobId"`
+		CollectionName string               `json:"collectionName"`
+		CreateTime     string               `json:"createTime"`
+		CompleteTime   string               `json:"completeTime"`
+		State          string               `json:"state"`
+		Progress       int64                `json:"progress"`
+		ImportedRows   int64                `json:"importedRows"`
+		TotalRows      int64                `json:"totalRows"`
+		FileSize       int64                `json:"fileSize"`
+		Reason         string               `json:"reason,omitempty"`
+		Details        []ImportFileProgress `json:"details"`
+	} `json:"data"`
+}
+
+// ResourceGroupDetailResp is returned by describing a resource group, it has no code and
+// carries the resource group in the layout of the grpc response.
+type ResourceGroupDetailResp struct {
+	ResourceGroup map[string]any `json:"resource_group"`
+}
+
+type FieldLogIDs struct {
+	FieldID int64   `json:"fieldID"`
+	LogIDs  []int64 `json:"logIDs"`
+}
+
+type SegmentDetail struct {
+	SegmentID    int64         `json:"segmentID"`
+	CollectionID int64         `json:"collectionID"`
+	PartitionID  int64         `json:"partitionID"`
+	VChannel     string        `json:"vChannel"`
+	NumRows      int64         `json:"numRows"`
+	State        int32         `json:"state"`
+	Level        int32         `json:"level"`
+	IsSorted     bool          `json:"isSorted"`
+	InsertLogs   []FieldLogIDs `json:"insertLogs"`
+	DeltaLogs    []FieldLogIDs `json:"deltaLogs"`
+	StatsLogs    []FieldLogIDs `json:"statsLogs"`
+}
+
+type SegmentsInfoResp struct {
+	ReturnBase
+	Data struct {
+		SegmentInfos []SegmentDetail `json:"segmentInfos"`
+	} `json:"data"`
+}
+
+// QuotaMetricsResp returns the metrics of the quota center as a json string.
+type QuotaMetricsResp struct {
+	ReturnBase
+	Data string `json:"data"`
+}
+
+// AnalyzerToken is a token of the analyzed text, the offsets and the positions are only
+// returned with withDetail and the hash with withHash.
+type AnalyzerToken struct {
+	Token          string `json:"token"`
+	StartOffset    int64  `json:"startOffset,omitempty"`
+	EndOffset      int64  `json:"endOffset,omitempty"`
+	Position       int64  `json:"position,omitempty"`
+	PositionLength int64  `json:"positionLength,omitempty"`
+	Hash           uint32 `json:"hash,omitempty"`
+}
+
+type RunAnalyzerResp struct {
+	ReturnBase
+	Data struct {
+		Results []struct {
+			Tokens []AnalyzerToken `json:"tokens"`
+		} `json:"results"`
+	} `json:"data"`
+}
+
+type SnapshotDetailResp struct {
+	ReturnBase
+	Data struct {
+		SnapshotName   string   `json:"snapshotName"`
+		Description    string   `json:"description"`
+		CreateTs       int64    `json:"createTs"`
+		CollectionName string   `json:"collectionName"`
+		PartitionNames []string `json:"partitionNames"`
+		S3Location     string   `json:"s3Location"`
+	} `json:"data"`
+}
+
+type RestoreSnapshotJob struct {
+	JobID          int64  `json:"jobId"`
+	SnapshotName   string `json:"snapshotName"`
+	DbName         string `json:"dbName"`
+	CollectionName string `json:"collectionName"`
+	State          string `json:"state"`
+	Progress       int32  `json:"progress"`
+	StartTime      uint64 `json:"startTime"`
+	TimeCost       uint64 `json:"timeCost"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+type RestoreSnapshotJobResp struct {
+	ReturnBase
+	Data RestoreSnapshotJob `json:"data"`
+}
+
+type RestoreSnapshotJobsResp struct {
+	ReturnBase
+	Data struct {
+		Records []RestoreSnapshotJob `json:"records"`
+	} `json:"data"`
+}
+
+type PinSnapshotResp struct {
+	ReturnBase
+	Data struct {
+		PinID int64 `json:"pinId"`
+	} `json:"data"`
+}
+
+type RefreshExternalCollectionJob struct {
+	JobID          int64  `json:"jobId"`
+	CollectionName string `json:"collectionName"`
+	State          string `json:"state"`
+	Progress       int64  `json:"progress"`
+	ExternalSource string `json:"externalSource"`
+	StartTime      int64  `json:"startTime"`
+	EndTime        int64  `json:"endTime"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+type RefreshExternalCollectionJobResp struct {
+	ReturnBase
+	Data RefreshExternalCollectionJob `json:"data"`
+}
+
+type RefreshExternalCollectionJobsResp struct {
+	ReturnBase
+	Data struct {
+		Records []RefreshExternalCollectionJob `json:"records"`
+	} `json:"data"`
+}
+
+// ReplicateClusterInfo is a cluster of the replicate configuration, the token is not returned.
+type ReplicateClusterInfo struct {
+	ClusterID string   `json:"clusterId"`
+	URI       string   `json:"uri"`
+	Pchannels []string `json:"pchannels"`
+}
+
+type ReplicateConfigurationResp struct {
+	ReturnBase
+	Data struct {
+		Clusters             []ReplicateClusterInfo `json:"clusters"`
+		CrossClusterTopology []ReplicateTopology    `json:"crossClusterTopology"`
+	} `json:"data"`
+}
@@ -0,0 +1,233 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+func toRestoreSnapshotJob(info *milvuspb.RestoreSnapshotInfo) gin.H {
+	job := gin.H{
+		"jobId":            info.GetJobId(),
+		"snapshotName":     info.GetSnapshotName(),
+		HTTPDbName:         info.GetDbName(),
+		HTTPCollectionName: info.GetCollectionName(),
+		"state":            info.GetState().String(),
+		"progress":         info.GetProgress(),
+		"startTime":        info.GetStartTime(),
+		"timeCost":         info.GetTimeCost(),
+	}
+	if info.GetReason() != "" {
+		job["reason"] = info.GetReason()
+	}
+	return job
+}
+
+func (h *HandlersV2) createSnapshot(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*CreateSnapshotReq)
+	req := &milvuspb.CreateSnapshotRequest{
+		Base:           &commonpb.MsgBase{},
+		Name:           httpReq.GetSnapshotName(),
+		Description:    httpReq.GetDescription(),
+		DbName:         dbName,
+		CollectionName: httpReq.GetCollectionName(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/CreateSnapshot", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.CreateSnapshot(reqCtx, req.(*milvuspb.CreateSnapshotRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, wrapperReturnDefault())
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) dropSnapshot(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*SnapshotReq)
+	req := &milvuspb.DropSnapshotRequest{
+		Base:           &commonpb.MsgBase{},
+		Name:           httpReq.GetSnapshotName(),
+		DbName:         dbName,
+		CollectionName: httpReq.GetCollectionName(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/DropSnapshot", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.DropSnapshot(reqCtx, req.(*milvuspb.DropSnapshotRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, wrapperReturnDefault())
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) listSnapshots(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*OptionalCollectionNameReq)
+	req := &milvuspb.ListSnapshotsRequest{
+		Base:           &commonpb.MsgBase{},
+		DbName:         dbName,
+		CollectionName: httpReq.GetCollectionName(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/ListSnapshots", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.ListSnapshots(reqCtx, req.(*milvuspb.ListSnapshotsRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, wrapperReturnList(resp.(*milvuspb.ListSnapshotsResponse).GetSnapshots()))
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) describeSnapshot(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*SnapshotReq)
+	req := &milvuspb.DescribeSnapshotRequest{
+		Base:           &commonpb.MsgBase{},
+		Name:           httpReq.GetSnapshotName(),
+		DbName:         dbName,
+		CollectionName: httpReq.GetCollectionName(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/DescribeSnapshot", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.DescribeSnapshot(reqCtx, req.(*milvuspb.DescribeSnapshotRequest))
+	})
+	if err == nil {
+		response := resp.(*milvuspb.DescribeSnapshotResponse)
+		HTTPReturn(c, http.StatusOK, gin.H{HTTPReturnCode: merr.Code(nil), HTTPReturnData: gin.H{
+			"snapshotName":     response.GetName(),
+			"description":      response.GetDescription(),
+			"createTs":         response.GetCreateTs(),
+			HTTPCollectionName: response.GetCollectionName(),
+			HTTPPartitionNames: response.GetPartitionNames(),
+			"s3Location":       response.GetS3Location(),
+		}})
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) restoreSnapshot(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*RestoreSnapshotReq)
+	req := &milvuspb.RestoreSnapshotRequest{
+		Base:                 &commonpb.MsgBase{},
+		Name:                 httpReq.GetSnapshotName(),
+		DbName:               dbName,
+		CollectionName:       httpReq.GetCollectionName(),
+		TargetDbName:         httpReq.GetTargetDbName(),
+		TargetCollectionName: httpReq.GetTargetCollectionName(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/RestoreSnapshot", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.RestoreSnapshot(reqCtx, req.(*milvuspb.RestoreSnapshotRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, gin.H{HTTPReturnCode: merr.Code(nil), HTTPReturnData: gin.H{
+			"jobId": resp.(*milvuspb.RestoreSnapshotResponse).GetJobId(),
+		}})
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) getRestoreSnapshotState(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*Int64JobIDReq)
+	req := &milvuspb.GetRestoreSnapshotStateRequest{
+		Base:  &commonpb.MsgBase{},
+		JobId: httpReq.GetJobID(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/GetRestoreSnapshotState", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.GetRestoreSnapshotState(reqCtx, req.(*milvuspb.GetRestoreSnapshotStateRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, gin.H{
+			HTTPReturnCode: merr.Code(nil),
+			HTTPReturnData: toRestoreSnapshotJob(resp.(*milvuspb.GetRestoreSnapshotStateResponse).GetInfo()),
+		})
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) listRestoreSnapshotJobs(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*OptionalCollectionNameReq)
+	req := &milvuspb.ListRestoreSnapshotJobsRequest{
+		Base:           &commonpb.MsgBase{},
+		DbName:         dbName,
+		CollectionName: httpReq.GetCollectionName(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/ListRestoreSnapshotJobs", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.ListRestoreSnapshotJobs(reqCtx, req.(*milvuspb.ListRestoreSnapshotJobsRequest))
+	})
+	if err == nil {
+		jobs := lo.Map(resp.(*milvuspb.ListRestoreSnapshotJobsResponse).GetJobs(), func(info *milvuspb.RestoreSnapshotInfo, _ int) gin.H {
+			return toRestoreSnapshotJob(info)
+		})
+		HTTPReturn(c, http.StatusOK, gin.H{HTTPReturnCode: merr.Code(nil), HTTPReturnData: gin.H{"records": jobs}})
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) pinSnapshotData(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*PinSnapshotReq)
+	req := &milvuspb.PinSnapshotDataRequest{
+		Base:           &commonpb.MsgBase{},
+		Name:           httpReq.GetSnapshotName(),
+		DbName:         dbName,
+		CollectionName: httpReq.GetCollectionName(),
+		TtlSeconds:     httpReq.GetTTLSeconds(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/PinSnapshotData", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.PinSnapshotData(reqCtx, req.(*milvuspb.PinSnapshotDataRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, gin.H{HTTPReturnCode: merr.Code(nil), HTTPReturnData: gin.H{
+			"pinId": resp.(*milvuspb.PinSnapshotDataResponse).GetPinId(),
+		}})
+	}
+	return resp, err
+}
+
+func (h *HandlersV2) unpinSnapshotData(ctx context.Context, c *gin.Context, anyReq any, dbName string) (interface{}, error) {
+	httpReq := anyReq.(*UnpinSnapshotReq)
+	req := &milvuspb.UnpinSnapshotDataRequest{
+		Base:  &commonpb.MsgBase{},
+		PinId: httpReq.GetPinID(),
+	}
+	c.Set(ContextRequest, req)
+
+	resp, err := wrapperProxyWithLimit(ctx, c, req, h.checkAuth, false, "/milvus.proto.milvus.MilvusService/UnpinSnapshotData", true, h.proxy, func(reqCtx context.Context, req any) (interface{}, error) {
+		return h.proxy.UnpinSnapshotData(reqCtx, req.(*milvuspb.UnpinSnapshotDataRequest))
+	})
+	if err == nil {
+		HTTPReturn(c, http.StatusOK, wrapperReturnDefault())
+	}
+	return resp, err
+}
@@ -0,0 +1,143 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestSnapshotHandlerV2(t *testing.T) {
+	// init params
+	paramtable.Init()
+
+	// disable rate limit
+	paramtable.Get().Save(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key, "false")
+	defer paramtable.Get().Reset(paramtable.Get().QuotaConfig.QuotaAndLimitsEnabled.Key)
+
+	// mock proxy
+	mockProxy := mocks.NewMockProxy(t)
+	mockProxy.EXPECT().CreateSnapshot(mock.Anything, mock.Anything).Return(merr.Success(), nil)
+	mockProxy.EXPECT().DropSnapshot(mock.Anything, mock.Anything).Return(merr.Success(), nil)
+	mockProxy.EXPECT().ListSnapshots(mock.Anything, mock.Anything).Return(&milvuspb.ListSnapshotsResponse{
+		Status:    merr.Success(),
+		Snapshots: []string{"snapshot"},
+	}, nil)
+	mockProxy.EXPECT().DescribeSnapshot(mock.Anything, mock.Anything).Return(&milvuspb.DescribeSnapshotResponse{
+		Status:         merr.Success(),
+		Name:           "snapshot",
+		CollectionName: "hello_milvus",
+	}, nil)
+	mockProxy.EXPECT().RestoreSnapshot(mock.Anything, mock.Anything).Return(&milvuspb.RestoreSnapshotResponse{
+		Status: merr.Success(),
+		JobId:  1,
+	}, nil)
+	mockProxy.EXPECT().GetRestoreSnapshotState(mock.Anything, mock.Anything).Return(&milvuspb.GetRestoreSnapshotStateResponse{
+		Status: merr.Success(),
+		Info:   &milvuspb.RestoreSnapshotInfo{JobId: 1, SnapshotName: "snapshot"},
+	}, nil)
+	mockProxy.EXPECT().ListRestoreSnapshotJobs(mock.Anything, mock.Anything).Return(&milvuspb.ListRestoreSnapshotJobsResponse{
+		Status: merr.Success(),
+		Jobs:   []*milvuspb.RestoreSnapshotInfo{{JobId: 1, SnapshotName: "snapshot", Reason: "failed"}},
+	}, nil)
+	mockProxy.EXPECT().PinSnapshotData(mock.Anything, mock.Anything).Return(&milvuspb.PinSnapshotDataResponse{
+		Status: merr.Success(),
+		PinId:  1,
+	}, nil)
+	mockProxy.EXPECT().UnpinSnapshotData(mock.Anything, mock.Anything).Return(merr.Success(), nil)
+
+	// setup test server
+	testServer := initHTTPServerV2(mockProxy, false)
+
+	testCases := []requestBodyTestCase{
+		{
+			path:        versionalV2(SnapshotCategory, CreateAction),
+			requestBody: []byte(`{}`),
+			errCode:     1802,
+			errMsg:      "missing required parameters, error: Key: 'CreateSnapshotReq.CollectionName' Error:Field validation for 'CollectionName' failed on the 'required' tag\nKey: 'CreateSnapshotReq.SnapshotName' Error:Field validation for 'SnapshotName' failed on the 'required' tag",
+		},
+		{
+			path:        versionalV2(SnapshotCategory, CreateAction),
+			requestBody: []byte(`{"collectionName":"hello_milvus","snapshotName":"snapshot","description":"daily"}`),
+		},
+		{
+			path:        versionalV2(SnapshotCategory, DropAction),
+			requestBody: []byte(`{}`),
+			errCode:     1802,
+			errMsg:      "missing required parameters, error: Key: 'SnapshotReq.SnapshotName' Error:Field validation for 'SnapshotName' failed on the 'required' tag",
+		},
+		{
+			path:        versionalV2(SnapshotCategory, DropAction),
+			requestBody: []byte(`{"collectionName":"hello_milvus","snapshotName":"snapshot"}`),
+		},
+		{
+			path:        versionalV2(SnapshotCategory, ListAction),
+			requestBody: []byte(`{"collectionName":"hello_milvus"}`),
+		},
+		{
+			path:        versionalV2(SnapshotCategory, DescribeAction),
+			requestBody: []byte(`{"snapshotName":"snapshot"}`),
+		},
+		{
+			path:        versionalV2(SnapshotCategory, RestoreAction),
+			requestBody: []byte(`{"snapshotName":"snapshot"}`),
+			errCode:     1802,
+			errMsg:      "missing required parameters, error: Key: 'RestoreSnapshotReq.TargetCollectionName' Error:Field validation for 'TargetCollectionName' failed on the 'required' tag",
+		},
+		{
+			path:        versionalV2(SnapshotCategory, RestoreAction),
+			requestBody: []byte(`{"snapshotName":"snapshot","targetCollectionName":"restored"}`),
+		},
+		{
+			path:        versionalV2(SnapshotCategory, GetRestoreStateAction),
+			requestBody: []byte(`{}`),
+			errCode:     1802,
+			errMsg:      "missing required parameters, error: Key: 'Int64JobIDReq.JobID' Error:Field validation for 'JobID' failed on the 'required' tag",
+		},
+		{
+			path:        versionalV2(SnapshotCategory, GetRestoreStateAction),
+			requestBody: []byte(`{"jobId":1}`),
+		},
+		{
+			path:        versionalV2(SnapshotCategory, ListRestoreJobsAction),
+			requestBody: []byte(`{}`),
+		},
+		{
+			path:        versionalV2(SnapshotCategory, PinAction),
+			requestBody: []byte(`{"snapshotName":"snapshot","ttlSeconds":3600}`),
+		},
+		{
+			path:        versionalV2(SnapshotCategory, UnpinAction),
+			requestBody: []byte(`{}`),
+			errCode:     1802,
+			errMsg:      "missing required parameters, error: Key: 'UnpinSnapshotReq.PinID' Error:Field validation for 'PinID' failed on the 'required' tag",
+		},
+		{
+			path:        versionalV2(SnapshotCategory, UnpinAction),
+			requestBody: []byte(`{"pinId":1}`),
+		},
+	}
+
+	// verify test case
+	validateRequestBodyTestCases(t, testServer, testCases, false)
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/milvus-io/milvus/internal/proxy"
 	"github.com/milvus-io/milvus/internal/proxy/accesslog"
 	"github.com/milvus-io/milvus/internal/types"
+	functionutil "github.com/milvus-io/milvus/internal/util/function"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/metrics"
@@ -1725,12 +1726,16 @@ func CheckLimiter(ctx context.Context, req interface{}, pxy types.ProxyComponent
 	if err != nil {
 		return nil, err
 	}
-	err = limiter.Check(dbID, collectionIDToPartIDs, rt, n)
+	err = proxy.CheckLimiterWithBackpressure(ctx, limiter, dbID, collectionIDToPartIDs, rt, n)
 	nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
 	metrics.ProxyRateLimitReqCount.WithLabelValues(nodeID, rt.String(), metrics.TotalLabel).Inc()
 	if err != nil {
 		metrics.ProxyRateLimitReqCount.WithLabelValues(nodeID, rt.String(), metrics.FailLabel).Inc()
-		return proxy.GetFailedResponse(req, err), err
+		rsp := proxy.GetFailedResponse(req, err)
+		if retryAfter, ok := proxy.GetRetryAfter(err); ok {
+			proxy.SetRetryAfterHint(ctx, rsp, retryAfter)
+		}
+		return rsp, err
 	}
 	metrics.ProxyRateLimitReqCount.WithLabelValues(nodeID, rt.String(), metrics.SuccessLabel).Inc()
 	return nil, nil
@@ -2248,6 +2253,10 @@ func generateSearchParams(reqSearchParams map[string]interface{}) ([]*commonpb.K
 
 func genFunctionSchema(ctx context.Context, function *FunctionSchema) (*schemapb.FunctionSchema, error) {
 	functionTypeValue, ok := schemapb.FunctionType_value[function.FunctionType]
+	if !ok && function.FunctionType == functionutil.ExpressionFunctionName {
+		// the expression function type is not in milvus-proto yet.
+		functionTypeValue, ok = int32(functionutil.FunctionTypeExpression), true
+	}
 	if !ok {
 		log.Ctx(ctx).Warn("function's data type is invalid(case sensitive).", zap.Any("function.DataType", function.FunctionType), zap.Any("function", function))
 		return nil, merr.WrapErrParameterInvalidMsg("Unsupported function type: %s", function.FunctionType)
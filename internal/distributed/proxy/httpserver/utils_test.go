@@ -34,6 +34,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/json"
+	functionutil "github.com/milvus-io/milvus/internal/util/function"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
 )
@@ -2876,6 +2877,21 @@ func TestGenFunctionSchem(t *testing.T) {
 		_, err := genFunctionSchema(context.Background(), funcSchema)
 		assert.NoError(t, err)
 	}
+	{
+		funcSchema := &FunctionSchema{
+			FunctionName:     "test",
+			Description:      "",
+			FunctionType:     "Expression",
+			InputFieldNames:  []string{"title"},
+			OutputFieldNames: []string{"lower_title"},
+			Params: map[string]interface{}{
+				"expression": "lower(title)",
+			},
+		}
+		fSchema, err := genFunctionSchema(context.Background(), funcSchema)
+		assert.NoError(t, err)
+		assert.Equal(t, functionutil.FunctionTypeExpression, fSchema.GetType())
+	}
 }
 
 func TestGenFunctionScore(t *testing.T) {
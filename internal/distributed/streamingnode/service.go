@@ -269,7 +269,7 @@ func (s *Server) initSession() error {
 
 func (s *Server) initMeta() error {
 	params := paramtable.Get()
-	metaType := params.MetaStoreCfg.MetaStoreType.GetValue()
+	metaType := params.GetStreamingMetaStoreType()
 	log := log.Ctx(s.ctx)
 	log.Info("data coordinator connecting to metadata store", zap.String("metaType", metaType))
 	metaRootPath := ""
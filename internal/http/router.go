@@ -108,6 +108,8 @@ const (
 	HookConfigsPath = "/_hook/configs"
 	// SlowQueryPath is the path to get slow queries metrics
 	SlowQueryPath = "/_cluster/slow_query"
+	// SlowQueryStatsPath is the path to get the latency summary of slow queries
+	SlowQueryStatsPath = "/_cluster/slow_query/stats"
 
 	// QCDistPath is the path to get QueryCoord distribution.
 	QCDistPath = "/_qc/dist"
@@ -34,16 +34,36 @@ import (
 // │   └── cluster-1-pchannel-2
 // │   ├── cluster-2-pchannel-1
 // │   └── cluster-2-pchannel-2
-func NewCataLog(metaKV kv.MetaKv) metastore.StreamingCoordCataLog {
-	return &catalog{
+//
+// The replicate-configuration and replicating-pchannel are written into the replicate meta kv if given.
+func NewCataLog(metaKV kv.MetaKv, opts ...CatalogOption) metastore.StreamingCoordCataLog {
+	c := &catalog{
 		// catalog should be reliable to write, ensure the data is consistent in memory and underlying meta storage.
 		metaKV: kv.NewReliableWriteMetaKv(metaKV),
 	}
+	c.replicateMetaKV = c.metaKV
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CatalogOption is the option to create the catalog.
+type CatalogOption func(*catalog)
+
+// OptReplicateMetaKV sets the meta kv of the replicate configuration and replicating pchannels.
+// The replicating pchannels are watched by cdc on etcd,
+// so they are kept on etcd even if the other streaming meta is stored on tikv.
+func OptReplicateMetaKV(metaKV kv.MetaKv) CatalogOption {
+	return func(c *catalog) {
+		c.replicateMetaKV = kv.NewReliableWriteMetaKv(metaKV)
+	}
 }
 
 // catalog is a kv based catalog.
 type catalog struct {
-	metaKV kv.MetaKv
+	metaKV          kv.MetaKv
+	replicateMetaKV kv.MetaKv
 }
 
 // GetCChannel returns the control channel
@@ -193,13 +213,13 @@ func (c *catalog) SaveReplicateConfiguration(ctx context.Context, config *stream
 	}
 	maxTxnNum := paramtable.Get().MetaStoreCfg.MaxEtcdTxnNum.GetAsInt()
 	return etcd.SaveByBatchWithLimit(kvs, maxTxnNum, func(partialKvs map[string]string) error {
-		return c.metaKV.MultiSave(ctx, partialKvs)
+		return c.replicateMetaKV.MultiSave(ctx, partialKvs)
 	})
 }
 
 func (c *catalog) GetReplicateConfiguration(ctx context.Context) (*streamingpb.ReplicateConfigurationMeta, error) {
 	key := ReplicateConfigurationKey
-	value, err := c.metaKV.Load(ctx, key)
+	value, err := c.replicateMetaKV.Load(ctx, key)
 	if err != nil {
 		if errors.Is(err, merr.ErrIoKeyNotFound) {
 			return nil, nil
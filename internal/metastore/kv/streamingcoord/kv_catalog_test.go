@@ -201,3 +201,37 @@ func TestCatalog_ReplicationCatalog(t *testing.T) {
 		})
 	assert.NoError(t, err)
 }
+
+func TestCatalog_ReplicateMetaKV(t *testing.T) {
+	// the replicate meta never touches the meta kv.
+	metaKV := mock_kv.NewMockMetaKv(t)
+	replicateKV := mock_kv.NewMockMetaKv(t)
+	kvStorage := make(map[string]string)
+	replicateKV.EXPECT().MultiSave(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, kvs map[string]string) error {
+		for k, v := range kvs {
+			kvStorage[k] = v
+		}
+		return nil
+	})
+	replicateKV.EXPECT().Load(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, s string) (string, error) {
+		return kvStorage[s], nil
+	})
+
+	catalog := NewCataLog(metaKV, OptReplicateMetaKV(replicateKV))
+	err := catalog.SaveReplicateConfiguration(context.Background(),
+		&streamingpb.ReplicateConfigurationMeta{ReplicateConfiguration: &commonpb.ReplicateConfiguration{
+			Clusters: []*commonpb.MilvusCluster{{ClusterId: "source-cluster"}},
+		}},
+		[]*streamingpb.ReplicatePChannelMeta{{
+			SourceChannelName: "source-channel-1",
+			TargetChannelName: "target-channel-1",
+			TargetCluster:     &commonpb.MilvusCluster{ClusterId: "target-cluster"},
+		}})
+	assert.NoError(t, err)
+	assert.Contains(t, kvStorage, ReplicateConfigurationKey)
+	assert.Contains(t, kvStorage, ReplicatePChannelMetaPrefix+"target-cluster-source-channel-1")
+
+	cfg, err := catalog.GetReplicateConfiguration(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "source-cluster", cfg.GetReplicateConfiguration().GetClusters()[0].GetClusterId())
+}
@@ -0,0 +1,103 @@
+package streamingnode
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/kv"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/etcd"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// newCheckpointWriter creates a new checkpoint writer.
+func newCheckpointWriter(metaKV kv.MetaKv) *checkpointWriter {
+	return &checkpointWriter{
+		metaKV:  metaKV,
+		pending: make(map[string]string),
+	}
+}
+
+// checkpointWriter batches and coalesces the checkpoint writes of all wals on the streaming node.
+// The checkpoints saved within the coalesce window are written by one batch,
+// and only the latest checkpoint of a key is written.
+// A save returns after the checkpoint, or a newer one of the same key, is persisted,
+// so the durability of a checkpoint is the same as writing it directly.
+type checkpointWriter struct {
+	metaKV kv.MetaKv
+
+	mu        sync.Mutex
+	pending   map[string]string
+	notifiers []chan error
+	flushing  bool
+}
+
+// Save saves the checkpoint and blocks until it's persisted.
+// The checkpoints of the same key should be saved in order, the later one overwrites the earlier one.
+func (w *checkpointWriter) Save(ctx context.Context, key string, value string) error {
+	window := paramtable.Get().StreamingCfg.MetaStoreCheckpointCoalesceWindow.GetAsDurationByParse()
+	if window <= 0 {
+		return w.metaKV.Save(ctx, key, value)
+	}
+
+	notifier := make(chan error, 1)
+	w.mu.Lock()
+	w.pending[key] = value
+	w.notifiers = append(w.notifiers, notifier)
+	if !w.flushing {
+		w.flushing = true
+		go w.flushLoop(window)
+	}
+	w.mu.Unlock()
+
+	select {
+	case err := <-notifier:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flushLoop flushes the pending checkpoints every window until there's no pending checkpoint.
+func (w *checkpointWriter) flushLoop(window time.Duration) {
+	for {
+		time.Sleep(window)
+
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.flushing = false
+			w.mu.Unlock()
+			return
+		}
+		kvs, notifiers := w.pending, w.notifiers
+		w.pending = make(map[string]string, len(kvs))
+		w.notifiers = nil
+		w.mu.Unlock()
+
+		err := w.flush(kvs)
+		if err != nil {
+			log.Warn("failed to flush wal checkpoints", zap.Int("checkpointNum", len(kvs)), zap.Error(err))
+		}
+		for _, notifier := range notifiers {
+			notifier <- err
+		}
+	}
+}
+
+// flush writes the checkpoints into the meta store.
+func (w *checkpointWriter) flush(kvs map[string]string) error {
+	// the waiting callers may be canceled, the flush should not be interrupted by them.
+	ctx := context.Background()
+	if len(kvs) == 1 {
+		for key, value := range kvs {
+			return w.metaKV.Save(ctx, key, value)
+		}
+	}
+	maxTxnNum := paramtable.Get().MetaStoreCfg.MaxEtcdTxnNum.GetAsInt()
+	return etcd.SaveByBatchWithLimit(kvs, maxTxnNum, func(partialKvs map[string]string) error {
+		return w.metaKV.MultiSave(ctx, partialKvs)
+	})
+}
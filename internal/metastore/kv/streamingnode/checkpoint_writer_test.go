@@ -0,0 +1,105 @@
+package streamingnode
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus/internal/kv/mocks"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestCheckpointWriterCoalesce(t *testing.T) {
+	params := paramtable.Get()
+	params.Save(params.StreamingCfg.MetaStoreCheckpointCoalesceWindow.Key, "20ms")
+	defer params.Reset(params.StreamingCfg.MetaStoreCheckpointCoalesceWindow.Key)
+
+	kv := mocks.NewMetaKv(t)
+	var mu sync.Mutex
+	saved := make(map[string]string)
+	batches := 0
+	kv.EXPECT().MultiSave(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, kvs map[string]string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		batches++
+		for k, v := range kvs {
+			saved[k] = v
+		}
+		return nil
+	}).Maybe()
+	kv.EXPECT().Save(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, k string, v string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		batches++
+		saved[k] = v
+		return nil
+	}).Maybe()
+
+	w := newCheckpointWriter(kv)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("p%d", i)
+			for j := 0; j < 10; j++ {
+				assert.NoError(t, w.Save(context.Background(), key, fmt.Sprintf("%d", j)))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, "9", saved[fmt.Sprintf("p%d", i)])
+	}
+	// the checkpoints of different wals are written by the same batch.
+	assert.Less(t, batches, 40)
+
+	// the flush loop exits after all the checkpoints are flushed.
+	assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return !w.flushing
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCheckpointWriterFailure(t *testing.T) {
+	params := paramtable.Get()
+	params.Save(params.StreamingCfg.MetaStoreCheckpointCoalesceWindow.Key, "10ms")
+	defer params.Reset(params.StreamingCfg.MetaStoreCheckpointCoalesceWindow.Key)
+
+	kv := mocks.NewMetaKv(t)
+	kv.EXPECT().Save(mock.Anything, mock.Anything, mock.Anything).Return(errors.New("err"))
+	w := newCheckpointWriter(kv)
+	assert.Error(t, w.Save(context.Background(), "p1", "1"))
+
+	// the caller is canceled before the checkpoint is flushed.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, w.Save(ctx, "p1", "2"), context.Canceled)
+	assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return !w.flushing
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCheckpointWriterDisabled(t *testing.T) {
+	params := paramtable.Get()
+	params.Save(params.StreamingCfg.MetaStoreCheckpointCoalesceWindow.Key, "0")
+	defer params.Reset(params.StreamingCfg.MetaStoreCheckpointCoalesceWindow.Key)
+
+	kv := mocks.NewMetaKv(t)
+	kv.EXPECT().Save(mock.Anything, "p1", "1").Return(nil)
+	w := newCheckpointWriter(kv)
+	assert.NoError(t, w.Save(context.Background(), "p1", "1"))
+	assert.False(t, w.flushing)
+}
@@ -47,15 +47,20 @@ import (
 //	        ├── 456398247934
 //	        ├── 456398247935
 //	        └── 456398247938
+//
+// The consume checkpoints are the most frequently updated keys,
+// they are written by the checkpoint writer to batch and coalesce the writes of all wals.
 func NewCataLog(metaKV kv.MetaKv) metastore.StreamingNodeCataLog {
 	return &catalog{
-		metaKV: metaKV,
+		metaKV:           metaKV,
+		checkpointWriter: newCheckpointWriter(metaKV),
 	}
 }
 
 // catalog is a kv based catalog.
 type catalog struct {
-	metaKV kv.MetaKv
+	metaKV           kv.MetaKv
+	checkpointWriter *checkpointWriter
 }
 
 // ListVChannel lists the vchannel info of the pchannel.
@@ -269,7 +274,7 @@ func (c *catalog) SaveConsumeCheckpoint(ctx context.Context, pchannelName string
 	if err != nil {
 		return err
 	}
-	return c.metaKV.Save(ctx, key, string(value))
+	return c.checkpointWriter.Save(ctx, key, string(value))
 }
 
 // SaveSalvageCheckpoint saves the salvage checkpoint, keyed by the source cluster ID.
@@ -7,6 +7,8 @@ import (
 	message "github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
 	mock "github.com/stretchr/testify/mock"
 
+	schemapb "github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+
 	shards "github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors/shard/shards"
 
 	types "github.com/milvus-io/milvus/pkg/v2/streaming/util/types"
@@ -1040,6 +1042,64 @@ func (_c *MockShardManager_FlushSegment_Call) RunAndReturn(run func(message.Immu
 	return _c
 }
 
+// GetCollectionSchema provides a mock function with given fields: collectionID
+func (_m *MockShardManager) GetCollectionSchema(collectionID int64) (*schemapb.CollectionSchema, error) {
+	ret := _m.Called(collectionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCollectionSchema")
+	}
+
+	var r0 *schemapb.CollectionSchema
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*schemapb.CollectionSchema, error)); ok {
+		return rf(collectionID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *schemapb.CollectionSchema); ok {
+		r0 = rf(collectionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schemapb.CollectionSchema)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(collectionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockShardManager_GetCollectionSchema_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCollectionSchema'
+type MockShardManager_GetCollectionSchema_Call struct {
+	*mock.Call
+}
+
+// GetCollectionSchema is a helper method to define mock.On call
+//   - collectionID int64
+func (_e *MockShardManager_Expecter) GetCollectionSchema(collectionID interface{}) *MockShardManager_GetCollectionSchema_Call {
+	return &MockShardManager_GetCollectionSchema_Call{Call: _e.mock.On("GetCollectionSchema", collectionID)}
+}
+
+func (_c *MockShardManager_GetCollectionSchema_Call) Run(run func(collectionID int64)) *MockShardManager_GetCollectionSchema_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockShardManager_GetCollectionSchema_Call) Return(_a0 *schemapb.CollectionSchema, _a1 error) *MockShardManager_GetCollectionSchema_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockShardManager_GetCollectionSchema_Call) RunAndReturn(run func(int64) (*schemapb.CollectionSchema, error)) *MockShardManager_GetCollectionSchema_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Logger provides a mock function with no fields
 func (_m *MockShardManager) Logger() *log.MLogger {
 	ret := _m.Called()
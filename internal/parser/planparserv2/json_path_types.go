@@ -0,0 +1,149 @@
+package planparserv2
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/util/jsonschema"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// castJSONPathValues casts the values compared with the json paths to the types declared by the json schema of the field,
+// so the json paths are compared with the values of the declared types without trying the other types at runtime,
+// and the comparisons that never match the declared types are rejected.
+// It's applied after the template values are filled, so both the literal and template values are casted.
+func castJSONPathValues(schema *typeutil.SchemaHelper, expr *planpb.Expr) error {
+	switch e := expr.GetExpr().(type) {
+	case *planpb.Expr_UnaryExpr:
+		return castJSONPathValues(schema, e.UnaryExpr.GetChild())
+	case *planpb.Expr_BinaryExpr:
+		if err := castJSONPathValues(schema, e.BinaryExpr.GetLeft()); err != nil {
+			return err
+		}
+		return castJSONPathValues(schema, e.BinaryExpr.GetRight())
+	case *planpb.Expr_RandomSampleExpr:
+		return castJSONPathValues(schema, e.RandomSampleExpr.GetPredicate())
+	case *planpb.Expr_UnaryRangeExpr:
+		dataType, ok := declaredJSONPathType(schema, e.UnaryRangeExpr.GetColumnInfo())
+		if !ok {
+			return nil
+		}
+		value, err := castJSONPathValue(dataType, e.UnaryRangeExpr.GetColumnInfo(), e.UnaryRangeExpr.GetValue())
+		if err != nil {
+			return err
+		}
+		e.UnaryRangeExpr.Value = value
+	case *planpb.Expr_BinaryRangeExpr:
+		dataType, ok := declaredJSONPathType(schema, e.BinaryRangeExpr.GetColumnInfo())
+		if !ok {
+			return nil
+		}
+		lower, err := castJSONPathValue(dataType, e.BinaryRangeExpr.GetColumnInfo(), e.BinaryRangeExpr.GetLowerValue())
+		if err != nil {
+			return err
+		}
+		upper, err := castJSONPathValue(dataType, e.BinaryRangeExpr.GetColumnInfo(), e.BinaryRangeExpr.GetUpperValue())
+		if err != nil {
+			return err
+		}
+		e.BinaryRangeExpr.LowerValue, e.BinaryRangeExpr.UpperValue = lower, upper
+	case *planpb.Expr_TermExpr:
+		dataType, ok := declaredJSONPathType(schema, e.TermExpr.GetColumnInfo())
+		if !ok {
+			return nil
+		}
+		values := make([]*planpb.GenericValue, 0, len(e.TermExpr.GetValues()))
+		for _, v := range e.TermExpr.GetValues() {
+			value, err := castJSONPathValue(dataType, e.TermExpr.GetColumnInfo(), v)
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+		e.TermExpr.Values = values
+	case *planpb.Expr_JsonContainsExpr:
+		// the elements are compared with the items of the declared array.
+		s := declaredJSONPathSchema(schema, e.JsonContainsExpr.GetColumnInfo())
+		if dataType, ok := s.DataType(); !ok || dataType != schemapb.DataType_Array {
+			return nil
+		}
+		dataType, ok := s.Items.DataType()
+		if !ok {
+			return nil
+		}
+		elements := make([]*planpb.GenericValue, 0, len(e.JsonContainsExpr.GetElements()))
+		for _, v := range e.JsonContainsExpr.GetElements() {
+			element, err := castJSONPathValue(dataType, e.JsonContainsExpr.GetColumnInfo(), v)
+			if err != nil {
+				return err
+			}
+			elements = append(elements, element)
+		}
+		e.JsonContainsExpr.Elements = elements
+		e.JsonContainsExpr.ElementsSameType = true
+	}
+	return nil
+}
+
+// declaredJSONPathSchema returns the schema declared for the json path of the column, nil if not declared.
+func declaredJSONPathSchema(schema *typeutil.SchemaHelper, columnInfo *planpb.ColumnInfo) *jsonschema.Schema {
+	if !typeutil.IsJSONType(columnInfo.GetDataType()) || len(columnInfo.GetNestedPath()) == 0 {
+		return nil
+	}
+	field, err := schema.GetFieldFromID(columnInfo.GetFieldId())
+	if err != nil {
+		return nil
+	}
+	// the schema is checked when it's attached, so the fields with invalid schemas are planned as usual.
+	s, err := jsonschema.FromField(field)
+	if err != nil || s == nil {
+		return nil
+	}
+	return s.Lookup(columnInfo.GetNestedPath())
+}
+
+// declaredJSONPathType returns the scalar type declared for the json path of the column.
+func declaredJSONPathType(schema *typeutil.SchemaHelper, columnInfo *planpb.ColumnInfo) (schemapb.DataType, bool) {
+	dataType, ok := declaredJSONPathSchema(schema, columnInfo).DataType()
+	if !ok || dataType == schemapb.DataType_JSON {
+		return schemapb.DataType_None, false
+	}
+	return dataType, true
+}
+
+func castJSONPathValue(dataType schemapb.DataType, columnInfo *planpb.ColumnInfo, value *planpb.GenericValue) (*planpb.GenericValue, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch dataType {
+	case schemapb.DataType_Bool:
+		if IsBool(value) {
+			return value, nil
+		}
+	case schemapb.DataType_Int64:
+		// integers may still be compared with the floating values.
+		if IsNumber(value) {
+			return value, nil
+		}
+	case schemapb.DataType_Double:
+		if IsFloating(value) {
+			return value, nil
+		}
+		if IsInteger(value) {
+			return NewFloat(float64(value.GetInt64Val())), nil
+		}
+	case schemapb.DataType_VarChar:
+		if IsString(value) {
+			return value, nil
+		}
+	case schemapb.DataType_Array:
+		if IsArray(value) {
+			return value, nil
+		}
+	default:
+		return value, nil
+	}
+	return nil, fmt.Errorf("json path %v is declared as %s by the json schema, can't be compared with %s",
+		columnInfo.GetNestedPath(), dataType.String(), value)
+}
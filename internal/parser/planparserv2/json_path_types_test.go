@@ -0,0 +1,133 @@
+package planparserv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func newJSONPathTypesSchemaHelper(t *testing.T) *typeutil.SchemaHelper {
+	schema := &schemapb.CollectionSchema{
+		Name: "test",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "id", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{
+				FieldID: 101, Name: "attrs", DataType: schemapb.DataType_JSON,
+				TypeParams: []*commonpb.KeyValuePair{{
+					Key:   common.JSONPathTypesKey,
+					Value: `{"price": "double", "stock": "int64", "color": "varchar", "tags": "array_varchar", "meta.active": "bool"}`,
+				}},
+			},
+			{
+				FieldID: 102, Name: "doc", DataType: schemapb.DataType_JSON,
+				TypeParams: []*commonpb.KeyValuePair{{
+					Key:   common.JSONSchemaKey,
+					Value: `{"properties": {"scores": {"type": "array", "items": {"type": "number"}}, "any": {}}}`,
+				}},
+			},
+			{FieldID: 103, Name: "plain", DataType: schemapb.DataType_JSON},
+		},
+	}
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+	return helper
+}
+
+func TestCastJSONPathValues(t *testing.T) {
+	helper := newJSONPathTypesSchemaHelper(t)
+	parse := func(exprStr string, templateValues map[string]*schemapb.TemplateValue) (*planpb.Expr, error) {
+		return parseExprWithoutRewrite(helper, exprStr, templateValues, &ParserVisitorArgs{})
+	}
+
+	t.Run("unary range", func(t *testing.T) {
+		expr, err := parse(`attrs["price"] > 10`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, expr.GetUnaryRangeExpr().GetValue().GetFloatVal())
+
+		// integers are not casted to be compared with floating values
+		expr, err = parse(`attrs["stock"] >= 1.5`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1.5, expr.GetUnaryRangeExpr().GetValue().GetFloatVal())
+
+		expr, err = parse(`attrs["meta"]["active"] == true`, nil)
+		require.NoError(t, err)
+		assert.True(t, expr.GetUnaryRangeExpr().GetValue().GetBoolVal())
+
+		// the paths not declared are planned as usual
+		expr, err = parse(`attrs["other"] > 10`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), expr.GetUnaryRangeExpr().GetValue().GetInt64Val())
+
+		expr, err = parse(`plain["price"] > 10`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), expr.GetUnaryRangeExpr().GetValue().GetInt64Val())
+
+		expr, err = parse(`doc["any"] > 10`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), expr.GetUnaryRangeExpr().GetValue().GetInt64Val())
+	})
+
+	t.Run("binary range and term", func(t *testing.T) {
+		expr, err := parse(`1 < attrs["price"] < 2.5`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, expr.GetBinaryRangeExpr().GetLowerValue().GetFloatVal())
+		assert.Equal(t, 2.5, expr.GetBinaryRangeExpr().GetUpperValue().GetFloatVal())
+
+		expr, err = parse(`attrs["price"] in [1, 2.5] and attrs["color"] in ["red"]`, nil)
+		require.NoError(t, err)
+		values := expr.GetBinaryExpr().GetLeft().GetTermExpr().GetValues()
+		require.Len(t, values, 2)
+		assert.Equal(t, 1.0, values[0].GetFloatVal())
+		assert.Equal(t, 2.5, values[1].GetFloatVal())
+	})
+
+	t.Run("template values", func(t *testing.T) {
+		expr, err := parse(`attrs["price"] > {p}`, map[string]*schemapb.TemplateValue{
+			"p": {Val: &schemapb.TemplateValue_Int64Val{Int64Val: 10}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, expr.GetUnaryRangeExpr().GetValue().GetFloatVal())
+
+		_, err = parse(`attrs["color"] == {c}`, map[string]*schemapb.TemplateValue{
+			"c": {Val: &schemapb.TemplateValue_Int64Val{Int64Val: 10}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("json contains", func(t *testing.T) {
+		expr, err := parse(`json_contains_any(attrs["tags"], ["a", "b"])`, nil)
+		require.NoError(t, err)
+		assert.True(t, expr.GetJsonContainsExpr().GetElementsSameType())
+
+		expr, err = parse(`json_contains(doc["scores"], 1)`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, expr.GetJsonContainsExpr().GetElements()[0].GetFloatVal())
+		assert.True(t, expr.GetJsonContainsExpr().GetElementsSameType())
+
+		_, err = parse(`json_contains(attrs["tags"], 1)`, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("mismatched types", func(t *testing.T) {
+		invalid := []string{
+			`attrs["color"] == 1`,
+			`attrs["price"] == "1"`,
+			`attrs["stock"] in ["a"]`,
+			`attrs["meta"]["active"] == 1`,
+			`1 < attrs["color"] < 2`,
+			`not (attrs["price"] > "a")`,
+			`attrs["tags"] == 1`,
+		}
+		for _, exprStr := range invalid {
+			_, err := ParseExpr(helper, exprStr, nil)
+			assert.Error(t, err, exprStr)
+		}
+	})
+}
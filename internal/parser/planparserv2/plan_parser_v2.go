@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/golang-lru/v2/expirable"
 	"github.com/samber/lo"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
@@ -127,6 +128,14 @@ func handleExpr(schema *typeutil.SchemaHelper, exprStr string) (result interface
 }
 
 func parseExprInner(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue, visitorArgs *ParserVisitorArgs) (*planpb.Expr, error) {
+	expr, err := parseExprWithoutRewrite(schema, exprStr, exprTemplateValues, visitorArgs)
+	if err != nil {
+		return nil, err
+	}
+	return rewriter.RewriteExpr(expr), nil
+}
+
+func parseExprWithoutRewrite(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue, visitorArgs *ParserVisitorArgs) (*planpb.Expr, error) {
 	ret := handleExprInternal(schema, exprStr, visitorArgs)
 
 	if err := getError(ret); err != nil {
@@ -150,10 +159,26 @@ func parseExprInner(schema *typeutil.SchemaHelper, exprStr string, exprTemplateV
 		return nil, err
 	}
 
-	predicate.expr = rewriter.RewriteExpr(predicate.expr)
+	if err := castJSONPathValues(schema, predicate.expr); err != nil {
+		return nil, err
+	}
+
 	return predicate.expr, nil
 }
 
+// ExplainExpr parses the expression and shows the expression tree before and after it's rewritten by the optimizer.
+func ExplainExpr(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue, visitorArgs *ParserVisitorArgs) (parsed interface{}, rewritten interface{}, err error) {
+	expr, err := parseExprWithoutRewrite(schema, exprStr, exprTemplateValues, visitorArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	v := &ShowExprVisitor{}
+	// the rewriter may modify the expression in place, so show the parsed one first.
+	parsed = v.VisitExpr(proto.Clone(expr).(*planpb.Expr))
+	rewritten = v.VisitExpr(rewriter.RewriteExpr(expr))
+	return parsed, rewritten, nil
+}
+
 func ParseExpr(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue) (*planpb.Expr, error) {
 	return parseExprInner(schema, exprStr, exprTemplateValues, &ParserVisitorArgs{})
 }
@@ -181,6 +206,26 @@ func ParseIdentifier(schema *typeutil.SchemaHelper, identifier string, checkFunc
 	return parseIdentifierInner(schema, identifier, checkFunc, visitorArgs)
 }
 
+// ParseValueExpr parses the expression computing a value from the fields, e.g. `lower(title)` or `json["a"]["b"]`,
+// the result isn't required to be boolean and the expression isn't rewritten.
+func ParseValueExpr(schema *typeutil.SchemaHelper, exprStr string) (*planpb.Expr, error) {
+	if isEmptyExpression(exprStr) {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	ret := handleExprInternal(schema, exprStr, &ParserVisitorArgs{})
+	if err := getError(ret); err != nil {
+		return nil, fmt.Errorf("cannot parse expression: %s, error: %s", exprStr, err)
+	}
+	expr := getExpr(ret)
+	if expr == nil {
+		return nil, fmt.Errorf("cannot parse expression: %s", exprStr)
+	}
+	if expr.expr.GetIsTemplate() || isTemplateExpr(expr.expr.GetValueExpr()) {
+		return nil, fmt.Errorf("placeholder is not supported in expression: %s", exprStr)
+	}
+	return expr.expr, nil
+}
+
 func CreateRetrievePlanArgs(schema *typeutil.SchemaHelper, exprStr string, exprTemplateValues map[string]*schemapb.TemplateValue, visitorArgs *ParserVisitorArgs) (*planpb.PlanNode, error) {
 	expr, err := parseExprInner(schema, exprStr, exprTemplateValues, visitorArgs)
 	if err != nil {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/json"
 	"github.com/milvus-io/milvus/internal/util/function/rerank"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
@@ -178,6 +179,28 @@ func TestExpr_Call(t *testing.T) {
 	assert.Nil(t, expr)
 }
 
+func TestParseValueExpr(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	expr, err := ParseValueExpr(helper, "lower(VarCharField)")
+	assert.NoError(t, err)
+	assert.Equal(t, "lower", expr.GetCallExpr().GetFunctionName())
+	assert.Equal(t, int64(100+schemapb.DataType_VarChar), expr.GetCallExpr().GetFunctionParameters()[0].GetColumnExpr().GetInfo().GetFieldId())
+
+	expr, err = ParseValueExpr(helper, `JSONField["a"]["b"]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, expr.GetColumnExpr().GetInfo().GetNestedPath())
+
+	expr, err = ParseValueExpr(helper, "Int64Field > 10")
+	assert.NoError(t, err)
+	assert.NotNil(t, expr.GetUnaryRangeExpr())
+
+	for _, exprStr := range []string{"", "   ", "lower(NotExistField)", "Int64Field >", "{placeholder}"} {
+		_, err = ParseValueExpr(helper, exprStr)
+		assert.Error(t, err, exprStr)
+	}
+}
+
 func TestExpr_Compare(t *testing.T) {
 	schema := newTestSchema(true)
 	helper, err := typeutil.CreateSchemaHelper(schema)
@@ -3452,3 +3475,21 @@ func TestExpr_BooleanLiteral(t *testing.T) {
 		assert.NotNil(t, ue.GetChild().GetAlwaysTrueExpr())
 	})
 }
+
+func TestExplainExpr(t *testing.T) {
+	helper := newTestSchemaHelper(t)
+
+	parsed, rewritten, err := ExplainExpr(helper, "Int64Field == 1 or Int64Field == 2", nil, &ParserVisitorArgs{})
+	assert.NoError(t, err)
+	parsedJSON, err := json.Marshal(parsed)
+	assert.NoError(t, err)
+	rewrittenJSON, err := json.Marshal(rewritten)
+	assert.NoError(t, err)
+	// the equal predicates on the same field are rewritten into a term expression.
+	assert.Contains(t, string(parsedJSON), "LogicalOr")
+	assert.Contains(t, string(rewrittenJSON), `"expr_type":"term"`)
+	assert.NotContains(t, string(rewrittenJSON), "LogicalOr")
+
+	_, _, err = ExplainExpr(helper, "not_exist_field > 1", nil, &ParserVisitorArgs{})
+	assert.Error(t, err)
+}
@@ -390,3 +390,11 @@ func (i *GrpcAccessInfo) PartialUpdate() string {
 	}
 	return NotAny
 }
+
+// RequestCost returns the resource cost of the key carried in the extra info of the response status.
+func (i *GrpcAccessInfo) RequestCost(key string) string {
+	if value, ok := i.respStatus().GetExtraInfo()[key]; ok {
+		return value
+	}
+	return NotAny
+}
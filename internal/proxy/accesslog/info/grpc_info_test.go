@@ -36,6 +36,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proxy/connection"
+	"github.com/milvus-io/milvus/internal/util/costutil"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/util"
 	"github.com/milvus-io/milvus/pkg/v2/util/crypto"
@@ -227,6 +228,16 @@ func (s *GrpcAccessInfoSuite) TestPartialUpdate() {
 	s.Equal("true", Get(s.info, "$partial_update")[0])
 }
 
+func (s *GrpcAccessInfoSuite) TestRequestCost() {
+	s.Equal(NotAny, Get(s.info, "$cost_segment_rows")[0])
+
+	status := merr.Success()
+	cost := costutil.Cost{SegmentRows: 100, SegmentsTouched: 2, SegmentTimeUs: 30}
+	cost.Attach(status)
+	s.info.resp = &milvuspb.SearchResults{Status: status}
+	s.Equal([]any{"100", "2", "0", "30"}, Get(s.info, "$cost_segment_rows", "$cost_segments_touched", "$cost_disk_bytes", "$cost_segment_time_us"))
+}
+
 func (s *GrpcAccessInfoSuite) TestConsistencyLevel() {
 	result := Get(s.info, "$consistency_level")
 	s.Equal(Unknown, result[0])
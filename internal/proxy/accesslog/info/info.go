@@ -16,7 +16,10 @@
 
 package info
 
-import "github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+import (
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/util/costutil"
+)
 
 const (
 	Unknown            = "Unknown"
@@ -57,6 +60,13 @@ var MetricFuncMap = map[string]getMetricFunc{
 	"$client_request_time":   getClientRequestTime,
 	"$template_value_length": getTemplateValueLength,
 	"$partial_update":        getPartialUpdate,
+
+	"$" + costutil.SegmentRowsKey:          getRequestCost(costutil.SegmentRowsKey),
+	"$" + costutil.SegmentsTouchedKey:      getRequestCost(costutil.SegmentsTouchedKey),
+	"$" + costutil.CacheBytesKey:           getRequestCost(costutil.CacheBytesKey),
+	"$" + costutil.DiskBytesKey:            getRequestCost(costutil.DiskBytesKey),
+	"$" + costutil.DistanceComputationsKey: getRequestCost(costutil.DistanceComputationsKey),
+	"$" + costutil.SegmentTimeKey:          getRequestCost(costutil.SegmentTimeKey),
 }
 
 type AccessInfo interface {
@@ -87,6 +97,7 @@ type AccessInfo interface {
 	ClientRequestTime() string
 	TemplateValueLength() string
 	PartialUpdate() string
+	RequestCost(key string) string
 	SetActualConsistencyLevel(commonpb.ConsistencyLevel)
 }
 
@@ -214,3 +225,9 @@ func getTemplateValueLength(i AccessInfo) string {
 func getPartialUpdate(i AccessInfo) string {
 	return i.PartialUpdate()
 }
+
+func getRequestCost(key string) getMetricFunc {
+	return func(i AccessInfo) string {
+		return i.RequestCost(key)
+	}
+}
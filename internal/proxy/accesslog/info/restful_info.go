@@ -37,6 +37,8 @@ const (
 	ContextReturnMessage = "message"
 	ContextRequest       = "request"
 	ContextToken         = "token"
+	// ContextResponseStatus is the status of the response returned by the proxy.
+	ContextResponseStatus = "response_status"
 )
 
 type RestfulInfo struct {
@@ -294,3 +296,19 @@ func (i *RestfulInfo) PartialUpdate() string {
 	}
 	return NotAny
 }
+
+// RequestCost returns the resource cost of the key carried in the extra info of the response status.
+func (i *RestfulInfo) RequestCost(key string) string {
+	value, ok := i.ctx.Get(ContextResponseStatus)
+	if !ok {
+		return NotAny
+	}
+	status, ok := value.(*commonpb.Status)
+	if !ok {
+		return NotAny
+	}
+	if cost, ok := status.GetExtraInfo()[key]; ok {
+		return cost
+	}
+	return NotAny
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/util/costutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
 )
@@ -221,6 +222,16 @@ func (s *RestfulAccessInfoSuite) TestClusterPrefix() {
 	s.Equal(cluster, result[0])
 }
 
+func (s *RestfulAccessInfoSuite) TestRequestCost() {
+	s.Equal(NotAny, Get(s.info, "$cost_segment_rows")[0])
+
+	status := merr.Success()
+	cost := costutil.Cost{SegmentRows: 100, DistanceComputations: 800}
+	cost.Attach(status)
+	s.ctx.Set(ContextResponseStatus, status)
+	s.Equal([]any{"100", "800"}, Get(s.info, "$cost_segment_rows", "$cost_distance_computations"))
+}
+
 func (s *RestfulAccessInfoSuite) TestNQ() {
 	nq := int64(10)
 	s.Equal(Unknown, Get(s.info, "$nq")[0])
@@ -23,6 +23,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"sort"
 	"sync"
 	"time"
 
@@ -162,28 +163,55 @@ type RotateWriter struct {
 	closeOnce sync.Once
 }
 
+// RotateConfig is the config of the rotated file writer.
+type RotateConfig struct {
+	LocalPath   string
+	Filename    string
+	RotatedTime int64
+	MaxSize     int
+	MaxBackups  int
+
+	MinioEnable bool
+	RemotePath  string
+	// the retention time of the files uploaded to minIO, in hours
+	RemoteMaxTime int
+}
+
 func NewRotateWriter(logCfg *paramtable.AccessLogConfig, minioCfg *paramtable.MinioConfig) (*RotateWriter, error) {
+	return NewRotateWriterWithConfig(RotateConfig{
+		LocalPath:     logCfg.LocalPath.GetValue(),
+		Filename:      logCfg.Filename.GetValue(),
+		RotatedTime:   logCfg.RotatedTime.GetAsInt64(),
+		MaxSize:       logCfg.MaxSize.GetAsInt(),
+		MaxBackups:    logCfg.MaxBackups.GetAsInt(),
+		MinioEnable:   logCfg.MinioEnable.GetAsBool(),
+		RemotePath:    logCfg.RemotePath.GetValue(),
+		RemoteMaxTime: logCfg.RemoteMaxTime.GetAsInt(),
+	}, minioCfg)
+}
+
+func NewRotateWriterWithConfig(cfg RotateConfig, minioCfg *paramtable.MinioConfig) (*RotateWriter, error) {
 	logger := &RotateWriter{
-		localPath:   logCfg.LocalPath.GetValue(),
-		fileName:    logCfg.Filename.GetValue(),
-		rotatedTime: logCfg.RotatedTime.GetAsInt64(),
-		maxSize:     logCfg.MaxSize.GetAsInt(),
-		maxBackups:  logCfg.MaxBackups.GetAsInt(),
+		localPath:   cfg.LocalPath,
+		fileName:    cfg.Filename,
+		rotatedTime: cfg.RotatedTime,
+		maxSize:     cfg.MaxSize,
+		maxBackups:  cfg.MaxBackups,
 		closeCh:     make(chan struct{}),
 	}
-	log.Info("Access log save to " + logger.dir())
-	if logCfg.MinioEnable.GetAsBool() {
+	log.Info("Rotated log save to " + logger.dir())
+	if cfg.MinioEnable {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		log.Info("Access log will backup files to minio", zap.String("remote", logCfg.RemotePath.GetValue()), zap.String("maxBackups", logCfg.MaxBackups.GetValue()))
-		handler, err := NewMinioHandler(ctx, minioCfg, logCfg.RemotePath.GetValue(), logCfg.MaxBackups.GetAsInt())
+		log.Info("Rotated log will backup files to minio", zap.String("remote", cfg.RemotePath), zap.Int("maxBackups", cfg.MaxBackups))
+		handler, err := NewMinioHandler(ctx, minioCfg, cfg.RemotePath, cfg.MaxBackups)
 		if err != nil {
 			return nil, err
 		}
 		prefix, ext := logger.prefixAndExt()
-		if logCfg.RemoteMaxTime.GetAsInt() > 0 {
-			handler.retentionPolicy = getTimeRetentionFunc(logCfg.RemoteMaxTime.GetAsInt(), prefix, ext)
+		if cfg.RemoteMaxTime > 0 {
+			handler.retentionPolicy = getTimeRetentionFunc(cfg.RemoteMaxTime, prefix, ext)
 		}
 
 		logger.handler = handler
@@ -447,6 +475,23 @@ func (l *RotateWriter) oldLogFiles() ([]logInfo, error) {
 	return logFiles, nil
 }
 
+// LogFiles returns the paths of the sealed local log files from the oldest to the newest,
+// followed by the path of the log file being written.
+func (l *RotateWriter) LogFiles() ([]string, error) {
+	files, err := l.oldLogFiles()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].timestamp.Before(files[j].timestamp)
+	})
+	paths := make([]string, 0, len(files)+1)
+	for _, f := range files {
+		paths = append(paths, path.Join(l.dir(), f.fileName))
+	}
+	return append(paths, l.filename()), nil
+}
+
 type logInfo struct {
 	timestamp time.Time
 	fileName  string
@@ -315,3 +315,30 @@ func TestCacheWriter_WithAutoFlush(t *testing.T) {
 	writer.Close()
 	assert.True(t, buffer.closed)
 }
+
+func TestRotateWriter_LogFiles(t *testing.T) {
+	testPath := t.TempDir()
+	logger, err := NewRotateWriterWithConfig(RotateConfig{
+		LocalPath: testPath,
+		Filename:  "test_files.log",
+		MaxSize:   1,
+	}, nil)
+	require.NoError(t, err)
+	defer logger.Close()
+
+	for i := 0; i < 3; i++ {
+		_, err = logger.Write([]byte("test\n"))
+		require.NoError(t, err)
+		require.NoError(t, logger.Rotate())
+		// the sealed files are named by the time of rotation in milliseconds.
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	files, err := logger.LogFiles()
+	assert.NoError(t, err)
+	assert.Len(t, files, 4)
+	assert.Equal(t, path.Join(testPath, "test_files.log"), files[3])
+	for i := 1; i < 3; i++ {
+		assert.Less(t, files[i-1], files[i])
+	}
+}
@@ -0,0 +1,152 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package capture writes the sampled requests received by the proxy into the rolling workload files,
+// which can be replayed against another cluster by the milvus replay command.
+package capture
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/internal/proxy/accesslog"
+	"github.com/milvus-io/milvus/internal/util/workload"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+var globalRecorder atomic.Pointer[Recorder]
+
+// Done writes the captured request with its response.
+type Done func(resp any, err error)
+
+func noop(resp any, err error) {}
+
+// Recorder samples the requests and writes them into the rotated workload files.
+type Recorder struct {
+	cfg    *paramtable.WorkloadCaptureConfig
+	writer *accesslog.RotateWriter
+}
+
+// NewRecorder creates a recorder writing the workload files configured by cfg,
+// which are uploaded to the object storage if configured.
+func NewRecorder(cfg *paramtable.WorkloadCaptureConfig, minioCfg *paramtable.MinioConfig) (*Recorder, error) {
+	writer, err := accesslog.NewRotateWriterWithConfig(accesslog.RotateConfig{
+		LocalPath:     cfg.LocalPath.GetValue(),
+		Filename:      cfg.Filename.GetValue(),
+		RotatedTime:   cfg.RotatedTime.GetAsInt64(),
+		MaxSize:       cfg.MaxSize.GetAsInt(),
+		MaxBackups:    cfg.MaxBackups.GetAsInt(),
+		MinioEnable:   cfg.MinioEnable.GetAsBool(),
+		RemotePath:    cfg.RemotePath.GetValue(),
+		RemoteMaxTime: cfg.RemoteMaxTime.GetAsInt(),
+	}, minioCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		cfg:    cfg,
+		writer: writer,
+	}, nil
+}
+
+// InitRecorder starts capturing the requests if enabled.
+func InitRecorder(cfg *paramtable.WorkloadCaptureConfig, minioCfg *paramtable.MinioConfig) error {
+	if !cfg.Enable.GetAsBool() {
+		return nil
+	}
+	r, err := NewRecorder(cfg, minioCfg)
+	if err != nil {
+		return err
+	}
+	if old := globalRecorder.Swap(r); old != nil {
+		old.Close()
+	}
+	log.Info("workload capture enabled", zap.String("path", cfg.LocalPath.GetValue()),
+		zap.Float64("sampleRate", cfg.SampleRate.GetAsFloat()))
+	return nil
+}
+
+// Close stops capturing the requests.
+func Close() {
+	if r := globalRecorder.Swap(nil); r != nil {
+		r.Close()
+	}
+}
+
+// Capture samples the request of the full grpc method,
+// the returned function must be called with the response to write the captured request.
+func Capture(fullMethod string, user string, req any) Done {
+	r := globalRecorder.Load()
+	if r == nil {
+		return noop
+	}
+	return r.Capture(workload.MethodOf(fullMethod), user, req)
+}
+
+func (r *Recorder) sampled(method string) bool {
+	if !lo.Contains(r.cfg.Methods.GetAsStrings(), method) {
+		return false
+	}
+	rate := r.cfg.SampleRate.GetAsFloat()
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// Capture samples the request of the method,
+// the request is encoded before it's executed as the execution may modify it.
+func (r *Recorder) Capture(method string, user string, req any) Done {
+	msg, ok := req.(proto.Message)
+	if !ok || workload.NewRequest(method) == nil || !r.sampled(method) {
+		return noop
+	}
+	start := time.Now()
+	record, err := workload.NewRecord(method, user, start, msg)
+	if err != nil {
+		log.Warn("failed to capture the request", zap.String("method", method), zap.Error(err))
+		return noop
+	}
+	// the time encoding the request isn't counted in the latency.
+	start = time.Now()
+	return func(resp any, err error) {
+		record.LatencyUs = time.Since(start).Microseconds()
+		record.Code = merr.Code(merr.CheckRPCCall(resp, err))
+		r.write(record)
+	}
+}
+
+func (r *Recorder) write(record *workload.Record) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Warn("failed to marshal the captured request", zap.String("method", record.Method), zap.Error(err))
+		return
+	}
+	// the requests larger than the max size of the workload file are dropped by the writer.
+	if _, err := r.writer.Write(append(line, '\n')); err != nil {
+		log.RatedWarn(60, "failed to write the captured request", zap.String("method", record.Method), zap.Error(err))
+	}
+}
+
+// Close closes the workload files of the recorder.
+func (r *Recorder) Close() {
+	r.writer.Close()
+}
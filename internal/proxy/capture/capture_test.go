@@ -0,0 +1,97 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capture
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/util/workload"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestCapture(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	cfg := &params.ProxyCfg.WorkloadCapture
+	dir := t.TempDir()
+	params.Save(cfg.LocalPath.Key, dir)
+	params.Save(cfg.SampleRate.Key, "1")
+	params.Save(cfg.Methods.Key, "Search,Insert")
+	defer params.Reset(cfg.LocalPath.Key)
+	defer params.Reset(cfg.SampleRate.Key)
+	defer params.Reset(cfg.Methods.Key)
+
+	// nothing is captured if disabled
+	require.NoError(t, InitRecorder(cfg, &params.MinioCfg))
+	assert.Nil(t, globalRecorder.Load())
+	Capture("/milvus.proto.milvus.MilvusService/Search", "root", &milvuspb.SearchRequest{})(nil, nil)
+
+	params.Save(cfg.Enable.Key, "true")
+	defer params.Reset(cfg.Enable.Key)
+	require.NoError(t, InitRecorder(cfg, &params.MinioCfg))
+
+	search := &milvuspb.SearchRequest{DbName: "db1", CollectionName: "coll", Dsl: "id > 0"}
+	done := Capture("/milvus.proto.milvus.MilvusService/Search", "root", search)
+	// the request modified during the execution is captured as received
+	search.Dsl = "id > 1"
+	done(&milvuspb.SearchResults{Status: merr.Success()}, nil)
+
+	Capture("/milvus.proto.milvus.MilvusService/Insert", "root", &milvuspb.InsertRequest{CollectionName: "coll"})(
+		&milvuspb.MutationResult{Status: merr.Status(merr.WrapErrCollectionNotFound("coll"))}, nil)
+	// the methods not configured are skipped
+	Capture("/milvus.proto.milvus.MilvusService/Query", "root", &milvuspb.QueryRequest{CollectionName: "coll"})(nil, nil)
+	Capture("/milvus.proto.milvus.MilvusService/Flush", "root", &milvuspb.FlushRequest{})(nil, nil)
+	Close()
+
+	reader, err := workload.OpenRecords(dir)
+	require.NoError(t, err)
+	defer reader.Close()
+	records := make([]*workload.Record, 0)
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+
+	assert.Equal(t, workload.MethodSearch, records[0].Method)
+	assert.Equal(t, "root", records[0].User)
+	assert.Equal(t, int32(0), records[0].Code)
+	req, err := records[0].DecodeRequest()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&milvuspb.SearchRequest{DbName: "db1", CollectionName: "coll", Dsl: "id > 0"}, req))
+
+	assert.Equal(t, workload.MethodInsert, records[1].Method)
+	assert.Equal(t, merr.Code(merr.ErrCollectionNotFound), records[1].Code)
+
+	// sampled out
+	params.Save(cfg.SampleRate.Key, "0")
+	r, err := NewRecorder(cfg, &params.MinioCfg)
+	require.NoError(t, err)
+	defer r.Close()
+	assert.False(t, r.sampled(workload.MethodSearch))
+}
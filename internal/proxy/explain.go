@@ -0,0 +1,171 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/explainutil"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const (
+	explainUsageFilter = "filter"
+	explainUsageSearch = "search"
+)
+
+// explainExpr records the filter expression before and after it's rewritten by the optimizer.
+func explainExpr(collector *explainutil.Collector, schemaHelper *typeutil.SchemaHelper, expr string, exprTemplateValues map[string]*schemapb.TemplateValue, visitorArgs *planparserv2.ParserVisitorArgs) error {
+	if collector == nil {
+		return nil
+	}
+	parsed, rewritten, err := planparserv2.ExplainExpr(schemaHelper, expr, exprTemplateValues, visitorArgs)
+	if err != nil {
+		return err
+	}
+	collector.AddExpr(&explainutil.ExprExplanation{
+		Expr:      expr,
+		Parsed:    parsed,
+		Rewritten: rewritten,
+	})
+	return nil
+}
+
+// explainIndexes records the index used by every field searched or filtered by the plans.
+func explainIndexes(ctx context.Context, collector *explainutil.Collector, mixCoord types.MixCoordClient, schema *schemapb.CollectionSchema, collectionID int64, plans ...*planpb.PlanNode) error {
+	if collector == nil || len(plans) == 0 {
+		return nil
+	}
+	resp, err := mixCoord.DescribeIndex(ctx, &indexpb.DescribeIndexRequest{
+		CollectionID: collectionID,
+	})
+	if err = merr.CheckRPCCall(resp, err); err != nil && !errors.Is(err, merr.ErrIndexNotFound) {
+		return err
+	}
+	indexes := make(map[int64]*indexpb.IndexInfo, len(resp.GetIndexInfos()))
+	for _, index := range resp.GetIndexInfos() {
+		indexes[index.GetFieldID()] = index
+	}
+
+	usages := make(map[int64]string)
+	for _, plan := range plans {
+		if anns := plan.GetVectorAnns(); anns != nil {
+			usages[anns.GetFieldId()] = explainUsageSearch
+		}
+		filterFields := typeutil.NewSet[int64]()
+		collectColumnFieldIDs(plan.GetVectorAnns().GetPredicates().ProtoReflect(), filterFields)
+		collectColumnFieldIDs(plan.GetQuery().GetPredicates().ProtoReflect(), filterFields)
+		for fieldID := range filterFields {
+			if _, ok := usages[fieldID]; !ok {
+				usages[fieldID] = explainUsageFilter
+			}
+		}
+	}
+
+	fieldIDs := make([]int64, 0, len(usages))
+	for fieldID := range usages {
+		fieldIDs = append(fieldIDs, fieldID)
+	}
+	sort.Slice(fieldIDs, func(i, j int) bool { return fieldIDs[i] < fieldIDs[j] })
+	fieldIndexes := make([]*explainutil.FieldIndex, 0, len(fieldIDs))
+	for _, fieldID := range fieldIDs {
+		fieldIndex := &explainutil.FieldIndex{
+			FieldID: fieldID,
+			Usage:   usages[fieldID],
+		}
+		if field := typeutil.GetField(schema, fieldID); field != nil {
+			fieldIndex.FieldName = field.GetName()
+		}
+		if index, ok := indexes[fieldID]; ok {
+			fieldIndex.IndexName = index.GetIndexName()
+			fieldIndex.IndexType = explainIndexType(index.GetIndexParams(), index.GetUserIndexParams())
+		}
+		fieldIndexes = append(fieldIndexes, fieldIndex)
+	}
+	collector.AddIndexes(fieldIndexes...)
+	return nil
+}
+
+func explainIndexType(kvs ...[]*commonpb.KeyValuePair) string {
+	for _, params := range kvs {
+		if indexType, ok := funcutil.TryGetAttrByKeyFromRepeatedKV(common.IndexTypeKey, params); ok {
+			return indexType
+		}
+	}
+	return ""
+}
+
+// collectColumnFieldIDs collects the field ids of all columns referenced by the expression.
+func collectColumnFieldIDs(m protoreflect.Message, fieldIDs typeutil.Set[int64]) {
+	if !m.IsValid() {
+		return
+	}
+	if info, ok := m.Interface().(*planpb.ColumnInfo); ok {
+		fieldIDs.Insert(info.GetFieldId())
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					collectColumnFieldIDs(mv.Message(), fieldIDs)
+					return true
+				})
+			}
+		case fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind:
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				collectColumnFieldIDs(list.Get(i).Message(), fieldIDs)
+			}
+		default:
+			collectColumnFieldIDs(v.Message(), fieldIDs)
+		}
+		return true
+	})
+}
+
+// SetExplanation sets the explanation of the explained search or query request into the extra info of status.
+func SetExplanation(status *commonpb.Status, collector *explainutil.Collector) {
+	if collector == nil || !merr.Ok(status) {
+		return
+	}
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+		// keep the default report_value for compatibility, see SetStorageCost.
+		status.ExtraInfo["report_value"] = "0"
+	}
+	if err := collector.Attach(status); err != nil {
+		log.Warn("failed to attach the explanation", zap.Error(err))
+	}
+}
@@ -0,0 +1,114 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
+	"github.com/milvus-io/milvus/internal/util/explainutil"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/indexpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+func newExplainTestSchema() *schemapb.CollectionSchema {
+	return &schemapb.CollectionSchema{
+		Name: "explain",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", IsPrimaryKey: true, DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "vec", DataType: schemapb.DataType_FloatVector, TypeParams: []*commonpb.KeyValuePair{{Key: common.DimKey, Value: "4"}}},
+			{FieldID: 102, Name: "age", DataType: schemapb.DataType_Int64},
+			{FieldID: 103, Name: "name", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "64"}}},
+		},
+	}
+}
+
+func TestExplainExprAndIndexes(t *testing.T) {
+	paramtable.Init()
+	schema := newExplainTestSchema()
+	helper, err := typeutil.CreateSchemaHelper(schema)
+	require.NoError(t, err)
+
+	// nothing is explained without the collector.
+	assert.NoError(t, explainExpr(nil, helper, "age > 1", nil, &planparserv2.ParserVisitorArgs{}))
+	assert.NoError(t, explainIndexes(context.Background(), nil, nil, schema, 1))
+
+	collector := explainutil.NewCollector(explainutil.ModeExplain)
+	expr := `age > 1 and name == "a"`
+	assert.NoError(t, explainExpr(collector, helper, expr, nil, &planparserv2.ParserVisitorArgs{}))
+	assert.Error(t, explainExpr(collector, helper, "unknown > 1", nil, &planparserv2.ParserVisitorArgs{}))
+
+	plan, err := planparserv2.CreateSearchPlan(helper, expr, "vec", &planpb.QueryInfo{Topk: 10, MetricType: "L2"}, nil, nil)
+	require.NoError(t, err)
+
+	mixCoord := mocks.NewMockMixCoordClient(t)
+	mixCoord.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(&indexpb.DescribeIndexResponse{
+		Status: merr.Success(),
+		IndexInfos: []*indexpb.IndexInfo{
+			{FieldID: 101, IndexName: "vec_index", IndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "HNSW"}}},
+			{FieldID: 102, IndexName: "age_index", UserIndexParams: []*commonpb.KeyValuePair{{Key: common.IndexTypeKey, Value: "STL_SORT"}}},
+		},
+	}, nil).Once()
+	assert.NoError(t, explainIndexes(context.Background(), collector, mixCoord, schema, 1, plan))
+
+	explanation := collector.Explanation()
+	assert.Len(t, explanation.Exprs, 1)
+	assert.Equal(t, expr, explanation.Exprs[0].Expr)
+	assert.Equal(t, []*explainutil.FieldIndex{
+		{FieldID: 101, FieldName: "vec", Usage: explainUsageSearch, IndexName: "vec_index", IndexType: "HNSW"},
+		{FieldID: 102, FieldName: "age", Usage: explainUsageFilter, IndexName: "age_index", IndexType: "STL_SORT"},
+		{FieldID: 103, FieldName: "name", Usage: explainUsageFilter},
+	}, explanation.Indexes)
+
+	// the collection without any index is scanned by brute force.
+	mixCoord.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(&indexpb.DescribeIndexResponse{
+		Status: merr.Status(merr.WrapErrIndexNotFoundForCollection("explain")),
+	}, nil).Once()
+	collector = explainutil.NewCollector(explainutil.ModeExplain)
+	assert.NoError(t, explainIndexes(context.Background(), collector, mixCoord, schema, 1, plan))
+	assert.Len(t, collector.Explanation().Indexes, 3)
+	assert.Empty(t, collector.Explanation().Indexes[0].IndexName)
+
+	mixCoord.EXPECT().DescribeIndex(mock.Anything, mock.Anything).Return(nil, merr.ErrServiceNotReady).Once()
+	assert.Error(t, explainIndexes(context.Background(), collector, mixCoord, schema, 1, plan))
+}
+
+func TestSetExplanation(t *testing.T) {
+	SetExplanation(merr.Success(), nil)
+
+	collector := explainutil.NewCollector(explainutil.ModeProfile)
+	status := merr.Status(merr.ErrServiceNotReady)
+	SetExplanation(status, collector)
+	assert.Nil(t, status.GetExtraInfo())
+
+	status = merr.Success()
+	SetExplanation(status, collector)
+	assert.Equal(t, "0", status.GetExtraInfo()["report_value"])
+	assert.Contains(t, status.GetExtraInfo()[explainutil.ExtraInfoKey], `"mode":"profile"`)
+}
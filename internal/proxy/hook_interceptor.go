@@ -10,6 +10,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/milvus-io/milvus/internal/proxy/capture"
 	"github.com/milvus-io/milvus/internal/util/hookutil"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/metrics"
@@ -53,7 +54,9 @@ func HookInterceptor(ctx context.Context, req any, userName, fullMethod string,
 		// NOTE: don't use the merr, because it will cause the wrong retry behavior in the sdk
 		return nil, status.Error(codes.InvalidArgument, "detail: "+err.Error())
 	}
+	captured := capture.Capture(fullMethod, userName, req)
 	realResp, realErr = handler(newCtx, req)
+	captured(realResp, realErr)
 	if err = hoo.After(newCtx, realResp, realErr, fullMethod); err != nil {
 		log.Warn("hook after error", zap.String("user", userName), zap.String("full method", fullMethod),
 			zap.Any("request", req), zap.Error(err))
@@ -29,6 +29,7 @@ import (
 	mhttp "github.com/milvus-io/milvus/internal/http"
 	"github.com/milvus-io/milvus/internal/json"
 	"github.com/milvus-io/milvus/internal/proxy/connection"
+	"github.com/milvus-io/milvus/internal/proxy/slowlog"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/internal/util/dependency"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
@@ -159,9 +160,14 @@ func getDependencies(c *gin.Context) {
 	c.Data(http.StatusOK, contentType, ret)
 }
 
+// getSlowQuery lists the slow queries recorded by all proxies of the cluster,
+// filtered by the time range, user, database, collection and type in the query parameters.
 func getSlowQuery(node *Proxy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		slowQueries := node.slowQueries.Values()
+		slowQueries, ok := listSlowQueriesByRequest(c, node)
+		if !ok {
+			return
+		}
 		ret, err := json.Marshal(slowQueries)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
@@ -173,6 +179,42 @@ func getSlowQuery(node *Proxy) gin.HandlerFunc {
 	}
 }
 
+// getSlowQueryStats summarizes the latency percentiles of the filtered slow queries of the cluster.
+func getSlowQueryStats(node *Proxy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slowQueries, ok := listSlowQueriesByRequest(c, node)
+		if !ok {
+			return
+		}
+		ret, err := json.Marshal(slowlog.Summarize(slowQueries))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				mhttp.HTTPReturnMessage: err.Error(),
+			})
+			return
+		}
+		c.Data(http.StatusOK, contentType, ret)
+	}
+}
+
+func listSlowQueriesByRequest(c *gin.Context, node *Proxy) ([]*metricsinfo.SlowQuery, bool) {
+	filter, err := slowlog.ParseFilter(c.Query)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			mhttp.HTTPReturnMessage: err.Error(),
+		})
+		return nil, false
+	}
+	slowQueries, err := node.listClusterSlowQueries(c.Request.Context(), filter)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			mhttp.HTTPReturnMessage: err.Error(),
+		})
+		return nil, false
+	}
+	return slowQueries, true
+}
+
 // buildReqParams fetch all parameters from query parameter of URL, add them into a map data structure.
 // put key and value from query parameter into map, concatenate values with separator if values size is greater than 1
 func buildReqParams(c *gin.Context, metricsType string, customParams ...*commonpb.KeyValuePair) map[string]interface{} {
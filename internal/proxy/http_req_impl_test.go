@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/gin-gonic/gin"
@@ -14,8 +15,10 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/json"
 	"github.com/milvus-io/milvus/internal/mocks"
 	"github.com/milvus-io/milvus/internal/proxy/connection"
+	"github.com/milvus-io/milvus/internal/proxy/slowlog"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
 	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
@@ -132,6 +135,56 @@ func TestBuildReqParams(t *testing.T) {
 	assert.Equal(t, "value2,value3", params["key2"])
 }
 
+func TestGetSlowQuery(t *testing.T) {
+	paramtable.Init()
+	node := &Proxy{slowQueries: slowlog.NewRecorder(10)}
+	now := time.Now()
+	for i, collection := range []string{"c1", "c2", "c1"} {
+		node.slowQueries.Record(&metricsinfo.SlowQuery{
+			Collection: collection,
+			User:       "root",
+			Timestamp:  now.Add(time.Duration(i) * time.Second).UnixMilli(),
+			DurationMs: float64(i+1) * 10,
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/?collection_name=c1&user=root", nil)
+		getSlowQuery(node)(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		entries := make([]*metricsinfo.SlowQuery, 0)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		assert.Len(t, entries, 2)
+		assert.Equal(t, 30.0, entries[0].DurationMs)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/", nil)
+		getSlowQueryStats(node)(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		stats := &metricsinfo.SlowQueryStats{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), stats))
+		assert.Equal(t, 3, stats.Total.Count)
+		assert.Equal(t, 20.0, stats.Total.P50Ms)
+		assert.Equal(t, 2, stats.Collections[".c1"].Count)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/?start_time=yesterday", nil)
+		getSlowQueryStats(node)(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestGetQueryComponentMetrics(t *testing.T) {
 	t.Run("get metrics failed", func(t *testing.T) {
 		w := httptest.NewRecorder()
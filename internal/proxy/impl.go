@@ -3360,9 +3360,7 @@ func (node *Proxy) search(ctx context.Context, request *milvuspb.SearchRequest,
 			if sp != nil {
 				traceID = sp.SpanContext().TraceID().String()
 			}
-			if node.slowQueries != nil {
-				node.slowQueries.Add(qt.BeginTs(), metricsinfo.NewSlowQueryWithSearchRequest(request, user, span, traceID))
-			}
+			node.recordSlowQuery(metricsinfo.NewSlowQueryWithSearchRequest(request, user, span, traceID), qt)
 			metrics.ProxySlowQueryCount.WithLabelValues(
 				strconv.FormatInt(paramtable.GetNodeID(), 10),
 				metrics.SearchLabel,
@@ -3461,6 +3459,8 @@ func (node *Proxy) search(ctx context.Context, request *milvuspb.SearchRequest,
 		})
 		SetReportValue(qt.result.GetStatus(), v)
 		SetStorageCost(qt.result.GetStatus(), qt.storageCost)
+		SetRequestCost(qt.result.GetStatus(), qt.requestCost, qt.storageCost, hookutil.OpTypeSearch, dbName, username)
+		SetExplanation(qt.result.GetStatus(), qt.explainCollector)
 		if merr.Ok(qt.result.GetStatus()) {
 			metrics.ProxyReportValue.WithLabelValues(nodeID, hookutil.OpTypeSearch, dbName, username).Add(float64(v))
 		}
@@ -3602,9 +3602,7 @@ func (node *Proxy) hybridSearch(ctx context.Context, request *milvuspb.HybridSea
 			if sp != nil {
 				traceID = sp.SpanContext().TraceID().String()
 			}
-			if node.slowQueries != nil {
-				node.slowQueries.Add(qt.BeginTs(), metricsinfo.NewSlowQueryWithSearchRequest(newSearchReq, user, span, traceID))
-			}
+			node.recordSlowQuery(metricsinfo.NewSlowQueryWithSearchRequest(newSearchReq, user, span, traceID), qt)
 			metrics.ProxySlowQueryCount.WithLabelValues(
 				strconv.FormatInt(paramtable.GetNodeID(), 10),
 				metrics.HybridSearchLabel,
@@ -3702,6 +3700,8 @@ func (node *Proxy) hybridSearch(ctx context.Context, request *milvuspb.HybridSea
 		})
 		SetReportValue(qt.result.GetStatus(), v)
 		SetStorageCost(qt.result.GetStatus(), qt.storageCost)
+		SetRequestCost(qt.result.GetStatus(), qt.requestCost, qt.storageCost, hookutil.OpTypeHybridSearch, dbName, username)
+		SetExplanation(qt.result.GetStatus(), qt.explainCollector)
 		if merr.Ok(qt.result.GetStatus()) {
 			metrics.ProxyReportValue.WithLabelValues(nodeID, hookutil.OpTypeHybridSearch, dbName, username).Add(float64(v))
 		}
@@ -4084,8 +4084,8 @@ func (node *Proxy) query(ctx context.Context, qt *queryTask, sp trace.Span) (*mi
 			if sp != nil {
 				traceID = sp.SpanContext().TraceID().String()
 			}
-			if node.slowQueries != nil && queryLabel == metrics.QueryLabel {
-				node.slowQueries.Add(qt.BeginTs(), metricsinfo.NewSlowQueryWithQueryRequest(request, user, span, traceID))
+			if queryLabel == metrics.QueryLabel {
+				node.recordSlowQuery(metricsinfo.NewSlowQueryWithQueryRequest(request, user, span, traceID), qt)
 			}
 			if queryLabel == metrics.QueryLabel {
 				metrics.ProxySlowQueryCount.WithLabelValues(
@@ -4228,6 +4228,8 @@ func (node *Proxy) Query(ctx context.Context, request *milvuspb.QueryRequest) (*
 	})
 	SetReportValue(res.Status, v)
 	SetStorageCost(res.Status, storageCost)
+	SetRequestCost(res.Status, qt.requestCost, storageCost, hookutil.OpTypeQuery, request.DbName, username)
+	SetExplanation(res.Status, qt.explainCollector)
 	metrics.ProxyReportValue.WithLabelValues(nodeID, hookutil.OpTypeQuery, request.DbName, username).Add(float64(v))
 
 	if log.Ctx(ctx).Core().Enabled(zap.DebugLevel) && matchCountRule(request.OutputFields) {
@@ -5021,6 +5023,24 @@ func (node *Proxy) GetProxyMetrics(ctx context.Context, req *milvuspb.GetMetrics
 		return proxyMetrics, nil
 	}
 
+	if metricType == metricsinfo.SlowQueryKey {
+		slowQueries, err := node.listSlowQueries(ret)
+		if err != nil {
+			log.Warn("Proxy.GetProxyMetrics failed to list slow queries",
+				zap.Error(err))
+
+			return &milvuspb.GetMetricsResponse{
+				Status: merr.Status(err),
+			}, nil
+		}
+
+		return &milvuspb.GetMetricsResponse{
+			Status:        merr.Success(),
+			Response:      slowQueries,
+			ComponentName: metricsinfo.ConstructComponentName(typeutil.ProxyRole, paramtable.GetNodeID()),
+		}, nil
+	}
+
 	log.Warn("Proxy.GetProxyMetrics failed, request metric type is not implemented yet",
 		zap.String("metricType", metricType))
 
@@ -6963,6 +6983,7 @@ func (node *Proxy) RegisterRestRouter(router gin.IRouter) {
 
 	// Slow query request that executed by proxy
 	router.GET(http.SlowQueryPath, getSlowQuery(node))
+	router.GET(http.SlowQueryStatsPath, getSlowQueryStats(node))
 
 	// QueryCoord requests that are forwarded from proxy
 	router.GET(http.QCTargetPath, getQueryComponentMetrics(node, metricsinfo.TargetKey))
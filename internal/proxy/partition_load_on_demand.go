@@ -0,0 +1,154 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/contextutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// LoadOnDemandWaitKey is the search and query param to wait until the partitions loaded on demand are ready,
+// the request fails fast with partition not loaded error otherwise.
+const LoadOnDemandWaitKey = "load_on_demand_wait"
+
+const loadOnDemandPollInterval = 200 * time.Millisecond
+
+var globalPartitionAccessCache = newPartitionAccessCache()
+
+// partitionAccessCache remembers the partitions reported to querycoord as loaded,
+// a partition is reported again after the report interval to keep it from being evicted.
+type partitionAccessCache struct {
+	mu       sync.Mutex
+	reported map[int64]time.Time // partitionID -> last report time
+}
+
+func newPartitionAccessCache() *partitionAccessCache {
+	return &partitionAccessCache{
+		reported: make(map[int64]time.Time),
+	}
+}
+
+// toReport returns the partitions not reported within the interval.
+func (c *partitionAccessCache) toReport(partitionIDs []int64, interval time.Duration, now time.Time) []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make([]int64, 0, len(partitionIDs))
+	for _, partitionID := range partitionIDs {
+		if reportTime, ok := c.reported[partitionID]; ok && now.Sub(reportTime) < interval {
+			continue
+		}
+		delete(c.reported, partitionID)
+		result = append(result, partitionID)
+	}
+	return result
+}
+
+func (c *partitionAccessCache) markReported(partitionIDs []int64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, partitionID := range partitionIDs {
+		c.reported[partitionID] = now
+	}
+}
+
+func isLoadOnDemandWait(params []*commonpb.KeyValuePair) (bool, error) {
+	for _, kv := range params {
+		if kv.GetKey() == LoadOnDemandWaitKey {
+			wait, err := strconv.ParseBool(kv.GetValue())
+			if err != nil {
+				return false, merr.WrapErrParameterInvalidMsg("invalid %s: %s", LoadOnDemandWaitKey, kv.GetValue())
+			}
+			return wait, nil
+		}
+	}
+	return false, nil
+}
+
+// checkPartitionsLoadedOnDemand reports the access of the partitions to querycoord if the collection loads partitions on demand,
+// querycoord starts loading the unloaded partitions on the report.
+// The request waits until the partitions are loaded if load_on_demand_wait is set, otherwise it fails fast.
+func checkPartitionsLoadedOnDemand(ctx context.Context, mc types.MixCoordClient, collInfo *collectionInfo,
+	partitionIDs []int64, params []*commonpb.KeyValuePair,
+) error {
+	if len(partitionIDs) == 0 || !common.IsPartitionLoadOnDemandEnabled(collInfo.properties...) {
+		return nil
+	}
+	wait, err := isLoadOnDemandWait(params)
+	if err != nil {
+		return err
+	}
+
+	interval := paramtable.Get().ProxyCfg.PartitionLoadOnDemandReportInterval.GetAsDuration(time.Second)
+	toReport := globalPartitionAccessCache.toReport(partitionIDs, interval, time.Now())
+	if len(toReport) == 0 {
+		return nil
+	}
+
+	for {
+		loaded, err := showPartitionsLoaded(ctx, mc, collInfo.collID, toReport)
+		if err != nil {
+			return err
+		}
+		if loaded {
+			globalPartitionAccessCache.markReported(toReport, time.Now())
+			return nil
+		}
+		if !wait {
+			return merr.WrapErrPartitionNotLoaded(toReport, "partitions are being loaded on demand, retry later")
+		}
+		select {
+		case <-ctx.Done():
+			log.Ctx(ctx).Warn("wait for partitions loaded on demand failed", zap.Int64s("partitionIDs", toReport), zap.Error(ctx.Err()))
+			return merr.WrapErrPartitionNotLoaded(toReport, "wait for partitions loaded on demand: "+ctx.Err().Error())
+		case <-time.After(loadOnDemandPollInterval):
+		}
+	}
+}
+
+// showPartitionsLoaded reports the access of the partitions and returns whether all the partitions are fully loaded.
+func showPartitionsLoaded(ctx context.Context, mc types.MixCoordClient, collectionID int64, partitionIDs []int64) (bool, error) {
+	resp, err := mc.ShowLoadPartitions(contextutil.PartitionAccessHeader.With(ctx, "true"), &querypb.ShowPartitionsRequest{
+		CollectionID: collectionID,
+		PartitionIDs: partitionIDs,
+	})
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		if errors.Is(err, merr.ErrPartitionNotLoaded) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, percentage := range resp.GetInMemoryPercentages() {
+		if percentage < 100 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
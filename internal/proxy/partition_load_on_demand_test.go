@@ -0,0 +1,124 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestPartitionAccessCache(t *testing.T) {
+	cache := newPartitionAccessCache()
+	now := time.Now()
+	assert.ElementsMatch(t, []int64{1, 2}, cache.toReport([]int64{1, 2}, time.Minute, now))
+
+	cache.markReported([]int64{1}, now)
+	assert.ElementsMatch(t, []int64{2}, cache.toReport([]int64{1, 2}, time.Minute, now.Add(time.Second)))
+	assert.ElementsMatch(t, []int64{1, 2}, cache.toReport([]int64{1, 2}, time.Minute, now.Add(2*time.Minute)))
+}
+
+func TestIsLoadOnDemandWait(t *testing.T) {
+	wait, err := isLoadOnDemandWait(nil)
+	assert.NoError(t, err)
+	assert.False(t, wait)
+
+	wait, err = isLoadOnDemandWait([]*commonpb.KeyValuePair{{Key: LoadOnDemandWaitKey, Value: "true"}})
+	assert.NoError(t, err)
+	assert.True(t, wait)
+
+	_, err = isLoadOnDemandWait([]*commonpb.KeyValuePair{{Key: LoadOnDemandWaitKey, Value: "x"}})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestCheckPartitionsLoadedOnDemand(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+	collInfo := &collectionInfo{
+		collID:     100,
+		properties: []*commonpb.KeyValuePair{{Key: common.PartitionLoadOnDemandKey, Value: "true"}},
+	}
+
+	t.Run("not on demand", func(t *testing.T) {
+		mixc := mocks.NewMockMixCoordClient(t)
+		err := checkPartitionsLoadedOnDemand(ctx, mixc, &collectionInfo{collID: 100}, []int64{1}, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("loaded", func(t *testing.T) {
+		globalPartitionAccessCache = newPartitionAccessCache()
+		mixc := mocks.NewMockMixCoordClient(t)
+		mixc.EXPECT().ShowLoadPartitions(mock.Anything, mock.Anything).Return(&querypb.ShowPartitionsResponse{
+			Status:              merr.Success(),
+			PartitionIDs:        []int64{1},
+			InMemoryPercentages: []int64{100},
+		}, nil).Once()
+		assert.NoError(t, checkPartitionsLoadedOnDemand(ctx, mixc, collInfo, []int64{1}, nil))
+		// reported recently, no rpc
+		assert.NoError(t, checkPartitionsLoadedOnDemand(ctx, mixc, collInfo, []int64{1}, nil))
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		globalPartitionAccessCache = newPartitionAccessCache()
+		mixc := mocks.NewMockMixCoordClient(t)
+		mixc.EXPECT().ShowLoadPartitions(mock.Anything, mock.Anything).Return(&querypb.ShowPartitionsResponse{
+			Status: merr.Status(merr.WrapErrPartitionNotLoaded(1)),
+		}, nil).Once()
+		err := checkPartitionsLoadedOnDemand(ctx, mixc, collInfo, []int64{1}, nil)
+		assert.ErrorIs(t, err, merr.ErrPartitionNotLoaded)
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		globalPartitionAccessCache = newPartitionAccessCache()
+		mixc := mocks.NewMockMixCoordClient(t)
+		mixc.EXPECT().ShowLoadPartitions(mock.Anything, mock.Anything).Return(&querypb.ShowPartitionsResponse{
+			Status:              merr.Success(),
+			PartitionIDs:        []int64{1},
+			InMemoryPercentages: []int64{50},
+		}, nil).Once()
+		mixc.EXPECT().ShowLoadPartitions(mock.Anything, mock.Anything).Return(&querypb.ShowPartitionsResponse{
+			Status:              merr.Success(),
+			PartitionIDs:        []int64{1},
+			InMemoryPercentages: []int64{100},
+		}, nil).Once()
+		params := []*commonpb.KeyValuePair{{Key: LoadOnDemandWaitKey, Value: "true"}}
+		assert.NoError(t, checkPartitionsLoadedOnDemand(ctx, mixc, collInfo, []int64{1}, params))
+	})
+
+	t.Run("wait timeout", func(t *testing.T) {
+		globalPartitionAccessCache = newPartitionAccessCache()
+		mixc := mocks.NewMockMixCoordClient(t)
+		mixc.EXPECT().ShowLoadPartitions(mock.Anything, mock.Anything).Return(&querypb.ShowPartitionsResponse{
+			Status: merr.Status(merr.WrapErrPartitionNotLoaded(1)),
+		}, nil)
+		ctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		defer cancel()
+		params := []*commonpb.KeyValuePair{{Key: LoadOnDemandWaitKey, Value: "true"}}
+		err := checkPartitionsLoadedOnDemand(ctx, mixc, collInfo, []int64{1}, params)
+		assert.ErrorIs(t, err, merr.ErrPartitionNotLoaded)
+	})
+}
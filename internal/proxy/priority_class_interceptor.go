@@ -0,0 +1,117 @@
+/*
+ * Licensed to the LF AI & Data foundation under one
+ * or more contributor license agreements. See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership. The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License. You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util"
+	"github.com/milvus-io/milvus/pkg/v2/util/contextutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// PriorityClassInterceptor passes the priority class of search and query requests to the query nodes.
+// The class carried in the "priority-class" header is used if the user is allowed to choose its own class,
+// otherwise it's derived from the user, the roles of the user and the database of the request.
+func PriorityClassInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(contextutil.PriorityClassHeader.With(ctx, ResolvePriorityClass(ctx, req)), req)
+	}
+}
+
+// ResolvePriorityClass returns the priority class of the request, "" if no class is specified or configured.
+func ResolvePriorityClass(ctx context.Context, req any) string {
+	if class := contextutil.PriorityClassHeader.Get(ctx); class != "" && canSetPriorityClass(ctx) {
+		return class
+	}
+
+	var dbName string
+	switch r := req.(type) {
+	case *milvuspb.SearchRequest:
+		dbName = r.GetDbName()
+	case *milvuspb.HybridSearchRequest:
+		dbName = r.GetDbName()
+	case *milvuspb.QueryRequest:
+		dbName = r.GetDbName()
+	default:
+		return ""
+	}
+
+	if username, err := GetCurUserFromContext(ctx); err == nil && username != "" {
+		if class, ok := parsePriorityClassMapping(Params.ProxyCfg.PriorityClassUsers.GetValue())[username]; ok {
+			return class
+		}
+		if roleMapping := parsePriorityClassMapping(Params.ProxyCfg.PriorityClassRoles.GetValue()); len(roleMapping) > 0 {
+			roles, _ := GetRole(username)
+			sort.Strings(roles)
+			for _, role := range roles {
+				if class, ok := roleMapping[role]; ok {
+					return class
+				}
+			}
+		}
+	}
+
+	if dbName == "" {
+		dbName = GetCurDBNameFromContextOrDefault(ctx)
+	}
+	return parsePriorityClassMapping(Params.ProxyCfg.PriorityClassDatabases.GetValue())[dbName]
+}
+
+// canSetPriorityClass returns whether the user of the request may choose its priority class by the header,
+// only root and admin users may do so, otherwise any client could raise its own priority.
+func canSetPriorityClass(ctx context.Context) bool {
+	if !Params.CommonCfg.AuthorizationEnabled.GetAsBool() {
+		return true
+	}
+	username, err := GetCurUserFromContext(ctx)
+	if err != nil || username == "" {
+		return false
+	}
+	if username == util.UserRoot {
+		return true
+	}
+	roles, err := GetRole(username)
+	if err != nil {
+		return false
+	}
+	return lo.Contains(roles, util.RoleAdmin)
+}
+
+// parsePriorityClassMapping parses the config in the format of "name1:class1,name2:class2".
+func parsePriorityClassMapping(config string) map[string]string {
+	mapping := make(map[string]string)
+	for _, item := range paramtable.ParseAsStings(config) {
+		name, class, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		name, class = strings.TrimSpace(name), strings.TrimSpace(class)
+		if name != "" && class != "" {
+			mapping[name] = class
+		}
+	}
+	return mapping
+}
@@ -0,0 +1,111 @@
+/*
+ * Licensed to the LF AI & Data foundation under one
+ * or more contributor license agreements. See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership. The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License. You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestPriorityClassInterceptor(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.ProxyCfg.PriorityClassUsers.Key, "alice:interactive")
+	defer params.Reset(params.ProxyCfg.PriorityClassUsers.Key)
+	params.Save(params.ProxyCfg.PriorityClassDatabases.Key, "analytics:batch")
+	defer params.Reset(params.ProxyCfg.PriorityClassDatabases.Key)
+
+	var outgoing []string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		outgoing = md.Get(util.HeaderPriorityClass)
+		return nil, nil
+	}
+	interceptor := PriorityClassInterceptor()
+
+	t.Run("class in header", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(util.HeaderPriorityClass, "batch"))
+		ctx = NewContextWithMetadata(ctx, "alice", "")
+		_, err := interceptor(ctx, &milvuspb.SearchRequest{}, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"batch"}, outgoing)
+	})
+
+	t.Run("class in header from non-admin user", func(t *testing.T) {
+		params.Save(params.CommonCfg.AuthorizationEnabled.Key, "true")
+		defer params.Reset(params.CommonCfg.AuthorizationEnabled.Key)
+
+		// the header is ignored, the class is derived from the user
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(util.HeaderPriorityClass, "batch"))
+		ctx = NewContextWithMetadata(ctx, "alice", "")
+		_, err := interceptor(ctx, &milvuspb.SearchRequest{}, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"interactive"}, outgoing)
+
+		ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(util.HeaderPriorityClass, "batch"))
+		ctx = NewContextWithMetadata(ctx, util.UserRoot, "")
+		_, err = interceptor(ctx, &milvuspb.SearchRequest{}, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"batch"}, outgoing)
+	})
+
+	t.Run("derived from user", func(t *testing.T) {
+		ctx := NewContextWithMetadata(context.Background(), "alice", "analytics")
+		_, err := interceptor(ctx, &milvuspb.QueryRequest{DbName: "analytics"}, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"interactive"}, outgoing)
+	})
+
+	t.Run("derived from database", func(t *testing.T) {
+		ctx := NewContextWithMetadata(context.Background(), "bob", "")
+		_, err := interceptor(ctx, &milvuspb.HybridSearchRequest{DbName: "analytics"}, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"batch"}, outgoing)
+
+		ctx = NewContextWithMetadata(context.Background(), "bob", "analytics")
+		_, err = interceptor(ctx, &milvuspb.SearchRequest{}, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"batch"}, outgoing)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		ctx := NewContextWithMetadata(context.Background(), "bob", "")
+		_, err := interceptor(ctx, &milvuspb.SearchRequest{}, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Empty(t, outgoing)
+
+		// not a search or query request
+		_, err = interceptor(ctx, &milvuspb.InsertRequest{DbName: "analytics"}, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Empty(t, outgoing)
+	})
+}
+
+func TestParsePriorityClassMapping(t *testing.T) {
+	assert.Equal(t, map[string]string{"a": "interactive", "b": "batch"}, parsePriorityClassMapping("a:interactive, b : batch,c,:x,d:"))
+	assert.Empty(t, parsePriorityClassMapping(""))
+}
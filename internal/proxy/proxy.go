@@ -24,15 +24,16 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/uuid"
-	"github.com/hashicorp/golang-lru/v2/expirable"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus/internal/allocator"
+	"github.com/milvus-io/milvus/internal/proxy/capture"
 	"github.com/milvus-io/milvus/internal/proxy/connection"
 	"github.com/milvus-io/milvus/internal/proxy/shardclient"
+	"github.com/milvus-io/milvus/internal/proxy/slowlog"
 	"github.com/milvus-io/milvus/internal/types"
 	"github.com/milvus-io/milvus/internal/util/dependency"
 	"github.com/milvus-io/milvus/internal/util/hookutil"
@@ -115,7 +116,7 @@ type Proxy struct {
 	// delete rate limiter
 	enableComplexDeleteLimit bool
 
-	slowQueries *expirable.LRU[Timestamp, *metricsinfo.SlowQuery]
+	slowQueries *slowlog.Recorder
 
 	// alterSchemaInFlight tracks collections that have an AlterCollectionSchema
 	// request in progress, keyed by "dbName/collectionName". Prevents concurrent
@@ -137,7 +138,7 @@ func NewProxy(ctx context.Context, _ dependency.Factory) (*Proxy, error) {
 		simpleLimiter: NewSimpleLimiter(Params.QuotaConfig.AllocWaitInterval.GetAsDuration(time.Millisecond), Params.QuotaConfig.AllocRetryTimes.GetAsUint()),
 		// lbPolicy:        lbPolicy,
 		resourceManager: resourceManager,
-		slowQueries:     expirable.NewLRU[Timestamp, *metricsinfo.SlowQuery](20, nil, time.Minute*15),
+		slowQueries:     slowlog.NewRecorder(Params.ProxyCfg.SlowQueryLog.Capacity.GetAsInt()),
 	}
 	node.UpdateStateCode(commonpb.StateCode_Abnormal)
 	expr.Register("proxy", node)
@@ -254,6 +255,21 @@ func (node *Proxy) Init() error {
 
 	node.enableMaterializedView = Params.CommonCfg.EnableMaterializedView.GetAsBool()
 
+	if Params.ProxyCfg.SlowQueryLog.Enable.GetAsBool() {
+		slowQueries, err := slowlog.NewPersistentRecorder(&Params.ProxyCfg.SlowQueryLog, &Params.MinioCfg)
+		if err != nil {
+			// the slow queries are kept in memory only if the log files are unavailable.
+			log.Warn("failed to init slow query log", zap.Error(err))
+		} else {
+			node.slowQueries = slowQueries
+		}
+	}
+
+	if err := capture.InitRecorder(&Params.ProxyCfg.WorkloadCapture, &Params.MinioCfg); err != nil {
+		// the proxy keeps serving without capturing the workload.
+		log.Warn("failed to init workload capture", zap.Error(err))
+	}
+
 	// Enable internal rand pool for UUIDv4 generation
 	// This is NOT thread-safe and should only be called before the service starts and
 	// there is no possibility that New or any other UUID V4 generation function will be called concurrently
@@ -338,6 +354,12 @@ func (node *Proxy) Stop() error {
 		node.resourceManager.Close()
 	}
 
+	if node.slowQueries != nil {
+		node.slowQueries.Close()
+	}
+
+	capture.Close()
+
 	node.cancel()
 	node.wg.Wait()
 
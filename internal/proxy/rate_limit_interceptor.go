@@ -55,12 +55,15 @@ func RateLimitInterceptor(limiter types.Limiter) grpc.UnaryServerInterceptor {
 				}
 			}
 		}
-		err = limiter.Check(dbID, collectionIDToPartIDs, rt, n)
+		err = CheckLimiterWithBackpressure(ctx, limiter, dbID, collectionIDToPartIDs, rt, n)
 		nodeID := strconv.FormatInt(paramtable.GetNodeID(), 10)
 		metrics.ProxyRateLimitReqCount.WithLabelValues(nodeID, rt.String(), metrics.TotalLabel).Inc()
 		if err != nil {
 			metrics.ProxyRateLimitReqCount.WithLabelValues(nodeID, rt.String(), metrics.FailLabel).Inc()
 			rsp := GetFailedResponse(req, err)
+			if retryAfter, ok := GetRetryAfter(err); ok {
+				SetRetryAfterHint(ctx, rsp, retryAfter)
+			}
 			if rsp != nil {
 				return rsp, nil
 			}
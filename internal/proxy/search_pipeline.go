@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/apache/arrow/go/v17/arrow/memory"
 	"github.com/samber/lo"
@@ -35,6 +36,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/explainutil"
 	"github.com/milvus-io/milvus/internal/util/function/chain"
 	"github.com/milvus-io/milvus/internal/util/function/models"
 	"github.com/milvus-io/milvus/internal/util/segcore"
@@ -1875,11 +1877,13 @@ func (p *pipeline) Run(ctx context.Context, span trace.Span, toReduceResults []*
 	for _, node := range p.nodes {
 		var err error
 		log.Ctx(ctx).Debug("SearchPipeline run node", zap.String("node", node.name))
+		start := time.Now()
 		msg, err = node.Run(ctx, span, msg)
 		if err != nil {
 			log.Ctx(ctx).Error("Run node failed: ", zap.String("err", err.Error()))
 			return nil, storageCost, err
 		}
+		explainutil.FromContext(ctx).RecordStage("pipeline_"+node.name, time.Since(start))
 		pTrace.TraceMsg(node.opName, msg)
 	}
 	pTrace.LogIfEnabled(ctx, p.name)
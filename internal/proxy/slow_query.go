@@ -0,0 +1,207 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/internal/proxy/slowlog"
+	"github.com/milvus-io/milvus/internal/util/proxyutil"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const slowQueryPeerTimeout = 5 * time.Second
+
+// slowQueryPeerCreator creates the client of another proxy to fetch the slow queries recorded by it.
+var slowQueryPeerCreator proxyutil.ProxyCreator = proxyutil.DefaultProxyCreator
+
+// recordSlowQuery records the slow query with the details of the executed task.
+func (node *Proxy) recordSlowQuery(slowQuery *metricsinfo.SlowQuery, t task) {
+	if node.slowQueries == nil {
+		return
+	}
+	slowQuery.NodeID = paramtable.GetNodeID()
+	if recorder, ok := t.(stageRecorder); ok {
+		slowQuery.Stages = recorder.GetStages()
+	}
+	switch t := t.(type) {
+	case *searchTask:
+		slowQuery.NQ = t.GetNq()
+		slowQuery.TopK = t.GetTopk()
+		slowQuery.PlanSummary = t.planSummary()
+	case *queryTask:
+		slowQuery.TopK = t.GetLimit()
+		slowQuery.PlanSummary = t.planSummary()
+	}
+	node.slowQueries.Record(slowQuery)
+}
+
+// planSummary summarizes the plan of the search for the slow query log.
+func (t *searchTask) planSummary() string {
+	dsls := []string{t.request.GetDsl()}
+	if t.GetIsAdvanced() {
+		dsls = make([]string, 0, len(t.request.GetSubReqs()))
+		for _, subReq := range t.request.GetSubReqs() {
+			dsls = append(dsls, subReq.GetDsl())
+		}
+	}
+	summaries := make([]string, 0, len(t.queryInfos))
+	for i, info := range t.queryInfos {
+		summary := fmt.Sprintf("ann(field=%d, metric=%s, topk=%d", info.GetQueryFieldId(), info.GetMetricType(), info.GetTopk())
+		if i < len(dsls) && dsls[i] != "" {
+			summary += ", filtered"
+		}
+		if groupByFieldIDs := info.GetGroupByFieldIds(); len(groupByFieldIDs) > 0 {
+			summary += fmt.Sprintf(", group_by=%v", groupByFieldIDs)
+		} else if info.GetGroupByFieldId() > 0 {
+			summary += fmt.Sprintf(", group_by=%d", info.GetGroupByFieldId())
+		}
+		summaries = append(summaries, summary+")")
+	}
+	if t.GetIsAdvanced() {
+		return fmt.Sprintf("rerank(topk=%d, %s)", t.GetTopk(), strings.Join(summaries, ", "))
+	}
+	return strings.Join(summaries, ", ")
+}
+
+// planSummary summarizes the plan of the query for the slow query log.
+func (t *queryTask) planSummary() string {
+	query := t.plan.GetQuery()
+	summary := "query("
+	if query.GetIsCount() {
+		summary = "count("
+	}
+	parts := make([]string, 0)
+	if query.GetPredicates() != nil {
+		parts = append(parts, "filtered")
+	}
+	if query.GetLimit() > 0 {
+		parts = append(parts, fmt.Sprintf("limit=%d", query.GetLimit()))
+	}
+	if len(query.GetGroupByFieldIds()) > 0 {
+		parts = append(parts, fmt.Sprintf("group_by=%v", query.GetGroupByFieldIds()))
+	}
+	if len(query.GetAggregates()) > 0 {
+		parts = append(parts, fmt.Sprintf("aggregates=%d", len(query.GetAggregates())))
+	}
+	if len(query.GetOrderByFields()) > 0 {
+		parts = append(parts, fmt.Sprintf("order_by=%d", len(query.GetOrderByFields())))
+	}
+	return summary + strings.Join(parts, ", ") + ")"
+}
+
+// listSlowQueries lists the slow queries recorded by this proxy and matched by the metrics request.
+func (node *Proxy) listSlowQueries(jsonReq gjson.Result) (string, error) {
+	filter, err := slowlog.ParseFilter(func(key string) string {
+		return jsonReq.Get(key).String()
+	})
+	if err != nil {
+		return "", err
+	}
+	slowQueries := make([]*metricsinfo.SlowQuery, 0)
+	if node.slowQueries != nil {
+		slowQueries = node.slowQueries.List(filter, Params.ProxyCfg.SlowQueryLog.MaxAge.GetAsDuration(time.Hour))
+	}
+	ret, err := json.Marshal(slowQueries)
+	if err != nil {
+		return "", err
+	}
+	return string(ret), nil
+}
+
+// listClusterSlowQueries lists the slow queries recorded by all proxies of the cluster and matched by the filter,
+// the slow queries of the proxies failed to respond are skipped.
+func (node *Proxy) listClusterSlowQueries(ctx context.Context, filter *slowlog.Filter) ([]*metricsinfo.SlowQuery, error) {
+	lists := make([][]*metricsinfo.SlowQuery, 0)
+	if node.slowQueries != nil {
+		lists = append(lists, node.slowQueries.List(filter, Params.ProxyCfg.SlowQueryLog.MaxAge.GetAsDuration(time.Hour)))
+	}
+	if node.session == nil {
+		return slowlog.Merge(filter, lists...), nil
+	}
+	sessions, _, err := node.session.GetSessions(ctx, typeutil.ProxyRole)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	group := &errgroup.Group{}
+	for _, session := range sessions {
+		if session.ServerID == paramtable.GetNodeID() {
+			continue
+		}
+		group.Go(func() error {
+			slowQueries, err := fetchPeerSlowQueries(ctx, session, filter)
+			if err != nil {
+				log.Ctx(ctx).Warn("failed to fetch the slow queries from proxy",
+					zap.Int64("nodeID", session.ServerID), zap.String("address", session.Address), zap.Error(err))
+				return nil
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			lists = append(lists, slowQueries)
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return slowlog.Merge(filter, lists...), nil
+}
+
+func fetchPeerSlowQueries(ctx context.Context, session *sessionutil.Session, filter *slowlog.Filter) ([]*metricsinfo.SlowQuery, error) {
+	ctx, cancel := context.WithTimeout(ctx, slowQueryPeerTimeout)
+	defer cancel()
+
+	client, err := slowQueryPeerCreator(ctx, session.Address, session.ServerID)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	params := map[string]interface{}{
+		metricsinfo.MetricTypeKey: metricsinfo.SlowQueryKey,
+	}
+	for key, value := range filter.Params() {
+		params[key] = value
+	}
+	req, err := metricsinfo.ConstructGetMetricsRequest(params)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetProxyMetrics(ctx, req)
+	if err := merr.CheckRPCCall(resp, err); err != nil {
+		return nil, err
+	}
+	slowQueries := make([]*metricsinfo.SlowQuery, 0)
+	if err := json.Unmarshal([]byte(resp.GetResponse()), &slowQueries); err != nil {
+		return nil, err
+	}
+	return slowQueries, nil
+}
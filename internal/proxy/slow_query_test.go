@@ -0,0 +1,150 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/internal/mocks"
+	"github.com/milvus-io/milvus/internal/proxy/slowlog"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/sessionutil"
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestRecordSlowQuery(t *testing.T) {
+	paramtable.Init()
+	node := &Proxy{slowQueries: slowlog.NewRecorder(10)}
+
+	st := &searchTask{
+		SearchRequest: &internalpb.SearchRequest{Nq: 2, Topk: 10},
+		request:       &milvuspb.SearchRequest{Dsl: "age > 1"},
+		queryInfos:    []*planpb.QueryInfo{{QueryFieldId: 101, MetricType: "L2", Topk: 10, GroupByFieldId: 102}},
+	}
+	st.RecordStage("queue", time.Millisecond)
+	st.RecordStage("execute", 2*time.Millisecond)
+	node.recordSlowQuery(&metricsinfo.SlowQuery{Collection: "c1", Type: "Search", Timestamp: time.Now().UnixMilli()}, st)
+
+	qt := &queryTask{
+		RetrieveRequest: &internalpb.RetrieveRequest{Limit: 5},
+		plan: &planpb.PlanNode{Node: &planpb.PlanNode_Query{Query: &planpb.QueryPlanNode{
+			Predicates: &planpb.Expr{},
+			Limit:      5,
+		}}},
+	}
+	node.recordSlowQuery(&metricsinfo.SlowQuery{Collection: "c2", Type: "Query", Timestamp: time.Now().UnixMilli()}, qt)
+
+	entries := node.slowQueries.List(nil, 0)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "query(filtered, limit=5)", entries[0].PlanSummary)
+	assert.Equal(t, int64(5), entries[0].TopK)
+	assert.Equal(t, paramtable.GetNodeID(), entries[0].NodeID)
+
+	assert.Equal(t, "ann(field=101, metric=L2, topk=10, filtered, group_by=102)", entries[1].PlanSummary)
+	assert.Equal(t, int64(2), entries[1].NQ)
+	assert.Equal(t, int64(10), entries[1].TopK)
+	assert.Equal(t, []*metricsinfo.SlowQueryStage{{Name: "queue", DurationMs: 1}, {Name: "execute", DurationMs: 2}}, entries[1].Stages)
+
+	// hybrid search
+	st = &searchTask{
+		SearchRequest: &internalpb.SearchRequest{IsAdvanced: true, Topk: 5},
+		request:       &milvuspb.SearchRequest{SubReqs: []*milvuspb.SubSearchRequest{{Dsl: "age > 1"}, {}}},
+		queryInfos:    []*planpb.QueryInfo{{QueryFieldId: 101, MetricType: "L2", Topk: 10}, {QueryFieldId: 102, MetricType: "IP", Topk: 20}},
+	}
+	assert.Equal(t, "rerank(topk=5, ann(field=101, metric=L2, topk=10, filtered), ann(field=102, metric=IP, topk=20))", st.planSummary())
+
+	qt = &queryTask{plan: &planpb.PlanNode{Node: &planpb.PlanNode_Query{Query: &planpb.QueryPlanNode{IsCount: true}}}}
+	assert.Equal(t, "count()", qt.planSummary())
+
+	// nothing is recorded without the recorder.
+	(&Proxy{}).recordSlowQuery(&metricsinfo.SlowQuery{}, qt)
+}
+
+func TestListSlowQueries(t *testing.T) {
+	paramtable.Init()
+	now := time.Now()
+	node := &Proxy{slowQueries: slowlog.NewRecorder(10)}
+	node.slowQueries.Record(&metricsinfo.SlowQuery{Collection: "c1", Timestamp: now.Add(-time.Minute).UnixMilli(), DurationMs: 10})
+	node.slowQueries.Record(&metricsinfo.SlowQuery{Collection: "c2", Timestamp: now.UnixMilli(), DurationMs: 20})
+
+	ret, err := node.listSlowQueries(gjson.Parse(`{"metric_type":"slow_query","collection_name":"c1"}`))
+	require.NoError(t, err)
+	entries := make([]*metricsinfo.SlowQuery, 0)
+	require.NoError(t, json.Unmarshal([]byte(ret), &entries))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "c1", entries[0].Collection)
+
+	_, err = node.listSlowQueries(gjson.Parse(`{"metric_type":"slow_query","limit":"-1"}`))
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	// the proxy without session only lists the local slow queries.
+	entries, err = node.listClusterSlowQueries(context.Background(), &slowlog.Filter{Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "c2", entries[0].Collection)
+}
+
+func TestFetchPeerSlowQueries(t *testing.T) {
+	paramtable.Init()
+	original := slowQueryPeerCreator
+	defer func() { slowQueryPeerCreator = original }()
+
+	client := mocks.NewMockProxyClient(t)
+	slowQueryPeerCreator = func(ctx context.Context, addr string, nodeID int64) (types.ProxyClient, error) {
+		return client, nil
+	}
+	client.EXPECT().Close().Return(nil)
+	client.EXPECT().GetProxyMetrics(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, req *milvuspb.GetMetricsRequest) (*milvuspb.GetMetricsResponse, error) {
+		jsonReq := gjson.Parse(req.GetRequest())
+		assert.Equal(t, metricsinfo.SlowQueryKey, jsonReq.Get(metricsinfo.MetricTypeKey).String())
+		assert.Equal(t, "root", jsonReq.Get(slowlog.UserKey).String())
+		return &milvuspb.GetMetricsResponse{
+			Status:   merr.Success(),
+			Response: `[{"collection":"c1","user":"root","duration_ms":10}]`,
+		}, nil
+	}).Once()
+
+	session := &sessionutil.Session{SessionRaw: sessionutil.SessionRaw{ServerID: 2, Address: "localhost:19530"}}
+	entries, err := fetchPeerSlowQueries(context.Background(), session, &slowlog.Filter{User: "root"})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, 10.0, entries[0].DurationMs)
+
+	client.EXPECT().GetProxyMetrics(mock.Anything, mock.Anything).Return(nil, errors.New("mock")).Once()
+	_, err = fetchPeerSlowQueries(context.Background(), session, &slowlog.Filter{})
+	assert.Error(t, err)
+
+	slowQueryPeerCreator = func(ctx context.Context, addr string, nodeID int64) (types.ProxyClient, error) {
+		return nil, errors.New("mock")
+	}
+	_, err = fetchPeerSlowQueries(context.Background(), session, &slowlog.Filter{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,205 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowlog
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+)
+
+// the keys of the slow query filter, shared by the http query parameters and the metrics request.
+const (
+	StartTimeKey  = "start_time"
+	EndTimeKey    = "end_time"
+	UserKey       = "user"
+	DatabaseKey   = "db_name"
+	CollectionKey = "collection_name"
+	TypeKey       = "type"
+	LimitKey      = "limit"
+)
+
+// Filter filters the slow queries, the zero value matches all slow queries.
+type Filter struct {
+	// in unix milliseconds, the start time is inclusive and the end time is exclusive, zero means unbounded.
+	StartTime  int64
+	EndTime    int64
+	User       string
+	Database   string
+	Collection string
+	// Search, HybridSearch or Query
+	Type string
+	// the max number of the returned slow queries, zero means unlimited.
+	Limit int
+}
+
+// ParseFilter parses the filter from the parameters looked up by the key,
+// the time is either in unix milliseconds or in RFC3339 format.
+func ParseFilter(get func(key string) string) (*Filter, error) {
+	filter := &Filter{
+		User:       get(UserKey),
+		Database:   get(DatabaseKey),
+		Collection: get(CollectionKey),
+		Type:       get(TypeKey),
+	}
+	var err error
+	if filter.StartTime, err = parseTime(StartTimeKey, get(StartTimeKey)); err != nil {
+		return nil, err
+	}
+	if filter.EndTime, err = parseTime(EndTimeKey, get(EndTimeKey)); err != nil {
+		return nil, err
+	}
+	if value := get(LimitKey); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return nil, merr.WrapErrParameterInvalid("non-negative integer", value, "invalid "+LimitKey)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+func parseTime(key, value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return ms, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, merr.WrapErrParameterInvalid("unix milliseconds or RFC3339 time", value, "invalid "+key)
+	}
+	return t.UnixMilli(), nil
+}
+
+// Params returns the parameters of the filter, which can be parsed back by ParseFilter.
+func (f *Filter) Params() map[string]string {
+	params := map[string]string{
+		UserKey:       f.User,
+		DatabaseKey:   f.Database,
+		CollectionKey: f.Collection,
+		TypeKey:       f.Type,
+	}
+	if f.StartTime > 0 {
+		params[StartTimeKey] = strconv.FormatInt(f.StartTime, 10)
+	}
+	if f.EndTime > 0 {
+		params[EndTimeKey] = strconv.FormatInt(f.EndTime, 10)
+	}
+	if f.Limit > 0 {
+		params[LimitKey] = strconv.Itoa(f.Limit)
+	}
+	for key, value := range params {
+		if value == "" {
+			delete(params, key)
+		}
+	}
+	return params
+}
+
+// Match returns whether the slow query is matched by the filter.
+func (f *Filter) Match(entry *metricsinfo.SlowQuery) bool {
+	if f.StartTime > 0 && entry.Timestamp < f.StartTime {
+		return false
+	}
+	if f.EndTime > 0 && entry.Timestamp >= f.EndTime {
+		return false
+	}
+	return matchString(f.User, entry.User) &&
+		matchString(f.Database, entry.Database) &&
+		matchString(f.Collection, entry.Collection) &&
+		matchString(f.Type, entry.Type)
+}
+
+func matchString(expected, actual string) bool {
+	return expected == "" || expected == actual
+}
+
+// Merge merges the slow queries listed from multiple proxies into the newest ones limited by the filter.
+func Merge(filter *Filter, lists ...[]*metricsinfo.SlowQuery) []*metricsinfo.SlowQuery {
+	merged := make([]*metricsinfo.SlowQuery, 0)
+	for _, list := range lists {
+		merged = append(merged, list...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp > merged[j].Timestamp
+	})
+	if filter != nil && filter.Limit > 0 && len(merged) > filter.Limit {
+		merged = merged[:filter.Limit]
+	}
+	return merged
+}
+
+// Summarize summarizes the latency of the slow queries, in total, by collection and by stage.
+func Summarize(entries []*metricsinfo.SlowQuery) *metricsinfo.SlowQueryStats {
+	total := make([]float64, 0, len(entries))
+	collections := make(map[string][]float64)
+	stages := make(map[string][]float64)
+	for _, entry := range entries {
+		total = append(total, entry.DurationMs)
+		key := entry.Database + "." + entry.Collection
+		collections[key] = append(collections[key], entry.DurationMs)
+		for _, stage := range entry.Stages {
+			stages[stage.Name] = append(stages[stage.Name], stage.DurationMs)
+		}
+	}
+
+	stats := &metricsinfo.SlowQueryStats{
+		Total:       summarizeLatency(total),
+		Collections: make(map[string]*metricsinfo.SlowQueryLatency, len(collections)),
+		Stages:      make(map[string]*metricsinfo.SlowQueryLatency, len(stages)),
+	}
+	for key, durations := range collections {
+		stats.Collections[key] = summarizeLatency(durations)
+	}
+	for name, durations := range stages {
+		stats.Stages[name] = summarizeLatency(durations)
+	}
+	return stats
+}
+
+func summarizeLatency(durations []float64) *metricsinfo.SlowQueryLatency {
+	latency := &metricsinfo.SlowQueryLatency{Count: len(durations)}
+	if len(durations) == 0 {
+		return latency
+	}
+	sort.Float64s(durations)
+	sum := 0.0
+	for _, d := range durations {
+		sum += d
+	}
+	latency.AvgMs = sum / float64(len(durations))
+	latency.P50Ms = percentile(durations, 0.5)
+	latency.P90Ms = percentile(durations, 0.9)
+	latency.P99Ms = percentile(durations, 0.99)
+	latency.MaxMs = durations[len(durations)-1]
+	return latency
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
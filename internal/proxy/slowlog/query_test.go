@@ -0,0 +1,120 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+)
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ParseFilter(func(string) string { return "" })
+	require.NoError(t, err)
+	assert.Equal(t, &Filter{}, filter)
+	assert.Empty(t, filter.Params())
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	params := map[string]string{
+		StartTimeKey:  start.Format(time.RFC3339),
+		EndTimeKey:    "1767323045000",
+		UserKey:       "root",
+		DatabaseKey:   "default",
+		CollectionKey: "c1",
+		TypeKey:       "Query",
+		LimitKey:      "10",
+	}
+	filter, err = ParseFilter(func(key string) string { return params[key] })
+	require.NoError(t, err)
+	assert.Equal(t, &Filter{
+		StartTime:  start.UnixMilli(),
+		EndTime:    1767323045000,
+		User:       "root",
+		Database:   "default",
+		Collection: "c1",
+		Type:       "Query",
+		Limit:      10,
+	}, filter)
+
+	// the params are parsed back to the same filter.
+	params = filter.Params()
+	parsed, err := ParseFilter(func(key string) string { return params[key] })
+	require.NoError(t, err)
+	assert.Equal(t, filter, parsed)
+
+	for key, value := range map[string]string{StartTimeKey: "yesterday", LimitKey: "-1"} {
+		_, err = ParseFilter(func(k string) string {
+			if k == key {
+				return value
+			}
+			return ""
+		})
+		assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	entry := newSlowQuery("c1", time.Minute, 10)
+	assert.True(t, (&Filter{}).Match(entry))
+	assert.True(t, (&Filter{User: "root", Database: "default", Collection: "c1", Type: "Search"}).Match(entry))
+	assert.False(t, (&Filter{User: "alice"}).Match(entry))
+	assert.False(t, (&Filter{Type: "Query"}).Match(entry))
+	assert.False(t, (&Filter{StartTime: entry.Timestamp + 1}).Match(entry))
+	assert.False(t, (&Filter{EndTime: entry.Timestamp}).Match(entry))
+	assert.True(t, (&Filter{StartTime: entry.Timestamp, EndTime: entry.Timestamp + 1}).Match(entry))
+}
+
+func TestMergeAndSummarize(t *testing.T) {
+	proxy1 := []*metricsinfo.SlowQuery{newSlowQuery("c1", time.Minute, 10), newSlowQuery("c1", 3*time.Minute, 30)}
+	proxy2 := []*metricsinfo.SlowQuery{newSlowQuery("c2", 2*time.Minute, 20)}
+	merged := Merge(&Filter{Limit: 2}, proxy1, proxy2)
+	assert.Len(t, merged, 2)
+	assert.Equal(t, 10.0, merged[0].DurationMs)
+	assert.Equal(t, 20.0, merged[1].DurationMs)
+	assert.Len(t, Merge(nil, proxy1, proxy2), 3)
+
+	entries := make([]*metricsinfo.SlowQuery, 0, 100)
+	for i := 1; i <= 100; i++ {
+		entry := newSlowQuery("c1", 0, float64(i))
+		entry.Stages = []*metricsinfo.SlowQueryStage{{Name: "execute", DurationMs: float64(i) / 2}}
+		entries = append(entries, entry)
+	}
+	entries = append(entries, newSlowQuery("c2", 0, 1000))
+	stats := Summarize(entries)
+	assert.Equal(t, 101, stats.Total.Count)
+	assert.Equal(t, 1000.0, stats.Total.MaxMs)
+	assert.Equal(t, 51.0, stats.Total.P50Ms)
+
+	c1 := stats.Collections["default.c1"]
+	assert.Equal(t, 100, c1.Count)
+	assert.Equal(t, 50.5, c1.AvgMs)
+	assert.Equal(t, 50.0, c1.P50Ms)
+	assert.Equal(t, 90.0, c1.P90Ms)
+	assert.Equal(t, 99.0, c1.P99Ms)
+	assert.Equal(t, 100.0, c1.MaxMs)
+	assert.Equal(t, 1, stats.Collections["default.c2"].Count)
+	assert.Equal(t, 25.0, stats.Stages["execute"].P50Ms)
+
+	stats = Summarize(nil)
+	assert.Zero(t, stats.Total.Count)
+	assert.Empty(t, stats.Collections)
+}
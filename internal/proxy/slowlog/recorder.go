@@ -0,0 +1,181 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowlog
+
+import (
+	"bufio"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/internal/proxy/accesslog"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// the max size of a single slow query line read from the log files.
+const maxLineSize = 1024 * 1024
+
+// Recorder records the slow queries of the proxy.
+// The latest slow queries are kept in memory to be served by the slow query api,
+// and all slow queries are written into the rotated local log files if persisted.
+type Recorder struct {
+	mu sync.RWMutex
+	// ordered by the time recorded.
+	entries  []*metricsinfo.SlowQuery
+	capacity int
+
+	writer *accesslog.RotateWriter
+}
+
+// NewRecorder creates a recorder keeping the latest slow queries in memory only.
+func NewRecorder(capacity int) *Recorder {
+	return &Recorder{
+		entries:  make([]*metricsinfo.SlowQuery, 0),
+		capacity: capacity,
+	}
+}
+
+// NewPersistentRecorder creates a recorder writing the slow queries into the rotated local log files,
+// which are uploaded to the object storage if configured.
+// The latest slow queries in the local log files are reloaded into memory.
+func NewPersistentRecorder(cfg *paramtable.SlowQueryLogConfig, minioCfg *paramtable.MinioConfig) (*Recorder, error) {
+	writer, err := accesslog.NewRotateWriterWithConfig(accesslog.RotateConfig{
+		LocalPath:     cfg.LocalPath.GetValue(),
+		Filename:      cfg.Filename.GetValue(),
+		RotatedTime:   cfg.RotatedTime.GetAsInt64(),
+		MaxSize:       cfg.MaxSize.GetAsInt(),
+		MaxBackups:    cfg.MaxBackups.GetAsInt(),
+		MinioEnable:   cfg.MinioEnable.GetAsBool(),
+		RemotePath:    cfg.RemotePath.GetValue(),
+		RemoteMaxTime: cfg.RemoteMaxTime.GetAsInt(),
+	}, minioCfg)
+	if err != nil {
+		return nil, err
+	}
+	r := NewRecorder(cfg.Capacity.GetAsInt())
+	r.writer = writer
+	r.load()
+	return r, nil
+}
+
+// load reloads the latest slow queries from the local log files.
+func (r *Recorder) load() {
+	files, err := r.writer.LogFiles()
+	if err != nil {
+		log.Warn("failed to list the slow query log files", zap.Error(err))
+		return
+	}
+	for _, file := range files {
+		if err := r.loadFile(file); err != nil {
+			log.Warn("failed to load the slow query log file", zap.String("file", file), zap.Error(err))
+		}
+	}
+	log.Info("slow queries reloaded from the log files", zap.Int("fileNum", len(files)), zap.Int("slowQueryNum", len(r.entries)))
+}
+
+func (r *Recorder) loadFile(file string) error {
+	f, err := os.OpenFile(file, os.O_RDONLY, 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		entry := &metricsinfo.SlowQuery{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			// the last line may be partially written before the crash.
+			continue
+		}
+		r.add(entry)
+	}
+	return scanner.Err()
+}
+
+// Record records the slow query.
+func (r *Recorder) Record(entry *metricsinfo.SlowQuery) {
+	r.mu.Lock()
+	r.add(entry)
+	r.mu.Unlock()
+
+	if r.writer == nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Warn("failed to marshal the slow query", zap.Error(err))
+		return
+	}
+	if _, err := r.writer.Write(append(line, '\n')); err != nil {
+		log.Warn("failed to write the slow query log", zap.Error(err))
+	}
+}
+
+func (r *Recorder) add(entry *metricsinfo.SlowQuery) {
+	if r.capacity <= 0 {
+		return
+	}
+	if len(r.entries) >= r.capacity {
+		n := copy(r.entries, r.entries[len(r.entries)-r.capacity+1:])
+		clear(r.entries[n:])
+		r.entries = r.entries[:n]
+	}
+	r.entries = append(r.entries, entry)
+}
+
+// List returns the slow queries matched by the filter from the newest to the oldest,
+// the slow queries older than maxAge are skipped.
+func (r *Recorder) List(filter *Filter, maxAge time.Duration) []*metricsinfo.SlowQuery {
+	if filter == nil {
+		filter = &Filter{}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expired := time.Now().Add(-maxAge).UnixMilli()
+	result := make([]*metricsinfo.SlowQuery, 0)
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		entry := r.entries[i]
+		if maxAge > 0 && entry.Timestamp < expired {
+			break
+		}
+		if !filter.Match(entry) {
+			continue
+		}
+		result = append(result, entry)
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+	return result
+}
+
+// Close closes the log files of the recorder.
+func (r *Recorder) Close() {
+	if r.writer != nil {
+		r.writer.Close()
+	}
+}
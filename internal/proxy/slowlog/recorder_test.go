@@ -0,0 +1,102 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slowlog
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func newSlowQuery(collection string, age time.Duration, durationMs float64) *metricsinfo.SlowQuery {
+	return &metricsinfo.SlowQuery{
+		Database:   "default",
+		Collection: collection,
+		User:       "root",
+		Type:       "Search",
+		Timestamp:  time.Now().Add(-age).UnixMilli(),
+		DurationMs: durationMs,
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder(3)
+	defer r.Close()
+	r.Record(newSlowQuery("c1", time.Hour, 10))
+	r.Record(newSlowQuery("c2", 3*time.Minute, 20))
+	r.Record(newSlowQuery("c1", 2*time.Minute, 30))
+	r.Record(newSlowQuery("c2", time.Minute, 40))
+
+	// the oldest slow query is evicted out of the capacity.
+	entries := r.List(nil, 0)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, 40.0, entries[0].DurationMs)
+	assert.Equal(t, 20.0, entries[2].DurationMs)
+
+	entries = r.List(&Filter{Collection: "c1"}, 0)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, 30.0, entries[0].DurationMs)
+
+	assert.Len(t, r.List(&Filter{Limit: 2}, 0), 2)
+	assert.Len(t, r.List(nil, 150*time.Second), 2)
+
+	// nothing is kept without capacity.
+	r = NewRecorder(0)
+	r.Record(newSlowQuery("c1", 0, 10))
+	assert.Empty(t, r.List(nil, 0))
+}
+
+func TestPersistentRecorder(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	dir := t.TempDir()
+	params.Save(params.ProxyCfg.SlowQueryLog.LocalPath.Key, dir)
+	params.Save(params.ProxyCfg.SlowQueryLog.Capacity.Key, "2")
+	defer params.Reset(params.ProxyCfg.SlowQueryLog.LocalPath.Key)
+	defer params.Reset(params.ProxyCfg.SlowQueryLog.Capacity.Key)
+
+	r, err := NewPersistentRecorder(&params.ProxyCfg.SlowQueryLog, &params.MinioCfg)
+	require.NoError(t, err)
+	r.Record(newSlowQuery("c1", 3*time.Minute, 10))
+	r.Record(newSlowQuery("c1", 2*time.Minute, 20))
+	r.Record(newSlowQuery("c2", time.Minute, 30))
+	r.Close()
+
+	file := path.Join(dir, params.ProxyCfg.SlowQueryLog.Filename.GetValue())
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	// the partially written line is skipped.
+	_, err = f.WriteString(`{"collection":`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// the latest slow queries are reloaded after restart.
+	r, err = NewPersistentRecorder(&params.ProxyCfg.SlowQueryLog, &params.MinioCfg)
+	require.NoError(t, err)
+	defer r.Close()
+	entries := r.List(nil, 0)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "c2", entries[0].Collection)
+	assert.Equal(t, 20.0, entries[1].DurationMs)
+}
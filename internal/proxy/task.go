@@ -32,6 +32,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/proxy/shardclient"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/function"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/mq/msgstream"
@@ -40,6 +41,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/util/commonpbutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/v2/util/retry"
 	"github.com/milvus-io/milvus/pkg/v2/util/timestamptz"
@@ -168,6 +170,8 @@ type task interface {
 type baseTask struct {
 	onEnqueueTime time.Time
 	executingTime time.Time
+	// the latency of the stages the task went through in the scheduler.
+	stages []*metricsinfo.SlowQueryStage
 }
 
 func (bt *baseTask) CanSkipAllocTimestamp() bool {
@@ -194,6 +198,23 @@ func (bt *baseTask) GetDurationInExecuting() time.Duration {
 	return time.Since(bt.executingTime)
 }
 
+func (bt *baseTask) RecordStage(name string, duration time.Duration) {
+	bt.stages = append(bt.stages, &metricsinfo.SlowQueryStage{
+		Name:       name,
+		DurationMs: float64(duration.Microseconds()) / 1000.0,
+	})
+}
+
+func (bt *baseTask) GetStages() []*metricsinfo.SlowQueryStage {
+	return bt.stages
+}
+
+// stageRecorder records the latency of the stages the task went through in the scheduler.
+type stageRecorder interface {
+	RecordStage(name string, duration time.Duration)
+	GetStages() []*metricsinfo.SlowQueryStage
+}
+
 type dmlTask interface {
 	task
 	setChannels() error
@@ -397,6 +418,41 @@ func validateTTLField(props []*commonpb.KeyValuePair, fields []*schemapb.FieldSc
 	return false, nil
 }
 
+// validateCollectionCompactionTimeBucket checks the time-partitioned compaction properties,
+// the time bucket requires a timestamptz time field to bucket the rows by.
+func validateCollectionCompactionTimeBucket(props []*commonpb.KeyValuePair, fields []*schemapb.FieldSchema) error {
+	bucket, err := common.GetCollectionCompactionTimeBucket(props...)
+	if err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid collection compaction time bucket: %s", err.Error())
+	}
+	fieldName, hasTimeField := funcutil.TryGetAttrByKeyFromRepeatedKV(common.CollectionCompactionTimeFieldKey, props)
+	if hasTimeField && fieldName != "" {
+		found := false
+		for _, field := range fields {
+			if field.GetName() == fieldName {
+				if field.GetDataType() != schemapb.DataType_Timestamptz {
+					return merr.WrapErrParameterInvalidMsg("compaction time field must be timestamptz, field name = %s", fieldName)
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return merr.WrapErrParameterInvalidMsg("compaction time field name %s not found in schema", fieldName)
+		}
+	} else if bucket > 0 {
+		return merr.WrapErrParameterInvalidMsg("%s is required by %s", common.CollectionCompactionTimeFieldKey, common.CollectionCompactionTimeBucketKey)
+	}
+	return nil
+}
+
+func validateCollectionCompactionPriority(props []*commonpb.KeyValuePair) error {
+	if _, err := common.GetCollectionCompactionPriority(props...); err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid collection compaction priority: %s", err.Error())
+	}
+	return nil
+}
+
 func (t *createCollectionTask) validateTTL() error {
 	hasCollectionTTL, err := validateCollectionTTL(t.GetProperties())
 	if err != nil {
@@ -589,6 +645,14 @@ func (t *createCollectionTask) PreExecute(ctx context.Context) error {
 		return err
 	}
 
+	if err := validateCollectionCompactionPriority(t.GetProperties()); err != nil {
+		return err
+	}
+
+	if err := validateCollectionCompactionTimeBucket(t.GetProperties(), t.schema.GetFields()); err != nil {
+		return err
+	}
+
 	t.Schema, err = proto.Marshal(t.schema)
 	if err != nil {
 		return err
@@ -876,12 +940,13 @@ func (t *alterCollectionSchemaTask) PreExecute(ctx context.Context) error {
 		}
 	}
 
-	// Physical backfill is currently only implemented for BM25 in the datanode backfill
-	// compactor. Reject unsupported types early so the request never reaches RootCoord
-	// and no segment is left in an unrecoverable stale-schema state.
-	if addRequest.GetDoPhysicalBackfill() && funcSchemas[0].GetType() != schemapb.FunctionType_BM25 {
+	// Physical backfill is currently only implemented for BM25 and expression functions in the
+	// datanode backfill compactor. Reject unsupported types early so the request never reaches
+	// RootCoord and no segment is left in an unrecoverable stale-schema state.
+	if addRequest.GetDoPhysicalBackfill() && funcSchemas[0].GetType() != schemapb.FunctionType_BM25 &&
+		funcSchemas[0].GetType() != function.FunctionTypeExpression {
 		return merr.WrapErrParameterInvalidMsg(
-			"physical backfill is currently only supported for BM25 functions, got %s",
+			"physical backfill is currently only supported for BM25 and expression functions, got %s",
 			funcSchemas[0].GetType().String())
 	}
 
@@ -1775,6 +1840,20 @@ func (t *alterCollectionTask) PreExecute(ctx context.Context) error {
 			return merr.WrapErrParameterInvalidMsg("unknown or invalid IANA Time Zone ID: %s", userDefinedTimezone)
 		}
 
+		if err := validateCollectionCompactionPriority(t.GetProperties()); err != nil {
+			return err
+		}
+
+		// the new properties are validated together with the existing ones
+		propMap := common.KeyValuePairs(collSchema.GetProperties()).ToMap()
+		for _, prop := range t.GetProperties() {
+			propMap[prop.GetKey()] = prop.GetValue()
+		}
+		props := common.NewKeyValuePairs(propMap)
+		if err := validateCollectionCompactionTimeBucket(props, collSchema.GetFields()); err != nil {
+			return err
+		}
+
 		hasTTL, err := validateCollectionTTL(t.GetProperties())
 		if err != nil {
 			return err
@@ -1953,6 +2032,8 @@ var allowedAlterProps = []string{
 	common.WarmupScalarIndexKey,
 	common.WarmupVectorFieldKey,
 	common.WarmupVectorIndexKey,
+	common.JSONSchemaKey,
+	common.JSONPathTypesKey,
 }
 
 var allowedDropProps = []string{
@@ -1962,6 +2043,8 @@ var allowedDropProps = []string{
 	common.WarmupScalarIndexKey,
 	common.WarmupVectorFieldKey,
 	common.WarmupVectorIndexKey,
+	common.JSONSchemaKey,
+	common.JSONPathTypesKey,
 }
 
 func IsKeyAllowAlter(key string) bool {
@@ -2029,6 +2112,7 @@ func (t *alterCollectionFieldTask) PreExecute(ctx context.Context) error {
 	}
 
 	t.Properties = updatePropertiesKeys(t.Properties)
+	alterJSONSchema := false
 	for _, prop := range t.Properties {
 		if !IsKeyAllowAlter(prop.Key) {
 			return merr.WrapErrParameterInvalidMsg("%s does not allow update in collection field param", prop.Key)
@@ -2099,6 +2183,8 @@ func (t *alterCollectionFieldTask) PreExecute(ctx context.Context) error {
 			if maxCapacityPerRow > defaultMaxArrayCapacity || maxCapacityPerRow <= 0 {
 				return merr.WrapErrParameterInvalidMsg("the maximum capacity specified for a Array should be in (0, %d]", defaultMaxArrayCapacity)
 			}
+		case common.JSONSchemaKey, common.JSONPathTypesKey:
+			alterJSONSchema = true
 		}
 	}
 
@@ -2123,6 +2209,31 @@ func (t *alterCollectionFieldTask) PreExecute(ctx context.Context) error {
 	}
 	t.DeleteKeys = deleteKeys
 
+	// the json schema is validated against the type params of the field after altered,
+	// the documents already inserted are not validated again.
+	if alterJSONSchema {
+		var field *schemapb.FieldSchema
+		for _, f := range collSchema.Fields {
+			if f.GetName() == t.FieldName {
+				field = f
+				break
+			}
+		}
+		if field == nil {
+			return merr.WrapErrFieldNotFound(t.FieldName)
+		}
+		typeParams := common.KeyValuePairs(field.GetTypeParams()).ToMap()
+		for _, prop := range t.Properties {
+			typeParams[prop.Key] = prop.Value
+		}
+		for _, key := range t.DeleteKeys {
+			delete(typeParams, key)
+		}
+		if err := validateJSONSchema(field, common.NewKeyValuePairs(typeParams)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -20,6 +20,8 @@ import (
 	"github.com/milvus-io/milvus/internal/proxy/accesslog"
 	"github.com/milvus-io/milvus/internal/proxy/shardclient"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/costutil"
+	"github.com/milvus-io/milvus/internal/util/explainutil"
 	"github.com/milvus-io/milvus/internal/util/exprutil"
 	"github.com/milvus-io/milvus/internal/util/reduce"
 	"github.com/milvus-io/milvus/internal/util/reduce/orderby"
@@ -32,6 +34,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
 	"github.com/milvus-io/milvus/pkg/v2/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/contextutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
@@ -87,6 +90,11 @@ type queryTask struct {
 	resolvedTimezoneStr  string
 	storageCost          segcore.StorageCost
 	aggregationFieldMap  *agg.AggregationFieldMap
+	// requestCost is the resources consumed by the query nodes to serve the request.
+	requestCost costutil.Cost
+
+	// explainCollector collects the explanation of the request, nil if the request isn't explained.
+	explainCollector *explainutil.Collector
 }
 
 func (t *queryTask) getQueryLabel() string {
@@ -493,6 +501,10 @@ func (t *queryTask) createPlanArgs(ctx context.Context, visitorArgs *planparserv
 			return merr.WrapErrAsInputError(merr.WrapErrParameterInvalidMsg("failed to create query plan: %v", err))
 		}
 		metrics.ProxyParseExpressionLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), metrics.QueryLabel, metrics.SuccessLabel).Observe(float64(time.Since(start).Microseconds()) / 1000.0)
+		t.explainCollector.RecordStage("parse_expr", time.Since(start))
+		if err := explainExpr(t.explainCollector, schema.schemaHelper, t.request.Expr, t.request.GetExprTemplateValues(), visitorArgs); err != nil {
+			return err
+		}
 	}
 	// parse output fields names
 	originalOuputFields := t.request.GetOutputFields()
@@ -716,9 +728,18 @@ func (t *queryTask) PreExecute(ctx context.Context) error {
 		log.Debug("determine timezone from collection", zap.Any("collection timezone", t.resolvedTimezoneStr))
 	}
 
+	explainMode, err := explainutil.ParseMode(t.request.GetQueryParams())
+	if err != nil {
+		return err
+	}
+	t.explainCollector = explainutil.NewCollector(explainMode)
 	if err := t.createPlanArgs(ctx, &planparserv2.ParserVisitorArgs{Timezone: t.resolvedTimezoneStr}); err != nil {
 		return err
 	}
+	if err := explainIndexes(ctx, t.explainCollector, t.mixCoord, t.schema.CollectionSchema, t.CollectionID, t.plan); err != nil {
+		log.Warn("failed to explain the indexes of query", zap.Error(err))
+		return err
+	}
 	t.plan.GetQuery().Limit = t.Limit
 
 	// Aggregation queries have bounded result sizes:
@@ -751,6 +772,12 @@ func (t *queryTask) PreExecute(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		if !t.partitionKeyMode && len(t.request.GetPartitionNames()) > 0 {
+			if err := checkPartitionsLoadedOnDemand(ctx, t.mixCoord, colInfo, t.PartitionIDs, t.request.GetQueryParams()); err != nil {
+				log.Warn("partitions are not loaded on demand", zap.Error(err))
+				return err
+			}
+		}
 	}
 
 	// count(*) without GROUP BY is a single-value result, pagination is meaningless.
@@ -859,6 +886,7 @@ func (t *queryTask) Execute(ctx context.Context) error {
 		log.Warn("fail to execute query", zap.Error(err))
 		return errors.Wrap(err, "failed to query")
 	}
+	t.explainCollector.RecordStage("execute", tr.ElapseSpan())
 
 	log.Debug("Query Execute done.")
 	return nil
@@ -880,6 +908,7 @@ func (t *queryTask) PostExecute(ctx context.Context) error {
 	t.allQueryCnt = 0
 	t.totalRelatedDataSize = 0
 	t.storageCost = segcore.StorageCost{}
+	t.requestCost = costutil.Cost{}
 	select {
 	case <-t.TraceCtx().Done():
 		log.Warn("proxy", zap.Int64("Query: wait to finish failed, timeout!, msgID:", t.ID()))
@@ -892,6 +921,10 @@ func (t *queryTask) PostExecute(ctx context.Context) error {
 			t.storageCost.ScannedRemoteBytes += res.GetScannedRemoteBytes()
 			t.storageCost.ScannedTotalBytes += res.GetScannedTotalBytes()
 			t.totalRelatedDataSize += res.GetCostAggregation().GetTotalRelatedDataSize()
+			t.requestCost.AddAggregation(res.GetCostAggregation())
+			if err := t.explainCollector.Merge(res.GetStatus()); err != nil {
+				log.Warn("failed to merge the explanation of shard", zap.Error(err))
+			}
 			log.Debug("proxy receives one query result", zap.Int64("sourceID", res.GetBase().GetSourceID()))
 			return true
 		})
@@ -931,11 +964,13 @@ func (t *queryTask) PostExecute(ctx context.Context) error {
 		log.Warn("fail to create query pipeline", zap.Error(err))
 		return err
 	}
+	reduceStart := time.Now()
 	t.result, err = pipeline.Execute(ctx, toReduceResults)
 	if err != nil {
 		log.Warn("fail to reduce query result", zap.Error(err))
 		return err
 	}
+	t.explainCollector.RecordStage("reduce", time.Since(reduceStart))
 
 	// FieldName/Type/IsDynamic setting and timestamp column removal are now
 	// handled by complementFieldOperator in the pipeline (for non-aggregation queries).
@@ -988,6 +1023,7 @@ func (t *queryTask) IsSubTask() bool {
 
 func (t *queryTask) queryShard(ctx context.Context, nodeID int64, qn types.QueryNodeClient, channel string) error {
 	ctx = retry.WithMaxAttemptsContext(ctx, 1)
+	ctx = contextutil.ExplainModeHeader.With(ctx, string(t.explainCollector.Mode()))
 	needOverrideMvcc := false
 	mvccTs := t.MvccTimestamp
 	if len(t.channelsMvcc) > 0 {
@@ -511,7 +511,17 @@ func (sched *taskScheduler) processTask(t task, q taskQueue) {
 		WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), t.Type().String()).
 		Observe(float64(waitDuration.Microseconds()) / 1000.0)
 
+	recorder, _ := t.(stageRecorder)
+	recordStage := func(name string, duration time.Duration) {
+		if recorder != nil {
+			recorder.RecordStage(name, duration)
+		}
+	}
+	recordStage("queue", waitDuration)
+
+	start := time.Now()
 	err := t.PreExecute(ctx)
+	recordStage("pre_execute", time.Since(start))
 
 	defer func() {
 		t.Notify(err)
@@ -523,7 +533,9 @@ func (sched *taskScheduler) processTask(t task, q taskQueue) {
 	}
 
 	span.AddEvent("scheduler process Execute")
+	start = time.Now()
 	err = t.Execute(ctx)
+	recordStage("execute", time.Since(start))
 	if err != nil {
 		span.RecordError(err)
 		log.Ctx(ctx).Warn("Failed to execute task: ", zap.Error(err))
@@ -531,7 +543,9 @@ func (sched *taskScheduler) processTask(t task, q taskQueue) {
 	}
 
 	span.AddEvent("scheduler process PostExecute")
+	start = time.Now()
 	err = t.PostExecute(ctx)
+	recordStage("post_execute", time.Since(start))
 	if err != nil {
 		span.RecordError(err)
 		log.Ctx(ctx).Warn("Failed to post-execute task: ", zap.Error(err))
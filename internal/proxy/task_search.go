@@ -23,6 +23,8 @@ import (
 	"github.com/milvus-io/milvus/internal/proxy/accesslog"
 	"github.com/milvus-io/milvus/internal/proxy/shardclient"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/costutil"
+	"github.com/milvus-io/milvus/internal/util/explainutil"
 	"github.com/milvus-io/milvus/internal/util/exprutil"
 	"github.com/milvus-io/milvus/internal/util/function/embedding"
 	"github.com/milvus-io/milvus/internal/util/function/models"
@@ -35,6 +37,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
 	"github.com/milvus-io/milvus/pkg/v2/util/commonpbutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/contextutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/metric"
@@ -115,6 +118,12 @@ type searchTask struct {
 
 	storageCost  segcore.StorageCost
 	traceEnabled bool
+	// requestCost is the resources consumed by the query nodes to serve the request.
+	requestCost costutil.Cost
+
+	// explainCollector collects the explanation of the request, nil if the request isn't explained.
+	explainCollector *explainutil.Collector
+	explainPlans     []*planpb.PlanNode
 }
 
 func (t *searchTask) CanSkipAllocTimestamp() bool {
@@ -197,6 +206,10 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 			log.Warn("failed to get partition ids", zap.Error(err))
 			return err
 		}
+		if err := checkPartitionsLoadedOnDemand(ctx, t.mixCoord, collectionInfo, t.PartitionIDs, t.request.GetSearchParams()); err != nil {
+			log.Warn("partitions are not loaded on demand", zap.Error(err))
+			return err
+		}
 	}
 	err = common.CheckNamespace(t.schema.CollectionSchema, t.request.Namespace)
 	if err != nil {
@@ -244,6 +257,11 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 	// searches with small result size could no longer need requery.
 	traceVal, _ := funcutil.GetAttrByKeyFromRepeatedKV(PipelineTraceKey, t.request.GetSearchParams())
 	t.traceEnabled = strings.EqualFold(traceVal, "true")
+	explainMode, err := explainutil.ParseMode(t.request.GetSearchParams())
+	if err != nil {
+		return err
+	}
+	t.explainCollector = explainutil.NewCollector(explainMode)
 	if t.GetIsAdvanced() {
 		err = t.initAdvancedSearchRequest(ctx)
 	} else {
@@ -253,6 +271,10 @@ func (t *searchTask) PreExecute(ctx context.Context) error {
 		log.Debug("init search request failed", zap.Error(err))
 		return err
 	}
+	if err := explainIndexes(ctx, t.explainCollector, t.mixCoord, t.schema.CollectionSchema, t.CollectionID, t.explainPlans...); err != nil {
+		log.Warn("failed to explain the indexes of search", zap.Error(err))
+		return err
+	}
 
 	guaranteeTs := t.request.GetGuaranteeTimestamp()
 	var consistencyLevel commonpb.ConsistencyLevel
@@ -915,7 +937,8 @@ func (t *searchTask) tryGeneratePlan(params []*commonpb.KeyValuePair, dsl string
 	}
 
 	start := time.Now()
-	plan, planErr := planparserv2.CreateSearchPlanArgs(t.schema.schemaHelper, dsl, annsFieldName, searchInfo.planInfo, exprTemplateValues, t.request.GetFunctionScore(), &planparserv2.ParserVisitorArgs{Timezone: t.resolvedTimezoneStr})
+	visitorArgs := &planparserv2.ParserVisitorArgs{Timezone: t.resolvedTimezoneStr}
+	plan, planErr := planparserv2.CreateSearchPlanArgs(t.schema.schemaHelper, dsl, annsFieldName, searchInfo.planInfo, exprTemplateValues, t.request.GetFunctionScore(), visitorArgs)
 	if planErr != nil {
 		log.Ctx(t.ctx).Warn("failed to create query plan", zap.Error(planErr),
 			zap.String("dsl", dsl), // may be very large if large term passed.
@@ -924,6 +947,13 @@ func (t *searchTask) tryGeneratePlan(params []*commonpb.KeyValuePair, dsl string
 		return nil, nil, 0, false, nil, internalpb.SearchType_DEFAULT, merr.WrapErrParameterInvalidMsg("failed to create query plan: %v", planErr)
 	}
 	metrics.ProxyParseExpressionLatency.WithLabelValues(strconv.FormatInt(paramtable.GetNodeID(), 10), "search", metrics.SuccessLabel).Observe(float64(time.Since(start).Microseconds()) / 1000.0)
+	if t.explainCollector != nil {
+		t.explainCollector.RecordStage("parse_expr", time.Since(start))
+		if err := explainExpr(t.explainCollector, t.schema.schemaHelper, dsl, exprTemplateValues, visitorArgs); err != nil {
+			return nil, nil, 0, false, nil, internalpb.SearchType_DEFAULT, err
+		}
+		t.explainPlans = append(t.explainPlans, plan)
+	}
 	log.Ctx(t.ctx).Debug("create query plan",
 		zap.String("dsl", t.request.Dsl), // may be very large if large term passed.
 		zap.String("anns field", annsFieldName), zap.Any("query info", searchInfo.planInfo))
@@ -974,6 +1004,7 @@ func (t *searchTask) Execute(ctx context.Context) error {
 		log.Warn("search execute failed", zap.Error(err))
 		return errors.Wrap(err, "failed to search")
 	}
+	t.explainCollector.RecordStage("execute", tr.ElapseSpan())
 
 	log.Debug("Search Execute done.",
 		zap.Int64("collection", t.GetCollectionID()),
@@ -1030,6 +1061,7 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 
 	t.queryChannelsTs = make(map[string]uint64)
 	t.relatedDataSize = 0
+	t.requestCost = costutil.Cost{}
 	isTopkReduce := false
 	isRecallEvaluation := false
 	storageCost := segcore.StorageCost{}
@@ -1041,11 +1073,15 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 			isRecallEvaluation = true
 		}
 		t.relatedDataSize += r.GetCostAggregation().GetTotalRelatedDataSize()
+		t.requestCost.AddAggregation(r.GetCostAggregation())
 		for ch, ts := range r.GetChannelsMvcc() {
 			t.queryChannelsTs[ch] = ts
 		}
 		storageCost.ScannedRemoteBytes += r.GetScannedRemoteBytes()
 		storageCost.ScannedTotalBytes += r.GetScannedTotalBytes()
+		if err := t.explainCollector.Merge(r.GetStatus()); err != nil {
+			log.Warn("failed to merge the explanation of shard", zap.Error(err))
+		}
 	}
 
 	t.isTopkReduce = isTopkReduce
@@ -1057,7 +1093,7 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 		log.Warn("Faild to create post process pipeline")
 		return err
 	}
-	if t.result, t.storageCost, err = pipeline.Run(ctx, sp, toReduceResults, storageCost); err != nil {
+	if t.result, t.storageCost, err = pipeline.Run(explainutil.WithCollector(ctx, t.explainCollector), sp, toReduceResults, storageCost); err != nil {
 		return err
 	}
 	t.fillResult()
@@ -1151,6 +1187,7 @@ func (t *searchTask) PostExecute(ctx context.Context) error {
 
 func (t *searchTask) searchShard(ctx context.Context, nodeID int64, qn types.QueryNodeClient, channel string) error {
 	ctx = retry.WithMaxAttemptsContext(ctx, 1)
+	ctx = contextutil.ExplainModeHeader.With(ctx, string(t.explainCollector.Mode()))
 	searchReq := shallowcopy.ShallowCopySearchRequest(t.SearchRequest, nodeID)
 	req := &querypb.SearchRequest{
 		Req:             searchReq,
@@ -6713,6 +6713,42 @@ func TestValidateCollectionTTL(t *testing.T) {
 	}
 }
 
+func TestValidateCollectionCompactionPriority(t *testing.T) {
+	assert.NoError(t, validateCollectionCompactionPriority(nil))
+	assert.NoError(t, validateCollectionCompactionPriority([]*commonpb.KeyValuePair{{Key: common.CollectionCompactionPriorityKey, Value: "3"}}))
+	err := validateCollectionCompactionPriority([]*commonpb.KeyValuePair{{Key: common.CollectionCompactionPriorityKey, Value: "0"}})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	err = validateCollectionCompactionPriority([]*commonpb.KeyValuePair{{Key: common.CollectionCompactionPriorityKey, Value: "abc"}})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestValidateCollectionCompactionTimeBucket(t *testing.T) {
+	fields := []*schemapb.FieldSchema{
+		{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+		{FieldID: 101, Name: "event_time", DataType: schemapb.DataType_Timestamptz},
+		{FieldID: 102, Name: "count", DataType: schemapb.DataType_Int64},
+	}
+	timeField := func(name string) *commonpb.KeyValuePair {
+		return &commonpb.KeyValuePair{Key: common.CollectionCompactionTimeFieldKey, Value: name}
+	}
+	timeBucket := func(bucket string) *commonpb.KeyValuePair {
+		return &commonpb.KeyValuePair{Key: common.CollectionCompactionTimeBucketKey, Value: bucket}
+	}
+
+	assert.NoError(t, validateCollectionCompactionTimeBucket(nil, fields))
+	assert.NoError(t, validateCollectionCompactionTimeBucket([]*commonpb.KeyValuePair{timeField("event_time"), timeBucket("day")}, fields))
+	assert.NoError(t, validateCollectionCompactionTimeBucket([]*commonpb.KeyValuePair{timeField("event_time")}, fields))
+	err := validateCollectionCompactionTimeBucket([]*commonpb.KeyValuePair{timeField("event_time"), timeBucket("minute")}, fields)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	// the time bucket requires the time field
+	err = validateCollectionCompactionTimeBucket([]*commonpb.KeyValuePair{timeBucket("hour")}, fields)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	err = validateCollectionCompactionTimeBucket([]*commonpb.KeyValuePair{timeField("unknown"), timeBucket("hour")}, fields)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	err = validateCollectionCompactionTimeBucket([]*commonpb.KeyValuePair{timeField("count"), timeBucket("hour")}, fields)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
 func TestHasWarmupProp(t *testing.T) {
 	t.Run("has generic warmup key", func(t *testing.T) {
 		props := []*commonpb.KeyValuePair{
@@ -41,14 +41,18 @@ import (
 	"github.com/milvus-io/milvus/internal/parser/planparserv2"
 	"github.com/milvus-io/milvus/internal/proxy/privilege"
 	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/internal/util/costutil"
+	functionutil "github.com/milvus-io/milvus/internal/util/function"
 	"github.com/milvus-io/milvus/internal/util/function/embedding"
 	"github.com/milvus-io/milvus/internal/util/function/models"
 	"github.com/milvus-io/milvus/internal/util/hookutil"
 	"github.com/milvus-io/milvus/internal/util/indexparamcheck"
+	"github.com/milvus-io/milvus/internal/util/jsonschema"
 	"github.com/milvus-io/milvus/internal/util/segcore"
 	typeutil2 "github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
 	"github.com/milvus-io/milvus/pkg/v2/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
@@ -644,6 +648,35 @@ func ValidateField(field *schemapb.FieldSchema, schema *schemapb.CollectionSchem
 		}
 	}
 
+	if err = validateJSONSchema(field, field.GetTypeParams()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateJSONSchema checks the json schema or json path types in the type params of the field,
+// which can only be attached to json fields and must be matched by the default value of the field.
+func validateJSONSchema(field *schemapb.FieldSchema, typeParams []*commonpb.KeyValuePair) error {
+	attached := lo.ContainsBy(typeParams, func(param *commonpb.KeyValuePair) bool {
+		return param.GetKey() == common.JSONSchemaKey || param.GetKey() == common.JSONPathTypesKey
+	})
+	if !attached {
+		return nil
+	}
+	if field.GetDataType() != schemapb.DataType_JSON {
+		return merr.WrapErrParameterInvalidMsg("%s and %s can only be set on json fields, but field %s is %s",
+			common.JSONSchemaKey, common.JSONPathTypesKey, field.GetName(), field.GetDataType().String())
+	}
+	s, err := jsonschema.FromParams(typeParams)
+	if err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid json schema of field %s: %s", field.GetName(), err.Error())
+	}
+	if defaultValue := field.GetDefaultValue().GetBytesData(); defaultValue != nil {
+		if err := s.Validate(defaultValue); err != nil {
+			return merr.WrapErrParameterInvalidMsg("the default value of field %s doesn't match its json schema: %s", field.GetName(), err.Error())
+		}
+	}
 	return nil
 }
 
@@ -932,7 +965,8 @@ func validateFunction(coll *schemapb.CollectionSchema, needValidateFunctionName
 				return fmt.Errorf("function output field cannot be partition key or clustering key: function %s, field %s", function.GetName(), outputField.GetName())
 			}
 
-			if outputField.GetNullable() {
+			// the expression may compute null, e.g. from a nullable input field.
+			if outputField.GetNullable() && !functionutil.IsExpressionFunction(function) {
 				return fmt.Errorf("function output field cannot be nullable: function %s, field %s", function.GetName(), outputField.GetName())
 			}
 
@@ -977,6 +1011,13 @@ func checkFunctionOutputField(fSchema *schemapb.FunctionSchema, fields []*schema
 		if fields[0].GetDataType() != schemapb.DataType_BinaryVector {
 			return fmt.Errorf("MinHash function output field must be a BinaryVector field, but got %s", fields[0].DataType.String())
 		}
+	case functionutil.FunctionTypeExpression:
+		if len(fields) != 1 {
+			return fmt.Errorf("Expression function only need 1 output field, but got %d", len(fields))
+		}
+		if dataType := fields[0].GetDataType(); !typeutil.IsBoolType(dataType) && !typeutil.IsArithmetic(dataType) && dataType != schemapb.DataType_VarChar {
+			return fmt.Errorf("Expression function output field must be a scalar field of bool, integer, float, double or varchar, but got %s", fields[0].DataType.String())
+		}
 	default:
 		return errors.New("check output field for unknown function type")
 	}
@@ -1003,6 +1044,13 @@ func checkFunctionInputField(function *schemapb.FunctionSchema, fields []*schema
 			return fmt.Errorf("MinHash function input field must be a VARCHAR/TEXT field, got %d field with type %s",
 				len(fields), fields[0].DataType.String())
 		}
+	case functionutil.FunctionTypeExpression:
+		for _, field := range fields {
+			if typeutil.IsVectorType(field.GetDataType()) || field.GetDataType() == schemapb.DataType_Text {
+				return fmt.Errorf("Expression function input field must be a scalar field, got field %s with type %s",
+					field.GetName(), field.GetDataType().String())
+			}
+		}
 	default:
 		return errors.New("check input field with unknown function type")
 	}
@@ -1051,6 +1099,10 @@ func checkFunctionBasicParams(function *schemapb.FunctionSchema) error {
 	case schemapb.FunctionType_MinHash:
 		// MinHash function can accept optional params
 		return nil
+	case functionutil.FunctionTypeExpression:
+		if len(function.GetParams()) != 1 || function.GetParams()[0].GetKey() != functionutil.ExpressionKey {
+			return fmt.Errorf("Expression function accepts only the param %s", functionutil.ExpressionKey)
+		}
 	default:
 		return errors.New("check function params with unknown function type")
 	}
@@ -2689,6 +2741,28 @@ func SetStorageCost(status *commonpb.Status, storageCost segcore.StorageCost) {
 	status.ExtraInfo["cache_hit_ratio"] = strconv.FormatFloat(cacheHitRatio, 'f', -1, 64)
 }
 
+// SetRequestCost sets the resources consumed by the search or query request into the extra info of status,
+// and accumulates them into the request cost metrics of the user and database.
+func SetRequestCost(status *commonpb.Status, cost costutil.Cost, storageCost segcore.StorageCost, opType string, dbName string, username string) {
+	if !merr.Ok(status) {
+		return
+	}
+	cost.SetScannedBytes(storageCost.ScannedRemoteBytes, storageCost.ScannedTotalBytes)
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+		// keep the default report_value for compatibility, see SetStorageCost.
+		status.ExtraInfo["report_value"] = strconv.Itoa(0)
+	}
+	cost.Attach(status)
+
+	nodeID := paramtable.GetStringNodeID()
+	for _, key := range costutil.Keys {
+		if value, _ := cost.Get(key); value > 0 {
+			metrics.ProxyRequestCost.WithLabelValues(nodeID, opType, dbName, username, key).Add(float64(value))
+		}
+	}
+}
+
 func GetCostValue(status *commonpb.Status) int {
 	if status == nil || status.ExtraInfo == nil {
 		return 0
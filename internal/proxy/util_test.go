@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -38,9 +39,14 @@ import (
 	"github.com/milvus-io/milvus/internal/json"
 	"github.com/milvus-io/milvus/internal/mocks"
 	"github.com/milvus-io/milvus/internal/proxy/privilege"
+	"github.com/milvus-io/milvus/internal/util/costutil"
+	"github.com/milvus-io/milvus/internal/util/function"
 	"github.com/milvus-io/milvus/internal/util/function/embedding"
+	"github.com/milvus-io/milvus/internal/util/hookutil"
+	"github.com/milvus-io/milvus/internal/util/segcore"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
 	"github.com/milvus-io/milvus/pkg/v2/mq/msgstream"
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
@@ -2498,6 +2504,29 @@ func TestNewContextWithMetadata(t *testing.T) {
 	})
 }
 
+func TestSetRequestCost(t *testing.T) {
+	paramtable.Init()
+	cost := costutil.Cost{SegmentRows: 100, SegmentsTouched: 2, DistanceComputations: 800, SegmentTimeUs: 30}
+	storageCost := segcore.StorageCost{ScannedRemoteBytes: 10, ScannedTotalBytes: 40}
+
+	failed := merr.Status(merr.ErrServiceInternal)
+	SetRequestCost(failed, cost, storageCost, hookutil.OpTypeSearch, "db", "user")
+	assert.Empty(t, failed.GetExtraInfo())
+
+	status := merr.Success()
+	SetRequestCost(status, cost, storageCost, hookutil.OpTypeSearch, "db", "user")
+	assert.Equal(t, "0", status.GetExtraInfo()["report_value"])
+	assert.Equal(t, "100", status.GetExtraInfo()[costutil.SegmentRowsKey])
+	assert.Equal(t, "2", status.GetExtraInfo()[costutil.SegmentsTouchedKey])
+	assert.Equal(t, "30", status.GetExtraInfo()[costutil.CacheBytesKey])
+	assert.Equal(t, "10", status.GetExtraInfo()[costutil.DiskBytesKey])
+	assert.Equal(t, "800", status.GetExtraInfo()[costutil.DistanceComputationsKey])
+	assert.Equal(t, "30", status.GetExtraInfo()[costutil.SegmentTimeKey])
+
+	counter := metrics.ProxyRequestCost.WithLabelValues(paramtable.GetStringNodeID(), hookutil.OpTypeSearch, "db", "user", costutil.SegmentRowsKey)
+	assert.Equal(t, float64(100), testutil.ToFloat64(counter))
+}
+
 func TestGetCostValue(t *testing.T) {
 	t.Run("empty status", func(t *testing.T) {
 		{
@@ -2873,6 +2902,51 @@ func TestValidateFunction(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "function output field cannot be nullable")
 	})
+
+	t.Run("Valid expression function schema", func(t *testing.T) {
+		schema := &schemapb.CollectionSchema{
+			Fields: []*schemapb.FieldSchema{
+				{Name: "title", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{{Key: "max_length", Value: "64"}}, Nullable: true},
+				{Name: "lower_title", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{{Key: "max_length", Value: "64"}}, Nullable: true},
+			},
+			Functions: []*schemapb.FunctionSchema{
+				{
+					Name:             "lower_title_func",
+					Type:             function.FunctionTypeExpression,
+					InputFieldNames:  []string{"title"},
+					OutputFieldNames: []string{"lower_title"},
+					Params:           []*commonpb.KeyValuePair{{Key: function.ExpressionKey, Value: "lower(title)"}},
+				},
+			},
+		}
+		err := validateFunction(schema, "", false)
+		assert.NoError(t, err)
+		assert.True(t, schema.Fields[1].GetIsFunctionOutput())
+	})
+
+	t.Run("Invalid expression function schema", func(t *testing.T) {
+		schema := &schemapb.CollectionSchema{
+			Fields: []*schemapb.FieldSchema{
+				{Name: "title", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{{Key: "max_length", Value: "64"}}},
+				{Name: "title_len", DataType: schemapb.DataType_VarChar, TypeParams: []*commonpb.KeyValuePair{{Key: "max_length", Value: "64"}}},
+			},
+			Functions: []*schemapb.FunctionSchema{
+				{
+					Name:             "title_len_func",
+					Type:             function.FunctionTypeExpression,
+					InputFieldNames:  []string{"title"},
+					OutputFieldNames: []string{"title_len"},
+					Params:           []*commonpb.KeyValuePair{{Key: function.ExpressionKey, Value: "len(title)"}},
+				},
+			},
+		}
+		err := validateFunction(schema, "", false)
+		assert.Error(t, err)
+
+		schema.Functions[0].Params = nil
+		err = validateFunction(schema, "", false)
+		assert.Error(t, err)
+	})
 }
 
 func TestValidateModelFunction(t *testing.T) {
@@ -5527,3 +5601,37 @@ func TestInjectVirtualPKForExternalCollection(t *testing.T) {
 		assert.Equal(t, int64(0), schema.Fields[0].FieldID)
 	})
 }
+
+func TestValidateJSONSchema(t *testing.T) {
+	field := &schemapb.FieldSchema{Name: "json", DataType: schemapb.DataType_JSON}
+	assert.NoError(t, validateJSONSchema(field, nil))
+	assert.NoError(t, validateJSONSchema(field, []*commonpb.KeyValuePair{
+		{Key: common.JSONSchemaKey, Value: `{"properties": {"a": {"type": "integer"}}}`},
+	}))
+	assert.NoError(t, validateJSONSchema(field, []*commonpb.KeyValuePair{
+		{Key: common.JSONPathTypesKey, Value: `{"a": "int64"}`},
+	}))
+
+	err := validateJSONSchema(field, []*commonpb.KeyValuePair{
+		{Key: common.JSONSchemaKey, Value: `{"properties": {"a": {"anyOf": []}}}`},
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	err = validateJSONSchema(field, []*commonpb.KeyValuePair{
+		{Key: common.JSONSchemaKey, Value: `true`},
+		{Key: common.JSONPathTypesKey, Value: `{"a": "int64"}`},
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	// the default value must match the schema
+	field.DefaultValue = &schemapb.ValueField{Data: &schemapb.ValueField_BytesData{BytesData: []byte(`{"a": "1"}`)}}
+	err = validateJSONSchema(field, []*commonpb.KeyValuePair{
+		{Key: common.JSONPathTypesKey, Value: `{"a": "int64"}`},
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+
+	err = validateJSONSchema(&schemapb.FieldSchema{Name: "varchar", DataType: schemapb.DataType_VarChar}, []*commonpb.KeyValuePair{
+		{Key: common.JSONPathTypesKey, Value: `{"a": "int64"}`},
+	})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/util/jsonschema"
 	"github.com/milvus-io/milvus/internal/util/nullutil"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
@@ -947,6 +948,31 @@ func (v *validateUtil) checkJSONFieldData(field *schemapb.FieldData, fieldSchema
 			}
 		}
 	}
+
+	return checkJSONSchema(field, fieldSchema)
+}
+
+// checkJSONSchema validates the json documents against the json schema attached to the field,
+// the null rows are skipped.
+func checkJSONSchema(field *schemapb.FieldData, fieldSchema *schemapb.FieldSchema) error {
+	s, err := jsonschema.FromField(fieldSchema)
+	if err != nil {
+		return merr.WrapErrParameterInvalidMsg("invalid json schema of field %s: %s", fieldSchema.GetName(), err.Error())
+	}
+	if s == nil {
+		return nil
+	}
+	jsonArray := field.GetScalars().GetJsonData().GetData()
+	validData := field.GetValidData()
+	for i, doc := range jsonArray {
+		if len(validData) == len(jsonArray) && !validData[i] {
+			continue
+		}
+		if err := s.Validate(doc); err != nil {
+			return merr.WrapErrParameterInvalidMsg("json field %s doesn't match its json schema, row number: %d, %s",
+				fieldSchema.GetName(), i, err.Error())
+		}
+	}
 	return nil
 }
 
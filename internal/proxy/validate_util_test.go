@@ -8191,3 +8191,48 @@ func Test_MetaNullableCompat_v25_vs_v26(t *testing.T) {
 		assert.Equal(t, numRows, len(fieldData.GetValidData()), "nullable $meta should have ValidData")
 	})
 }
+
+func Test_validateUtil_checkJSONSchema(t *testing.T) {
+	v := newValidateUtil()
+	f := &schemapb.FieldSchema{
+		Name:     "json",
+		DataType: schemapb.DataType_JSON,
+		Nullable: true,
+		TypeParams: []*commonpb.KeyValuePair{
+			{Key: common.JSONPathTypesKey, Value: `{"price": "double", "attrs.color": "varchar"}`},
+		},
+	}
+	newData := func(validData []bool, docs ...string) *schemapb.FieldData {
+		data := make([][]byte, 0, len(docs))
+		for _, doc := range docs {
+			data = append(data, []byte(doc))
+		}
+		return &schemapb.FieldData{
+			Field: &schemapb.FieldData_Scalars{
+				Scalars: &schemapb.ScalarField{
+					Data: &schemapb.ScalarField_JsonData{
+						JsonData: &schemapb.JSONArray{Data: data},
+					},
+				},
+			},
+			ValidData: validData,
+		}
+	}
+
+	err := v.checkJSONFieldData(newData(nil, `{"price": 1}`, `{"attrs": {"color": "red"}}`, `{}`), f)
+	assert.NoError(t, err)
+
+	err = v.checkJSONFieldData(newData(nil, `{"price": 1}`, `{"attrs": {"color": 1}}`), f)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+	assert.Contains(t, err.Error(), "row number: 1")
+
+	// the null rows are not validated
+	err = v.checkJSONFieldData(newData([]bool{true, false}, `{"price": 1}`, `{"price": "1"}`), f)
+	assert.NoError(t, err)
+
+	f.TypeParams = []*commonpb.KeyValuePair{
+		{Key: common.JSONSchemaKey, Value: `{"type": "object", "required": ["price"]}`},
+	}
+	err = v.checkJSONFieldData(newData(nil, `{"price": 1}`, `{"color": "red"}`), f)
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
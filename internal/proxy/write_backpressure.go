@@ -0,0 +1,212 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/types"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+const (
+	// RetryAfterKey is the extra info key of the response status carrying the retry-after hint in milliseconds.
+	RetryAfterKey = "retry_after_ms"
+	// RetryAfterHeader is the grpc and http header carrying the retry-after hint in seconds.
+	RetryAfterHeader = "retry-after"
+
+	minBackpressureRecheckInterval = 10 * time.Millisecond
+)
+
+// retryAfterError is a rejection carrying the time the client is suggested to wait before retrying.
+type retryAfterError struct {
+	error
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.error
+}
+
+func withRetryAfter(err error, retryAfter time.Duration) error {
+	return &retryAfterError{error: err, retryAfter: max(retryAfter, time.Second)}
+}
+
+// GetRetryAfter returns the retry-after hint carried by the error.
+func GetRetryAfter(err error) (time.Duration, bool) {
+	var rae *retryAfterError
+	if errors.As(err, &rae) {
+		return rae.retryAfter, true
+	}
+	return 0, false
+}
+
+// RetryAfterSeconds rounds the retry-after hint up to seconds, as the Retry-After header expects.
+func RetryAfterSeconds(retryAfter time.Duration) int {
+	return int((retryAfter + time.Second - 1) / time.Second)
+}
+
+// SetRetryAfterHint sets the retry-after hint into the grpc header and the status of the response.
+func SetRetryAfterHint(ctx context.Context, rsp any, retryAfter time.Duration) {
+	// not a grpc call if failed, e.g. from the restful server.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, strconv.Itoa(RetryAfterSeconds(retryAfter))))
+
+	var status *commonpb.Status
+	switch r := rsp.(type) {
+	case *commonpb.Status:
+		status = r
+	case interface{ GetStatus() *commonpb.Status }:
+		status = r.GetStatus()
+	}
+	if status == nil {
+		return
+	}
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+	}
+	status.ExtraInfo[RetryAfterKey] = strconv.FormatInt(retryAfter.Milliseconds(), 10)
+}
+
+func isBackpressureRateType(rt internalpb.RateType) bool {
+	// upsert shares the insert rate type.
+	return rt == internalpb.RateType_DMLInsert || rt == internalpb.RateType_DMLDelete
+}
+
+// CheckLimiterWithBackpressure checks if the request would be limited or denied.
+// If write backpressure is enabled, an insert, upsert or delete request denied by the quota center
+// is delayed and rechecked instead of rejected at once. A request rejected after the delay, or
+// rejected because the delay queue is saturated, carries a retry-after hint, see GetRetryAfter.
+func CheckLimiterWithBackpressure(ctx context.Context, limiter types.Limiter, dbID int64,
+	collectionIDToPartIDs map[int64][]int64, rt internalpb.RateType, n int,
+) error {
+	err := limiter.Check(dbID, collectionIDToPartIDs, rt, n)
+	if err == nil || !isBackpressureRateType(rt) ||
+		!Params.QuotaConfig.WriteBackpressureEnabled.GetAsBool() ||
+		!errors.Is(err, merr.ErrServiceQuotaExceeded) {
+		return err
+	}
+	return globalWriteBackpressure.wait(ctx, dbID, err, func() error {
+		return limiter.Check(dbID, collectionIDToPartIDs, rt, n)
+	})
+}
+
+// writeBackpressure bounds the write requests delayed in the proxy.
+// The capacity is shared fairly by the databases with delayed requests, so one database
+// flooding writes can not occupy the whole queue.
+type writeBackpressure struct {
+	mu      sync.Mutex
+	waiting map[int64]int // dbID -> number of delayed requests
+	total   int
+}
+
+var globalWriteBackpressure = newWriteBackpressure()
+
+func newWriteBackpressure() *writeBackpressure {
+	return &writeBackpressure{
+		waiting: make(map[int64]int),
+	}
+}
+
+// acquire takes a slot of the queue for the database, returns false if the queue is
+// full or the database has used up its share.
+func (b *writeBackpressure) acquire(dbID int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	capacity := Params.QuotaConfig.WriteBackpressureQueueCapacity.GetAsInt()
+	if b.total >= capacity {
+		return false
+	}
+	dbNum := len(b.waiting)
+	if _, ok := b.waiting[dbID]; !ok {
+		dbNum++
+	}
+	share := max(1, capacity/dbNum)
+	if b.waiting[dbID] >= share {
+		return false
+	}
+	b.waiting[dbID]++
+	b.total++
+	return true
+}
+
+func (b *writeBackpressure) release(dbID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.waiting[dbID]--
+	if b.waiting[dbID] <= 0 {
+		delete(b.waiting, dbID)
+	}
+	b.total--
+}
+
+// waitingNum returns the number of delayed requests of the database.
+func (b *writeBackpressure) waitingNum(dbID int64) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.waiting[dbID]
+}
+
+// wait delays the denied request and rechecks it until it passes, the max wait is
+// reached or the request is canceled.
+func (b *writeBackpressure) wait(ctx context.Context, dbID int64, err error, check func() error) error {
+	maxWait := Params.QuotaConfig.WriteBackpressureMaxWait.GetAsDuration(time.Second)
+	if maxWait <= 0 {
+		return err
+	}
+	if !b.acquire(dbID) {
+		log.Ctx(ctx).RatedInfo(10, "write backpressure queue is saturated, reject the write request",
+			zap.Int64("dbID", dbID))
+		return withRetryAfter(err, maxWait)
+	}
+	defer b.release(dbID)
+
+	interval := max(Params.QuotaConfig.WriteBackpressureRecheckInterval.GetAsDuration(time.Second), minBackpressureRecheckInterval)
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return withRetryAfter(err, maxWait)
+		case <-timer.C:
+			log.Ctx(ctx).RatedInfo(10, "write request is still denied after the backpressure delay",
+				zap.Int64("dbID", dbID), zap.Duration("maxWait", maxWait), zap.Error(err))
+			return withRetryAfter(err, maxWait)
+		case <-ticker.C:
+			err = check()
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, merr.ErrServiceQuotaExceeded) {
+				// e.g. rate limited, which the clients retry on their own.
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// deniedLimiter denies the requests until it is allowed.
+type deniedLimiter struct {
+	allowed atomic.Bool
+	checked atomic.Int32
+}
+
+func (l *deniedLimiter) Check(dbID int64, collectionIDToPartIDs map[int64][]int64, rt internalpb.RateType, n int) error {
+	l.checked.Inc()
+	if l.allowed.Load() {
+		return nil
+	}
+	return merr.WrapErrServiceQuotaExceeded("memory quota exceeded")
+}
+
+func (l *deniedLimiter) Alloc(ctx context.Context, dbID int64, collectionIDToPartIDs map[int64][]int64, rt internalpb.RateType, n int) error {
+	return l.Check(dbID, collectionIDToPartIDs, rt, n)
+}
+
+func saveWriteBackpressureParams(t *testing.T, enabled string, maxWait string, capacity string) {
+	params := paramtable.Get()
+	params.Save(params.QuotaConfig.WriteBackpressureEnabled.Key, enabled)
+	params.Save(params.QuotaConfig.WriteBackpressureMaxWait.Key, maxWait)
+	params.Save(params.QuotaConfig.WriteBackpressureRecheckInterval.Key, "0.01")
+	params.Save(params.QuotaConfig.WriteBackpressureQueueCapacity.Key, capacity)
+	t.Cleanup(func() {
+		params.Reset(params.QuotaConfig.WriteBackpressureEnabled.Key)
+		params.Reset(params.QuotaConfig.WriteBackpressureMaxWait.Key)
+		params.Reset(params.QuotaConfig.WriteBackpressureRecheckInterval.Key)
+		params.Reset(params.QuotaConfig.WriteBackpressureQueueCapacity.Key)
+	})
+}
+
+func TestCheckLimiterWithBackpressure(t *testing.T) {
+	paramtable.Init()
+	ctx := context.Background()
+
+	t.Run("disabled", func(t *testing.T) {
+		saveWriteBackpressureParams(t, "false", "1", "8")
+		limiter := &deniedLimiter{}
+		err := CheckLimiterWithBackpressure(ctx, limiter, 1, nil, internalpb.RateType_DMLInsert, 1)
+		assert.ErrorIs(t, err, merr.ErrServiceQuotaExceeded)
+		_, ok := GetRetryAfter(err)
+		assert.False(t, ok)
+		assert.Equal(t, int32(1), limiter.checked.Load())
+	})
+
+	t.Run("not a write request", func(t *testing.T) {
+		saveWriteBackpressureParams(t, "true", "1", "8")
+		limiter := &deniedLimiter{}
+		err := CheckLimiterWithBackpressure(ctx, limiter, 1, nil, internalpb.RateType_DQLSearch, 1)
+		assert.ErrorIs(t, err, merr.ErrServiceQuotaExceeded)
+		assert.Equal(t, int32(1), limiter.checked.Load())
+	})
+
+	t.Run("delayed until allowed", func(t *testing.T) {
+		saveWriteBackpressureParams(t, "true", "10", "8")
+		limiter := &deniedLimiter{}
+		time.AfterFunc(50*time.Millisecond, func() { limiter.allowed.Store(true) })
+		err := CheckLimiterWithBackpressure(ctx, limiter, 1, nil, internalpb.RateType_DMLDelete, 1)
+		assert.NoError(t, err)
+		assert.Greater(t, limiter.checked.Load(), int32(1))
+		assert.Equal(t, 0, globalWriteBackpressure.waitingNum(1))
+	})
+
+	t.Run("rejected with retry after", func(t *testing.T) {
+		saveWriteBackpressureParams(t, "true", "0.05", "8")
+		limiter := &deniedLimiter{}
+		err := CheckLimiterWithBackpressure(ctx, limiter, 1, nil, internalpb.RateType_DMLInsert, 1)
+		assert.ErrorIs(t, err, merr.ErrServiceQuotaExceeded)
+		assert.Equal(t, merr.Code(merr.ErrServiceQuotaExceeded), merr.Code(err))
+		retryAfter, ok := GetRetryAfter(err)
+		assert.True(t, ok)
+		assert.Equal(t, time.Second, retryAfter)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		saveWriteBackpressureParams(t, "true", "10", "8")
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		err := CheckLimiterWithBackpressure(ctx, &deniedLimiter{}, 1, nil, internalpb.RateType_DMLInsert, 1)
+		assert.ErrorIs(t, err, merr.ErrServiceQuotaExceeded)
+		retryAfter, ok := GetRetryAfter(err)
+		assert.True(t, ok)
+		assert.Equal(t, 10*time.Second, retryAfter)
+	})
+}
+
+func TestWriteBackpressureFairShare(t *testing.T) {
+	paramtable.Init()
+	saveWriteBackpressureParams(t, "true", "10", "4")
+	b := newWriteBackpressure()
+
+	// a single database can take the whole queue.
+	assert.True(t, b.acquire(1))
+	assert.True(t, b.acquire(1))
+	assert.True(t, b.acquire(1))
+	b.release(1)
+
+	// another database gets its share, the first one is capped at half of the capacity.
+	assert.True(t, b.acquire(2))
+	assert.False(t, b.acquire(1))
+	assert.True(t, b.acquire(2))
+	assert.Equal(t, 2, b.waitingNum(1))
+	assert.Equal(t, 2, b.waitingNum(2))
+
+	// the queue is full.
+	assert.False(t, b.acquire(3))
+
+	b.release(1)
+	b.release(1)
+	assert.Equal(t, 0, b.waitingNum(1))
+	assert.True(t, b.acquire(3))
+}
+
+func TestSetRetryAfterHint(t *testing.T) {
+	rsp := GetFailedResponse(&milvuspb.InsertRequest{}, merr.ErrServiceQuotaExceeded)
+	SetRetryAfterHint(context.Background(), rsp, 1500*time.Millisecond)
+	assert.Equal(t, "1500", rsp.(*milvuspb.MutationResult).GetStatus().GetExtraInfo()[RetryAfterKey])
+	assert.Equal(t, 2, RetryAfterSeconds(1500*time.Millisecond))
+	assert.Equal(t, 1, RetryAfterSeconds(time.Second))
+
+	// no panic for a response without status.
+	SetRetryAfterHint(context.Background(), nil, time.Second)
+}
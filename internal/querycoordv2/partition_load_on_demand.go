@@ -0,0 +1,455 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycoordv2
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/querycoordv2/job"
+	"github.com/milvus-io/milvus/internal/querycoordv2/meta"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/syncutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// NewPartitionLoadOnDemand creates a new partition load on demand manager.
+func NewPartitionLoadOnDemand(s *Server) *PartitionLoadOnDemand {
+	p := &PartitionLoadOnDemand{
+		triggerCh:   make(chan struct{}, 1),
+		notifier:    syncutil.NewAsyncTaskNotifier[struct{}](),
+		s:           s,
+		lastAccess:  make(map[int64]map[int64]time.Time),
+		pending:     make(map[int64]typeutil.UniqueSet),
+		loading:     typeutil.NewUniqueSet(),
+		collections: make(map[int64]*onDemandCollection),
+	}
+	p.SetLogger(log.With(log.FieldModule(typeutil.QueryCoordRole), log.FieldComponent("partition_load_on_demand")))
+	go p.background()
+	return p
+}
+
+// PartitionLoadOnDemand loads the partitions of the collections with property partition.loadOnDemand on first access,
+// and releases the least recently accessed partitions of them when a resource group exceeds its memory budget.
+// The access of partitions is reported by proxies on search and query through ShowLoadPartitions
+// with the partition access header, other calls of ShowLoadPartitions don't count as access.
+type PartitionLoadOnDemand struct {
+	log.Binder
+	triggerCh chan struct{}
+	notifier  *syncutil.AsyncTaskNotifier[struct{}]
+	s         *Server
+
+	mu         sync.Mutex
+	lastAccess map[int64]map[int64]time.Time // collectionID -> partitionID -> last access time
+	pending    map[int64]typeutil.UniqueSet  // collectionID -> partitions to load
+	loading    typeutil.UniqueSet            // partitions pending or being loaded
+
+	// collections is only accessed by the background goroutine.
+	collections map[int64]*onDemandCollection
+}
+
+// onDemandCollectionCacheTTL is how long a described collection is cached, changes of the collection properties
+// take effect on partition load on demand within the ttl.
+const onDemandCollectionCacheTTL = time.Minute
+
+// onDemandCollection caches the collection described from rootcoord, so that the eviction check
+// doesn't describe every loaded collection on every tick.
+type onDemandCollection struct {
+	info          *milvuspb.DescribeCollectionResponse
+	enabled       bool
+	partitionIDs  typeutil.UniqueSet // only set if partition load on demand is enabled
+	sizePerRecord int
+	updateTime    time.Time
+}
+
+// describeCollection returns the cached collection, the collection is described again if the cache expires or refresh is set.
+func (p *PartitionLoadOnDemand) describeCollection(ctx context.Context, collectionID int64, refresh bool) (*onDemandCollection, error) {
+	if cached, ok := p.collections[collectionID]; ok && !refresh && time.Since(cached.updateTime) < onDemandCollectionCacheTTL {
+		return cached, nil
+	}
+	info, err := p.s.broker.DescribeCollection(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	cached := &onDemandCollection{
+		info:       info,
+		enabled:    common.IsPartitionLoadOnDemandEnabled(info.GetProperties()...),
+		updateTime: time.Now(),
+	}
+	if cached.enabled {
+		partitionIDs, err := p.s.broker.GetPartitions(ctx, collectionID)
+		if err != nil {
+			return nil, err
+		}
+		cached.partitionIDs = typeutil.NewUniqueSet(partitionIDs...)
+		if cached.sizePerRecord, err = typeutil.EstimateSizePerRecord(info.GetSchema()); err != nil {
+			return nil, err
+		}
+	}
+	p.collections[collectionID] = cached
+	return cached, nil
+}
+
+// Access records the access of the partitions and triggers the loading of the unloaded ones.
+func (p *PartitionLoadOnDemand) Access(ctx context.Context, collectionID int64, partitionIDs ...int64) {
+	if p == nil || !paramtable.Get().QueryCoordCfg.PartitionLoadOnDemandEnabled.GetAsBool() || len(partitionIDs) == 0 {
+		return
+	}
+
+	now := time.Now()
+	triggered := false
+	p.mu.Lock()
+	accessed, ok := p.lastAccess[collectionID]
+	if !ok {
+		accessed = make(map[int64]time.Time)
+		p.lastAccess[collectionID] = accessed
+	}
+	for _, partitionID := range partitionIDs {
+		accessed[partitionID] = now
+		if p.loading.Contain(partitionID) || p.s.meta.GetPartition(ctx, partitionID) != nil {
+			continue
+		}
+		if _, ok := p.pending[collectionID]; !ok {
+			p.pending[collectionID] = typeutil.NewUniqueSet()
+		}
+		p.pending[collectionID].Insert(partitionID)
+		p.loading.Insert(partitionID)
+		triggered = true
+	}
+	p.mu.Unlock()
+
+	if triggered {
+		p.trigger()
+	}
+}
+
+func (p *PartitionLoadOnDemand) trigger() {
+	select {
+	case p.triggerCh <- struct{}{}:
+	default:
+	}
+}
+
+func (p *PartitionLoadOnDemand) background() {
+	defer func() {
+		p.notifier.Finish(struct{}{})
+		p.Logger().Info("partition load on demand stopped")
+	}()
+	p.Logger().Info("partition load on demand started")
+
+	ctx := p.notifier.Context()
+	timer := time.NewTimer(paramtable.Get().QueryCoordCfg.PartitionLoadOnDemandCheckInterval.GetAsDuration(time.Second))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.triggerCh:
+			p.loadPending(ctx)
+		case <-timer.C:
+			if paramtable.Get().QueryCoordCfg.PartitionLoadOnDemandEnabled.GetAsBool() {
+				p.evict(ctx)
+			}
+			timer.Reset(paramtable.Get().QueryCoordCfg.PartitionLoadOnDemandCheckInterval.GetAsDuration(time.Second))
+		}
+	}
+}
+
+// loadPending loads the partitions accessed but not loaded.
+func (p *PartitionLoadOnDemand) loadPending(ctx context.Context) {
+	p.mu.Lock()
+	pending := p.pending
+	p.pending = make(map[int64]typeutil.UniqueSet)
+	p.mu.Unlock()
+
+	for collectionID, partitions := range pending {
+		partitionIDs := partitions.Collect()
+		if err := p.load(ctx, collectionID, partitionIDs); err != nil {
+			p.Logger().Info("skip loading partitions on demand",
+				zap.Int64("collectionID", collectionID), zap.Int64s("partitionIDs", partitionIDs), zap.Error(err))
+		} else {
+			p.Logger().Info("load partitions on demand", zap.Int64("collectionID", collectionID), zap.Int64s("partitionIDs", partitionIDs))
+			metrics.QueryCoordOnDemandPartitionCount.WithLabelValues(metrics.LoadedLabel).Add(float64(len(partitionIDs)))
+		}
+		p.mu.Lock()
+		p.loading.Remove(partitionIDs...)
+		p.mu.Unlock()
+	}
+}
+
+// load loads the partitions, the collection is described again if any partition is not in the cache,
+// the partition may be created after the collection is cached.
+func (p *PartitionLoadOnDemand) load(ctx context.Context, collectionID int64, partitionIDs []int64) error {
+	coll, err := p.describeCollection(ctx, collectionID, false)
+	if err != nil {
+		return err
+	}
+	if coll.enabled && !coll.partitionIDs.Contain(partitionIDs...) {
+		if coll, err = p.describeCollection(ctx, collectionID, true); err != nil {
+			return err
+		}
+	}
+	return p.s.loadPartitionsOnDemand(ctx, coll, partitionIDs)
+}
+
+// onDemandPartition is a loaded partition of an on demand collection.
+type onDemandPartition struct {
+	collectionID   int64
+	partitionID    int64
+	memorySize     int64
+	lastAccess     time.Time
+	resourceGroups []string
+}
+
+// evict releases the least recently accessed partitions of the resource groups exceeding their memory budget.
+func (p *PartitionLoadOnDemand) evict(ctx context.Context) {
+	partitions, partitionNum := p.collectPartitions(ctx)
+	usage := make(map[string]int64)
+	for _, partition := range partitions {
+		for _, rg := range partition.resourceGroups {
+			usage[rg] += partition.memorySize
+		}
+	}
+	for rg, size := range usage {
+		metrics.QueryCoordOnDemandPartitionMemory.WithLabelValues(rg).Set(float64(size))
+	}
+
+	budgets := getPartitionLoadOnDemandBudgets(lo.Keys(usage))
+	minIdleTime := paramtable.Get().QueryCoordCfg.PartitionLoadOnDemandMinIdleTime.GetAsDuration(time.Second)
+	toRelease := planPartitionEviction(partitions, partitionNum, budgets, minIdleTime, time.Now())
+	for collectionID, partitionIDs := range toRelease {
+		_, err := p.s.broadcastAlterLoadConfigCollectionV2ForReleasePartitions(ctx, &querypb.ReleasePartitionsRequest{
+			CollectionID: collectionID,
+			PartitionIDs: partitionIDs,
+		})
+		if err != nil && !errors.Is(err, job.ErrIgnoredAlterLoadConfig) {
+			p.Logger().Warn("failed to evict partitions", zap.Int64("collectionID", collectionID), zap.Int64s("partitionIDs", partitionIDs), zap.Error(err))
+			continue
+		}
+		p.Logger().Info("evict cold partitions", zap.Int64("collectionID", collectionID), zap.Int64s("partitionIDs", partitionIDs))
+		metrics.QueryCoordOnDemandPartitionCount.WithLabelValues(metrics.EvictedLabel).Add(float64(len(partitionIDs)))
+	}
+}
+
+// collectPartitions collects the loaded partitions of the on demand collections and the loaded partition number of them,
+// the access records of the partitions not loaded any more are cleaned.
+func (p *PartitionLoadOnDemand) collectPartitions(ctx context.Context) ([]*onDemandPartition, map[int64]int) {
+	partitions := make([]*onDemandPartition, 0)
+	partitionNum := make(map[int64]int)
+	loaded := make(map[int64]typeutil.UniqueSet)
+	collectionIDs := typeutil.NewUniqueSet(p.s.meta.GetAll(ctx)...)
+	for collectionID := range p.collections {
+		if !collectionIDs.Contain(collectionID) {
+			delete(p.collections, collectionID)
+		}
+	}
+	for collectionID := range collectionIDs {
+		if p.s.meta.GetCollection(ctx, collectionID) == nil {
+			continue
+		}
+		coll, err := p.describeCollection(ctx, collectionID, false)
+		if err != nil {
+			p.Logger().Warn("failed to describe collection", zap.Int64("collectionID", collectionID), zap.Error(err))
+			continue
+		}
+		if !coll.enabled {
+			continue
+		}
+		resourceGroups := make([]string, 0)
+		for _, replica := range p.s.meta.ReplicaManager.GetByCollection(ctx, collectionID) {
+			resourceGroups = append(resourceGroups, replica.GetResourceGroup())
+		}
+
+		p.mu.Lock()
+		accessed := p.lastAccess[collectionID]
+		loaded[collectionID] = typeutil.NewUniqueSet()
+		for _, partition := range p.s.meta.GetPartitionsByCollection(ctx, collectionID) {
+			var rows int64
+			for _, segment := range p.s.targetMgr.GetSealedSegmentsByPartition(ctx, collectionID, partition.GetPartitionID(), meta.CurrentTargetFirst) {
+				rows += segment.GetNumOfRows()
+			}
+			lastAccess, ok := accessed[partition.GetPartitionID()]
+			if !ok || lastAccess.Before(partition.CreatedAt) {
+				lastAccess = partition.CreatedAt
+			}
+			partitions = append(partitions, &onDemandPartition{
+				collectionID:   collectionID,
+				partitionID:    partition.GetPartitionID(),
+				memorySize:     rows * int64(coll.sizePerRecord),
+				lastAccess:     lastAccess,
+				resourceGroups: resourceGroups,
+			})
+			partitionNum[collectionID]++
+			loaded[collectionID].Insert(partition.GetPartitionID())
+		}
+		p.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	for collectionID, accessed := range p.lastAccess {
+		for partitionID := range accessed {
+			if !loaded[collectionID].Contain(partitionID) && !p.loading.Contain(partitionID) {
+				delete(accessed, partitionID)
+			}
+		}
+		if len(accessed) == 0 {
+			delete(p.lastAccess, collectionID)
+		}
+	}
+	p.mu.Unlock()
+	return partitions, partitionNum
+}
+
+// Close closes the partition load on demand manager.
+func (p *PartitionLoadOnDemand) Close() {
+	p.notifier.Cancel()
+	p.notifier.BlockUntilFinish()
+}
+
+// planPartitionEviction returns the partitions to release by collection, the least recently accessed partitions
+// are released until the memory usage of every resource group fits its budget.
+// Partitions accessed within minIdleTime and the last loaded partition of a collection are never released.
+func planPartitionEviction(partitions []*onDemandPartition, partitionNum map[int64]int, budgets map[string]int64,
+	minIdleTime time.Duration, now time.Time,
+) map[int64][]int64 {
+	usage := make(map[string]int64)
+	for _, partition := range partitions {
+		for _, rg := range partition.resourceGroups {
+			usage[rg] += partition.memorySize
+		}
+	}
+	overBudget := func(rgs []string) bool {
+		for _, rg := range rgs {
+			if budget := budgets[rg]; budget > 0 && usage[rg] > budget {
+				return true
+			}
+		}
+		return false
+	}
+
+	sorted := make([]*onDemandPartition, len(partitions))
+	copy(sorted, partitions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].lastAccess.Before(sorted[j].lastAccess)
+	})
+
+	remain := make(map[int64]int, len(partitionNum))
+	for collectionID, num := range partitionNum {
+		remain[collectionID] = num
+	}
+	toRelease := make(map[int64][]int64)
+	for _, partition := range sorted {
+		if now.Sub(partition.lastAccess) < minIdleTime {
+			break
+		}
+		if !overBudget(partition.resourceGroups) || remain[partition.collectionID] <= 1 {
+			continue
+		}
+		toRelease[partition.collectionID] = append(toRelease[partition.collectionID], partition.partitionID)
+		remain[partition.collectionID]--
+		for _, rg := range partition.resourceGroups {
+			usage[rg] -= partition.memorySize
+		}
+	}
+	return toRelease
+}
+
+// getPartitionLoadOnDemandBudgets returns the memory budget in bytes of the resource groups, 0 means unlimited.
+func getPartitionLoadOnDemandBudgets(resourceGroups []string) map[string]int64 {
+	params := paramtable.Get().QueryCoordCfg
+	defaultBudget := params.PartitionLoadOnDemandMemoryBudget.GetAsInt64()
+	overrides := make(map[string]int64)
+	for _, item := range paramtable.ParseAsStings(params.PartitionLoadOnDemandMemoryBudgets.GetValue()) {
+		rg, value, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		budget, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		overrides[strings.TrimSpace(rg)] = budget
+	}
+
+	budgets := make(map[string]int64, len(resourceGroups))
+	for _, rg := range resourceGroups {
+		budget, ok := overrides[rg]
+		if !ok {
+			budget = defaultBudget
+		}
+		budgets[rg] = budget * 1024 * 1024
+	}
+	return budgets
+}
+
+// loadPartitionsOnDemand adds the partitions into the load config of the loaded collection,
+// the replicas and load fields of the collection are kept.
+func (s *Server) loadPartitionsOnDemand(ctx context.Context, coll *onDemandCollection, partitionIDs []int64) error {
+	if !coll.enabled {
+		return merr.WrapErrParameterInvalidMsg("partition load on demand is not enabled for the collection")
+	}
+	collectionID := coll.info.GetCollectionID()
+	notExist := lo.Filter(partitionIDs, func(partitionID int64, _ int) bool { return !coll.partitionIDs.Contain(partitionID) })
+	if len(notExist) > 0 {
+		return merr.WrapErrPartitionNotFound(notExist)
+	}
+
+	broadcaster, err := s.startBroadcastWithCollectionIDLock(ctx, collectionID)
+	if err != nil {
+		return err
+	}
+	defer broadcaster.Close()
+
+	current := s.getCurrentLoadConfig(ctx, collectionID)
+	if current.Collection == nil {
+		return merr.WrapErrCollectionNotLoaded(collectionID, "the collection must be loaded to load partitions on demand")
+	}
+	expectedPartitions := typeutil.NewUniqueSet(current.GetPartitionIDs()...)
+	expectedPartitions.Insert(partitionIDs...)
+	msg, err := job.GenerateAlterLoadConfigMessage(ctx, &job.AlterLoadConfigRequest{
+		Meta:           s.meta,
+		CollectionInfo: coll.info,
+		Current:        current,
+		Expected: job.ExpectedLoadConfig{
+			ExpectedPartitionIDs:             expectedPartitions.Collect(),
+			ExpectedReplicaNumber:            current.GetReplicaNumber(),
+			ExpectedFieldIndexID:             current.GetFieldIndexID(),
+			ExpectedLoadFields:               current.GetLoadFields(),
+			ExpectedPriority:                 current.GetLoadPriority(),
+			ExpectedUserSpecifiedReplicaMode: current.GetUserSpecifiedReplicaMode(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = broadcaster.Broadcast(ctx, msg)
+	return err
+}
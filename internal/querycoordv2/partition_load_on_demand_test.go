@@ -0,0 +1,153 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycoordv2
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/querycoordv2/meta"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestPlanPartitionEviction(t *testing.T) {
+	now := time.Now()
+	newPartition := func(collectionID, partitionID, size int64, idle time.Duration, rgs ...string) *onDemandPartition {
+		return &onDemandPartition{
+			collectionID:   collectionID,
+			partitionID:    partitionID,
+			memorySize:     size,
+			lastAccess:     now.Add(-idle),
+			resourceGroups: rgs,
+		}
+	}
+	partitions := []*onDemandPartition{
+		newPartition(1, 10, 100, time.Hour, "rg1"),
+		newPartition(1, 11, 100, 2*time.Hour, "rg1"),
+		newPartition(1, 12, 100, time.Second, "rg1"),
+		newPartition(2, 20, 100, 3*time.Hour, "rg2"),
+		newPartition(3, 30, 100, 4*time.Hour, "rg1", "rg2"),
+		newPartition(3, 31, 100, 5*time.Hour, "rg1", "rg2"),
+	}
+	partitionNum := map[int64]int{1: 3, 2: 1, 3: 2}
+
+	t.Run("within budget", func(t *testing.T) {
+		toRelease := planPartitionEviction(partitions, partitionNum, map[string]int64{"rg1": 1000, "rg2": 1000}, time.Minute, now)
+		assert.Empty(t, toRelease)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		toRelease := planPartitionEviction(partitions, partitionNum, map[string]int64{"rg1": 0, "rg2": 0}, time.Minute, now)
+		assert.Empty(t, toRelease)
+	})
+
+	t.Run("evict least recently accessed", func(t *testing.T) {
+		// rg1 holds 500, evict the coldest partition 31 to fit 400
+		toRelease := planPartitionEviction(partitions, partitionNum, map[string]int64{"rg1": 400}, time.Minute, now)
+		assert.Equal(t, map[int64][]int64{3: {31}}, toRelease)
+	})
+
+	t.Run("keep last partition of collection", func(t *testing.T) {
+		// partition 30 is the last one of collection 3 after 31 evicted, partition 11 and 10 are the next
+		toRelease := planPartitionEviction(partitions, partitionNum, map[string]int64{"rg1": 200}, time.Minute, now)
+		for _, partitionIDs := range toRelease {
+			sort.Slice(partitionIDs, func(i, j int) bool { return partitionIDs[i] < partitionIDs[j] })
+		}
+		assert.Equal(t, map[int64][]int64{1: {10, 11}, 3: {31}}, toRelease)
+	})
+
+	t.Run("keep recently accessed", func(t *testing.T) {
+		toRelease := planPartitionEviction(partitions, partitionNum, map[string]int64{"rg1": 100}, 90*time.Minute, now)
+		for _, partitionIDs := range toRelease {
+			sort.Slice(partitionIDs, func(i, j int) bool { return partitionIDs[i] < partitionIDs[j] })
+		}
+		assert.Equal(t, map[int64][]int64{1: {11}, 3: {31}}, toRelease)
+	})
+
+	t.Run("only over budget resource group", func(t *testing.T) {
+		// rg2 holds 300, partition 20 is the last of collection 2, so 31 is evicted
+		toRelease := planPartitionEviction(partitions, partitionNum, map[string]int64{"rg2": 250}, time.Minute, now)
+		assert.Equal(t, map[int64][]int64{3: {31}}, toRelease)
+	})
+}
+
+func TestGetPartitionLoadOnDemandBudgets(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+
+	budgets := getPartitionLoadOnDemandBudgets([]string{"rg1"})
+	assert.Equal(t, map[string]int64{"rg1": 0}, budgets)
+
+	params.Save(params.QueryCoordCfg.PartitionLoadOnDemandMemoryBudget.Key, "10")
+	defer params.Reset(params.QueryCoordCfg.PartitionLoadOnDemandMemoryBudget.Key)
+	params.Save(params.QueryCoordCfg.PartitionLoadOnDemandMemoryBudgets.Key, "rg2:20, rg3:invalid,rg4")
+	defer params.Reset(params.QueryCoordCfg.PartitionLoadOnDemandMemoryBudgets.Key)
+
+	budgets = getPartitionLoadOnDemandBudgets([]string{"rg1", "rg2", "rg3"})
+	assert.Equal(t, map[string]int64{
+		"rg1": 10 * 1024 * 1024,
+		"rg2": 20 * 1024 * 1024,
+		"rg3": 10 * 1024 * 1024,
+	}, budgets)
+}
+
+func TestPartitionLoadOnDemandDescribeCollection(t *testing.T) {
+	broker := meta.NewMockBroker(t)
+	broker.EXPECT().DescribeCollection(mock.Anything, int64(1)).Return(&milvuspb.DescribeCollectionResponse{
+		CollectionID: 1,
+		Schema: &schemapb.CollectionSchema{Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+		}},
+		Properties: []*commonpb.KeyValuePair{{Key: common.PartitionLoadOnDemandKey, Value: "true"}},
+	}, nil).Times(2)
+	broker.EXPECT().GetPartitions(mock.Anything, int64(1)).Return([]int64{10, 11}, nil).Times(2)
+	broker.EXPECT().DescribeCollection(mock.Anything, int64(2)).Return(&milvuspb.DescribeCollectionResponse{CollectionID: 2}, nil).Once()
+
+	p := &PartitionLoadOnDemand{
+		s:           &Server{broker: broker},
+		collections: make(map[int64]*onDemandCollection),
+	}
+	ctx := context.Background()
+
+	// described once and cached
+	for i := 0; i < 3; i++ {
+		coll, err := p.describeCollection(ctx, 1, false)
+		assert.NoError(t, err)
+		assert.True(t, coll.enabled)
+		assert.True(t, coll.partitionIDs.Contain(10, 11))
+		assert.Equal(t, 8, coll.sizePerRecord)
+	}
+
+	// described again on refresh
+	_, err := p.describeCollection(ctx, 1, true)
+	assert.NoError(t, err)
+
+	// partitions are not listed if the collection doesn't load partitions on demand
+	coll, err := p.describeCollection(ctx, 2, false)
+	assert.NoError(t, err)
+	assert.False(t, coll.enabled)
+	assert.Nil(t, coll.partitionIDs)
+}
@@ -134,6 +134,8 @@ type Server struct {
 
 	// load config watcher
 	loadConfigWatcher *LoadConfigWatcher
+
+	partitionLoadOnDemand *PartitionLoadOnDemand
 }
 
 type FileResourceObserver interface {
@@ -500,6 +502,7 @@ func (s *Server) startQueryCoord() error {
 	// check replica changes after restart
 	// Note: this should be called after start progress is done
 	s.watchLoadConfigChanges()
+	s.partitionLoadOnDemand = NewPartitionLoadOnDemand(s)
 	return nil
 }
 
@@ -542,6 +545,11 @@ func (s *Server) Stop() error {
 		s.loadConfigWatcher.Close()
 	}
 
+	if s.partitionLoadOnDemand != nil {
+		log.Info("stop partition load on demand...")
+		s.partitionLoadOnDemand.Close()
+	}
+
 	if s.jobScheduler != nil {
 		log.Info("stop job scheduler...")
 		s.jobScheduler.Stop()
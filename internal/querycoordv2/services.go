@@ -38,6 +38,7 @@ import (
 	"github.com/milvus-io/milvus/pkg/v2/metrics"
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/contextutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/metricsinfo"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
@@ -148,6 +149,11 @@ func (s *Server) ShowLoadPartitions(ctx context.Context, req *querypb.ShowPartit
 	percentages := make([]int64, 0)
 	refreshProgress := int64(0)
 
+	// only the access reported by search and query counts, load the partitions if the collection loads partitions on demand
+	if contextutil.PartitionAccessHeader.Get(ctx) != "" {
+		s.partitionLoadOnDemand.Access(ctx, req.GetCollectionID(), partitions...)
+	}
+
 	if len(partitions) == 0 {
 		partitions = lo.Map(s.meta.GetPartitionsByCollection(ctx, req.GetCollectionID()), func(partition *meta.Partition, _ int) int64 {
 			return partition.GetPartitionID()
@@ -41,6 +41,7 @@ import (
 	"github.com/milvus-io/milvus/internal/querynodev2/delegator/deletebuffer"
 	"github.com/milvus-io/milvus/internal/querynodev2/segments"
 	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/explainutil"
 	"github.com/milvus-io/milvus/internal/util/function"
 	"github.com/milvus-io/milvus/internal/util/grpcclient"
 	"github.com/milvus-io/milvus/internal/util/reduce"
@@ -326,24 +327,35 @@ func (sd *shardDelegator) search(ctx context.Context, req *querypb.SearchRequest
 	if req.Req.IgnoreGrowing {
 		growing = []SegmentEntry{}
 	}
+	explainSegments(ctx, sealed, growing)
+
+	// rows of sealed segments before prune, used to estimate the filter selectivity by admission control
+	var totalSealedRows int64
+	for _, item := range sealed {
+		for _, seg := range item.Segments {
+			totalSealedRows += sealedRowCount[seg.SegmentID]
+		}
+	}
 
 	if paramtable.Get().QueryNodeCfg.EnableSegmentPrune.GetAsBool() {
-		func() {
+		explainPrune(ctx, pruneReasonPartitionStats, sealed, func() {
 			sd.partitionStatsMut.RLock()
 			defer sd.partitionStatsMut.RUnlock()
 			PruneSegments(ctx, sd.partitionStats, req.GetReq(), nil, sd.collection.Schema(), sealed,
 				PruneInfo{filterRatio: paramtable.Get().QueryNodeCfg.DefaultSegmentFilterRatio.GetAsFloat()})
-		}()
+		})
 	}
 
 	if paramtable.Get().QueryNodeCfg.EnableSegmentFilter.GetAsBool() {
-		PruneSealedSegmentsByPKFilter(ctx,
-			req.GetReq().GetSerializedExprPlan(),
-			req.GetReq().GetPkFilter(),
-			sealed,
-			req.GetReq().GetCollectionID(),
-			metrics.SearchLabel,
-		)
+		explainPrune(ctx, pruneReasonPKFilter, sealed, func() {
+			PruneSealedSegmentsByPKFilter(ctx,
+				req.GetReq().GetSerializedExprPlan(),
+				req.GetReq().GetPkFilter(),
+				sealed,
+				req.GetReq().GetCollectionID(),
+				metrics.SearchLabel,
+			)
+		})
 	}
 
 	if sd.functionFieldType[req.GetReq().GetFieldId()] == schemapb.FunctionType_BM25 {
@@ -381,6 +393,12 @@ func (sd *shardDelegator) search(ctx context.Context, req *querypb.SearchRequest
 	}
 	effectiveSegmentNum := optimizers.CalculateEffectiveSegmentNum(sd.queryHook, rowCounts, req.GetReq().GetTopk())
 
+	release, err := sd.admitSearch(ctx, req, rowCounts, growing, totalSealedRows)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	log.Debug("search segments...",
 		zap.Int("sealedNum", sealedNum),
 		zap.Int("growingNum", len(growing)),
@@ -400,7 +418,7 @@ func (sd *shardDelegator) search(ctx context.Context, req *querypb.SearchRequest
 	}
 
 	const isSecondStageSearch = false
-	req, err := optimizers.OptimizeSearchParams(ctx, req, sd.queryHook, effectiveSegmentNum, isSecondStageSearch, sd.getVectorFieldDim)
+	req, err = optimizers.OptimizeSearchParams(ctx, req, sd.queryHook, effectiveSegmentNum, isSecondStageSearch, sd.getVectorFieldDim)
 	if err != nil {
 		log.Warn("failed to optimize search params", zap.Error(err))
 		return nil, err
@@ -460,6 +478,7 @@ func (sd *shardDelegator) Search(ctx context.Context, req *querypb.SearchRequest
 	metrics.QueryNodeSQLatencyWaitTSafe.WithLabelValues(
 		paramtable.GetStringNodeID(), metrics.SearchLabel).
 		Observe(float64(waitTr.ElapseSpan().Milliseconds()))
+	explainutil.FromContext(ctx).RecordStage("wait_tsafe", waitTr.ElapseSpan())
 
 	if err != nil {
 		log.Warn("delegator search failed to wait tsafe", zap.Error(err))
@@ -680,6 +699,7 @@ func (sd *shardDelegator) Query(ctx context.Context, req *querypb.QueryRequest)
 	metrics.QueryNodeSQLatencyWaitTSafe.WithLabelValues(
 		paramtable.GetStringNodeID(), contextutil.GetQueryLabel(ctx)).
 		Observe(float64(waitTr.ElapseSpan().Milliseconds()))
+	explainutil.FromContext(ctx).RecordStage("wait_tsafe", waitTr.ElapseSpan())
 
 	if err != nil {
 		log.Warn("delegator query failed to wait tsafe", zap.Error(err))
@@ -701,23 +721,26 @@ func (sd *shardDelegator) Query(ctx context.Context, req *querypb.QueryRequest)
 	if req.Req.IgnoreGrowing {
 		growing = []SegmentEntry{}
 	}
+	explainSegments(ctx, sealed, growing)
 
 	if paramtable.Get().QueryNodeCfg.EnableSegmentPrune.GetAsBool() {
-		func() {
+		explainPrune(ctx, pruneReasonPartitionStats, sealed, func() {
 			sd.partitionStatsMut.RLock()
 			defer sd.partitionStatsMut.RUnlock()
 			PruneSegments(ctx, sd.partitionStats, nil, req.GetReq(), sd.collection.Schema(), sealed, PruneInfo{paramtable.Get().QueryNodeCfg.DefaultSegmentFilterRatio.GetAsFloat()})
-		}()
+		})
 	}
 
 	if paramtable.Get().QueryNodeCfg.EnableSegmentFilter.GetAsBool() {
-		PruneSealedSegmentsByPKFilter(ctx,
-			req.GetReq().GetSerializedExprPlan(),
-			req.GetReq().GetPkFilter(),
-			sealed,
-			req.GetReq().GetCollectionID(),
-			metrics.QueryLabel,
-		)
+		explainPrune(ctx, pruneReasonPKFilter, sealed, func() {
+			PruneSealedSegmentsByPKFilter(ctx,
+				req.GetReq().GetSerializedExprPlan(),
+				req.GetReq().GetPkFilter(),
+				sealed,
+				req.GetReq().GetCollectionID(),
+				metrics.QueryLabel,
+			)
+		})
 	}
 
 	sealedNum := lo.SumBy(sealed, func(item SnapshotItem) int { return len(item.Segments) })
@@ -930,7 +953,9 @@ func executeSubTasks[T any, R interface {
 				}
 				err = fmt.Errorf("segments not loaded in any worker: %v", segments[:min(len(segments), 10)])
 			} else {
+				start := time.Now()
 				result, err = execute(ctx, task.req, task.worker)
+				explainFanout(ctx, task.targetID, task.req, result, time.Since(start))
 				if result.GetStatus().GetErrorCode() != commonpb.ErrorCode_Success {
 					err = fmt.Errorf("worker(%d) query failed: %s", task.targetID, result.GetStatus().GetReason())
 				}
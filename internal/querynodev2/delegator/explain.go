@@ -0,0 +1,81 @@
+package delegator
+
+import (
+	"context"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus/internal/util/explainutil"
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const (
+	pruneReasonPartitionStats = "partition_stats"
+	pruneReasonPKFilter       = "pk_filter"
+)
+
+// explainSegments records the number of segments readable by the explained request.
+func explainSegments(ctx context.Context, sealed []SnapshotItem, growing []SegmentEntry) {
+	collector := explainutil.FromContext(ctx)
+	if collector == nil {
+		return
+	}
+	collector.SetSegments(lo.SumBy(sealed, func(item SnapshotItem) int { return len(item.Segments) }), len(growing))
+}
+
+// explainPrune runs the prune function, and records the sealed segments pruned by it
+// and the time spent if the request is explained.
+func explainPrune(ctx context.Context, reason string, sealed []SnapshotItem, prune func()) {
+	collector := explainutil.FromContext(ctx)
+	if collector == nil {
+		prune()
+		return
+	}
+	before := sealedSegmentIDs(sealed)
+	start := time.Now()
+	prune()
+	collector.RecordStage("prune_"+reason, time.Since(start))
+
+	after := typeutil.NewSet(sealedSegmentIDs(sealed)...)
+	collector.AddPrunedSegments(reason, lo.Filter(before, func(segmentID int64, _ int) bool {
+		return !after.Contain(segmentID)
+	})...)
+}
+
+// explainFanout records the sub request sent to the worker if the request is explained.
+func explainFanout(ctx context.Context, nodeID int64, req any, result any, elapsed time.Duration) {
+	collector := explainutil.FromContext(ctx)
+	if collector == nil {
+		return
+	}
+	fanout := &explainutil.WorkerFanout{
+		NodeID:    nodeID,
+		ElapsedMs: float64(elapsed.Microseconds()) / 1000.0,
+	}
+	if r, ok := req.(interface{ GetSegmentIDs() []int64 }); ok {
+		fanout.Segments = r.GetSegmentIDs()
+	}
+	if r, ok := req.(interface{ GetScope() querypb.DataScope }); ok {
+		fanout.Scope = r.GetScope().String()
+	}
+	if r, ok := result.(interface {
+		GetCostAggregation() *internalpb.CostAggregation
+	}); ok {
+		fanout.ResponseMs = r.GetCostAggregation().GetResponseTime()
+		fanout.ServiceTimeMs = r.GetCostAggregation().GetServiceTime()
+	}
+	collector.AddFanout(fanout)
+}
+
+func sealedSegmentIDs(sealed []SnapshotItem) []int64 {
+	segmentIDs := make([]int64, 0)
+	for _, item := range sealed {
+		for _, segment := range item.Segments {
+			segmentIDs = append(segmentIDs, segment.SegmentID)
+		}
+	}
+	return segmentIDs
+}
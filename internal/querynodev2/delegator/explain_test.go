@@ -0,0 +1,56 @@
+package delegator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/util/explainutil"
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+)
+
+func TestExplainDelegator(t *testing.T) {
+	newSealed := func() []SnapshotItem {
+		return []SnapshotItem{
+			{NodeID: 1, Segments: []SegmentEntry{{SegmentID: 1}, {SegmentID: 2}}},
+			{NodeID: 2, Segments: []SegmentEntry{{SegmentID: 3}}},
+		}
+	}
+	pruneSegment2 := func(sealed []SnapshotItem) func() {
+		return func() {
+			sealed[0].Segments = sealed[0].Segments[:1]
+		}
+	}
+
+	// the prune function is still executed if the request isn't explained.
+	sealed := newSealed()
+	explainPrune(context.Background(), pruneReasonPKFilter, sealed, pruneSegment2(sealed))
+	assert.Equal(t, []int64{1, 3}, sealedSegmentIDs(sealed))
+	explainSegments(context.Background(), sealed, nil)
+	explainFanout(context.Background(), 1, nil, nil, time.Second)
+
+	collector := explainutil.NewShardCollector(explainutil.ModeProfile, "ch", 1)
+	ctx := explainutil.WithCollector(context.Background(), collector)
+	sealed = newSealed()
+	explainSegments(ctx, sealed, []SegmentEntry{{SegmentID: 4}})
+	explainPrune(ctx, pruneReasonPartitionStats, sealed, pruneSegment2(sealed))
+	explainPrune(ctx, pruneReasonPKFilter, sealed, func() {})
+	explainFanout(ctx, 2, &querypb.SearchRequest{SegmentIDs: []int64{3}, Scope: querypb.DataScope_Historical},
+		&internalpb.SearchResults{CostAggregation: &internalpb.CostAggregation{ResponseTime: 3, ServiceTime: 2}}, time.Millisecond)
+
+	shard := collector.Explanation().Shards[0]
+	assert.Equal(t, 3, shard.SealedSegments)
+	assert.Equal(t, 1, shard.GrowingSegments)
+	assert.Equal(t, []*explainutil.PrunedSegment{{SegmentID: 2, Reason: pruneReasonPartitionStats}}, shard.PrunedSegments)
+	assert.Len(t, shard.Stages, 2)
+	assert.Len(t, shard.Fanout, 1)
+	assert.Equal(t, int64(2), shard.Fanout[0].NodeID)
+	assert.Equal(t, []int64{3}, shard.Fanout[0].Segments)
+	assert.Equal(t, querypb.DataScope_Historical.String(), shard.Fanout[0].Scope)
+	assert.Equal(t, int64(3), shard.Fanout[0].ResponseMs)
+	assert.Equal(t, int64(2), shard.Fanout[0].ServiceTimeMs)
+	assert.Equal(t, 1.0, shard.Fanout[0].ElapsedMs)
+}
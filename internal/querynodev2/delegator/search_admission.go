@@ -0,0 +1,181 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/syncutil"
+)
+
+const searchAdmissionPolicyQueue = "queue"
+
+// overBudgetSearches limits the searches over the cost budget executing on the node when the policy is queue,
+// it's shared by all delegators on the node.
+var overBudgetSearches = newSearchAdmission()
+
+// searchCost is the estimated cost of a search on a shard.
+type searchCost struct {
+	cost float64
+	// rows to search after pruning and the rows before.
+	rows      int64
+	totalRows int64
+	segments  int
+}
+
+// selectivity returns the ratio of rows left after pruning by the filter.
+func (c searchCost) selectivity() float64 {
+	if c.totalRows == 0 {
+		return 1
+	}
+	return float64(c.rows) / float64(c.totalRows)
+}
+
+// indexCostFactor returns the approximate ratio of rows visited by a search on the index type.
+func indexCostFactor(indexType string) float64 {
+	indexType = strings.ToUpper(indexType)
+	switch {
+	case indexType == "", strings.HasSuffix(indexType, "FLAT") && !strings.Contains(indexType, "IVF"),
+		strings.Contains(indexType, "BRUTE_FORCE"):
+		return 1
+	case strings.Contains(indexType, "HNSW"), strings.Contains(indexType, "DISKANN"),
+		strings.Contains(indexType, "CAGRA"), indexType == "AUTOINDEX":
+		return 0.01
+	default:
+		// IVF family, SCANN, sparse inverted indexes and others
+		return 0.05
+	}
+}
+
+// estimateSearchCost estimates the distances computed by a search,
+// every segment is searched for nq vectors and at least topk rows are visited per segment.
+// Growing segments are always searched by brute force.
+func estimateSearchCost(nq, topk int64, indexType string, sealedRows, growingRows []int64, totalRows int64) searchCost {
+	factor := indexCostFactor(indexType)
+	c := searchCost{
+		totalRows: totalRows,
+		segments:  len(sealedRows) + len(growingRows),
+	}
+	var perQuery float64
+	for _, rows := range sealedRows {
+		c.rows += rows
+		perQuery += max(float64(rows)*factor, float64(topk))
+	}
+	for _, rows := range growingRows {
+		c.rows += rows
+		perQuery += max(float64(rows), float64(topk))
+	}
+	c.cost = float64(nq) * perQuery
+	return c
+}
+
+// admitSearch estimates the cost of the search on the segments left after pruning
+// and applies the admission policy if the cost exceeds the budget.
+// The returned release func must be called once the search is done.
+func (sd *shardDelegator) admitSearch(ctx context.Context, req *querypb.SearchRequest,
+	sealedRows []int64, growing []SegmentEntry, totalSealedRows int64,
+) (func(), error) {
+	pt := paramtable.Get()
+	budget := pt.QueryNodeCfg.SearchAdmissionCostBudget.GetAsFloat()
+	if budget <= 0 {
+		return func() {}, nil
+	}
+
+	growingRows := make([]int64, 0, len(growing))
+	totalRows := totalSealedRows
+	for _, seg := range growing {
+		if segment := sd.segmentManager.GetGrowing(seg.SegmentID); segment != nil {
+			growingRows = append(growingRows, segment.RowNum())
+			totalRows += segment.RowNum()
+		}
+	}
+	indexType := sd.collection.GetIndexType(req.GetReq().GetFieldId())
+	cost := estimateSearchCost(req.GetReq().GetNq(), req.GetReq().GetTopk(), indexType, sealedRows, growingRows, totalRows)
+
+	nodeID := paramtable.GetStringNodeID()
+	metrics.QueryNodeSearchEstimatedCost.WithLabelValues(nodeID).Observe(cost.cost)
+	if cost.cost <= budget {
+		return func() {}, nil
+	}
+
+	log := sd.getLogger(ctx).With(
+		zap.Float64("cost", cost.cost),
+		zap.Float64("budget", budget),
+		zap.Int64("nq", req.GetReq().GetNq()),
+		zap.Int64("topk", req.GetReq().GetTopk()),
+		zap.String("indexType", indexType),
+		zap.Int("segments", cost.segments),
+		zap.Float64("selectivity", cost.selectivity()),
+	)
+	reason := fmt.Sprintf("estimated search cost %.0f exceeds budget %.0f (nq=%d, topk=%d, segments=%d, rows=%d, selectivity=%.2f)",
+		cost.cost, budget, req.GetReq().GetNq(), req.GetReq().GetTopk(), cost.segments, cost.rows, cost.selectivity())
+
+	if pt.QueryNodeCfg.SearchAdmissionPolicy.GetValue() != searchAdmissionPolicyQueue {
+		metrics.QueryNodeSearchOverBudgetCount.WithLabelValues(nodeID, metrics.RejectedLabel).Inc()
+		log.Warn("reject search over cost budget")
+		return nil, merr.WrapErrServiceQuotaExceeded(reason, "search rejected by admission control")
+	}
+
+	metrics.QueryNodeSearchOverBudgetCount.WithLabelValues(nodeID, metrics.QueuedLabel).Inc()
+	log.Info("queue search over cost budget")
+	if err := overBudgetSearches.acquire(ctx, pt.QueryNodeCfg.SearchAdmissionMaxQueuedExecuting.GetAsInt()); err != nil {
+		log.Warn("search over cost budget failed to wait for execution", zap.Error(err))
+		return nil, merr.WrapErrServiceQuotaExceeded(reason, fmt.Sprintf("search queued by admission control timeout: %s", err.Error()))
+	}
+	return overBudgetSearches.release, nil
+}
+
+func newSearchAdmission() *searchAdmission {
+	return &searchAdmission{
+		cond: syncutil.NewContextCond(&sync.Mutex{}),
+	}
+}
+
+// searchAdmission limits the number of executing searches.
+type searchAdmission struct {
+	cond      *syncutil.ContextCond
+	executing int
+}
+
+// acquire waits until less than limit searches are executing or ctx is done.
+func (a *searchAdmission) acquire(ctx context.Context, limit int) error {
+	limit = max(limit, 1)
+	a.cond.L.Lock()
+	for a.executing >= limit {
+		if err := a.cond.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	a.executing++
+	a.cond.L.Unlock()
+	return nil
+}
+
+func (a *searchAdmission) release() {
+	a.cond.LockAndBroadcast()
+	a.executing--
+	a.cond.L.Unlock()
+}
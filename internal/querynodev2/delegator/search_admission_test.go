@@ -0,0 +1,132 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestIndexCostFactor(t *testing.T) {
+	assert.Equal(t, 1.0, indexCostFactor(""))
+	assert.Equal(t, 1.0, indexCostFactor("FLAT"))
+	assert.Equal(t, 1.0, indexCostFactor("BIN_FLAT"))
+	assert.Equal(t, 1.0, indexCostFactor("GPU_BRUTE_FORCE"))
+	assert.Equal(t, 0.05, indexCostFactor("IVF_FLAT"))
+	assert.Equal(t, 0.05, indexCostFactor("IVF_SQ8"))
+	assert.Equal(t, 0.05, indexCostFactor("SCANN"))
+	assert.Equal(t, 0.01, indexCostFactor("HNSW"))
+	assert.Equal(t, 0.01, indexCostFactor("hnsw_sq"))
+	assert.Equal(t, 0.01, indexCostFactor("DISKANN"))
+	assert.Equal(t, 0.01, indexCostFactor("GPU_CAGRA"))
+	assert.Equal(t, 0.01, indexCostFactor("AUTOINDEX"))
+	assert.Equal(t, 0.05, indexCostFactor("SPARSE_INVERTED_INDEX"))
+}
+
+func TestEstimateSearchCost(t *testing.T) {
+	// brute force, small segments are bounded by topk
+	cost := estimateSearchCost(2, 100, "", []int64{1000, 10}, []int64{50}, 2000)
+	assert.Equal(t, float64(2*(1000+100+100)), cost.cost)
+	assert.Equal(t, int64(1060), cost.rows)
+	assert.Equal(t, 3, cost.segments)
+	assert.InDelta(t, 0.53, cost.selectivity(), 1e-9)
+
+	// growing segments are searched by brute force regardless of the index
+	cost = estimateSearchCost(1, 10, "HNSW", []int64{100000}, []int64{2000}, 102000)
+	assert.Equal(t, float64(1000+2000), cost.cost)
+	assert.Equal(t, 1.0, cost.selectivity())
+
+	cost = estimateSearchCost(1, 10, "HNSW", nil, nil, 0)
+	assert.Equal(t, 0.0, cost.cost)
+	assert.Equal(t, 1.0, cost.selectivity())
+}
+
+func TestAdmitSearch(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	sd := &shardDelegator{
+		collection: &segments.Collection{},
+	}
+	req := &querypb.SearchRequest{
+		Req: &internalpb.SearchRequest{Nq: 10, Topk: 10},
+	}
+	ctx := context.Background()
+
+	t.Run("disabled", func(t *testing.T) {
+		release, err := sd.admitSearch(ctx, req, []int64{1000000}, nil, 1000000)
+		require.NoError(t, err)
+		release()
+	})
+
+	params.Save(params.QueryNodeCfg.SearchAdmissionCostBudget.Key, "100000")
+	defer params.Reset(params.QueryNodeCfg.SearchAdmissionCostBudget.Key)
+
+	t.Run("within budget", func(t *testing.T) {
+		release, err := sd.admitSearch(ctx, req, []int64{1000}, nil, 1000)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		_, err := sd.admitSearch(ctx, req, []int64{1000000}, nil, 2000000)
+		assert.ErrorIs(t, err, merr.ErrServiceQuotaExceeded)
+		assert.Contains(t, err.Error(), "exceeds budget")
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		params.Save(params.QueryNodeCfg.SearchAdmissionPolicy.Key, "queue")
+		defer params.Reset(params.QueryNodeCfg.SearchAdmissionPolicy.Key)
+
+		release, err := sd.admitSearch(ctx, req, []int64{1000000}, nil, 1000000)
+		require.NoError(t, err)
+
+		// the second over budget search waits until the first one is done
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err = sd.admitSearch(timeoutCtx, req, []int64{1000000}, nil, 1000000)
+		assert.ErrorIs(t, err, merr.ErrServiceQuotaExceeded)
+
+		admitted := make(chan func())
+		go func() {
+			release, err := sd.admitSearch(ctx, req, []int64{1000000}, nil, 1000000)
+			assert.NoError(t, err)
+			admitted <- release
+		}()
+		select {
+		case <-admitted:
+			t.Fatal("search admitted over the limit")
+		case <-time.After(20 * time.Millisecond):
+		}
+		release()
+		(<-admitted)()
+
+		// searches within budget are never queued
+		release, err = sd.admitSearch(ctx, req, []int64{1000}, nil, 1000)
+		require.NoError(t, err)
+		release()
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/samber/lo"
 	"go.opentelemetry.io/otel/trace"
@@ -29,6 +30,8 @@ import (
 	"github.com/milvus-io/milvus/internal/querynodev2/delegator"
 	"github.com/milvus-io/milvus/internal/querynodev2/segments"
 	"github.com/milvus-io/milvus/internal/querynodev2/tasks"
+	"github.com/milvus-io/milvus/internal/util/costutil"
+	"github.com/milvus-io/milvus/internal/util/explainutil"
 	"github.com/milvus-io/milvus/internal/util/reduce"
 	"github.com/milvus-io/milvus/internal/util/segmentutil"
 	"github.com/milvus-io/milvus/internal/util/streamrpc"
@@ -289,10 +292,12 @@ func (node *QueryNode) queryChannel(ctx context.Context, req *querypb.QueryReque
 		node.manager.Collection.Unref(req.GetReq().GetCollectionID(), 1)
 	}()
 
+	reduceStart := time.Now()
 	resp, err := segments.RunDelegatorQueryPipeline(ctx, req, collection.Schema(), results)
 	if err != nil {
 		return nil, err
 	}
+	explainutil.FromContext(ctx).RecordStage("reduce", time.Since(reduceStart))
 	// aggregate cost
 	requestCosts := lo.FilterMap(results, func(result *internalpb.RetrieveResults, _ int) (*internalpb.CostAggregation, bool) {
 		if paramtable.Get().QueryNodeCfg.EnableWorkerSQCostMetrics.GetAsBool() {
@@ -317,6 +322,9 @@ func (node *QueryNode) queryChannel(ctx context.Context, req *querypb.QueryReque
 		return cost.GetTotalRelatedDataSize()
 	})
 	resp.CostAggregation.TotalRelatedDataSize = relatedDataSize
+	costutil.MergeAggregations(resp.CostAggregation, lo.Map(results, func(result *internalpb.RetrieveResults, _ int) *internalpb.CostAggregation {
+		return result.GetCostAggregation()
+	}))
 	tr.CtxElapse(ctx, fmt.Sprintf("do query with channel done , vChannel = %s, segmentIDs = %v",
 		channel,
 		req.GetSegmentIDs(),
@@ -481,6 +489,7 @@ func (node *QueryNode) searchChannel(ctx context.Context, req *querypb.SearchReq
 	metrics.QueryNodeReduceLatency.
 		WithLabelValues(nodeIDStr, metrics.SearchLabel, metrics.ReduceShards, metrics.BatchReduce).
 		Observe(float64(reduceLatency.Microseconds()) / 1000.0)
+	explainutil.FromContext(ctx).RecordStage("reduce", reduceLatency)
 
 	if err != nil {
 		return nil, err
@@ -109,6 +109,7 @@ func (m *collectionManager) PutOrRef(collectionID int64, schema *schemapb.Collec
 			if err := collection.ccollection.UpdateIndexMeta(meta); err != nil {
 				return err
 			}
+			collection.setIndexTypes(meta)
 		}
 		collection.Ref(1)
 		return nil
@@ -199,6 +200,7 @@ type Collection struct {
 	metricType    atomic.String // deprecated
 	schema        atomic.Pointer[schemapb.CollectionSchema]
 	isGpuIndex    bool
+	indexTypes    atomic.Pointer[map[int64]string]
 	loadFields    typeutil.Set[int64]
 	schemaVersion uint64
 
@@ -239,6 +241,27 @@ func (c *Collection) IsGpuIndex() bool {
 	return c.isGpuIndex
 }
 
+// GetIndexType returns the index type of the field, "" if the field has no index.
+func (c *Collection) GetIndexType(fieldID int64) string {
+	indexTypes := c.indexTypes.Load()
+	if indexTypes == nil {
+		return ""
+	}
+	return (*indexTypes)[fieldID]
+}
+
+func (c *Collection) setIndexTypes(meta *segcorepb.CollectionIndexMeta) {
+	indexTypes := make(map[int64]string, len(meta.GetIndexMetas()))
+	for _, indexMeta := range meta.GetIndexMetas() {
+		for _, param := range indexMeta.GetIndexParams() {
+			if param.GetKey() == common.IndexTypeKey {
+				indexTypes[indexMeta.GetFieldID()] = param.GetValue()
+			}
+		}
+	}
+	c.indexTypes.Store(&indexTypes)
+}
+
 // getPartitionIDs return partitionIDs of collection
 func (c *Collection) GetPartitions() []int64 {
 	return c.partitions.Collect()
@@ -339,6 +362,7 @@ func NewCollection(collectionID int64, schema *schemapb.CollectionSchema, indexM
 		coll.partitions.Insert(partitionID)
 	}
 	coll.schema.Store(schema)
+	coll.setIndexTypes(indexMeta)
 
 	return coll, nil
 }
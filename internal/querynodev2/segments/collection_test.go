@@ -134,6 +134,22 @@ func (s *CollectionManagerSuite) TestPutOrRefUpdateIndexMeta() {
 		newVecFieldID)
 }
 
+func (s *CollectionManagerSuite) TestGetIndexType() {
+	coll := s.cm.Get(1)
+	s.Require().NotNil(coll)
+
+	indexMeta := mock_segcore.GenTestIndexMeta(1, coll.Schema())
+	s.Require().NotEmpty(indexMeta.GetIndexMetas())
+	for _, meta := range indexMeta.GetIndexMetas() {
+		for _, param := range meta.GetIndexParams() {
+			if param.GetKey() == common.IndexTypeKey {
+				s.Equal(param.GetValue(), coll.GetIndexType(meta.GetFieldID()))
+			}
+		}
+	}
+	s.Equal("", coll.GetIndexType(-1))
+}
+
 func (s *CollectionManagerSuite) TestRef() {
 	s.Run("ref_existing_collection", func() {
 		ok := s.cm.Ref(1, 1)
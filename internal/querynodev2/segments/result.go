@@ -26,6 +26,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/util/costutil"
 	"github.com/milvus-io/milvus/internal/util/reduce"
 	"github.com/milvus-io/milvus/internal/util/segcore"
 	"github.com/milvus-io/milvus/pkg/v2/log"
@@ -118,6 +119,7 @@ func ReduceSearchResults(ctx context.Context, results []*internalpb.SearchResult
 	if searchResults.CostAggregation == nil {
 		searchResults.CostAggregation = &internalpb.CostAggregation{}
 	}
+	costutil.MergeAggregations(searchResults.CostAggregation, requestCosts)
 	relatedDataSize := lo.Reduce(results, func(acc int64, result *internalpb.SearchResults, _ int) int64 {
 		return acc + result.GetCostAggregation().GetTotalRelatedDataSize()
 	}, 0)
@@ -182,6 +184,7 @@ func ReduceAdvancedSearchResults(ctx context.Context, results []*internalpb.Sear
 	if searchResults.CostAggregation == nil {
 		searchResults.CostAggregation = &internalpb.CostAggregation{}
 	}
+	costutil.MergeAggregations(searchResults.CostAggregation, requestCosts)
 	searchResults.CostAggregation.TotalRelatedDataSize = relatedDataSize
 	searchResults.IsTopkReduce = isTopkReduce
 	searchResults.ScannedRemoteBytes = storageCost.ScannedRemoteBytes
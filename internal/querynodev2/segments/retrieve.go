@@ -23,6 +23,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/util/costutil"
 	"github.com/milvus-io/milvus/internal/util/streamrpc"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/metrics"
@@ -53,12 +54,14 @@ func retrieveOnSegments(ctx context.Context, mgr *Manager, segments []Segment, s
 		label = metrics.GrowingSegmentLabel
 	}
 
+	counter := costutil.FromContext(ctx)
 	retriever := func(ctx context.Context, s Segment) error {
 		tr := timerecord.NewTimeRecorder("retrieveOnSegments")
 		result, err := s.Retrieve(ctx, plan)
 		if err != nil {
 			return err
 		}
+		counter.AddSegment(s.RowNum(), 0, tr.ElapseSpan())
 
 		log := log.Ctx(ctx)
 		if log.Core().Enabled(zap.DebugLevel) && req.GetReq().GetIsCount() {
@@ -147,6 +150,9 @@ func retrieveOnSegmentsWithStream(ctx context.Context, mgr *Manager, segments []
 					FieldsData: result.GetFieldsData(),
 					CostAggregation: &internalpb.CostAggregation{
 						TotalRelatedDataSize: GetSegmentRelatedDataSize(segment),
+						SegmentRows:          segment.RowNum(),
+						SegmentsTouched:      1,
+						SegmentTimeUs:        tr.ElapseSpan().Microseconds(),
 					},
 					SealedSegmentIDsRetrieved: []int64{segment.ID()},
 					AllRetrieveCount:          result.GetAllRetrieveCount(),
@@ -24,6 +24,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/querynodev2/segments/metricsutil"
+	"github.com/milvus-io/milvus/internal/util/costutil"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/metrics"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
@@ -40,6 +41,16 @@ func searchSegments(ctx context.Context, mgr *Manager, segments []Segment, segTy
 
 	nodeIDStr := paramtable.GetStringNodeID()
 	searchResults := make([]*SearchResult, len(segments))
+	segmentsWithoutIndex := make([]int64, 0, len(segments))
+	bruteForce := make([]bool, len(segments))
+	for i, seg := range segments {
+		if !seg.ExistIndex(searchReq.SearchFieldID()) {
+			segmentsWithoutIndex = append(segmentsWithoutIndex, seg.ID())
+			bruteForce[i] = true
+		}
+	}
+
+	counter := costutil.FromContext(ctx)
 	searcher := func(ctx context.Context, s Segment, idx int) error {
 		tr := timerecord.NewTimeRecorder("searchOnSegments")
 		searchResult, err := s.Search(ctx, searchReq)
@@ -47,7 +58,14 @@ func searchSegments(ctx context.Context, mgr *Manager, segments []Segment, segTy
 			return err
 		}
 		searchResults[idx] = searchResult
-		elapsed := float64(tr.ElapseSpan().Microseconds()) / 1000.0
+		span := tr.ElapseSpan()
+		rows := s.RowNum()
+		distanceComputations := int64(0)
+		if bruteForce[idx] && !searchReq.FilterOnly() {
+			distanceComputations = searchReq.GetNumOfQuery() * rows
+		}
+		counter.AddSegment(rows, distanceComputations, span)
+		elapsed := float64(span.Microseconds()) / 1000.0
 		metrics.QueryNodeSQSegmentLatency.WithLabelValues(nodeIDStr,
 			metrics.SearchLabel, searchLabel).Observe(elapsed)
 		metrics.QueryNodeSegmentSearchLatencyPerVector.WithLabelValues(nodeIDStr,
@@ -69,13 +87,6 @@ func searchSegments(ctx context.Context, mgr *Manager, segments []Segment, segTy
 		return searcher(ctx, seg, idx)
 	}
 
-	segmentsWithoutIndex := make([]int64, 0, len(segments))
-	for _, seg := range segments {
-		if !seg.ExistIndex(searchReq.SearchFieldID()) {
-			segmentsWithoutIndex = append(segmentsWithoutIndex, seg.ID())
-		}
-	}
-
 	var err error
 	if len(segments) == 1 {
 		// Single segment fast path: skip errgroup/goroutine overhead
@@ -39,6 +39,7 @@ import (
 	"github.com/milvus-io/milvus/internal/storage"
 	"github.com/milvus-io/milvus/internal/storagev2"
 	"github.com/milvus-io/milvus/internal/util/analyzer"
+	"github.com/milvus-io/milvus/internal/util/explainutil"
 	"github.com/milvus-io/milvus/internal/util/fileresource"
 	"github.com/milvus-io/milvus/internal/util/streamrpc"
 	"github.com/milvus-io/milvus/internal/util/textmatch"
@@ -848,6 +849,8 @@ func (node *QueryNode) Search(ctx context.Context, req *querypb.SearchRequest) (
 	}
 
 	ch := req.GetDmlChannels()[0]
+	collector := explainutil.NewShardCollector(explainutil.Mode(contextutil.ExplainModeHeader.Get(ctx)), ch, node.GetNodeID())
+	ctx = explainutil.WithCollector(ctx, collector)
 	channelReq := &querypb.SearchRequest{
 		Req:             req.Req,
 		DmlChannels:     []string{ch},
@@ -869,6 +872,9 @@ func (node *QueryNode) Search(ctx context.Context, req *querypb.SearchRequest) (
 	if ret.GetCostAggregation() != nil {
 		ret.GetCostAggregation().ResponseTime = tr.ElapseSpan().Milliseconds()
 	}
+	if err := collector.Attach(ret.GetStatus()); err != nil {
+		log.Warn("failed to attach the explanation of search", zap.Error(err))
+	}
 	return ret, nil
 }
 
@@ -994,12 +1000,17 @@ func (node *QueryNode) Query(ctx context.Context, req *querypb.QueryRequest) (*i
 		req.GetDmlChannels()[0],
 		req.GetSegmentIDs(),
 	))
+	collector := explainutil.NewShardCollector(explainutil.Mode(contextutil.ExplainModeHeader.Get(ctx)), req.GetDmlChannels()[0], node.GetNodeID())
+	ctx = explainutil.WithCollector(ctx, collector)
 	res, err := node.queryChannel(ctx, req, req.GetDmlChannels()[0])
 	if err != nil {
 		return &internalpb.RetrieveResults{
 			Status: merr.Status(err),
 		}, nil
 	}
+	if err := collector.Attach(res.GetStatus()); err != nil {
+		log.Warn("failed to attach the explanation of query", zap.Error(err))
+	}
 	return res, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/milvus-io/milvus/internal/util/streamrpc"
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/contextutil"
 )
 
 var _ scheduler.Task = &QueryStreamTask{}
@@ -50,6 +51,12 @@ func (t *QueryStreamTask) Username() string {
 	return t.req.Req.GetUsername()
 }
 
+// Return the priority class carried in the request.
+// Return "" if the request do not specify any class.
+func (t *QueryStreamTask) PriorityClass() string {
+	return contextutil.PriorityClassHeader.Get(t.ctx)
+}
+
 func (t *QueryStreamTask) IsGpuIndex() bool {
 	return false
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/internal/util/costutil"
 	"github.com/milvus-io/milvus/internal/util/searchutil/scheduler"
 	"github.com/milvus-io/milvus/internal/util/segcore"
 	"github.com/milvus-io/milvus/pkg/v2/metrics"
@@ -64,6 +65,12 @@ func (t *QueryTask) Username() string {
 	return t.req.Req.GetUsername()
 }
 
+// Return the priority class carried in the request.
+// Return "" if the request do not specify any class.
+func (t *QueryTask) PriorityClass() string {
+	return contextutil.PriorityClassHeader.Get(t.ctx)
+}
+
 func (t *QueryTask) IsGpuIndex() bool {
 	return false
 }
@@ -125,7 +132,8 @@ func (t *QueryTask) Execute() error {
 	}
 	defer retrievePlan.Delete()
 
-	results, pinnedSegments, err := segments.Retrieve(t.ctx, t.segmentManager, retrievePlan, t.req)
+	counter := costutil.NewCounter()
+	results, pinnedSegments, err := segments.Retrieve(costutil.WithCounter(t.ctx, counter), t.segmentManager, retrievePlan, t.req)
 	defer t.segmentManager.Segment.Unpin(pinnedSegments)
 	if err != nil {
 		return err
@@ -175,6 +183,7 @@ func (t *QueryTask) Execute() error {
 		ElementLevel:       reducedResult.GetElementLevel(),
 		ElementIndices:     convertSegcoreElementIndicesToInternal(reducedResult.GetElementIndices()),
 	}
+	counter.Fill(t.result.CostAggregation, 1, 1)
 	return nil
 }
 
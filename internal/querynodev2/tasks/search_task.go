@@ -16,12 +16,14 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus/internal/querynodev2/segments"
+	"github.com/milvus-io/milvus/internal/util/costutil"
 	"github.com/milvus-io/milvus/internal/util/searchutil/scheduler"
 	"github.com/milvus-io/milvus/internal/util/segcore"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/metrics"
 	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/querypb"
+	"github.com/milvus-io/milvus/pkg/v2/util/contextutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
@@ -88,6 +90,12 @@ func (t *SearchTask) Username() string {
 	return t.req.Req.GetUsername()
 }
 
+// Return the priority class carried in the request.
+// Return "" if the request do not specify any class.
+func (t *SearchTask) PriorityClass() string {
+	return contextutil.PriorityClassHeader.Get(t.ctx)
+}
+
 func (t *SearchTask) GetNodeID() int64 {
 	return t.serverID
 }
@@ -153,13 +161,15 @@ func (t *SearchTask) Execute() error {
 	}
 	defer searchReq.Delete()
 
+	counter := costutil.NewCounter()
+	ctx := costutil.WithCounter(t.ctx, counter)
 	var (
 		results          []*segments.SearchResult
 		searchedSegments []segments.Segment
 	)
 	if req.GetScope() == querypb.DataScope_Historical {
 		results, searchedSegments, err = segments.SearchHistorical(
-			t.ctx,
+			ctx,
 			t.segmentManager,
 			searchReq,
 			req.GetReq().GetCollectionID(),
@@ -168,7 +178,7 @@ func (t *SearchTask) Execute() error {
 		)
 	} else if req.GetScope() == querypb.DataScope_Streaming {
 		results, searchedSegments, err = segments.SearchStreaming(
-			t.ctx,
+			ctx,
 			t.segmentManager,
 			searchReq,
 			req.GetReq().GetCollectionID(),
@@ -213,6 +223,7 @@ func (t *SearchTask) Execute() error {
 					TotalRelatedDataSize: relatedDataSize,
 				},
 			}
+			counter.Fill(task.result.CostAggregation, t.originNqs[i], t.nq)
 		}
 		log.Debug("filter-only search completed", zap.Int("segments", len(segmentIDs)))
 		return nil
@@ -323,6 +334,7 @@ func (t *SearchTask) Execute() error {
 			ScannedRemoteBytes: cost.ScannedRemoteBytes,
 			ScannedTotalBytes:  cost.ScannedTotalBytes,
 		}
+		counter.Fill(allTasks[i].result.CostAggregation, t.originNqs[i], t.nq)
 	}
 
 	// Phase 2: on error, nil out all results and release all refs.
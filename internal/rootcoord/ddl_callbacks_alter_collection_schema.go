@@ -25,6 +25,7 @@ import (
 	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/distributed/streaming"
+	"github.com/milvus-io/milvus/internal/util/function"
 	"github.com/milvus-io/milvus/pkg/v2/proto/messagespb"
 	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
@@ -60,12 +61,13 @@ func (c *Core) broadcastAlterCollectionSchema(ctx context.Context, req *milvuspb
 	}
 	functionSchema := funcSchemas[0]
 
-	// Physical backfill is currently only implemented for BM25 functions in the datanode
+	// Physical backfill is currently only implemented for BM25 and expression functions in the datanode
 	// backfill_compactor. Proxy performs the same check; this is a defense-in-depth guard
 	// for requests that bypass the Proxy (e.g. direct gRPC to RootCoord).
-	if addRequest.GetDoPhysicalBackfill() && functionSchema.GetType() != schemapb.FunctionType_BM25 {
+	if addRequest.GetDoPhysicalBackfill() && functionSchema.GetType() != schemapb.FunctionType_BM25 &&
+		functionSchema.GetType() != function.FunctionTypeExpression {
 		return merr.WrapErrParameterInvalidMsg(
-			"physical backfill is currently only supported for BM25 functions, got %s",
+			"physical backfill is currently only supported for BM25 and expression functions, got %s",
 			functionSchema.GetType().String())
 	}
 
@@ -151,7 +151,7 @@ func TestDDLCallbacksBroadcastAlterCollectionSchema(t *testing.T) {
 		},
 	})
 	require.ErrorIs(t, merr.CheckRPCCall(resp.GetAlterStatus(), err), merr.ErrParameterInvalid)
-	require.Contains(t, resp.GetAlterStatus().GetReason(), "physical backfill is currently only supported for BM25 functions")
+	require.Contains(t, resp.GetAlterStatus().GetReason(), "physical backfill is currently only supported for BM25 and expression functions")
 
 	// case 4.2: non-BM25 function with DoPhysicalBackfill=false should NOT be rejected by
 	// the type check (it may still fail later for other reasons, but the type check must pass).
@@ -188,8 +188,8 @@ func TestDDLCallbacksBroadcastAlterCollectionSchema(t *testing.T) {
 	// Whatever happens, it must not be the BM25-only rejection.
 	if err := merr.CheckRPCCall(resp.GetAlterStatus(), err); err != nil {
 		require.NotContains(t, resp.GetAlterStatus().GetReason(),
-			"physical backfill is currently only supported for BM25 functions",
-			"non-BM25 with DoPhysicalBackfill=false must not be rejected by the BM25-only type check")
+			"physical backfill is currently only supported for BM25 and expression functions",
+			"non-BM25 with DoPhysicalBackfill=false must not be rejected by the backfill type check")
 	}
 
 	// case 5: fieldSchema nil in fieldInfos
@@ -0,0 +1,259 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// BinlogEventInfo describes one event of a binlog file as it is laid out on disk.
+type BinlogEventInfo struct {
+	Offset         int64
+	TypeCode       EventTypeCode
+	Timestamp      typeutil.Timestamp
+	EventLength    int32
+	NextPosition   int32
+	StartTimestamp typeutil.Timestamp
+	EndTimestamp   typeutil.Timestamp
+	RowNum         int
+}
+
+// BinlogInfo is the result of InspectBinlog, it holds the descriptor of a binlog file,
+// the layout of all its events and every inconsistency found while walking them.
+type BinlogInfo struct {
+	Size            int64
+	Checksum        uint32
+	CollectionID    int64
+	PartitionID     int64
+	SegmentID       int64
+	FieldID         int64
+	PayloadDataType schemapb.DataType
+	Nullable        bool
+	Encrypted       bool
+	StartTimestamp  typeutil.Timestamp
+	EndTimestamp    typeutil.Timestamp
+	Extras          map[string]interface{}
+	Events          []*BinlogEventInfo
+	RowNum          int
+	Issues          []string
+}
+
+// Healthy returns true if no inconsistency is found in the binlog.
+func (info *BinlogInfo) Healthy() bool {
+	return len(info.Issues) == 0
+}
+
+// IsDeltalog returns true if the binlog holds delete events.
+func (info *BinlogInfo) IsDeltalog() bool {
+	return len(info.Events) > 0 && info.Events[0].TypeCode == DeleteEventType
+}
+
+// IsMultiFieldDeltalog returns true if the deltalog is written in the multi-field parquet format,
+// which stores pk and ts as two separated columns instead of one json string column.
+func (info *BinlogInfo) IsMultiFieldDeltalog() bool {
+	v, ok := info.Extras[version]
+	return ok && v == MultiField
+}
+
+func (info *BinlogInfo) addIssue(format string, args ...any) {
+	info.Issues = append(info.Issues, fmt.Sprintf(format, args...))
+}
+
+func eventTimeRange(data eventData) (typeutil.Timestamp, typeutil.Timestamp) {
+	switch evd := data.(type) {
+	case *insertEventData:
+		return evd.StartTimestamp, evd.EndTimestamp
+	case *deleteEventData:
+		return evd.StartTimestamp, evd.EndTimestamp
+	case *createCollectionEventData:
+		return evd.StartTimestamp, evd.EndTimestamp
+	case *dropCollectionEventData:
+		return evd.StartTimestamp, evd.EndTimestamp
+	case *createPartitionEventData:
+		return evd.StartTimestamp, evd.EndTimestamp
+	case *dropPartitionEventData:
+		return evd.StartTimestamp, evd.EndTimestamp
+	case *indexFileEventData:
+		return evd.StartTimestamp, evd.EndTimestamp
+	default:
+		return 0, 0
+	}
+}
+
+// InspectBinlog walks through all events of a binlog file and verifies the magic number,
+// the descriptor event, the header chain (event length and next position) and that every
+// payload can be decoded. An error is returned only if the file cannot be parsed at all,
+// inconsistencies found afterwards are recorded in BinlogInfo.Issues.
+func InspectBinlog(data []byte) (*BinlogInfo, error) {
+	info := &BinlogInfo{
+		Size:     int64(len(data)),
+		Checksum: crc32.ChecksumIEEE(data),
+	}
+
+	reader, err := NewBinlogReader(data)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	desc := reader.descriptorEvent
+	info.CollectionID = desc.CollectionID
+	info.PartitionID = desc.PartitionID
+	info.SegmentID = desc.SegmentID
+	info.FieldID = desc.FieldID
+	info.PayloadDataType = desc.PayloadDataType
+	info.StartTimestamp = desc.descriptorEventData.StartTimestamp
+	info.EndTimestamp = desc.descriptorEventData.EndTimestamp
+	info.Extras = desc.Extras
+	info.Nullable, err = desc.GetNullable()
+	if err != nil {
+		info.addIssue("invalid nullable extra: %s", err.Error())
+	}
+
+	if desc.descriptorEventHeader.TypeCode != DescriptorEventType {
+		info.addIssue("descriptor event has type code %s", desc.descriptorEventHeader.TypeCode.String())
+	}
+	descLength := desc.descriptorEventHeader.GetMemoryUsageInBytes() + desc.descriptorEventData.GetMemoryUsageInBytes()
+	if desc.descriptorEventHeader.EventLength != descLength {
+		info.addIssue("descriptor event length %d, expected %d", desc.descriptorEventHeader.EventLength, descLength)
+	}
+	if expected := int32(binary.Size(MagicNumber)) + descLength; desc.descriptorEventHeader.NextPosition != expected {
+		info.addIssue("descriptor next position %d, expected %d", desc.descriptorEventHeader.NextPosition, expected)
+	}
+	if _, ok := desc.descriptorEventData.Extras[originalSizeKey]; !ok {
+		info.addIssue("descriptor extras miss %s", originalSizeKey)
+	}
+	if _, ok := schemapb.DataType_name[int32(desc.PayloadDataType)]; !ok {
+		info.addIssue("undefined payload data type %d", desc.PayloadDataType)
+	}
+	if desc.descriptorEventData.StartTimestamp > desc.descriptorEventData.EndTimestamp {
+		info.addIssue("descriptor start timestamp %d is greater than end timestamp %d",
+			desc.descriptorEventData.StartTimestamp, desc.descriptorEventData.EndTimestamp)
+	}
+
+	// payloads of encrypted binlogs cannot be decoded without the key, only the descriptor is verified
+	if _, ok := desc.GetEdek(); ok {
+		info.Encrypted = true
+		return info, nil
+	}
+
+	for {
+		offset := info.Size - int64(reader.buffer.Len())
+		if reader.buffer.Len() == 0 {
+			break
+		}
+		event, err := reader.NextEventReader()
+		if err != nil {
+			info.addIssue("failed to read event at offset %d: %s", offset, err.Error())
+			break
+		}
+		if event == nil {
+			break
+		}
+		start, end := eventTimeRange(event.eventData)
+		ev := &BinlogEventInfo{
+			Offset:         offset,
+			TypeCode:       event.TypeCode,
+			Timestamp:      event.eventHeader.Timestamp,
+			EventLength:    event.EventLength,
+			NextPosition:   event.NextPosition,
+			StartTimestamp: start,
+			EndTimestamp:   end,
+		}
+		info.Events = append(info.Events, ev)
+
+		// writers of the serde formats leave the next position unset
+		if ev.NextPosition != -1 && int64(ev.NextPosition) != offset+int64(ev.EventLength) {
+			info.addIssue("event at offset %d has next position %d, expected %d", offset, ev.NextPosition, offset+int64(ev.EventLength))
+		}
+		if offset+int64(ev.EventLength) > info.Size {
+			info.addIssue("event at offset %d has length %d exceeding file size %d", offset, ev.EventLength, info.Size)
+		}
+		if start > end {
+			info.addIssue("event at offset %d has start timestamp %d greater than end timestamp %d", offset, start, end)
+		}
+		if len(info.Events) > 1 && ev.TypeCode != info.Events[0].TypeCode {
+			info.addIssue("event at offset %d has type %s, differs from first event type %s", offset, ev.TypeCode.String(), info.Events[0].TypeCode.String())
+		}
+
+		if ev.TypeCode == IndexFileEventType {
+			continue
+		}
+		rows, err := event.GetPayloadLengthFromReader()
+		if err != nil {
+			info.addIssue("failed to decode payload of event at offset %d: %s", offset, err.Error())
+			continue
+		}
+		ev.RowNum = rows
+		info.RowNum += rows
+	}
+
+	if len(info.Events) == 0 {
+		info.addIssue("binlog has no event")
+	}
+	return info, nil
+}
+
+// ReadBinlogRecords decodes the payloads of all insert or delete events in a binlog file
+// as arrow records and calls fn with each of them. Records are released after fn returns.
+func ReadBinlogRecords(data []byte, fn func(rec arrow.Record) error) error {
+	reader, err := NewBinlogReader(data)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if _, ok := reader.GetEdek(); ok {
+		return errors.New("cannot read records of an encrypted binlog")
+	}
+
+	for {
+		event, err := reader.NextEventReader()
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			return nil
+		}
+		if event.TypeCode != InsertEventType && event.TypeCode != DeleteEventType {
+			return fmt.Errorf("cannot read records from %s", event.TypeCode.String())
+		}
+		rr, err := event.GetArrowRecordReader()
+		if err != nil {
+			return err
+		}
+		for rr.Next() {
+			if err := fn(rr.Record()); err != nil {
+				rr.Release()
+				return err
+			}
+		}
+		err = rr.Err()
+		rr.Release()
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
+)
+
+func writeInt64BinlogForInspect(t *testing.T) []byte {
+	w := NewInsertBinlogWriter(schemapb.DataType_Int64, 10, 20, 30, 40, false)
+	defer w.Close()
+
+	curTS := time.Now().UnixNano() / int64(time.Millisecond)
+	e1, err := w.NextInsertEventWriter()
+	require.NoError(t, err)
+	require.NoError(t, e1.AddDataToPayloadForUT([]int64{1, 2, 3}, nil))
+	e1.SetEventTimestamp(tsoutil.ComposeTS(curTS, 0), tsoutil.ComposeTS(curTS+1000, 0))
+
+	e2, err := w.NextInsertEventWriter()
+	require.NoError(t, err)
+	require.NoError(t, e2.AddDataToPayloadForUT([]int64{4, 5}, nil))
+	e2.SetEventTimestamp(tsoutil.ComposeTS(curTS+1000, 0), tsoutil.ComposeTS(curTS+2000, 0))
+
+	w.SetEventTimeStamp(tsoutil.ComposeTS(curTS, 0), tsoutil.ComposeTS(curTS+2000, 0))
+	w.AddExtra(originalSizeKey, fmt.Sprintf("%v", 40))
+	require.NoError(t, w.Finish())
+	buf, err := w.GetBuffer()
+	require.NoError(t, err)
+	return buf
+}
+
+func TestInspectBinlog(t *testing.T) {
+	buf := writeInt64BinlogForInspect(t)
+
+	info, err := InspectBinlog(buf)
+	require.NoError(t, err)
+	assert.True(t, info.Healthy(), info.Issues)
+	assert.Equal(t, int64(10), info.CollectionID)
+	assert.Equal(t, int64(20), info.PartitionID)
+	assert.Equal(t, int64(30), info.SegmentID)
+	assert.Equal(t, int64(40), info.FieldID)
+	assert.Equal(t, schemapb.DataType_Int64, info.PayloadDataType)
+	assert.Equal(t, 5, info.RowNum)
+	assert.Len(t, info.Events, 2)
+	assert.Equal(t, 3, info.Events[0].RowNum)
+	assert.Equal(t, 2, info.Events[1].RowNum)
+	assert.False(t, info.IsDeltalog())
+
+	t.Run("corrupted next position", func(t *testing.T) {
+		corrupted := append([]byte{}, buf...)
+		// event header layout: timestamp(8) + type code(1) + event length(4) + next position(4)
+		pos := info.Events[0].Offset + 13
+		common.Endian.PutUint32(corrupted[pos:], uint32(info.Events[0].NextPosition+1))
+
+		corruptedInfo, err := InspectBinlog(corrupted)
+		require.NoError(t, err)
+		assert.False(t, corruptedInfo.Healthy())
+		assert.NotEqual(t, info.Checksum, corruptedInfo.Checksum)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		truncatedInfo, err := InspectBinlog(buf[:len(buf)-8])
+		require.NoError(t, err)
+		assert.False(t, truncatedInfo.Healthy())
+	})
+
+	t.Run("bad magic", func(t *testing.T) {
+		_, err := InspectBinlog([]byte{0, 1, 2, 3, 4, 5})
+		assert.Error(t, err)
+	})
+}
+
+func TestInspectDeltalog(t *testing.T) {
+	dData := NewDeleteData([]PrimaryKey{NewInt64PrimaryKey(1), NewInt64PrimaryKey(2)}, []Timestamp{100, 200})
+	blob, err := NewDeleteCodec().Serialize(1, 2, 3, dData)
+	require.NoError(t, err)
+
+	info, err := InspectBinlog(blob.Value)
+	require.NoError(t, err)
+	assert.True(t, info.Healthy(), info.Issues)
+	assert.True(t, info.IsDeltalog())
+	assert.False(t, info.IsMultiFieldDeltalog())
+	assert.Equal(t, 2, info.RowNum)
+}
+
+func TestReadBinlogRecords(t *testing.T) {
+	buf := writeInt64BinlogForInspect(t)
+
+	rows := 0
+	err := ReadBinlogRecords(buf, func(rec arrow.Record) error {
+		rows += int(rec.NumRows())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, rows)
+
+	err = ReadBinlogRecords(buf, func(rec arrow.Record) error {
+		return fmt.Errorf("mock error")
+	})
+	assert.Error(t, err)
+}
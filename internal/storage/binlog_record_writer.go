@@ -45,6 +45,8 @@ type BinlogRecordWriter interface {
 		manifest string,
 		expirQuantiles []int64,
 	)
+	// GetTimeFieldRange returns the [min, max] values of the compaction time field written.
+	GetTimeFieldRange() []int64
 	GetRowNum() int64
 	FlushChunk() error
 	GetBufferUncompressed() uint64
@@ -69,6 +71,7 @@ type packedBinlogRecordWriterBase struct {
 
 	pkCollector         *PkStatsCollector
 	bm25Collector       *Bm25StatsCollector
+	timeRangeCollector  *TimeFieldRangeCollector
 	tsFrom              typeutil.Timestamp
 	tsTo                typeutil.Timestamp
 	rowNum              int64
@@ -107,6 +110,10 @@ func (pw *packedBinlogRecordWriterBase) GetExpirQuantiles() []int64 {
 	return calculateExpirQuantiles(pw.ttlFieldID, pw.rowNum, pw.ttlFieldValues)
 }
 
+func (pw *packedBinlogRecordWriterBase) GetTimeFieldRange() []int64 {
+	return pw.timeRangeCollector.Range()
+}
+
 func (pw *packedBinlogRecordWriterBase) writeStats() error {
 	// Write PK stats
 	pkStatsMap, err := pw.pkCollector.Digest(
@@ -247,6 +254,9 @@ func (pw *PackedBinlogRecordWriter) Write(r Record) error {
 	if err := pw.bm25Collector.Collect(r); err != nil {
 		return err
 	}
+	if err := pw.timeRangeCollector.Collect(r); err != nil {
+		return err
+	}
 
 	pw.collectNullCounts(r)
 
@@ -367,6 +377,7 @@ func newPackedBinlogRecordWriter(collectionID, partitionID, segmentID UniqueID,
 	}
 
 	writer.bm25Collector = NewBm25StatsCollector(schema)
+	writer.timeRangeCollector = NewTimeFieldRangeCollector(schema)
 
 	return writer, nil
 }
@@ -404,6 +415,9 @@ func (pw *PackedManifestRecordWriter) Write(r Record) error {
 	if err := pw.bm25Collector.Collect(r); err != nil {
 		return err
 	}
+	if err := pw.timeRangeCollector.Collect(r); err != nil {
+		return err
+	}
 
 	pw.collectNullCounts(r)
 
@@ -595,6 +609,7 @@ func newPackedManifestRecordWriter(collectionID, partitionID, segmentID UniqueID
 	}
 
 	writer.bm25Collector = NewBm25StatsCollector(schema)
+	writer.timeRangeCollector = NewTimeFieldRangeCollector(schema)
 
 	return writer, nil
 }
@@ -634,6 +649,9 @@ func (pw *PackedTextManifestRecordWriter) Write(r Record) error {
 	if err := pw.bm25Collector.Collect(r); err != nil {
 		return err
 	}
+	if err := pw.timeRangeCollector.Collect(r); err != nil {
+		return err
+	}
 
 	pw.collectNullCounts(r)
 
@@ -756,6 +774,7 @@ func NewPackedTextManifestRecordWriter(
 	}
 
 	writer.bm25Collector = NewBm25StatsCollector(schema)
+	writer.timeRangeCollector = NewTimeFieldRangeCollector(schema)
 
 	return writer, nil
 }
@@ -161,6 +161,47 @@ func ValueDeserializerWithSchema(r Record, v []*Value, schema *schemapb.Collecti
 	return valueDeserializer(r, v, allFields, shouldCopy)
 }
 
+// DeserializeFieldData deserializes the column of the field in the record into field data,
+// null is replaced by the default value of the field if it has one, the same as valueDeserializer.
+func DeserializeFieldData(r Record, field *schemapb.FieldSchema) (FieldData, error) {
+	col := r.Column(field.GetFieldID())
+	if col == nil {
+		return nil, merr.WrapErrServiceInternal(fmt.Sprintf("field %d not found in record", field.GetFieldID()))
+	}
+	dt := field.GetDataType()
+	elementType := schemapb.DataType_None
+	dim := 0
+	if typeutil.IsVectorType(dt) {
+		dimValue, _ := typeutil.GetDim(field)
+		dim = int(dimValue)
+	}
+	if dt == schemapb.DataType_ArrayOfVector {
+		elementType = field.GetElementType()
+	}
+
+	fieldData, err := NewFieldData(dt, field, r.Len())
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < r.Len(); i++ {
+		var d any
+		if col.IsNull(i) {
+			if field.GetDefaultValue() != nil {
+				d = GetDefaultValue(field)
+			}
+		} else {
+			d, err = serdeMap[dt].deserialize(col, i, elementType, dim, true)
+			if err != nil {
+				return nil, merr.WrapErrServiceInternal(fmt.Sprintf("deserialize error on type %s: %v", dt, err))
+			}
+		}
+		if err := fieldData.AppendRow(d); err != nil {
+			return nil, err
+		}
+	}
+	return fieldData, nil
+}
+
 func valueDeserializer(r Record, v []*Value, fields []*schemapb.FieldSchema, shouldCopy bool) error {
 	pkField := func() *schemapb.FieldSchema {
 		for _, field := range fields {
@@ -492,13 +533,14 @@ type CompositeBinlogRecordWriter struct {
 	maxRowNum    int64
 
 	// writers and stats generated at runtime
-	fieldWriters  map[FieldID]*BinlogStreamWriter
-	rw            RecordWriter
-	pkCollector   *PkStatsCollector
-	bm25Collector *Bm25StatsCollector
-	tsFrom        typeutil.Timestamp
-	tsTo          typeutil.Timestamp
-	rowNum        int64
+	fieldWriters       map[FieldID]*BinlogStreamWriter
+	rw                 RecordWriter
+	pkCollector        *PkStatsCollector
+	bm25Collector      *Bm25StatsCollector
+	timeRangeCollector *TimeFieldRangeCollector
+	tsFrom             typeutil.Timestamp
+	tsTo               typeutil.Timestamp
+	rowNum             int64
 
 	// results
 	fieldBinlogs map[FieldID]*datapb.FieldBinlog
@@ -562,6 +604,9 @@ func (c *CompositeBinlogRecordWriter) Write(r Record) error {
 	if err := c.bm25Collector.Collect(r); err != nil {
 		return err
 	}
+	if err := c.timeRangeCollector.Collect(r); err != nil {
+		return err
+	}
 
 	if err := c.rw.Write(r); err != nil {
 		return err
@@ -721,6 +766,10 @@ func (c *CompositeBinlogRecordWriter) GetExpirQuantiles() []int64 {
 	return calculateExpirQuantiles(c.ttlFieldID, c.rowNum, c.ttlFieldValues)
 }
 
+func (c *CompositeBinlogRecordWriter) GetTimeFieldRange() []int64 {
+	return c.timeRangeCollector.Range()
+}
+
 func (c *CompositeBinlogRecordWriter) GetLogs() (
 	fieldBinlogs map[FieldID]*datapb.FieldBinlog,
 	statsLog *datapb.FieldBinlog,
@@ -768,6 +817,7 @@ func newCompositeBinlogRecordWriter(collectionID, partitionID, segmentID UniqueI
 	}
 
 	writer.bm25Collector = NewBm25StatsCollector(schema)
+	writer.timeRangeCollector = NewTimeFieldRangeCollector(schema)
 
 	return writer, nil
 }
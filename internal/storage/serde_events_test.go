@@ -512,6 +512,50 @@ func TestNull(t *testing.T) {
 	})
 }
 
+func TestDeserializeFieldData(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "int64", DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "nullable", DataType: schemapb.DataType_VarChar, Nullable: true},
+			{
+				FieldID: 102, Name: "default", DataType: schemapb.DataType_VarChar, Nullable: true,
+				DefaultValue: &schemapb.ValueField{Data: &schemapb.ValueField_StringData{StringData: "none"}},
+			},
+		},
+	}
+	insertData := &InsertData{
+		Data: map[FieldID]FieldData{
+			100: &Int64FieldData{Data: []int64{1, 2, 3}},
+			101: &StringFieldData{Data: []string{"a", "", "c"}, ValidData: []bool{true, false, true}, Nullable: true},
+			102: &StringFieldData{Data: []string{"", "b", ""}, ValidData: []bool{false, true, false}, Nullable: true},
+		},
+	}
+
+	arrowSchema, err := ConvertToArrowSchema(schema, false)
+	assert.NoError(t, err)
+	recordBuilder := array.NewRecordBuilder(memory.DefaultAllocator, arrowSchema)
+	defer recordBuilder.Release()
+	err = BuildRecord(recordBuilder, insertData, schema)
+	assert.NoError(t, err)
+	record := NewSimpleArrowRecord(recordBuilder.NewRecord(), map[FieldID]int{100: 0, 101: 1, 102: 2})
+	defer record.Release()
+
+	fieldData, err := DeserializeFieldData(record, schema.Fields[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, fieldData.GetDataRows())
+
+	fieldData, err = DeserializeFieldData(record, schema.Fields[1])
+	assert.NoError(t, err)
+	assert.Equal(t, 3, fieldData.RowNum())
+	assert.Equal(t, "a", fieldData.GetRow(0))
+	assert.Nil(t, fieldData.GetRow(1))
+	assert.Equal(t, "c", fieldData.GetRow(2))
+
+	fieldData, err = DeserializeFieldData(record, schema.Fields[2])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"none", "b", "none"}, fieldData.GetDataRows())
+}
+
 func generateTestDeltalogData(size int) (*Blob, error) {
 	codec := NewDeleteCodec()
 	pks := make([]int64, size)
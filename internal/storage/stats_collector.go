@@ -25,6 +25,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/allocator"
+	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/proto/datapb"
 	"github.com/milvus-io/milvus/pkg/v2/proto/etcdpb"
 	"github.com/milvus-io/milvus/pkg/v2/util/metautil"
@@ -321,3 +322,56 @@ func NewBm25StatsCollector(schema *schemapb.CollectionSchema) *Bm25StatsCollecto
 		bm25Stats: bm25Stats,
 	}
 }
+
+// TimeFieldRangeCollector collects the min and max values of the time field of the
+// time-partitioned compaction, the range is kept in the segment meta instead of a stats log.
+type TimeFieldRangeCollector struct {
+	fieldID   int64
+	min       int64
+	max       int64
+	collected bool
+}
+
+// Collect collects the time field range from the record, null values are skipped.
+func (c *TimeFieldRangeCollector) Collect(r Record) error {
+	if c.fieldID < common.StartOfUserFieldID {
+		return nil
+	}
+	column := r.Column(c.fieldID)
+	if column == nil {
+		return errors.New("compaction time field not found")
+	}
+	values, ok := column.(*array.Int64)
+	if !ok {
+		return errors.New("compaction time field is not int64")
+	}
+	for i := 0; i < values.Len(); i++ {
+		if values.IsNull(i) {
+			continue
+		}
+		v := values.Value(i)
+		if !c.collected || v < c.min {
+			c.min = v
+		}
+		if !c.collected || v > c.max {
+			c.max = v
+		}
+		c.collected = true
+	}
+	return nil
+}
+
+// Range returns the [min, max] of the collected values, nil if there is no time field or no value.
+func (c *TimeFieldRangeCollector) Range() []int64 {
+	if !c.collected {
+		return nil
+	}
+	return []int64{c.min, c.max}
+}
+
+// NewTimeFieldRangeCollector creates a collector of the time field range of the schema.
+func NewTimeFieldRangeCollector(schema *schemapb.CollectionSchema) *TimeFieldRangeCollector {
+	return &TimeFieldRangeCollector{
+		fieldID: common.GetCollectionCompactionTimeFieldID(schema),
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
 	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/allocator"
 	"github.com/milvus-io/milvus/pkg/v2/common"
@@ -259,3 +260,52 @@ func TestBm25StatsCollector_DigestEndToEnd(t *testing.T) {
 	_, err := collector.Digest(1, 2, 3, "/tmp", 10, alloc, writer)
 	assert.NoError(t, err)
 }
+
+func TestTimeFieldRangeCollector(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "event_time", DataType: schemapb.DataType_Timestamptz, Nullable: true},
+		},
+		Properties: []*commonpb.KeyValuePair{{Key: common.CollectionCompactionTimeFieldKey, Value: "event_time"}},
+	}
+
+	newRecord := func(values []int64, valid []bool) Record {
+		arrowSchema := arrow.NewSchema([]arrow.Field{{Name: "event_time", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+		builder := array.NewRecordBuilder(memory.DefaultAllocator, arrowSchema)
+		defer builder.Release()
+		builder.Field(0).(*array.Int64Builder).AppendValues(values, valid)
+		return NewSimpleArrowRecord(builder.NewRecord(), map[FieldID]int{101: 0})
+	}
+
+	t.Run("collect range", func(t *testing.T) {
+		collector := NewTimeFieldRangeCollector(schema)
+		assert.Nil(t, collector.Range())
+
+		assert.NoError(t, collector.Collect(newRecord([]int64{300, 100, 999}, []bool{true, true, false})))
+		assert.NoError(t, collector.Collect(newRecord([]int64{200, 500}, nil)))
+		assert.Equal(t, []int64{100, 500}, collector.Range())
+	})
+
+	t.Run("all null", func(t *testing.T) {
+		collector := NewTimeFieldRangeCollector(schema)
+		assert.NoError(t, collector.Collect(newRecord([]int64{1, 2}, []bool{false, false})))
+		assert.Nil(t, collector.Range())
+	})
+
+	t.Run("no time field", func(t *testing.T) {
+		collector := NewTimeFieldRangeCollector(&schemapb.CollectionSchema{Fields: schema.GetFields()})
+		assert.NoError(t, collector.Collect(newRecord([]int64{1}, nil)))
+		assert.Nil(t, collector.Range())
+	})
+
+	t.Run("time field not int64", func(t *testing.T) {
+		collector := NewTimeFieldRangeCollector(schema)
+		arrowSchema := arrow.NewSchema([]arrow.Field{{Name: "event_time", Type: arrow.PrimitiveTypes.Float64}}, nil)
+		builder := array.NewRecordBuilder(memory.DefaultAllocator, arrowSchema)
+		defer builder.Release()
+		builder.Field(0).(*array.Float64Builder).Append(1)
+		record := NewSimpleArrowRecord(builder.NewRecord(), map[FieldID]int{101: 0})
+		assert.Error(t, collector.Collect(record))
+	})
+}
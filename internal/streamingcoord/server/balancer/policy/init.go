@@ -2,9 +2,11 @@ package vchannelfair
 
 import (
 	"github.com/milvus-io/milvus/internal/streamingcoord/server/balancer"
+	"github.com/milvus-io/milvus/internal/streamingcoord/server/balancer/policy/throughputfair"
 	"github.com/milvus-io/milvus/internal/streamingcoord/server/balancer/policy/vchannelfair"
 )
 
 func init() {
 	balancer.RegisterPolicy(&vchannelfair.PolicyBuilder{})
+	balancer.RegisterPolicy(&throughputfair.PolicyBuilder{})
 }
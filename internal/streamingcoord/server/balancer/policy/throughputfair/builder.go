@@ -0,0 +1,60 @@
+package throughputfair
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/milvus-io/milvus/internal/streamingcoord/server/balancer"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+const (
+	policyName = "throughputFair"
+)
+
+// PolicyBuilder is a builder to build throughput fair policy.
+type PolicyBuilder struct{}
+
+// Name returns the name of the throughput fair policy.
+func (b *PolicyBuilder) Name() string {
+	return policyName
+}
+
+// Build creates a new throughput fair policy.
+func (b *PolicyBuilder) Build() balancer.Policy {
+	cfg := newThroughputFairPolicyConfig()
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+	return &policy{
+		cfg: cfg,
+	}
+}
+
+// newThroughputFairPolicyConfig creates a new throughput fair policy config.
+func newThroughputFairPolicyConfig() policyConfig {
+	params := paramtable.Get()
+	return policyConfig{
+		RebalanceTolerance: params.StreamingCfg.WALBalancerPolicyThroughputFairRebalanceTolerance.GetAsFloat(),
+		RebalanceMaxStep:   params.StreamingCfg.WALBalancerPolicyThroughputFairRebalanceMaxStep.GetAsInt(),
+		RebalanceInterval:  params.StreamingCfg.WALBalancerPolicyThroughputFairRebalanceInterval.GetAsDurationByParse(),
+		MinThroughput:      float64(params.StreamingCfg.WALBalancerPolicyThroughputFairMinThroughput.GetAsSize()),
+	}
+}
+
+// policyConfig is the config for throughput fair policy.
+type policyConfig struct {
+	RebalanceTolerance float64
+	RebalanceMaxStep   int
+	RebalanceInterval  time.Duration
+	MinThroughput      float64
+}
+
+// Validate validates the throughput fair policy config.
+func (c policyConfig) Validate() error {
+	if c.RebalanceTolerance < 0 || c.RebalanceMaxStep < 0 || c.RebalanceInterval < 0 || c.MinThroughput < 0 {
+		return errors.Errorf("invalid throughput fair policy config, %+v", c)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package throughputfair
+
+import (
+	"github.com/milvus-io/milvus/internal/streamingcoord/server/balancer"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/types"
+)
+
+// newExpectedLayoutForThroughputFairPolicy creates a new expected layout for throughput fair policy.
+func newExpectedLayoutForThroughputFairPolicy(currentLayout balancer.CurrentLayout) *expectedLayoutForThroughputFairPolicy {
+	nodes := make(map[int64]*streamingNodeInfo, len(currentLayout.AllNodesInfo))
+	for nodeID := range currentLayout.AllNodesInfo {
+		nodes[nodeID] = &streamingNodeInfo{
+			ServerID:         nodeID,
+			AssignedChannels: make(map[types.ChannelID]struct{}),
+		}
+	}
+	return &expectedLayoutForThroughputFairPolicy{
+		CurrentLayout: currentLayout,
+		Assignments:   make(map[types.ChannelID]types.PChannelInfoAssigned, len(currentLayout.Channels)),
+		Nodes:         nodes,
+	}
+}
+
+// streamingNodeInfo is the streaming node info for throughput fair policy.
+type streamingNodeInfo struct {
+	ServerID         int64
+	Throughput       float64 // the sum of append throughput of all pchannels assigned to the node.
+	AssignedChannels map[types.ChannelID]struct{}
+}
+
+// lessLoaded returns true if the node is less loaded than the other one.
+func (n *streamingNodeInfo) lessLoaded(other *streamingNodeInfo) bool {
+	if n.Throughput != other.Throughput {
+		return n.Throughput < other.Throughput
+	}
+	if len(n.AssignedChannels) != len(other.AssignedChannels) {
+		return len(n.AssignedChannels) < len(other.AssignedChannels)
+	}
+	return n.ServerID < other.ServerID
+}
+
+// expectedLayoutForThroughputFairPolicy is the expected layout of streaming node and pChannel.
+type expectedLayoutForThroughputFairPolicy struct {
+	CurrentLayout balancer.CurrentLayout
+	Assignments   map[types.ChannelID]types.PChannelInfoAssigned // current assignment of pchannel to streamingnode.
+	Nodes         map[int64]*streamingNodeInfo
+}
+
+// ChannelThroughput returns the append throughput of the pchannel, 0 if the metrics of the pchannel is not reported.
+func (p *expectedLayoutForThroughputFairPolicy) ChannelThroughput(channelID types.ChannelID) float64 {
+	if m, ok := p.CurrentLayout.GetWALMetrics(channelID).(types.RWWALMetrics); ok {
+		return float64(m.AppendBytesPerSecond)
+	}
+	return 0
+}
+
+// ChannelBacklog returns the recovery lag of the pchannel, 0 if the metrics of the pchannel is not reported.
+func (p *expectedLayoutForThroughputFairPolicy) ChannelBacklog(channelID types.ChannelID) float64 {
+	if m, ok := p.CurrentLayout.GetWALMetrics(channelID).(types.RWWALMetrics); ok {
+		return m.RecoveryLag().Seconds()
+	}
+	return 0
+}
+
+// TotalThroughput returns the sum of append throughput of all assigned pchannels.
+func (p *expectedLayoutForThroughputFairPolicy) TotalThroughput() float64 {
+	total := 0.0
+	for _, node := range p.Nodes {
+		total += node.Throughput
+	}
+	return total
+}
+
+// LeastLoadedNode returns the node with the lowest append throughput.
+func (p *expectedLayoutForThroughputFairPolicy) LeastLoadedNode() *streamingNodeInfo {
+	var target *streamingNodeInfo
+	for _, node := range p.Nodes {
+		if target == nil || node.lessLoaded(target) {
+			target = node
+		}
+	}
+	return target
+}
+
+// MostLoadedNode returns the node with the highest append throughput.
+func (p *expectedLayoutForThroughputFairPolicy) MostLoadedNode() *streamingNodeInfo {
+	var target *streamingNodeInfo
+	for _, node := range p.Nodes {
+		if target == nil || target.lessLoaded(node) {
+			target = node
+		}
+	}
+	return target
+}
+
+// Assign will assign the channel to the node.
+func (p *expectedLayoutForThroughputFairPolicy) Assign(channelID types.ChannelID, serverID int64) {
+	if _, ok := p.Assignments[channelID]; ok {
+		panic("channel already assigned")
+	}
+	expectedAccessMode, ok := p.CurrentLayout.ExpectedAccessMode[channelID]
+	if !ok {
+		panic("expected access mode not found")
+	}
+	node, ok := p.CurrentLayout.AllNodesInfo[serverID]
+	if !ok {
+		panic("node info not found")
+	}
+
+	info := p.CurrentLayout.Channels[channelID]
+	info.AccessMode = expectedAccessMode
+	info.Term++
+	p.Assignments[channelID] = types.PChannelInfoAssigned{
+		Channel: info,
+		Node:    node.StreamingNodeInfo,
+	}
+	p.Nodes[serverID].AssignedChannels[channelID] = struct{}{}
+	p.Nodes[serverID].Throughput += p.ChannelThroughput(channelID)
+}
+
+// Unassign will unassign the channel from the node.
+func (p *expectedLayoutForThroughputFairPolicy) Unassign(channelID types.ChannelID) {
+	assignment, ok := p.Assignments[channelID]
+	if !ok {
+		panic("channel is not assigned")
+	}
+	node := p.Nodes[assignment.Node.ServerID]
+	delete(p.Assignments, channelID)
+	delete(node.AssignedChannels, channelID)
+	node.Throughput -= p.ChannelThroughput(channelID)
+}
@@ -0,0 +1,161 @@
+package throughputfair
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/streamingcoord/server/balancer"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/types"
+)
+
+var _ balancer.Policy = &policy{}
+
+// policy is a policy to balance the load of streaming node by the append throughput of wal.
+// The new incoming pchannels are assigned to the least loaded node,
+// and the pchannels on the hottest node are moved to the coldest node step by step if the throughput is unbalanced.
+// The rebalance is protected by the tolerance (hysteresis) and the rebalance interval (move rate limit)
+// to avoid pchannels moving back and forth for the fluctuation of throughput.
+type policy struct {
+	log.Binder
+	cfg           policyConfig
+	lastRebalance time.Time
+}
+
+// Name returns the name of the policy.
+func (p *policy) Name() string {
+	return policyName
+}
+
+// Balance will balance the load of streaming node by the append throughput of wal.
+func (p *policy) Balance(currentLayout balancer.CurrentLayout) (balancer.ExpectedLayout, error) {
+	if currentLayout.TotalNodes() == 0 {
+		return balancer.ExpectedLayout{}, errors.New("no available streaming node")
+	}
+	// update policy configuration before balancing.
+	p.updatePolicyConfiguration()
+
+	expectedLayout := newExpectedLayoutForThroughputFairPolicy(currentLayout)
+
+	// 1. Keep the current layout first to make the balance result more stable.
+	newIncomingChannels := make([]types.ChannelID, 0)
+	for channelID := range currentLayout.Channels {
+		if serverID, ok := currentLayout.ChannelsToNodes[channelID]; ok {
+			expectedLayout.Assign(channelID, serverID)
+			continue
+		}
+		newIncomingChannels = append(newIncomingChannels, channelID)
+	}
+
+	// 2. Assign the new incoming channels to the least loaded node,
+	// the hottest pchannel is assigned first.
+	sort.Slice(newIncomingChannels, func(i, j int) bool {
+		ti, tj := expectedLayout.ChannelThroughput(newIncomingChannels[i]), expectedLayout.ChannelThroughput(newIncomingChannels[j])
+		if ti != tj {
+			return ti > tj
+		}
+		return newIncomingChannels[i].LT(newIncomingChannels[j])
+	})
+	for _, channelID := range newIncomingChannels {
+		expectedLayout.Assign(channelID, expectedLayout.LeastLoadedNode().ServerID)
+	}
+
+	if !currentLayout.Config.AllowRebalance || time.Since(p.lastRebalance) < p.cfg.RebalanceInterval {
+		return balancer.ExpectedLayout{
+			ChannelAssignment: expectedLayout.Assignments,
+		}, nil
+	}
+
+	// 3. Move the pchannels from the hottest node to the coldest node until the layout is balanced.
+	movedChannels := make([]types.ChannelID, 0, p.cfg.RebalanceMaxStep)
+	for i := 0; i < p.cfg.RebalanceMaxStep; i++ {
+		channelID, targetNodeID := p.findChannelToMove(expectedLayout)
+		if channelID.IsZero() {
+			break
+		}
+		expectedLayout.Unassign(channelID)
+		expectedLayout.Assign(channelID, targetNodeID)
+		movedChannels = append(movedChannels, channelID)
+	}
+	if len(movedChannels) > 0 {
+		p.lastRebalance = time.Now()
+		p.Logger().Info(
+			"throughput fair policy rebalance pchannels",
+			zap.Stringers("movedChannels", movedChannels),
+			zap.Any("nodeThroughputs", nodeThroughputs(expectedLayout)),
+			zap.Any("config", p.cfg),
+		)
+	}
+	return balancer.ExpectedLayout{
+		ChannelAssignment: expectedLayout.Assignments,
+	}, nil
+}
+
+// findChannelToMove finds the pchannel on the hottest node that should be moved to the coldest node.
+// A zero channel id is returned if the layout is balanced enough or no pchannel can be moved.
+func (p *policy) findChannelToMove(expectedLayout *expectedLayoutForThroughputFairPolicy) (types.ChannelID, int64) {
+	if len(expectedLayout.Nodes) < 2 {
+		return types.ChannelID{}, 0
+	}
+	hottest := expectedLayout.MostLoadedNode()
+	coldest := expectedLayout.LeastLoadedNode()
+	average := expectedLayout.TotalThroughput() / float64(len(expectedLayout.Nodes))
+	if hottest.Throughput < p.cfg.MinThroughput || hottest.Throughput <= average*(1+p.cfg.RebalanceTolerance) {
+		return types.ChannelID{}, 0
+	}
+
+	// the move should reduce the throughput gap of the two nodes by more than the tolerance.
+	currentGap := hottest.Throughput - coldest.Throughput
+	maxGap := currentGap - average*p.cfg.RebalanceTolerance
+	var target types.ChannelID
+	targetGap := math.MaxFloat64
+	for channelID := range hottest.AssignedChannels {
+		throughput := expectedLayout.ChannelThroughput(channelID)
+		if throughput <= 0 || !expectedLayout.CurrentLayout.AllowRebalance(channelID) {
+			continue
+		}
+		gap := math.Abs(currentGap - 2*throughput)
+		if gap >= maxGap {
+			continue
+		}
+		// prefer the pchannel with less backlog, which can be recovered faster on the new node.
+		if target.IsZero() || gap < targetGap || (gap == targetGap && p.lessBacklog(expectedLayout, channelID, target)) {
+			target = channelID
+			targetGap = gap
+		}
+	}
+	return target, coldest.ServerID
+}
+
+// lessBacklog returns true if the backlog of channel a is less than channel b.
+func (p *policy) lessBacklog(expectedLayout *expectedLayoutForThroughputFairPolicy, a types.ChannelID, b types.ChannelID) bool {
+	ba, bb := expectedLayout.ChannelBacklog(a), expectedLayout.ChannelBacklog(b)
+	if ba != bb {
+		return ba < bb
+	}
+	return a.LT(b)
+}
+
+// updatePolicyConfiguration will update the policy configuration.
+func (p *policy) updatePolicyConfiguration() {
+	// try to fetch latest configuration.
+	newCfg := newThroughputFairPolicyConfig()
+	if err := newCfg.Validate(); err != nil {
+		p.Logger().Warn("invalid new incoming throughput fair policy config", zap.Any("new", newCfg))
+	} else if p.cfg != newCfg {
+		p.Logger().Info("throughput fair policy config updated", zap.Any("old", p.cfg), zap.Any("new", newCfg))
+		p.cfg = newCfg
+	}
+}
+
+// nodeThroughputs returns the append throughput of all nodes, used for logging.
+func nodeThroughputs(expectedLayout *expectedLayoutForThroughputFairPolicy) map[int64]float64 {
+	return lo.MapValues(expectedLayout.Nodes, func(node *streamingNodeInfo, _ int64) float64 {
+		return node.Throughput
+	})
+}
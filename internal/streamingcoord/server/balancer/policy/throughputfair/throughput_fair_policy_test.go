@@ -0,0 +1,164 @@
+package throughputfair
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/internal/streamingcoord/server/balancer"
+	"github.com/milvus-io/milvus/internal/streamingcoord/server/balancer/channel"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/types"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/tsoutil"
+)
+
+const mb = 1024 * 1024
+
+func TestThroughputFairPolicy(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.StreamingCfg.WALBalancerPolicyThroughputFairRebalanceInterval.Key, "1h")
+	defer params.Reset(params.StreamingCfg.WALBalancerPolicyThroughputFairRebalanceInterval.Key)
+
+	policy := (&PolicyBuilder{}).Build()
+	assert.Equal(t, "throughputFair", policy.Name())
+	_, err := policy.Balance(balancer.CurrentLayout{})
+	assert.Error(t, err)
+
+	// the hottest pchannel is moved to balance the throughput.
+	layout := newLayout(map[string]channelLoad{
+		"c1": {node: 1, throughput: 4 * mb},
+		"c2": {node: 1, throughput: 3 * mb},
+		"c3": {node: 1, throughput: 1 * mb},
+		"c4": {node: 1, throughput: 1 * mb},
+		"c5": {node: 2, throughput: 1 * mb},
+		"c6": {node: -1},
+	}, []int64{1, 2})
+	expected, err := policy.Balance(layout)
+	assert.NoError(t, err)
+	assert.Len(t, expected.ChannelAssignment, 6)
+	assert.Equal(t, int64(2), expected.ChannelAssignment[newChannelID("c1")].Node.ServerID)
+	assert.Equal(t, int64(1), expected.ChannelAssignment[newChannelID("c2")].Node.ServerID)
+	// the new incoming pchannel is assigned to the least loaded node.
+	assert.Equal(t, int64(2), expected.ChannelAssignment[newChannelID("c6")].Node.ServerID)
+
+	// the move rate is limited by the rebalance interval.
+	expected, err = policy.Balance(layout)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), expected.ChannelAssignment[newChannelID("c1")].Node.ServerID)
+
+	params.Save(params.StreamingCfg.WALBalancerPolicyThroughputFairRebalanceInterval.Key, "0s")
+	expected, err = policy.Balance(layout)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), expected.ChannelAssignment[newChannelID("c1")].Node.ServerID)
+
+	// the layout within the tolerance is not rebalanced.
+	expected, err = policy.Balance(newLayout(map[string]channelLoad{
+		"c1": {node: 1, throughput: 5 * mb},
+		"c2": {node: 2, throughput: 3 * mb},
+		"c3": {node: 2, throughput: 1 * mb},
+	}, []int64{1, 2}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), expected.ChannelAssignment[newChannelID("c1")].Node.ServerID)
+
+	// the pchannel that makes the layout worse is not moved.
+	expected, err = policy.Balance(newLayout(map[string]channelLoad{
+		"c1": {node: 1, throughput: 10 * mb},
+		"c2": {node: 2, throughput: 1 * mb},
+	}, []int64{1, 2}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), expected.ChannelAssignment[newChannelID("c1")].Node.ServerID)
+
+	// the cluster with low write load is not rebalanced.
+	expected, err = policy.Balance(newLayout(map[string]channelLoad{
+		"c1": {node: 1, throughput: 1024},
+		"c2": {node: 1, throughput: 1024},
+	}, []int64{1, 2}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), expected.ChannelAssignment[newChannelID("c1")].Node.ServerID)
+	assert.Equal(t, int64(1), expected.ChannelAssignment[newChannelID("c2")].Node.ServerID)
+
+	// the pchannel with less backlog is preferred.
+	layout = newLayout(map[string]channelLoad{
+		"c1": {node: 1, throughput: 2 * mb, backlog: 10 * time.Second},
+		"c2": {node: 1, throughput: 2 * mb},
+		"c3": {node: 2},
+	}, []int64{1, 2})
+	layout.Config.AllowRebalanceRecoveryLagThreshold = time.Minute
+	expected, err = policy.Balance(layout)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), expected.ChannelAssignment[newChannelID("c1")].Node.ServerID)
+	assert.Equal(t, int64(2), expected.ChannelAssignment[newChannelID("c2")].Node.ServerID)
+
+	// rebalance is not allowed.
+	layout.Config.AllowRebalance = false
+	expected, err = policy.Balance(layout)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), expected.ChannelAssignment[newChannelID("c2")].Node.ServerID)
+}
+
+func TestPolicyConfig(t *testing.T) {
+	assert.NoError(t, policyConfig{RebalanceTolerance: 0.2, RebalanceMaxStep: 1}.Validate())
+	assert.Error(t, policyConfig{RebalanceTolerance: -1}.Validate())
+	assert.Error(t, policyConfig{RebalanceMaxStep: -1}.Validate())
+}
+
+type channelLoad struct {
+	node       int64
+	throughput uint64
+	backlog    time.Duration
+}
+
+func newChannelID(channel string) types.ChannelID {
+	return types.ChannelID{
+		Name: channel,
+	}
+}
+
+func newLayout(channels map[string]channelLoad, serverIDs []int64) balancer.CurrentLayout {
+	layout := balancer.CurrentLayout{
+		Config: balancer.CommonBalancePolicyConfig{
+			AllowRebalance:                     true,
+			AllowRebalanceRecoveryLagThreshold: 1 * time.Second,
+			MinRebalanceIntervalThreshold:      1 * time.Second,
+		},
+		Channels:           make(map[channel.ChannelID]types.PChannelInfo),
+		Stats:              make(map[channel.ChannelID]channel.PChannelStatsView),
+		AllNodesInfo:       make(map[int64]types.StreamingNodeStatus),
+		ChannelsToNodes:    make(map[types.ChannelID]int64),
+		ExpectedAccessMode: make(map[channel.ChannelID]types.AccessMode),
+	}
+	for _, id := range serverIDs {
+		layout.AllNodesInfo[id] = types.StreamingNodeStatus{
+			StreamingNodeInfo: types.StreamingNodeInfo{
+				ServerID: id,
+			},
+			Metrics: types.StreamingNodeMetrics{
+				WALMetrics: make(map[types.ChannelID]types.WALMetrics),
+			},
+		}
+	}
+	now := time.Now()
+	for c, load := range channels {
+		channelID := newChannelID(c)
+		info := types.PChannelInfo{
+			Name:       c,
+			Term:       1,
+			AccessMode: types.AccessModeRW,
+		}
+		layout.Stats[channelID] = channel.PChannelStatsView{VChannels: make(map[string]int64)}
+		layout.Channels[channelID] = info
+		layout.ExpectedAccessMode[channelID] = types.AccessModeRW
+		if load.node > 0 {
+			layout.ChannelsToNodes[channelID] = load.node
+			layout.AllNodesInfo[load.node].Metrics.WALMetrics[channelID] = types.RWWALMetrics{
+				ChannelInfo:          info,
+				MVCCTimeTick:         tsoutil.ComposeTSByTime(now, 0),
+				RecoveryTimeTick:     tsoutil.ComposeTSByTime(now.Add(-load.backlog), 0),
+				AppendBytesPerSecond: load.throughput,
+			}
+		}
+	}
+	return layout
+}
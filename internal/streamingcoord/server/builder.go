@@ -14,10 +14,11 @@ import (
 )
 
 type ServerBuilder struct {
-	etcdClient     *clientv3.Client
-	metaKV         kv.MetaKv
-	session        sessionutil.SessionInterface
-	mixCoordClient *syncutil.Future[types.MixCoordClient]
+	etcdClient      *clientv3.Client
+	metaKV          kv.MetaKv
+	replicateMetaKV kv.MetaKv
+	session         sessionutil.SessionInterface
+	mixCoordClient  *syncutil.Future[types.MixCoordClient]
 }
 
 func NewServerBuilder() *ServerBuilder {
@@ -34,6 +35,12 @@ func (b *ServerBuilder) WithMetaKV(metaKV kv.MetaKv) *ServerBuilder {
 	return b
 }
 
+// WithReplicateMetaKV sets the meta kv of the replicate meta, the meta kv is used if not set.
+func (b *ServerBuilder) WithReplicateMetaKV(metaKV kv.MetaKv) *ServerBuilder {
+	b.replicateMetaKV = metaKV
+	return b
+}
+
 func (b *ServerBuilder) WithMixCoordClient(mixCoordClient *syncutil.Future[types.MixCoordClient]) *ServerBuilder {
 	b.mixCoordClient = mixCoordClient
 	return b
@@ -45,9 +52,13 @@ func (b *ServerBuilder) WithSession(session sessionutil.SessionInterface) *Serve
 }
 
 func (s *ServerBuilder) Build() *Server {
+	var catalogOpts []streamingcoord.CatalogOption
+	if s.replicateMetaKV != nil {
+		catalogOpts = append(catalogOpts, streamingcoord.OptReplicateMetaKV(s.replicateMetaKV))
+	}
 	resource.Init(
 		resource.OptETCD(s.etcdClient),
-		resource.OptStreamingCatalog(streamingcoord.NewCataLog(s.metaKV)),
+		resource.OptStreamingCatalog(streamingcoord.NewCataLog(s.metaKV, catalogOpts...)),
 		resource.OptMixCoordClient(s.mixCoordClient),
 		resource.OptSession(s.session),
 	)
@@ -110,9 +110,10 @@ func (w *walAdaptorImpl) Metrics() types.WALMetrics {
 	currentMVCC := w.param.MVCCManager.GetMVCCOfVChannel(w.Channel().Name)
 	recoveryMetrics := w.flusher.Metrics()
 	return types.RWWALMetrics{
-		ChannelInfo:      w.Channel(),
-		MVCCTimeTick:     currentMVCC.Timetick,
-		RecoveryTimeTick: recoveryMetrics.RecoveryTimeTick,
+		ChannelInfo:          w.Channel(),
+		MVCCTimeTick:         currentMVCC.Timetick,
+		RecoveryTimeTick:     recoveryMetrics.RecoveryTimeTick,
+		AppendBytesPerSecond: uint64(w.appendRateCounter.Rate()),
 	}
 }
 
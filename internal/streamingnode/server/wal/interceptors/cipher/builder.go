@@ -0,0 +1,20 @@
+package cipher
+
+import (
+	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors"
+)
+
+// NewInterceptorBuilder creates a new cipher interceptor builder.
+func NewInterceptorBuilder() interceptors.InterceptorBuilder {
+	return &interceptorBuilder{}
+}
+
+// interceptorBuilder is the builder for cipher interceptor.
+type interceptorBuilder struct{}
+
+// Build creates a new cipher interceptor.
+func (b *interceptorBuilder) Build(param *interceptors.InterceptorBuildParam) interceptors.Interceptor {
+	return &cipherAppendInterceptor{
+		shardManager: param.ShardManager,
+	}
+}
@@ -0,0 +1,79 @@
+package cipher
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors"
+	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors/shard/shards"
+	"github.com/milvus-io/milvus/internal/util/hookutil"
+	"github.com/milvus-io/milvus/internal/util/streamingutil/status"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
+)
+
+const interceptorName = "cipher"
+
+var _ interceptors.InterceptorWithMetrics = (*cipherAppendInterceptor)(nil)
+
+// cipherAppendInterceptor encrypts the payload of the dml messages before they are written into the wal,
+// so the plaintext of the collection in an encrypted database never reaches the storage of wal backend.
+// The data key of the encryption zone of the database is used, and the encrypted data key is kept in the message properties,
+// the payload is decrypted transparently when the message is read from the wal, even if the root key has been rotated.
+// The messages that are already encrypted by the proxy are kept unchanged.
+type cipherAppendInterceptor struct {
+	shardManager shards.ShardManager
+}
+
+// Name returns the name of the interceptor.
+func (impl *cipherAppendInterceptor) Name() string {
+	return interceptorName
+}
+
+// DoAppend encrypts the message if the collection of the message belongs to an encryption zone.
+func (impl *cipherAppendInterceptor) DoAppend(ctx context.Context, msg message.MutableMessage, appendOp interceptors.Append) (message.MessageID, error) {
+	if err := impl.encryptIfNeeded(msg); err != nil {
+		return nil, err
+	}
+	return appendOp(ctx, msg)
+}
+
+// encryptIfNeeded encrypts the payload of the message in place if the message is not encrypted yet.
+func (impl *cipherAppendInterceptor) encryptIfNeeded(msg message.MutableMessage) error {
+	if !msg.MessageType().CanEnableCipher() || message.IsEncrypted(msg) || !hookutil.IsClusterEncryptionEnabled() {
+		return nil
+	}
+	collectionID := getCollectionID(msg)
+	schema, err := impl.shardManager.GetCollectionSchema(collectionID)
+	if err != nil {
+		// the collection without schema is written by the legacy proxy that encrypts the message by itself,
+		// and the message of non-existent collection will be rejected by the shard interceptor.
+		impl.shardManager.Logger().Debug("skip encrypting message without collection schema", log.FieldMessage(msg), zap.Error(err))
+		return nil
+	}
+	ez := hookutil.GetEzByCollProperties(schema.GetProperties(), collectionID)
+	if ez == nil {
+		return nil
+	}
+	if err := message.EncryptMutableMessage(msg, ez.AsMessageConfig()); err != nil {
+		impl.shardManager.Logger().Warn("failed to encrypt message", log.FieldMessage(msg), zap.Int64("ezID", ez.EzID), zap.Error(err))
+		return status.NewInner("failed to encrypt message of collection %d, %s", collectionID, err.Error())
+	}
+	return nil
+}
+
+// Close the interceptor.
+func (impl *cipherAppendInterceptor) Close() {}
+
+// getCollectionID returns the collection id of the cipher enabled message.
+func getCollectionID(msg message.MutableMessage) int64 {
+	switch msg.MessageType() {
+	case message.MessageTypeInsert:
+		return message.MustAsMutableInsertMessageV1(msg).Header().GetCollectionId()
+	case message.MessageTypeDelete:
+		return message.MustAsMutableDeleteMessageV1(msg).Header().GetCollectionId()
+	default:
+		panic("unreachable: unsupported cipher message type " + msg.MessageType().String())
+	}
+}
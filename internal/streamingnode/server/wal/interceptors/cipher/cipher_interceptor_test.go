@@ -0,0 +1,108 @@
+package cipher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/mocks/streamingnode/server/wal/interceptors/shard/mock_shards"
+	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors"
+	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors/shard/shards"
+	"github.com/milvus-io/milvus/internal/util/hookutil"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
+	"github.com/milvus-io/milvus/pkg/v2/streaming/walimpls/impls/walimplstest"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+func TestCipherInterceptor(t *testing.T) {
+	paramtable.Init()
+	hookutil.InitTestCipher()
+	message.RegisterCipher(hookutil.GetCipher())
+
+	shardManager := mock_shards.NewMockShardManager(t)
+	shardManager.EXPECT().Logger().Return(log.With()).Maybe()
+	shardManager.EXPECT().GetCollectionSchema(mock.Anything).RunAndReturn(func(collectionID int64) (*schemapb.CollectionSchema, error) {
+		switch collectionID {
+		case 1:
+			return &schemapb.CollectionSchema{
+				Properties: []*commonpb.KeyValuePair{{Key: common.EncryptionEzIDKey, Value: "100"}},
+			}, nil
+		case 2:
+			return &schemapb.CollectionSchema{}, nil
+		default:
+			return nil, shards.ErrCollectionNotFound
+		}
+	})
+	interceptor := NewInterceptorBuilder().Build(&interceptors.InterceptorBuildParam{
+		ShardManager: shardManager,
+	})
+	defer interceptor.Close()
+	assert.Equal(t, "cipher", interceptor.(interceptors.InterceptorWithMetrics).Name())
+
+	var appended message.MutableMessage
+	appendOp := func(ctx context.Context, msg message.MutableMessage) (message.MessageID, error) {
+		appended = msg
+		return walimplstest.NewTestMessageID(1), nil
+	}
+
+	// the insert message of the collection in an encryption zone is encrypted.
+	msg := newInsertMessage(1)
+	plaintext := msg.Payload()
+	_, err := interceptor.DoAppend(context.Background(), msg, appendOp)
+	assert.NoError(t, err)
+	assert.True(t, message.IsEncrypted(appended))
+	assert.NotEqual(t, plaintext, appended.IntoMessageProto().GetPayload())
+	assert.Equal(t, plaintext, appended.Payload())
+
+	// the encrypted message is not encrypted again.
+	encrypted := appended.IntoMessageProto().GetPayload()
+	_, err = interceptor.DoAppend(context.Background(), appended, appendOp)
+	assert.NoError(t, err)
+	assert.Equal(t, encrypted, appended.IntoMessageProto().GetPayload())
+
+	// the delete message is encrypted too.
+	deleteMsg := message.NewDeleteMessageBuilderV1().
+		WithVChannel("v1").
+		WithHeader(&message.DeleteMessageHeader{CollectionId: 1}).
+		WithBody(&msgpb.DeleteRequest{}).
+		MustBuildMutable()
+	_, err = interceptor.DoAppend(context.Background(), deleteMsg, appendOp)
+	assert.NoError(t, err)
+	assert.True(t, message.IsEncrypted(appended))
+
+	// the collection without encryption zone or schema is not encrypted.
+	for _, collectionID := range []int64{2, 3} {
+		msg := newInsertMessage(collectionID)
+		_, err = interceptor.DoAppend(context.Background(), msg, appendOp)
+		assert.NoError(t, err)
+		assert.False(t, message.IsEncrypted(appended))
+	}
+
+	// the message type that cannot enable cipher is skipped.
+	flushMsg := message.NewManualFlushMessageBuilderV2().
+		WithVChannel("v1").
+		WithHeader(&message.ManualFlushMessageHeader{CollectionId: 1}).
+		WithBody(&message.ManualFlushMessageBody{}).
+		MustBuildMutable()
+	_, err = interceptor.DoAppend(context.Background(), flushMsg, appendOp)
+	assert.NoError(t, err)
+	assert.False(t, message.IsEncrypted(appended))
+}
+
+func newInsertMessage(collectionID int64) message.MutableMessage {
+	return message.NewInsertMessageBuilderV1().
+		WithVChannel("v1").
+		WithHeader(&message.InsertMessageHeader{
+			CollectionId: collectionID,
+			Partitions:   []*message.PartitionSegmentAssignment{{PartitionId: 1}},
+		}).
+		WithBody(&msgpb.InsertRequest{ShardName: "v1", CollectionID: collectionID}).
+		MustBuildMutable()
+}
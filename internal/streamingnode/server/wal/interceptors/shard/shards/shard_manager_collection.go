@@ -4,6 +4,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"go.uber.org/zap"
 
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors/shard/policy"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/proto/streamingpb"
@@ -193,6 +194,21 @@ func (m *shardManagerImpl) CheckIfCollectionSchemaVersionMatch(collectionID int6
 	return m.checkIfCollectionSchemaVersionMatch(collectionID, schemaVersion)
 }
 
+// GetCollectionSchema returns the latest schema of the collection on the shard.
+func (m *shardManagerImpl) GetCollectionSchema(collectionID int64) (*schemapb.CollectionSchema, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	collectionInfo, ok := m.collections[collectionID]
+	if !ok {
+		return nil, ErrCollectionNotFound
+	}
+	if collectionInfo.Schema == nil || collectionInfo.Schema.GetSchema() == nil {
+		return nil, ErrCollectionSchemaNotFound
+	}
+	return collectionInfo.Schema.GetSchema(), nil
+}
+
 func (m *shardManagerImpl) checkIfCollectionSchemaVersionMatch(collectionID int64, schemaVersion int32) (int32, error) {
 	collectionInfo, ok := m.collections[collectionID]
 	if !ok {
@@ -1,6 +1,7 @@
 package shards
 
 import (
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors/shard/utils"
 	"github.com/milvus-io/milvus/pkg/v2/log"
 	"github.com/milvus-io/milvus/pkg/v2/streaming/util/message"
@@ -55,5 +56,8 @@ type ShardManager interface {
 
 	CheckIfCollectionSchemaVersionMatch(collectionID int64, schemaVersion int32) (int32, error)
 
+	// GetCollectionSchema returns the latest schema of the collection on the shard.
+	GetCollectionSchema(collectionID int64) (*schemapb.CollectionSchema, error)
+
 	Close()
 }
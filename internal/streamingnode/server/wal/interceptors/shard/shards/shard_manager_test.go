@@ -359,6 +359,12 @@ func TestShardManagerSchemaVersionCheck(t *testing.T) {
 	assert.ErrorIs(t, err, ErrCollectionSchemaVersionNotMatch)
 	assert.Equal(t, int32(1), ver)
 
+	gotSchema, err := m.GetCollectionSchema(100)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_schema_collection", gotSchema.GetName())
+	_, err = m.GetCollectionSchema(999)
+	assert.ErrorIs(t, err, ErrCollectionNotFound)
+
 	// Test 3: Create collection without schema (legacy), then check version
 	createMsgNoSchema := message.NewCreateCollectionMessageBuilderV1().
 		WithVChannel("v_no_schema").
@@ -376,6 +382,8 @@ func TestShardManagerSchemaVersionCheck(t *testing.T) {
 	// collection exists but has no schema
 	_, err = m.CheckIfCollectionSchemaVersionMatch(101, 1)
 	assert.ErrorIs(t, err, ErrCollectionSchemaNotFound)
+	_, err = m.GetCollectionSchema(101)
+	assert.ErrorIs(t, err, ErrCollectionSchemaNotFound)
 
 	// backward-compat: old proxy sends schemaVersion=0 against a schema-less collection,
 	// must succeed and return the legacy version 0 instead of ErrCollectionSchemaNotFound.
@@ -9,6 +9,7 @@ import (
 	"github.com/milvus-io/milvus/internal/streamingnode/server/wal"
 	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/adaptor"
 	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors"
+	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors/cipher"
 	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors/lock"
 	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors/redo"
 	"github.com/milvus-io/milvus/internal/streamingnode/server/wal/interceptors/replicate"
@@ -34,6 +35,8 @@ func OpenManager() (Manager, error) {
 			replicate.NewInterceptorBuilder(),
 			timetick.NewInterceptorBuilder(),
 			shard.NewInterceptorBuilder(),
+			// the cipher interceptor should be the last one to keep the plaintext visible to the other interceptors.
+			cipher.NewInterceptorBuilder(),
 		},
 	)
 	return newManager(opener), nil
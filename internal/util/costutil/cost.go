@@ -0,0 +1,195 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package costutil accounts the resources consumed by a search or query request.
+// Query nodes accumulate the counters of the segments a request is executed on into the cost aggregation of the result,
+// the proxy sums them up and returns them to the user in the extra info of the response status.
+package costutil
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+)
+
+// The keys of the response status extra info that carry the cost of a request,
+// they are also exported as `$cost_*` variables of the access log.
+const (
+	SegmentRowsKey          = "cost_segment_rows"
+	SegmentsTouchedKey      = "cost_segments_touched"
+	CacheBytesKey           = "cost_cache_bytes"
+	DiskBytesKey            = "cost_disk_bytes"
+	DistanceComputationsKey = "cost_distance_computations"
+	SegmentTimeKey          = "cost_segment_time_us"
+)
+
+// Keys are all the cost keys in the order they are reported.
+var Keys = []string{
+	SegmentRowsKey,
+	SegmentsTouchedKey,
+	CacheBytesKey,
+	DiskBytesKey,
+	DistanceComputationsKey,
+	SegmentTimeKey,
+}
+
+// Cost is the resources consumed by a request.
+type Cost struct {
+	// SegmentRows is the number of rows of the segments the request is executed on,
+	// it's not the rows passed the filters or reached by the indexes.
+	SegmentRows int64
+	// SegmentsTouched is the number of segments the request is executed on.
+	SegmentsTouched int64
+	// CacheBytes is the bytes served from the local cache of the query nodes.
+	CacheBytes int64
+	// DiskBytes is the bytes missing in the local cache and loaded from disk or remote storage.
+	DiskBytes int64
+	// DistanceComputations is the number of vector distance computations of the brute force search,
+	// the computations inside the vector indexes are not counted as the indexes don't expose them.
+	DistanceComputations int64
+	// SegmentTimeUs is the wall time in microseconds the query nodes spent on the segments, including
+	// the waits for lazy loading and io, it's not the cpu time. Segments are searched concurrently
+	// so it's usually larger than the latency of the request.
+	SegmentTimeUs int64
+}
+
+// AddAggregation adds the counters carried in the cost aggregation returned by a query node.
+func (c *Cost) AddAggregation(agg *internalpb.CostAggregation) {
+	c.SegmentRows += agg.GetSegmentRows()
+	c.SegmentsTouched += agg.GetSegmentsTouched()
+	c.DistanceComputations += agg.GetDistanceComputations()
+	c.SegmentTimeUs += agg.GetSegmentTimeUs()
+}
+
+// SetScannedBytes sets the bytes read from cache or disk by the scanned bytes reported by segcore.
+func (c *Cost) SetScannedBytes(scannedRemoteBytes int64, scannedTotalBytes int64) {
+	c.DiskBytes = scannedRemoteBytes
+	c.CacheBytes = max(scannedTotalBytes-scannedRemoteBytes, 0)
+}
+
+// Get returns the value of the cost key.
+func (c *Cost) Get(key string) (int64, bool) {
+	switch key {
+	case SegmentRowsKey:
+		return c.SegmentRows, true
+	case SegmentsTouchedKey:
+		return c.SegmentsTouched, true
+	case CacheBytesKey:
+		return c.CacheBytes, true
+	case DiskBytesKey:
+		return c.DiskBytes, true
+	case DistanceComputationsKey:
+		return c.DistanceComputations, true
+	case SegmentTimeKey:
+		return c.SegmentTimeUs, true
+	default:
+		return 0, false
+	}
+}
+
+// Attach sets the cost into the extra info of the status.
+func (c *Cost) Attach(status *commonpb.Status) {
+	if status == nil {
+		return
+	}
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+	}
+	for _, key := range Keys {
+		value, _ := c.Get(key)
+		status.ExtraInfo[key] = strconv.FormatInt(value, 10)
+	}
+}
+
+// Counter accumulates the counters of the segments a request is executed on in a query node.
+// All methods are safe to call concurrently and on a nil counter, which means the request isn't accounted.
+type Counter struct {
+	segmentRows          atomic.Int64
+	segmentsTouched      atomic.Int64
+	distanceComputations atomic.Int64
+	segmentTime          atomic.Int64
+}
+
+// NewCounter creates a counter.
+func NewCounter() *Counter {
+	return &Counter{}
+}
+
+type counterKey struct{}
+
+// WithCounter attaches the counter to the context.
+func WithCounter(ctx context.Context, c *Counter) context.Context {
+	if c == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, counterKey{}, c)
+}
+
+// FromContext returns the counter attached to the context, nil if the request isn't accounted.
+func FromContext(ctx context.Context) *Counter {
+	c, _ := ctx.Value(counterKey{}).(*Counter)
+	return c
+}
+
+// AddSegment records a segment of the given rows the request is executed on.
+func (c *Counter) AddSegment(rows int64, distanceComputations int64, elapsed time.Duration) {
+	if c == nil {
+		return
+	}
+	c.segmentRows.Add(rows)
+	c.segmentsTouched.Add(1)
+	c.distanceComputations.Add(distanceComputations)
+	c.segmentTime.Add(elapsed.Microseconds())
+}
+
+// Fill sets the accumulated counters into the cost aggregation of the result.
+// A task may execute several requests merged together, the distance computations and segment time
+// are split among them by the share of nq, while the rows and segments are scanned by all of them.
+func (c *Counter) Fill(agg *internalpb.CostAggregation, nq int64, totalNq int64) {
+	if c == nil || agg == nil {
+		return
+	}
+	share := func(v int64) int64 {
+		if totalNq <= 0 || nq >= totalNq {
+			return v
+		}
+		return v * nq / totalNq
+	}
+	agg.SegmentRows = c.segmentRows.Load()
+	agg.SegmentsTouched = c.segmentsTouched.Load()
+	agg.DistanceComputations = share(c.distanceComputations.Load())
+	agg.SegmentTimeUs = share(c.segmentTime.Load())
+}
+
+// MergeAggregations sums the counters of the cost aggregations into dst,
+// dst may be one of the merged aggregations.
+func MergeAggregations(dst *internalpb.CostAggregation, aggs []*internalpb.CostAggregation) {
+	if dst == nil {
+		return
+	}
+	cost := Cost{}
+	for _, agg := range aggs {
+		cost.AddAggregation(agg)
+	}
+	dst.SegmentRows = cost.SegmentRows
+	dst.SegmentsTouched = cost.SegmentsTouched
+	dst.DistanceComputations = cost.DistanceComputations
+	dst.SegmentTimeUs = cost.SegmentTimeUs
+}
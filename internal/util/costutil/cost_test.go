@@ -0,0 +1,120 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package costutil
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+func TestCounter(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+	assert.Equal(t, ctx, WithCounter(ctx, nil))
+
+	// a nil counter is a no-op
+	var nilCounter *Counter
+	nilCounter.AddSegment(100, 100, time.Millisecond)
+	nilCounter.Fill(&internalpb.CostAggregation{}, 1, 1)
+
+	counter := NewCounter()
+	ctx = WithCounter(ctx, counter)
+	assert.Same(t, counter, FromContext(ctx))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			FromContext(ctx).AddSegment(1000, 4000, 10*time.Microsecond)
+		}()
+	}
+	wg.Wait()
+
+	agg := &internalpb.CostAggregation{ServiceTime: 5}
+	counter.Fill(agg, 4, 4)
+	assert.Equal(t, int64(5), agg.GetServiceTime())
+	assert.Equal(t, int64(4000), agg.GetSegmentRows())
+	assert.Equal(t, int64(4), agg.GetSegmentsTouched())
+	assert.Equal(t, int64(16000), agg.GetDistanceComputations())
+	assert.Equal(t, int64(40), agg.GetSegmentTimeUs())
+
+	// the request merged with others takes its share of nq
+	agg = &internalpb.CostAggregation{}
+	counter.Fill(agg, 1, 4)
+	assert.Equal(t, int64(4000), agg.GetSegmentRows())
+	assert.Equal(t, int64(4), agg.GetSegmentsTouched())
+	assert.Equal(t, int64(4000), agg.GetDistanceComputations())
+	assert.Equal(t, int64(10), agg.GetSegmentTimeUs())
+}
+
+func TestMergeAggregations(t *testing.T) {
+	aggs := []*internalpb.CostAggregation{
+		{ResponseTime: 10, SegmentRows: 100, SegmentsTouched: 1, DistanceComputations: 100, SegmentTimeUs: 7},
+		nil,
+		{ResponseTime: 20, SegmentRows: 200, SegmentsTouched: 2, SegmentTimeUs: 3},
+	}
+	// merging into one of the merged aggregations doesn't count it twice
+	MergeAggregations(aggs[2], aggs)
+	assert.Equal(t, int64(20), aggs[2].GetResponseTime())
+	assert.Equal(t, int64(300), aggs[2].GetSegmentRows())
+	assert.Equal(t, int64(3), aggs[2].GetSegmentsTouched())
+	assert.Equal(t, int64(100), aggs[2].GetDistanceComputations())
+	assert.Equal(t, int64(10), aggs[2].GetSegmentTimeUs())
+
+	MergeAggregations(nil, aggs)
+}
+
+func TestCost(t *testing.T) {
+	cost := Cost{}
+	cost.AddAggregation(&internalpb.CostAggregation{SegmentRows: 100, SegmentsTouched: 1, DistanceComputations: 800, SegmentTimeUs: 20})
+	cost.AddAggregation(&internalpb.CostAggregation{SegmentRows: 50, SegmentsTouched: 1, SegmentTimeUs: 5})
+	cost.AddAggregation(nil)
+	cost.SetScannedBytes(1024, 4096)
+
+	assert.Equal(t, Cost{
+		SegmentRows:          150,
+		SegmentsTouched:      2,
+		CacheBytes:           3072,
+		DiskBytes:            1024,
+		DistanceComputations: 800,
+		SegmentTimeUs:        25,
+	}, cost)
+
+	_, ok := cost.Get("cost_unknown")
+	assert.False(t, ok)
+
+	status := merr.Success()
+	cost.Attach(status)
+	assert.Equal(t, map[string]string{
+		SegmentRowsKey:          "150",
+		SegmentsTouchedKey:      "2",
+		CacheBytesKey:           "3072",
+		DiskBytesKey:            "1024",
+		DistanceComputationsKey: "800",
+		SegmentTimeKey:          "25",
+	}, status.GetExtraInfo())
+
+	cost.Attach(nil)
+}
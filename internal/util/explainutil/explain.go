@@ -0,0 +1,312 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package explainutil collects how a search or query request is planned and executed,
+// which is returned to the user when the request is issued with the explain or profile mode.
+package explainutil
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/pkg/v2/util/funcutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+const (
+	// ExplainKey is the search/query param key to explain the request.
+	ExplainKey = "explain"
+	// ProfileKey is the search/query param key to explain the request with the time spent in every stage.
+	ProfileKey = "profile"
+	// ExtraInfoKey is the key of the status extra info that carries the marshaled explanation.
+	ExtraInfoKey = "explain"
+)
+
+// Mode is the explain mode of a request.
+type Mode string
+
+const (
+	ModeNone    Mode = ""
+	ModeExplain Mode = "explain"
+	ModeProfile Mode = "profile"
+)
+
+// ParseMode parses the explain mode from the search/query params of the request,
+// the profile mode implies the explain mode.
+func ParseMode(params []*commonpb.KeyValuePair) (Mode, error) {
+	mode := ModeNone
+	for _, kv := range []struct {
+		key  string
+		mode Mode
+	}{{ExplainKey, ModeExplain}, {ProfileKey, ModeProfile}} {
+		value, ok := funcutil.TryGetAttrByKeyFromRepeatedKV(kv.key, params)
+		if !ok {
+			continue
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return ModeNone, merr.WrapErrParameterInvalidMsg("%s should be true or false, but got %s", kv.key, value)
+		}
+		if enabled {
+			mode = kv.mode
+		}
+	}
+	return mode, nil
+}
+
+// Explanation is the explanation of a search or query request.
+type Explanation struct {
+	Mode    Mode                `json:"mode"`
+	Exprs   []*ExprExplanation  `json:"exprs,omitempty"`
+	Indexes []*FieldIndex       `json:"indexes,omitempty"`
+	Shards  []*ShardExplanation `json:"shards,omitempty"`
+	Stages  []*Stage            `json:"stages,omitempty"`
+}
+
+// ExprExplanation is the filter expression before and after it's rewritten by the optimizer.
+type ExprExplanation struct {
+	Expr      string `json:"expr"`
+	Parsed    any    `json:"parsed"`
+	Rewritten any    `json:"rewritten"`
+}
+
+// FieldIndex is the index used by a field referenced in the request,
+// the index name is empty if the field is not indexed and scanned by brute force.
+type FieldIndex struct {
+	FieldID   int64  `json:"field_id"`
+	FieldName string `json:"field_name"`
+	Usage     string `json:"usage"`
+	IndexName string `json:"index_name,omitempty"`
+	IndexType string `json:"index_type,omitempty"`
+}
+
+// ShardExplanation is how the shard delegator of a vchannel executes the request.
+type ShardExplanation struct {
+	Channel         string           `json:"channel"`
+	NodeID          int64            `json:"node_id"`
+	SealedSegments  int              `json:"sealed_segments"`
+	GrowingSegments int              `json:"growing_segments"`
+	PrunedSegments  []*PrunedSegment `json:"pruned_segments,omitempty"`
+	Fanout          []*WorkerFanout  `json:"fanout,omitempty"`
+	Stages          []*Stage         `json:"stages,omitempty"`
+}
+
+// PrunedSegment is a sealed segment skipped by the shard delegator.
+type PrunedSegment struct {
+	SegmentID int64  `json:"segment_id"`
+	Reason    string `json:"reason"`
+}
+
+// WorkerFanout is the sub request sent by the shard delegator to a worker,
+// the durations are only filled in the profile mode.
+type WorkerFanout struct {
+	NodeID        int64   `json:"node_id"`
+	Scope         string  `json:"scope"`
+	Segments      []int64 `json:"segments"`
+	ElapsedMs     float64 `json:"elapsed_ms,omitempty"`
+	ResponseMs    int64   `json:"response_ms,omitempty"`
+	ServiceTimeMs int64   `json:"service_time_ms,omitempty"`
+}
+
+// Stage is the time spent in a stage of the request, only filled in the profile mode.
+type Stage struct {
+	Name       string  `json:"name"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// Collector collects the explanation of a request.
+// All methods are safe to call concurrently and on a nil collector, which means the request isn't explained.
+type Collector struct {
+	mu          sync.Mutex
+	explanation Explanation
+	shard       *ShardExplanation
+}
+
+// NewCollector creates a collector for the request explained in the given mode,
+// nil is returned if the mode is none.
+func NewCollector(mode Mode) *Collector {
+	if mode == ModeNone {
+		return nil
+	}
+	return &Collector{explanation: Explanation{Mode: mode}}
+}
+
+// NewShardCollector creates a collector for the part of the request executed by the shard delegator of the channel.
+func NewShardCollector(mode Mode, channel string, nodeID int64) *Collector {
+	c := NewCollector(mode)
+	if c == nil {
+		return nil
+	}
+	c.shard = &ShardExplanation{Channel: channel, NodeID: nodeID}
+	c.explanation.Shards = []*ShardExplanation{c.shard}
+	return c
+}
+
+type collectorKey struct{}
+
+// WithCollector attaches the collector to the context.
+func WithCollector(ctx context.Context, c *Collector) context.Context {
+	if c == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, collectorKey{}, c)
+}
+
+// FromContext returns the collector attached to the context, nil if the request isn't explained.
+func FromContext(ctx context.Context) *Collector {
+	c, _ := ctx.Value(collectorKey{}).(*Collector)
+	return c
+}
+
+// Mode returns the explain mode of the collector.
+func (c *Collector) Mode() Mode {
+	if c == nil {
+		return ModeNone
+	}
+	return c.explanation.Mode
+}
+
+// AddExpr records the filter expression of the request.
+func (c *Collector) AddExpr(expr *ExprExplanation) {
+	if c == nil || expr == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.explanation.Exprs = append(c.explanation.Exprs, expr)
+}
+
+// AddIndexes records the indexes used by the fields referenced in the request.
+func (c *Collector) AddIndexes(indexes ...*FieldIndex) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.explanation.Indexes = append(c.explanation.Indexes, indexes...)
+}
+
+// RecordStage records the time spent in the stage, it's a no-op if the request isn't profiled.
+// The stage is recorded into the shard explanation if the collector is created for a shard.
+func (c *Collector) RecordStage(name string, d time.Duration) {
+	if c == nil || c.explanation.Mode != ModeProfile {
+		return
+	}
+	stage := &Stage{Name: name, DurationMs: float64(d.Microseconds()) / 1000.0}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shard != nil {
+		c.shard.Stages = append(c.shard.Stages, stage)
+		return
+	}
+	c.explanation.Stages = append(c.explanation.Stages, stage)
+}
+
+// SetSegments records the number of segments readable by the shard delegator.
+func (c *Collector) SetSegments(sealed int, growing int) {
+	if c == nil || c.shard == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shard.SealedSegments = sealed
+	c.shard.GrowingSegments = growing
+}
+
+// AddPrunedSegments records the sealed segments skipped by the shard delegator for the reason.
+func (c *Collector) AddPrunedSegments(reason string, segmentIDs ...int64) {
+	if c == nil || c.shard == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, segmentID := range segmentIDs {
+		c.shard.PrunedSegments = append(c.shard.PrunedSegments, &PrunedSegment{SegmentID: segmentID, Reason: reason})
+	}
+}
+
+// AddFanout records a sub request sent by the shard delegator to a worker.
+func (c *Collector) AddFanout(fanout *WorkerFanout) {
+	if c == nil || c.shard == nil {
+		return
+	}
+	if c.explanation.Mode != ModeProfile {
+		fanout.ElapsedMs, fanout.ResponseMs, fanout.ServiceTimeMs = 0, 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shard.Fanout = append(c.shard.Fanout, fanout)
+}
+
+// Merge merges the shard explanations carried in the status returned by the query node.
+func (c *Collector) Merge(status *commonpb.Status) error {
+	if c == nil {
+		return nil
+	}
+	data, ok := status.GetExtraInfo()[ExtraInfoKey]
+	if !ok {
+		return nil
+	}
+	explanation := &Explanation{}
+	if err := json.Unmarshal([]byte(data), explanation); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.explanation.Shards = append(c.explanation.Shards, explanation.Shards...)
+	return nil
+}
+
+// Explanation returns a snapshot of the collected explanation.
+func (c *Collector) Explanation() Explanation {
+	if c == nil {
+		return Explanation{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.explanation
+}
+
+// Marshal marshals the collected explanation into json.
+func (c *Collector) Marshal() (string, error) {
+	explanation := c.Explanation()
+	data, err := json.Marshal(explanation)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Attach sets the marshaled explanation into the extra info of the status.
+func (c *Collector) Attach(status *commonpb.Status) error {
+	if c == nil || status == nil {
+		return nil
+	}
+	data, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+	if status.ExtraInfo == nil {
+		status.ExtraInfo = make(map[string]string)
+	}
+	status.ExtraInfo[ExtraInfoKey] = data
+	return nil
+}
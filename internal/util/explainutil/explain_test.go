@@ -0,0 +1,110 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package explainutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ModeNone, mode)
+
+	mode, err = ParseMode([]*commonpb.KeyValuePair{{Key: ExplainKey, Value: "true"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ModeExplain, mode)
+
+	mode, err = ParseMode([]*commonpb.KeyValuePair{{Key: ExplainKey, Value: "false"}, {Key: ProfileKey, Value: "True"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ModeProfile, mode)
+
+	mode, err = ParseMode([]*commonpb.KeyValuePair{{Key: ExplainKey, Value: "false"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ModeNone, mode)
+
+	_, err = ParseMode([]*commonpb.KeyValuePair{{Key: ProfileKey, Value: "yes please"}})
+	assert.ErrorIs(t, err, merr.ErrParameterInvalid)
+}
+
+func TestNilCollector(t *testing.T) {
+	c := NewCollector(ModeNone)
+	assert.Nil(t, c)
+	assert.Nil(t, NewShardCollector(ModeNone, "ch", 1))
+	assert.Nil(t, FromContext(WithCollector(context.Background(), c)))
+
+	// all methods are no-op on the nil collector.
+	assert.Equal(t, ModeNone, c.Mode())
+	c.AddExpr(&ExprExplanation{Expr: "a > 1"})
+	c.AddIndexes(&FieldIndex{FieldID: 100})
+	c.RecordStage("reduce", time.Second)
+	c.SetSegments(1, 1)
+	c.AddPrunedSegments("pk_filter", 1)
+	c.AddFanout(&WorkerFanout{NodeID: 1})
+	assert.NoError(t, c.Merge(&commonpb.Status{}))
+	status := &commonpb.Status{}
+	assert.NoError(t, c.Attach(status))
+	assert.Nil(t, status.GetExtraInfo())
+}
+
+func TestCollector(t *testing.T) {
+	// the shard collector on the query node.
+	shard := NewShardCollector(ModeProfile, "ch1", 2)
+	ctx := WithCollector(context.Background(), shard)
+	assert.Equal(t, shard, FromContext(ctx))
+	shard.SetSegments(3, 1)
+	shard.AddPrunedSegments("partition_stats", 10, 11)
+	shard.AddFanout(&WorkerFanout{NodeID: 3, Scope: "Historical", Segments: []int64{12}, ElapsedMs: 1.5, ServiceTimeMs: 1})
+	shard.RecordStage("wait_tsafe", time.Millisecond)
+	status := merr.Success()
+	assert.NoError(t, shard.Attach(status))
+
+	// the request collector on the proxy merges the shard explanation.
+	c := NewCollector(ModeExplain)
+	assert.Equal(t, ModeExplain, c.Mode())
+	c.AddExpr(&ExprExplanation{Expr: "a > 1"})
+	c.AddIndexes(&FieldIndex{FieldID: 100, FieldName: "a", Usage: "filter"})
+	// stages are only recorded in the profile mode.
+	c.RecordStage("reduce", time.Millisecond)
+	assert.NoError(t, c.Merge(status))
+	assert.NoError(t, c.Merge(merr.Success()))
+	assert.Error(t, c.Merge(&commonpb.Status{ExtraInfo: map[string]string{ExtraInfoKey: "{"}}))
+
+	explanation := c.Explanation()
+	assert.Len(t, explanation.Exprs, 1)
+	assert.Len(t, explanation.Indexes, 1)
+	assert.Empty(t, explanation.Stages)
+	assert.Len(t, explanation.Shards, 1)
+	assert.Equal(t, "ch1", explanation.Shards[0].Channel)
+	assert.Equal(t, 3, explanation.Shards[0].SealedSegments)
+	assert.Len(t, explanation.Shards[0].PrunedSegments, 2)
+	assert.Equal(t, 1.5, explanation.Shards[0].Fanout[0].ElapsedMs)
+	assert.Len(t, explanation.Shards[0].Stages, 1)
+
+	// the durations of fan-out are dropped if the request isn't profiled.
+	shard = NewShardCollector(ModeExplain, "ch2", 2)
+	shard.AddFanout(&WorkerFanout{NodeID: 3, ElapsedMs: 1.5, ServiceTimeMs: 1})
+	assert.Zero(t, shard.Explanation().Shards[0].Fanout[0].ElapsedMs)
+	assert.Zero(t, shard.Explanation().Shards[0].Fanout[0].ServiceTimeMs)
+}
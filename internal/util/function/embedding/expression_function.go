@@ -0,0 +1,109 @@
+/*
+ * # Licensed to the LF AI & Data foundation under one
+ * # or more contributor license agreements. See the NOTICE file
+ * # distributed with this work for additional information
+ * # regarding copyright ownership. The ASF licenses this file
+ * # to you under the Apache License, Version 2.0 (the
+ * # "License"); you may not use this file except in compliance
+ * # with the License. You may obtain a copy of the License at
+ * #
+ * #     http://www.apache.org/licenses/LICENSE-2.0
+ * #
+ * # Unless required by applicable law or agreed to in writing, software
+ * # distributed under the License is distributed on an "AS IS" BASIS,
+ * # WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * # See the License for the specific language governing permissions and
+ * # limitations under the License.
+ */
+
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/internal/util/function"
+)
+
+const expressionProvider string = "expression"
+
+// ExpressionFunction generates the output scalar field from the expression of the input fields at write time,
+// it doesn't call any external service.
+type ExpressionFunction struct {
+	runner         *function.ExpressionFunctionRunner
+	collectionName string
+}
+
+func NewExpressionFunction(coll *schemapb.CollectionSchema, fSchema *schemapb.FunctionSchema) (*ExpressionFunction, error) {
+	runner, err := function.NewExpressionFunctionRunner(coll, fSchema)
+	if err != nil {
+		return nil, err
+	}
+	return &ExpressionFunction{
+		runner:         runner,
+		collectionName: coll.GetName(),
+	}, nil
+}
+
+func (f *ExpressionFunction) GetSchema() *schemapb.FunctionSchema {
+	return f.runner.GetSchema()
+}
+
+func (f *ExpressionFunction) GetOutputFields() []*schemapb.FieldSchema {
+	return f.runner.GetOutputFields()
+}
+
+func (f *ExpressionFunction) GetCollectionName() string {
+	return f.collectionName
+}
+
+func (f *ExpressionFunction) GetFunctionTypeName() string {
+	return function.ExpressionFunctionName
+}
+
+func (f *ExpressionFunction) GetFunctionName() string {
+	return f.runner.GetSchema().GetName()
+}
+
+func (f *ExpressionFunction) GetFunctionProvider() string {
+	return expressionProvider
+}
+
+// Check does nothing since the expression is already compiled when the function is created.
+func (f *ExpressionFunction) Check(ctx context.Context) error {
+	return nil
+}
+
+func (f *ExpressionFunction) MaxBatch() int {
+	return math.MaxInt
+}
+
+func (f *ExpressionFunction) ProcessInsert(ctx context.Context, inputs []*schemapb.FieldData) ([]*schemapb.FieldData, error) {
+	output, err := f.runner.RunFieldsData(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return []*schemapb.FieldData{output}, nil
+}
+
+func (f *ExpressionFunction) ProcessSearch(ctx context.Context, placeholderGroup *commonpb.PlaceholderGroup) (*commonpb.PlaceholderGroup, error) {
+	return nil, fmt.Errorf("expression function %s doesn't support search", f.GetFunctionName())
+}
+
+func (f *ExpressionFunction) ProcessBulkInsert(ctx context.Context, inputs []storage.FieldData) (map[storage.FieldID]storage.FieldData, error) {
+	args := make([]any, 0, len(inputs))
+	for _, input := range inputs {
+		args = append(args, input)
+	}
+	outputs, err := f.runner.BatchRun(args...)
+	if err != nil {
+		return nil, err
+	}
+	return map[storage.FieldID]storage.FieldData{
+		f.runner.GetOutputFields()[0].GetFieldID(): outputs[0].(storage.FieldData),
+	}, nil
+}
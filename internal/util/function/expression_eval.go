@@ -0,0 +1,944 @@
+/*
+ * # Licensed to the LF AI & Data foundation under one
+ * # or more contributor license agreements. See the NOTICE file
+ * # distributed with this work for additional information
+ * # regarding copyright ownership. The ASF licenses this file
+ * # to you under the Apache License, Version 2.0 (the
+ * # "License"); you may not use this file except in compliance
+ * # with the License. You may obtain a copy of the License at
+ * #
+ * #     http://www.apache.org/licenses/LICENSE-2.0
+ * #
+ * # Unless required by applicable law or agreed to in writing, software
+ * # distributed under the License is distributed on an "AS IS" BASIS,
+ * # WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * # See the License for the specific language governing permissions and
+ * # limitations under the License.
+ */
+
+package function
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/proto/planpb"
+)
+
+// kindDynamic is the kind of the values read by json paths, it's only known when the row is evaluated.
+const kindDynamic = schemapb.DataType_None
+
+// exprEvaluator evaluates a parsed expression row by row.
+// The values are nil for null, bool, int64, float64 or string.
+// The columns of the expression refer to the input fields by position,
+// the field id of the i-th input field is common.StartOfUserFieldID + i.
+type exprEvaluator struct {
+	expr   *planpb.Expr
+	fields []*schemapb.FieldSchema
+	kind   schemapb.DataType
+}
+
+func newExprEvaluator(expr *planpb.Expr, fields []*schemapb.FieldSchema) (*exprEvaluator, error) {
+	e := &exprEvaluator{expr: expr, fields: fields}
+	kind, err := e.check(expr)
+	if err != nil {
+		return nil, err
+	}
+	e.kind = kind
+	return e, nil
+}
+
+func (e *exprEvaluator) field(info *planpb.ColumnInfo) (*schemapb.FieldSchema, error) {
+	idx := info.GetFieldId() - common.StartOfUserFieldID
+	if idx < 0 || idx >= int64(len(e.fields)) {
+		return nil, fmt.Errorf("field %d is not an input field of the expression", info.GetFieldId())
+	}
+	return e.fields[idx], nil
+}
+
+func scalarKind(dataType schemapb.DataType) (schemapb.DataType, bool) {
+	switch dataType {
+	case schemapb.DataType_Bool:
+		return schemapb.DataType_Bool, true
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32, schemapb.DataType_Int64, schemapb.DataType_Timestamptz:
+		return schemapb.DataType_Int64, true
+	case schemapb.DataType_Float, schemapb.DataType_Double:
+		return schemapb.DataType_Double, true
+	case schemapb.DataType_VarChar, schemapb.DataType_String:
+		return schemapb.DataType_VarChar, true
+	default:
+		return schemapb.DataType_None, false
+	}
+}
+
+func isNumericKind(kind schemapb.DataType) bool {
+	return kind == schemapb.DataType_Int64 || kind == schemapb.DataType_Double || kind == kindDynamic
+}
+
+func isStringKind(kind schemapb.DataType) bool {
+	return kind == schemapb.DataType_VarChar || kind == kindDynamic
+}
+
+// checkColumn returns the kind of the column value, json fields must be accessed by a path
+// and array fields by an index.
+func (e *exprEvaluator) checkColumn(info *planpb.ColumnInfo) (schemapb.DataType, error) {
+	field, err := e.field(info)
+	if err != nil {
+		return 0, err
+	}
+	switch field.GetDataType() {
+	case schemapb.DataType_JSON:
+		if len(info.GetNestedPath()) == 0 {
+			return 0, fmt.Errorf("json field %s must be accessed by a path in expression", field.GetName())
+		}
+		return kindDynamic, nil
+	case schemapb.DataType_Array:
+		kind, ok := scalarKind(field.GetElementType())
+		if len(info.GetNestedPath()) != 1 || !ok {
+			return 0, fmt.Errorf("array field %s must be accessed by an index in expression", field.GetName())
+		}
+		return kind, nil
+	}
+	kind, ok := scalarKind(field.GetDataType())
+	if !ok {
+		return 0, fmt.Errorf("field %s of type %s is not supported in expression", field.GetName(), field.GetDataType())
+	}
+	return kind, nil
+}
+
+func (e *exprEvaluator) checkLength(expr *planpb.Expr) error {
+	if info := expr.GetColumnExpr().GetInfo(); info != nil {
+		field, err := e.field(info)
+		if err != nil {
+			return err
+		}
+		if field.GetDataType() == schemapb.DataType_Array && len(info.GetNestedPath()) == 0 {
+			return nil
+		}
+	}
+	kind, err := e.check(expr)
+	if err != nil {
+		return err
+	}
+	if !isStringKind(kind) {
+		return fmt.Errorf("length can only apply on string, array or json, got %s", kind)
+	}
+	return nil
+}
+
+// check validates the expression and returns the kind of its value.
+func (e *exprEvaluator) check(expr *planpb.Expr) (schemapb.DataType, error) {
+	switch expr.GetExpr().(type) {
+	case *planpb.Expr_ColumnExpr:
+		return e.checkColumn(expr.GetColumnExpr().GetInfo())
+	case *planpb.Expr_ValueExpr:
+		switch expr.GetValueExpr().GetValue().GetVal().(type) {
+		case *planpb.GenericValue_BoolVal:
+			return schemapb.DataType_Bool, nil
+		case *planpb.GenericValue_Int64Val:
+			return schemapb.DataType_Int64, nil
+		case *planpb.GenericValue_FloatVal:
+			return schemapb.DataType_Double, nil
+		case *planpb.GenericValue_StringVal:
+			return schemapb.DataType_VarChar, nil
+		default:
+			return 0, fmt.Errorf("unsupported value in expression: %s", expr.GetValueExpr().GetValue())
+		}
+	case *planpb.Expr_CallExpr:
+		return e.checkCall(expr.GetCallExpr())
+	case *planpb.Expr_BinaryArithExpr:
+		arith := expr.GetBinaryArithExpr()
+		if arith.GetOp() == planpb.ArithOpType_ArrayLength {
+			return schemapb.DataType_Int64, e.checkLength(arith.GetLeft())
+		}
+		left, err := e.check(arith.GetLeft())
+		if err != nil {
+			return 0, err
+		}
+		right, err := e.check(arith.GetRight())
+		if err != nil {
+			return 0, err
+		}
+		return arithKind(arith.GetOp(), left, right)
+	case *planpb.Expr_UnaryRangeExpr:
+		if _, err := compareOp(expr.GetUnaryRangeExpr().GetOp()); err != nil {
+			return 0, err
+		}
+		_, err := e.checkColumn(expr.GetUnaryRangeExpr().GetColumnInfo())
+		return schemapb.DataType_Bool, err
+	case *planpb.Expr_BinaryRangeExpr:
+		_, err := e.checkColumn(expr.GetBinaryRangeExpr().GetColumnInfo())
+		return schemapb.DataType_Bool, err
+	case *planpb.Expr_CompareExpr:
+		if _, err := compareOp(expr.GetCompareExpr().GetOp()); err != nil {
+			return 0, err
+		}
+		if _, err := e.checkColumn(expr.GetCompareExpr().GetLeftColumnInfo()); err != nil {
+			return 0, err
+		}
+		_, err := e.checkColumn(expr.GetCompareExpr().GetRightColumnInfo())
+		return schemapb.DataType_Bool, err
+	case *planpb.Expr_BinaryArithOpEvalRangeExpr:
+		arith := expr.GetBinaryArithOpEvalRangeExpr()
+		if _, err := compareOp(arith.GetOp()); err != nil {
+			return 0, err
+		}
+		kind, err := e.checkColumn(arith.GetColumnInfo())
+		if err != nil {
+			return 0, err
+		}
+		operand, err := e.check(&planpb.Expr{Expr: &planpb.Expr_ValueExpr{ValueExpr: &planpb.ValueExpr{Value: arith.GetRightOperand()}}})
+		if err != nil {
+			return 0, err
+		}
+		_, err = arithKind(arith.GetArithOp(), kind, operand)
+		return schemapb.DataType_Bool, err
+	case *planpb.Expr_TermExpr:
+		_, err := e.checkColumn(expr.GetTermExpr().GetColumnInfo())
+		return schemapb.DataType_Bool, err
+	case *planpb.Expr_NullExpr:
+		_, err := e.checkColumn(expr.GetNullExpr().GetColumnInfo())
+		return schemapb.DataType_Bool, err
+	case *planpb.Expr_UnaryExpr:
+		if expr.GetUnaryExpr().GetOp() != planpb.UnaryExpr_Not {
+			return 0, fmt.Errorf("unsupported unary operator in expression: %s", expr.GetUnaryExpr().GetOp())
+		}
+		return e.checkBool(expr.GetUnaryExpr().GetChild())
+	case *planpb.Expr_BinaryExpr:
+		if _, err := e.checkBool(expr.GetBinaryExpr().GetLeft()); err != nil {
+			return 0, err
+		}
+		return e.checkBool(expr.GetBinaryExpr().GetRight())
+	default:
+		return 0, fmt.Errorf("unsupported expression: %T", expr.GetExpr())
+	}
+}
+
+func (e *exprEvaluator) checkBool(expr *planpb.Expr) (schemapb.DataType, error) {
+	kind, err := e.check(expr)
+	if err != nil {
+		return 0, err
+	}
+	if kind != schemapb.DataType_Bool && kind != kindDynamic {
+		return 0, fmt.Errorf("logical operators can only apply on bool, got %s", kind)
+	}
+	return schemapb.DataType_Bool, nil
+}
+
+func arithKind(op planpb.ArithOpType, left, right schemapb.DataType) (schemapb.DataType, error) {
+	if !isNumericKind(left) || !isNumericKind(right) {
+		return 0, fmt.Errorf("arithmetic operator %s can only apply on numbers", op)
+	}
+	switch op {
+	case planpb.ArithOpType_Add, planpb.ArithOpType_Sub, planpb.ArithOpType_Mul, planpb.ArithOpType_Div:
+	case planpb.ArithOpType_Mod:
+		if left == schemapb.DataType_Double || right == schemapb.DataType_Double {
+			return 0, fmt.Errorf("modulo can only apply on integer")
+		}
+	default:
+		return 0, fmt.Errorf("unsupported arithmetic operator in expression: %s", op)
+	}
+	switch {
+	case left == schemapb.DataType_Double || right == schemapb.DataType_Double:
+		return schemapb.DataType_Double, nil
+	case left == kindDynamic || right == kindDynamic:
+		return kindDynamic, nil
+	default:
+		return schemapb.DataType_Int64, nil
+	}
+}
+
+func (e *exprEvaluator) checkCall(call *planpb.CallExpr) (schemapb.DataType, error) {
+	name, params := call.GetFunctionName(), call.GetFunctionParameters()
+	checkParamNum := func(num int) error {
+		if len(params) != num {
+			return fmt.Errorf("function %s expects %d parameters, got %d", name, num, len(params))
+		}
+		return nil
+	}
+	kinds := make([]schemapb.DataType, 0, len(params))
+	checkParams := func() error {
+		for _, param := range params {
+			kind, err := e.check(param)
+			if err != nil {
+				return err
+			}
+			kinds = append(kinds, kind)
+		}
+		return nil
+	}
+
+	switch name {
+	case "lower", "upper", "trim":
+		if err := checkParamNum(1); err != nil {
+			return 0, err
+		}
+		if err := checkParams(); err != nil {
+			return 0, err
+		}
+		if !isStringKind(kinds[0]) {
+			return 0, fmt.Errorf("function %s can only apply on string, got %s", name, kinds[0])
+		}
+		return schemapb.DataType_VarChar, nil
+	case "len", "length":
+		if err := checkParamNum(1); err != nil {
+			return 0, err
+		}
+		return schemapb.DataType_Int64, e.checkLength(params[0])
+	case "abs":
+		if err := checkParamNum(1); err != nil {
+			return 0, err
+		}
+		if err := checkParams(); err != nil {
+			return 0, err
+		}
+		if !isNumericKind(kinds[0]) {
+			return 0, fmt.Errorf("function %s can only apply on number, got %s", name, kinds[0])
+		}
+		return kinds[0], nil
+	case "concat":
+		if len(params) == 0 {
+			return 0, fmt.Errorf("function %s expects at least 1 parameter", name)
+		}
+		if err := checkParams(); err != nil {
+			return 0, err
+		}
+		for _, kind := range kinds {
+			if !isStringKind(kind) {
+				return 0, fmt.Errorf("function %s can only apply on string, got %s", name, kind)
+			}
+		}
+		return schemapb.DataType_VarChar, nil
+	case "coalesce":
+		if len(params) == 0 {
+			return 0, fmt.Errorf("function %s expects at least 1 parameter", name)
+		}
+		if err := checkParams(); err != nil {
+			return 0, err
+		}
+		ret := kindDynamic
+		for _, kind := range kinds {
+			switch {
+			case kind == kindDynamic || kind == ret:
+			case ret == kindDynamic:
+				ret = kind
+			case isNumericKind(kind) && isNumericKind(ret):
+				ret = schemapb.DataType_Double
+			default:
+				return 0, fmt.Errorf("function %s cannot apply on both %s and %s", name, ret, kind)
+			}
+		}
+		return ret, nil
+	case "year", "month", "day", "hour":
+		if err := checkParamNum(1); err != nil {
+			return 0, err
+		}
+		info := params[0].GetColumnExpr().GetInfo()
+		if info == nil {
+			return 0, fmt.Errorf("function %s can only apply on timestamptz field", name)
+		}
+		field, err := e.field(info)
+		if err != nil {
+			return 0, err
+		}
+		if field.GetDataType() != schemapb.DataType_Timestamptz {
+			return 0, fmt.Errorf("function %s can only apply on timestamptz field, got %s", name, field.GetDataType())
+		}
+		return schemapb.DataType_Int64, nil
+	default:
+		return 0, fmt.Errorf("unsupported function in expression: %s", name)
+	}
+}
+
+// eval evaluates the expression on the row, row[i] is the value of the i-th input field as returned
+// by storage.FieldData.GetRow, nil for null.
+func (e *exprEvaluator) eval(row []any) (any, error) {
+	return e.evalExpr(e.expr, row)
+}
+
+func (e *exprEvaluator) evalExpr(expr *planpb.Expr, row []any) (any, error) {
+	switch expr.GetExpr().(type) {
+	case *planpb.Expr_ColumnExpr:
+		return e.evalColumn(expr.GetColumnExpr().GetInfo(), row)
+	case *planpb.Expr_ValueExpr:
+		return genericValue(expr.GetValueExpr().GetValue()), nil
+	case *planpb.Expr_CallExpr:
+		return e.evalCall(expr.GetCallExpr(), row)
+	case *planpb.Expr_BinaryArithExpr:
+		arith := expr.GetBinaryArithExpr()
+		if arith.GetOp() == planpb.ArithOpType_ArrayLength {
+			return e.evalLength(arith.GetLeft(), row)
+		}
+		left, err := e.evalExpr(arith.GetLeft(), row)
+		if err != nil {
+			return nil, err
+		}
+		right, err := e.evalExpr(arith.GetRight(), row)
+		if err != nil {
+			return nil, err
+		}
+		return evalArith(arith.GetOp(), left, right)
+	case *planpb.Expr_UnaryRangeExpr:
+		unary := expr.GetUnaryRangeExpr()
+		value, err := e.evalColumn(unary.GetColumnInfo(), row)
+		if err != nil {
+			return nil, err
+		}
+		return evalCompare(unary.GetOp(), value, genericValue(unary.GetValue()))
+	case *planpb.Expr_BinaryRangeExpr:
+		return e.evalRange(expr.GetBinaryRangeExpr(), row)
+	case *planpb.Expr_CompareExpr:
+		compare := expr.GetCompareExpr()
+		left, err := e.evalColumn(compare.GetLeftColumnInfo(), row)
+		if err != nil {
+			return nil, err
+		}
+		right, err := e.evalColumn(compare.GetRightColumnInfo(), row)
+		if err != nil {
+			return nil, err
+		}
+		return evalCompare(compare.GetOp(), left, right)
+	case *planpb.Expr_BinaryArithOpEvalRangeExpr:
+		arith := expr.GetBinaryArithOpEvalRangeExpr()
+		value, err := e.evalColumn(arith.GetColumnInfo(), row)
+		if err != nil {
+			return nil, err
+		}
+		value, err = evalArith(arith.GetArithOp(), value, genericValue(arith.GetRightOperand()))
+		if err != nil {
+			return nil, err
+		}
+		return evalCompare(arith.GetOp(), value, genericValue(arith.GetValue()))
+	case *planpb.Expr_TermExpr:
+		term := expr.GetTermExpr()
+		value, err := e.evalColumn(term.GetColumnInfo(), row)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		for _, v := range term.GetValues() {
+			if eq, err := evalCompare(planpb.OpType_Equal, value, genericValue(v)); err != nil {
+				return nil, err
+			} else if eq == true {
+				return true, nil
+			}
+		}
+		return false, nil
+	case *planpb.Expr_NullExpr:
+		null := expr.GetNullExpr()
+		value, err := e.evalColumn(null.GetColumnInfo(), row)
+		if err != nil {
+			return nil, err
+		}
+		return (value == nil) == (null.GetOp() == planpb.NullExpr_IsNull), nil
+	case *planpb.Expr_UnaryExpr:
+		value, err := e.evalBool(expr.GetUnaryExpr().GetChild(), row)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		return !value.(bool), nil
+	case *planpb.Expr_BinaryExpr:
+		return e.evalLogical(expr.GetBinaryExpr(), row)
+	default:
+		return nil, fmt.Errorf("unsupported expression: %T", expr.GetExpr())
+	}
+}
+
+func genericValue(v *planpb.GenericValue) any {
+	switch val := v.GetVal().(type) {
+	case *planpb.GenericValue_BoolVal:
+		return val.BoolVal
+	case *planpb.GenericValue_Int64Val:
+		return val.Int64Val
+	case *planpb.GenericValue_FloatVal:
+		return val.FloatVal
+	case *planpb.GenericValue_StringVal:
+		return val.StringVal
+	default:
+		return nil
+	}
+}
+
+// normalizeValue converts the row value of a scalar field into the value of the expression.
+func normalizeValue(v any) any {
+	switch val := v.(type) {
+	case int8:
+		return int64(val)
+	case int16:
+		return int64(val)
+	case int32:
+		return int64(val)
+	case float32:
+		return float64(val)
+	default:
+		return v
+	}
+}
+
+func (e *exprEvaluator) evalColumn(info *planpb.ColumnInfo, row []any) (any, error) {
+	field, err := e.field(info)
+	if err != nil {
+		return nil, err
+	}
+	value := row[info.GetFieldId()-common.StartOfUserFieldID]
+	if value == nil {
+		return nil, nil
+	}
+	switch field.GetDataType() {
+	case schemapb.DataType_JSON:
+		v, err := lookupJSON(value, info.GetNestedPath())
+		if err != nil {
+			return nil, fmt.Errorf("invalid json of field %s: %w", field.GetName(), err)
+		}
+		switch v.(type) {
+		case []any, map[string]any:
+			return nil, fmt.Errorf("json path %v of field %s is not a scalar", info.GetNestedPath(), field.GetName())
+		}
+		return v, nil
+	case schemapb.DataType_Array:
+		idx, err := strconv.Atoi(info.GetNestedPath()[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid index %s of array field %s", info.GetNestedPath()[0], field.GetName())
+		}
+		return arrayElement(value, idx), nil
+	default:
+		return normalizeValue(value), nil
+	}
+}
+
+// lookupJSON decodes the json and returns the value at the path, nil if the path doesn't exist.
+// The numbers are returned as int64 if they are integers, float64 otherwise.
+func lookupJSON(value any, path []string) (any, error) {
+	data, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected json value %T", value)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	for _, key := range path {
+		switch node := v.(type) {
+		case map[string]any:
+			v = node[key]
+		case []any:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, nil
+			}
+			v = node[idx]
+		default:
+			return nil, nil
+		}
+	}
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		return n.Float64()
+	}
+	return v, nil
+}
+
+// arrayElement returns the idx-th element of the array, nil if it's out of range.
+func arrayElement(value any, idx int) any {
+	array, ok := value.(*schemapb.ScalarField)
+	if !ok || idx < 0 {
+		return nil
+	}
+	switch data := array.GetData().(type) {
+	case *schemapb.ScalarField_BoolData:
+		if idx < len(data.BoolData.GetData()) {
+			return data.BoolData.GetData()[idx]
+		}
+	case *schemapb.ScalarField_IntData:
+		if idx < len(data.IntData.GetData()) {
+			return int64(data.IntData.GetData()[idx])
+		}
+	case *schemapb.ScalarField_LongData:
+		if idx < len(data.LongData.GetData()) {
+			return data.LongData.GetData()[idx]
+		}
+	case *schemapb.ScalarField_FloatData:
+		if idx < len(data.FloatData.GetData()) {
+			return float64(data.FloatData.GetData()[idx])
+		}
+	case *schemapb.ScalarField_DoubleData:
+		if idx < len(data.DoubleData.GetData()) {
+			return data.DoubleData.GetData()[idx]
+		}
+	case *schemapb.ScalarField_StringData:
+		if idx < len(data.StringData.GetData()) {
+			return data.StringData.GetData()[idx]
+		}
+	}
+	return nil
+}
+
+func arrayLength(array *schemapb.ScalarField) int {
+	switch data := array.GetData().(type) {
+	case *schemapb.ScalarField_BoolData:
+		return len(data.BoolData.GetData())
+	case *schemapb.ScalarField_IntData:
+		return len(data.IntData.GetData())
+	case *schemapb.ScalarField_LongData:
+		return len(data.LongData.GetData())
+	case *schemapb.ScalarField_FloatData:
+		return len(data.FloatData.GetData())
+	case *schemapb.ScalarField_DoubleData:
+		return len(data.DoubleData.GetData())
+	case *schemapb.ScalarField_StringData:
+		return len(data.StringData.GetData())
+	default:
+		return 0
+	}
+}
+
+// evalLength returns the number of characters of a string or the number of elements of an array.
+func (e *exprEvaluator) evalLength(expr *planpb.Expr, row []any) (any, error) {
+	var value any
+	var err error
+	info := expr.GetColumnExpr().GetInfo()
+	field, _ := e.field(info)
+	switch {
+	case info != nil && field.GetDataType() == schemapb.DataType_Array && len(info.GetNestedPath()) == 0:
+		array, ok := row[info.GetFieldId()-common.StartOfUserFieldID].(*schemapb.ScalarField)
+		if !ok {
+			return nil, nil
+		}
+		return int64(arrayLength(array)), nil
+	case info != nil && field.GetDataType() == schemapb.DataType_JSON:
+		// the json path may hold an array, which isn't a valid column value.
+		if value = row[info.GetFieldId()-common.StartOfUserFieldID]; value != nil {
+			if value, err = lookupJSON(value, info.GetNestedPath()); err != nil {
+				return nil, fmt.Errorf("invalid json of field %s: %w", field.GetName(), err)
+			}
+		}
+	default:
+		if value, err = e.evalExpr(expr, row); err != nil {
+			return nil, err
+		}
+	}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return int64(utf8.RuneCountInString(v)), nil
+	case []any:
+		return int64(len(v)), nil
+	default:
+		return nil, fmt.Errorf("length can only apply on string or array, got %T", value)
+	}
+}
+
+func (e *exprEvaluator) evalCall(call *planpb.CallExpr, row []any) (any, error) {
+	name, params := call.GetFunctionName(), call.GetFunctionParameters()
+	switch name {
+	case "len", "length":
+		return e.evalLength(params[0], row)
+	case "coalesce":
+		for _, param := range params {
+			v, err := e.evalExpr(param, row)
+			if err != nil || v != nil {
+				return v, err
+			}
+		}
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(params))
+	for _, param := range params {
+		v, err := e.evalExpr(param, row)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			// null in, null out
+			return nil, nil
+		}
+		args = append(args, v)
+	}
+	switch name {
+	case "lower", "upper", "trim":
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("function %s can only apply on string, got %T", name, args[0])
+		}
+		switch name {
+		case "lower":
+			return strings.ToLower(s), nil
+		case "upper":
+			return strings.ToUpper(s), nil
+		default:
+			return strings.TrimSpace(s), nil
+		}
+	case "concat":
+		var sb strings.Builder
+		for _, arg := range args {
+			s, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("function %s can only apply on string, got %T", name, arg)
+			}
+			sb.WriteString(s)
+		}
+		return sb.String(), nil
+	case "abs":
+		switch v := args[0].(type) {
+		case int64:
+			if v < 0 {
+				return -v, nil
+			}
+			return v, nil
+		case float64:
+			return math.Abs(v), nil
+		default:
+			return nil, fmt.Errorf("function %s can only apply on number, got %T", name, args[0])
+		}
+	case "year", "month", "day", "hour":
+		t := time.UnixMicro(args[0].(int64)).UTC()
+		switch name {
+		case "year":
+			return int64(t.Year()), nil
+		case "month":
+			return int64(t.Month()), nil
+		case "day":
+			return int64(t.Day()), nil
+		default:
+			return int64(t.Hour()), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported function in expression: %s", name)
+	}
+}
+
+func evalArith(op planpb.ArithOpType, left, right any) (any, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	l, lok := left.(int64)
+	r, rok := right.(int64)
+	if lok && rok {
+		switch op {
+		case planpb.ArithOpType_Add:
+			return l + r, nil
+		case planpb.ArithOpType_Sub:
+			return l - r, nil
+		case planpb.ArithOpType_Mul:
+			return l * r, nil
+		case planpb.ArithOpType_Div:
+			if r == 0 {
+				return nil, fmt.Errorf("cannot divide by zero")
+			}
+			return l / r, nil
+		case planpb.ArithOpType_Mod:
+			if r == 0 {
+				return nil, fmt.Errorf("cannot modulo by zero")
+			}
+			return l % r, nil
+		}
+		return nil, fmt.Errorf("unsupported arithmetic operator in expression: %s", op)
+	}
+
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("arithmetic operator %s can only apply on numbers, got %T and %T", op, left, right)
+	}
+	switch op {
+	case planpb.ArithOpType_Add:
+		return lf + rf, nil
+	case planpb.ArithOpType_Sub:
+		return lf - rf, nil
+	case planpb.ArithOpType_Mul:
+		return lf * rf, nil
+	case planpb.ArithOpType_Div:
+		if rf == 0 {
+			return nil, fmt.Errorf("cannot divide by zero")
+		}
+		return lf / rf, nil
+	}
+	return nil, fmt.Errorf("arithmetic operator %s can only apply on integers", op)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch val := v.(type) {
+	case int64:
+		return float64(val), true
+	case float64:
+		return val, true
+	default:
+		return 0, false
+	}
+}
+
+// compareOp checks the comparison operator is supported by the evaluator.
+func compareOp(op planpb.OpType) (planpb.OpType, error) {
+	switch op {
+	case planpb.OpType_GreaterThan, planpb.OpType_GreaterEqual, planpb.OpType_LessThan, planpb.OpType_LessEqual,
+		planpb.OpType_Equal, planpb.OpType_NotEqual, planpb.OpType_PrefixMatch, planpb.OpType_PostfixMatch:
+		return op, nil
+	default:
+		return op, fmt.Errorf("unsupported comparison operator in expression: %s", op)
+	}
+}
+
+// compareValues returns -1, 0 or 1, the values must be both numbers, strings or bools.
+func compareValues(left, right any) (int, error) {
+	switch l := left.(type) {
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(l, r), nil
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			switch {
+			case l == r:
+				return 0, nil
+			case r:
+				return -1, nil
+			default:
+				return 1, nil
+			}
+		}
+	default:
+		li, lok := left.(int64)
+		ri, rok := right.(int64)
+		if lok && rok {
+			switch {
+			case li < ri:
+				return -1, nil
+			case li > ri:
+				return 1, nil
+			default:
+				return 0, nil
+			}
+		}
+		lf, lok := toFloat(left)
+		rf, rok := toFloat(right)
+		if lok && rok {
+			switch {
+			case lf < rf:
+				return -1, nil
+			case lf > rf:
+				return 1, nil
+			default:
+				return 0, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %T with %T", left, right)
+}
+
+func evalCompare(op planpb.OpType, left, right any) (any, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	switch op {
+	case planpb.OpType_PrefixMatch, planpb.OpType_PostfixMatch:
+		l, lok := left.(string)
+		r, rok := right.(string)
+		if !lok || !rok {
+			return nil, fmt.Errorf("pattern match can only apply on string, got %T", left)
+		}
+		if op == planpb.OpType_PrefixMatch {
+			return strings.HasPrefix(l, r), nil
+		}
+		return strings.HasSuffix(l, r), nil
+	}
+
+	c, err := compareValues(left, right)
+	if err != nil {
+		// values of different types are never equal, e.g. a json path holding a string is compared with a number.
+		switch op {
+		case planpb.OpType_Equal:
+			return false, nil
+		case planpb.OpType_NotEqual:
+			return true, nil
+		}
+		return nil, err
+	}
+	switch op {
+	case planpb.OpType_GreaterThan:
+		return c > 0, nil
+	case planpb.OpType_GreaterEqual:
+		return c >= 0, nil
+	case planpb.OpType_LessThan:
+		return c < 0, nil
+	case planpb.OpType_LessEqual:
+		return c <= 0, nil
+	case planpb.OpType_Equal:
+		return c == 0, nil
+	case planpb.OpType_NotEqual:
+		return c != 0, nil
+	default:
+		return nil, fmt.Errorf("unsupported comparison operator in expression: %s", op)
+	}
+}
+
+func (e *exprEvaluator) evalRange(expr *planpb.BinaryRangeExpr, row []any) (any, error) {
+	value, err := e.evalColumn(expr.GetColumnInfo(), row)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	lowerOp, upperOp := planpb.OpType_GreaterThan, planpb.OpType_LessThan
+	if expr.GetLowerInclusive() {
+		lowerOp = planpb.OpType_GreaterEqual
+	}
+	if expr.GetUpperInclusive() {
+		upperOp = planpb.OpType_LessEqual
+	}
+	lower, err := evalCompare(lowerOp, value, genericValue(expr.GetLowerValue()))
+	if err != nil || lower != true {
+		return lower, err
+	}
+	return evalCompare(upperOp, value, genericValue(expr.GetUpperValue()))
+}
+
+func (e *exprEvaluator) evalBool(expr *planpb.Expr, row []any) (any, error) {
+	value, err := e.evalExpr(expr, row)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	if _, ok := value.(bool); !ok {
+		return nil, fmt.Errorf("logical operators can only apply on bool, got %T", value)
+	}
+	return value, nil
+}
+
+// evalLogical follows the three-valued logic: false && null is false, true || null is true,
+// otherwise null if any operand is null.
+func (e *exprEvaluator) evalLogical(expr *planpb.BinaryExpr, row []any) (any, error) {
+	left, err := e.evalBool(expr.GetLeft(), row)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.evalBool(expr.GetRight(), row)
+	if err != nil {
+		return nil, err
+	}
+	switch expr.GetOp() {
+	case planpb.BinaryExpr_LogicalAnd:
+		if left == false || right == false {
+			return false, nil
+		}
+	case planpb.BinaryExpr_LogicalOr:
+		if left == true || right == true {
+			return true, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported logical operator in expression: %s", expr.GetOp())
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	return expr.GetOp() == planpb.BinaryExpr_LogicalAnd, nil
+}
@@ -0,0 +1,483 @@
+/*
+ * # Licensed to the LF AI & Data foundation under one
+ * # or more contributor license agreements. See the NOTICE file
+ * # distributed with this work for additional information
+ * # regarding copyright ownership. The ASF licenses this file
+ * # to you under the Apache License, Version 2.0 (the
+ * # "License"); you may not use this file except in compliance
+ * # with the License. You may obtain a copy of the License at
+ * #
+ * #     http://www.apache.org/licenses/LICENSE-2.0
+ * #
+ * # Unless required by applicable law or agreed to in writing, software
+ * # distributed under the License is distributed on an "AS IS" BASIS,
+ * # WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * # See the License for the specific language governing permissions and
+ * # limitations under the License.
+ */
+
+package function
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/parser/planparserv2"
+	"github.com/milvus-io/milvus/internal/storage"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+	"github.com/milvus-io/milvus/pkg/v2/util/parameterutil"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+const (
+	// FunctionTypeExpression computes a scalar field from an expression of the input fields at write time.
+	// It's not defined by milvus-proto yet, so the value is reserved out of the range of the defined types.
+	FunctionTypeExpression schemapb.FunctionType = 100
+	// ExpressionFunctionName is the name of FunctionTypeExpression in the RESTful API.
+	ExpressionFunctionName = "Expression"
+	// ExpressionKey is the function param of the expression, e.g. `lower(title)` or `json_field["a"]["b"]`.
+	ExpressionKey = "expression"
+)
+
+// IsExpressionFunction returns whether the function computes its output by an expression.
+func IsExpressionFunction(schema *schemapb.FunctionSchema) bool {
+	return schema.GetType() == FunctionTypeExpression
+}
+
+// ExpressionFunctionRunner
+// Input: the fields referred by the expression, in the order of the input field names
+// Output: a scalar field, which may be nullable
+type ExpressionFunctionRunner struct {
+	schema      *schemapb.FunctionSchema
+	inputFields []*schemapb.FieldSchema
+	outputField *schemapb.FieldSchema
+	maxLength   int64
+	evaluator   *exprEvaluator
+}
+
+func getExpression(schema *schemapb.FunctionSchema) (string, error) {
+	for _, param := range schema.GetParams() {
+		if param.GetKey() == ExpressionKey {
+			return param.GetValue(), nil
+		}
+	}
+	return "", fmt.Errorf("expression function %s must have the param %s", schema.GetName(), ExpressionKey)
+}
+
+func isExpressionOutputType(dataType schemapb.DataType) bool {
+	switch dataType {
+	case schemapb.DataType_Bool, schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32,
+		schemapb.DataType_Int64, schemapb.DataType_Float, schemapb.DataType_Double, schemapb.DataType_VarChar:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewExpressionFunctionRunner compiles the expression of the function, the fields are resolved by names
+// since the field ids may not be assigned yet when the collection is being created.
+func NewExpressionFunctionRunner(coll *schemapb.CollectionSchema, schema *schemapb.FunctionSchema) (*ExpressionFunctionRunner, error) {
+	if len(schema.GetOutputFieldNames()) != 1 {
+		return nil, fmt.Errorf("expression function should only have one output field, but now %d", len(schema.GetOutputFieldNames()))
+	}
+	if len(schema.GetInputFieldNames()) == 0 {
+		return nil, errors.New("expression function should have at least one input field")
+	}
+
+	fields := make(map[string]*schemapb.FieldSchema, len(coll.GetFields()))
+	for _, field := range coll.GetFields() {
+		fields[field.GetName()] = field
+	}
+	inputFields := make([]*schemapb.FieldSchema, 0, len(schema.GetInputFieldNames()))
+	for _, name := range schema.GetInputFieldNames() {
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("expression function input field '%s' not found", name)
+		}
+		inputFields = append(inputFields, field)
+	}
+	outputField, ok := fields[schema.GetOutputFieldNames()[0]]
+	if !ok {
+		return nil, fmt.Errorf("expression function output field '%s' not found", schema.GetOutputFieldNames()[0])
+	}
+	if !isExpressionOutputType(outputField.GetDataType()) {
+		return nil, fmt.Errorf("expression function output field '%s' must be bool, integer, float, double or varchar, got %s",
+			outputField.GetName(), outputField.GetDataType())
+	}
+	var maxLength int64
+	if outputField.GetDataType() == schemapb.DataType_VarChar {
+		var err error
+		if maxLength, err = parameterutil.GetMaxLength(outputField); err != nil {
+			return nil, err
+		}
+	}
+
+	expression, err := getExpression(schema)
+	if err != nil {
+		return nil, err
+	}
+	// the expression is parsed against the input fields only, so it can't refer to any other field.
+	// The fields get positional ids in case the ids aren't assigned yet.
+	exprFields := make([]*schemapb.FieldSchema, 0, len(inputFields))
+	for i, field := range inputFields {
+		f := proto.Clone(field).(*schemapb.FieldSchema)
+		f.FieldID = common.StartOfUserFieldID + int64(i)
+		f.IsPrimaryKey, f.IsPartitionKey, f.IsClusteringKey, f.AutoID = false, false, false, false
+		exprFields = append(exprFields, f)
+	}
+	helper, err := typeutil.CreateSchemaHelper(&schemapb.CollectionSchema{Name: coll.GetName(), Fields: exprFields})
+	if err != nil {
+		return nil, err
+	}
+	expr, err := planparserv2.ParseValueExpr(helper, expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression of function %s: %w", schema.GetName(), err)
+	}
+	evaluator, err := newExprEvaluator(expr, exprFields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression of function %s: %w", schema.GetName(), err)
+	}
+	if !canAssign(evaluator.kind, outputField.GetDataType()) {
+		return nil, fmt.Errorf("expression of function %s has type %s, which can't be assigned to the output field '%s' of type %s",
+			schema.GetName(), evaluator.kind, outputField.GetName(), outputField.GetDataType())
+	}
+
+	return &ExpressionFunctionRunner{
+		schema:      schema,
+		inputFields: inputFields,
+		outputField: outputField,
+		maxLength:   maxLength,
+		evaluator:   evaluator,
+	}, nil
+}
+
+// ValidateExpressionFunction checks the expression can be compiled and assigned to the output field.
+func ValidateExpressionFunction(coll *schemapb.CollectionSchema, schema *schemapb.FunctionSchema) error {
+	_, err := NewExpressionFunctionRunner(coll, schema)
+	return err
+}
+
+// canAssign returns whether the value of the expression can be written to the output field,
+// json paths are checked row by row.
+func canAssign(kind, dataType schemapb.DataType) bool {
+	switch kind {
+	case kindDynamic:
+		return true
+	case schemapb.DataType_Bool:
+		return dataType == schemapb.DataType_Bool
+	case schemapb.DataType_Int64:
+		return typeutil.IsIntegerType(dataType) || typeutil.IsFloatingType(dataType)
+	case schemapb.DataType_Double:
+		return typeutil.IsFloatingType(dataType)
+	case schemapb.DataType_VarChar:
+		return dataType == schemapb.DataType_VarChar
+	default:
+		return false
+	}
+}
+
+// castOutput converts the value of the expression into the row value of the output field.
+func (r *ExpressionFunctionRunner) castOutput(v any) (any, error) {
+	field := r.outputField
+	if v == nil {
+		if !field.GetNullable() {
+			return nil, fmt.Errorf("expression function %s computes null for the output field '%s', which isn't nullable",
+				r.schema.GetName(), field.GetName())
+		}
+		return nil, nil
+	}
+
+	mismatch := func() error {
+		return fmt.Errorf("expression function %s computes %v for the output field '%s' of type %s",
+			r.schema.GetName(), v, field.GetName(), field.GetDataType())
+	}
+	outOfRange := func(minValue, maxValue int64) (int64, error) {
+		var i int64
+		switch val := v.(type) {
+		case int64:
+			i = val
+		case float64:
+			if val != math.Trunc(val) || val < math.MinInt64 || val >= math.MaxInt64 {
+				return 0, mismatch()
+			}
+			i = int64(val)
+		default:
+			return 0, mismatch()
+		}
+		if i < minValue || i > maxValue {
+			return 0, fmt.Errorf("expression function %s computes %d for the output field '%s', which is out of the range of %s",
+				r.schema.GetName(), i, field.GetName(), field.GetDataType())
+		}
+		return i, nil
+	}
+
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case schemapb.DataType_Int8:
+		i, err := outOfRange(math.MinInt8, math.MaxInt8)
+		return int8(i), err
+	case schemapb.DataType_Int16:
+		i, err := outOfRange(math.MinInt16, math.MaxInt16)
+		return int16(i), err
+	case schemapb.DataType_Int32:
+		i, err := outOfRange(math.MinInt32, math.MaxInt32)
+		return int32(i), err
+	case schemapb.DataType_Int64:
+		return outOfRange(math.MinInt64, math.MaxInt64)
+	case schemapb.DataType_Float:
+		if f, ok := toFloat(v); ok {
+			return float32(f), nil
+		}
+	case schemapb.DataType_Double:
+		if f, ok := toFloat(v); ok {
+			return f, nil
+		}
+	case schemapb.DataType_VarChar:
+		if s, ok := v.(string); ok {
+			if int64(utf8.RuneCountInString(s)) > r.maxLength {
+				return nil, fmt.Errorf("expression function %s computes a string of length %d for the output field '%s', which exceeds the max length %d",
+					r.schema.GetName(), utf8.RuneCountInString(s), field.GetName(), r.maxLength)
+			}
+			return s, nil
+		}
+	}
+	return nil, mismatch()
+}
+
+// run evaluates the expression for each row, rows[i] returns the value of the i-th input field at the row.
+func (r *ExpressionFunctionRunner) run(numRows int, rows []func(int) any, appendRow func(any) error) error {
+	row := make([]any, len(rows))
+	for i := 0; i < numRows; i++ {
+		for j, get := range rows {
+			row[j] = get(i)
+		}
+		v, err := r.evaluator.eval(row)
+		if err != nil {
+			return fmt.Errorf("expression function %s failed at row %d: %w", r.schema.GetName(), i, err)
+		}
+		if v, err = r.castOutput(v); err != nil {
+			return err
+		}
+		if err := appendRow(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchRun takes the storage.FieldData of the input fields and returns the storage.FieldData of the output field.
+func (r *ExpressionFunctionRunner) BatchRun(inputs ...any) ([]any, error) {
+	if len(inputs) != len(r.inputFields) {
+		return nil, fmt.Errorf("expression function %s expects %d input columns, got %d", r.schema.GetName(), len(r.inputFields), len(inputs))
+	}
+	numRows := -1
+	rows := make([]func(int) any, 0, len(inputs))
+	for _, input := range inputs {
+		data, ok := input.(storage.FieldData)
+		if !ok {
+			return nil, fmt.Errorf("expression function %s batch input is not field data", r.schema.GetName())
+		}
+		if numRows != -1 && numRows != data.RowNum() {
+			return nil, fmt.Errorf("expression function %s received input columns with different number of rows", r.schema.GetName())
+		}
+		numRows = data.RowNum()
+		rows = append(rows, data.GetRow)
+	}
+
+	output, err := storage.NewFieldData(r.outputField.GetDataType(), r.outputField, numRows)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.run(numRows, rows, output.AppendRow); err != nil {
+		return nil, err
+	}
+	return []any{output}, nil
+}
+
+// RunFieldsData takes the field data of the insert request, the nullable fields may be in the compact form
+// that only the valid rows are in the data. The output is in the same compact form if it's nullable.
+func (r *ExpressionFunctionRunner) RunFieldsData(inputs []*schemapb.FieldData) (*schemapb.FieldData, error) {
+	if len(inputs) != len(r.inputFields) {
+		return nil, fmt.Errorf("expression function %s expects %d input columns, got %d", r.schema.GetName(), len(r.inputFields), len(inputs))
+	}
+	numRows := -1
+	rows := make([]func(int) any, 0, len(inputs))
+	for _, input := range inputs {
+		get, n := fieldDataRows(input)
+		if numRows != -1 && numRows != n {
+			return nil, fmt.Errorf("expression function %s received input columns with different number of rows", r.schema.GetName())
+		}
+		numRows = n
+		rows = append(rows, get)
+	}
+
+	values := make([]any, 0, numRows)
+	if err := r.run(numRows, rows, func(v any) error {
+		values = append(values, v)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return buildFieldData(r.outputField, values), nil
+}
+
+// fieldDataRows returns the accessor of the rows and the number of rows of the field data.
+func fieldDataRows(fd *schemapb.FieldData) (func(int) any, int) {
+	scalars := fd.GetScalars()
+	var get func(int) any
+	var dataLen int
+	switch fd.GetType() {
+	case schemapb.DataType_Bool:
+		data := scalars.GetBoolData().GetData()
+		get, dataLen = func(i int) any { return data[i] }, len(data)
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		data := scalars.GetIntData().GetData()
+		get, dataLen = func(i int) any { return data[i] }, len(data)
+	case schemapb.DataType_Int64:
+		data := scalars.GetLongData().GetData()
+		get, dataLen = func(i int) any { return data[i] }, len(data)
+	case schemapb.DataType_Float:
+		data := scalars.GetFloatData().GetData()
+		get, dataLen = func(i int) any { return data[i] }, len(data)
+	case schemapb.DataType_Double:
+		data := scalars.GetDoubleData().GetData()
+		get, dataLen = func(i int) any { return data[i] }, len(data)
+	case schemapb.DataType_Timestamptz:
+		data := scalars.GetTimestamptzData().GetData()
+		get, dataLen = func(i int) any { return data[i] }, len(data)
+	case schemapb.DataType_VarChar, schemapb.DataType_String, schemapb.DataType_Text:
+		data := scalars.GetStringData().GetData()
+		get, dataLen = func(i int) any { return data[i] }, len(data)
+	case schemapb.DataType_JSON:
+		data := scalars.GetJsonData().GetData()
+		get, dataLen = func(i int) any { return data[i] }, len(data)
+	case schemapb.DataType_Array:
+		data := scalars.GetArrayData().GetData()
+		get, dataLen = func(i int) any { return data[i] }, len(data)
+	default:
+		return func(int) any { return nil }, 0
+	}
+
+	validData := fd.GetValidData()
+	if len(validData) == 0 {
+		return get, dataLen
+	}
+	if len(validData) == dataLen {
+		return func(i int) any {
+			if !validData[i] {
+				return nil
+			}
+			return get(i)
+		}, dataLen
+	}
+	// compact form, map the row to the position in the data.
+	idxs := make([]int, len(validData))
+	cnt := 0
+	for i, valid := range validData {
+		idxs[i] = -1
+		if valid && cnt < dataLen {
+			idxs[i] = cnt
+			cnt++
+		}
+	}
+	return func(i int) any {
+		if idxs[i] == -1 {
+			return nil
+		}
+		return get(idxs[i])
+	}, len(validData)
+}
+
+// buildFieldData builds the field data of the output field from the row values, nil for null.
+func buildFieldData(field *schemapb.FieldSchema, values []any) *schemapb.FieldData {
+	var validData []bool
+	if field.GetNullable() {
+		validData = make([]bool, len(values))
+		for i, v := range values {
+			validData[i] = v != nil
+		}
+	}
+	valid := make([]any, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			valid = append(valid, v)
+		}
+	}
+
+	scalars := &schemapb.ScalarField{}
+	switch field.GetDataType() {
+	case schemapb.DataType_Bool:
+		data := make([]bool, 0, len(valid))
+		for _, v := range valid {
+			data = append(data, v.(bool))
+		}
+		scalars.Data = &schemapb.ScalarField_BoolData{BoolData: &schemapb.BoolArray{Data: data}}
+	case schemapb.DataType_Int8, schemapb.DataType_Int16, schemapb.DataType_Int32:
+		data := make([]int32, 0, len(valid))
+		for _, v := range valid {
+			switch i := v.(type) {
+			case int8:
+				data = append(data, int32(i))
+			case int16:
+				data = append(data, int32(i))
+			case int32:
+				data = append(data, i)
+			}
+		}
+		scalars.Data = &schemapb.ScalarField_IntData{IntData: &schemapb.IntArray{Data: data}}
+	case schemapb.DataType_Int64:
+		data := make([]int64, 0, len(valid))
+		for _, v := range valid {
+			data = append(data, v.(int64))
+		}
+		scalars.Data = &schemapb.ScalarField_LongData{LongData: &schemapb.LongArray{Data: data}}
+	case schemapb.DataType_Float:
+		data := make([]float32, 0, len(valid))
+		for _, v := range valid {
+			data = append(data, v.(float32))
+		}
+		scalars.Data = &schemapb.ScalarField_FloatData{FloatData: &schemapb.FloatArray{Data: data}}
+	case schemapb.DataType_Double:
+		data := make([]float64, 0, len(valid))
+		for _, v := range valid {
+			data = append(data, v.(float64))
+		}
+		scalars.Data = &schemapb.ScalarField_DoubleData{DoubleData: &schemapb.DoubleArray{Data: data}}
+	case schemapb.DataType_VarChar:
+		data := make([]string, 0, len(valid))
+		for _, v := range valid {
+			data = append(data, v.(string))
+		}
+		scalars.Data = &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: data}}
+	}
+
+	return &schemapb.FieldData{
+		Type:      field.GetDataType(),
+		FieldName: field.GetName(),
+		FieldId:   field.GetFieldID(),
+		Field:     &schemapb.FieldData_Scalars{Scalars: scalars},
+		ValidData: validData,
+	}
+}
+
+func (r *ExpressionFunctionRunner) GetSchema() *schemapb.FunctionSchema {
+	return r.schema
+}
+
+func (r *ExpressionFunctionRunner) GetOutputFields() []*schemapb.FieldSchema {
+	return []*schemapb.FieldSchema{r.outputField}
+}
+
+func (r *ExpressionFunctionRunner) GetInputFields() []*schemapb.FieldSchema {
+	return r.inputFields
+}
+
+func (r *ExpressionFunctionRunner) Close() {}
@@ -0,0 +1,272 @@
+/*
+ * # Licensed to the LF AI & Data foundation under one
+ * # or more contributor license agreements. See the NOTICE file
+ * # distributed with this work for additional information
+ * # regarding copyright ownership. The ASF licenses this file
+ * # to you under the Apache License, Version 2.0 (the
+ * # "License"); you may not use this file except in compliance
+ * # with the License. You may obtain a copy of the License at
+ * #
+ * #     http://www.apache.org/licenses/LICENSE-2.0
+ * #
+ * # Unless required by applicable law or agreed to in writing, software
+ * # distributed under the License is distributed on an "AS IS" BASIS,
+ * # WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * # See the License for the specific language governing permissions and
+ * # limitations under the License.
+ */
+
+package function
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/storage"
+)
+
+func TestExpressionFunctionRunnerSuite(t *testing.T) {
+	suite.Run(t, new(ExpressionFunctionRunnerSuite))
+}
+
+type ExpressionFunctionRunnerSuite struct {
+	suite.Suite
+	schema *schemapb.CollectionSchema
+}
+
+func (s *ExpressionFunctionRunnerSuite) SetupTest() {
+	maxLength := []*commonpb.KeyValuePair{{Key: "max_length", Value: "8"}}
+	s.schema = &schemapb.CollectionSchema{
+		Name: "test",
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64, IsPrimaryKey: true},
+			{FieldID: 101, Name: "title", DataType: schemapb.DataType_VarChar, TypeParams: maxLength},
+			{FieldID: 102, Name: "ts", DataType: schemapb.DataType_Timestamptz},
+			{FieldID: 103, Name: "tags", DataType: schemapb.DataType_Array, ElementType: schemapb.DataType_VarChar, TypeParams: maxLength},
+			{FieldID: 104, Name: "meta", DataType: schemapb.DataType_JSON},
+			{FieldID: 105, Name: "price", DataType: schemapb.DataType_Double},
+			{FieldID: 106, Name: "nullable_title", DataType: schemapb.DataType_VarChar, TypeParams: maxLength, Nullable: true},
+			{FieldID: 200, Name: "out_varchar", DataType: schemapb.DataType_VarChar, TypeParams: maxLength, IsFunctionOutput: true},
+			{FieldID: 201, Name: "out_int64", DataType: schemapb.DataType_Int64, IsFunctionOutput: true},
+			{FieldID: 202, Name: "out_int8", DataType: schemapb.DataType_Int8, IsFunctionOutput: true},
+			{FieldID: 203, Name: "out_bool", DataType: schemapb.DataType_Bool, IsFunctionOutput: true},
+			{FieldID: 204, Name: "out_nullable_varchar", DataType: schemapb.DataType_VarChar, TypeParams: maxLength, Nullable: true, IsFunctionOutput: true},
+			{FieldID: 205, Name: "out_nullable_int64", DataType: schemapb.DataType_Int64, Nullable: true, IsFunctionOutput: true},
+		},
+	}
+}
+
+func (s *ExpressionFunctionRunnerSuite) newRunner(expression string, output string, inputs ...string) (*ExpressionFunctionRunner, error) {
+	return NewExpressionFunctionRunner(s.schema, &schemapb.FunctionSchema{
+		Name:             "test",
+		Type:             FunctionTypeExpression,
+		InputFieldNames:  inputs,
+		OutputFieldNames: []string{output},
+		Params:           []*commonpb.KeyValuePair{{Key: ExpressionKey, Value: expression}},
+	})
+}
+
+func stringFieldData(name string, data []string, validData []bool) *schemapb.FieldData {
+	return &schemapb.FieldData{
+		Type:      schemapb.DataType_VarChar,
+		FieldName: name,
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: data}},
+		}},
+		ValidData: validData,
+	}
+}
+
+func (s *ExpressionFunctionRunnerSuite) TestInvalidFunction() {
+	_, err := s.newRunner("", "out_varchar", "title")
+	s.Error(err)
+
+	// unknown input or output field
+	_, err = s.newRunner("lower(title)", "out_varchar", "not_exist")
+	s.Error(err)
+	_, err = s.newRunner("lower(title)", "not_exist", "title")
+	s.Error(err)
+
+	// the expression can only refer to the input fields
+	_, err = s.newRunner("lower(title)", "out_varchar", "price")
+	s.Error(err)
+
+	// unsupported function
+	_, err = s.newRunner("reverse(title)", "out_varchar", "title")
+	s.Error(err)
+
+	// type mismatch
+	_, err = s.newRunner("lower(price)", "out_varchar", "price")
+	s.Error(err)
+	_, err = s.newRunner("lower(title)", "out_int64", "title")
+	s.Error(err)
+	_, err = s.newRunner("year(title)", "out_int64", "title")
+	s.Error(err)
+
+	// json field must be accessed by path
+	_, err = s.newRunner("meta", "out_int64", "meta")
+	s.Error(err)
+
+	err = ValidateExpressionFunction(s.schema, &schemapb.FunctionSchema{
+		Name:             "test",
+		Type:             FunctionTypeExpression,
+		InputFieldNames:  []string{"title"},
+		OutputFieldNames: []string{"out_varchar"},
+	})
+	s.Error(err)
+}
+
+func (s *ExpressionFunctionRunnerSuite) TestRunFieldsData() {
+	runner, err := s.newRunner("lower(title)", "out_varchar", "title")
+	s.NoError(err)
+	s.Equal(int64(101), runner.GetInputFields()[0].GetFieldID())
+	s.Equal(int64(200), runner.GetOutputFields()[0].GetFieldID())
+
+	output, err := runner.RunFieldsData([]*schemapb.FieldData{stringFieldData("title", []string{"Milvus", "ABC"}, nil)})
+	s.NoError(err)
+	s.Equal(int64(200), output.GetFieldId())
+	s.Equal([]string{"milvus", "abc"}, output.GetScalars().GetStringData().GetData())
+
+	// exceeds the max length of the output field
+	_, err = runner.RunFieldsData([]*schemapb.FieldData{stringFieldData("title", []string{"Hello Milvus"}, nil)})
+	s.Error(err)
+
+	// the number of inputs mismatches
+	_, err = runner.RunFieldsData(nil)
+	s.Error(err)
+
+	runner, err = s.newRunner("year(ts)", "out_int64", "ts")
+	s.NoError(err)
+	ts := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC).UnixMicro()
+	output, err = runner.RunFieldsData([]*schemapb.FieldData{{
+		Type:      schemapb.DataType_Timestamptz,
+		FieldName: "ts",
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_TimestamptzData{TimestamptzData: &schemapb.TimestamptzArray{Data: []int64{ts}}},
+		}},
+	}})
+	s.NoError(err)
+	s.Equal([]int64{2024}, output.GetScalars().GetLongData().GetData())
+
+	runner, err = s.newRunner("len(tags)", "out_int8", "tags")
+	s.NoError(err)
+	output, err = runner.RunFieldsData([]*schemapb.FieldData{{
+		Type:      schemapb.DataType_Array,
+		FieldName: "tags",
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_ArrayData{ArrayData: &schemapb.ArrayArray{
+				ElementType: schemapb.DataType_VarChar,
+				Data: []*schemapb.ScalarField{
+					{Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{"a", "b"}}}},
+					{Data: &schemapb.ScalarField_StringData{StringData: &schemapb.StringArray{Data: []string{}}}},
+				},
+			}},
+		}},
+	}})
+	s.NoError(err)
+	s.Equal([]int32{2, 0}, output.GetScalars().GetIntData().GetData())
+
+	runner, err = s.newRunner("price > 10", "out_bool", "price")
+	s.NoError(err)
+	output, err = runner.RunFieldsData([]*schemapb.FieldData{{
+		Type:      schemapb.DataType_Double,
+		FieldName: "price",
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_DoubleData{DoubleData: &schemapb.DoubleArray{Data: []float64{5, 20}}},
+		}},
+	}})
+	s.NoError(err)
+	s.Equal([]bool{false, true}, output.GetScalars().GetBoolData().GetData())
+}
+
+func (s *ExpressionFunctionRunnerSuite) TestJSONPath() {
+	runner, err := s.newRunner(`meta["a"]["b"]`, "out_nullable_int64", "meta")
+	s.NoError(err)
+	meta := &schemapb.FieldData{
+		Type:      schemapb.DataType_JSON,
+		FieldName: "meta",
+		Field: &schemapb.FieldData_Scalars{Scalars: &schemapb.ScalarField{
+			Data: &schemapb.ScalarField_JsonData{JsonData: &schemapb.JSONArray{Data: [][]byte{
+				[]byte(`{"a": {"b": 1}}`),
+				[]byte(`{"a": {}}`),
+				[]byte(`{"a": {"b": 3}}`),
+			}}},
+		}},
+	}
+	output, err := runner.RunFieldsData([]*schemapb.FieldData{meta})
+	s.NoError(err)
+	s.Equal([]int64{1, 3}, output.GetScalars().GetLongData().GetData())
+	s.Equal([]bool{true, false, true}, output.GetValidData())
+
+	// the value of the path can't be written to the output field
+	meta.GetScalars().GetJsonData().Data = [][]byte{[]byte(`{"a": {"b": "x"}}`)}
+	_, err = runner.RunFieldsData([]*schemapb.FieldData{meta})
+	s.Error(err)
+
+	// null can't be written to the non-nullable output field
+	runner, err = s.newRunner(`meta["a"]["b"]`, "out_int64", "meta")
+	s.NoError(err)
+	meta.GetScalars().GetJsonData().Data = [][]byte{[]byte(`{}`)}
+	_, err = runner.RunFieldsData([]*schemapb.FieldData{meta})
+	s.Error(err)
+}
+
+func (s *ExpressionFunctionRunnerSuite) TestNullable() {
+	runner, err := s.newRunner("upper(nullable_title)", "out_nullable_varchar", "nullable_title")
+	s.NoError(err)
+
+	// compact form, only the valid rows are in the data
+	output, err := runner.RunFieldsData([]*schemapb.FieldData{
+		stringFieldData("nullable_title", []string{"a", "c"}, []bool{true, false, true}),
+	})
+	s.NoError(err)
+	s.Equal([]string{"A", "C"}, output.GetScalars().GetStringData().GetData())
+	s.Equal([]bool{true, false, true}, output.GetValidData())
+
+	// full length form
+	output, err = runner.RunFieldsData([]*schemapb.FieldData{
+		stringFieldData("nullable_title", []string{"a", "", "c"}, []bool{true, false, true}),
+	})
+	s.NoError(err)
+	s.Equal([]string{"A", "C"}, output.GetScalars().GetStringData().GetData())
+	s.Equal([]bool{true, false, true}, output.GetValidData())
+
+	runner, err = s.newRunner(`coalesce(nullable_title, "none")`, "out_varchar", "nullable_title")
+	s.NoError(err)
+	output, err = runner.RunFieldsData([]*schemapb.FieldData{
+		stringFieldData("nullable_title", []string{"a"}, []bool{false, true}),
+	})
+	s.NoError(err)
+	s.Equal([]string{"none", "a"}, output.GetScalars().GetStringData().GetData())
+	s.Nil(output.GetValidData())
+}
+
+func (s *ExpressionFunctionRunnerSuite) TestBatchRun() {
+	runner, err := s.newRunner("lower(nullable_title)", "out_nullable_varchar", "nullable_title")
+	s.NoError(err)
+
+	input := &storage.StringFieldData{
+		Data:      []string{"A", "", "C"},
+		DataType:  schemapb.DataType_VarChar,
+		ValidData: []bool{true, false, true},
+		Nullable:  true,
+	}
+	outputs, err := runner.BatchRun(input)
+	s.NoError(err)
+	s.Equal(1, len(outputs))
+	output, ok := outputs[0].(storage.FieldData)
+	s.True(ok)
+	s.Equal(3, output.RowNum())
+	s.Equal("a", output.GetRow(0))
+	s.Nil(output.GetRow(1))
+	s.Equal("c", output.GetRow(2))
+
+	_, err = runner.BatchRun()
+	s.Error(err)
+	_, err = runner.BatchRun("not field data")
+	s.Error(err)
+}
@@ -42,6 +42,9 @@ func NewFunctionRunner(coll *schemapb.CollectionSchema, schema *schemapb.Functio
 		return NewMinHashFunctionRunner(coll, schema)
 	case schemapb.FunctionType_TextEmbedding:
 		return nil, nil
+	case FunctionTypeExpression:
+		// computed by the proxy at write time, see NewExpressionFunctionRunner for backfill
+		return nil, nil
 	default:
 		return nil, fmt.Errorf("unknown functionRunner type %s", schema.GetType().String())
 	}
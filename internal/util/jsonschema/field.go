@@ -0,0 +1,108 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+)
+
+var (
+	// the compiled schemas keyed by the type param, schemas are compiled once for each insert and filter otherwise.
+	schemaCache = expirable.NewLRU[string, *Schema](1024, nil, time.Minute*10)
+	// the compiled schemas keyed by the field, the fields of the cached collection schemas are reused by
+	// the inserts and filters, so the schema is looked up without reading the type params again.
+	fieldCache = expirable.NewLRU[*schemapb.FieldSchema, fieldSchema](1024, nil, time.Minute*10)
+)
+
+// fieldSchema is the schema compiled from the type param of a field, the param is kept
+// to tell whether the field has been updated since it's compiled.
+type fieldSchema struct {
+	key    string
+	value  string
+	schema *Schema
+}
+
+// attachedParam returns the type param of the attached schema, empty key if no schema is attached.
+func attachedParam(params []*commonpb.KeyValuePair) (key string, value string, err error) {
+	for _, param := range params {
+		switch param.GetKey() {
+		case common.JSONSchemaKey, common.JSONPathTypesKey:
+			if key != "" {
+				return "", "", errors.Newf("%s and %s can't be set at the same time", common.JSONSchemaKey, common.JSONPathTypesKey)
+			}
+			key, value = param.GetKey(), param.GetValue()
+		}
+	}
+	return key, value, nil
+}
+
+func compileParam(key string, value string) (*Schema, error) {
+	cacheKey := key + "\x00" + value
+	if s, ok := schemaCache.Get(cacheKey); ok {
+		return s, nil
+	}
+	var s *Schema
+	var err error
+	if key == common.JSONSchemaKey {
+		s, err = Compile([]byte(value))
+	} else {
+		s, err = CompilePathTypes([]byte(value))
+	}
+	if err != nil {
+		return nil, err
+	}
+	schemaCache.Add(cacheKey, s)
+	return s, nil
+}
+
+// FromParams compiles the schema attached by the type params, nil if no schema is attached.
+func FromParams(params []*commonpb.KeyValuePair) (*Schema, error) {
+	key, value, err := attachedParam(params)
+	if err != nil || key == "" {
+		return nil, err
+	}
+	return compileParam(key, value)
+}
+
+// FromField returns the schema attached to the JSON field, nil if no schema is attached.
+// The compiled schema is cached per field.
+func FromField(field *schemapb.FieldSchema) (*Schema, error) {
+	if field.GetDataType() != schemapb.DataType_JSON {
+		return nil, nil
+	}
+	key, value, err := attachedParam(field.GetTypeParams())
+	if err != nil || key == "" {
+		return nil, err
+	}
+	// the param of an unchanged field shares the memory with the cached one, so the comparison is cheap.
+	if cached, ok := fieldCache.Get(field); ok && cached.key == key && cached.value == value {
+		return cached.schema, nil
+	}
+	s, err := compileParam(key, value)
+	if err != nil {
+		return nil, err
+	}
+	fieldCache.Add(field, fieldSchema{key: key, value: value, schema: s})
+	return s, nil
+}
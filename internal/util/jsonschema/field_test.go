@@ -0,0 +1,89 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/pkg/v2/common"
+)
+
+func TestFromParams(t *testing.T) {
+	s, err := FromParams([]*commonpb.KeyValuePair{{Key: common.MaxLengthKey, Value: "10"}})
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	params := []*commonpb.KeyValuePair{{Key: common.JSONSchemaKey, Value: `{"properties": {"a": {"type": "integer"}}}`}}
+	s, err = FromParams(params)
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate([]byte(`{"a": 1}`)))
+	assert.Error(t, s.Validate([]byte(`{"a": "1"}`)))
+
+	// the compiled schema is cached
+	cached, err := FromParams(params)
+	require.NoError(t, err)
+	assert.Same(t, s, cached)
+
+	s, err = FromParams([]*commonpb.KeyValuePair{{Key: common.JSONPathTypesKey, Value: `{"a": "varchar"}`}})
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate([]byte(`{"a": "1"}`)))
+	assert.Error(t, s.Validate([]byte(`{"a": 1}`)))
+
+	_, err = FromParams([]*commonpb.KeyValuePair{{Key: common.JSONPathTypesKey, Value: `{"a": "int"}`}})
+	assert.Error(t, err)
+
+	_, err = FromParams([]*commonpb.KeyValuePair{
+		{Key: common.JSONSchemaKey, Value: `true`},
+		{Key: common.JSONPathTypesKey, Value: `{}`},
+	})
+	assert.Error(t, err)
+}
+
+func TestFromField(t *testing.T) {
+	params := []*commonpb.KeyValuePair{{Key: common.JSONPathTypesKey, Value: `{"a": "bool"}`}}
+
+	field := &schemapb.FieldSchema{DataType: schemapb.DataType_JSON, TypeParams: params}
+	s, err := FromField(field)
+	require.NoError(t, err)
+	assert.NotNil(t, s)
+
+	// the compiled schema is cached per field
+	assert.Contains(t, fieldCache.Keys(), field)
+	cached, err := FromField(field)
+	require.NoError(t, err)
+	assert.Same(t, s, cached)
+
+	// the schema is compiled again once the param of the field is updated
+	field.TypeParams = []*commonpb.KeyValuePair{{Key: common.JSONPathTypesKey, Value: `{"a": "varchar"}`}}
+	updated, err := FromField(field)
+	require.NoError(t, err)
+	assert.NotSame(t, s, updated)
+	assert.NoError(t, updated.Validate([]byte(`{"a": "1"}`)))
+
+	field.TypeParams = append(field.TypeParams, &commonpb.KeyValuePair{Key: common.JSONSchemaKey, Value: `true`})
+	_, err = FromField(field)
+	assert.Error(t, err)
+
+	s, err = FromField(&schemapb.FieldSchema{DataType: schemapb.DataType_VarChar, TypeParams: params})
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+}
@@ -0,0 +1,528 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonschema validates the documents of a JSON field against the schema attached to the field,
+// and resolves the types declared for the JSON paths used in filters.
+//
+// The schema is attached by the "json_schema" type param as a JSON Schema document,
+// or by the "json_path_types" type param as a simplified map from the dotted paths to the types, such as
+// {"price": "double", "attrs.color": "varchar", "tags": "array_varchar"}.
+//
+// Only a subset of JSON Schema is supported: type, properties, required, additionalProperties, items,
+// enum, const, minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength, minItems, maxItems
+// and pattern, along with the annotations. Other keywords are rejected rather than silently ignored.
+// Unlike JSON Schema, an integer must be written without fraction or exponent, as it's stored in the document.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+// The types of JSON Schema.
+const (
+	TypeNull    = "null"
+	TypeBoolean = "boolean"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeString  = "string"
+	TypeArray   = "array"
+	TypeObject  = "object"
+)
+
+var allTypes = []string{TypeNull, TypeBoolean, TypeInteger, TypeNumber, TypeString, TypeArray, TypeObject}
+
+// the keywords carrying no constraint.
+var annotations = []string{"$schema", "$id", "$comment", "title", "description", "default", "examples", "deprecated", "readOnly", "writeOnly"}
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	// never is true for the false schema which matches nothing.
+	never bool
+
+	Types                []string
+	Properties           map[string]*Schema
+	Required             []string
+	AdditionalProperties *Schema
+	Items                *Schema
+	Enum                 []any
+	Minimum              *float64
+	Maximum              *float64
+	ExclusiveMinimum     *float64
+	ExclusiveMaximum     *float64
+	MinLength            *int
+	MaxLength            *int
+	MinItems             *int
+	MaxItems             *int
+	Pattern              *regexp.Regexp
+}
+
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the top-level value")
+	}
+	return value, nil
+}
+
+// Compile compiles the JSON Schema document.
+func Compile(data []byte) (*Schema, error) {
+	value, err := decode(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid json schema")
+	}
+	return compile(value, "")
+}
+
+func compile(value any, path string) (*Schema, error) {
+	if b, ok := value.(bool); ok {
+		return &Schema{never: !b}, nil
+	}
+	keywords, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.Newf("schema%s must be an object or a boolean", path)
+	}
+
+	s := &Schema{}
+	for keyword, v := range keywords {
+		var err error
+		switch keyword {
+		case "type":
+			s.Types, err = compileTypes(v)
+		case "properties":
+			props, ok := v.(map[string]any)
+			if !ok {
+				return nil, errors.Newf("properties of schema%s must be an object", path)
+			}
+			s.Properties = make(map[string]*Schema, len(props))
+			for name, prop := range props {
+				if s.Properties[name], err = compile(prop, path+"["+strconv.Quote(name)+"]"); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			s.Required, err = compileStrings(v)
+		case "additionalProperties":
+			s.AdditionalProperties, err = compile(v, path+"[*]")
+		case "items":
+			s.Items, err = compile(v, path+"[*]")
+		case "enum":
+			values, ok := v.([]any)
+			if !ok {
+				return nil, errors.Newf("enum of schema%s must be an array", path)
+			}
+			s.Enum = values
+		case "const":
+			s.Enum = []any{v}
+		case "minimum":
+			s.Minimum, err = compileNumber(v)
+		case "maximum":
+			s.Maximum, err = compileNumber(v)
+		case "exclusiveMinimum":
+			s.ExclusiveMinimum, err = compileNumber(v)
+		case "exclusiveMaximum":
+			s.ExclusiveMaximum, err = compileNumber(v)
+		case "minLength":
+			s.MinLength, err = compileCount(v)
+		case "maxLength":
+			s.MaxLength, err = compileCount(v)
+		case "minItems":
+			s.MinItems, err = compileCount(v)
+		case "maxItems":
+			s.MaxItems, err = compileCount(v)
+		case "pattern":
+			pattern, ok := v.(string)
+			if !ok {
+				return nil, errors.Newf("pattern of schema%s must be a string", path)
+			}
+			s.Pattern, err = regexp.Compile(pattern)
+		default:
+			if !lo.Contains(annotations, keyword) {
+				return nil, errors.Newf("unsupported keyword %s in schema%s", keyword, path)
+			}
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s of schema%s", keyword, path)
+		}
+	}
+	return s, nil
+}
+
+func compileTypes(value any) ([]string, error) {
+	var types []string
+	switch v := value.(type) {
+	case string:
+		types = []string{v}
+	case []any:
+		var err error
+		if types, err = compileStrings(v); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("must be a string or an array of strings")
+	}
+	for _, t := range types {
+		if !lo.Contains(allTypes, t) {
+			return nil, errors.Newf("unknown type %s", t)
+		}
+	}
+	return types, nil
+}
+
+func compileStrings(value any) ([]string, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, errors.New("must be an array of strings")
+	}
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be an array of strings")
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func compileNumber(value any) (*float64, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return nil, errors.New("must be a number")
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func compileCount(value any) (*int, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return nil, errors.New("must be a non-negative integer")
+	}
+	i, err := strconv.Atoi(n.String())
+	if err != nil || i < 0 {
+		return nil, errors.New("must be a non-negative integer")
+	}
+	return &i, nil
+}
+
+// The types of the simplified typed-path map, named after the json_cast_type of the JSON path index.
+var pathTypes = map[string]*Schema{
+	"bool":          {Types: []string{TypeBoolean, TypeNull}},
+	"int64":         {Types: []string{TypeInteger, TypeNull}},
+	"double":        {Types: []string{TypeNumber, TypeNull}},
+	"varchar":       {Types: []string{TypeString, TypeNull}},
+	"array":         {Types: []string{TypeArray, TypeNull}},
+	"object":        {Types: []string{TypeObject, TypeNull}},
+	"array_bool":    {Types: []string{TypeArray, TypeNull}, Items: &Schema{Types: []string{TypeBoolean}}},
+	"array_int64":   {Types: []string{TypeArray, TypeNull}, Items: &Schema{Types: []string{TypeInteger}}},
+	"array_double":  {Types: []string{TypeArray, TypeNull}, Items: &Schema{Types: []string{TypeNumber}}},
+	"array_varchar": {Types: []string{TypeArray, TypeNull}, Items: &Schema{Types: []string{TypeString}}},
+}
+
+// CompilePathTypes compiles the simplified typed-path map into a schema.
+// The paths are separated by dots, the values of the paths may be null or absent.
+func CompilePathTypes(data []byte) (*Schema, error) {
+	paths := make(map[string]string)
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return nil, errors.Wrap(err, "json path types must be an object mapping the paths to the types")
+	}
+	root := &Schema{Types: []string{TypeObject}, Properties: make(map[string]*Schema)}
+	for path, typeName := range paths {
+		declared, ok := pathTypes[strings.ToLower(strings.TrimSpace(typeName))]
+		if !ok {
+			return nil, errors.Newf("unknown type %s of json path %s, supported types: %s",
+				typeName, path, strings.Join(lo.Keys(pathTypes), ", "))
+		}
+		keys := strings.Split(path, ".")
+		parent := root
+		for i, key := range keys {
+			if key == "" {
+				return nil, errors.Newf("invalid json path %s", path)
+			}
+			child, ok := parent.Properties[key]
+			if i == len(keys)-1 {
+				if ok {
+					return nil, errors.Newf("json path %s conflicts with its nested paths", path)
+				}
+				parent.Properties[key] = declared
+				break
+			}
+			if !ok {
+				child = &Schema{Types: []string{TypeObject, TypeNull}, Properties: make(map[string]*Schema)}
+				parent.Properties[key] = child
+			} else if child.Properties == nil {
+				return nil, errors.Newf("json path %s conflicts with the declared type of %s", path, strings.Join(keys[:i+1], "."))
+			}
+			parent = child
+		}
+	}
+	return root, nil
+}
+
+// Validate validates the JSON document against the schema.
+func (s *Schema) Validate(data []byte) error {
+	value, err := decode(data)
+	if err != nil {
+		return errors.Wrap(err, "invalid json")
+	}
+	return s.validate(value, "")
+}
+
+func typeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return TypeNull
+	case bool:
+		return TypeBoolean
+	case json.Number:
+		if _, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return TypeInteger
+		}
+		return TypeNumber
+	case string:
+		return TypeString
+	case []any:
+		return TypeArray
+	default:
+		return TypeObject
+	}
+}
+
+func (s *Schema) matchType(value any) bool {
+	if len(s.Types) == 0 {
+		return true
+	}
+	t := typeOf(value)
+	for _, expected := range s.Types {
+		if expected == t || (expected == TypeNumber && t == TypeInteger) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) validate(value any, path string) error {
+	if s.never {
+		return errors.Newf("%s is not allowed", displayPath(path))
+	}
+	if !s.matchType(value) {
+		return errors.Newf("%s must be %s, got %s", displayPath(path), strings.Join(s.Types, " or "), typeOf(value))
+	}
+	if len(s.Enum) > 0 && !lo.ContainsBy(s.Enum, func(e any) bool { return equal(e, value) }) {
+		return errors.Newf("%s must be one of the enum values", displayPath(path))
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		return s.validateNumber(v, path)
+	case string:
+		return s.validateString(v, path)
+	case []any:
+		return s.validateArray(v, path)
+	case map[string]any:
+		return s.validateObject(v, path)
+	}
+	return nil
+}
+
+func (s *Schema) validateNumber(value json.Number, path string) error {
+	f, err := value.Float64()
+	if err != nil {
+		return errors.Wrapf(err, "%s is not a valid number", displayPath(path))
+	}
+	if s.Minimum != nil && f < *s.Minimum {
+		return errors.Newf("%s must be >= %v, got %s", displayPath(path), *s.Minimum, value)
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		return errors.Newf("%s must be <= %v, got %s", displayPath(path), *s.Maximum, value)
+	}
+	if s.ExclusiveMinimum != nil && f <= *s.ExclusiveMinimum {
+		return errors.Newf("%s must be > %v, got %s", displayPath(path), *s.ExclusiveMinimum, value)
+	}
+	if s.ExclusiveMaximum != nil && f >= *s.ExclusiveMaximum {
+		return errors.Newf("%s must be < %v, got %s", displayPath(path), *s.ExclusiveMaximum, value)
+	}
+	return nil
+}
+
+func (s *Schema) validateString(value string, path string) error {
+	length := utf8.RuneCountInString(value)
+	if s.MinLength != nil && length < *s.MinLength {
+		return errors.Newf("the length of %s must be >= %d, got %d", displayPath(path), *s.MinLength, length)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		return errors.Newf("the length of %s must be <= %d, got %d", displayPath(path), *s.MaxLength, length)
+	}
+	if s.Pattern != nil && !s.Pattern.MatchString(value) {
+		return errors.Newf("%s must match the pattern %s", displayPath(path), s.Pattern.String())
+	}
+	return nil
+}
+
+func (s *Schema) validateArray(value []any, path string) error {
+	if s.MinItems != nil && len(value) < *s.MinItems {
+		return errors.Newf("%s must have at least %d items, got %d", displayPath(path), *s.MinItems, len(value))
+	}
+	if s.MaxItems != nil && len(value) > *s.MaxItems {
+		return errors.Newf("%s must have at most %d items, got %d", displayPath(path), *s.MaxItems, len(value))
+	}
+	if s.Items == nil {
+		return nil
+	}
+	for i, item := range value {
+		if err := s.Items.validate(item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(value map[string]any, path string) error {
+	for _, name := range s.Required {
+		if _, ok := value[name]; !ok {
+			return errors.Newf("%s is required", displayPath(path+"["+strconv.Quote(name)+"]"))
+		}
+	}
+	for name, v := range value {
+		prop, ok := s.Properties[name]
+		if !ok {
+			prop = s.AdditionalProperties
+		}
+		if prop == nil {
+			continue
+		}
+		if err := prop.validate(v, path+"["+strconv.Quote(name)+"]"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "the json document"
+	}
+	return "json path " + path
+}
+
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		bn, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, err1 := a.Float64()
+		bf, err2 := bn.Float64()
+		return err1 == nil && err2 == nil && (af == bf || (math.IsNaN(af) && math.IsNaN(bf)))
+	case []any:
+		bs, ok := b.([]any)
+		if !ok || len(a) != len(bs) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], bs[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bm, ok := b.(map[string]any)
+		if !ok || len(a) != len(bm) {
+			return false
+		}
+		for k, v := range a {
+			if bv, ok := bm[k]; !ok || !equal(v, bv) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// Lookup returns the schema declared for the nested path, nil if the path isn't declared.
+// The integer keys of the path index into the arrays.
+func (s *Schema) Lookup(path []string) *Schema {
+	current := s
+	for _, key := range path {
+		if current == nil || current.never {
+			return nil
+		}
+		if prop, ok := current.Properties[key]; ok {
+			current = prop
+			continue
+		}
+		if _, err := strconv.Atoi(key); err == nil && current.Items != nil {
+			current = current.Items
+			continue
+		}
+		current = current.AdditionalProperties
+	}
+	return current
+}
+
+// DataType returns the milvus data type declared by the schema,
+// false if the schema declares no type or several types besides null.
+// Objects are reported as JSON.
+func (s *Schema) DataType() (schemapb.DataType, bool) {
+	if s == nil {
+		return schemapb.DataType_None, false
+	}
+	types := lo.Without(s.Types, TypeNull)
+	if len(types) == 2 && lo.Contains(types, TypeInteger) && lo.Contains(types, TypeNumber) {
+		types = []string{TypeNumber}
+	}
+	if len(types) != 1 {
+		return schemapb.DataType_None, false
+	}
+	switch types[0] {
+	case TypeBoolean:
+		return schemapb.DataType_Bool, true
+	case TypeInteger:
+		return schemapb.DataType_Int64, true
+	case TypeNumber:
+		return schemapb.DataType_Double, true
+	case TypeString:
+		return schemapb.DataType_VarChar, true
+	case TypeArray:
+		return schemapb.DataType_Array, true
+	case TypeObject:
+		return schemapb.DataType_JSON, true
+	default:
+		return schemapb.DataType_None, false
+	}
+}
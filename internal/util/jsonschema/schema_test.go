@@ -0,0 +1,178 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+)
+
+const testSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "product",
+	"type": "object",
+	"required": ["price"],
+	"properties": {
+		"price": {"type": "number", "minimum": 0},
+		"stock": {"type": ["integer", "null"], "maximum": 1000},
+		"color": {"enum": ["red", "green"]},
+		"code": {"type": "string", "minLength": 2, "maxLength": 4, "pattern": "^[A-Z]+$"},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+		"dims": {"type": "object", "properties": {"w": {"type": "integer"}}, "additionalProperties": false}
+	},
+	"additionalProperties": {"type": ["string", "boolean"]}
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Compile([]byte(testSchema))
+	require.NoError(t, err)
+
+	valid := []string{
+		`{"price": 1}`,
+		`{"price": 1.5, "stock": null, "color": "red", "code": "AB"}`,
+		`{"price": 0, "stock": 1000, "tags": ["a", "b"], "dims": {"w": 3}}`,
+		`{"price": 2, "note": "additional", "flag": true}`,
+	}
+	for _, doc := range valid {
+		assert.NoError(t, s.Validate([]byte(doc)), doc)
+	}
+
+	invalid := map[string]string{
+		`{"stock": 1}`:                          `json path ["price"] is required`,
+		`{"price": "1"}`:                        `json path ["price"] must be number, got string`,
+		`{"price": -1}`:                         `json path ["price"] must be >= 0, got -1`,
+		`{"price": 1, "stock": 1.0}`:            `json path ["stock"] must be integer or null, got number`,
+		`{"price": 1, "stock": 1001}`:           `json path ["stock"] must be <= 1000, got 1001`,
+		`{"price": 1, "color": "blue"}`:         `json path ["color"] must be one of the enum values`,
+		`{"price": 1, "code": "A"}`:             `the length of json path ["code"] must be >= 2, got 1`,
+		`{"price": 1, "code": "ABCDE"}`:         `the length of json path ["code"] must be <= 4, got 5`,
+		`{"price": 1, "code": "ab"}`:            `json path ["code"] must match the pattern ^[A-Z]+$`,
+		`{"price": 1, "tags": ["a", 1]}`:        `json path ["tags"][1] must be string, got integer`,
+		`{"price": 1, "tags": ["a", "b", "c"]}`: `json path ["tags"] must have at most 2 items, got 3`,
+		`{"price": 1, "dims": {"h": 1}}`:        `json path ["dims"]["h"] is not allowed`,
+		`{"price": 1, "note": 1}`:               `json path ["note"] must be string or boolean, got integer`,
+		`[1]`:                                   `the json document must be object, got array`,
+		`{"price": 1`:                           `invalid json`,
+		`{"price": 1} {}`:                       `invalid json`,
+	}
+	for doc, msg := range invalid {
+		err := s.Validate([]byte(doc))
+		if assert.Error(t, err, doc) {
+			assert.Contains(t, err.Error(), msg, doc)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	s, err := Compile([]byte(`true`))
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate([]byte(`{"a": [1, "b"]}`)))
+
+	s, err = Compile([]byte(`{"const": {"a": [1, 2.0]}}`))
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate([]byte(`{"a": [1.0, 2]}`)))
+	assert.Error(t, s.Validate([]byte(`{"a": [1, 2], "b": 1}`)))
+
+	invalid := []string{
+		`[]`,
+		`{"type": "int"}`,
+		`{"type": 1}`,
+		`{"properties": []}`,
+		`{"properties": {"a": 1}}`,
+		`{"required": [1]}`,
+		`{"items": "string"}`,
+		`{"enum": 1}`,
+		`{"minimum": "1"}`,
+		`{"maxLength": -1}`,
+		`{"maxItems": 1.5}`,
+		`{"pattern": "("}`,
+		`{"oneOf": [{"type": "string"}]}`,
+		`{"$ref": "#/definitions/a"}`,
+		`{`,
+	}
+	for _, schema := range invalid {
+		_, err := Compile([]byte(schema))
+		assert.Error(t, err, schema)
+	}
+}
+
+func TestCompilePathTypes(t *testing.T) {
+	s, err := CompilePathTypes([]byte(`{"price": "DOUBLE", "attrs.color": "varchar", "attrs.size.w": "int64", "tags": "array_varchar", "active": "bool"}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, s.Validate([]byte(`{"price": 1, "attrs": {"color": "red", "size": {"w": 1}}, "tags": ["a"], "active": true, "other": 1}`)))
+	// the declared paths may be null or absent
+	assert.NoError(t, s.Validate([]byte(`{"price": null, "attrs": null}`)))
+	assert.NoError(t, s.Validate([]byte(`{}`)))
+	assert.Error(t, s.Validate([]byte(`{"price": "1"}`)))
+	assert.Error(t, s.Validate([]byte(`{"attrs": {"size": {"w": 1.5}}}`)))
+	assert.Error(t, s.Validate([]byte(`{"tags": [1]}`)))
+	assert.Error(t, s.Validate([]byte(`{"attrs": "red"}`)))
+
+	invalid := []string{
+		`[]`,
+		`{"price": 1}`,
+		`{"price": "float"}`,
+		`{"a..b": "bool"}`,
+		`{"a": "bool", "a.b": "bool"}`,
+		`{"a.b": "bool", "a.b.c": "bool"}`,
+	}
+	for _, types := range invalid {
+		_, err := CompilePathTypes([]byte(types))
+		assert.Error(t, err, types)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	s, err := Compile([]byte(testSchema))
+	require.NoError(t, err)
+
+	cases := []struct {
+		path     []string
+		dataType schemapb.DataType
+		declared bool
+	}{
+		{[]string{"price"}, schemapb.DataType_Double, true},
+		{[]string{"stock"}, schemapb.DataType_Int64, true},
+		{[]string{"code"}, schemapb.DataType_VarChar, true},
+		{[]string{"tags"}, schemapb.DataType_Array, true},
+		{[]string{"tags", "0"}, schemapb.DataType_VarChar, true},
+		{[]string{"dims"}, schemapb.DataType_JSON, true},
+		{[]string{"dims", "w"}, schemapb.DataType_Int64, true},
+		{[]string{"dims", "h"}, schemapb.DataType_None, false},
+		{[]string{"dims", "w", "x"}, schemapb.DataType_None, false},
+		// enum without type
+		{[]string{"color"}, schemapb.DataType_None, false},
+		// several types by the additional properties
+		{[]string{"note"}, schemapb.DataType_None, false},
+	}
+	for _, c := range cases {
+		dataType, ok := s.Lookup(c.path).DataType()
+		assert.Equal(t, c.declared, ok, c.path)
+		assert.Equal(t, c.dataType, dataType, c.path)
+	}
+
+	s, err = Compile([]byte(`{"properties": {"n": {"type": ["integer", "number"]}}}`))
+	require.NoError(t, err)
+	dataType, ok := s.Lookup([]string{"n"}).DataType()
+	assert.True(t, ok)
+	assert.Equal(t, schemapb.DataType_Double, dataType)
+}
@@ -24,8 +24,15 @@ func newScheduler(policy schedulePolicy) Scheduler {
 	maxReadConcurrency := paramtable.Get().QueryNodeCfg.MaxReadConcurrency.GetAsInt()
 	maxReceiveChanSize := paramtable.Get().QueryNodeCfg.MaxReceiveChanSize.GetAsInt()
 	log.Info("query node use concurrent safe scheduler", zap.Int("max_concurrency", maxReadConcurrency))
+	observer, _ := policy.(taskDoneObserver)
+	var notify <-chan struct{}
+	if observer != nil {
+		notify = observer.Notify()
+	}
 	return &scheduler{
 		policy:           policy,
+		observer:         observer,
+		notify:           notify,
 		receiveChan:      make(chan addTaskReq, maxReceiveChanSize),
 		execChan:         make(chan Task),
 		pool:             conc.NewPool[any](maxReadConcurrency, conc.WithPreAlloc(true)),
@@ -43,6 +50,8 @@ type addTaskReq struct {
 // scheduler is a general concurrent safe scheduler implementation by wrapping a schedule policy.
 type scheduler struct {
 	policy      schedulePolicy
+	observer    taskDoneObserver
+	notify      <-chan struct{}
 	receiveChan chan addTaskReq
 	execChan    chan Task
 	pool        *conc.Pool[any]
@@ -126,10 +135,19 @@ func (s *scheduler) schedule() {
 			if !ok {
 				log.Info("receiveChan closed, processing remaining request")
 				// drain policy maintained task
-				for task != nil {
+				for {
+					task, nq, execChan = s.setupExecListener(task)
+					if task == nil && s.notify != nil && s.policy.Len() > 0 {
+						// the remaining tasks are held back by the policy, wait for the running ones.
+						<-s.notify
+						continue
+					}
+					if task == nil {
+						break
+					}
 					execChan <- task
 					s.updateWaitingTaskCounter(-1, -nq)
-					task = s.produceExecChan()
+					task = nil
 				}
 				log.Info("all task put into exeChan, schedule worker exit")
 				close(s.execChan)
@@ -144,6 +162,8 @@ func (s *scheduler) schedule() {
 			s.updateWaitingTaskCounter(-1, -nq)
 			// And produce new task into execChan as much as possible.
 			task = s.produceExecChan()
+		case <-s.notify:
+			// A running task finished, the tasks held back by the policy may be ready now.
 		}
 	}
 }
@@ -225,12 +245,12 @@ func (s *scheduler) exec() {
 		// Skip this task if task is canceled.
 		if err := t.Canceled(); err != nil {
 			log.Warn("task canceled before executing", zap.Error(err))
-			t.Done(err)
+			s.done(t, err)
 			continue
 		}
 		if err := t.PreExecute(); err != nil {
 			log.Warn("failed to pre-execute task", zap.Error(err))
-			t.Done(err)
+			s.done(t, err)
 			continue
 		}
 
@@ -246,12 +266,20 @@ func (s *scheduler) exec() {
 			collector.Counter.Dec(metricsinfo.ExecuteQueueType)
 
 			// Notify task done.
-			t.Done(err)
+			s.done(t, err)
 			return nil, err
 		})
 	}
 }
 
+// done notify the task finished and release it from the policy.
+func (s *scheduler) done(t Task, err error) {
+	t.Done(err)
+	if s.observer != nil {
+		s.observer.OnTaskDone(t)
+	}
+}
+
 func (s *scheduler) getPool(t Task) *conc.Pool[any] {
 	if t.IsGpuIndex() {
 		return s.gpuPool
@@ -24,6 +24,12 @@ func TestScheduler(t *testing.T) {
 	t.Run("fifo", func(t *testing.T) {
 		testScheduler(t, newFIFOPolicy())
 	})
+	t.Run("priority-class", func(t *testing.T) {
+		params := paramtable.Get()
+		params.Save(params.QueryNodeCfg.SchedulePolicyPriorityClassMaxInflight.Key, "default:2")
+		defer params.Reset(params.QueryNodeCfg.SchedulePolicyPriorityClassMaxInflight.Key)
+		testScheduler(t, newPriorityClassPolicy())
+	})
 	t.Run("scheduler_not_working", func(t *testing.T) {
 		scheduler := newScheduler(newFIFOPolicy())
 
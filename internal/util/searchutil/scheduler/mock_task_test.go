@@ -19,6 +19,7 @@ type mockTaskConfig struct {
 	mergeAble   bool
 	nq          int64
 	username    string
+	class       string
 	executeCost time.Duration
 	execution   func(ctx context.Context) error
 }
@@ -40,6 +41,7 @@ func newMockTask(c mockTaskConfig) Task {
 		mergeAble:   c.mergeAble,
 		nq:          c.nq,
 		username:    c.username,
+		class:       c.class,
 		execution:   c.execution,
 		tr:          timerecord.NewTimeRecorderWithTrace(c.ctx, "searchTask"),
 	}
@@ -52,6 +54,7 @@ type MockTask struct {
 	mergeAble   bool
 	nq          int64
 	username    string
+	class       string
 	execution   func(ctx context.Context) error
 	tr          *timerecord.TimeRecorder
 }
@@ -65,6 +68,10 @@ func (t *MockTask) Username() string {
 	return t.username
 }
 
+func (t *MockTask) PriorityClass() string {
+	return t.class
+}
+
 func (t *MockTask) IsGpuIndex() bool {
 	return false
 }
@@ -20,6 +20,81 @@ func TestFIFOPolicy(t *testing.T) {
 	testCommonPolicyOperation(t, newFIFOPolicy())
 }
 
+func TestPriorityClassPolicy(t *testing.T) {
+	paramtable.Init()
+	testCommonPolicyOperation(t, newPriorityClassPolicy())
+	testCrossUserMerge(t, newPriorityClassPolicy())
+
+	t.Run("weighted fair queuing", func(t *testing.T) {
+		params := paramtable.Get()
+		params.Save(params.QueryNodeCfg.SchedulePolicyPriorityClassWeights.Key, "interactive:3,batch:1")
+		defer params.Reset(params.QueryNodeCfg.SchedulePolicyPriorityClassWeights.Key)
+
+		policy := newPriorityClassPolicy()
+		n := 8
+		for _, class := range []string{"batch", "interactive"} {
+			for i := 0; i < n; i++ {
+				_, err := policy.Push(newMockTask(mockTaskConfig{username: "user", class: class}))
+				assert.NoError(t, err)
+			}
+		}
+		popped := make(map[string]int)
+		for i := 0; i < n; i++ {
+			task := policy.Pop()
+			assert.NotNil(t, task)
+			popped[task.PriorityClass()]++
+			policy.OnTaskDone(task)
+		}
+		assert.Equal(t, 6, popped["interactive"])
+		assert.Equal(t, 2, popped["batch"])
+		assert.Equal(t, n, policy.Len())
+	})
+
+	t.Run("unknown class", func(t *testing.T) {
+		policy := newPriorityClassPolicy()
+		_, err := policy.Push(newMockTask(mockTaskConfig{class: "unknown"}))
+		assert.NoError(t, err)
+		assert.Contains(t, policy.classes, paramtable.Get().QueryNodeCfg.SchedulePolicyDefaultPriorityClass.GetValue())
+		assert.NotContains(t, policy.classes, "unknown")
+	})
+
+	t.Run("max inflight", func(t *testing.T) {
+		params := paramtable.Get()
+		params.Save(params.QueryNodeCfg.SchedulePolicyPriorityClassMaxInflight.Key, "batch:1")
+		defer params.Reset(params.QueryNodeCfg.SchedulePolicyPriorityClassMaxInflight.Key)
+
+		policy := newPriorityClassPolicy()
+		for i := 0; i < 2; i++ {
+			_, err := policy.Push(newMockTask(mockTaskConfig{class: "batch"}))
+			assert.NoError(t, err)
+		}
+		task := policy.Pop()
+		assert.NotNil(t, task)
+		assert.Nil(t, policy.Pop())
+		assert.Equal(t, 1, policy.Len())
+
+		// other classes are not held back
+		_, err := policy.Push(newMockTask(mockTaskConfig{class: "interactive"}))
+		assert.NoError(t, err)
+		assert.Equal(t, "interactive", policy.Pop().PriorityClass())
+
+		policy.OnTaskDone(task)
+		select {
+		case <-policy.Notify():
+		default:
+			t.Fatal("policy should be notified when a task is done")
+		}
+		assert.NotNil(t, policy.Pop())
+		assert.Equal(t, 0, policy.Len())
+	})
+}
+
+func TestParsePriorityClassValues(t *testing.T) {
+	values := parsePriorityClassValues("interactive:8, batch : 1,invalid,negative:-1,nan:x")
+	assert.Equal(t, map[string]int64{"interactive": 8, "batch": 1}, values)
+	assert.Empty(t, parsePriorityClassValues(""))
+}
+
 func testCrossUserMerge(t *testing.T, policy schedulePolicy) {
 	userN := 10
 	maxNQ := paramtable.Get().QueryNodeCfg.MaxGroupNQ.GetAsInt64()
@@ -0,0 +1,211 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+var (
+	_ schedulePolicy   = &priorityClassPolicy{}
+	_ taskDoneObserver = &priorityClassPolicy{}
+)
+
+// newPriorityClassPolicy create a new priority class schedule policy.
+func newPriorityClassPolicy() *priorityClassPolicy {
+	return &priorityClassPolicy{
+		classes:     make(map[string]*priorityClass),
+		enqueueTime: make(map[Task]time.Time),
+		running:     typeutil.NewConcurrentMap[Task, *priorityClass](),
+		notify:      make(chan struct{}, 1),
+	}
+}
+
+// priorityClass holds the pending tasks of a priority class.
+type priorityClass struct {
+	name  string
+	queue *fairPollingTaskQueue
+	// pass is the virtual time of the class, the pending class with the smallest pass is scheduled next,
+	// and the pass grows by 1/weight on every pop, so classes are scheduled in proportion to their weights.
+	pass    float64
+	running atomic.Int64
+}
+
+// priorityClassPolicy is a weighted fair queuing schedule policy across priority classes.
+// Tasks of a class are polled by user like userTaskPollingPolicy,
+// and a class reaching its max in-flight limit is held back until its running tasks finish.
+type priorityClassPolicy struct {
+	classes     map[string]*priorityClass
+	enqueueTime map[Task]time.Time
+	count       int
+	// vtime is the pass of the class popped last, a class becoming pending starts from it
+	// instead of the credit accumulated while it was idle.
+	vtime float64
+
+	running *typeutil.ConcurrentMap[Task, *priorityClass]
+	notify  chan struct{}
+}
+
+// Push add a new task into scheduler, an error will be returned if scheduler reaches some limit.
+func (p *priorityClassPolicy) Push(task Task) (int, error) {
+	pt := paramtable.Get()
+	class := p.getClass(p.classOf(task, parsePriorityClassValues(pt.QueryNodeCfg.SchedulePolicyPriorityClassWeights.GetValue())))
+	username := task.Username()
+
+	// Try to merge task if task is mergeable, only tasks of the same class are merged.
+	if t := tryIntoMergeTask(task); t != nil {
+		maxNQ := pt.QueryNodeCfg.MaxGroupNQ.GetAsInt64()
+		if class.queue.tryMergeWithSameGroup(username, t, maxNQ) {
+			return 0, nil
+		}
+		enableCrossGroupMerge := pt.QueryNodeCfg.SchedulePolicyEnableCrossUserGrouping.GetAsBool()
+		if enableCrossGroupMerge && class.queue.tryMergeWithOtherGroup(username, t, maxNQ) {
+			return 0, nil
+		}
+	}
+
+	// Check if length of user queue is greater than limit.
+	taskGroupLen := class.queue.groupLen(username)
+	if taskGroupLen > 0 {
+		limit := pt.QueryNodeCfg.SchedulePolicyMaxPendingTaskPerUser.GetAsInt()
+		if limit > 0 && taskGroupLen >= limit {
+			return 0, merr.WrapErrTooManyRequests(
+				int32(limit),
+				fmt.Sprintf("limit by %s", pt.QueryNodeCfg.SchedulePolicyMaxPendingTaskPerUser.Key),
+			)
+		}
+	}
+
+	if class.queue.len() == 0 && class.pass < p.vtime {
+		class.pass = p.vtime
+	}
+	class.queue.push(username, task)
+	p.enqueueTime[task] = time.Now()
+	p.count++
+	return 1, nil
+}
+
+// Pop get the task next ready to run.
+// Return nil if there's no task or all the pending classes reach their max in-flight limit.
+func (p *priorityClassPolicy) Pop() Task {
+	if p.count == 0 {
+		return nil
+	}
+	pt := paramtable.Get()
+	weights := parsePriorityClassValues(pt.QueryNodeCfg.SchedulePolicyPriorityClassWeights.GetValue())
+	maxInflight := parsePriorityClassValues(pt.QueryNodeCfg.SchedulePolicyPriorityClassMaxInflight.GetValue())
+
+	var next *priorityClass
+	for _, class := range p.classes {
+		if class.queue.len() == 0 {
+			continue
+		}
+		if limit := maxInflight[class.name]; limit > 0 && class.running.Load() >= limit {
+			continue
+		}
+		if next == nil || class.pass < next.pass || (class.pass == next.pass && class.name < next.name) {
+			next = class
+		}
+	}
+	if next == nil {
+		return nil
+	}
+
+	expire := pt.QueryNodeCfg.SchedulePolicyTaskQueueExpire.GetAsDuration(time.Second)
+	task := next.queue.pop(expire)
+	p.count--
+	p.vtime = next.pass
+	weight := weights[next.name]
+	if weight <= 0 {
+		weight = 1
+	}
+	next.pass += 1 / float64(weight)
+
+	nodeID := paramtable.GetStringNodeID()
+	if enqueueTime, ok := p.enqueueTime[task]; ok {
+		delete(p.enqueueTime, task)
+		metrics.QueryNodeReadTaskQueueWaitLatency.WithLabelValues(nodeID, next.name).
+			Observe(float64(time.Since(enqueueTime).Microseconds()) / 1000)
+	}
+	p.running.Insert(task, next)
+	metrics.QueryNodeReadTaskInflight.WithLabelValues(nodeID, next.name).Set(float64(next.running.Inc()))
+	return task
+}
+
+// Len get ready task counts.
+func (p *priorityClassPolicy) Len() int {
+	return p.count
+}
+
+// OnTaskDone release the in-flight quota of the class which task is belong to.
+func (p *priorityClassPolicy) OnTaskDone(task Task) {
+	class, ok := p.running.GetAndRemove(task)
+	if !ok {
+		return
+	}
+	running := class.running.Dec()
+	metrics.QueryNodeReadTaskInflight.WithLabelValues(paramtable.GetStringNodeID(), class.name).Set(float64(running))
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Notify returns a channel signaled when a running task finished.
+func (p *priorityClassPolicy) Notify() <-chan struct{} {
+	return p.notify
+}
+
+// classOf returns the class name of the task,
+// tasks without a class or with a class not configured are put into the default class.
+func (p *priorityClassPolicy) classOf(task Task, weights map[string]int64) string {
+	name := task.PriorityClass()
+	if _, ok := weights[name]; ok {
+		return name
+	}
+	return paramtable.Get().QueryNodeCfg.SchedulePolicyDefaultPriorityClass.GetValue()
+}
+
+func (p *priorityClassPolicy) getClass(name string) *priorityClass {
+	class, ok := p.classes[name]
+	if !ok {
+		class = &priorityClass{
+			name:  name,
+			queue: newFairPollingTaskQueue(),
+			pass:  p.vtime,
+		}
+		p.classes[name] = class
+	}
+	return class
+}
+
+// parsePriorityClassValues parses the config in the format of "class1:value1,class2:value2",
+// invalid entries are ignored.
+func parsePriorityClassValues(config string) map[string]int64 {
+	values := make(map[string]int64)
+	for _, item := range paramtable.ParseAsStings(config) {
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, ":")
+		if ok {
+			v, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err == nil && v >= 0 {
+				values[strings.TrimSpace(name)] = v
+				continue
+			}
+		}
+		log.RatedWarn(60, "invalid priority class config item", zap.String("config", config), zap.String("item", item))
+	}
+	return values
+}
@@ -5,6 +5,7 @@ import "github.com/milvus-io/milvus/pkg/v2/proto/internalpb"
 const (
 	schedulePolicyNameFIFO            = "fifo"
 	schedulePolicyNameUserTaskPolling = "user-task-polling"
+	schedulePolicyNamePriorityClass   = "priority-class"
 )
 
 // NewScheduler create a scheduler by policyName.
@@ -20,6 +21,10 @@ func NewScheduler(policyName string) Scheduler {
 		return newScheduler(
 			newUserTaskPollingPolicy(),
 		)
+	case schedulePolicyNamePriorityClass:
+		return newScheduler(
+			newPriorityClassPolicy(),
+		)
 	default:
 		panic("invalid schedule task policy")
 	}
@@ -69,6 +74,16 @@ type schedulePolicy interface {
 	Len() int
 }
 
+// taskDoneObserver is implemented by the policies which hold back tasks until the running ones finish.
+type taskDoneObserver interface {
+	// OnTaskDone is called once a task popped from the policy is finished.
+	// Concurrent safe.
+	OnTaskDone(task Task)
+
+	// Notify returns a channel signaled when a task held back by the policy may be ready to pop.
+	Notify() <-chan struct{}
+}
+
 // MergeTask is a Task which can be merged with other task
 type MergeTask interface {
 	Task
@@ -84,6 +99,10 @@ type Task interface {
 	// Return "" if the task do not contain any user info.
 	Username() string
 
+	// Return the priority class which task is belong to.
+	// Return "" if the task do not specify any class.
+	PriorityClass() string
+
 	// Return whether the task would be running on GPU.
 	IsGpuIndex() bool
 
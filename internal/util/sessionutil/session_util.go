@@ -41,6 +41,7 @@ import (
 	kvfactory "github.com/milvus-io/milvus/internal/util/dependency/kv"
 	"github.com/milvus-io/milvus/pkg/v2/common"
 	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/tracer"
 	"github.com/milvus-io/milvus/pkg/v2/util/merr"
 	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
 	"github.com/milvus-io/milvus/pkg/v2/util/retry"
@@ -341,6 +342,8 @@ func (s *Session) Register() {
 		panic(err)
 	}
 	s.UpdateRegistered(true)
+	// the node id is known only now, start exporting the role's metrics and logs to the otlp collector
+	tracer.RegisterOTLPRole(s.ServerName, s.ServerID)
 	s.startKeepAliveLoop()
 }
 
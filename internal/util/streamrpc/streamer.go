@@ -107,6 +107,10 @@ func mergeCostAggregation(a *internalpb.CostAggregation, b *internalpb.CostAggre
 		ServiceTime:          a.GetServiceTime() + b.GetServiceTime(),
 		TotalNQ:              a.GetTotalNQ(),
 		TotalRelatedDataSize: a.GetTotalRelatedDataSize() + b.GetTotalRelatedDataSize(),
+		SegmentRows:          a.GetSegmentRows() + b.GetSegmentRows(),
+		SegmentsTouched:      a.GetSegmentsTouched() + b.GetSegmentsTouched(),
+		DistanceComputations: a.GetDistanceComputations() + b.GetDistanceComputations(),
+		SegmentTimeUs:        a.GetSegmentTimeUs() + b.GetSegmentTimeUs(),
 	}
 }
 
@@ -165,13 +165,16 @@ func (s *ResultCacheServerSuite) TestMerge() {
 	s.Equal(cost, mergeCostAggregation(nil, cost))
 	s.Equal(cost, mergeCostAggregation(cost, nil))
 
-	a := &internalpb.CostAggregation{ResponseTime: 1, ServiceTime: 1, TotalNQ: 2, TotalRelatedDataSize: 1}
-	b := &internalpb.CostAggregation{ResponseTime: 2, ServiceTime: 2, TotalNQ: 2, TotalRelatedDataSize: 2}
+	a := &internalpb.CostAggregation{ResponseTime: 1, ServiceTime: 1, TotalNQ: 2, TotalRelatedDataSize: 1, SegmentRows: 10, SegmentsTouched: 1, SegmentTimeUs: 5}
+	b := &internalpb.CostAggregation{ResponseTime: 2, ServiceTime: 2, TotalNQ: 2, TotalRelatedDataSize: 2, SegmentRows: 20, SegmentsTouched: 1, SegmentTimeUs: 7}
 	c := mergeCostAggregation(a, b)
 	s.Equal(int64(3), c.ResponseTime)
 	s.Equal(int64(3), c.ServiceTime)
 	s.Equal(int64(2), c.TotalNQ)
 	s.Equal(int64(3), c.TotalRelatedDataSize)
+	s.Equal(int64(30), c.SegmentRows)
+	s.Equal(int64(2), c.SegmentsTouched)
+	s.Equal(int64(12), c.SegmentTimeUs)
 }
 
 func TestResultCacheServerSuite(t *testing.T) {
@@ -0,0 +1,192 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// ClientExecutor issues the captured requests by the milvus go client.
+// The client describes the collections in its own database,
+// so a client is created for each database the requests are captured in.
+type ClientExecutor struct {
+	config milvusclient.ClientConfig
+
+	mu      sync.Mutex
+	clients map[string]*milvusclient.Client
+}
+
+// NewClientExecutor creates an executor connecting to the target cluster by the config,
+// the database of the config is ignored.
+func NewClientExecutor(config milvusclient.ClientConfig) *ClientExecutor {
+	return &ClientExecutor{
+		config:  config,
+		clients: make(map[string]*milvusclient.Client),
+	}
+}
+
+// client returns the client of the database, the client is dialed outside the lock
+// so that a slow connection doesn't block the requests of the other databases.
+// The client dialed by the concurrent requests of the same database is closed if another one is kept.
+func (e *ClientExecutor) client(ctx context.Context, dbName string) (*milvusclient.Client, error) {
+	e.mu.Lock()
+	cli, ok := e.clients[dbName]
+	e.mu.Unlock()
+	if ok {
+		return cli, nil
+	}
+
+	config := e.config
+	config.DBName = dbName
+	cli, err := milvusclient.New(ctx, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if existing, ok := e.clients[dbName]; ok {
+		cli.Close(ctx)
+		return existing, nil
+	}
+	e.clients[dbName] = cli
+	return cli, nil
+}
+
+// Execute issues the request by the client of the database the request is captured in.
+func (e *ClientExecutor) Execute(ctx context.Context, record *Record, req proto.Message) error {
+	dbReq, ok := req.(interface{ GetDbName() string })
+	if !ok {
+		return errors.Newf("unsupported request %T", req)
+	}
+	cli, err := e.client(ctx, dbReq.GetDbName())
+	if err != nil {
+		return err
+	}
+
+	switch req := req.(type) {
+	case *milvuspb.SearchRequest:
+		_, err = cli.Search(ctx, rawSearchOption{req})
+	case *milvuspb.HybridSearchRequest:
+		_, err = cli.HybridSearch(ctx, rawHybridSearchOption{req})
+	case *milvuspb.QueryRequest:
+		_, err = cli.Query(ctx, rawQueryOption{req})
+	case *milvuspb.InsertRequest:
+		_, err = cli.Insert(ctx, rawInsertOption{req})
+	case *milvuspb.UpsertRequest:
+		_, err = cli.Upsert(ctx, rawUpsertOption{req})
+	case *milvuspb.DeleteRequest:
+		_, err = cli.Delete(ctx, rawDeleteOption{req})
+	default:
+		err = errors.Newf("unsupported request %T", req)
+	}
+	return err
+}
+
+// Close closes all the clients.
+func (e *ClientExecutor) Close(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	errs := make([]error, 0)
+	for _, cli := range e.clients {
+		if err := cli.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	clear(e.clients)
+	return merr.Combine(errs...)
+}
+
+// the raw options issue the captured requests as is.
+var (
+	_ milvusclient.SearchOption       = rawSearchOption{}
+	_ milvusclient.HybridSearchOption = rawHybridSearchOption{}
+	_ milvusclient.QueryOption        = rawQueryOption{}
+	_ milvusclient.InsertOption       = rawInsertOption{}
+	_ milvusclient.UpsertOption       = rawUpsertOption{}
+	_ milvusclient.DeleteOption       = rawDeleteOption{}
+)
+
+type rawSearchOption struct {
+	req *milvuspb.SearchRequest
+}
+
+func (o rawSearchOption) Request() (*milvuspb.SearchRequest, error) {
+	return o.req, nil
+}
+
+type rawHybridSearchOption struct {
+	req *milvuspb.HybridSearchRequest
+}
+
+func (o rawHybridSearchOption) HybridRequest() (*milvuspb.HybridSearchRequest, error) {
+	return o.req, nil
+}
+
+type rawQueryOption struct {
+	req *milvuspb.QueryRequest
+}
+
+func (o rawQueryOption) Request() (*milvuspb.QueryRequest, error) {
+	return o.req, nil
+}
+
+type rawInsertOption struct {
+	req *milvuspb.InsertRequest
+}
+
+func (o rawInsertOption) InsertRequest(_ *entity.Collection) (*milvuspb.InsertRequest, error) {
+	return o.req, nil
+}
+
+func (o rawInsertOption) CollectionName() string {
+	return o.req.GetCollectionName()
+}
+
+func (o rawInsertOption) WriteBackPKs(_ *entity.Schema, _ column.Column) error {
+	return nil
+}
+
+type rawUpsertOption struct {
+	req *milvuspb.UpsertRequest
+}
+
+func (o rawUpsertOption) UpsertRequest(_ *entity.Collection) (*milvuspb.UpsertRequest, error) {
+	return o.req, nil
+}
+
+func (o rawUpsertOption) CollectionName() string {
+	return o.req.GetCollectionName()
+}
+
+type rawDeleteOption struct {
+	req *milvuspb.DeleteRequest
+}
+
+func (o rawDeleteOption) Request() *milvuspb.DeleteRequest {
+	return o.req
+}
@@ -0,0 +1,307 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workload defines the format of the requests captured by the proxy,
+// and replays the captured requests against another cluster to compare the latency and errors.
+package workload
+
+import (
+	"bufio"
+	"container/heap"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/internal/json"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// The methods of the requests that can be captured and replayed.
+const (
+	MethodSearch       = "Search"
+	MethodHybridSearch = "HybridSearch"
+	MethodQuery        = "Query"
+	MethodInsert       = "Insert"
+	MethodUpsert       = "Upsert"
+	MethodDelete       = "Delete"
+)
+
+// Methods are all the methods that can be captured and replayed.
+var Methods = []string{
+	MethodSearch,
+	MethodHybridSearch,
+	MethodQuery,
+	MethodInsert,
+	MethodUpsert,
+	MethodDelete,
+}
+
+// the max size of a single record line read from the workload files.
+const maxLineSize = 512 * 1024 * 1024
+
+// NewRequest creates an empty request message of the method, nil if the method isn't supported.
+func NewRequest(method string) proto.Message {
+	switch method {
+	case MethodSearch:
+		return &milvuspb.SearchRequest{}
+	case MethodHybridSearch:
+		return &milvuspb.HybridSearchRequest{}
+	case MethodQuery:
+		return &milvuspb.QueryRequest{}
+	case MethodInsert:
+		return &milvuspb.InsertRequest{}
+	case MethodUpsert:
+		return &milvuspb.UpsertRequest{}
+	case MethodDelete:
+		return &milvuspb.DeleteRequest{}
+	default:
+		return nil
+	}
+}
+
+// MethodOf returns the method of the full grpc method name, such as "/milvus.proto.milvus.MilvusService/Search".
+func MethodOf(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+// Record is a request captured by the proxy, encoded as a json line in the workload files.
+type Record struct {
+	// Timestamp is the time the request is received in unix microseconds.
+	Timestamp int64  `json:"ts"`
+	Method    string `json:"method"`
+	User      string `json:"user,omitempty"`
+	// LatencyUs is the latency of the request in microseconds.
+	LatencyUs int64 `json:"latency_us"`
+	// Code is the milvus error code of the response, 0 means success.
+	Code int32 `json:"code"`
+	// Request is the full request in protojson, including the vectors and template values.
+	Request json.RawMessage `json:"request"`
+}
+
+// NewRecord creates a record of the request received at the given time.
+func NewRecord(method string, user string, ts time.Time, req proto.Message) (*Record, error) {
+	data, err := protojson.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	return &Record{
+		Timestamp: ts.UnixMicro(),
+		Method:    method,
+		User:      user,
+		Request:   data,
+	}, nil
+}
+
+// Latency returns the latency of the request when it's captured.
+func (r *Record) Latency() time.Duration {
+	return time.Duration(r.LatencyUs) * time.Microsecond
+}
+
+// DecodeRequest decodes the captured request.
+func (r *Record) DecodeRequest() (proto.Message, error) {
+	req := NewRequest(r.Method)
+	if req == nil {
+		return nil, errors.Newf("unsupported method %s", r.Method)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(r.Request, req); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the %s request", r.Method)
+	}
+	return req, nil
+}
+
+// RecordIterator iterates the records ordered by the time they are captured, io.EOF is returned after the last record.
+type RecordIterator interface {
+	Next() (*Record, error)
+}
+
+// RecordReader reads the records from the workload files ordered by the time they are captured.
+// Each file is appended by the proxy as the requests complete, which is in the time order except for
+// the overlapped requests, so the files are merged while reading and only the next record of each file
+// is kept in memory. A record out of the order in its file is returned right after the record before it.
+type RecordReader struct {
+	files []*recordFile
+	heads recordHeap
+	err   error
+}
+
+var _ RecordIterator = (*RecordReader)(nil)
+
+// OpenRecords opens the workload files to read the records from.
+// The paths may be files or directories, all files in the directories are read.
+func OpenRecords(paths ...string) (*RecordReader, error) {
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			names = append(names, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				names = append(names, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	r := &RecordReader{
+		files: make([]*recordFile, 0, len(names)),
+		heads: make(recordHeap, 0, len(names)),
+	}
+	for _, name := range names {
+		file, err := openRecordFile(name, len(r.files))
+		if err != nil {
+			r.Close()
+			return nil, errors.Wrapf(err, "failed to open the workload file %s", name)
+		}
+		r.files = append(r.files, file)
+		if err := r.advance(file); err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+	heap.Init(&r.heads)
+	return r, nil
+}
+
+// advance reads the next record of the file and pushes it to the heads if there is one.
+func (r *RecordReader) advance(file *recordFile) error {
+	record, err := file.next()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the workload file %s", file.name)
+	}
+	r.heads = append(r.heads, recordHead{record: record, file: file})
+	return nil
+}
+
+// Next returns the earliest record of all the files, io.EOF is returned once all the files are read.
+func (r *RecordReader) Next() (*Record, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.heads.Len() == 0 {
+		return nil, io.EOF
+	}
+	head := r.heads[0]
+	record, err := head.file.next()
+	switch {
+	case err == nil:
+		r.heads[0].record = record
+		heap.Fix(&r.heads, 0)
+	case err == io.EOF:
+		heap.Pop(&r.heads)
+	default:
+		r.err = errors.Wrapf(err, "failed to read the workload file %s", head.file.name)
+		return nil, r.err
+	}
+	return head.record, nil
+}
+
+// Close closes all the workload files.
+func (r *RecordReader) Close() error {
+	errs := make([]error, 0)
+	for _, file := range r.files {
+		if err := file.f.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	r.files = nil
+	r.heads = nil
+	return merr.Combine(errs...)
+}
+
+type recordFile struct {
+	name    string
+	index   int
+	f       *os.File
+	scanner *bufio.Scanner
+}
+
+func openRecordFile(name string, index int) (*recordFile, error) {
+	f, err := os.OpenFile(name, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &recordFile{
+		name:    name,
+		index:   index,
+		f:       f,
+		scanner: scanner,
+	}, nil
+}
+
+func (f *recordFile) next() (*Record, error) {
+	for f.scanner.Scan() {
+		record := &Record{}
+		if err := json.Unmarshal(f.scanner.Bytes(), record); err != nil {
+			// the last line may be partially written before the crash.
+			continue
+		}
+		return record, nil
+	}
+	if err := f.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+type recordHead struct {
+	record *Record
+	file   *recordFile
+}
+
+// recordHeap orders the next records of the files by the time they are captured,
+// the records captured at the same time are ordered by the order of the files.
+type recordHeap []recordHead
+
+func (h recordHeap) Len() int { return len(h) }
+
+func (h recordHeap) Less(i, j int) bool {
+	if h[i].record.Timestamp != h[j].record.Timestamp {
+		return h[i].record.Timestamp < h[j].record.Timestamp
+	}
+	return h[i].file.index < h[j].file.index
+}
+
+func (h recordHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *recordHeap) Push(x any) { *h = append(*h, x.(recordHead)) }
+
+func (h *recordHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,126 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
+	"github.com/milvus-io/milvus/internal/json"
+)
+
+func TestMethodOf(t *testing.T) {
+	assert.Equal(t, MethodSearch, MethodOf("/milvus.proto.milvus.MilvusService/Search"))
+	assert.Equal(t, MethodQuery, MethodOf("Query"))
+	for _, method := range Methods {
+		assert.NotNil(t, NewRequest(method))
+	}
+	assert.Nil(t, NewRequest("CreateCollection"))
+}
+
+func TestRecord(t *testing.T) {
+	req := &milvuspb.QueryRequest{
+		DbName:         "db1",
+		CollectionName: "coll",
+		Expr:           "id in {ids}",
+		ExprTemplateValues: map[string]*schemapb.TemplateValue{
+			"ids": {Val: &schemapb.TemplateValue_ArrayVal{ArrayVal: &schemapb.TemplateArrayValue{
+				Data: &schemapb.TemplateArrayValue_LongData{LongData: &schemapb.LongArray{Data: []int64{1, 2, 3}}},
+			}}},
+		},
+	}
+	ts := time.UnixMicro(1700000000000000)
+	record, err := NewRecord(MethodQuery, "root", ts, req)
+	require.NoError(t, err)
+	record.LatencyUs = 1500
+	assert.Equal(t, int64(1700000000000000), record.Timestamp)
+	assert.Equal(t, 1500*time.Microsecond, record.Latency())
+
+	line, err := json.Marshal(record)
+	require.NoError(t, err)
+	decoded := &Record{}
+	require.NoError(t, json.Unmarshal(line, decoded))
+	assert.Equal(t, "root", decoded.User)
+
+	decodedReq, err := decoded.DecodeRequest()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(req, decodedReq))
+
+	_, err = (&Record{Method: "Flush"}).DecodeRequest()
+	assert.Error(t, err)
+	_, err = (&Record{Method: MethodSearch, Request: json.RawMessage(`{"dbName":1}`)}).DecodeRequest()
+	assert.Error(t, err)
+}
+
+func readAllRecords(t *testing.T, paths ...string) []*Record {
+	reader, err := OpenRecords(paths...)
+	require.NoError(t, err)
+	defer reader.Close()
+	records := make([]*Record, 0)
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestRecordReader(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, records ...*Record) {
+		f, err := os.Create(filepath.Join(dir, name))
+		require.NoError(t, err)
+		defer f.Close()
+		for _, record := range records {
+			line, err := json.Marshal(record)
+			require.NoError(t, err)
+			_, err = f.Write(append(line, '\n'))
+			require.NoError(t, err)
+		}
+		// a partially written line is skipped
+		_, err = f.WriteString(`{"ts":`)
+		require.NoError(t, err)
+	}
+	write("workload-1.log", &Record{Timestamp: 1, Method: MethodSearch}, &Record{Timestamp: 4, Method: MethodDelete}, &Record{Timestamp: 6, Method: MethodQuery})
+	write("workload-2.log", &Record{Timestamp: 2, Method: MethodInsert}, &Record{Timestamp: 4, Method: MethodUpsert})
+	write("workload.log")
+
+	records := readAllRecords(t, dir)
+	require.Len(t, records, 5)
+	assert.Equal(t, []int64{1, 2, 4, 4, 6}, lo.Map(records, func(r *Record, _ int) int64 { return r.Timestamp }))
+	// the records captured at the same time are ordered by the files
+	assert.Equal(t, MethodDelete, records[2].Method)
+	assert.Equal(t, MethodUpsert, records[3].Method)
+
+	records = readAllRecords(t, filepath.Join(dir, "workload-2.log"))
+	assert.Len(t, records, 2)
+
+	_, err := OpenRecords(filepath.Join(dir, "not_exist"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,307 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// the max number of error messages kept for each method in the report.
+const maxErrorSamples = 5
+
+// Executor issues a captured request against the target cluster.
+type Executor interface {
+	Execute(ctx context.Context, record *Record, req proto.Message) error
+}
+
+// Option is the option of the replayer.
+type Option func(r *Replayer)
+
+// WithSpeed scales the intervals between the requests, 2 replays the requests twice as fast as they are captured.
+// The requests are issued as fast as possible if the speed is not positive.
+func WithSpeed(speed float64) Option {
+	return func(r *Replayer) {
+		r.speed = speed
+	}
+}
+
+// WithConcurrency limits the number of the requests in flight.
+func WithConcurrency(concurrency int) Option {
+	return func(r *Replayer) {
+		if concurrency > 0 {
+			r.concurrency = concurrency
+		}
+	}
+}
+
+// WithMethods replays the requests of the given methods only.
+func WithMethods(methods ...string) Option {
+	return func(r *Replayer) {
+		if len(methods) > 0 {
+			r.methods = typeutil.NewSet(methods...)
+		}
+	}
+}
+
+// Replayer replays the captured requests against the target cluster by the executor,
+// keeping the intervals between the requests scaled by the speed.
+type Replayer struct {
+	executor    Executor
+	speed       float64
+	concurrency int
+	methods     typeutil.Set[string]
+}
+
+// NewReplayer creates a replayer.
+func NewReplayer(executor Executor, opts ...Option) *Replayer {
+	r := &Replayer{
+		executor:    executor,
+		speed:       1,
+		concurrency: 16,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Replay replays the records ordered by the time they are captured and reports the differences.
+// The replay stops issuing new requests once the context is done or the records fail to read,
+// the report of the requests issued is returned along with the read error.
+func (r *Replayer) Replay(ctx context.Context, records RecordIterator) (*Report, error) {
+	report := newReport()
+	sem := make(chan struct{}, r.concurrency)
+	wg := sync.WaitGroup{}
+
+	start := time.Now()
+	first := int64(-1)
+	var readErr error
+	for {
+		record, err := records.Next()
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			break
+		}
+		if r.methods != nil && !r.methods.Contain(record.Method) {
+			continue
+		}
+		if first < 0 {
+			first = record.Timestamp
+		}
+		if r.speed > 0 {
+			offset := time.Duration(float64(record.Timestamp-first)/r.speed) * time.Microsecond
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(wait):
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(record *Record) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			req, err := record.DecodeRequest()
+			if err != nil {
+				report.addSkipped(record, err)
+				return
+			}
+			begin := time.Now()
+			err = r.executor.Execute(ctx, record, req)
+			report.add(record, time.Since(begin), err)
+		}(record)
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+	return report, readErr
+}
+
+// LatencyStats is the distribution of the latencies.
+type LatencyStats struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+func newLatencyStats(latencies []time.Duration) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	percentile := func(p float64) time.Duration {
+		return latencies[int(float64(len(latencies)-1)*p)]
+	}
+	return LatencyStats{
+		P50: percentile(0.5),
+		P90: percentile(0.9),
+		P99: percentile(0.99),
+		Max: latencies[len(latencies)-1],
+	}
+}
+
+// MethodReport compares the replayed requests of a method with the captured ones.
+type MethodReport struct {
+	Method string
+	// Count is the number of the requests replayed.
+	Count int
+	// Skipped is the number of the requests failed to decode.
+	Skipped int
+	// RecordedErrors is the number of the requests failed when they are captured.
+	RecordedErrors int
+	// ReplayedErrors is the number of the requests failed when they are replayed.
+	ReplayedErrors int
+	// NewErrors is the number of the requests succeeded when captured but failed when replayed.
+	NewErrors int
+	// FixedErrors is the number of the requests failed when captured but succeeded when replayed.
+	FixedErrors int
+	// ChangedErrors is the number of the requests failed with a different error code when replayed.
+	ChangedErrors int
+	// ErrorSamples are the messages of the first errors only happened when replayed.
+	ErrorSamples []string
+
+	Recorded LatencyStats
+	Replayed LatencyStats
+
+	recordedLatencies []time.Duration
+	replayedLatencies []time.Duration
+}
+
+// Report is the result of a replay.
+type Report struct {
+	mu      sync.Mutex
+	methods map[string]*MethodReport
+
+	// Elapsed is the duration of the whole replay.
+	Elapsed time.Duration
+}
+
+func newReport() *Report {
+	return &Report{
+		methods: make(map[string]*MethodReport),
+	}
+}
+
+func (r *Report) method(method string) *MethodReport {
+	m, ok := r.methods[method]
+	if !ok {
+		m = &MethodReport{Method: method}
+		r.methods[method] = m
+	}
+	return m
+}
+
+func (r *Report) addSkipped(record *Record, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.method(record.Method)
+	m.Skipped++
+	if len(m.ErrorSamples) < maxErrorSamples {
+		m.ErrorSamples = append(m.ErrorSamples, err.Error())
+	}
+}
+
+func (r *Report) add(record *Record, latency time.Duration, err error) {
+	code := merr.Code(err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.method(record.Method)
+	m.Count++
+	m.recordedLatencies = append(m.recordedLatencies, record.Latency())
+	m.replayedLatencies = append(m.replayedLatencies, latency)
+	if record.Code != 0 {
+		m.RecordedErrors++
+	}
+	if err != nil {
+		m.ReplayedErrors++
+	}
+	switch {
+	case record.Code == 0 && err != nil:
+		m.NewErrors++
+	case record.Code != 0 && err == nil:
+		m.FixedErrors++
+	case record.Code != code:
+		m.ChangedErrors++
+	default:
+		return
+	}
+	if err != nil && len(m.ErrorSamples) < maxErrorSamples {
+		m.ErrorSamples = append(m.ErrorSamples, err.Error())
+	}
+}
+
+// Methods returns the reports of the methods replayed ordered by the method name.
+func (r *Report) Methods() []*MethodReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]*MethodReport, 0, len(r.methods))
+	for _, m := range r.methods {
+		m.Recorded = newLatencyStats(m.recordedLatencies)
+		m.Replayed = newLatencyStats(m.replayedLatencies)
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Method < result[j].Method
+	})
+	return result
+}
+
+// Print prints the report as a table.
+func (r *Report) Print(w io.Writer) {
+	methods := r.Methods()
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tCOUNT\tSKIPPED\tERRORS(REC/REP)\tNEW\tFIXED\tCHANGED\tP50(REC/REP)\tP90(REC/REP)\tP99(REC/REP)\tMAX(REC/REP)")
+	for _, m := range methods {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d/%d\t%d\t%d\t%d\t%v/%v\t%v/%v\t%v/%v\t%v/%v\n",
+			m.Method, m.Count, m.Skipped, m.RecordedErrors, m.ReplayedErrors, m.NewErrors, m.FixedErrors, m.ChangedErrors,
+			m.Recorded.P50, m.Replayed.P50, m.Recorded.P90, m.Replayed.P90,
+			m.Recorded.P99, m.Replayed.P99, m.Recorded.Max, m.Replayed.Max)
+	}
+	tw.Flush()
+	for _, m := range methods {
+		for _, sample := range m.ErrorSamples {
+			fmt.Fprintf(w, "%s error: %s\n", m.Method, sample)
+		}
+	}
+	fmt.Fprintf(w, "replayed in %v\n", r.Elapsed)
+}
@@ -0,0 +1,184 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus-proto/go-api/v2/milvuspb"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+type mockExecutor struct {
+	mu       sync.Mutex
+	issued   []string
+	inflight atomic.Int32
+	peak     atomic.Int32
+	execute  func(req proto.Message) error
+}
+
+func (e *mockExecutor) Execute(ctx context.Context, record *Record, req proto.Message) error {
+	n := e.inflight.Add(1)
+	defer e.inflight.Add(-1)
+	for {
+		peak := e.peak.Load()
+		if n <= peak || e.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	e.mu.Lock()
+	e.issued = append(e.issued, req.(interface{ GetCollectionName() string }).GetCollectionName())
+	e.mu.Unlock()
+	return e.execute(req)
+}
+
+// sliceRecords iterates the records of the slice, err is returned after the records if set.
+type sliceRecords struct {
+	records []*Record
+	err     error
+}
+
+func (s *sliceRecords) Next() (*Record, error) {
+	if len(s.records) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	record := s.records[0]
+	s.records = s.records[1:]
+	return record, nil
+}
+
+func newTestRecord(t *testing.T, ts int64, method string, code int32, req proto.Message) *Record {
+	record, err := NewRecord(method, "root", time.UnixMicro(ts), req)
+	require.NoError(t, err)
+	record.Code = code
+	record.LatencyUs = 1000
+	return record
+}
+
+func TestReplayer(t *testing.T) {
+	records := []*Record{
+		newTestRecord(t, 0, MethodSearch, 0, &milvuspb.SearchRequest{CollectionName: "ok"}),
+		newTestRecord(t, 10000, MethodSearch, 0, &milvuspb.SearchRequest{CollectionName: "broken"}),
+		newTestRecord(t, 20000, MethodQuery, merr.Code(merr.ErrCollectionNotFound), &milvuspb.QueryRequest{CollectionName: "ok"}),
+		newTestRecord(t, 30000, MethodQuery, merr.Code(merr.ErrCollectionNotFound), &milvuspb.QueryRequest{CollectionName: "broken"}),
+		newTestRecord(t, 40000, MethodDelete, 0, &milvuspb.DeleteRequest{CollectionName: "ok"}),
+		{Timestamp: 50000, Method: MethodInsert, Request: []byte(`{"collectionName":1}`)},
+	}
+	executor := &mockExecutor{
+		execute: func(req proto.Message) error {
+			if req.(interface{ GetCollectionName() string }).GetCollectionName() == "broken" {
+				return merr.WrapErrServiceInternal("broken")
+			}
+			return nil
+		},
+	}
+
+	start := time.Now()
+	report, err := NewReplayer(executor, WithSpeed(2), WithConcurrency(1)).Replay(context.Background(), &sliceRecords{records: records})
+	require.NoError(t, err)
+	// the 40ms between the first and the last request issued is scaled to 20ms
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, int32(1), executor.peak.Load())
+	assert.Equal(t, []string{"ok", "broken", "ok", "broken", "ok"}, executor.issued)
+
+	methods := report.Methods()
+	require.Len(t, methods, 4)
+
+	assert.Equal(t, MethodDelete, methods[0].Method)
+	assert.Equal(t, 1, methods[0].Count)
+
+	assert.Equal(t, MethodInsert, methods[1].Method)
+	assert.Equal(t, 0, methods[1].Count)
+	assert.Equal(t, 1, methods[1].Skipped)
+	assert.Len(t, methods[1].ErrorSamples, 1)
+
+	query := methods[2]
+	assert.Equal(t, MethodQuery, query.Method)
+	assert.Equal(t, 2, query.Count)
+	assert.Equal(t, 2, query.RecordedErrors)
+	assert.Equal(t, 1, query.ReplayedErrors)
+	assert.Equal(t, 1, query.FixedErrors)
+	assert.Equal(t, 1, query.ChangedErrors)
+	assert.Equal(t, time.Millisecond, query.Recorded.P99)
+
+	search := methods[3]
+	assert.Equal(t, MethodSearch, search.Method)
+	assert.Equal(t, 2, search.Count)
+	assert.Equal(t, 0, search.RecordedErrors)
+	assert.Equal(t, 1, search.ReplayedErrors)
+	assert.Equal(t, 1, search.NewErrors)
+	assert.Len(t, search.ErrorSamples, 1)
+	assert.GreaterOrEqual(t, search.Replayed.Max, 5*time.Millisecond)
+
+	buf := &bytes.Buffer{}
+	report.Print(buf)
+	assert.Contains(t, buf.String(), "Search error: ")
+}
+
+func TestReplayerMethods(t *testing.T) {
+	records := make([]*Record, 0)
+	for i := 0; i < 8; i++ {
+		records = append(records,
+			newTestRecord(t, int64(i)*int64(time.Hour/time.Microsecond), MethodSearch, 0, &milvuspb.SearchRequest{CollectionName: "search"}),
+			newTestRecord(t, int64(i)*int64(time.Hour/time.Microsecond), MethodInsert, 0, &milvuspb.InsertRequest{CollectionName: "insert"}))
+	}
+	executor := &mockExecutor{
+		execute: func(req proto.Message) error { return nil },
+	}
+
+	// hours of captured requests are issued at once without the time scaling
+	report, err := NewReplayer(executor, WithSpeed(0), WithConcurrency(4), WithMethods(MethodSearch)).Replay(context.Background(), &sliceRecords{records: records})
+	require.NoError(t, err)
+	methods := report.Methods()
+	require.Len(t, methods, 1)
+	assert.Equal(t, 8, methods[0].Count)
+	assert.LessOrEqual(t, executor.peak.Load(), int32(4))
+	assert.Greater(t, executor.peak.Load(), int32(1))
+
+	// the replay stops once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	report, err = NewReplayer(executor).Replay(ctx, &sliceRecords{records: records})
+	require.NoError(t, err)
+	methods = report.Methods()
+	require.Len(t, methods, 2)
+	assert.Equal(t, 1, methods[0].Count)
+	assert.Equal(t, 1, methods[1].Count)
+
+	// the replay stops once the records fail to read
+	report, err = NewReplayer(executor, WithSpeed(0)).Replay(context.Background(), &sliceRecords{records: records[:2], err: io.ErrUnexpectedEOF})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	methods = report.Methods()
+	require.Len(t, methods, 2)
+	assert.Equal(t, 1, methods[0].Count)
+	assert.Equal(t, 1, methods[1].Count)
+}
@@ -19,6 +19,7 @@ package common
 import (
 	"encoding/binary"
 	"fmt"
+	"math"
 	"math/bits"
 	"strconv"
 	"strings"
@@ -215,6 +216,11 @@ const (
 	JSONPathKey         = "json_path"
 	JSONCastFunctionKey = "json_cast_function"
 
+	// JSONSchemaKey and JSONPathTypesKey attach a JSON Schema or a simplified typed-path map to a JSON field,
+	// the documents inserted are validated against it and the declared types are used to plan the filters.
+	JSONSchemaKey    = "json_schema"
+	JSONPathTypesKey = "json_path_types"
+
 	SchemaVersionConsistencyProportionKey = "schema_version_consistency_proportion"
 	// SchemaVersionConsistentSegmentsKey and SchemaVersionTotalSegmentsKey are emitted by DataCoord
 	// GetCollectionStatistics to surface per-segment schema-version consistency progress.
@@ -244,6 +250,19 @@ const (
 	CollectionTTLFieldKey       = "ttl_field"
 	MaxTTLSeconds               = 3155760000 // 100 years
 
+	// CollectionCompactionPriorityKey is the weight of the collection's tasks in the compaction queue,
+	// a collection with a larger weight gets its compaction tasks scheduled earlier among the tasks of the same level,
+	// see dataCoord.compaction.collectionPriorityWindow.
+	CollectionCompactionPriorityKey     = "collection.compaction.priority"
+	DefaultCollectionCompactionPriority = 1.0
+
+	// CollectionCompactionTimeBucketKey enables the time-partitioned compaction of the collection,
+	// segments are compacted into buckets of the given granularity, valid values are "hour" and "day".
+	CollectionCompactionTimeBucketKey = "collection.compaction.timeBucket"
+	// CollectionCompactionTimeFieldKey is the name of the timestamptz field the rows are bucketed by
+	// in the time-partitioned compaction, it's required by CollectionCompactionTimeBucketKey.
+	CollectionCompactionTimeFieldKey = "collection.compaction.timeField"
+
 	// Deprecated: will be removed in the 3.0 after implementing ack sync up semantic.
 	CollectionOnTruncatingKey = "collection.on.truncating" // when collection is on truncating, forbid the compaction of current collection.
 
@@ -305,6 +324,7 @@ const (
 	PartitionKeyIsolationKey   = "partitionkey.isolation"
 	FieldSkipLoadKey           = "field.skipLoad"
 	IndexOffsetCacheEnabledKey = "indexoffsetcache.enabled"
+	PartitionLoadOnDemandKey   = "partition.loadOnDemand"
 	IndexNonEncoding           = "index.nonEncoding"
 	EnableDynamicSchemaKey     = `dynamicfield.enabled`
 
@@ -569,6 +589,17 @@ func IsDisableFuncRuntimeCheck(kvs ...*commonpb.KeyValuePair) (bool, error) {
 	return false, nil
 }
 
+// IsPartitionLoadOnDemandEnabled checks if the partitions of the collection are loaded on first access.
+func IsPartitionLoadOnDemandEnabled(kvs ...*commonpb.KeyValuePair) bool {
+	for _, kv := range kvs {
+		if kv.GetKey() == PartitionLoadOnDemandKey {
+			enabled, _ := strconv.ParseBool(strings.ToLower(kv.GetValue()))
+			return enabled
+		}
+	}
+	return false
+}
+
 func IsPartitionKeyIsolationPropEnabled(props map[string]string) (bool, error) {
 	val, ok := props[PartitionKeyIsolationKey]
 	if !ok {
@@ -806,6 +837,100 @@ func GetCollectionTTLFromMap(kvs map[string]string) (time.Duration, error) {
 	return time.Duration(ttlSeconds) * time.Second, nil
 }
 
+// GetCollectionCompactionPriority returns the compaction priority weight of the collection,
+// DefaultCollectionCompactionPriority is returned if the property is not set.
+func GetCollectionCompactionPriority(kvs ...*commonpb.KeyValuePair) (float64, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() == CollectionCompactionPriorityKey {
+			return parseCompactionPriority(kv.GetValue())
+		}
+	}
+	return DefaultCollectionCompactionPriority, nil
+}
+
+func GetCollectionCompactionPriorityFromMap(kvs map[string]string) (float64, error) {
+	value, exist := kvs[CollectionCompactionPriorityKey]
+	if !exist {
+		return DefaultCollectionCompactionPriority, nil
+	}
+	return parseCompactionPriority(value)
+}
+
+func parseCompactionPriority(value string) (float64, error) {
+	priority, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(priority) || math.IsInf(priority, 0) || priority <= 0 {
+		return 0, fmt.Errorf("compaction priority should be a positive number, got %s", value)
+	}
+	return priority, nil
+}
+
+// GetCollectionCompactionTimeBucket returns the time bucket of the time-partitioned compaction,
+// zero is returned if the property is not set.
+func GetCollectionCompactionTimeBucket(kvs ...*commonpb.KeyValuePair) (time.Duration, error) {
+	for _, kv := range kvs {
+		if kv.GetKey() == CollectionCompactionTimeBucketKey {
+			return parseCompactionTimeBucket(kv.GetValue())
+		}
+	}
+	return 0, nil
+}
+
+func GetCollectionCompactionTimeBucketFromMap(kvs map[string]string) (time.Duration, error) {
+	value, exist := kvs[CollectionCompactionTimeBucketKey]
+	if !exist {
+		return 0, nil
+	}
+	return parseCompactionTimeBucket(value)
+}
+
+func parseCompactionTimeBucket(value string) (time.Duration, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return 0, nil
+	case "hour":
+		return time.Hour, nil
+	case "day":
+		return 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("compaction time bucket should be hour or day, got %s", value)
+	}
+}
+
+// GetCollectionCompactionTimeFieldID returns the id of the time field of the time-partitioned compaction,
+// -1 is returned if the property is not set or the field is not a timestamptz field of the schema.
+func GetCollectionCompactionTimeFieldID(schema *schemapb.CollectionSchema) int64 {
+	fieldName := ""
+	for _, kv := range schema.GetProperties() {
+		if kv.GetKey() == CollectionCompactionTimeFieldKey {
+			fieldName = kv.GetValue()
+			break
+		}
+	}
+	if fieldName == "" {
+		return -1
+	}
+	for _, field := range schema.GetFields() {
+		if field.GetName() == fieldName && field.GetDataType() == schemapb.DataType_Timestamptz {
+			return field.GetFieldID()
+		}
+	}
+	return -1
+}
+
+// GetCompactionTimeBucketStart returns the start of the time bucket holding the timestamptz value,
+// both in microseconds since the UTC epoch, buckets are aligned to the epoch.
+func GetCompactionTimeBucketStart(value int64, bucket time.Duration) int64 {
+	size := bucket.Microseconds()
+	start := value - value%size
+	if value%size < 0 {
+		start -= size
+	}
+	return start
+}
+
 func CheckNamespace(schema *schemapb.CollectionSchema, namespace *string) error {
 	enabled := schema.GetEnableNamespace()
 	namespaceIsSet := namespace != nil
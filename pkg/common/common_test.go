@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/commonpb"
+	"github.com/milvus-io/milvus-proto/go-api/v2/schemapb"
 )
 
 func TestIsSystemField(t *testing.T) {
@@ -249,6 +250,117 @@ func TestIsDisableFuncRuntimeCheck(t *testing.T) {
 	assert.False(t, disable)
 }
 
+func TestGetCollectionCompactionPriority(t *testing.T) {
+	cases := []struct {
+		tag       string
+		value     string
+		expect    float64
+		expectErr bool
+	}{
+		{tag: "normal_case", value: "2.5", expect: 2.5},
+		{tag: "low_priority", value: "0.1", expect: 0.1},
+		{tag: "zero", value: "0", expectErr: true},
+		{tag: "negative", value: "-1", expectErr: true},
+		{tag: "nan", value: "NaN", expectErr: true},
+		{tag: "inf", value: "+Inf", expectErr: true},
+		{tag: "error_value", value: "high", expectErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tag, func(t *testing.T) {
+			result, err := GetCollectionCompactionPriority(&commonpb.KeyValuePair{Key: CollectionCompactionPriorityKey, Value: tc.value})
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expect, result)
+			}
+			result, err = GetCollectionCompactionPriorityFromMap(map[string]string{CollectionCompactionPriorityKey: tc.value})
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expect, result)
+			}
+		})
+	}
+
+	result, err := GetCollectionCompactionPriority()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCollectionCompactionPriority, result)
+	result, err = GetCollectionCompactionPriorityFromMap(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCollectionCompactionPriority, result)
+}
+
+func TestGetCollectionCompactionTimeBucket(t *testing.T) {
+	cases := []struct {
+		tag       string
+		value     string
+		expect    time.Duration
+		expectErr bool
+	}{
+		{tag: "hour", value: "hour", expect: time.Hour},
+		{tag: "day", value: "Day", expect: 24 * time.Hour},
+		{tag: "empty", value: "", expect: 0},
+		{tag: "error_value", value: "week", expectErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tag, func(t *testing.T) {
+			result, err := GetCollectionCompactionTimeBucket(&commonpb.KeyValuePair{Key: CollectionCompactionTimeBucketKey, Value: tc.value})
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expect, result)
+			}
+			result, err = GetCollectionCompactionTimeBucketFromMap(map[string]string{CollectionCompactionTimeBucketKey: tc.value})
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expect, result)
+			}
+		})
+	}
+
+	result, err := GetCollectionCompactionTimeBucket()
+	assert.NoError(t, err)
+	assert.Zero(t, result)
+	result, err = GetCollectionCompactionTimeBucketFromMap(nil)
+	assert.NoError(t, err)
+	assert.Zero(t, result)
+}
+
+func TestGetCollectionCompactionTimeFieldID(t *testing.T) {
+	schema := &schemapb.CollectionSchema{
+		Fields: []*schemapb.FieldSchema{
+			{FieldID: 100, Name: "pk", DataType: schemapb.DataType_Int64},
+			{FieldID: 101, Name: "event_time", DataType: schemapb.DataType_Timestamptz},
+		},
+	}
+	assert.EqualValues(t, -1, GetCollectionCompactionTimeFieldID(schema))
+
+	schema.Properties = []*commonpb.KeyValuePair{{Key: CollectionCompactionTimeFieldKey, Value: "event_time"}}
+	assert.EqualValues(t, 101, GetCollectionCompactionTimeFieldID(schema))
+
+	schema.Properties = []*commonpb.KeyValuePair{{Key: CollectionCompactionTimeFieldKey, Value: "pk"}}
+	assert.EqualValues(t, -1, GetCollectionCompactionTimeFieldID(schema))
+
+	schema.Properties = []*commonpb.KeyValuePair{{Key: CollectionCompactionTimeFieldKey, Value: "not_exist"}}
+	assert.EqualValues(t, -1, GetCollectionCompactionTimeFieldID(schema))
+}
+
+func TestGetCompactionTimeBucketStart(t *testing.T) {
+	hour := time.Hour.Microseconds()
+	assert.EqualValues(t, 0, GetCompactionTimeBucketStart(0, time.Hour))
+	assert.EqualValues(t, 0, GetCompactionTimeBucketStart(hour-1, time.Hour))
+	assert.EqualValues(t, hour, GetCompactionTimeBucketStart(hour, time.Hour))
+	assert.EqualValues(t, -hour, GetCompactionTimeBucketStart(-1, time.Hour))
+	assert.EqualValues(t, -hour, GetCompactionTimeBucketStart(-hour, time.Hour))
+}
+
 func TestGetCollectionTTL(t *testing.T) {
 	type testCase struct {
 		tag       string
@@ -510,3 +622,11 @@ func TestWKTWKBConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPartitionLoadOnDemandEnabled(t *testing.T) {
+	assert.False(t, IsPartitionLoadOnDemandEnabled())
+	assert.False(t, IsPartitionLoadOnDemandEnabled(&commonpb.KeyValuePair{Key: MmapEnabledKey, Value: "true"}))
+	assert.True(t, IsPartitionLoadOnDemandEnabled(&commonpb.KeyValuePair{Key: PartitionLoadOnDemandKey, Value: "True"}))
+	assert.False(t, IsPartitionLoadOnDemandEnabled(&commonpb.KeyValuePair{Key: PartitionLoadOnDemandKey, Value: "false"}))
+	assert.False(t, IsPartitionLoadOnDemandEnabled(&commonpb.KeyValuePair{Key: PartitionLoadOnDemandKey, Value: "invalid"}))
+}
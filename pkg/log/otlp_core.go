@@ -0,0 +1,250 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	otlpTraceIDKey         = "traceID"
+	otlpAttributeCodeFile  = "code.filepath"
+	otlpAttributeCodeLine  = "code.lineno"
+	otlpAttributeLoggerKey = "logger.name"
+)
+
+var _ zapcore.Core = (*OTLPCore)(nil)
+
+// NewOTLPCore creates a core which converts the entries into otel log records and emits them to the logger,
+// the batching and exporting are left to the log processors of the logger provider.
+func NewOTLPCore(enab zapcore.LevelEnabler, logger otellog.Logger) *OTLPCore {
+	return &OTLPCore{
+		LevelEnabler: enab,
+		logger:       logger,
+		stopped:      &atomic.Bool{},
+	}
+}
+
+// OTLPCore is a zapcore.Core that emits the entries to an otel logger.
+type OTLPCore struct {
+	zapcore.LevelEnabler
+
+	fields  []zapcore.Field
+	logger  otellog.Logger
+	stopped *atomic.Bool // shared by the cores derived with With
+}
+
+// With returns a copy of the Core with the given fields added.
+func (c *OTLPCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &OTLPCore{
+		LevelEnabler: c.LevelEnabler,
+		fields:       merged,
+		logger:       c.logger,
+		stopped:      c.stopped,
+	}
+}
+
+// Check checks if the entry is enabled by the level enabler.
+func (c *OTLPCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write converts the entry into a log record and emits it,
+// the traceID field is lifted into the trace id of the record so that it correlates with the span.
+func (c *OTLPCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if c.stopped.Load() {
+		return nil
+	}
+	record, traceID := newOTLPLogRecord(ent, c.fields, fields)
+	ctx := context.Background()
+	if traceID.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID}))
+	}
+	c.logger.Emit(ctx, record)
+	return nil
+}
+
+// Sync is a no-op, the records are flushed by the logger provider.
+func (c *OTLPCore) Sync() error {
+	return nil
+}
+
+// Stop stops emitting the entries, the logger provider is expected to be shut down by the caller.
+func (c *OTLPCore) Stop() {
+	c.stopped.Store(true)
+}
+
+// newOTLPLogRecord converts the zap entry and its fields into an otel log record,
+// along with the trace id carried by the traceID field.
+func newOTLPLogRecord(ent zapcore.Entry, fieldGroups ...[]zapcore.Field) (otellog.Record, trace.TraceID) {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, fields := range fieldGroups {
+		for _, field := range fields {
+			field.AddTo(enc)
+		}
+	}
+	var record otellog.Record
+	record.SetTimestamp(ent.Time)
+	record.SetObservedTimestamp(time.Now())
+	record.SetSeverity(otlpSeverity(ent.Level))
+	record.SetSeverityText(ent.Level.CapitalString())
+	record.SetBody(otellog.StringValue(ent.Message))
+
+	attrs := make([]otellog.KeyValue, 0, len(enc.Fields)+3)
+	if ent.LoggerName != "" {
+		attrs = append(attrs, otellog.String(otlpAttributeLoggerKey, ent.LoggerName))
+	}
+	if ent.Caller.Defined {
+		attrs = append(attrs,
+			otellog.String(otlpAttributeCodeFile, ent.Caller.TrimmedPath()),
+			otellog.Int(otlpAttributeCodeLine, ent.Caller.Line))
+	}
+	var traceID trace.TraceID
+	for key, value := range enc.Fields {
+		if key == otlpTraceIDKey {
+			if s, ok := value.(string); ok {
+				if id, err := hex.DecodeString(s); err == nil && len(id) == len(traceID) {
+					copy(traceID[:], id)
+					continue
+				}
+			}
+		}
+		attrs = append(attrs, otellog.KeyValue{Key: key, Value: otlpValue(value)})
+	}
+	record.AddAttributes(attrs...)
+	return record, traceID
+}
+
+func otlpSeverity(level zapcore.Level) otellog.Severity {
+	switch level {
+	case zapcore.DebugLevel:
+		return otellog.SeverityDebug
+	case zapcore.InfoLevel:
+		return otellog.SeverityInfo
+	case zapcore.WarnLevel:
+		return otellog.SeverityWarn
+	case zapcore.ErrorLevel:
+		return otellog.SeverityError
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return otellog.SeverityError4
+	case zapcore.FatalLevel:
+		return otellog.SeverityFatal
+	default:
+		return otellog.SeverityUndefined
+	}
+}
+
+// otlpValue converts the values produced by zapcore.MapObjectEncoder.
+func otlpValue(value interface{}) otellog.Value {
+	switch v := value.(type) {
+	case string:
+		return otellog.StringValue(v)
+	case bool:
+		return otellog.BoolValue(v)
+	case int:
+		return otellog.IntValue(v)
+	case int8:
+		return otellog.Int64Value(int64(v))
+	case int16:
+		return otellog.Int64Value(int64(v))
+	case int32:
+		return otellog.Int64Value(int64(v))
+	case int64:
+		return otellog.Int64Value(v)
+	case uint8:
+		return otellog.Int64Value(int64(v))
+	case uint16:
+		return otellog.Int64Value(int64(v))
+	case uint32:
+		return otellog.Int64Value(int64(v))
+	case uint:
+		return otlpUintValue(uint64(v))
+	case uint64:
+		return otlpUintValue(v)
+	case uintptr:
+		return otlpUintValue(uint64(v))
+	case float32:
+		return otellog.Float64Value(float64(v))
+	case float64:
+		return otellog.Float64Value(v)
+	case []byte:
+		return otellog.BytesValue(v)
+	case []interface{}:
+		values := make([]otellog.Value, 0, len(v))
+		for _, elem := range v {
+			values = append(values, otlpValue(elem))
+		}
+		return otellog.SliceValue(values...)
+	case map[string]interface{}:
+		values := make([]otellog.KeyValue, 0, len(v))
+		for key, elem := range v {
+			values = append(values, otellog.KeyValue{Key: key, Value: otlpValue(elem)})
+		}
+		return otellog.MapValue(values...)
+	case time.Time:
+		return otellog.StringValue(v.Format(time.RFC3339Nano))
+	default:
+		return otellog.StringValue(fmt.Sprint(v))
+	}
+}
+
+// otlpUintValue keeps the unsigned values which don't fit into int64 as strings.
+func otlpUintValue(v uint64) otellog.Value {
+	if v > math.MaxInt64 {
+		return otellog.StringValue(strconv.FormatUint(v, 10))
+	}
+	return otellog.Int64Value(int64(v))
+}
+
+// TeeOTLPCore duplicates the entries of the global loggers into the otlp core.
+// The core is expected to check the global level itself, see NewOTLPLevelEnabler.
+func TeeOTLPCore(core *OTLPCore) {
+	wrap := zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, core)
+	})
+	logger := L().WithOptions(wrap)
+	_globalL.Store(logger)
+	_globalS.Store(logger.Sugar())
+	_globalLevelLogger.Range(func(key, val interface{}) bool {
+		_globalLevelLogger.Store(key, val.(*zap.Logger).WithOptions(wrap))
+		return true
+	})
+}
+
+// NewOTLPLevelEnabler enables the entries at or above the given level which are enabled by the global level as well.
+func NewOTLPLevelEnabler(level zapcore.Level) zapcore.LevelEnabler {
+	return zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= level && Level().Enabled(l)
+	})
+}
@@ -0,0 +1,149 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type mockOTLPLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *mockOTLPLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, record := range records {
+		e.records = append(e.records, record.Clone())
+	}
+	return nil
+}
+
+func (e *mockOTLPLogExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *mockOTLPLogExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (e *mockOTLPLogExporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+func newTestOTLPCore(enab zapcore.LevelEnabler) (*OTLPCore, *mockOTLPLogExporter) {
+	exporter := &mockOTLPLogExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	return NewOTLPCore(enab, provider.Logger("test")), exporter
+}
+
+func otlpAttribute(record sdklog.Record, key string) otellog.Value {
+	var value otellog.Value
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		if kv.Key == key {
+			value = kv.Value
+			return false
+		}
+		return true
+	})
+	return value
+}
+
+func TestOTLPCore(t *testing.T) {
+	core, exporter := newTestOTLPCore(zap.InfoLevel)
+	defer core.Stop()
+
+	logger := zap.New(core, zap.AddCaller()).Named("test").With(zap.String("module", "otlp"))
+	logger.Debug("filtered by level")
+	logger.Info("hello",
+		zap.String("traceID", "4bf92f3577b34da6a3ce929d0e0e4736"),
+		zap.Int64("collectionID", 100),
+		zap.Bool("ok", true),
+		zap.Float64("ratio", 0.5),
+		zap.Strings("fields", []string{"a", "b"}),
+		zap.Uint64("ts", 1<<63),
+		zap.Error(errors.New("boom")))
+	logger.Warn("not a trace id", zap.String("traceID", "invalid"))
+	require.NoError(t, logger.Sync())
+
+	records := exporter.Records()
+	require.Len(t, records, 2)
+
+	record := records[0]
+	assert.Equal(t, "hello", record.Body().AsString())
+	assert.Equal(t, otellog.SeverityInfo, record.Severity())
+	assert.Equal(t, "INFO", record.SeverityText())
+	assert.False(t, record.Timestamp().IsZero())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record.TraceID().String())
+	assert.True(t, otlpAttribute(record, "traceID").Empty())
+	assert.Equal(t, "otlp", otlpAttribute(record, "module").AsString())
+	assert.Equal(t, "test", otlpAttribute(record, otlpAttributeLoggerKey).AsString())
+	assert.NotEmpty(t, otlpAttribute(record, otlpAttributeCodeFile).AsString())
+	assert.Equal(t, int64(100), otlpAttribute(record, "collectionID").AsInt64())
+	assert.True(t, otlpAttribute(record, "ok").AsBool())
+	assert.Equal(t, 0.5, otlpAttribute(record, "ratio").AsFloat64())
+	assert.Len(t, otlpAttribute(record, "fields").AsSlice(), 2)
+	assert.Equal(t, "9223372036854775808", otlpAttribute(record, "ts").AsString())
+	assert.Equal(t, "boom", otlpAttribute(record, "error").AsString())
+
+	// a malformed trace id is kept as an attribute
+	record = records[1]
+	assert.Equal(t, otellog.SeverityWarn, record.Severity())
+	assert.False(t, record.TraceID().IsValid())
+	assert.Equal(t, "invalid", otlpAttribute(record, "traceID").AsString())
+}
+
+func TestOTLPCoreStop(t *testing.T) {
+	core, exporter := newTestOTLPCore(zap.DebugLevel)
+	derived := core.With([]zapcore.Field{zap.String("module", "otlp")})
+
+	assert.NoError(t, core.Write(zapcore.Entry{Level: zap.InfoLevel, Message: "exported"}, nil))
+	core.Stop()
+	// writes after stop are ignored, by the derived cores as well
+	assert.NoError(t, core.Write(zapcore.Entry{Level: zap.InfoLevel, Message: "ignored"}, nil))
+	assert.NoError(t, derived.Write(zapcore.Entry{Level: zap.InfoLevel, Message: "ignored"}, nil))
+	assert.NoError(t, core.Sync())
+	require.Len(t, exporter.Records(), 1)
+	assert.Equal(t, "exported", exporter.Records()[0].Body().AsString())
+}
+
+func TestOTLPLevelEnabler(t *testing.T) {
+	old := Level().Level()
+	defer SetLevel(old)
+
+	SetLevel(zap.WarnLevel)
+	enab := NewOTLPLevelEnabler(zap.InfoLevel)
+	assert.False(t, enab.Enabled(zap.InfoLevel))
+	assert.True(t, enab.Enabled(zap.WarnLevel))
+
+	SetLevel(zap.DebugLevel)
+	assert.False(t, enab.Enabled(zap.DebugLevel))
+	assert.True(t, enab.Enabled(zap.InfoLevel))
+}
@@ -30,6 +30,10 @@ const (
 	CDCMetricLastReplicatedTimeTick   = "last_replicated_time_tick"
 	CDCMetricStreamRPCConnections     = "stream_rpc_connections"
 	CDCMetricStreamRPCReconnectTimes  = "stream_rpc_reconnect_times"
+	CDCMetricSinkPublishedRowsTotal   = "sink_published_rows_total"
+	CDCMetricSinkPublishedBytesTotal  = "sink_published_bytes_total"
+	CDCMetricSinkPublishFailuresTotal = "sink_publish_failures_total"
+	CDCMetricSinkLag                  = "sink_lag"
 
 	// CDC metric labels
 	CDCLabelTargetCluster     = "target_cluster"
@@ -37,6 +41,8 @@ const (
 	CDCLabelTargetChannelName = "target_channel_name"
 	CDCLabelMsgType           = msgTypeLabelName
 	CDCLabelConnectionStatus  = "connection_status"
+	CDCLabelSinkType          = "sink_type"
+	CDCLabelCollectionName    = collectionName
 
 	// CDC metric values
 	CDCStatusConnected    = "connected"
@@ -117,6 +123,57 @@ var CDCStreamRPCReconnectTimes = prometheus.NewCounterVec(
 	},
 )
 
+var CDCSinkPublishedRowsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: milvusNamespace,
+		Subsystem: typeutil.CDCRole,
+		Name:      CDCMetricSinkPublishedRowsTotal,
+		Help:      "Total number of rows published into the CDC sink",
+	}, []string{
+		CDCLabelSinkType,
+		CDCLabelSourceChannelName,
+		CDCLabelCollectionName,
+		CDCLabelMsgType,
+	},
+)
+
+var CDCSinkPublishedBytesTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: milvusNamespace,
+		Subsystem: typeutil.CDCRole,
+		Name:      CDCMetricSinkPublishedBytesTotal,
+		Help:      "Total number of encoded bytes published into the CDC sink",
+	}, []string{
+		CDCLabelSinkType,
+		CDCLabelSourceChannelName,
+		CDCLabelCollectionName,
+	},
+)
+
+var CDCSinkPublishFailuresTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: milvusNamespace,
+		Subsystem: typeutil.CDCRole,
+		Name:      CDCMetricSinkPublishFailuresTotal,
+		Help:      "Total number of failed publishes into the CDC sink",
+	}, []string{
+		CDCLabelSinkType,
+		CDCLabelSourceChannelName,
+	},
+)
+
+var CDCSinkLag = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: milvusNamespace,
+		Subsystem: typeutil.CDCRole,
+		Name:      CDCMetricSinkLag,
+		Help:      "The lag in milliseconds between the time tick of the last published message and now",
+	}, []string{
+		CDCLabelSinkType,
+		CDCLabelSourceChannelName,
+	},
+)
+
 func RegisterCDC(registry *prometheus.Registry) {
 	registry.MustRegister(CDCReplicatedMessagesTotal)
 	registry.MustRegister(CDCReplicatedBytesTotal)
@@ -124,4 +181,8 @@ func RegisterCDC(registry *prometheus.Registry) {
 	registry.MustRegister(CDCLastReplicatedTimeTick)
 	registry.MustRegister(CDCStreamRPCConnections)
 	registry.MustRegister(CDCStreamRPCReconnectTimes)
+	registry.MustRegister(CDCSinkPublishedRowsTotal)
+	registry.MustRegister(CDCSinkPublishedBytesTotal)
+	registry.MustRegister(CDCSinkPublishFailuresTotal)
+	registry.MustRegister(CDCSinkLag)
 }
@@ -91,6 +91,27 @@ var (
 		Name:      "cgo_write_bytes",
 		Help:      "The total bytes of CGO write logs, the bytes is calculated before encoding, only considers the length of the message, so the actual bytes may be greater than the value",
 	})
+
+	LoggingOTLPExportedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: milvusNamespace,
+		Subsystem: loggingMetricSubsystem,
+		Name:      "otlp_exported_total",
+		Help:      "The total number of log records exported to the otlp collector",
+	})
+
+	LoggingOTLPDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: milvusNamespace,
+		Subsystem: loggingMetricSubsystem,
+		Name:      "otlp_dropped_total",
+		Help:      "The number of log records dropped due to otlp export failure",
+	})
+
+	LoggingOTLPExportFailureTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: milvusNamespace,
+		Subsystem: loggingMetricSubsystem,
+		Name:      "otlp_export_failure_total",
+		Help:      "The number of failed otlp log exports",
+	})
 )
 
 // RegisterLoggingMetrics registers logging metrics
@@ -105,5 +126,8 @@ func RegisterLoggingMetrics(registry *prometheus.Registry) {
 		registry.MustRegister(LoggingWriteBytes)
 		registry.MustRegister(LoggingCGOWriteTotal)
 		registry.MustRegister(LoggingCGOWriteBytes)
+		registry.MustRegister(LoggingOTLPExportedTotal)
+		registry.MustRegister(LoggingOTLPDroppedTotal)
+		registry.MustRegister(LoggingOTLPExportFailureTotal)
 	})
 }
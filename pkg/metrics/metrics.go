@@ -33,6 +33,9 @@ const (
 	TotalLabel    = "total"
 	RetryLabel    = "retry"
 	RejectedLabel = "rejected"
+	QueuedLabel   = "queued"
+	LoadedLabel   = "loaded"
+	EvictedLabel  = "evicted"
 
 	HybridSearchLabel = "hybrid_search"
 
@@ -133,6 +136,8 @@ const (
 	cgoTypeLabelName               = `cgo_type`
 	queueTypeLabelName             = `queue_type`
 	poolNameLabelName              = "pool_name"
+	priorityClassLabelName         = "priority_class"
+	costTypeLabelName              = "cost_type"
 
 	// model function/UDF labels
 	functionTypeName = "function_type_name"
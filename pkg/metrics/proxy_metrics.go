@@ -319,6 +319,15 @@ var (
 			Help:      "report value about the request",
 		}, []string{nodeIDLabelName, msgTypeLabelName, databaseLabelName, usernameLabelName})
 
+	// ProxyRequestCost records the resources consumed by the search and query requests, such as the segment rows and the segment time.
+	ProxyRequestCost = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.ProxyRole,
+			Name:      "request_cost",
+			Help:      "resources consumed by the search and query requests",
+		}, []string{nodeIDLabelName, msgTypeLabelName, databaseLabelName, usernameLabelName, costTypeLabelName})
+
 	// ProxyLimiterRate records rates of rateLimiter in Proxy.
 	ProxyLimiterRate = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -536,6 +545,7 @@ func RegisterProxy(registry *prometheus.Registry) {
 
 	registry.MustRegister(ProxySlowQueryCount)
 	registry.MustRegister(ProxyReportValue)
+	registry.MustRegister(ProxyRequestCost)
 	registry.MustRegister(ProxyReqInQueueLatency)
 
 	registry.MustRegister(MaxInsertRate)
@@ -176,6 +176,22 @@ var (
 			Name:      "last_heartbeat_timestamp",
 			Help:      "heartbeat timestamp of query node",
 		}, []string{nodeIDLabelName})
+
+	QueryCoordOnDemandPartitionCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.QueryCoordRole,
+			Name:      "on_demand_partition_count",
+			Help:      "count of partitions loaded on access or evicted by the partition load on demand",
+		}, []string{statusLabelName})
+
+	QueryCoordOnDemandPartitionMemory = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.QueryCoordRole,
+			Name:      "on_demand_partition_memory",
+			Help:      "estimated memory in bytes of the partitions loaded on demand in resource group",
+		}, []string{ResourceGroupLabelName})
 )
 
 // RegisterQueryCoord registers QueryCoord metrics
@@ -195,6 +211,8 @@ func RegisterQueryCoord(registry *prometheus.Registry) {
 	registry.MustRegister(QueryCoordResourceGroupReplicaTotal)
 	registry.MustRegister(QueryCoordReplicaRONodeTotal)
 	registry.MustRegister(QueryCoordLastHeartbeatTimeStamp)
+	registry.MustRegister(QueryCoordOnDemandPartitionCount)
+	registry.MustRegister(QueryCoordOnDemandPartitionMemory)
 }
 
 func CleanQueryCoordMetricsWithCollectionID(collectionID int64) {
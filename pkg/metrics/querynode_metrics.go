@@ -297,6 +297,51 @@ var (
 			nodeIDLabelName,
 		})
 
+	QueryNodeReadTaskQueueWaitLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.QueryNodeRole,
+			Name:      "read_task_queue_wait_latency",
+			Help:      "latency of read tasks waiting in the scheduler queue of each priority class",
+			Buckets:   subMsBuckets,
+		}, []string{
+			nodeIDLabelName,
+			priorityClassLabelName,
+		})
+
+	QueryNodeReadTaskInflight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.QueryNodeRole,
+			Name:      "read_task_inflight",
+			Help:      "number of read tasks of each priority class scheduled and not finished yet",
+		}, []string{
+			nodeIDLabelName,
+			priorityClassLabelName,
+		})
+
+	QueryNodeSearchEstimatedCost = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.QueryNodeRole,
+			Name:      "search_estimated_cost",
+			Help:      "estimated cost of searches on a shard used by admission control",
+			Buckets:   prometheus.ExponentialBuckets(1000, 4, 12),
+		}, []string{
+			nodeIDLabelName,
+		})
+
+	QueryNodeSearchOverBudgetCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: milvusNamespace,
+			Subsystem: typeutil.QueryNodeRole,
+			Name:      "search_over_budget_count",
+			Help:      "count of searches whose estimated cost exceeds the budget, by the action taken",
+		}, []string{
+			nodeIDLabelName,
+			statusLabelName,
+		})
+
 	QueryNodeEstimateCPUUsage = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: milvusNamespace,
@@ -950,6 +995,10 @@ func RegisterQueryNode(registry *prometheus.Registry) {
 	registry.MustRegister(QueryNodeReadTaskUnsolveLen)
 	registry.MustRegister(QueryNodeReadTaskReadyLen)
 	registry.MustRegister(QueryNodeReadTaskConcurrency)
+	registry.MustRegister(QueryNodeReadTaskQueueWaitLatency)
+	registry.MustRegister(QueryNodeReadTaskInflight)
+	registry.MustRegister(QueryNodeSearchEstimatedCost)
+	registry.MustRegister(QueryNodeSearchOverBudgetCount)
 	registry.MustRegister(QueryNodeEstimateCPUUsage)
 	registry.MustRegister(QueryNodeSearchGroupNQ)
 	registry.MustRegister(QueryNodeSearchNQ)
@@ -350,23 +350,9 @@ func (b *mutableMesasgeBuilder[H, B]) build() (*messageImpl, error) {
 			panic(fmt.Sprintf("the message type cannot enable cipher, %s", messageType))
 		}
 
-		cipher := mustGetCipher()
-		encryptor, safeKey, err := cipher.GetEncryptor(b.cipherConfig.EzID, b.cipherConfig.CollectionID)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to get encryptor")
-		}
-		payloadBytes := len(payload)
-		if payload, err = encryptor.Encrypt(payload); err != nil {
-			return nil, errors.Wrap(err, "failed to encrypt payload")
-		}
-		ch, err := EncodeProto(&messagespb.CipherHeader{
-			EzId:         b.cipherConfig.EzID,
-			CollectionId: b.cipherConfig.CollectionID,
-			SafeKey:      safeKey,
-			PayloadBytes: int64(payloadBytes),
-		})
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to encode cipher header")
+		var ch string
+		if payload, ch, err = encryptPayload(payload, b.cipherConfig); err != nil {
+			return nil, err
 		}
 		b.properties.Set(messageCipherHeader, ch)
 	}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/hook"
 	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/proto/messagespb"
 )
 
 // cipher is a global variable that is used to encrypt and decrypt messages.
@@ -107,3 +108,56 @@ type CipherConfig struct {
 	// Collection ID
 	CollectionID int64
 }
+
+// encryptPayload encrypts the payload with the cipher config,
+// returns the encrypted payload and the encoded cipher header that should be set into the properties of message.
+// The safe key (the encrypted data key) is kept in the cipher header,
+// so the message can still be decrypted after the root key of encryption zone is rotated.
+func encryptPayload(payload []byte, cipherConfig *CipherConfig) ([]byte, string, error) {
+	encryptor, safeKey, err := mustGetCipher().GetEncryptor(cipherConfig.EzID, cipherConfig.CollectionID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to get encryptor")
+	}
+	payloadBytes := len(payload)
+	encrypted, err := encryptor.Encrypt(payload)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to encrypt payload")
+	}
+	ch, err := EncodeProto(&messagespb.CipherHeader{
+		EzId:         cipherConfig.EzID,
+		CollectionId: cipherConfig.CollectionID,
+		SafeKey:      safeKey,
+		PayloadBytes: int64(payloadBytes),
+	})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to encode cipher header")
+	}
+	return encrypted, ch, nil
+}
+
+// IsEncrypted returns true if the payload of message is encrypted.
+func IsEncrypted(msg BasicMessage) bool {
+	return msg.Properties().Exist(messageCipherHeader)
+}
+
+// EncryptMutableMessage encrypts the payload of a mutable message in place with the cipher config.
+// The message that is already encrypted is kept unchanged.
+func EncryptMutableMessage(msg MutableMessage, cipherConfig *CipherConfig) error {
+	if IsEncrypted(msg) {
+		return nil
+	}
+	if !msg.MessageType().CanEnableCipher() {
+		return errors.Errorf("the message type cannot enable cipher, %s", msg.MessageType())
+	}
+	impl, ok := msg.(*messageImpl)
+	if !ok {
+		return errors.Errorf("unsupported message implementation %T for encryption", msg)
+	}
+	payload, ch, err := encryptPayload(impl.payload, cipherConfig)
+	if err != nil {
+		return err
+	}
+	impl.payload = payload
+	impl.properties.Set(messageCipherHeader, ch)
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/milvus-io/milvus-proto/go-api/v2/hook"
+	"github.com/milvus-io/milvus-proto/go-api/v2/msgpb"
 )
 
 // mockCipher is a simple mock implementation for testing
@@ -285,3 +286,59 @@ func TestIsKmsKeyInvalidError(t *testing.T) {
 		})
 	}
 }
+
+// mockEncryptor is a simple mock encryptor
+type mockEncryptor struct{}
+
+func (m *mockEncryptor) Encrypt(data []byte) ([]byte, error) {
+	return append([]byte("enc:"), data...), nil
+}
+
+func TestEncryptMutableMessage(t *testing.T) {
+	origCipher := cipher
+	defer func() { cipher = origCipher }()
+
+	var safeKeys [][]byte
+	cipher = &mockCipher{
+		getEncryptorFunc: func(ezID, collectionID int64) (hook.Encryptor, []byte, error) {
+			return &mockEncryptor{}, []byte(fmt.Sprintf("key-%d-%d", ezID, collectionID)), nil
+		},
+		getDecryptorFunc: func(ezID, collectionID int64, safeKey []byte) (hook.Decryptor, error) {
+			safeKeys = append(safeKeys, safeKey)
+			return &mockDecryptor{decryptFunc: func(b []byte) ([]byte, error) {
+				return b[len("enc:"):], nil
+			}}, nil
+		},
+	}
+
+	msg := NewInsertMessageBuilderV1().
+		WithHeader(&InsertMessageHeader{CollectionId: 2}).
+		WithBody(&msgpb.InsertRequest{ShardName: "v1"}).
+		WithVChannel("v1").
+		MustBuildMutable()
+	plaintext := msg.Payload()
+	assert.False(t, IsEncrypted(msg))
+
+	assert.NoError(t, EncryptMutableMessage(msg, &CipherConfig{EzID: 1, CollectionID: 2}))
+	assert.True(t, IsEncrypted(msg))
+	assert.NotEqual(t, plaintext, msg.IntoMessageProto().GetPayload())
+	// the payload is decrypted with the safe key kept in the message properties.
+	assert.Equal(t, plaintext, msg.Payload())
+	assert.Equal(t, [][]byte{[]byte("key-1-2")}, safeKeys)
+	body, err := MustAsMutableInsertMessageV1(msg).Body()
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", body.GetShardName())
+
+	// the encrypted message is not encrypted again.
+	encrypted := msg.IntoMessageProto().GetPayload()
+	assert.NoError(t, EncryptMutableMessage(msg, &CipherConfig{EzID: 3, CollectionID: 2}))
+	assert.Equal(t, encrypted, msg.IntoMessageProto().GetPayload())
+
+	// the message type that cannot enable cipher is rejected.
+	flush := NewManualFlushMessageBuilderV2().
+		WithHeader(&ManualFlushMessageHeader{CollectionId: 2}).
+		WithBody(&ManualFlushMessageBody{}).
+		WithVChannel("v1").
+		MustBuildMutable()
+	assert.Error(t, EncryptMutableMessage(flush, &CipherConfig{EzID: 1, CollectionID: 2}))
+}
@@ -14,11 +14,29 @@ func TestWALMetrics(t *testing.T) {
 	rw := RWWALMetrics{
 		ChannelInfo: PChannelInfo{
 			Name: "ch1",
+			Term: 1,
 		},
-		MVCCTimeTick:     tsoutil.ComposeTSByTime(now, 0),
-		RecoveryTimeTick: tsoutil.ComposeTSByTime(now.Add(-time.Second), 0),
+		MVCCTimeTick:         tsoutil.ComposeTSByTime(now, 0),
+		RecoveryTimeTick:     tsoutil.ComposeTSByTime(now.Add(-time.Second), 0),
+		AppendBytesPerSecond: 1024,
 	}
 	assert.Equal(t, time.Second, rw.RecoveryLag())
 	rw.MVCCTimeTick = tsoutil.ComposeTSByTime(now.Add(-2*time.Second), 0)
 	assert.Zero(t, rw.RecoveryLag())
+
+	ro := ROWALMetrics{
+		ChannelInfo: PChannelInfo{
+			Name:       "ch2",
+			Term:       1,
+			AccessMode: AccessModeRO,
+		},
+	}
+	m := NewStreamingNodeBalanceAttrsFromProto(NewProtoFromStreamingNodeMetrics(StreamingNodeMetrics{
+		WALMetrics: map[ChannelID]WALMetrics{
+			{Name: "ch1"}: rw,
+			{Name: "ch2"}: ro,
+		},
+	}))
+	assert.Equal(t, rw, m.WALMetrics[ChannelID{Name: "ch1"}])
+	assert.Equal(t, ro, m.WALMetrics[ChannelID{Name: "ch2"}])
 }
@@ -0,0 +1,185 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracer
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+const (
+	otlpScopeName       = "github.com/milvus-io/milvus"
+	otlpShutdownTimeout = 10 * time.Second
+)
+
+var otlpExporters struct {
+	mu             sync.Mutex
+	initialized    bool
+	gatherer       prometheus.Gatherer
+	meterProviders map[string]*sdkmetric.MeterProvider
+	loggerProvider *sdklog.LoggerProvider
+	logs           *log.OTLPCore
+}
+
+// otlpRoles are the registered roles in the order of registration,
+// it's guarded by a mutex of its own since the meter providers read it while exporting.
+var otlpRoles struct {
+	mu    sync.RWMutex
+	roles []string
+}
+
+// InitOTLP prepares exporting the metrics of the gatherer and the global logs to the otlp collector,
+// each of them is only exported if enabled by trace.otlp.metrics.enabled and trace.otlp.logs.enabled.
+// Nothing is exported until the roles register their sessions, see RegisterOTLPRole.
+func InitOTLP(gatherer prometheus.Gatherer) error {
+	params := paramtable.Get()
+	enableMetrics := params.TraceCfg.OtlpMetricsEnabled.GetAsBool()
+	enableLogs := params.TraceCfg.OtlpLogsEnabled.GetAsBool()
+	if !enableMetrics && !enableLogs {
+		return nil
+	}
+	if err := checkOTLPConfig(params); err != nil {
+		log.Warn("Init otlp exporters failed", zap.Error(err))
+		return err
+	}
+	if enableLogs {
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(params.TraceCfg.OtlpLogsLevel.GetValue())); err != nil {
+			log.Warn("Init otlp exporters failed", zap.Error(err))
+			return err
+		}
+	}
+
+	otlpExporters.mu.Lock()
+	defer otlpExporters.mu.Unlock()
+	otlpExporters.initialized = true
+	otlpExporters.gatherer = gatherer
+	otlpExporters.meterProviders = make(map[string]*sdkmetric.MeterProvider)
+	log.Info("Init otlp exporters finished, waiting for the roles to register",
+		zap.String("endpoint", params.TraceCfg.OtlpEndpoint.GetValue()),
+		zap.Bool("metrics", enableMetrics),
+		zap.Bool("logs", enableLogs))
+	return nil
+}
+
+// RegisterOTLPRole starts exporting to the otlp collector for the role once its session is registered,
+// so that the resource carries the node id assigned by the registration.
+// The metrics are exported by a meter provider per role with the resource of the role.
+// The global loggers are shared by the roles of the process, so the logs are exported with the resource
+// of the process role and the node id of the first registered role,
+// the roles of standalone mode share the node id.
+func RegisterOTLPRole(role string, nodeID int64) {
+	otlpExporters.mu.Lock()
+	defer otlpExporters.mu.Unlock()
+	if !otlpExporters.initialized {
+		return
+	}
+	otlpRoles.mu.Lock()
+	registered := slices.Contains(otlpRoles.roles, role)
+	if !registered {
+		otlpRoles.roles = append(otlpRoles.roles, role)
+	}
+	otlpRoles.mu.Unlock()
+	if registered {
+		return
+	}
+	params := paramtable.Get()
+
+	if params.TraceCfg.OtlpMetricsEnabled.GetAsBool() {
+		exporter, err := createOTLPMetricExporter(params)
+		if err != nil {
+			log.Warn("create otlp metric exporter failed", zap.String("role", role), zap.Error(err))
+		} else {
+			gatherer := &roleGatherer{
+				gatherer: otlpExporters.gatherer,
+				role:     role,
+				roles:    getOTLPRoles,
+			}
+			otlpExporters.meterProviders[role] = newOTLPMeterProvider(exporter, gatherer, nodeID,
+				params.TraceCfg.OtlpMetricsInterval.GetAsDuration(time.Second))
+		}
+	}
+
+	if params.TraceCfg.OtlpLogsEnabled.GetAsBool() && otlpExporters.loggerProvider == nil {
+		exporter, err := createOTLPLogExporter(params)
+		if err != nil {
+			log.Warn("create otlp log exporter failed", zap.String("role", role), zap.Error(err))
+		} else {
+			var level zapcore.Level
+			// validated by InitOTLP
+			_ = level.UnmarshalText([]byte(params.TraceCfg.OtlpLogsLevel.GetValue()))
+			otlpExporters.loggerProvider = sdklog.NewLoggerProvider(
+				sdklog.WithResource(newResource(paramtable.GetRole(), nodeID)),
+				sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+			)
+			otlpExporters.logs = log.NewOTLPCore(log.NewOTLPLevelEnabler(level), otlpExporters.loggerProvider.Logger(otlpScopeName))
+			log.TeeOTLPCore(otlpExporters.logs)
+		}
+	}
+	log.Info("otlp exporters started for role", zap.String("role", role), zap.Int64("nodeID", nodeID))
+}
+
+// getOTLPRoles returns the roles registered in the order of registration.
+func getOTLPRoles() []string {
+	otlpRoles.mu.RLock()
+	defer otlpRoles.mu.RUnlock()
+	return slices.Clone(otlpRoles.roles)
+}
+
+// CloseOTLP flushes the pending metrics and logs and shuts down the otlp exporters.
+func CloseOTLP() {
+	otlpExporters.mu.Lock()
+	defer otlpExporters.mu.Unlock()
+	if !otlpExporters.initialized {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), otlpShutdownTimeout)
+	defer cancel()
+	for role, provider := range otlpExporters.meterProviders {
+		// the provider collects and exports the last round of metrics on shutdown
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Warn("shutdown otlp meter provider failed", zap.String("role", role), zap.Error(err))
+		}
+	}
+	if otlpExporters.logs != nil {
+		otlpExporters.logs.Stop()
+		otlpExporters.logs = nil
+	}
+	if otlpExporters.loggerProvider != nil {
+		if err := otlpExporters.loggerProvider.Shutdown(ctx); err != nil {
+			log.Warn("shutdown otlp logger provider failed", zap.Error(err))
+		}
+		otlpExporters.loggerProvider = nil
+	}
+	otlpExporters.initialized = false
+	otlpExporters.gatherer = nil
+	otlpRoles.mu.Lock()
+	otlpRoles.roles = nil
+	otlpRoles.mu.Unlock()
+	otlpExporters.meterProviders = nil
+}
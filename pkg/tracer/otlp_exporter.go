@@ -0,0 +1,133 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracer
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/milvus-io/milvus/pkg/v2/metrics"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// checkOTLPConfig checks the otlp endpoint and method shared by the metric and log exporters.
+func checkOTLPConfig(params *paramtable.ComponentParam) error {
+	if params.TraceCfg.OtlpEndpoint.GetValue() == "" {
+		return errors.New("otlp endpoint is not set")
+	}
+	switch params.TraceCfg.OtlpMethod.GetValue() {
+	case "", "grpc", "http":
+		return nil
+	default:
+		return errors.Newf("otlp method not supported: %s", params.TraceCfg.OtlpMethod.GetValue())
+	}
+}
+
+// createOTLPMetricExporter creates the metric exporter to the collector configured by trace.otlp,
+// the same collector the otlp trace exporter talks to.
+func createOTLPMetricExporter(params *paramtable.ComponentParam) (sdkmetric.Exporter, error) {
+	if err := checkOTLPConfig(params); err != nil {
+		return nil, err
+	}
+	endpoint := params.TraceCfg.OtlpEndpoint.GetValue()
+	secure := params.TraceCfg.OtlpSecure.GetAsBool()
+	headersMap := parseHeaders(params.TraceCfg.OtlpHeaders.GetValue())
+	if params.TraceCfg.OtlpMethod.GetValue() == "http" {
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(endpoint),
+		}
+		if !secure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if headersMap != nil {
+			opts = append(opts, otlpmetrichttp.WithHeaders(headersMap))
+		}
+		return otlpmetrichttp.New(context.Background(), opts...)
+	}
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(endpoint),
+	}
+	if !secure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if headersMap != nil {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(headersMap))
+	}
+	return otlpmetricgrpc.New(context.Background(), opts...)
+}
+
+// createOTLPLogExporter creates the log exporter to the collector configured by trace.otlp.
+func createOTLPLogExporter(params *paramtable.ComponentParam) (sdklog.Exporter, error) {
+	if err := checkOTLPConfig(params); err != nil {
+		return nil, err
+	}
+	endpoint := params.TraceCfg.OtlpEndpoint.GetValue()
+	secure := params.TraceCfg.OtlpSecure.GetAsBool()
+	headersMap := parseHeaders(params.TraceCfg.OtlpHeaders.GetValue())
+	var exp sdklog.Exporter
+	var err error
+	if params.TraceCfg.OtlpMethod.GetValue() == "http" {
+		opts := []otlploghttp.Option{
+			otlploghttp.WithEndpoint(endpoint),
+		}
+		if !secure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if headersMap != nil {
+			opts = append(opts, otlploghttp.WithHeaders(headersMap))
+		}
+		exp, err = otlploghttp.New(context.Background(), opts...)
+	} else {
+		opts := []otlploggrpc.Option{
+			otlploggrpc.WithEndpoint(endpoint),
+		}
+		if !secure {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		if headersMap != nil {
+			opts = append(opts, otlploggrpc.WithHeaders(headersMap))
+		}
+		exp, err = otlploggrpc.New(context.Background(), opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &countingLogExporter{Exporter: exp}, nil
+}
+
+// countingLogExporter counts the exported log records into the logging metrics.
+// It never logs the failures, they would be exported again.
+type countingLogExporter struct {
+	sdklog.Exporter
+}
+
+func (e *countingLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	if err := e.Exporter.Export(ctx, records); err != nil {
+		metrics.LoggingOTLPExportFailureTotal.Inc()
+		metrics.LoggingOTLPDroppedTotal.Add(float64(len(records)))
+		return err
+	}
+	metrics.LoggingOTLPExportedTotal.Add(float64(len(records)))
+	return nil
+}
@@ -0,0 +1,97 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracer
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	promb "go.opentelemetry.io/contrib/bridges/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.uber.org/zap"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// otlpRoleSubsystems lists the metric subsystems of the roles which don't match their role names.
+var otlpRoleSubsystems = map[string][]string{
+	typeutil.MixCoordRole: {typeutil.RootCoordRole, typeutil.DataCoordRole, typeutil.QueryCoordRole, typeutil.StreamingCoordRole},
+	typeutil.DataNodeRole: {typeutil.DataNodeRole, typeutil.IndexNodeRole},
+}
+
+// otlpMetricOwner returns the registered role which exports the metric family,
+// the families of the subsystems not owned by any registered role are exported by the first one.
+// The roles share one prometheus registry in standalone mode, so each family is exported by exactly one role.
+func otlpMetricOwner(name string, roles []string) string {
+	for _, role := range roles {
+		subsystems, ok := otlpRoleSubsystems[role]
+		if !ok {
+			subsystems = []string{role}
+		}
+		for _, subsystem := range subsystems {
+			if strings.HasPrefix(name, "milvus_"+subsystem+"_") {
+				return role
+			}
+		}
+	}
+	if len(roles) == 0 {
+		return ""
+	}
+	return roles[0]
+}
+
+// roleGatherer gathers the metric families owned by the role.
+type roleGatherer struct {
+	gatherer prometheus.Gatherer
+	role     string
+	roles    func() []string
+}
+
+func (g *roleGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+	// the gatherer returns what it could gather along with the error
+	if err != nil {
+		if len(families) == 0 {
+			return nil, err
+		}
+		log.RatedWarn(60, "gather metrics for otlp collector failed partially", zap.Error(err))
+	}
+	roles := g.roles()
+	owned := make([]*dto.MetricFamily, 0, len(families))
+	for _, family := range families {
+		if otlpMetricOwner(family.GetName(), roles) == g.role {
+			owned = append(owned, family)
+		}
+	}
+	return owned, nil
+}
+
+// newOTLPMeterProvider creates the meter provider pushing the metrics owned by the role periodically,
+// the prometheus endpoint keeps serving the same collectors.
+func newOTLPMeterProvider(exporter sdkmetric.Exporter, gatherer *roleGatherer, nodeID int64, interval time.Duration) *sdkmetric.MeterProvider {
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(interval),
+		sdkmetric.WithProducer(promb.NewMetricProducer(promb.WithGatherer(gatherer))),
+	)
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(newResource(gatherer.role, nodeID)),
+		sdkmetric.WithReader(reader),
+	)
+}
@@ -0,0 +1,302 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracer
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/milvus-io/milvus/pkg/v2/log"
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+	"github.com/milvus-io/milvus/pkg/v2/util/typeutil"
+)
+
+// otlpCollector is a stand-in of the otlp collector, it records the received requests.
+type otlpCollector struct {
+	colmetricspb.UnimplementedMetricsServiceServer
+
+	mu      sync.Mutex
+	metrics []*colmetricspb.ExportMetricsServiceRequest
+	logs    []*collogspb.ExportLogsServiceRequest
+	headers []string
+}
+
+func (c *otlpCollector) Export(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = append(c.metrics, req)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		c.headers = append(c.headers, md.Get("x-tenant")...)
+	}
+	return &colmetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+func (c *otlpCollector) getMetrics() []*colmetricspb.ExportMetricsServiceRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*colmetricspb.ExportMetricsServiceRequest(nil), c.metrics...)
+}
+
+func (c *otlpCollector) getLogs() []*collogspb.ExportLogsServiceRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*collogspb.ExportLogsServiceRequest(nil), c.logs...)
+}
+
+// otlpLogsCollector serves the logs service of the collector stand-in,
+// both services name their rpc Export so they can't share one receiver.
+type otlpLogsCollector struct {
+	collogspb.UnimplementedLogsServiceServer
+	*otlpCollector
+}
+
+func (c otlpLogsCollector) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logs = append(c.logs, req)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func startGrpcCollector(t *testing.T) (*otlpCollector, string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	collector := &otlpCollector{}
+	server := grpc.NewServer()
+	colmetricspb.RegisterMetricsServiceServer(server, collector)
+	collogspb.RegisterLogsServiceServer(server, otlpLogsCollector{otlpCollector: collector})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return collector, lis.Addr().String()
+}
+
+func startHTTPCollector(t *testing.T) (*otlpCollector, string) {
+	collector := &otlpCollector{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		collector.mu.Lock()
+		defer collector.mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/metrics":
+			req := &colmetricspb.ExportMetricsServiceRequest{}
+			if proto.Unmarshal(body, req) != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			collector.metrics = append(collector.metrics, req)
+			collector.headers = append(collector.headers, r.Header.Get("x-tenant"))
+		case "/v1/logs":
+			req := &collogspb.ExportLogsServiceRequest{}
+			if proto.Unmarshal(body, req) != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			collector.logs = append(collector.logs, req)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return collector, strings.TrimPrefix(server.URL, "http://")
+}
+
+func resourceAttribute(attrs []*commonpb.KeyValue, key string) *commonpb.AnyValue {
+	for _, kv := range attrs {
+		if kv.GetKey() == key {
+			return kv.GetValue()
+		}
+	}
+	return nil
+}
+
+// collectedMetrics returns the names of the metrics received by the collector, keyed by the role of the resource.
+func collectedMetrics(collector *otlpCollector) (map[string][]string, map[string]*resourcepb.Resource) {
+	names := make(map[string][]string)
+	resources := make(map[string]*resourcepb.Resource)
+	for _, req := range collector.getMetrics() {
+		for _, rm := range req.GetResourceMetrics() {
+			role := resourceAttribute(rm.GetResource().GetAttributes(), "Role").GetStringValue()
+			resources[role] = rm.GetResource()
+			for _, sm := range rm.GetScopeMetrics() {
+				for _, m := range sm.GetMetrics() {
+					names[role] = append(names[role], m.GetName())
+				}
+			}
+		}
+	}
+	return names, resources
+}
+
+func TestOTLPMetricOwner(t *testing.T) {
+	roles := []string{typeutil.ProxyRole, typeutil.MixCoordRole, typeutil.DataNodeRole}
+	assert.Equal(t, typeutil.ProxyRole, otlpMetricOwner("milvus_proxy_req_count", roles))
+	assert.Equal(t, typeutil.MixCoordRole, otlpMetricOwner("milvus_rootcoord_ddl_req_count", roles))
+	assert.Equal(t, typeutil.MixCoordRole, otlpMetricOwner("milvus_datacoord_segment_num", roles))
+	assert.Equal(t, typeutil.DataNodeRole, otlpMetricOwner("milvus_indexnode_build_index_latency", roles))
+	// the metrics of no registered role are exported by the first role
+	assert.Equal(t, typeutil.ProxyRole, otlpMetricOwner("milvus_querynode_search_latency", roles))
+	assert.Equal(t, typeutil.ProxyRole, otlpMetricOwner("go_goroutines", roles))
+	assert.Equal(t, "", otlpMetricOwner("go_goroutines", nil))
+}
+
+func TestRoleGatherer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	for _, name := range []string{"milvus_proxy_req", "milvus_querynode_search", "process_cpu"} {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: name})
+		registry.MustRegister(gauge)
+		gauge.Set(1)
+	}
+	roles := func() []string { return []string{typeutil.QueryNodeRole, typeutil.ProxyRole} }
+	gatherNames := func(role string) []string {
+		families, err := (&roleGatherer{gatherer: registry, role: role, roles: roles}).Gather()
+		require.NoError(t, err)
+		names := make([]string, 0, len(families))
+		for _, family := range families {
+			names = append(names, family.GetName())
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"milvus_querynode_search", "process_cpu"}, gatherNames(typeutil.QueryNodeRole))
+	assert.ElementsMatch(t, []string{"milvus_proxy_req"}, gatherNames(typeutil.ProxyRole))
+}
+
+func TestCreateOTLPExporters(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	defer params.Reset(params.TraceCfg.OtlpEndpoint.Key)
+	defer params.Reset(params.TraceCfg.OtlpMethod.Key)
+	defer params.Reset(params.TraceCfg.OtlpMetricsEnabled.Key)
+
+	params.Save(params.TraceCfg.OtlpEndpoint.Key, "")
+	_, err := createOTLPMetricExporter(params)
+	assert.Error(t, err)
+	_, err = createOTLPLogExporter(params)
+	assert.Error(t, err)
+
+	params.Save(params.TraceCfg.OtlpEndpoint.Key, "127.0.0.1:4317")
+	params.Save(params.TraceCfg.OtlpMethod.Key, "udp")
+	_, err = createOTLPMetricExporter(params)
+	assert.Error(t, err)
+	_, err = createOTLPLogExporter(params)
+	assert.Error(t, err)
+	params.Save(params.TraceCfg.OtlpMetricsEnabled.Key, "true")
+	assert.Error(t, InitOTLP(prometheus.NewRegistry()))
+}
+
+func TestRegisterOTLPRole(t *testing.T) {
+	paramtable.Init()
+	params := paramtable.Get()
+	params.Save(params.TraceCfg.OtlpSecure.Key, "false")
+	params.Save(params.TraceCfg.OtlpHeaders.Key, `{"x-tenant":"milvus"}`)
+	params.Save(params.TraceCfg.OtlpLogsEnabled.Key, "true")
+	params.Save(params.TraceCfg.OtlpLogsLevel.Key, "warn")
+	params.Save(params.TraceCfg.OtlpMetricsEnabled.Key, "true")
+	defer params.Reset(params.TraceCfg.OtlpSecure.Key)
+	defer params.Reset(params.TraceCfg.OtlpHeaders.Key)
+	defer params.Reset(params.TraceCfg.OtlpLogsEnabled.Key)
+	defer params.Reset(params.TraceCfg.OtlpLogsLevel.Key)
+	defer params.Reset(params.TraceCfg.OtlpMetricsEnabled.Key)
+	defer params.Reset(params.TraceCfg.OtlpEndpoint.Key)
+	defer params.Reset(params.TraceCfg.OtlpMethod.Key)
+
+	registry := prometheus.NewRegistry()
+	for _, name := range []string{"milvus_proxy_req", "milvus_querynode_search"} {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: name})
+		registry.MustRegister(gauge)
+		gauge.Set(1)
+	}
+
+	for _, method := range []string{"grpc", "http"} {
+		t.Run(method, func(t *testing.T) {
+			var collector *otlpCollector
+			var endpoint string
+			if method == "grpc" {
+				collector, endpoint = startGrpcCollector(t)
+			} else {
+				collector, endpoint = startHTTPCollector(t)
+			}
+			params.Save(params.TraceCfg.OtlpEndpoint.Key, endpoint)
+			params.Save(params.TraceCfg.OtlpMethod.Key, method)
+
+			require.NoError(t, InitOTLP(registry))
+			log.Warn("before the registration")
+			// the roles register with the node id assigned by the session
+			RegisterOTLPRole(typeutil.ProxyRole, 7)
+			RegisterOTLPRole(typeutil.QueryNodeRole, 7)
+			RegisterOTLPRole(typeutil.QueryNodeRole, 7)
+			log.Info("below the otlp log level")
+			log.Warn("exported to otlp")
+			CloseOTLP()
+			// closing twice is fine
+			CloseOTLP()
+
+			names, resources := collectedMetrics(collector)
+			require.Len(t, resources, 2)
+			assert.ElementsMatch(t, []string{"milvus_proxy_req"}, names[typeutil.ProxyRole])
+			assert.ElementsMatch(t, []string{"milvus_querynode_search"}, names[typeutil.QueryNodeRole])
+			for role, resource := range resources {
+				assert.Equal(t, int64(7), resourceAttribute(resource.GetAttributes(), "NodeID").GetIntValue())
+				assert.Equal(t, role, resourceAttribute(resource.GetAttributes(), "service.name").GetStringValue())
+				assert.Equal(t, params.CommonCfg.ClusterPrefix.GetValue(), resourceAttribute(resource.GetAttributes(), "Cluster").GetStringValue())
+			}
+			assert.Contains(t, collector.headers, "milvus")
+
+			var messages []string
+			for _, req := range collector.getLogs() {
+				for _, rl := range req.GetResourceLogs() {
+					assert.Equal(t, int64(7), resourceAttribute(rl.GetResource().GetAttributes(), "NodeID").GetIntValue())
+					assert.Equal(t, paramtable.GetRole(), resourceAttribute(rl.GetResource().GetAttributes(), "Role").GetStringValue())
+					for _, sl := range rl.GetScopeLogs() {
+						for _, record := range sl.GetLogRecords() {
+							messages = append(messages, record.GetBody().GetStringValue())
+						}
+					}
+				}
+			}
+			assert.Contains(t, messages, "exported to otlp")
+			assert.NotContains(t, messages, "below the otlp log level")
+			assert.NotContains(t, messages, "before the registration")
+		})
+	}
+}
+
+func TestRegisterOTLPRoleNotInitialized(t *testing.T) {
+	// nothing is started if otlp metrics and logs are not enabled
+	RegisterOTLPRole(typeutil.ProxyRole, 1)
+	assert.Empty(t, getOTLPRoles())
+	CloseOTLP()
+}
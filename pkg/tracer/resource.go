@@ -0,0 +1,41 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracer
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/paramtable"
+)
+
+// ResourceAttributes returns the attributes identifying the role running on the node,
+// traces, metrics and logs share them so that they can be correlated by the backend.
+func ResourceAttributes(role string, nodeID int64) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.ServiceNameKey.String(role),
+		attribute.String("Cluster", paramtable.Get().CommonCfg.ClusterPrefix.GetValue()),
+		attribute.String("Role", role),
+		attribute.Int64("NodeID", nodeID),
+	}
+}
+
+// newResource returns the otel resource of the role running on the node.
+func newResource(role string, nodeID int64) *resource.Resource {
+	return resource.NewWithAttributes(semconv.SchemaURL, ResourceAttributes(role, nodeID)...)
+}
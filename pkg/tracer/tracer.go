@@ -23,15 +23,12 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/jaeger"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	stdout "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
 	sdk "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.uber.org/zap"
 
 	"github.com/milvus-io/milvus/pkg/v2/log"
@@ -67,11 +64,7 @@ func CloseTracerProvider(ctx context.Context) error {
 func SetTracerProvider(exp sdk.SpanExporter, traceIDRatio float64) {
 	tp := sdk.NewTracerProvider(
 		sdk.WithBatcher(exp),
-		sdk.WithResource(resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceNameKey.String(paramtable.GetRole()),
-			attribute.Int64("NodeID", paramtable.GetNodeID()),
-		)),
+		sdk.WithResource(newResource(paramtable.GetRole(), paramtable.GetNodeID())),
 		sdk.WithSampler(sdk.ParentBased(
 			sdk.TraceIDRatioBased(traceIDRatio),
 		)),
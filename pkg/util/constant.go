@@ -63,8 +63,11 @@ const (
 
 	IdentifierKey = "identifier"
 
-	HeaderUserAgent = "user-agent"
-	HeaderDBName    = "dbName"
+	HeaderUserAgent       = "user-agent"
+	HeaderDBName          = "dbName"
+	HeaderPriorityClass   = "priority-class"
+	HeaderExplainMode     = "explain-mode"
+	HeaderPartitionAccess = "partition-access"
 
 	RoleConfigPrivileges = "privileges"
 	RoleConfigObjectType = "object_type"
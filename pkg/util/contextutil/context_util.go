@@ -167,3 +167,37 @@ func GetQueryLabel(ctx context.Context) string {
 	}
 	return metrics.QueryLabel
 }
+
+// MetadataHeader is a header which is passed along the rpc calls in the grpc metadata.
+type MetadataHeader string
+
+const (
+	// PriorityClassHeader carries the priority class the request is scheduled with on query nodes.
+	PriorityClassHeader MetadataHeader = util.HeaderPriorityClass
+	// ExplainModeHeader asks the query nodes to explain the request.
+	ExplainModeHeader MetadataHeader = util.HeaderExplainMode
+	// PartitionAccessHeader reports the partitions shown by ShowLoadPartitions are accessed by search or query.
+	PartitionAccessHeader MetadataHeader = util.HeaderPartitionAccess
+)
+
+// Get returns the value of the header carried in the metadata of the incoming request,
+// "" is returned if the request doesn't carry the header.
+func (h MetadataHeader) Get(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(string(h))
+	if len(values) < 1 {
+		return ""
+	}
+	return values[0]
+}
+
+// With passes the header to the rpc calls made with the returned context, empty value is not passed.
+func (h MetadataHeader) With(ctx context.Context, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, string(h), value)
+}
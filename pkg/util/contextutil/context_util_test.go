@@ -117,3 +117,19 @@ func TestQueryLabel(t *testing.T) {
 	ctx2 := WithQueryLabel(context.Background(), "")
 	assert.Equal(t, "query", GetQueryLabel(ctx2))
 }
+
+func TestMetadataHeader(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", PriorityClassHeader.Get(ctx))
+
+	ctx = AppendToIncomingContext(ctx, util.HeaderPriorityClass, "batch")
+	assert.Equal(t, "batch", PriorityClassHeader.Get(ctx))
+	assert.Equal(t, "", ExplainModeHeader.Get(ctx))
+
+	outgoing := ExplainModeHeader.With(ctx, "explain")
+	md, ok := metadata.FromOutgoingContext(outgoing)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"explain"}, md.Get(util.HeaderExplainMode))
+
+	assert.Equal(t, ctx, PriorityClassHeader.With(ctx, ""))
+}
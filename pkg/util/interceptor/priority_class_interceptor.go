@@ -0,0 +1,54 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/contextutil"
+)
+
+// PriorityClassUnaryServerInterceptor returns a new unary server interceptor that
+// passes the priority class of the incoming request to the rpc calls made while handling it,
+// so the class reaches every query node serving the request.
+func PriorityClassUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(forwardPriorityClass(ctx), req)
+	}
+}
+
+// PriorityClassStreamServerInterceptor returns a new streaming server interceptor that
+// passes the priority class of the incoming request to the rpc calls made while handling it.
+func PriorityClassStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		newCtx := forwardPriorityClass(ctx)
+		if newCtx == ctx {
+			return handler(srv, ss)
+		}
+		wrappedStream := grpc_middleware.WrapServerStream(ss)
+		wrappedStream.WrappedContext = newCtx
+		return handler(srv, wrappedStream)
+	}
+}
+
+func forwardPriorityClass(ctx context.Context) context.Context {
+	return contextutil.PriorityClassHeader.With(ctx, contextutil.PriorityClassHeader.Get(ctx))
+}
@@ -0,0 +1,71 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/milvus-io/milvus/pkg/v2/util"
+)
+
+func TestPriorityClassInterceptor(t *testing.T) {
+	t.Run("test PriorityClassUnaryServerInterceptor", func(t *testing.T) {
+		var outgoing []string
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			outgoing = md.Get(util.HeaderPriorityClass)
+			return nil, nil
+		}
+		interceptor := PriorityClassUnaryServerInterceptor()
+
+		// no class in md
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Empty(t, outgoing)
+
+		md := metadata.Pairs(util.HeaderPriorityClass, "batch")
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"batch"}, outgoing)
+	})
+
+	t.Run("test PriorityClassStreamServerInterceptor", func(t *testing.T) {
+		var outgoing []string
+		handler := func(srv interface{}, stream grpc.ServerStream) error {
+			md, _ := metadata.FromOutgoingContext(stream.Context())
+			outgoing = md.Get(util.HeaderPriorityClass)
+			return nil
+		}
+		interceptor := PriorityClassStreamServerInterceptor()
+
+		err := interceptor(nil, newMockSS(context.Background()), nil, handler)
+		assert.NoError(t, err)
+		assert.Empty(t, outgoing)
+
+		md := metadata.Pairs(util.HeaderPriorityClass, "interactive")
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		err = interceptor(nil, newMockSS(ctx), nil, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"interactive"}, outgoing)
+	})
+}
@@ -82,6 +82,9 @@ const (
 	// SyncTaskKey request for get sync tasks from the datanode
 	SyncTaskKey = "sync_tasks"
 
+	// SlowQueryKey request for get slow queries from the proxy
+	SlowQueryKey = "slow_query"
+
 	// MetricRequestParamVerboseKey as a request parameter decide to whether return verbose value
 	MetricRequestParamVerboseKey = "verbose"
 
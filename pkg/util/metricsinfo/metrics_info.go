@@ -129,6 +129,37 @@ type SlowQuery struct {
 	QueryParams           *QueryParams `json:"query_params,omitempty"`
 	Type                  string       `json:"type,omitempty"`
 	TraceID               string       `json:"trace_id,omitempty"`
+
+	NodeID      int64             `json:"node_id,omitempty,string"`
+	Timestamp   int64             `json:"timestamp,omitempty"` // in unix milliseconds
+	DurationMs  float64           `json:"duration_ms,omitempty"`
+	NQ          int64             `json:"nq,omitempty"`
+	TopK        int64             `json:"topk,omitempty"`
+	PlanSummary string            `json:"plan_summary,omitempty"`
+	Stages      []*SlowQueryStage `json:"stages,omitempty"`
+}
+
+type SlowQueryStage struct {
+	Name       string  `json:"name,omitempty"`
+	DurationMs float64 `json:"duration_ms,omitempty"`
+}
+
+// SlowQueryLatency is the latency summary of a group of slow queries.
+type SlowQueryLatency struct {
+	Count int     `json:"count"`
+	AvgMs float64 `json:"avg_ms"`
+	P50Ms float64 `json:"p50_ms"`
+	P90Ms float64 `json:"p90_ms"`
+	P99Ms float64 `json:"p99_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+type SlowQueryStats struct {
+	Total *SlowQueryLatency `json:"total,omitempty"`
+	// keyed by "database.collection"
+	Collections map[string]*SlowQueryLatency `json:"collections,omitempty"`
+	// keyed by the stage name
+	Stages map[string]*SlowQueryLatency `json:"stages,omitempty"`
 }
 
 type DmChannel struct {
@@ -68,6 +68,7 @@ func NewSlowQueryWithQueryRequest(request *milvuspb.QueryRequest, user string, c
 		OutputFields: strings.Join(request.GetOutputFields(), ","),
 	}
 
+	now := time.Now()
 	return &SlowQuery{
 		Role:                  typeutil.ProxyRole,
 		Database:              request.GetDbName(),
@@ -81,7 +82,9 @@ func NewSlowQueryWithQueryRequest(request *milvuspb.QueryRequest, user string, c
 		QueryParams:           queryParams,
 		Type:                  "Query",
 		TraceID:               traceID,
-		Time:                  time.Now().Format(time.DateTime),
+		Time:                  now.Format(time.DateTime),
+		Timestamp:             now.UnixMilli(),
+		DurationMs:            float64(cost.Microseconds()) / 1000.0,
 	}
 }
 
@@ -113,6 +116,7 @@ func NewSlowQueryWithSearchRequest(request *milvuspb.SearchRequest, user string,
 		OutputFields: strings.Join(request.GetOutputFields(), ","),
 	}
 
+	now := time.Now()
 	return &SlowQuery{
 		Role:                  typeutil.ProxyRole,
 		Database:              request.GetDbName(),
@@ -126,7 +130,9 @@ func NewSlowQueryWithSearchRequest(request *milvuspb.SearchRequest, user string,
 		QueryParams:           queryParams,
 		Type:                  searchType,
 		TraceID:               traceID,
-		Time:                  time.Now().Format(time.DateTime),
+		Time:                  now.Format(time.DateTime),
+		Timestamp:             now.UnixMilli(),
+		DurationMs:            float64(cost.Microseconds()) / 1000.0,
 	}
 }
 
@@ -76,6 +76,8 @@ func TestNewSlowQueryWithSearchRequest(t *testing.T) {
 	assert.True(t, slowQuery.UseDefaultConsistency)
 	assert.Equal(t, uint64(123456789), slowQuery.GuaranteeTimestamp)
 	assert.Equal(t, "100ms", slowQuery.Duration)
+	assert.Equal(t, 100.0, slowQuery.DurationMs)
+	assert.NotZero(t, slowQuery.Timestamp)
 	assert.Equal(t, user, slowQuery.User)
 	assert.Equal(t, "HybridSearch", slowQuery.Type)
 	assert.NotNil(t, slowQuery.QueryParams)
@@ -112,6 +114,8 @@ func TestNewSlowQueryWithQueryRequest(t *testing.T) {
 	assert.True(t, slowQuery.UseDefaultConsistency)
 	assert.Equal(t, uint64(123456789), slowQuery.GuaranteeTimestamp)
 	assert.Equal(t, "100ms", slowQuery.Duration)
+	assert.Equal(t, 100.0, slowQuery.DurationMs)
+	assert.NotZero(t, slowQuery.Timestamp)
 	assert.Equal(t, user, slowQuery.User)
 	assert.Equal(t, "Query", slowQuery.Type)
 	assert.NotNil(t, slowQuery.QueryParams)
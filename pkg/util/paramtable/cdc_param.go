@@ -0,0 +1,131 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package paramtable
+
+type cdcConfig struct {
+	SinkEnabled            ParamItem  `refreshable:"false"`
+	SinkType               ParamItem  `refreshable:"false"`
+	SinkFormat             ParamItem  `refreshable:"false"`
+	SinkCollections        ParamItem  `refreshable:"false"`
+	SinkRoute              ParamItem  `refreshable:"false"`
+	SinkKafkaBrokers       ParamItem  `refreshable:"false"`
+	SinkKafkaProducer      ParamGroup `refreshable:"false"`
+	SinkWebhookTimeout     ParamItem  `refreshable:"true"`
+	SinkWebhookHeaders     ParamGroup `refreshable:"false"`
+	SinkMaxRetries         ParamItem  `refreshable:"true"`
+	SinkCheckpointInterval ParamItem  `refreshable:"true"`
+}
+
+func (p *cdcConfig) init(base *BaseTable) {
+	p.SinkEnabled = ParamItem{
+		Key:          "cdc.sink.enabled",
+		DefaultValue: "false",
+		Version:      "3.0.0",
+		Doc: `Whether to publish the inserts and deletes of the collections to the sink.
+The changes are published at least once, the progress is checkpointed into the meta storage.`,
+		Export: true,
+	}
+	p.SinkEnabled.Init(base.mgr)
+
+	p.SinkType = ParamItem{
+		Key:          "cdc.sink.type",
+		DefaultValue: "kafka",
+		Version:      "3.0.0",
+		Doc:          "The type of the sink, one of kafka, webhook and file",
+		Export:       true,
+	}
+	p.SinkType.Init(base.mgr)
+
+	p.SinkFormat = ParamItem{
+		Key:          "cdc.sink.format",
+		DefaultValue: "json",
+		Version:      "3.0.0",
+		Doc: `The encoding of the published rows, one of json and avro.
+The avro rows are published as single object encoding with the writer schema in the avro.schema header into kafka,
+and as object container files into the webhooks and the files.`,
+		Export: true,
+	}
+	p.SinkFormat.Init(base.mgr)
+
+	p.SinkCollections = ParamItem{
+		Key:          "cdc.sink.collections",
+		DefaultValue: "",
+		Version:      "3.0.0",
+		Doc:          "The comma separated collections to publish, as db.collection or collection of the default database, empty means all",
+		Export:       true,
+	}
+	p.SinkCollections.Init(base.mgr)
+
+	p.SinkRoute = ParamItem{
+		Key:          "cdc.sink.route",
+		DefaultValue: "milvus-cdc-{db}-{collection}",
+		Version:      "3.0.0",
+		Doc: `The route of the changes of a collection, the kafka topic, the webhook url or the file path.
+The {db} and {collection} placeholders are replaced by the database and the collection of the changes.`,
+		Export: true,
+	}
+	p.SinkRoute.Init(base.mgr)
+
+	p.SinkKafkaBrokers = ParamItem{
+		Key:          "cdc.sink.kafka.brokers",
+		DefaultValue: "localhost:9092",
+		Version:      "3.0.0",
+		Doc:          "The comma separated bootstrap servers of the kafka sink",
+		Export:       true,
+	}
+	p.SinkKafkaBrokers.Init(base.mgr)
+
+	p.SinkKafkaProducer = ParamGroup{
+		KeyPrefix: "cdc.sink.kafka.producerConfig.",
+		Version:   "3.0.0",
+	}
+	p.SinkKafkaProducer.Init(base.mgr)
+
+	p.SinkWebhookTimeout = ParamItem{
+		Key:          "cdc.sink.webhook.timeout",
+		DefaultValue: "10",
+		Version:      "3.0.0",
+		Doc:          "The timeout in seconds of a webhook request",
+		Export:       true,
+	}
+	p.SinkWebhookTimeout.Init(base.mgr)
+
+	p.SinkWebhookHeaders = ParamGroup{
+		KeyPrefix: "cdc.sink.webhook.headers.",
+		Version:   "3.0.0",
+	}
+	p.SinkWebhookHeaders.Init(base.mgr)
+
+	p.SinkMaxRetries = ParamItem{
+		Key:          "cdc.sink.maxRetries",
+		DefaultValue: "10",
+		Version:      "3.0.0",
+		Doc:          "The max retries of a failed publish before the publisher restarts from the last checkpoint",
+		Export:       true,
+	}
+	p.SinkMaxRetries.Init(base.mgr)
+
+	p.SinkCheckpointInterval = ParamItem{
+		Key:          "cdc.sink.checkpointInterval",
+		DefaultValue: "1s",
+		Version:      "3.0.0",
+		Doc: `The interval to save the checkpoint of the published changes,
+the changes after the last checkpoint are published again after a restart.`,
+		Export: true,
+	}
+	p.SinkCheckpointInterval.Init(base.mgr)
+}
@@ -0,0 +1,41 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package paramtable
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCDCConfig_Init(t *testing.T) {
+	params := ComponentParam{}
+	params.Init(NewBaseTable(SkipRemote(true)))
+	cfg := &params.CDCCfg
+	assert.False(t, cfg.SinkEnabled.GetAsBool())
+	assert.Equal(t, "kafka", cfg.SinkType.GetValue())
+	assert.Equal(t, "json", cfg.SinkFormat.GetValue())
+	assert.Empty(t, cfg.SinkCollections.GetAsStrings())
+	assert.Equal(t, "milvus-cdc-{db}-{collection}", cfg.SinkRoute.GetValue())
+	assert.Equal(t, 10*time.Second, cfg.SinkWebhookTimeout.GetAsDuration(time.Second))
+	assert.Equal(t, time.Second, cfg.SinkCheckpointInterval.GetAsDurationByParse())
+
+	params.Save("cdc.sink.kafka.producerConfig.acks", "all")
+	defer params.Reset("cdc.sink.kafka.producerConfig.acks")
+	assert.Equal(t, map[string]string{"acks": "all"}, cfg.SinkKafkaProducer.GetValue())
+}
@@ -90,6 +90,7 @@ type ComponentParam struct {
 	RoleCfg        roleConfig
 	RbacConfig     rbacConfig
 	StreamingCfg   streamingConfig
+	CDCCfg         cdcConfig
 	FunctionCfg    functionConfig
 	CredentialCfg  credentialConfig
 
@@ -141,6 +142,7 @@ func (p *ComponentParam) init(bt *BaseTable) {
 	p.DataCoordCfg.init(bt)
 	p.DataNodeCfg.init(bt)
 	p.StreamingCfg.init(bt)
+	p.CDCCfg.init(bt)
 	p.HTTPCfg.init(bt)
 	p.LogCfg.init(bt)
 	p.RoleCfg.init(bt)
@@ -173,6 +175,14 @@ func (p *ComponentParam) init(bt *BaseTable) {
 	p.IntegrationTestCfg.init(bt)
 }
 
+// GetStreamingMetaStoreType returns the meta store type of the streaming coordinator and streaming node.
+func (p *ComponentParam) GetStreamingMetaStoreType() string {
+	if metaType := p.StreamingCfg.MetaStoreType.GetValue(); metaType != "" {
+		return metaType
+	}
+	return p.MetaStoreCfg.MetaStoreType.GetValue()
+}
+
 func (p *ComponentParam) GetComponentConfigurations(componentName string, sub string) map[string]string {
 	allownPrefixs := append(globalConfigPrefixs(), componentName+".")
 	return p.baseTable.mgr.GetBy(config.WithSubstr(sub), config.WithOneOfPrefixs(allownPrefixs...))
@@ -1495,6 +1505,11 @@ type traceConfig struct {
 	OtlpSecure         ParamItem `refreshable:"false"`
 	OtlpHeaders        ParamItem `refreshable:"false"`
 	InitTimeoutSeconds ParamItem `refreshable:"false"`
+
+	OtlpMetricsEnabled  ParamItem `refreshable:"false"`
+	OtlpMetricsInterval ParamItem `refreshable:"false"`
+	OtlpLogsEnabled     ParamItem `refreshable:"false"`
+	OtlpLogsLevel       ParamItem `refreshable:"false"`
 }
 
 func (t *traceConfig) init(base *BaseTable) {
@@ -1569,6 +1584,42 @@ Fractions >= 1 will always sample. Fractions < 0 are treated as zero.`,
 		Doc:          "segcore initialization timeout in seconds, preventing otlp grpc hangs forever",
 	}
 	t.InitTimeoutSeconds.Init(base.mgr)
+
+	t.OtlpMetricsEnabled = ParamItem{
+		Key:          "trace.otlp.metrics.enabled",
+		Version:      "3.0.0",
+		DefaultValue: "false",
+		Doc:          "export the prometheus metrics to the otlp endpoint as well, prometheus scraping is not affected",
+		Export:       true,
+	}
+	t.OtlpMetricsEnabled.Init(base.mgr)
+
+	t.OtlpMetricsInterval = ParamItem{
+		Key:          "trace.otlp.metrics.exportInterval",
+		Version:      "3.0.0",
+		DefaultValue: "15",
+		Doc:          "interval in seconds between two otlp metrics exports",
+		Export:       true,
+	}
+	t.OtlpMetricsInterval.Init(base.mgr)
+
+	t.OtlpLogsEnabled = ParamItem{
+		Key:          "trace.otlp.logs.enabled",
+		Version:      "3.0.0",
+		DefaultValue: "false",
+		Doc:          "export the logs to the otlp endpoint as well, the log files are not affected",
+		Export:       true,
+	}
+	t.OtlpLogsEnabled.Init(base.mgr)
+
+	t.OtlpLogsLevel = ParamItem{
+		Key:          "trace.otlp.logs.level",
+		Version:      "3.0.0",
+		DefaultValue: "info",
+		Doc:          "minimal level of the logs exported to the otlp endpoint, logs below log.level are never exported",
+		Export:       true,
+	}
+	t.OtlpLogsLevel.Init(base.mgr)
 }
 
 type holmesConfig struct {
@@ -1993,6 +2044,34 @@ type AccessLogConfig struct {
 	CacheFlushInterval ParamItem `refreshable:"false"`
 }
 
+type SlowQueryLogConfig struct {
+	Enable        ParamItem `refreshable:"false"`
+	LocalPath     ParamItem `refreshable:"false"`
+	Filename      ParamItem `refreshable:"false"`
+	MaxSize       ParamItem `refreshable:"false"`
+	RotatedTime   ParamItem `refreshable:"false"`
+	MaxBackups    ParamItem `refreshable:"false"`
+	MinioEnable   ParamItem `refreshable:"false"`
+	RemotePath    ParamItem `refreshable:"false"`
+	RemoteMaxTime ParamItem `refreshable:"false"`
+	Capacity      ParamItem `refreshable:"false"`
+	MaxAge        ParamItem `refreshable:"true"`
+}
+
+type WorkloadCaptureConfig struct {
+	Enable        ParamItem `refreshable:"false"`
+	SampleRate    ParamItem `refreshable:"true"`
+	Methods       ParamItem `refreshable:"true"`
+	LocalPath     ParamItem `refreshable:"false"`
+	Filename      ParamItem `refreshable:"false"`
+	MaxSize       ParamItem `refreshable:"false"`
+	RotatedTime   ParamItem `refreshable:"false"`
+	MaxBackups    ParamItem `refreshable:"false"`
+	MinioEnable   ParamItem `refreshable:"false"`
+	RemotePath    ParamItem `refreshable:"false"`
+	RemoteMaxTime ParamItem `refreshable:"false"`
+}
+
 type proxyConfig struct {
 	// Alias  string
 	SoPath ParamItem `refreshable:"false"`
@@ -2047,9 +2126,18 @@ type proxyConfig struct {
 	GracefulStopTimeout ParamItem `refreshable:"true"`
 
 	SlowQuerySpanInSeconds ParamItem `refreshable:"true"`
+	SlowQueryLog           SlowQueryLogConfig
+	WorkloadCapture        WorkloadCaptureConfig
 	QueryNodePoolingSize   ParamItem `refreshable:"false"`
 
 	HybridSearchRequeryPolicy ParamItem `refreshable:"true"`
+
+	// priority class of requests without an explicit one
+	PriorityClassUsers     ParamItem `refreshable:"true"`
+	PriorityClassRoles     ParamItem `refreshable:"true"`
+	PriorityClassDatabases ParamItem `refreshable:"true"`
+
+	PartitionLoadOnDemandReportInterval ParamItem `refreshable:"true"`
 }
 
 func (p *proxyConfig) init(base *BaseTable) {
@@ -2563,6 +2651,204 @@ Disabled if the value is less or equal to 0.`,
 	}
 	p.SlowQuerySpanInSeconds.Init(base.mgr)
 
+	p.SlowQueryLog.Enable = ParamItem{
+		Key:          "proxy.slowQueryLog.enable",
+		Version:      "3.0.0",
+		DefaultValue: "false",
+		Doc:          "Whether to persist the slow queries into the local log files. The slow queries are only kept in memory if disabled.",
+		Export:       true,
+	}
+	p.SlowQueryLog.Enable.Init(base.mgr)
+
+	p.SlowQueryLog.LocalPath = ParamItem{
+		Key:          "proxy.slowQueryLog.localPath",
+		Version:      "3.0.0",
+		DefaultValue: "/tmp/milvus_slow_query",
+		Doc:          "The local folder path where the slow query log file is stored.",
+		Export:       true,
+	}
+	p.SlowQueryLog.LocalPath.Init(base.mgr)
+
+	p.SlowQueryLog.Filename = ParamItem{
+		Key:          "proxy.slowQueryLog.filename",
+		Version:      "3.0.0",
+		DefaultValue: "slow_query.log",
+		Doc:          "The name of the slow query log file, each line of the file is a slow query in json.",
+		Export:       true,
+	}
+	p.SlowQueryLog.Filename.Init(base.mgr)
+
+	p.SlowQueryLog.MaxSize = ParamItem{
+		Key:          "proxy.slowQueryLog.maxSize",
+		Version:      "3.0.0",
+		DefaultValue: "64",
+		Doc:          "The maximum size of a single slow query log file before it's rotated. Unit: MB.",
+		Export:       true,
+	}
+	p.SlowQueryLog.MaxSize.Init(base.mgr)
+
+	p.SlowQueryLog.RotatedTime = ParamItem{
+		Key:          "proxy.slowQueryLog.rotatedTime",
+		Version:      "3.0.0",
+		DefaultValue: "0",
+		Doc:          "The maximum time interval of rotating a single slow query log file, 0 means never rotated by time. Unit: seconds.",
+		Export:       true,
+	}
+	p.SlowQueryLog.RotatedTime.Init(base.mgr)
+
+	p.SlowQueryLog.MaxBackups = ParamItem{
+		Key:          "proxy.slowQueryLog.maxBackups",
+		Version:      "3.0.0",
+		DefaultValue: "8",
+		Doc:          "The maximum number of sealed slow query log files retained locally.",
+		Export:       true,
+	}
+	p.SlowQueryLog.MaxBackups.Init(base.mgr)
+
+	p.SlowQueryLog.MinioEnable = ParamItem{
+		Key:          "proxy.slowQueryLog.minioEnable",
+		Version:      "3.0.0",
+		DefaultValue: "false",
+		Doc:          "Whether to upload the sealed slow query log files to the object storage.",
+		Export:       true,
+	}
+	p.SlowQueryLog.MinioEnable.Init(base.mgr)
+
+	p.SlowQueryLog.RemotePath = ParamItem{
+		Key:          "proxy.slowQueryLog.remotePath",
+		Version:      "3.0.0",
+		DefaultValue: "slow_query_log/",
+		Doc:          "The path of the object storage for uploading slow query log files.",
+		Export:       true,
+	}
+	p.SlowQueryLog.RemotePath.Init(base.mgr)
+
+	p.SlowQueryLog.RemoteMaxTime = ParamItem{
+		Key:          "proxy.slowQueryLog.remoteMaxTime",
+		Version:      "3.0.0",
+		DefaultValue: "0",
+		Doc:          "The retention time of the slow query log files uploaded to the object storage, 0 means retained forever. Unit: hours.",
+		Export:       true,
+	}
+	p.SlowQueryLog.RemoteMaxTime.Init(base.mgr)
+
+	p.SlowQueryLog.Capacity = ParamItem{
+		Key:          "proxy.slowQueryLog.capacity",
+		Version:      "3.0.0",
+		DefaultValue: "1000",
+		Doc:          "The maximum number of the latest slow queries kept in memory for querying, the kept slow queries are reloaded from the local log files after restart.",
+		Export:       true,
+	}
+	p.SlowQueryLog.Capacity.Init(base.mgr)
+
+	p.SlowQueryLog.MaxAge = ParamItem{
+		Key:          "proxy.slowQueryLog.maxAge",
+		Version:      "3.0.0",
+		DefaultValue: "24",
+		Doc:          "The slow queries older than it are no longer served by the slow query api. Unit: hours.",
+		Export:       true,
+	}
+	p.SlowQueryLog.MaxAge.Init(base.mgr)
+
+	p.WorkloadCapture.Enable = ParamItem{
+		Key:          "proxy.workloadCapture.enable",
+		Version:      "3.0.0",
+		DefaultValue: "false",
+		Doc:          "Whether to capture the sampled requests into the local workload files, which can be replayed against another cluster by the milvus replay command.",
+		Export:       true,
+	}
+	p.WorkloadCapture.Enable.Init(base.mgr)
+
+	p.WorkloadCapture.SampleRate = ParamItem{
+		Key:          "proxy.workloadCapture.sampleRate",
+		Version:      "3.0.0",
+		DefaultValue: "0.01",
+		Doc:          "The ratio of the requests captured, in the range of [0, 1].",
+		Export:       true,
+	}
+	p.WorkloadCapture.SampleRate.Init(base.mgr)
+
+	p.WorkloadCapture.Methods = ParamItem{
+		Key:          "proxy.workloadCapture.methods",
+		Version:      "3.0.0",
+		DefaultValue: "Search,HybridSearch,Query,Insert,Upsert,Delete",
+		Doc:          "The methods of the requests captured, separated by comma.",
+		Export:       true,
+	}
+	p.WorkloadCapture.Methods.Init(base.mgr)
+
+	p.WorkloadCapture.LocalPath = ParamItem{
+		Key:          "proxy.workloadCapture.localPath",
+		Version:      "3.0.0",
+		DefaultValue: "/tmp/milvus_workload",
+		Doc:          "The local folder path where the workload file is stored.",
+		Export:       true,
+	}
+	p.WorkloadCapture.LocalPath.Init(base.mgr)
+
+	p.WorkloadCapture.Filename = ParamItem{
+		Key:          "proxy.workloadCapture.filename",
+		Version:      "3.0.0",
+		DefaultValue: "workload.log",
+		Doc:          "The name of the workload file, each line of the file is a captured request in json.",
+		Export:       true,
+	}
+	p.WorkloadCapture.Filename.Init(base.mgr)
+
+	p.WorkloadCapture.MaxSize = ParamItem{
+		Key:          "proxy.workloadCapture.maxSize",
+		Version:      "3.0.0",
+		DefaultValue: "256",
+		Doc:          "The maximum size of a single workload file before it's rotated, the requests larger than it are not captured. Unit: MB.",
+		Export:       true,
+	}
+	p.WorkloadCapture.MaxSize.Init(base.mgr)
+
+	p.WorkloadCapture.RotatedTime = ParamItem{
+		Key:          "proxy.workloadCapture.rotatedTime",
+		Version:      "3.0.0",
+		DefaultValue: "3600",
+		Doc:          "The maximum time interval of rotating a single workload file, 0 means never rotated by time. Unit: seconds.",
+		Export:       true,
+	}
+	p.WorkloadCapture.RotatedTime.Init(base.mgr)
+
+	p.WorkloadCapture.MaxBackups = ParamItem{
+		Key:          "proxy.workloadCapture.maxBackups",
+		Version:      "3.0.0",
+		DefaultValue: "24",
+		Doc:          "The maximum number of sealed workload files retained locally.",
+		Export:       true,
+	}
+	p.WorkloadCapture.MaxBackups.Init(base.mgr)
+
+	p.WorkloadCapture.MinioEnable = ParamItem{
+		Key:          "proxy.workloadCapture.minioEnable",
+		Version:      "3.0.0",
+		DefaultValue: "false",
+		Doc:          "Whether to upload the sealed workload files to the object storage.",
+		Export:       true,
+	}
+	p.WorkloadCapture.MinioEnable.Init(base.mgr)
+
+	p.WorkloadCapture.RemotePath = ParamItem{
+		Key:          "proxy.workloadCapture.remotePath",
+		Version:      "3.0.0",
+		DefaultValue: "workload_capture/",
+		Doc:          "The path of the object storage for uploading workload files.",
+		Export:       true,
+	}
+	p.WorkloadCapture.RemotePath.Init(base.mgr)
+
+	p.WorkloadCapture.RemoteMaxTime = ParamItem{
+		Key:          "proxy.workloadCapture.remoteMaxTime",
+		Version:      "3.0.0",
+		DefaultValue: "0",
+		Doc:          "The retention time of the workload files uploaded to the object storage, 0 means retained forever. Unit: hours.",
+		Export:       true,
+	}
+	p.WorkloadCapture.RemoteMaxTime.Init(base.mgr)
+
 	p.QueryNodePoolingSize = ParamItem{
 		Key:          "proxy.queryNodePooling.size",
 		Version:      "2.4.7",
@@ -2571,6 +2857,45 @@ Disabled if the value is less or equal to 0.`,
 		Export:       true,
 	}
 	p.QueryNodePoolingSize.Init(base.mgr)
+
+	p.PriorityClassUsers = ParamItem{
+		Key:          "proxy.priorityClass.users",
+		Version:      "3.0.0",
+		DefaultValue: "",
+		Doc: `Priority class of the requests from the listed users, in the format of "user1:class1,user2:class2".
+A class carried in the "priority-class" header of a request takes precedence if the request is from root or an admin user, or authorization is disabled,
+otherwise the class is derived from the user, then the roles of the user, then the database.`,
+		Export: true,
+	}
+	p.PriorityClassUsers.Init(base.mgr)
+
+	p.PriorityClassRoles = ParamItem{
+		Key:          "proxy.priorityClass.roles",
+		Version:      "3.0.0",
+		DefaultValue: "",
+		Doc:          `Priority class of the requests from the users granted the listed roles, in the format of "role1:class1,role2:class2"`,
+		Export:       true,
+	}
+	p.PriorityClassRoles.Init(base.mgr)
+
+	p.PriorityClassDatabases = ParamItem{
+		Key:          "proxy.priorityClass.databases",
+		Version:      "3.0.0",
+		DefaultValue: "",
+		Doc:          `Priority class of the requests to the listed databases, in the format of "db1:class1,db2:class2"`,
+		Export:       true,
+	}
+	p.PriorityClassDatabases.Init(base.mgr)
+
+	p.PartitionLoadOnDemandReportInterval = ParamItem{
+		Key:          "proxy.partitionLoadOnDemand.reportInterval",
+		Version:      "3.0.0",
+		DefaultValue: "30",
+		Doc: `The interval (in seconds) of reporting the access of a partition to querycoord for the collections loading partitions on demand,
+the access keeps the partition from being evicted.`,
+		Export: true,
+	}
+	p.PartitionLoadOnDemandReportInterval.Init(base.mgr)
 }
 
 // /////////////////////////////////////////////////////////////////////////////
@@ -2677,6 +3002,13 @@ type queryCoordConfig struct {
 	UpdateTargetNeedSegmentDataReady ParamItem `refreshable:"true"`
 
 	AutoWarmupForNonPKIsolationCollection ParamItem `refreshable:"false"`
+
+	// partition load on demand
+	PartitionLoadOnDemandEnabled       ParamItem `refreshable:"true"`
+	PartitionLoadOnDemandMemoryBudget  ParamItem `refreshable:"true"`
+	PartitionLoadOnDemandMemoryBudgets ParamItem `refreshable:"true"`
+	PartitionLoadOnDemandMinIdleTime   ParamItem `refreshable:"true"`
+	PartitionLoadOnDemandCheckInterval ParamItem `refreshable:"true"`
 }
 
 func (p *queryCoordConfig) init(base *BaseTable) {
@@ -3383,6 +3715,52 @@ Set to 0 to disable the penalty period.`,
 		Export:       false,
 	}
 	p.AutoWarmupForNonPKIsolationCollection.Init(base.mgr)
+
+	p.PartitionLoadOnDemandEnabled = ParamItem{
+		Key:          "queryCoord.partitionLoadOnDemand.enabled",
+		Version:      "3.0.0",
+		DefaultValue: "false",
+		Doc: `Enable loading partitions on first access for the loaded collections with property partition.loadOnDemand=true,
+and releasing the least recently accessed partitions of them when a resource group exceeds its memory budget.`,
+		Export: true,
+	}
+	p.PartitionLoadOnDemandEnabled.Init(base.mgr)
+
+	p.PartitionLoadOnDemandMemoryBudget = ParamItem{
+		Key:          "queryCoord.partitionLoadOnDemand.memoryBudget",
+		Version:      "3.0.0",
+		DefaultValue: "0",
+		Doc:          "The default memory budget in MB of the on demand partitions in a resource group, 0 means unlimited",
+		Export:       true,
+	}
+	p.PartitionLoadOnDemandMemoryBudget.Init(base.mgr)
+
+	p.PartitionLoadOnDemandMemoryBudgets = ParamItem{
+		Key:          "queryCoord.partitionLoadOnDemand.resourceGroupMemoryBudgets",
+		Version:      "3.0.0",
+		DefaultValue: "",
+		Doc:          `The memory budgets in MB overriding memoryBudget for resource groups, in the format of "rg1:1024,rg2:2048"`,
+		Export:       true,
+	}
+	p.PartitionLoadOnDemandMemoryBudgets.Init(base.mgr)
+
+	p.PartitionLoadOnDemandMinIdleTime = ParamItem{
+		Key:          "queryCoord.partitionLoadOnDemand.minIdleTime",
+		Version:      "3.0.0",
+		DefaultValue: "300",
+		Doc:          "The partitions accessed within the duration (in seconds) are never evicted, should be larger than proxy.partitionLoadOnDemand.reportInterval",
+		Export:       true,
+	}
+	p.PartitionLoadOnDemandMinIdleTime.Init(base.mgr)
+
+	p.PartitionLoadOnDemandCheckInterval = ParamItem{
+		Key:          "queryCoord.partitionLoadOnDemand.checkInterval",
+		Version:      "3.0.0",
+		DefaultValue: "30",
+		Doc:          "The interval (in seconds) of checking the memory budget of the on demand partitions",
+		Export:       true,
+	}
+	p.PartitionLoadOnDemandCheckInterval.Init(base.mgr)
 }
 
 // /////////////////////////////////////////////////////////////////////////////
@@ -3501,10 +3879,18 @@ type queryNodeConfig struct {
 	DiskSizeFetchInterval       ParamItem `refreshable:"false"`
 
 	// schedule task policy.
-	SchedulePolicyName                    ParamItem `refreshable:"false"`
-	SchedulePolicyTaskQueueExpire         ParamItem `refreshable:"true"`
-	SchedulePolicyEnableCrossUserGrouping ParamItem `refreshable:"true"`
-	SchedulePolicyMaxPendingTaskPerUser   ParamItem `refreshable:"true"`
+	SchedulePolicyName                     ParamItem `refreshable:"false"`
+	SchedulePolicyTaskQueueExpire          ParamItem `refreshable:"true"`
+	SchedulePolicyEnableCrossUserGrouping  ParamItem `refreshable:"true"`
+	SchedulePolicyMaxPendingTaskPerUser    ParamItem `refreshable:"true"`
+	SchedulePolicyPriorityClassWeights     ParamItem `refreshable:"true"`
+	SchedulePolicyPriorityClassMaxInflight ParamItem `refreshable:"true"`
+	SchedulePolicyDefaultPriorityClass     ParamItem `refreshable:"true"`
+
+	// admission control of expensive searches
+	SearchAdmissionCostBudget         ParamItem `refreshable:"true"`
+	SearchAdmissionPolicy             ParamItem `refreshable:"true"`
+	SearchAdmissionMaxQueuedExecuting ParamItem `refreshable:"true"`
 
 	// CGOPoolSize ratio to MaxReadConcurrency
 	CGOPoolSizeRatio ParamItem `refreshable:"true"`
@@ -4583,7 +4969,10 @@ user-task-polling:
 	Scheduling is fair on task granularity.
 	The policy is based on the username for authentication.
 	And an empty username is considered the same user.
-	When there are no multi-users, the policy decay into FIFO"`,
+	When there are no multi-users, the policy decay into FIFO"
+priority-class:
+	Tasks are grouped by the priority class carried in the request,
+	classes are scheduled by weighted fair queuing and the tasks of a class by user-task-polling.`,
 		Export: true,
 	}
 	p.SchedulePolicyName.Init(base.mgr)
@@ -4611,6 +5000,63 @@ user-task-polling:
 		Export:       true,
 	}
 	p.SchedulePolicyMaxPendingTaskPerUser.Init(base.mgr)
+	p.SchedulePolicyPriorityClassWeights = ParamItem{
+		Key:          "queryNode.scheduler.scheduleReadPolicy.priorityClass.weights",
+		Version:      "3.0.0",
+		DefaultValue: "interactive:8,default:4,batch:1",
+		Doc: `Weights of the priority classes when using priority-class policy, in the format of "class1:weight1,class2:weight2".
+Tasks of a class are scheduled in proportion to its weight when several classes are pending.`,
+		Export: true,
+	}
+	p.SchedulePolicyPriorityClassWeights.Init(base.mgr)
+	p.SchedulePolicyPriorityClassMaxInflight = ParamItem{
+		Key:          "queryNode.scheduler.scheduleReadPolicy.priorityClass.maxInflight",
+		Version:      "3.0.0",
+		DefaultValue: "",
+		Doc: `Max in-flight tasks of the priority classes when using priority-class policy, in the format of "class1:num1,class2:num2".
+Classes not listed are unlimited.`,
+		Export: true,
+	}
+	p.SchedulePolicyPriorityClassMaxInflight.Init(base.mgr)
+	p.SchedulePolicyDefaultPriorityClass = ParamItem{
+		Key:          "queryNode.scheduler.scheduleReadPolicy.priorityClass.default",
+		Version:      "3.0.0",
+		DefaultValue: "default",
+		Doc:          "The priority class of tasks without a class or with a class not configured in weights",
+		Export:       true,
+	}
+	p.SchedulePolicyDefaultPriorityClass.Init(base.mgr)
+
+	p.SearchAdmissionCostBudget = ParamItem{
+		Key:          "queryNode.searchAdmission.costBudget",
+		Version:      "3.0.0",
+		DefaultValue: "0",
+		Doc: `The max estimated cost of a search on a shard, 0 disables the admission control.
+The cost approximates the distances computed by the search: nq * sum(max(rows * indexFactor, topk)) over the segments left after pruning,
+indexFactor is 1 for brute force, 0.05 for IVF family indexes and 0.01 for graph indexes.`,
+		Export: true,
+	}
+	p.SearchAdmissionCostBudget.Init(base.mgr)
+
+	p.SearchAdmissionPolicy = ParamItem{
+		Key:          "queryNode.searchAdmission.policy",
+		Version:      "3.0.0",
+		DefaultValue: "reject",
+		Doc: `How to handle the searches over the cost budget, "reject" or "queue".
+reject: fail the search before dispatching it to workers.
+queue: run at most maxQueuedExecuting such searches on the node at the same time, the others wait until timeout.`,
+		Export: true,
+	}
+	p.SearchAdmissionPolicy.Init(base.mgr)
+
+	p.SearchAdmissionMaxQueuedExecuting = ParamItem{
+		Key:          "queryNode.searchAdmission.maxQueuedExecuting",
+		Version:      "3.0.0",
+		DefaultValue: "1",
+		Doc:          "The max number of searches over the cost budget executing on the node at the same time when the policy is queue",
+		Export:       true,
+	}
+	p.SearchAdmissionMaxQueuedExecuting.Init(base.mgr)
 
 	p.CGOPoolSizeRatio = ParamItem{
 		Key:          "queryNode.segcore.cgoPoolSizeRatio",
@@ -4875,9 +5321,14 @@ type dataCoordConfig struct {
 	EnableAutoCompaction                   ParamItem `refreshable:"true"`
 	IndexBasedCompaction                   ParamItem `refreshable:"true"`
 	CompactionTaskPrioritizer              ParamItem `refreshable:"true"`
+	CompactionCollectionPriorityWindow     ParamItem `refreshable:"true"`
 	CompactionTaskQueueCapacity            ParamItem `refreshable:"false"`
 	CompactionPreAllocateIDExpansionFactor ParamItem `refreshable:"false"`
 
+	CompactionMaintenanceWindows               ParamItem `refreshable:"true"`
+	CompactionMaintenanceWindowTimezone        ParamItem `refreshable:"true"`
+	CompactionMaintenanceWindowOutsideMaxViews ParamItem `refreshable:"true"`
+
 	CompactionRPCTimeout                      ParamItem `refreshable:"true"`
 	CompactionMaxParallelTasks                ParamItem `refreshable:"true"`
 	CompactionWorkerParallelTasks             ParamItem `refreshable:"true"`
@@ -4934,6 +5385,11 @@ type dataCoordConfig struct {
 	ClusteringCompactionMaxClusterSizeRatio    ParamItem `refreshable:"true"`
 	ClusteringCompactionMaxClusterSize         ParamItem `refreshable:"true"`
 
+	// Time-partitioned Compaction
+	TimePartitionCompactionEnable           ParamItem `refreshable:"true"`
+	TimePartitionCompactionTriggerInterval  ParamItem `refreshable:"true"`
+	TimePartitionCompactionBucketCloseDelay ParamItem `refreshable:"true"`
+
 	// LevelZero Segment
 	LevelZeroCompactionTriggerMinSize        ParamItem `refreshable:"true"`
 	LevelZeroCompactionTriggerMaxSize        ParamItem `refreshable:"true"`
@@ -5250,6 +5706,20 @@ mix is prioritized by level: mix compactions first, then L0 compactions, then cl
 	}
 	p.CompactionTaskPrioritizer.Init(base.mgr)
 
+	p.CompactionCollectionPriorityWindow = ParamItem{
+		Key:          "dataCoord.compaction.collectionPriorityWindow",
+		Version:      "3.0.0",
+		DefaultValue: "600",
+		Doc: `in seconds, how much the collection compaction priority moves the queued tasks of the collection.
+The task prioritizer orders the tasks first, the tasks of the same level of the level and mix prioritizers are FIFO by their start time,
+each doubling of the collection.compaction.priority property moves the tasks of the collection ahead by one window,
+and each halving moves them behind by one window. The tasks of a level never overtake the tasks of a more prior level,
+and the tasks of other collections which have waited longer than the shift are not overtaken, so no collection is starved.
+The default prioritizer keeps the plan id order, so the collection priority takes no effect with it.`,
+		Export: true,
+	}
+	p.CompactionCollectionPriorityWindow.Init(base.mgr)
+
 	p.CompactionTaskQueueCapacity = ParamItem{
 		Key:          "dataCoord.compaction.taskQueueCapacity",
 		Version:      "2.5.0",
@@ -5267,6 +5737,38 @@ mix is prioritized by level: mix compactions first, then L0 compactions, then cl
 	}
 	p.CompactionPreAllocateIDExpansionFactor.Init(base.mgr)
 
+	p.CompactionMaintenanceWindows = ParamItem{
+		Key:          "dataCoord.compaction.maintenanceWindow.windows",
+		Version:      "3.0.0",
+		DefaultValue: "",
+		Doc: `comma separated daily maintenance windows in HH:MM-HH:MM, e.g. "01:00-05:00,22:00-23:30".
+A window may cross midnight, e.g. "23:00-02:00".
+Heavy compactions (clustering, force merge and storage version upgrade) are throttled outside the windows.
+Empty means heavy compactions are not restricted.`,
+		Export: true,
+	}
+	p.CompactionMaintenanceWindows.Init(base.mgr)
+
+	p.CompactionMaintenanceWindowTimezone = ParamItem{
+		Key:          "dataCoord.compaction.maintenanceWindow.timezone",
+		Version:      "3.0.0",
+		DefaultValue: "Local",
+		Doc:          `IANA time zone of the maintenance windows, e.g. "UTC" or "Asia/Shanghai"`,
+		Export:       true,
+	}
+	p.CompactionMaintenanceWindowTimezone.Init(base.mgr)
+
+	p.CompactionMaintenanceWindowOutsideMaxViews = ParamItem{
+		Key:          "dataCoord.compaction.maintenanceWindow.outsideMaxViews",
+		Version:      "3.0.0",
+		DefaultValue: "0",
+		Doc: `max number of heavy compaction views submitted by one trigger outside the maintenance windows.
+The rest of a manual trigger is deferred until a window opens, the rest of a periodic trigger is skipped until its next round.
+0 defers all the heavy compactions to the maintenance windows.`,
+		Export: true,
+	}
+	p.CompactionMaintenanceWindowOutsideMaxViews.Init(base.mgr)
+
 	p.CompactionRPCTimeout = ParamItem{
 		Key:          "dataCoord.compaction.rpcTimeout",
 		Version:      "2.2.12",
@@ -5749,6 +6251,33 @@ During compaction, the size of segment # of rows is able to exceed segment max #
 	}
 	p.ClusteringCompactionMaxClusterSize.Init(base.mgr)
 
+	p.TimePartitionCompactionEnable = ParamItem{
+		Key:          "dataCoord.compaction.timePartition.enable",
+		Version:      "3.0.0",
+		DefaultValue: "true",
+		Doc:          "Enable time-partitioned compaction for the collections with collection.compaction.timeBucket and collection.compaction.timeField set, the segments are bucketed and split by the values of the time field",
+		Export:       true,
+	}
+	p.TimePartitionCompactionEnable.Init(base.mgr)
+
+	p.TimePartitionCompactionTriggerInterval = ParamItem{
+		Key:          "dataCoord.compaction.timePartition.triggerInterval",
+		Version:      "3.0.0",
+		DefaultValue: "600",
+		Doc:          "time-partitioned compaction trigger interval in seconds",
+		Export:       true,
+	}
+	p.TimePartitionCompactionTriggerInterval.Init(base.mgr)
+
+	p.TimePartitionCompactionBucketCloseDelay = ParamItem{
+		Key:          "dataCoord.compaction.timePartition.bucketCloseDelay",
+		Version:      "3.0.0",
+		DefaultValue: "600",
+		Doc:          "A time bucket is compacted only after its end has passed for this many seconds, so late writes can still land in it",
+		Export:       true,
+	}
+	p.TimePartitionCompactionBucketCloseDelay.Init(base.mgr)
+
 	p.EnableGarbageCollection = ParamItem{
 		Key:          "dataCoord.enableGarbageCollection",
 		Version:      "2.0.0",
@@ -7130,6 +7659,10 @@ type streamingConfig struct {
 	WALBalancerPolicyVChannelFairAntiAffinityWeight     ParamItem `refreshable:"true"`
 	WALBalancerPolicyVChannelFairRebalanceTolerance     ParamItem `refreshable:"true"`
 	WALBalancerPolicyVChannelFairRebalanceMaxStep       ParamItem `refreshable:"true"`
+	WALBalancerPolicyThroughputFairRebalanceTolerance   ParamItem `refreshable:"true"`
+	WALBalancerPolicyThroughputFairRebalanceMaxStep     ParamItem `refreshable:"true"`
+	WALBalancerPolicyThroughputFairRebalanceInterval    ParamItem `refreshable:"true"`
+	WALBalancerPolicyThroughputFairMinThroughput        ParamItem `refreshable:"true"`
 	WALBalancerExpectedInitialStreamingNodeNum          ParamItem `refreshable:"true"`
 
 	// broadcaster
@@ -7167,6 +7700,10 @@ type streamingConfig struct {
 	WALRecoveryGracefulCloseTimeout      ParamItem `refreshable:"true"`
 	WALRecoverySchemaExpirationTolerance ParamItem `refreshable:"true"`
 
+	// meta store configuration.
+	MetaStoreType                     ParamItem `refreshable:"false"`
+	MetaStoreCheckpointCoalesceWindow ParamItem `refreshable:"true"`
+
 	// wal rate limit
 	WALRateLimitDefaultBurst                     ParamItem `refreshable:"true"`
 	WALRateLimitNodeMemorySlowdownThreshold      ParamItem `refreshable:"true"`
@@ -7380,6 +7917,46 @@ it also determine the depth of depth first search method that is used to find th
 	}
 	p.WALBalancerPolicyVChannelFairRebalanceMaxStep.Init(base.mgr)
 
+	p.WALBalancerPolicyThroughputFairRebalanceTolerance = ParamItem{
+		Key:     "streaming.walBalancer.balancePolicy.throughputFair.rebalanceTolerance",
+		Version: "3.0.0",
+		Doc: `The tolerance of throughputFair balance policy, 0.2 by default.
+The rebalance is triggered only if the append throughput of the hottest streaming node exceeds the average by the tolerance ratio,
+and a pchannel is moved only if it reduces the throughput gap of two nodes by more than the tolerance of the average.`,
+		DefaultValue: "0.2",
+		Export:       true,
+	}
+	p.WALBalancerPolicyThroughputFairRebalanceTolerance.Init(base.mgr)
+
+	p.WALBalancerPolicyThroughputFairRebalanceMaxStep = ParamItem{
+		Key:          "streaming.walBalancer.balancePolicy.throughputFair.rebalanceMaxStep",
+		Version:      "3.0.0",
+		Doc:          `The max number of pchannels moved by one rebalance of throughputFair balance policy, 1 by default.`,
+		DefaultValue: "1",
+		Export:       true,
+	}
+	p.WALBalancerPolicyThroughputFairRebalanceMaxStep.Init(base.mgr)
+
+	p.WALBalancerPolicyThroughputFairRebalanceInterval = ParamItem{
+		Key:     "streaming.walBalancer.balancePolicy.throughputFair.rebalanceInterval",
+		Version: "3.0.0",
+		Doc: `The min interval between two rebalances of throughputFair balance policy, 1m by default.
+It limits the rate of pchannel movement of the whole cluster, the append throughput of a moved pchannel takes some time to be observed again.`,
+		DefaultValue: "1m",
+		Export:       true,
+	}
+	p.WALBalancerPolicyThroughputFairRebalanceInterval.Init(base.mgr)
+
+	p.WALBalancerPolicyThroughputFairMinThroughput = ParamItem{
+		Key:     "streaming.walBalancer.balancePolicy.throughputFair.minThroughput",
+		Version: "3.0.0",
+		Doc: `The min append throughput per second of the hottest streaming node to trigger a rebalance of throughputFair balance policy, 1m by default.
+The rebalance of a cluster with low write load is skipped to avoid moving pchannels for the noise of throughput.`,
+		DefaultValue: "1m",
+		Export:       true,
+	}
+	p.WALBalancerPolicyThroughputFairMinThroughput.Init(base.mgr)
+
 	p.WALBalancerExpectedInitialStreamingNodeNum = ParamItem{
 		Key:     "streaming.walBalancer.expectedInitialStreamingNodeNum",
 		Version: "2.6.9",
@@ -7587,6 +8164,29 @@ If the schema is older than (the channel checkpoint - tolerance), it will be rem
 	}
 	p.WALRecoverySchemaExpirationTolerance.Init(base.mgr)
 
+	p.MetaStoreType = ParamItem{
+		Key:     "streaming.metaStore.type",
+		Version: "3.0.0",
+		Doc: `The meta store of the streaming coordinator and streaming node, etcd or tikv.
+Follow the metastore.type if empty. Setting it to tikv moves the frequently updated wal checkpoints off etcd,
+the existing streaming meta should be migrated by cmd/tools/streamingmeta before switching.
+The replicate configuration is always kept on etcd, where the cdc watches it.`,
+		DefaultValue: "",
+		Export:       true,
+	}
+	p.MetaStoreType.Init(base.mgr)
+
+	p.MetaStoreCheckpointCoalesceWindow = ParamItem{
+		Key:     "streaming.metaStore.checkpointCoalesceWindow",
+		Version: "3.0.0",
+		Doc: `The window to coalesce the wal checkpoint writes of the streaming node, 50ms by default.
+The checkpoints saved within the window are written into the meta store by one batch, and only the latest one of each wal is kept.
+The checkpoint writing is not batched if 0 is given.`,
+		DefaultValue: "50ms",
+		Export:       true,
+	}
+	p.MetaStoreCheckpointCoalesceWindow.Init(base.mgr)
+
 	p.OldVersionLastConfirmedWindowSize = ParamItem{
 		Key:     "streaming.walScanner.oldVersionLastConfirmedWindowSize",
 		Version: "2.6.13",
@@ -170,6 +170,14 @@ func TestComponentParam(t *testing.T) {
 		assert.Equal(t, int64(1024*1024), Params.AsyncWriteMaxBytesPerLog.GetAsSize())
 	})
 
+	t.Run("test traceConfig", func(t *testing.T) {
+		Params := &params.TraceCfg
+		assert.False(t, Params.OtlpMetricsEnabled.GetAsBool())
+		assert.Equal(t, 15*time.Second, Params.OtlpMetricsInterval.GetAsDuration(time.Second))
+		assert.False(t, Params.OtlpLogsEnabled.GetAsBool())
+		assert.Equal(t, "info", Params.OtlpLogsLevel.GetValue())
+	})
+
 	t.Run("test rootCoordConfig", func(t *testing.T) {
 		Params := &params.RootCoordCfg
 
@@ -222,6 +230,23 @@ func TestComponentParam(t *testing.T) {
 
 		t.Logf("ShardLeaderCacheInterval: %d", Params.ShardLeaderCacheInterval.GetAsInt64())
 
+		assert.False(t, Params.SlowQueryLog.Enable.GetAsBool())
+		assert.Equal(t, "slow_query.log", Params.SlowQueryLog.Filename.GetValue())
+		assert.Equal(t, 64, Params.SlowQueryLog.MaxSize.GetAsInt())
+		assert.Equal(t, 8, Params.SlowQueryLog.MaxBackups.GetAsInt())
+		assert.False(t, Params.SlowQueryLog.MinioEnable.GetAsBool())
+		assert.Equal(t, "slow_query_log/", Params.SlowQueryLog.RemotePath.GetValue())
+		assert.Equal(t, 1000, Params.SlowQueryLog.Capacity.GetAsInt())
+		assert.Equal(t, 24*time.Hour, Params.SlowQueryLog.MaxAge.GetAsDuration(time.Hour))
+
+		assert.False(t, Params.WorkloadCapture.Enable.GetAsBool())
+		assert.Equal(t, 0.01, Params.WorkloadCapture.SampleRate.GetAsFloat())
+		assert.Equal(t, []string{"Search", "HybridSearch", "Query", "Insert", "Upsert", "Delete"}, Params.WorkloadCapture.Methods.GetAsStrings())
+		assert.Equal(t, "workload.log", Params.WorkloadCapture.Filename.GetValue())
+		assert.Equal(t, 256, Params.WorkloadCapture.MaxSize.GetAsInt())
+		assert.Equal(t, int64(3600), Params.WorkloadCapture.RotatedTime.GetAsInt64())
+		assert.Equal(t, "workload_capture/", Params.WorkloadCapture.RemotePath.GetValue())
+
 		assert.Equal(t, Params.ReplicaSelectionPolicy.GetValue(), "look_aside")
 		params.Save(Params.ReplicaSelectionPolicy.Key, "round_robin")
 		assert.Equal(t, Params.ReplicaSelectionPolicy.GetValue(), "round_robin")
@@ -568,6 +593,15 @@ func TestComponentParam(t *testing.T) {
 		params.Save("dataCoord.compaction.dropTolerance", "100")
 		assert.Equal(t, float64(100), Params.CompactionDropToleranceInSeconds.GetAsDuration(time.Second).Seconds())
 		assert.Equal(t, int64(10000), Params.CompactionPreAllocateIDExpansionFactor.GetAsInt64())
+		assert.Equal(t, "", Params.CompactionMaintenanceWindows.GetValue())
+		params.Save("dataCoord.compaction.maintenanceWindow.windows", "01:00-05:00,23:00-02:00")
+		assert.Equal(t, []string{"01:00-05:00", "23:00-02:00"}, Params.CompactionMaintenanceWindows.GetAsStrings())
+		assert.Equal(t, "Local", Params.CompactionMaintenanceWindowTimezone.GetValue())
+		assert.Equal(t, 0, Params.CompactionMaintenanceWindowOutsideMaxViews.GetAsInt())
+		assert.Equal(t, 600*time.Second, Params.CompactionCollectionPriorityWindow.GetAsDuration(time.Second))
+		assert.True(t, Params.TimePartitionCompactionEnable.GetAsBool())
+		assert.Equal(t, 600, Params.TimePartitionCompactionTriggerInterval.GetAsInt())
+		assert.Equal(t, 600, Params.TimePartitionCompactionBucketCloseDelay.GetAsInt())
 
 		params.Save("dataCoord.compaction.clustering.enable", "true")
 		assert.Equal(t, true, Params.ClusteringCompactionEnable.GetAsBool())
@@ -692,6 +726,10 @@ func TestComponentParam(t *testing.T) {
 		assert.Equal(t, 0.01, params.StreamingCfg.WALBalancerPolicyVChannelFairAntiAffinityWeight.GetAsFloat())
 		assert.Equal(t, 0.01, params.StreamingCfg.WALBalancerPolicyVChannelFairRebalanceTolerance.GetAsFloat())
 		assert.Equal(t, 3, params.StreamingCfg.WALBalancerPolicyVChannelFairRebalanceMaxStep.GetAsInt())
+		assert.Equal(t, 0.2, params.StreamingCfg.WALBalancerPolicyThroughputFairRebalanceTolerance.GetAsFloat())
+		assert.Equal(t, 1, params.StreamingCfg.WALBalancerPolicyThroughputFairRebalanceMaxStep.GetAsInt())
+		assert.Equal(t, time.Minute, params.StreamingCfg.WALBalancerPolicyThroughputFairRebalanceInterval.GetAsDurationByParse())
+		assert.Equal(t, int64(1024*1024), params.StreamingCfg.WALBalancerPolicyThroughputFairMinThroughput.GetAsSize())
 		assert.Equal(t, 30*time.Minute, params.StreamingCfg.WALBalancerOperationTimeout.GetAsDurationByParse())
 		assert.Equal(t, 4.0, params.StreamingCfg.WALBroadcasterConcurrencyRatio.GetAsFloat())
 		assert.Equal(t, 5*time.Minute, params.StreamingCfg.WALBroadcasterTombstoneCheckInternal.GetAsDurationByParse())
@@ -706,6 +744,9 @@ func TestComponentParam(t *testing.T) {
 		assert.Equal(t, 24*time.Hour, params.StreamingCfg.WALRecoverySchemaExpirationTolerance.GetAsDurationByParse())
 		assert.Equal(t, 100, params.StreamingCfg.WALRecoveryMaxDirtyMessage.GetAsInt())
 		assert.Equal(t, 10*time.Second, params.StreamingCfg.WALRecoveryPersistInterval.GetAsDurationByParse())
+		assert.Equal(t, "", params.StreamingCfg.MetaStoreType.GetValue())
+		assert.Equal(t, params.MetaStoreCfg.MetaStoreType.GetValue(), params.GetStreamingMetaStoreType())
+		assert.Equal(t, 50*time.Millisecond, params.StreamingCfg.MetaStoreCheckpointCoalesceWindow.GetAsDurationByParse())
 		assert.Equal(t, float64(0.6), params.StreamingCfg.FlushMemoryThreshold.GetAsFloat())
 		assert.Equal(t, float64(0.2), params.StreamingCfg.FlushGrowingSegmentBytesHwmThreshold.GetAsFloat())
 		assert.Equal(t, float64(0.1), params.StreamingCfg.FlushGrowingSegmentBytesLwmThreshold.GetAsFloat())
@@ -798,6 +839,8 @@ func TestComponentParam(t *testing.T) {
 		params.Save(params.StreamingCfg.WALBalancerPolicyAllowRebalanceRecoveryLagThreshold.Key, "1s")
 		params.Save(params.StreamingCfg.LoggingAppendSlowThreshold.Key, "3s")
 		params.Save(params.StreamingCfg.WALRecoveryGracefulCloseTimeout.Key, "4s")
+		params.Save(params.StreamingCfg.MetaStoreType.Key, "tikv")
+		params.Save(params.StreamingCfg.MetaStoreCheckpointCoalesceWindow.Key, "10ms")
 		params.Save(params.StreamingCfg.WALRecoveryMaxDirtyMessage.Key, "200")
 		params.Save(params.StreamingCfg.WALRecoveryPersistInterval.Key, "20s")
 		params.Save(params.StreamingCfg.FlushMemoryThreshold.Key, "0.7")
@@ -825,6 +868,8 @@ func TestComponentParam(t *testing.T) {
 		assert.Equal(t, int64(128*1024), params.StreamingCfg.WALWriteAheadBufferCapacity.GetAsSize())
 		assert.Equal(t, 3*time.Second, params.StreamingCfg.LoggingAppendSlowThreshold.GetAsDurationByParse())
 		assert.Equal(t, 4*time.Second, params.StreamingCfg.WALRecoveryGracefulCloseTimeout.GetAsDurationByParse())
+		assert.Equal(t, "tikv", params.GetStreamingMetaStoreType())
+		assert.Equal(t, 10*time.Millisecond, params.StreamingCfg.MetaStoreCheckpointCoalesceWindow.GetAsDurationByParse())
 		assert.Equal(t, 200, params.StreamingCfg.WALRecoveryMaxDirtyMessage.GetAsInt())
 		assert.Equal(t, 20*time.Second, params.StreamingCfg.WALRecoveryPersistInterval.GetAsDurationByParse())
 		assert.Equal(t, float64(0.7), params.StreamingCfg.FlushMemoryThreshold.GetAsFloat())
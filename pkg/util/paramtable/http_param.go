@@ -27,6 +27,8 @@ type httpConfig struct {
 	HSTSIncludeSubDomains ParamItem `refreshable:"false"`
 	EnableHSTS            ParamItem `refreshable:"false"`
 	EnableWebUI           ParamItem `refreshable:"false"`
+	IteratorCursorSecret  ParamItem `refreshable:"true"`
+	IteratorCursorTTL     ParamItem `refreshable:"true"`
 }
 
 func (p *httpConfig) init(base *BaseTable) {
@@ -120,4 +122,22 @@ func (p *httpConfig) init(base *BaseTable) {
 		Export:       true,
 	}
 	p.EnableWebUI.Init(base.mgr)
+
+	p.IteratorCursorSecret = ParamItem{
+		Key:          "proxy.http.iteratorCursorSecret",
+		DefaultValue: "",
+		Version:      "3.0.0",
+		Doc:          "The secret to sign the cursors of the restful iterators, all proxies must share the same secret. If empty, each proxy generates a random secret at startup, then a cursor can only be resumed on the proxy that issued it",
+		Export:       true,
+	}
+	p.IteratorCursorSecret.Init(base.mgr)
+
+	p.IteratorCursorTTL = ParamItem{
+		Key:          "proxy.http.iteratorCursorTTL",
+		DefaultValue: "3600",
+		Version:      "3.0.0",
+		Doc:          "The time in seconds that the cursor of a restful iterator stays valid",
+		Export:       true,
+	}
+	p.IteratorCursorTTL.Init(base.mgr)
 }
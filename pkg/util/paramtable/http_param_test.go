@@ -18,6 +18,7 @@ package paramtable
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -32,4 +33,6 @@ func TestHTTPConfig_Init(t *testing.T) {
 	assert.Equal(t, cfg.AcceptTypeAllowInt64.GetValue(), "true")
 	assert.Equal(t, cfg.EnablePprof.GetAsBool(), true)
 	assert.Equal(t, cfg.EnableWebUI.GetAsBool(), true)
+	assert.Equal(t, cfg.IteratorCursorSecret.GetValue(), "")
+	assert.Equal(t, cfg.IteratorCursorTTL.GetAsDuration(time.Second), time.Hour)
 }
@@ -161,6 +161,10 @@ type quotaConfig struct {
 	DeleteBufferSizeProtectionEnabled     ParamItem `refreshable:"true"`
 	DeleteBufferSizeLowWaterLevel         ParamItem `refreshable:"true"`
 	DeleteBufferSizeHighWaterLevel        ParamItem `refreshable:"true"`
+	WriteBackpressureEnabled              ParamItem `refreshable:"true"`
+	WriteBackpressureMaxWait              ParamItem `refreshable:"true"`
+	WriteBackpressureRecheckInterval      ParamItem `refreshable:"true"`
+	WriteBackpressureQueueCapacity        ParamItem `refreshable:"true"`
 
 	// limit reading
 	ForceDenyReading ParamItem `refreshable:"true"`
@@ -1926,6 +1930,44 @@ but the rate will not be lower than minRateRatio * dmlRate.`,
 	}
 	p.DeleteBufferSizeHighWaterLevel.Init(base.mgr)
 
+	p.WriteBackpressureEnabled = ParamItem{
+		Key:          "quotaAndLimits.limitWriting.backpressure.enabled",
+		Version:      "3.0.0",
+		DefaultValue: "false",
+		Doc: `switch to delay instead of reject the insert, upsert and delete requests when writing is denied,
+the delayed requests are rechecked until writing is allowed again or maxWait is reached`,
+		Export: true,
+	}
+	p.WriteBackpressureEnabled.Init(base.mgr)
+
+	p.WriteBackpressureMaxWait = ParamItem{
+		Key:          "quotaAndLimits.limitWriting.backpressure.maxWait",
+		Version:      "3.0.0",
+		DefaultValue: "10",
+		Doc:          "max time in seconds a write request is delayed before it is rejected with a retry-after hint",
+		Export:       true,
+	}
+	p.WriteBackpressureMaxWait.Init(base.mgr)
+
+	p.WriteBackpressureRecheckInterval = ParamItem{
+		Key:          "quotaAndLimits.limitWriting.backpressure.recheckInterval",
+		Version:      "3.0.0",
+		DefaultValue: "0.2",
+		Doc:          "interval in seconds to recheck the quota of a delayed write request",
+		Export:       true,
+	}
+	p.WriteBackpressureRecheckInterval.Init(base.mgr)
+
+	p.WriteBackpressureQueueCapacity = ParamItem{
+		Key:          "quotaAndLimits.limitWriting.backpressure.queueCapacity",
+		Version:      "3.0.0",
+		DefaultValue: "1024",
+		Doc: `max number of write requests delayed in a proxy, the capacity is shared fairly by the databases
+with delayed requests, requests beyond the share of their database are rejected at once with a retry-after hint`,
+		Export: true,
+	}
+	p.WriteBackpressureQueueCapacity.Init(base.mgr)
+
 	// limit reading
 	p.ForceDenyReading = ParamItem{
 		Key:          "quotaAndLimits.limitReading.forceDeny",
@@ -18,6 +18,7 @@ package paramtable
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -203,6 +204,10 @@ func TestQuotaParam(t *testing.T) {
 		assert.Equal(t, true, qc.DiskProtectionEnabled.GetAsBool())
 		assert.Equal(t, defaultMax, qc.DiskQuota.GetAsFloat())
 		assert.Equal(t, defaultMax, qc.DiskQuotaPerCollection.GetAsFloat())
+		assert.False(t, qc.WriteBackpressureEnabled.GetAsBool())
+		assert.Equal(t, 10*time.Second, qc.WriteBackpressureMaxWait.GetAsDuration(time.Second))
+		assert.Equal(t, 200*time.Millisecond, qc.WriteBackpressureRecheckInterval.GetAsDuration(time.Second))
+		assert.Equal(t, 1024, qc.WriteBackpressureQueueCapacity.GetAsInt())
 	})
 
 	t.Run("test limit reading", func(t *testing.T) {